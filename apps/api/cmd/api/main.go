@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -21,8 +22,13 @@ import (
 	"github.com/vido/api/internal/handlers"
 	"github.com/vido/api/internal/health"
 	"github.com/vido/api/internal/images"
+	"github.com/vido/api/internal/indexer"
 	"github.com/vido/api/internal/logger"
+	"github.com/vido/api/internal/metrics"
+	"github.com/vido/api/internal/notify"
 	"github.com/vido/api/internal/plugins"
+	"github.com/vido/api/internal/plugins/jellyfin"
+	"github.com/vido/api/internal/plugins/plex"
 	"github.com/vido/api/internal/plugins/radarr"
 	"github.com/vido/api/internal/plugins/sonarr"
 	"github.com/vido/api/internal/repository"
@@ -154,6 +160,10 @@ func main() {
 	setupService := services.NewSetupService(repos.Settings, secretsService)
 	qbittorrentService := services.NewQBittorrentService(repos.Settings, secretsService)
 	downloadService := services.NewDownloadService(qbittorrentService, slog.Default())
+	// The downloads page and the built-in fulfilment path follow the client
+	// selected under /settings/download-clients.
+	downloadClientService := services.NewDownloadClientService(repos.Settings, secretsService, qbittorrentService, slog.Default())
+	downloadService.SetClientResolver(downloadClientService)
 	mediaService := services.NewMediaService(cfg.MediaDirs)
 	mediaLibraryService := services.NewMediaLibraryService(repos.MediaLibraries)
 	setupService.SetLibraryService(mediaLibraryService) // Story 7b-3: wire library creation into setup
@@ -230,6 +240,17 @@ func main() {
 				return ids.TVDbID, nil
 			}))
 	})
+	// Media servers ride the same manager in their own registry, so the
+	// request poller never asks them for a download queue.
+	pluginManager.RegisterMediaServer("jellyfin", func(config plugins.PluginConfig) plugins.MediaServerPlugin {
+		return jellyfin.NewClient(config, jellyfin.FlavorJellyfin)
+	})
+	pluginManager.RegisterMediaServer("emby", func(config plugins.PluginConfig) plugins.MediaServerPlugin {
+		return jellyfin.NewClient(config, jellyfin.FlavorEmby)
+	})
+	pluginManager.RegisterMediaServer("plex", func(config plugins.PluginConfig) plugins.MediaServerPlugin {
+		return plex.NewClient(config)
+	})
 	fulfilmentService := services.NewFulfilmentService(pluginManager, repos.Settings, repos.Requests)
 	requestService.SetFulfilmentService(fulfilmentService)
 	dvrSettingsService := services.NewDVRSettingsService(pluginManager, repos.Settings, secretsService)
@@ -241,6 +262,14 @@ func main() {
 	// into every client construction that follows.
 	aiGovernor := ai.NewGovernor(cfg.AIMaxConcurrent, cfg.AIRatePerSec, cfg.AIMaxConcurrent)
 	slog.Info("AI governor initialized", "max_concurrent", cfg.AIMaxConcurrent, "rate_per_sec", cfg.AIRatePerSec, "run_budget_usd", cfg.AIRunBudgetUSD)
+	// Every metered AI call also feeds the token/cost/ASR counters on /metrics.
+	ai.SetUsageObserver(metrics.AIUsage{})
+	// ...and lands in the persistent spend ledger, and no call may spend past
+	// the global daily/monthly caps.
+	aiSpendService := services.NewAISpendService(repos.Settings, repos.AIUsage, slog.Default())
+	ai.SetUsageLedger(aiSpendService)
+	ai.SetSpendGuard(aiSpendService)
+	aiUsageHandler := handlers.NewAIUsageHandler(aiSpendService)
 
 	// Initialize AI service for AI-powered filename parsing (Story 3.1)
 	aiService, err := services.NewAIService(cfg, db.Conn(), aiGovernor)
@@ -249,6 +278,7 @@ func main() {
 		os.Exit(1)
 	}
 	if aiService != nil {
+		aiService.SetSpendGuard(aiSpendService)
 		slog.Info("AI service initialized", "provider", aiService.GetProviderName())
 	} else {
 		slog.Info("AI service not configured - AI parsing disabled")
@@ -277,6 +307,30 @@ func main() {
 		},
 	)
 	healthChecker.SetQBittorrent(qbHealthPingable)
+	// Plex shows in /health/services next to qBittorrent. The plugin manager
+	// already probes it every 60s, so the monitor reports that result instead
+	// of probing a second time.
+	healthChecker.SetPlex(health.PingFunc(func(ctx context.Context) error {
+		switch h := pluginManager.Health("plex"); h.Status {
+		case plugins.HealthStatusHealthy:
+			return nil
+		case plugins.HealthStatusUnconfigured:
+			return errors.New("Plex not configured")
+		default:
+			return errors.New(h.Message)
+		}
+	}))
+	// Transmission and Deluge are probed with their stored settings whether
+	// or not they are the active client, so the settings page can show each
+	// one's state before switching.
+	healthChecker.SetTransmission(health.PingFunc(func(ctx context.Context) error {
+		_, err := downloadClientService.TestClient(ctx, services.DownloadClientTransmission, nil)
+		return err
+	}))
+	healthChecker.SetDeluge(health.PingFunc(func(ctx context.Context) error {
+		_, err := downloadClientService.TestClient(ctx, services.DownloadClientDeluge, nil)
+		return err
+	}))
 	healthMonitor := health.NewHealthMonitor(healthChecker)
 	healthMonitor.SetHistoryRepo(repos.ConnectionHistory)
 	degradationService := services.NewDegradationServiceWithCache(healthMonitor, offlineCache)
@@ -309,6 +363,8 @@ func main() {
 		CircuitBreakerFailureThreshold: cfg.CircuitBreakerFailureThreshold,
 		CircuitBreakerTimeoutSeconds:   cfg.CircuitBreakerTimeoutSeconds,
 	}, tmdbService)
+	metadataService.RegisterMetrics(metrics.Default)
+	retryService.RegisterMetrics(metrics.Default)
 
 	// Initialize metadata editor service for manual editing (Story 3.8)
 	imageProcessor, err := images.NewImageProcessor(posterDir)
@@ -336,6 +392,15 @@ func main() {
 	// Wire retry service to metadata service for automatic retry queueing
 	metadataService.SetRetryService(retryService)
 
+	// Outbound notifications: webhook/Discord/Telegram/ntfy/Gotify/SMTP targets
+	// subscribed to scan, subtitle, request, backup and degradation events.
+	// Failed deliveries ride the retry queue under the "notification" task type.
+	notificationService := services.NewNotificationService(repos.Notifications, secretsService, notify.NewDispatcher(), slog.Default())
+	notificationService.SetRetryQueue(retryService)
+	retryExecutor.SetNotificationRedeliverer(notificationService)
+	backupScheduler.SetNotifier(notificationService)
+	healthMonitor.SetDegradationHandler(notificationService.DegradationChanged)
+
 	// Set up retry event handler for notifications and stats tracking (Story 3.11 - AC2, AC3)
 	retryService.SetEventHandler(func(event retry.Event) {
 		statsCtx := context.Background()
@@ -382,6 +447,8 @@ func main() {
 	// Initialize SSE hub for real-time event broadcasting
 	sseHub := sse.NewHub()
 	defer sseHub.Close()
+	metrics.Default.NewGaugeFunc("vido_sse_clients", "Connected SSE clients.", nil,
+		func(emit metrics.Emit) { emit(float64(sseHub.ClientCount())) })
 	slog.Info("SSE hub initialized")
 
 	// Initialize scanner service for media library scanning (Story 7.1)
@@ -394,6 +461,8 @@ func main() {
 	)
 	scannerService.SetLibraryRepo(repos.MediaLibraries) // Story 7b-5: DB-based library scanning
 	scannerService.SetEpisodeRepo(repos.Episodes)       // Story 9c-3: series file_size aggregation
+	scannerService.SetOrganizedSources(repos.OrganizedSources)
+	scannerService.SetNotifier(notificationService)
 
 	// TV routing (bugfix-b): without this the scanner writes every scanned file to `movies`,
 	// which is what left series/seasons/episodes empty while the movie table filled up with
@@ -425,6 +494,35 @@ func main() {
 	// parse_status=pending. Without a series repo the enrichment pass would leave them
 	// unmatched forever.
 	enrichmentService.SetSeriesRepo(repos.Series)
+	// Movie collections: enrichment records each TMDb match's collection;
+	// ownership and requests reuse the availability and request services.
+	collectionService := services.NewCollectionService(repos.Collections, tmdbService,
+		availabilityService, requestService, repos.Movies, slog.Default())
+	enrichmentService.SetCollectionRecorder(collectionService)
+	// Episode calendar: reads show and season details through the TMDb cache.
+	calendarService := services.NewCalendarService(repos.Series, repos.Episodes, tmdbService, slog.Default())
+	calendarService.SetFeedTokenStore(repos.Settings, cfg.CalendarToken)
+	// Missing-episode detection: diffs local episodes against TMDb seasons and
+	// requests gaps through the partial-request path.
+	episodeGapService := services.NewEpisodeGapService(repos.Series, repos.Episodes, tmdbService, requestService, slog.Default())
+	// Duplicate review: ranks copies on stored/probed tech info; trashed files
+	// land under the data dir.
+	duplicateService := services.NewDuplicateService(repos.Movies, repos.Series, repos.Episodes,
+		ffprobeService, filepath.Join(cfg.DataDir, "trash"), slog.Default())
+	// Quality profiles: judged per library once a day; shortfalls go to the
+	// *arr as a search, or become built-in upgrade requests the poller holds
+	// until the file on disk meets the profile.
+	qualityProfileService := services.NewQualityProfileService(repos.QualityProfiles)
+	mediaLibraryService.SetQualityProfiles(repos.QualityProfiles)
+	qualityUpgradeService := services.NewQualityUpgradeService(repos.MediaLibraries, repos.QualityProfiles,
+		repos.Movies, repos.Series, repos.Episodes, repos.Requests, pluginManager, ffprobeService, slog.Default())
+	// Indexers: the built-in fulfilment path. Requests no *arr serves (and
+	// built-in upgrade requests) are matched against Torznab/Newznab/RSS
+	// releases every 15 minutes and the best grab goes to qBittorrent.
+	indexerClient := indexer.NewClient()
+	indexerService := services.NewIndexerService(repos.Indexers, secretsService, indexerClient, slog.Default())
+	builtinFulfilmentService := services.NewBuiltinFulfilmentService(repos.Indexers, secretsService, indexerClient,
+		repos.Requests, tmdbService, downloadService, pluginManager, repos.Settings, repos.QualityProfiles, slog.Default())
 
 	// Wire post-scan auto-enrichment: after scan completes with new/updated files,
 	// automatically trigger metadata enrichment in background.
@@ -487,8 +585,18 @@ func main() {
 	// so the poller's title-level completion rule needs the episode-level
 	// refinement the request service already computes.
 	requestStatusPoller.SetSelectionOwnershipChecker(requestService)
+	requestStatusPoller.SetNotifier(notificationService)
+	requestStatusPoller.SetUpgradeChecker(qualityUpgradeService)
 	slog.Info("Request status poller initialized")
 
+	// Post-download importer: finished request and "vido" torrents are
+	// hardlinked into their library and ingested directly, seeding untouched.
+	downloadImportService := services.NewDownloadImportService(
+		downloadService, repos.Requests, repos.MediaLibraries, parserService,
+		repos.DownloadImports, scannerService, slog.Default(),
+	)
+	downloadImportService.SetUpgradeReplacement(repos.Movies, filepath.Join(cfg.DataDir, "trash"))
+
 	// Initialize subtitle engine components (Story 8.1-8.8)
 	subtitleConverter, _ := subtitle.NewConverter()
 	subtitleScorer := subtitle.NewScorer(subtitle.NewDefaultScorerConfig())
@@ -541,6 +649,7 @@ func main() {
 		keyResolver, cfg.ASRBaseURL, cfg.ASRModel, slog.Default(), ai.WithWhisperGovernor(aiGovernor))
 	transcriptionService := services.NewTranscriptionService(audioExtractorService, asrHolder, sseHub, slog.Default())
 	transcriptionService.SetRunBudgetUSD(cfg.AIRunBudgetUSD)
+	transcriptionService.SetSpendGuard(aiSpendService)
 	// 9R-10: wire the per-show glossary + OpenCC safety net + atomic placer
 	// into the Route C generation pipeline.
 	transcriptionService.SetGlossaryRepository(repos.Glossary)
@@ -574,12 +683,41 @@ func main() {
 	// declines with ErrAINotConfigured while no key resolves and starts working
 	// the moment one is saved. The old `if cfg.HasClaudeKey()` guard left these
 	// nil forever on a keyless boot, so a key added later reached nothing.
-	terminologyService := services.NewTerminologyCorrectionService(claudeHolder)
-	translationService := services.NewTranslationService(claudeHolder, sseHub)
+	//
+	// AI_PROVIDER=openai_compat swaps the holder for a self-hosted chat model:
+	// translation and terminology then run on the NAS at $0, the ASR_BASE_URL
+	// precedent. It needs no key, so there is nothing to hot-reload.
+	var textCompleter ai.TextCompleter = claudeHolder
+	translationModelID := cfg.GetClaudeModel()
+	if cfg.HasSelfHostedAI() {
+		textCompleter = ai.NewOpenAICompatProvider(cfg.AIBaseURL, cfg.AIModel,
+			ai.WithOpenAICompatAPIKey(cfg.AIAPIKey), ai.WithOpenAICompatGovernor(aiGovernor))
+		translationModelID = cfg.AIModel
+	}
+	terminologyService := services.NewTerminologyCorrectionService(textCompleter)
+	translationService := services.NewTranslationService(textCompleter, sseHub)
 	subtitleEngine.SetTerminologyService(terminologyService)
+	// Subtitle timing alignment: an embedded text track first, then the file's
+	// own audio through ASR. ASR transcribes the whole film, so even a free
+	// self-hosted one runs only when /subtitles/sync names it — the engine and
+	// the manual download stay on the embedded track.
+	subtitleAligner := subtitle.NewAligner(slog.Default(),
+		subtitle.NewEmbeddedTrackReference(ffprobeService, subtitle.NewExtractor(0, slog.Default())),
+		subtitle.NewSpeechReference(transcriptionService, ai.IsSelfHostedASRBaseURL(cfg.ASRBaseURL)),
+	)
+	subtitleEngine.SetAligner(subtitleAligner)
+	// Bitmap subtitle OCR: Blu-ray PGS and DVD VobSub tracks read by a local
+	// tesseract. It costs nothing, so both routers below may take it without
+	// cost consent; a boot without tesseract just leaves the route off.
+	subtitleOCR := subtitle.NewOCR(
+		subtitle.NewExtractor(0, slog.Default()),
+		subtitle.NewTesseract(0, slog.Default()),
+		slog.Default(),
+	)
 	transcriptionService.SetTranslationService(translationService)
 	slog.Info("AI services wired through the key holder",
-		"claude_configured", claudeHolder.IsConfigured(ctx))
+		"claude_configured", claudeHolder.IsConfigured(ctx),
+		"self_hosted_model", cfg.HasSelfHostedAI())
 	slog.Info("Subtitle engine initialized", "providers", len(subtitleProviders))
 
 	// ── Subtitle generation pipeline (sub-1-6: D5 flag seam + FR13 + FR23) ──
@@ -601,7 +739,9 @@ func main() {
 	// sub-2-1a AC #5 re-point: the gate now asks the RESOLVER, not the boot-time
 	// env snapshot, so saving a key in the settings page un-gates the pipeline
 	// without a restart. Still a plain func() bool — no Rule 20 bump owed.
-	subtitleCapabilityGate := func() bool { return keyResolver.Has(context.Background(), services.KeyClaude) }
+	subtitleCapabilityGate := func() bool {
+		return cfg.HasSelfHostedAI() || keyResolver.Has(context.Background(), services.KeyClaude)
+	}
 	// CR sub-2-1a H1: construction is gated ONLY by the mode flag. Keeping
 	// `&& subtitleCapabilityGate()` here froze the gate's boot-time value into
 	// EXISTENCE — a keyless boot never built the pool, so a key saved later from
@@ -614,8 +754,9 @@ func main() {
 			ffprobeService,
 			subtitle.NewExtractor(0, slog.Default()),
 			slog.Default(),
+			subtitle.WithRouterOCR(subtitleOCR),
 		)
-		modelID := cfg.GetClaudeModel()
+		modelID := translationModelID
 		// sub-3-1: the ASR fallback port + the sweep's availability gate share
 		// one adapter over the SAME transcription service the manual Route-C
 		// dialog uses, so a no_text_source movie is recovered by exactly the
@@ -639,9 +780,15 @@ func main() {
 			// its shared ceiling; only budget-less entries get this envelope.
 			subtitle.WithRunBudgetUSD(cfg.AIRunBudgetUSD),
 			subtitle.WithSpeechTranscriber(pipelineASR),
+			// Per-library bilingual output; a run request may override it.
+			subtitle.WithOutputModePolicy(outputModePolicyAdapter{libraries: repos.MediaLibraries}),
 			// AC #6: FR33/P8 progress. Same event type and payload shape the
 			// search path already broadcasts — sse/hub.go stays untouched.
-			subtitle.WithProgress(subtitle.NewSSEProgressHook(sseHub)),
+			// Terminal stages also go out to the notification targets.
+			subtitle.WithProgress(subtitle.ComposeProgress(
+				subtitle.NewSSEProgressHook(sseHub),
+				subtitle.NewNotifyProgressHook(notificationService, subtitlePipelineMedia),
+			)),
 		)
 		subtitlePipelinePool = subtitle.NewWorkerPool(subtitlePipeline, slog.Default(),
 			subtitle.WithCandidateFinders(repos.Movies, repos.Episodes),
@@ -736,6 +883,42 @@ func main() {
 	doubanRatingHandler := handlers.NewDoubanRatingHandler(doubanRatingService)
 	settingsHandler := handlers.NewSettingsHandler(settingsService)
 	setupHandler := handlers.NewSetupHandler(setupService)
+	// Accounts and sessions. An install with no account stays open (upgrades
+	// must not lock anyone out); VIDO_ADMIN_USERNAME/PASSWORD seed the first
+	// admin so a headless deployment is locked from its first boot.
+	authService := services.NewAuthService(repos.Users, repos.Sessions,
+		time.Duration(cfg.SessionTTLHours)*time.Hour, slog.Default())
+	if err := authService.EnsureAdmin(ctx, cfg.AdminUsername, cfg.AdminPassword); err != nil {
+		slog.Error("Failed to seed admin account", "error", err)
+	}
+	if required, err := authService.AuthRequired(ctx); err == nil && !required {
+		slog.Warn("No user accounts exist — the API is unauthenticated until an admin is created via POST /api/v1/auth/bootstrap or VIDO_ADMIN_USERNAME/VIDO_ADMIN_PASSWORD")
+	}
+	authHandler := handlers.NewAuthHandler(authService)
+	// Per-user watch history, progress and "continue watching".
+	watchService := services.NewWatchService(repos.WatchState, repos.Movies, repos.Series, repos.Episodes, slog.Default())
+	watchHandler := handlers.NewWatchHandler(watchService)
+	// Media servers: placed subtitles, localized NFOs and organized files
+	// trigger a debounced refresh; Jellyfin/Emby played state can be imported
+	// into the watch history.
+	mediaServerService := services.NewMediaServerService(pluginManager, repos.Settings, secretsService,
+		repos.Users, repos.Movies, repos.Episodes, watchService, slog.Default())
+	subtitlePlacer.SetNotifier(mediaServerService)
+	mediaServerHandler := handlers.NewMediaServerHandler(mediaServerService, "jellyfin", "emby", "plex")
+	// Trakt.tv: device-code link, history/ratings import, watchlist <-> requests,
+	// collection export, and scrobbling of reported playback.
+	traktService := services.NewTraktService(repos.Settings, secretsService,
+		repos.Movies, repos.Series, repos.Episodes, watchService, repos.UserRatings,
+		requestService, repos.Requests, slog.Default())
+	watchService.SetPlaybackObserver(traktService)
+	traktHandler := handlers.NewTraktHandler(traktService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	episodeGapHandler := handlers.NewEpisodeGapHandler(episodeGapService)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateService)
+	qualityProfileHandler := handlers.NewQualityProfileHandler(qualityProfileService, qualityUpgradeService)
+	indexerHandler := handlers.NewIndexerHandler(indexerService, builtinFulfilmentService)
 	mediaHandler := handlers.NewMediaHandler(mediaService)
 	availabilityHandler := handlers.NewAvailabilityHandler(availabilityService) // Story 10-4
 	tmdbHandler := handlers.NewTMDbHandler(tmdbService)
@@ -760,6 +943,8 @@ func main() {
 	serviceHealthHandler.SetHistoryService(connectionHistoryService)
 	qbittorrentHandler := handlers.NewQBittorrentHandler(qbittorrentService)
 	downloadHandler := handlers.NewDownloadHandler(downloadService)
+	downloadControlHandler := handlers.NewDownloadControlHandler(downloadService)
+	downloadClientHandler := handlers.NewDownloadClientHandler(downloadClientService)
 	libraryService := services.NewLibraryService(repos.Movies, repos.Series, repos.Episodes, services.WithTMDbVideos(tmdbService.VideosProvider()))
 	// Unified search takes the library service as its local leg — owned items
 	// stay searchable when TMDb is unreachable (testsprite-round1 TC092).
@@ -774,6 +959,12 @@ func main() {
 		mediaLibraryService,
 		handlers.WithAutoSubtitleSupport(cfg.SubtitlePipelineEnabled),
 	)
+	organizerService := services.NewOrganizerService(
+		repos.MediaLibraries, repos.Movies, repos.Series, repos.Episodes, repos.OrganizedSources, slog.Default(),
+	)
+	organizerService.SetChangeNotifier(mediaServerService)
+	downloadImportService.SetChangeNotifier(mediaServerService)
+	organizerHandler := handlers.NewOrganizerHandler(organizerService)
 	exploreBlocksHandler := handlers.NewExploreBlocksHandler(exploreBlockService)                // Story 10.3
 	filterPresetsHandler := handlers.NewFilterPresetsHandler(filterPresetService)                // Story 11.4
 	requestHandler := handlers.NewRequestHandler(requestService)                                 // Story 13-1a
@@ -802,12 +993,14 @@ func main() {
 	if nfoLocalizer != nil {
 		// 9R-13a: episode enumeration behind ?include_episodes=true.
 		nfoLocalizer.SetEpisodeLister(repos.Episodes)
+		nfoLocalizer.SetChangeNotifier(mediaServerService)
 	}
 	nfoLocalizerHandler := handlers.NewNFOLocalizerHandler(movieService, seriesService, repos.Episodes, nfoLocalizer)
 	subtitleHandler := handlers.NewSubtitleHandler(
 		subtitleProviders, subtitleScorer, subtitleConverter, subtitlePlacer,
 		sseHub, repos.Movies, repos.Series,
 	)
+	subtitleHandler.SetAligner(subtitleAligner)
 	// Wire batch processor (Story 8-9)
 	batchCollector := subtitle.NewRepoCollector(repos.Movies, repos.Series, repos.Episodes)
 	// sub-1-6 AC #1: the D5 seam. A nil ItemProcessor IS legacy mode, so in
@@ -840,6 +1033,7 @@ func main() {
 	}
 	generationBatchProcessor := services.NewGenerationBatchProcessor(
 		generationRunner, repos.Movies, repos.Episodes, sseHub, cfg.AIRunBudgetUSD, slog.Default())
+	generationBatchProcessor.SetSpendGuard(aiSpendService)
 	generationBatchHandler := handlers.NewGenerationBatchHandler(generationBatchProcessor)
 
 	// Cost preview (story sub-4-1): what would generating subtitles cost, per
@@ -859,8 +1053,11 @@ func main() {
 		// sub-5-3 AC #1: the F15 group headers' series titles — one memoized
 		// lookup per series per sweep, nil-safe fail-soft inside the service.
 		repos.Series,
+		// The same OCR as the pipeline's router, so an OCR-readable bitmap
+		// track is quoted as the free extraction it will be.
 		routePredictorAdapter{router: subtitle.NewRouter(
-			ffprobeService, subtitle.NewExtractor(0, slog.Default()), slog.Default())},
+			ffprobeService, subtitle.NewExtractor(0, slog.Default()), slog.Default(),
+			subtitle.WithRouterOCR(subtitleOCR))},
 		ai.IsSelfHostedASRBaseURL(cfg.ASRBaseURL),
 		// sub-5-1 AC #5: the F15 prefill source — the envelope carries the
 		// operator's real default instead of a frontend constant.
@@ -919,10 +1116,15 @@ func main() {
 
 	// Register routes
 	router.GET("/health", handlers.HealthCheckHandler(db))
+	router.GET("/metrics", handlers.MetricsHandler(metrics.Default, cfg.MetricsToken))
+	router.GET("/calendar.ics", handlers.CalendarFeedHandler(calendarService))
 
 	// API v1 routes with handler → service → repository architecture
-	apiV1 := router.Group("/api/v1")
+	// Every /api/v1 route requires a session once an account exists;
+	// handlers.AdminRoutes lists the ones reserved for admins.
+	apiV1 := router.Group("/api/v1", handlers.RequireAuth(authService, handlers.AdminRoutes))
 	{
+		authHandler.RegisterRoutes(apiV1) // /api/v1/auth/* + /api/v1/users (admin)
 		movieHandler.RegisterRoutes(apiV1)
 		seriesHandler.RegisterRoutes(apiV1)
 		doubanRatingHandler.RegisterRoutes(apiV1)  // /{movies,series}/:id/douban-rating (12-1) + /douban-review-summary (12-6)
@@ -946,13 +1148,27 @@ func main() {
 		handlers.RegisterRetryRoutes(apiV1, retryHandler)
 		qbittorrentHandler.RegisterRoutes(apiV1)
 		downloadHandler.RegisterRoutes(apiV1)
+		downloadControlHandler.RegisterRoutes(apiV1)
+		downloadClientHandler.RegisterRoutes(apiV1)
+		traktHandler.RegisterRoutes(apiV1)
+		aiUsageHandler.RegisterRoutes(apiV1)
 		libraryHandler.RegisterRoutes(apiV1)
 		mediaLibrariesHandler.RegisterRoutes(apiV1) // /api/v1/libraries CRUD (Story 7b-2)
+		organizerHandler.RegisterRoutes(apiV1)      // POST /api/v1/libraries/:id/organize — rename into the library template
+		watchHandler.RegisterRoutes(apiV1)          // /api/v1/watch/* — per-user progress, watched flags, continue watching
 		exploreBlocksHandler.RegisterRoutes(apiV1)  // /api/v1/explore-blocks CRUD + content (Story 10.3)
 		filterPresetsHandler.RegisterRoutes(apiV1)  // /api/v1/filter-presets CRUD (Story 11.4)
 		requestHandler.RegisterRoutes(apiV1)        // /api/v1/requests create+list (Story 13-1a, Epic 13)
 		glossaryHandler.RegisterRoutes(apiV1)       // /api/v1/media/:id/glossary CRUD (Story 9R-15)
 		dvrSettingsHandler.RegisterRoutes(apiV1)    // /api/v1/settings/radarr triad + profiles/root-folders passthrough (Story 13-4a)
+		mediaServerHandler.RegisterRoutes(apiV1)    // /api/v1/settings/{jellyfin,emby,plex} + refresh / import-watch-state
+		notificationHandler.RegisterRoutes(apiV1)   // /api/v1/settings/notifications targets CRUD + test send
+		collectionHandler.RegisterRoutes(apiV1)     // /api/v1/collections — franchises with owned/missing parts
+		calendarHandler.RegisterRoutes(apiV1)       // /api/v1/calendar — upcoming and missing episodes (feed: /calendar.ics)
+		episodeGapHandler.RegisterRoutes(apiV1)     // /api/v1/series/gaps + /series/:id/gaps — missing episodes, bulk request
+		duplicateHandler.RegisterRoutes(apiV1)      // /api/v1/library/duplicates — ranked copies + keep/trash resolve (admin)
+		qualityProfileHandler.RegisterRoutes(apiV1) // /api/v1/settings/quality-profiles CRUD + evaluate (admin)
+		indexerHandler.RegisterRoutes(apiV1)        // /api/v1/settings/indexers CRUD + test + run (admin)
 		recentMediaHandler.RegisterRoutes(apiV1)
 		scannerHandler.RegisterRoutes(apiV1)
 		subtitleHandler.RegisterRoutes(apiV1)
@@ -996,6 +1212,18 @@ func main() {
 	go scanScheduler.Start(scanSchedulerCtx)
 	slog.Info("Scan scheduler started")
 
+	// Start library watcher: incremental scans between scheduled ones.
+	libraryWatcherCtx, libraryWatcherCancel := context.WithCancel(context.Background())
+	libraryWatcher := services.NewLibraryWatcher(scannerService, 0, slog.Default())
+	if cfg.LibraryWatch {
+		go func() {
+			if err := libraryWatcher.Start(libraryWatcherCtx); err != nil {
+				slog.Warn("Library watcher unavailable — relying on scheduled scans", "error", err)
+			}
+		}()
+		slog.Info("Library watcher started")
+	}
+
 	// Start cache sweep scheduler (infra-cache-entries-expiry-sweep)
 	cacheSweepCtx, cacheSweepCancel := context.WithCancel(context.Background())
 	go cacheSweepScheduler.Start(cacheSweepCtx)
@@ -1025,11 +1253,31 @@ func main() {
 		slog.Error("Failed to start plugin health scheduler", "error", err)
 		// Non-fatal — settings endpoints still work; health refreshes on save
 	}
+	// Plex entry of /health/services, mirroring the sweep above.
+	go healthMonitor.StartPlexMonitoring(monitorCtx)
+	go healthMonitor.StartTransmissionMonitoring(monitorCtx)
+	go healthMonitor.StartDelugeMonitoring(monitorCtx)
 
 	// Start request status poller (Story 13-3a — 15s reconcile loop)
 	requestPollerCtx, requestPollerCancel := context.WithCancel(context.Background())
 	go requestStatusPoller.Start(requestPollerCtx)
 
+	// Start the post-download importer
+	downloadImportCtx, downloadImportCancel := context.WithCancel(context.Background())
+	go downloadImportService.Start(downloadImportCtx)
+
+	// Start the background Trakt sync
+	traktCtx, traktCancel := context.WithCancel(context.Background())
+	go traktService.Start(traktCtx)
+
+	// Start the quality upgrade evaluator (daily pass)
+	qualityUpgradeCtx, qualityUpgradeCancel := context.WithCancel(context.Background())
+	go qualityUpgradeService.Start(qualityUpgradeCtx)
+
+	// Start built-in fulfilment (indexer grabs every 15 minutes)
+	builtinFulfilmentCtx, builtinFulfilmentCancel := context.WithCancel(context.Background())
+	go builtinFulfilmentService.Start(builtinFulfilmentCtx)
+
 	// Start the subtitle generation worker pool (sub-1-6 AC #3 — fixed
 	// concurrency 2 per AD #5/NFR-P3). nil in legacy mode.
 	subtitlePipelineCtx, subtitlePipelineCancel := context.WithCancel(context.Background())
@@ -1069,6 +1317,11 @@ func main() {
 	scanSchedulerCancel()
 	scanScheduler.Stop()
 
+	// Stop library watcher
+	slog.Info("Stopping library watcher...")
+	libraryWatcherCancel()
+	libraryWatcher.Stop()
+
 	// Stop cache sweep scheduler (infra-cache-entries-expiry-sweep)
 	slog.Info("Stopping cache sweep scheduler...")
 	cacheSweepCancel()
@@ -1084,6 +1337,26 @@ func main() {
 	requestPollerCancel()
 	requestStatusPoller.Stop()
 
+	// Stop the post-download importer
+	slog.Info("Stopping download importer...")
+	downloadImportCancel()
+	downloadImportService.Stop()
+
+	// Stop the background Trakt sync
+	slog.Info("Stopping Trakt sync...")
+	traktCancel()
+	traktService.Stop()
+
+	// Stop quality upgrade evaluator
+	slog.Info("Stopping quality upgrade evaluator...")
+	qualityUpgradeCancel()
+	qualityUpgradeService.Stop()
+
+	// Stop built-in fulfilment
+	slog.Info("Stopping built-in fulfilment...")
+	builtinFulfilmentCancel()
+	builtinFulfilmentService.Stop()
+
 	// Stop subtitle generation worker pool (sub-1-6 AC #3)
 	subtitlePipelineCancel()
 	if subtitlePipelinePool != nil {
@@ -1095,6 +1368,7 @@ func main() {
 	slog.Info("Stopping plugin health scheduler...")
 	pluginManagerCancel()
 	pluginManager.Stop()
+	mediaServerService.Stop()
 
 	// Stop backup scheduler
 	slog.Info("Stopping backup scheduler...")
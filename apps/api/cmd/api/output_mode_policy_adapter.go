@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+)
+
+// outputModePolicyAdapter answers "which subtitle layout does this library
+// want" from the media_libraries table. It sits in cmd/api for the same Rule
+// 19 reason as autoSubtitlePolicyAdapter.
+type outputModePolicyAdapter struct {
+	libraries repository.MediaLibraryRepositoryInterface
+}
+
+// SubtitleOutputMode returns the library's mode. The error is returned, not
+// swallowed: the pipeline logs it and falls back to translated-only.
+func (a outputModePolicyAdapter) SubtitleOutputMode(ctx context.Context, libraryID string) (models.SubtitleOutputMode, error) {
+	lib, err := a.libraries.GetByID(ctx, libraryID)
+	if err != nil {
+		return models.SubtitleOutputDefault, err
+	}
+	return lib.SubtitleOutputMode, nil
+}
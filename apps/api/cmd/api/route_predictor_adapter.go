@@ -23,8 +23,9 @@ type routePredictorAdapter struct {
 }
 
 // FromTracks classifies an already-persisted track list — no disk access.
+// It goes through the router so a bitmap track its OCR can read counts.
 func (a routePredictorAdapter) FromTracks(tracks []services.SubtitleTrack) services.RoutePrediction {
-	return services.RoutePrediction(subtitle.PredictFromTracks(tracks))
+	return services.RoutePrediction(a.router.PredictTracks(tracks))
 }
 
 // Probe classifies a file by probing it. Never extracts — that is the whole
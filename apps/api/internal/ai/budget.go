@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 )
 
 // ModelPricing is the per-1M-token USD price for an LLM model (Story 9R-11
@@ -39,7 +40,24 @@ var fallbackLLMPricing = ModelPricing{InputPer1M: 1.0, OutputPer1M: 5.0}
 // whisperPerMinuteUSD is the OpenAI Whisper API price per audio minute.
 const whisperPerMinuteUSD = 0.006
 
+// selfHostedModels holds the model ids served from the operator's own hardware
+// (OpenAICompatProvider registers its model at construction). They price at
+// $0 — the LLM twin of EstimatedASRPerMinuteUSD(true) — and win over the table,
+// since a local server may well be serving a model under a hosted name.
+var selfHostedModels sync.Map // model id -> struct{}
+
+// RegisterSelfHostedModel marks model as served locally, so PricingFor, the
+// Budget and the spend ledger price it at $0.
+func RegisterSelfHostedModel(model string) {
+	if model != "" {
+		selfHostedModels.Store(model, struct{}{})
+	}
+}
+
 func llmPricing(model string) ModelPricing {
+	if _, ok := selfHostedModels.Load(model); ok {
+		return ModelPricing{}
+	}
 	if p, ok := defaultLLMPricing[model]; ok {
 		return p
 	}
@@ -50,7 +68,8 @@ func llmPricing(model string) ModelPricing {
 // FORWARD cost estimate (story sub-4-1) quotes exactly what the run will later
 // be billed at. Deliberately a read-through to llmPricing rather than a second
 // table: two copies of a price list drift, and the drift shows up as a quote
-// the invoice disagrees with.
+// the invoice disagrees with. A self-hosted model (RegisterSelfHostedModel)
+// quotes $0.
 func PricingFor(model string) ModelPricing { return llmPricing(model) }
 
 // HostedASRPerMinuteUSD is the per-audio-minute price of the hosted Whisper
@@ -81,6 +100,51 @@ func EstimatedASRPerMinuteUSD(selfHosted bool) float64 {
 	return whisperPerMinuteUSD
 }
 
+// llmCost prices one LLM call from the same table the Budget and the
+// estimators use.
+func llmCost(model string, inputTokens, outputTokens int64) float64 {
+	p := llmPricing(model)
+	return float64(inputTokens)/1_000_000*p.InputPer1M + float64(outputTokens)/1_000_000*p.OutputPer1M
+}
+
+// UsageObserver sees every metered AI call, whether or not a run Budget is on
+// the ctx — the non-capping observability counter the parse-path metering
+// ruling in services/ai_service.go deferred. metrics.AIUsage implements it and
+// main.go installs it; this package stays a leaf.
+type UsageObserver interface {
+	ObserveLLM(provider, model string, inputTokens, outputTokens int64, costUSD float64)
+	ObserveASR(model string, audioSeconds, costUSD float64, selfHosted bool)
+}
+
+type usageObserverBox struct{ o UsageObserver }
+
+var usageObserver atomic.Value // usageObserverBox
+
+// SetUsageObserver installs the process-wide usage observer (nil removes it).
+func SetUsageObserver(o UsageObserver) {
+	usageObserver.Store(usageObserverBox{o: o})
+}
+
+func currentUsageObserver() UsageObserver {
+	box, _ := usageObserver.Load().(usageObserverBox)
+	return box.o
+}
+
+// observeLLM reports one LLM call to the usage observer, if any.
+func observeLLM(provider ProviderName, model string, inputTokens, outputTokens int64) {
+	if o := currentUsageObserver(); o != nil {
+		o.ObserveLLM(string(provider), model, inputTokens, outputTokens, llmCost(model, inputTokens, outputTokens))
+	}
+}
+
+// observeASR reports one transcription to the usage observer, if any, at the
+// same rate the Budget is charged.
+func observeASR(model string, audioSeconds, perMinuteUSD float64, selfHosted bool) {
+	if o := currentUsageObserver(); o != nil {
+		o.ObserveASR(model, audioSeconds, audioSeconds/60.0*perMinuteUSD, selfHosted)
+	}
+}
+
 // Budget meters token usage and cost for one batch run and enforces an optional
 // USD ceiling (Story 9R-11 AC #2). It is created per run (per transcription /
 // translation job) and carried through the call chain via context, so a batch
@@ -118,8 +182,7 @@ func (b *Budget) RecordLLM(model string, inputTokens, outputTokens int64) {
 	if b == nil {
 		return
 	}
-	p := llmPricing(model)
-	cost := float64(inputTokens)/1_000_000*p.InputPer1M + float64(outputTokens)/1_000_000*p.OutputPer1M
+	cost := llmCost(model, inputTokens, outputTokens)
 	b.mu.Lock()
 	b.inputTokens += inputTokens
 	b.outputTokens += outputTokens
@@ -86,3 +86,37 @@ func TestBudget_RecordASRWithRate_NilSafe(t *testing.T) {
 	b.RecordASRWithRate(60, 0.006) // no panic
 	assert.Zero(t, b.SpentUSD())
 }
+
+type recordingUsageObserver struct {
+	llmProvider, llmModel string
+	in, out               int64
+	llmCost               float64
+	asrSeconds, asrCost   float64
+	asrSelfHosted         bool
+}
+
+func (r *recordingUsageObserver) ObserveLLM(provider, model string, in, out int64, costUSD float64) {
+	r.llmProvider, r.llmModel, r.in, r.out, r.llmCost = provider, model, in, out, costUSD
+}
+
+func (r *recordingUsageObserver) ObserveASR(model string, audioSeconds, costUSD float64, selfHosted bool) {
+	r.asrSeconds, r.asrCost, r.asrSelfHosted = audioSeconds, costUSD, selfHosted
+}
+
+func TestUsageObserver_SeesBudgetPricing(t *testing.T) {
+	obs := &recordingUsageObserver{}
+	SetUsageObserver(obs)
+	t.Cleanup(func() { SetUsageObserver(nil) })
+
+	observeLLM(ProviderClaude, "claude-haiku-4-5", 500_000, 200_000)
+	assert.Equal(t, "claude", obs.llmProvider)
+	assert.Equal(t, int64(500_000), obs.in)
+	assert.InDelta(t, 1.5, obs.llmCost, 1e-9, "same cost the Budget would charge")
+
+	observeASR("whisper-1", 120, whisperPerMinuteUSD, false)
+	assert.InDelta(t, 0.012, obs.asrCost, 1e-9)
+	assert.False(t, obs.asrSelfHosted)
+
+	SetUsageObserver(nil)
+	observeLLM(ProviderClaude, "claude-haiku-4-5", 1, 1) // no observer, no panic
+}
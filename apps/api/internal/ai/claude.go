@@ -283,6 +283,8 @@ func (p *ClaudeProvider) send(ctx context.Context, params anthropic.MessageNewPa
 	if b := BudgetFromContext(ctx); b != nil {
 		b.RecordLLM(p.model, msg.Usage.InputTokens, msg.Usage.OutputTokens)
 	}
+	observeLLM(ProviderClaude, p.model, msg.Usage.InputTokens, msg.Usage.OutputTokens)
+	recordUsageLLM(ctx, ProviderClaude, p.model, msg.Usage.InputTokens, msg.Usage.OutputTokens)
 	return msg, nil
 }
 
@@ -8,7 +8,8 @@ import (
 
 // FactoryConfig contains configuration for creating AI providers.
 type FactoryConfig struct {
-	// ProviderName is the name of the AI provider to use ("gemini" or "claude").
+	// ProviderName is the name of the AI provider to use ("gemini", "claude"
+	// or "openai_compat").
 	ProviderName string
 	// GeminiAPIKey is the API key for Gemini.
 	GeminiAPIKey string
@@ -17,6 +18,13 @@ type FactoryConfig struct {
 	// ClaudeModel optionally overrides the Claude model id (9R-1).
 	// Empty uses DefaultClaudeModel.
 	ClaudeModel string
+	// OpenAICompatBaseURL is the API root of a self-hosted OpenAI-compatible
+	// chat server (e.g. "http://nas:11434/v1" for Ollama).
+	OpenAICompatBaseURL string
+	// OpenAICompatModel is the model id that server serves. Required.
+	OpenAICompatModel string
+	// OpenAICompatAPIKey is an optional bearer token for that server.
+	OpenAICompatAPIKey string
 	// Governor is the shared AI throttle (sub-5-1 AC #2). Nil = unthrottled —
 	// today's parse-path behavior; wiring it lets the factory-built providers
 	// share the process-wide concurrency/QPS pool.
@@ -32,7 +40,7 @@ func NewProvider(cfg FactoryConfig) (Provider, error) {
 	if !providerName.IsValid() {
 		slog.Warn("Invalid AI provider name",
 			"provider", cfg.ProviderName,
-			"valid_providers", []string{string(ProviderGemini), string(ProviderClaude)},
+			"valid_providers", []string{string(ProviderGemini), string(ProviderClaude), string(ProviderOpenAICompat)},
 		)
 		return nil, fmt.Errorf("%w: invalid provider name '%s'", ErrAINotConfigured, cfg.ProviderName)
 	}
@@ -57,6 +65,15 @@ func NewProvider(cfg FactoryConfig) (Provider, error) {
 		}
 		return NewClaudeProvider(cfg.ClaudeAPIKey, WithClaudeGovernor(cfg.Governor)), nil
 
+	case ProviderOpenAICompat:
+		if cfg.OpenAICompatBaseURL == "" || cfg.OpenAICompatModel == "" {
+			slog.Error("OpenAI-compatible provider selected but AI_BASE_URL or AI_MODEL not set")
+			return nil, fmt.Errorf("%w: AI_BASE_URL and AI_MODEL must both be configured", ErrAINotConfigured)
+		}
+		slog.Info("Creating OpenAI-compatible AI provider", "base_url", cfg.OpenAICompatBaseURL, "model", cfg.OpenAICompatModel)
+		return NewOpenAICompatProvider(cfg.OpenAICompatBaseURL, cfg.OpenAICompatModel,
+			WithOpenAICompatAPIKey(cfg.OpenAICompatAPIKey), WithOpenAICompatGovernor(cfg.Governor)), nil
+
 	default:
 		return nil, fmt.Errorf("%w: unknown provider '%s'", ErrAINotConfigured, providerName)
 	}
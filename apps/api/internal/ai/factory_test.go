@@ -69,6 +69,25 @@ func TestNewProvider(t *testing.T) {
 			wantErr: true,
 			errType: ErrAINotConfigured,
 		},
+		{
+			name: "openai_compat provider success",
+			cfg: FactoryConfig{
+				ProviderName:        "openai_compat",
+				OpenAICompatBaseURL: "http://nas:11434/v1",
+				OpenAICompatModel:   "qwen2.5:7b",
+			},
+			wantErr: false,
+			wantProvider: ProviderOpenAICompat,
+		},
+		{
+			name: "openai_compat without model",
+			cfg: FactoryConfig{
+				ProviderName:        "openai_compat",
+				OpenAICompatBaseURL: "http://nas:11434/v1",
+			},
+			wantErr: true,
+			errType: ErrAINotConfigured,
+		},
 		{
 			name: "invalid provider name",
 			cfg: FactoryConfig{
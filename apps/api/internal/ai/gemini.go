@@ -230,6 +230,8 @@ func (p *GeminiProvider) Parse(ctx context.Context, req *ParseRequest) (*ParseRe
 		}
 		b.RecordLLM(p.model, usage.PromptTokenCount, usage.CandidatesTokenCount)
 	}
+	observeLLM(ProviderGemini, p.model, geminiResp.UsageMetadata.PromptTokenCount, geminiResp.UsageMetadata.CandidatesTokenCount)
+	recordUsageLLM(ctx, ProviderGemini, p.model, geminiResp.UsageMetadata.PromptTokenCount, geminiResp.UsageMetadata.CandidatesTokenCount)
 
 	// Extract text from response
 	text := geminiResp.GetText()
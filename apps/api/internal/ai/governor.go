@@ -64,13 +64,18 @@ func (g *Governor) Acquire(ctx context.Context) (release func(), err error) {
 
 // governed runs fn under an acquired slot, releasing it afterward. A budget
 // pre-check (ctx) short-circuits before acquiring so an exhausted run stops
-// spending immediately.
+// spending immediately; the global spend caps are checked the same way, so
+// even a budget-less parse call cannot spend past them.
 func governed[T any](ctx context.Context, g *Governor, op string, fn func() (T, error)) (T, error) {
 	var zero T
 	if b := BudgetFromContext(ctx); b != nil && b.Exceeded() {
 		slog.Warn("AI budget exhausted — skipping call", "op", op, "spent_usd", b.SpentUSD())
 		return zero, ErrBudgetExceeded
 	}
+	if err := checkSpend(ctx); err != nil {
+		slog.Warn("AI spend cap reached — skipping call", "op", op, "error", err)
+		return zero, err
+	}
 	release, err := g.Acquire(ctx)
 	if err != nil {
 		return zero, err
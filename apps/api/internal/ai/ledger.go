@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// Usage features — what an AI call was FOR, as the spend ledger groups it.
+const (
+	FeatureParse       = "parse"
+	FeatureTranslate   = "translate"
+	FeatureTerminology = "terminology"
+	FeatureKeyword     = "keyword"
+	FeatureTranscribe  = "transcribe"
+)
+
+// ErrSpendCapReached is returned when the global daily or monthly USD cap is
+// used up. Unlike ErrBudgetExceeded it spans every run, so a busy week cannot
+// spend past what the admin allowed. A SpendGuard wraps it ALONGSIDE
+// ErrBudgetExceeded, so every existing pause-on-ceiling path (generation
+// batch, subtitle batch, translation) treats it as a pause, not a failure.
+var ErrSpendCapReached = errors.New("AI_SPEND_CAP_REACHED: global AI spend cap reached")
+
+// UsageEntry is one metered AI call as the persistent spend ledger stores it.
+// The cost is priced from the same table the Budget uses.
+type UsageEntry struct {
+	Provider     string
+	Model        string
+	Feature      string
+	MediaID      string
+	RunID        string
+	InputTokens  int64
+	OutputTokens int64
+	AudioSeconds float64
+	CostUSD      float64
+}
+
+// UsageLedger persists metered AI calls across runs (the Budget forgets them
+// when the run ends). main.go installs the repository-backed ledger; this
+// package stays a leaf.
+type UsageLedger interface {
+	RecordUsage(ctx context.Context, entry UsageEntry)
+}
+
+// SpendGuard answers "may another AI call spend money right now?" against the
+// global caps. A non-nil error refuses the call.
+type SpendGuard interface {
+	CheckSpend(ctx context.Context) error
+}
+
+type usageLedgerBox struct{ l UsageLedger }
+
+type spendGuardBox struct{ g SpendGuard }
+
+var (
+	usageLedger atomic.Value // usageLedgerBox
+	spendGuard  atomic.Value // spendGuardBox
+)
+
+// SetUsageLedger installs the process-wide usage ledger (nil removes it).
+func SetUsageLedger(l UsageLedger) {
+	usageLedger.Store(usageLedgerBox{l: l})
+}
+
+// SetSpendGuard installs the process-wide spend guard consulted by every
+// governed call (nil removes it).
+func SetSpendGuard(g SpendGuard) {
+	spendGuard.Store(spendGuardBox{g: g})
+}
+
+// checkSpend runs the installed spend guard, if any.
+func checkSpend(ctx context.Context) error {
+	box, _ := spendGuard.Load().(spendGuardBox)
+	if box.g == nil {
+		return nil
+	}
+	return box.g.CheckSpend(ctx)
+}
+
+// recordUsageLLM writes one LLM call to the ledger, tagged from ctx.
+func recordUsageLLM(ctx context.Context, provider ProviderName, model string, inputTokens, outputTokens int64) {
+	recordUsage(ctx, UsageEntry{
+		Provider:     string(provider),
+		Model:        model,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		CostUSD:      llmCost(model, inputTokens, outputTokens),
+	})
+}
+
+// recordUsageASR writes one transcription to the ledger at the rate the Budget
+// was charged. ASR is always the transcribe feature, whatever ctx says.
+func recordUsageASR(ctx context.Context, model string, audioSeconds, perMinuteUSD float64) {
+	recordUsage(WithUsageFeature(ctx, FeatureTranscribe), UsageEntry{
+		Provider:     "whisper",
+		Model:        model,
+		AudioSeconds: audioSeconds,
+		CostUSD:      audioSeconds / 60.0 * perMinuteUSD,
+	})
+}
+
+func recordUsage(ctx context.Context, entry UsageEntry) {
+	box, _ := usageLedger.Load().(usageLedgerBox)
+	if box.l == nil {
+		return
+	}
+	tags := usageTagsFromContext(ctx)
+	entry.Feature, entry.MediaID, entry.RunID = tags.feature, tags.mediaID, tags.runID
+	box.l.RecordUsage(ctx, entry)
+}
+
+// usageTags label ledger rows without changing every method signature — the
+// budgetCtxKey precedent.
+type usageTags struct {
+	feature string
+	mediaID string
+	runID   string
+}
+
+type usageTagsCtxKey struct{}
+
+func usageTagsFromContext(ctx context.Context) usageTags {
+	tags, _ := ctx.Value(usageTagsCtxKey{}).(usageTags)
+	return tags
+}
+
+// WithUsageFeature tags the AI calls made under ctx with a feature.
+func WithUsageFeature(ctx context.Context, feature string) context.Context {
+	tags := usageTagsFromContext(ctx)
+	tags.feature = feature
+	return context.WithValue(ctx, usageTagsCtxKey{}, tags)
+}
+
+// WithUsageMedia tags the AI calls made under ctx with the media they serve.
+func WithUsageMedia(ctx context.Context, mediaID string) context.Context {
+	tags := usageTagsFromContext(ctx)
+	tags.mediaID = mediaID
+	return context.WithValue(ctx, usageTagsCtxKey{}, tags)
+}
+
+// WithUsageRun tags the AI calls made under ctx with a run ID. An existing run
+// ID wins, mirroring the shared Budget: a batch's items are one run.
+func WithUsageRun(ctx context.Context, runID string) context.Context {
+	tags := usageTagsFromContext(ctx)
+	if tags.runID != "" {
+		return ctx
+	}
+	tags.runID = runID
+	return context.WithValue(ctx, usageTagsCtxKey{}, tags)
+}
@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLedger struct{ entries []UsageEntry }
+
+func (r *recordingLedger) RecordUsage(_ context.Context, entry UsageEntry) {
+	r.entries = append(r.entries, entry)
+}
+
+type stubSpendGuard struct{ err error }
+
+func (g stubSpendGuard) CheckSpend(context.Context) error { return g.err }
+
+func TestUsageLedger_TagsFromContext(t *testing.T) {
+	ledger := &recordingLedger{}
+	SetUsageLedger(ledger)
+	t.Cleanup(func() { SetUsageLedger(nil) })
+
+	ctx := WithUsageMedia(WithUsageRun(context.Background(), "batch-1"), "m1")
+	ctx = WithUsageRun(ctx, "job-7") // a batch's items stay in the batch's run
+	recordUsageLLM(WithUsageFeature(ctx, FeatureTranslate), ProviderClaude, "claude-haiku-4-5", 500_000, 200_000)
+	recordUsageASR(ctx, "whisper-1", 120, whisperPerMinuteUSD)
+
+	require.Len(t, ledger.entries, 2)
+	assert.Equal(t, UsageEntry{
+		Provider: "claude", Model: "claude-haiku-4-5", Feature: FeatureTranslate, MediaID: "m1", RunID: "batch-1",
+		InputTokens: 500_000, OutputTokens: 200_000, CostUSD: 1.5,
+	}, ledger.entries[0], "same cost the Budget would charge")
+	assert.Equal(t, FeatureTranscribe, ledger.entries[1].Feature)
+	assert.InDelta(t, 0.012, ledger.entries[1].CostUSD, 1e-9)
+
+	SetUsageLedger(nil)
+	recordUsageLLM(ctx, ProviderClaude, "claude-haiku-4-5", 1, 1) // no ledger, no panic
+}
+
+func TestGoverned_SpendGuardRefuses(t *testing.T) {
+	capErr := errors.New("cap")
+	SetSpendGuard(stubSpendGuard{err: capErr})
+	t.Cleanup(func() { SetSpendGuard(nil) })
+
+	called := false
+	_, err := governed(context.Background(), nil, "test", func() (int, error) {
+		called = true
+		return 1, nil
+	})
+	assert.ErrorIs(t, err, capErr)
+	assert.False(t, called, "a refused call never reaches the provider")
+
+	SetSpendGuard(nil)
+	_, err = governed(context.Background(), nil, "test", func() (int, error) { return 1, nil })
+	assert.NoError(t, err)
+}
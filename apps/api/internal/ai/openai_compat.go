@@ -0,0 +1,360 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultOpenAICompatTimeoutSeconds is the per-attempt timeout for a
+// self-hosted chat server. A local model on NAS hardware answers in tens of
+// seconds, not the hosted APIs' NFR-I12 15s, and a translation batch prompt is
+// far longer than a filename — so the bound is looser here.
+const DefaultOpenAICompatTimeoutSeconds = 120
+
+// OpenAICompatProvider implements Provider, TextCompleter and CachingCompleter
+// against any OpenAI-compatible /chat/completions endpoint: Ollama, llama.cpp
+// server, vLLM, LM Studio. It is the chat twin of pointing ASR_BASE_URL at a
+// self-hosted Whisper — the model runs on the operator's hardware, so its
+// model id is registered as self-hosted and every call meters at $0.
+//
+// There is no prompt cache to drive: CompleteTextWithUsage flattens the system
+// blocks into one system message and reports zero cache tokens, which callers
+// already read as "cache inert".
+type OpenAICompatProvider struct {
+	baseURL    string
+	model      string
+	apiKey     string // optional — most local servers take none
+	httpClient *http.Client
+	timeout    time.Duration
+	governor   *Governor
+}
+
+// Compile-time interface verification.
+var (
+	_ Provider         = (*OpenAICompatProvider)(nil)
+	_ TextCompleter    = (*OpenAICompatProvider)(nil)
+	_ CachingCompleter = (*OpenAICompatProvider)(nil)
+)
+
+// OpenAICompatProviderOption is a functional option for configuring
+// OpenAICompatProvider.
+type OpenAICompatProviderOption func(*OpenAICompatProvider)
+
+// WithOpenAICompatAPIKey sets a bearer token, for servers (vLLM --api-key, a
+// reverse proxy) that require one.
+func WithOpenAICompatAPIKey(key string) OpenAICompatProviderOption {
+	return func(p *OpenAICompatProvider) {
+		p.apiKey = key
+	}
+}
+
+// WithOpenAICompatHTTPClient sets a custom HTTP client (useful for testing).
+func WithOpenAICompatHTTPClient(client *http.Client) OpenAICompatProviderOption {
+	return func(p *OpenAICompatProvider) {
+		p.httpClient = client
+	}
+}
+
+// WithOpenAICompatTimeout sets a custom per-attempt timeout.
+func WithOpenAICompatTimeout(timeout time.Duration) OpenAICompatProviderOption {
+	return func(p *OpenAICompatProvider) {
+		p.timeout = timeout
+	}
+}
+
+// WithOpenAICompatGovernor injects the shared AI throttle, so a local model is
+// not flooded any harder than a hosted one.
+func WithOpenAICompatGovernor(g *Governor) OpenAICompatProviderOption {
+	return func(p *OpenAICompatProvider) {
+		p.governor = g
+	}
+}
+
+// NewOpenAICompatProvider creates a provider for the chat server at baseURL
+// (the API root, e.g. "http://nas:11434/v1") serving model.
+func NewOpenAICompatProvider(baseURL, model string, opts ...OpenAICompatProviderOption) *OpenAICompatProvider {
+	p := &OpenAICompatProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   model,
+		timeout: DefaultOpenAICompatTimeoutSeconds * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.httpClient == nil {
+		p.httpClient = &http.Client{
+			Timeout: p.timeout,
+		}
+	}
+
+	RegisterSelfHostedModel(p.model)
+	return p
+}
+
+// Name returns the provider name.
+func (p *OpenAICompatProvider) Name() ProviderName {
+	return ProviderOpenAICompat
+}
+
+// Model returns the configured model id.
+func (p *OpenAICompatProvider) Model() string { return p.model }
+
+// Parse sends a filename to the chat server for parsing. Local models wrap
+// JSON in markdown fences more often than not, so the reply is cleaned before
+// it is decoded.
+func (p *OpenAICompatProvider) Parse(ctx context.Context, req *ParseRequest) (*ParseResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	prompt := req.Prompt
+	if prompt == "" {
+		prompt = fmt.Sprintf(DefaultPrompt, req.Filename)
+	}
+
+	slog.Debug("OpenAI-compatible API request",
+		"model", p.model,
+		"filename", req.Filename,
+	)
+
+	resp, err := p.chat(ctx, "openai_compat.parse", chatCompletionRequest{
+		Model:          p.model,
+		Messages:       []chatMessage{{Role: "user", Content: prompt}},
+		MaxTokens:      ClaudeMaxTokens,
+		ResponseFormat: &chatResponseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	text := CleanJSONResponse(resp.text())
+	if text == "" {
+		slog.Warn("Empty response from OpenAI-compatible server",
+			"filename", req.Filename,
+		)
+		return nil, ErrAIInvalidResponse
+	}
+
+	result, err := parseJSONResponse(text)
+	if err != nil {
+		slog.Error("Failed to parse JSON from OpenAI-compatible response",
+			"error", err,
+			"raw_response", text,
+		)
+		return nil, fmt.Errorf("%w: %v", ErrAIInvalidResponse, err)
+	}
+
+	result.RawResponse = text
+
+	slog.Info("OpenAI-compatible model parsed filename",
+		"model", p.model,
+		"filename", req.Filename,
+		"title", result.Title,
+		"media_type", result.MediaType,
+		"confidence", result.Confidence,
+	)
+
+	return result, nil
+}
+
+// CompleteText sends a system+user prompt pair and returns the raw text
+// response. The caller controls the overall timeout via the provided context.
+func (p *OpenAICompatProvider) CompleteText(ctx context.Context, systemPrompt, userPrompt string, maxTokens int) (string, error) {
+	req := CompletionRequest{UserPrompt: userPrompt, MaxTokens: maxTokens}
+	if systemPrompt != "" {
+		req.System = []SystemBlock{{Text: systemPrompt}}
+	}
+
+	res, err := p.CompleteTextWithUsage(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return res.Text, nil
+}
+
+// CompleteTextWithUsage returns the completion with the server's own token
+// accounting. The system blocks keep their order, joined into one message;
+// CacheTTL has no meaning here and is ignored.
+func (p *OpenAICompatProvider) CompleteTextWithUsage(ctx context.Context, req CompletionRequest) (CompletionResult, error) {
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = ClaudeMaxTokens
+	}
+
+	slog.Debug("OpenAI-compatible completion request",
+		"model", p.model,
+		"system_blocks", len(req.System),
+		"max_tokens", maxTokens,
+	)
+
+	messages := make([]chatMessage, 0, 2)
+	if len(req.System) > 0 {
+		parts := make([]string, 0, len(req.System))
+		for _, b := range req.System {
+			parts = append(parts, b.Text)
+		}
+		messages = append(messages, chatMessage{Role: "system", Content: strings.Join(parts, "\n\n")})
+	}
+	messages = append(messages, chatMessage{Role: "user", Content: req.UserPrompt})
+
+	resp, err := p.chat(ctx, "openai_compat.complete", chatCompletionRequest{
+		Model:     p.model,
+		Messages:  messages,
+		MaxTokens: maxTokens,
+	})
+	if err != nil {
+		return CompletionResult{}, err
+	}
+
+	text := resp.text()
+	if text == "" {
+		return CompletionResult{}, ErrAIInvalidResponse
+	}
+
+	return CompletionResult{
+		Text: text,
+		Usage: CompletionUsage{
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+		},
+	}, nil
+}
+
+// chat is the single round-trip: governed() wrapping retryTransient with a
+// per-attempt timeout — the gemini.go Parse shape — then metering. The calls
+// are free, but they still go through the Budget, the metrics observer and the
+// ledger so call counts and token volumes stay visible.
+func (p *OpenAICompatProvider) chat(ctx context.Context, op string, chatReq chatCompletionRequest) (*chatCompletionResponse, error) {
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	url := p.baseURL + "/chat/completions"
+
+	resp, err := governed(ctx, p.governor, op, func() (*chatCompletionResponse, error) {
+		return retryTransient(ctx, op, func() (*chatCompletionResponse, bool, error) {
+			attemptCtx, cancel := context.WithTimeout(ctx, p.timeout)
+			defer cancel()
+
+			httpReq, err := http.NewRequestWithContext(attemptCtx, http.MethodPost, url, bytes.NewReader(body))
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to create request: %w", err)
+			}
+			httpReq.Header.Set("Content-Type", "application/json")
+			if p.apiKey != "" {
+				httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+			}
+
+			httpResp, err := p.httpClient.Do(httpReq)
+			if err != nil {
+				if isTimeoutErr(err) {
+					slog.Warn("OpenAI-compatible API timeout",
+						"model", p.model,
+						"timeout_seconds", p.timeout.Seconds(),
+					)
+					return nil, true, ErrAITimeout
+				}
+				slog.Error("OpenAI-compatible API request failed",
+					"error", err,
+					"base_url", p.baseURL,
+				)
+				return nil, true, fmt.Errorf("%w: %v", ErrAIProviderError, err)
+			}
+			defer httpResp.Body.Close()
+
+			respBody, err := io.ReadAll(httpResp.Body)
+			if err != nil {
+				return nil, true, fmt.Errorf("failed to read response: %w", err)
+			}
+
+			if httpResp.StatusCode != http.StatusOK {
+				slog.Warn("OpenAI-compatible API error response",
+					"status_code", httpResp.StatusCode,
+					"body", string(respBody),
+				)
+				switch httpResp.StatusCode {
+				case http.StatusTooManyRequests:
+					return nil, true, ErrAIQuotaExceeded
+				case http.StatusUnauthorized, http.StatusForbidden:
+					return nil, false, fmt.Errorf("%w: %w: status %d", ErrAIProviderError, ErrAIUnauthorized, httpResp.StatusCode)
+				case http.StatusNotFound:
+					// Ollama answers 404 for a model that has not been pulled.
+					return nil, false, fmt.Errorf("%w: %w: status 404: model %q not found (pull it on the server or set AI_MODEL)", ErrAIProviderError, ErrAIModelNotFound, p.model)
+				}
+				return nil, isTransientStatus(httpResp.StatusCode), fmt.Errorf("%w: status %d", ErrAIProviderError, httpResp.StatusCode)
+			}
+
+			// A malformed 200 body is PERMANENT, as in gemini.go.
+			var parsed chatCompletionResponse
+			if err := json.Unmarshal(respBody, &parsed); err != nil {
+				slog.Error("Failed to parse OpenAI-compatible response",
+					"error", err,
+					"body", string(respBody),
+				)
+				return nil, false, fmt.Errorf("%w: %v", ErrAIInvalidResponse, err)
+			}
+			return &parsed, false, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	in, out := resp.Usage.PromptTokens, resp.Usage.CompletionTokens
+	if b := BudgetFromContext(ctx); b != nil {
+		b.RecordLLM(p.model, in, out)
+	}
+	observeLLM(ProviderOpenAICompat, p.model, in, out)
+	recordUsageLLM(ctx, ProviderOpenAICompat, p.model, in, out)
+	return resp, nil
+}
+
+// OpenAI chat-completions API types (the subset every compatible server
+// implements).
+
+type chatCompletionRequest struct {
+	Model          string              `json:"model"`
+	Messages       []chatMessage       `json:"messages"`
+	MaxTokens      int                 `json:"max_tokens,omitempty"`
+	ResponseFormat *chatResponseFormat `json:"response_format,omitempty"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type chatCompletionResponse struct {
+	Choices []chatChoice `json:"choices"`
+	Usage   chatUsage    `json:"usage"`
+}
+
+type chatChoice struct {
+	Message chatMessage `json:"message"`
+}
+
+type chatUsage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+}
+
+// text extracts the content of the first choice.
+func (r *chatCompletionResponse) text() string {
+	if len(r.Choices) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(r.Choices[0].Message.Content)
+}
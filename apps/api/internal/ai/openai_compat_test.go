@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chatServer answers /chat/completions with content and the given usage,
+// capturing the last request body.
+func chatServer(t *testing.T, content string, got *chatCompletionRequest, auth *string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		if auth != nil {
+			*auth = r.Header.Get("Authorization")
+		}
+		if got != nil {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(got))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(chatCompletionResponse{
+			Choices: []chatChoice{{Message: chatMessage{Role: "assistant", Content: content}}},
+			Usage:   chatUsage{PromptTokens: 120, CompletionTokens: 40},
+		})
+	}))
+}
+
+func TestOpenAICompatProvider_Parse(t *testing.T) {
+	var got chatCompletionRequest
+	server := chatServer(t, "```json\n{\"title\": \"Frieren\", \"season\": 1, \"episode\": 3, \"media_type\": \"tv\", \"confidence\": 0.9}\n```", &got, nil)
+	defer server.Close()
+
+	p := NewOpenAICompatProvider(server.URL+"/v1/", "qwen2.5:7b")
+	assert.Equal(t, ProviderOpenAICompat, p.Name())
+
+	res, err := p.Parse(context.Background(), &ParseRequest{Filename: "[SubsPlease] Sousou no Frieren - 03 [1080p].mkv"})
+	require.NoError(t, err)
+	assert.Equal(t, "Frieren", res.Title)
+	assert.True(t, res.IsTVShow())
+	assert.Equal(t, "qwen2.5:7b", got.Model)
+	require.NotNil(t, got.ResponseFormat)
+	assert.Equal(t, "json_object", got.ResponseFormat.Type)
+}
+
+func TestOpenAICompatProvider_CompleteTextWithUsage(t *testing.T) {
+	var got chatCompletionRequest
+	var auth string
+	server := chatServer(t, "  你好  ", &got, &auth)
+	defer server.Close()
+
+	p := NewOpenAICompatProvider(server.URL+"/v1", "llama3.1:8b", WithOpenAICompatAPIKey("local-token"))
+	res, err := p.CompleteTextWithUsage(context.Background(), CompletionRequest{
+		System:     []SystemBlock{{Text: "glossary", CacheTTL: CacheTTL1h}, {Text: "rules"}},
+		UserPrompt: "hello",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "你好", res.Text)
+	assert.Equal(t, CompletionUsage{InputTokens: 120, OutputTokens: 40}, res.Usage)
+	assert.Equal(t, "Bearer local-token", auth)
+	require.Len(t, got.Messages, 2)
+	assert.Equal(t, chatMessage{Role: "system", Content: "glossary\n\nrules"}, got.Messages[0])
+	assert.Equal(t, chatMessage{Role: "user", Content: "hello"}, got.Messages[1])
+	assert.Equal(t, ClaudeMaxTokens, got.MaxTokens)
+}
+
+// A self-hosted model costs nothing per token: the quote, the Budget and the
+// ledger must all read $0, while the call itself is still counted.
+func TestOpenAICompatProvider_IsFree(t *testing.T) {
+	server := chatServer(t, "ok", nil, nil)
+	defer server.Close()
+
+	p := NewOpenAICompatProvider(server.URL+"/v1", "free-local-model")
+	assert.Equal(t, ModelPricing{}, PricingFor("free-local-model"))
+
+	b := NewBudget(1)
+	ctx := WithBudget(context.Background(), b)
+	_, err := p.CompleteText(ctx, "", "hi", 0)
+	require.NoError(t, err)
+	assert.Zero(t, b.SpentUSD())
+	assert.Equal(t, 1, b.Snapshot().LLMCalls)
+}
+
+func TestOpenAICompatProvider_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		want      error
+		wantCalls int32
+	}{
+		{"unauthorized", http.StatusUnauthorized, ErrAIUnauthorized, 1},
+		{"model not pulled", http.StatusNotFound, ErrAIModelNotFound, 1},
+		{"overloaded", http.StatusServiceUnavailable, ErrAIProviderError, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			p := NewOpenAICompatProvider(server.URL+"/v1", "qwen2.5:7b")
+			_, err := p.CompleteText(context.Background(), "", "hi", 0)
+			assert.ErrorIs(t, err, tt.want)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
@@ -12,6 +12,9 @@ const (
 	ProviderGemini ProviderName = "gemini"
 	// ProviderClaude represents Anthropic's Claude AI.
 	ProviderClaude ProviderName = "claude"
+	// ProviderOpenAICompat represents any self-hosted OpenAI-compatible chat
+	// server (Ollama, llama.cpp server, vLLM, LM Studio).
+	ProviderOpenAICompat ProviderName = "openai_compat"
 )
 
 // String returns the string representation of the provider name.
@@ -22,7 +25,7 @@ func (p ProviderName) String() string {
 // IsValid checks if the provider name is a known provider.
 func (p ProviderName) IsValid() bool {
 	switch p {
-	case ProviderGemini, ProviderClaude:
+	case ProviderGemini, ProviderClaude, ProviderOpenAICompat:
 		return true
 	default:
 		return false
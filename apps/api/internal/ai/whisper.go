@@ -242,10 +242,11 @@ func (c *WhisperClient) TranscribeDetailed(ctx context.Context, audioPath, lang
 	// hand. The verbose→srt fallback issues a second HTTP request; billing the
 	// same minutes twice would burn the 9R-11 run budget at double rate and
 	// trip the ceiling halfway through a film.
-	if b := BudgetFromContext(ctx); b != nil {
-		if dur, _, derr := parseWAVInfo(audioPath); derr == nil {
-			b.RecordASRWithRate(dur, EstimatedASRPerMinuteUSD(c.isSelfHosted()))
-		}
+	if dur, _, derr := parseWAVInfo(audioPath); derr == nil {
+		rate := EstimatedASRPerMinuteUSD(c.isSelfHosted())
+		BudgetFromContext(ctx).RecordASRWithRate(dur, rate)
+		observeASR(c.model, dur, rate, c.isSelfHosted())
+		recordUsageASR(ctx, c.model, dur, rate)
 	}
 
 	c.logger.Info("Whisper transcription complete",
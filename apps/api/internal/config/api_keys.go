@@ -1,5 +1,7 @@
 package config
 
+import "strings"
+
 // HasTMDbKey returns true if a TMDb API key is configured
 func (c *Config) HasTMDbKey() bool {
 	return c.TMDbAPIKey != ""
@@ -39,13 +41,20 @@ func (c *Config) HasEncryptionKey() bool {
 	return c.EncryptionKey != ""
 }
 
-// HasAIProvider returns true if any text AI provider key is configured (Gemini or Claude).
+// HasAIProvider returns true if any text AI provider is configured (a Gemini or
+// Claude key, or a self-hosted OpenAI-compatible server).
 // Note: OpenAI key (for Whisper audio transcription) is checked separately via HasOpenAIKey().
 func (c *Config) HasAIProvider() bool {
-	return c.HasGeminiKey() || c.HasClaudeKey()
+	return c.HasGeminiKey() || c.HasClaudeKey() || c.HasSelfHostedAI()
+}
+
+// HasSelfHostedAI returns true when AI_PROVIDER selects a self-hosted
+// OpenAI-compatible chat server and both AI_BASE_URL and AI_MODEL are set.
+func (c *Config) HasSelfHostedAI() bool {
+	return strings.EqualFold(c.AIProvider, "openai_compat") && c.AIBaseURL != "" && c.AIModel != ""
 }
 
-// GetAIProvider returns the configured AI provider name ("gemini" or "claude")
+// GetAIProvider returns the configured AI provider name ("gemini", "claude" or "openai_compat")
 func (c *Config) GetAIProvider() string {
 	return c.AIProvider
 }
@@ -46,6 +46,27 @@ type Config struct {
 	DataDir   string
 	MediaDirs []string
 
+	// LibraryWatch enables the filesystem watcher that picks up library
+	// changes between scheduled scans. Off for mounts that emit no events.
+	LibraryWatch bool
+
+	// AdminUsername/AdminPassword seed the first admin account on an install
+	// that has none, so a headless deployment is authenticated from its first
+	// boot. Ignored once any account exists.
+	AdminUsername string
+	AdminPassword string
+	// SessionTTLHours is how long a login stays valid.
+	SessionTTLHours int
+
+	// MetricsToken, when set, is the bearer token a Prometheus scraper must
+	// present on /metrics. Empty leaves the endpoint open, like /health.
+	MetricsToken string
+	// CalendarToken, when set, pins the ?token= /calendar.ics requires.
+	// Calendar apps cannot send a session cookie or a header, so the feed
+	// sits outside /api/v1 and its token is the only guard; unset, a token
+	// is generated and kept in settings.
+	CalendarToken string
+
 	// API Keys (optional)
 	TMDbAPIKey    string
 	GeminiAPIKey  string
@@ -54,7 +75,15 @@ type Config struct {
 	EncryptionKey string
 
 	// AI Provider configuration (Story 3.1)
-	AIProvider string // "gemini" or "claude"
+	AIProvider string // "gemini", "claude" or "openai_compat"
+
+	// Self-hosted chat model, used when AIProvider is "openai_compat": the API
+	// root of an OpenAI-compatible server (Ollama, llama.cpp, vLLM, LM Studio),
+	// the model id it serves, and an optional bearer token. The ASR_BASE_URL
+	// counterpart for parsing and translation.
+	AIBaseURL string
+	AIModel   string
+	AIAPIKey  string
 
 	// ClaudeModel overrides the Claude model id (9R-1). Empty = provider default.
 	ClaudeModel string
@@ -121,6 +150,14 @@ func Load() (*Config, error) {
 
 	// Media directories (comma-separated)
 	cfg.MediaDirs = cfg.loadStringSlice("VIDO_MEDIA_DIRS", "/media")
+	cfg.LibraryWatch = cfg.loadBool("VIDO_LIBRARY_WATCH", true)
+
+	// Authentication: optional first-admin seed and session lifetime (30 days).
+	cfg.AdminUsername = cfg.loadString("VIDO_ADMIN_USERNAME", "")
+	cfg.AdminPassword = cfg.loadString("VIDO_ADMIN_PASSWORD", "")
+	cfg.SessionTTLHours = cfg.loadInt("VIDO_SESSION_TTL_HOURS", 720)
+	cfg.MetricsToken = cfg.loadString("VIDO_METRICS_TOKEN", "")
+	cfg.CalendarToken = cfg.loadString("VIDO_CALENDAR_TOKEN", "")
 
 	// API Keys (optional - empty string is valid default)
 	cfg.TMDbAPIKey = cfg.loadString("TMDB_API_KEY", "")
@@ -139,6 +176,9 @@ func Load() (*Config, error) {
 
 	// AI Provider configuration (Story 3.1) - defaults to "gemini" if not set
 	cfg.AIProvider = cfg.loadString("AI_PROVIDER", "gemini")
+	cfg.AIBaseURL = cfg.loadString("AI_BASE_URL", "")
+	cfg.AIModel = cfg.loadString("AI_MODEL", "")
+	cfg.AIAPIKey = cfg.loadString("AI_API_KEY", "")
 
 	// TMDb configuration (Story 2.1)
 	cfg.TMDbDefaultLanguage = cfg.loadString("TMDB_DEFAULT_LANGUAGE", "zh-TW")
@@ -300,6 +340,18 @@ func (c *Config) LogConfigSources() {
 		"VIDO_DATA_DIR_source", c.Sources["VIDO_DATA_DIR"].String(),
 		"VIDO_MEDIA_DIRS", strings.Join(c.MediaDirs, ","),
 		"VIDO_MEDIA_DIRS_source", c.Sources["VIDO_MEDIA_DIRS"].String(),
+		"VIDO_LIBRARY_WATCH", c.LibraryWatch,
+		"VIDO_LIBRARY_WATCH_source", c.Sources["VIDO_LIBRARY_WATCH"].String(),
+		"VIDO_ADMIN_USERNAME", c.AdminUsername,
+		"VIDO_ADMIN_USERNAME_source", c.Sources["VIDO_ADMIN_USERNAME"].String(),
+		"VIDO_ADMIN_PASSWORD", maskSecret(c.AdminPassword),
+		"VIDO_ADMIN_PASSWORD_source", c.Sources["VIDO_ADMIN_PASSWORD"].String(),
+		"VIDO_SESSION_TTL_HOURS", c.SessionTTLHours,
+		"VIDO_SESSION_TTL_HOURS_source", c.Sources["VIDO_SESSION_TTL_HOURS"].String(),
+		"VIDO_METRICS_TOKEN", maskSecret(c.MetricsToken),
+		"VIDO_METRICS_TOKEN_source", c.Sources["VIDO_METRICS_TOKEN"].String(),
+		"VIDO_CALENDAR_TOKEN", maskSecret(c.CalendarToken),
+		"VIDO_CALENDAR_TOKEN_source", c.Sources["VIDO_CALENDAR_TOKEN"].String(),
 		"VIDO_CORS_ORIGINS", strings.Join(c.CORSOrigins, ","),
 		"VIDO_CORS_ORIGINS_source", c.Sources["VIDO_CORS_ORIGINS"].String(),
 		"TMDB_API_KEY", maskSecret(c.TMDbAPIKey),
@@ -312,6 +364,12 @@ func (c *Config) LogConfigSources() {
 		"OPENAI_API_KEY_source", c.Sources["OPENAI_API_KEY"].String(),
 		"AI_PROVIDER", c.AIProvider,
 		"AI_PROVIDER_source", c.Sources["AI_PROVIDER"].String(),
+		"AI_BASE_URL", c.AIBaseURL,
+		"AI_BASE_URL_source", c.Sources["AI_BASE_URL"].String(),
+		"AI_MODEL", c.AIModel,
+		"AI_MODEL_source", c.Sources["AI_MODEL"].String(),
+		"AI_API_KEY", maskSecret(c.AIAPIKey),
+		"AI_API_KEY_source", c.Sources["AI_API_KEY"].String(),
 		"ENCRYPTION_KEY", maskSecret(c.EncryptionKey),
 		"ENCRYPTION_KEY_source", c.Sources["ENCRYPTION_KEY"].String(),
 		"TMDB_DEFAULT_LANGUAGE", c.TMDbDefaultLanguage,
@@ -548,6 +548,18 @@ func TestAPIKeyHelpers(t *testing.T) {
 		assert.False(t, cfg.HasAIProvider())
 	})
 
+	t.Run("HasAIProvider returns true for a self-hosted model", func(t *testing.T) {
+		cfg := &Config{AIProvider: "openai_compat", AIBaseURL: "http://nas:11434/v1", AIModel: "qwen2.5:7b"}
+		assert.True(t, cfg.HasSelfHostedAI())
+		assert.True(t, cfg.HasAIProvider())
+	})
+
+	t.Run("HasSelfHostedAI needs both base URL and model", func(t *testing.T) {
+		cfg := &Config{AIProvider: "openai_compat", AIBaseURL: "http://nas:11434/v1"}
+		assert.False(t, cfg.HasSelfHostedAI())
+		assert.False(t, cfg.HasAIProvider())
+	})
+
 	t.Run("GetAIProvider returns configured provider", func(t *testing.T) {
 		cfg := &Config{AIProvider: "claude"}
 		assert.Equal(t, "claude", cfg.GetAIProvider())
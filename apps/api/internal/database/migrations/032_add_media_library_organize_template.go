@@ -0,0 +1,38 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&addMediaLibraryOrganizeTemplate{
+		migrationBase: NewMigrationBase(32, "add_media_library_organize_template"),
+	})
+}
+
+// addMediaLibraryOrganizeTemplate adds the per-library naming template the
+// organizer renders release files into (e.g.
+// `{title} ({year})/{title} - S{season:02}E{episode:02}`).
+//
+// The empty-string default means "use the content type's built-in
+// template" — an existing library gets a sensible layout without anyone
+// having to type one, and the built-in can improve later without a data
+// migration. A column rather than a settings key for the same one-write
+// reason as auto_subtitle (migration 031).
+type addMediaLibraryOrganizeTemplate struct {
+	migrationBase
+}
+
+func (m *addMediaLibraryOrganizeTemplate) Up(tx *sql.Tx) error {
+	if columnExists(tx, "media_libraries", "organize_template") {
+		return nil
+	}
+	if _, err := tx.Exec("ALTER TABLE media_libraries ADD COLUMN organize_template TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *addMediaLibraryOrganizeTemplate) Down(tx *sql.Tx) error {
+	// An empty template is the built-in default and harmless if left in place;
+	// SQLite DROP COLUMN support is version-dependent (mirrors migration 031).
+	return nil
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runOrganizeTemplateMigration(t *testing.T, db *sql.DB) {
+	t.Helper()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	migration := &addMediaLibraryOrganizeTemplate{migrationBase: NewMigrationBase(32, "add_media_library_organize_template")}
+	require.NoError(t, migration.Up(tx))
+	require.NoError(t, tx.Commit())
+}
+
+// An existing library must come out with the empty template, which the
+// organizer reads as "built-in layout" — never a half-filled custom one.
+func TestAddMediaLibraryOrganizeTemplate_DefaultsEmpty(t *testing.T) {
+	db := setupMediaLibrariesTable(t)
+	defer db.Close()
+
+	runOrganizeTemplateMigration(t, db)
+
+	var template string
+	require.NoError(t, db.QueryRow(`SELECT organize_template FROM media_libraries WHERE id = 'lib-1'`).Scan(&template))
+	assert.Empty(t, template)
+}
+
+func TestAddMediaLibraryOrganizeTemplate_IsIdempotent(t *testing.T) {
+	db := setupMediaLibrariesTable(t)
+	defer db.Close()
+
+	runOrganizeTemplateMigration(t, db)
+	assert.NotPanics(t, func() { runOrganizeTemplateMigration(t, db) },
+		"re-running must be a no-op — migrations replay on every boot")
+}
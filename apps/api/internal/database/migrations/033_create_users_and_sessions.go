@@ -0,0 +1,61 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&createUsersAndSessions{
+		migrationBase: NewMigrationBase(33, "create_users_and_sessions"),
+	})
+}
+
+// createUsersAndSessions adds API accounts and their login sessions.
+//
+// Sessions are server-side rows rather than signed tokens so logout, a
+// password change, or deleting a user takes effect on the next request. The
+// row id is the SHA-256 of the cookie value — a leaked database (or backup)
+// holds no usable session.
+type createUsersAndSessions struct {
+	migrationBase
+}
+
+func (m *createUsersAndSessions) Up(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS users (
+			id TEXT PRIMARY KEY,
+			username TEXT NOT NULL COLLATE NOCASE UNIQUE,
+			password_hash TEXT NOT NULL,
+			role TEXT NOT NULL DEFAULT 'user' CHECK(role IN ('admin','user')),
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			last_login_at TIMESTAMP
+		)`); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS sessions (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			user_agent TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP NOT NULL
+		)`); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id)`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at)`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *createUsersAndSessions) Down(tx *sql.Tx) error {
+	if _, err := tx.Exec(`DROP TABLE IF EXISTS sessions`); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DROP TABLE IF EXISTS users`)
+	return err
+}
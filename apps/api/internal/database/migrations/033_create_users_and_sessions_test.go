@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func setupUsersMigration(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	migration := &createUsersAndSessions{migrationBase: NewMigrationBase(33, "create_users_and_sessions")}
+	require.NoError(t, migration.Up(tx))
+	require.NoError(t, tx.Commit())
+	return db
+}
+
+func TestCreateUsersAndSessions_Up(t *testing.T) {
+	db := setupUsersMigration(t)
+
+	_, err := db.Exec(`INSERT INTO users (id, username, password_hash) VALUES ('u1', 'Alice', 'x')`)
+	require.NoError(t, err)
+
+	t.Run("role defaults to user", func(t *testing.T) {
+		var role string
+		require.NoError(t, db.QueryRow(`SELECT role FROM users WHERE id = 'u1'`).Scan(&role))
+		assert.Equal(t, "user", role)
+	})
+
+	t.Run("username is unique regardless of case", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO users (id, username, password_hash) VALUES ('u2', 'alice', 'x')`)
+		assert.ErrorContains(t, err, "UNIQUE")
+	})
+
+	t.Run("rejects an unknown role", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO users (id, username, password_hash, role) VALUES ('u3', 'bob', 'x', 'owner')`)
+		assert.Error(t, err)
+	})
+
+	t.Run("sessions accept a known user", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO sessions (id, user_id, expires_at) VALUES ('s1', 'u1', CURRENT_TIMESTAMP)`)
+		assert.NoError(t, err)
+	})
+}
+
+func TestCreateUsersAndSessions_IsIdempotent(t *testing.T) {
+	db := setupUsersMigration(t)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	migration := &createUsersAndSessions{migrationBase: NewMigrationBase(33, "create_users_and_sessions")}
+	require.NoError(t, migration.Up(tx))
+	require.NoError(t, tx.Commit())
+}
+
+func TestCreateUsersAndSessions_Down(t *testing.T) {
+	db := setupUsersMigration(t)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	migration := &createUsersAndSessions{migrationBase: NewMigrationBase(33, "create_users_and_sessions")}
+	require.NoError(t, migration.Down(tx))
+	require.NoError(t, tx.Commit())
+
+	for _, table := range []string{"users", "sessions"} {
+		var name string
+		err := db.QueryRow(`SELECT name FROM sqlite_master WHERE type='table' AND name=?`, table).Scan(&name)
+		assert.ErrorIs(t, err, sql.ErrNoRows, "%s table should be dropped", table)
+	}
+}
@@ -0,0 +1,63 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&createWatchStateTable{
+		migrationBase: NewMigrationBase(34, "create_watch_state_table"),
+	})
+}
+
+// createWatchStateTable adds per-user play progress and watched flags for
+// movies and episodes.
+//
+// user_id is empty on an install without accounts (one shared household
+// history), so it carries no foreign key; a trigger clears a deleted
+// account's rows instead. series_id is copied onto episode rows so "continue
+// watching" can group by show without joining episodes.
+type createWatchStateTable struct {
+	migrationBase
+}
+
+func (m *createWatchStateTable) Up(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS watch_state (
+			user_id TEXT NOT NULL DEFAULT '',
+			media_type TEXT NOT NULL CHECK(media_type IN ('movie','episode')),
+			media_id TEXT NOT NULL,
+			series_id TEXT,
+			position_seconds REAL NOT NULL DEFAULT 0,
+			duration_seconds REAL NOT NULL DEFAULT 0,
+			watched INTEGER NOT NULL DEFAULT 0,
+			watch_count INTEGER NOT NULL DEFAULT 0,
+			last_watched_at TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, media_type, media_id)
+		)`); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_watch_state_user_updated ON watch_state(user_id, updated_at)`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_watch_state_series ON watch_state(user_id, series_id)`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		CREATE TRIGGER IF NOT EXISTS trg_watch_state_user_deleted
+		AFTER DELETE ON users
+		BEGIN
+			DELETE FROM watch_state WHERE user_id = OLD.id;
+		END`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *createWatchStateTable) Down(tx *sql.Tx) error {
+	if _, err := tx.Exec(`DROP TRIGGER IF EXISTS trg_watch_state_user_deleted`); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DROP TABLE IF EXISTS watch_state`)
+	return err
+}
@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func setupWatchStateMigration(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupUsersMigration(t)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	migration := &createWatchStateTable{migrationBase: NewMigrationBase(34, "create_watch_state_table")}
+	require.NoError(t, migration.Up(tx))
+	require.NoError(t, tx.Commit())
+	return db
+}
+
+func TestCreateWatchStateTable_Up(t *testing.T) {
+	db := setupWatchStateMigration(t)
+
+	t.Run("one row per user and item", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO watch_state (media_type, media_id) VALUES ('movie', 'm1')`)
+		require.NoError(t, err)
+		_, err = db.Exec(`INSERT INTO watch_state (media_type, media_id) VALUES ('movie', 'm1')`)
+		assert.ErrorContains(t, err, "UNIQUE")
+	})
+
+	t.Run("rejects an unknown media_type", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO watch_state (media_type, media_id) VALUES ('series', 's1')`)
+		assert.Error(t, err)
+	})
+
+	t.Run("deleting a user clears their history", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO users (id, username, password_hash) VALUES ('u1', 'alice', 'x')`)
+		require.NoError(t, err)
+		_, err = db.Exec(`INSERT INTO watch_state (user_id, media_type, media_id) VALUES ('u1', 'movie', 'm1')`)
+		require.NoError(t, err)
+
+		_, err = db.Exec(`DELETE FROM users WHERE id = 'u1'`)
+		require.NoError(t, err)
+
+		var n int
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM watch_state WHERE user_id = 'u1'`).Scan(&n))
+		assert.Zero(t, n)
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM watch_state WHERE user_id = ''`).Scan(&n))
+		assert.Equal(t, 1, n, "the shared history is untouched")
+	})
+}
+
+func TestCreateWatchStateTable_Down(t *testing.T) {
+	db := setupWatchStateMigration(t)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	migration := &createWatchStateTable{migrationBase: NewMigrationBase(34, "create_watch_state_table")}
+	require.NoError(t, migration.Down(tx))
+	require.NoError(t, tx.Commit())
+
+	var name string
+	err = db.QueryRow(`SELECT name FROM sqlite_master WHERE type='table' AND name='watch_state'`).Scan(&name)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
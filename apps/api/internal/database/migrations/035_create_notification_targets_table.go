@@ -0,0 +1,40 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&createNotificationTargetsTable{
+		migrationBase: NewMigrationBase(35, "create_notification_targets_table"),
+	})
+}
+
+// createNotificationTargetsTable adds the outbound notification destinations.
+//
+// events and config are JSON (an array of event types and a flat string map);
+// they are only ever read whole. The credential a target needs is not stored
+// here but in the secrets table, keyed by the target ID.
+type createNotificationTargetsTable struct {
+	migrationBase
+}
+
+func (m *createNotificationTargetsTable) Up(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS notification_targets (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL CHECK(type IN ('webhook','discord','telegram','ntfy','gotify','smtp')),
+			enabled INTEGER NOT NULL DEFAULT 1,
+			events TEXT NOT NULL DEFAULT '[]',
+			config TEXT NOT NULL DEFAULT '{}',
+			title_template TEXT NOT NULL DEFAULT '',
+			body_template TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+func (m *createNotificationTargetsTable) Down(tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS notification_targets`)
+	return err
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&createCollectionsTable{
+		migrationBase: NewMigrationBase(36, "create_collections_table"),
+	})
+}
+
+// createCollectionsTable adds TMDb movie collections (franchises).
+//
+// The primary key is the TMDb collection id. parts is the JSON member list as
+// TMDb reported it at enrichment time; ownership is joined against the movies
+// table by TMDb id when read, so nothing here goes stale when a movie is
+// added or removed.
+type createCollectionsTable struct {
+	migrationBase
+}
+
+func (m *createCollectionsTable) Up(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS collections (
+			id INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			overview TEXT NOT NULL DEFAULT '',
+			poster_path TEXT NOT NULL DEFAULT '',
+			backdrop_path TEXT NOT NULL DEFAULT '',
+			parts TEXT NOT NULL DEFAULT '[]',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+func (m *createCollectionsTable) Down(tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS collections`)
+	return err
+}
@@ -0,0 +1,61 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&createQualityProfiles{
+		migrationBase: NewMigrationBase(37, "create_quality_profiles"),
+	})
+}
+
+// createQualityProfiles adds Vido-native quality profiles and the two columns
+// that use them:
+//
+//   - media_libraries.quality_profile_id — the profile a library's files are
+//     judged against. NULL means the library has none and is never evaluated,
+//     which is where every existing library starts.
+//   - requests.upgrade_cutoff — set on a request the quality evaluator raised
+//     for a title that is already owned but below its profile's cutoff. The
+//     request poller completes such a row only once the file reaches it.
+//
+// The allow-lists are JSON arrays, the notification_targets.events precedent.
+// No foreign key on quality_profile_id: SQLite cannot add one with ALTER
+// TABLE, so deleting a profile clears its assignments in the same
+// transaction instead.
+type createQualityProfiles struct {
+	migrationBase
+}
+
+func (m *createQualityProfiles) Up(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS quality_profiles (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			resolutions TEXT NOT NULL DEFAULT '[]',
+			sources TEXT NOT NULL DEFAULT '[]',
+			codecs TEXT NOT NULL DEFAULT '[]',
+			cutoff TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return err
+	}
+	if !columnExists(tx, "media_libraries", "quality_profile_id") {
+		if _, err := tx.Exec("ALTER TABLE media_libraries ADD COLUMN quality_profile_id TEXT"); err != nil {
+			return err
+		}
+	}
+	if !columnExists(tx, "requests", "upgrade_cutoff") {
+		if _, err := tx.Exec("ALTER TABLE requests ADD COLUMN upgrade_cutoff TEXT"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *createQualityProfiles) Down(tx *sql.Tx) error {
+	// The added columns are NULL unless a profile is in use and harmless if
+	// left in place (SQLite DROP COLUMN is version-dependent, migration 031).
+	_, err := tx.Exec(`DROP TABLE IF EXISTS quality_profiles`)
+	return err
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runQualityProfilesMigration(t *testing.T, db *sql.DB) {
+	t.Helper()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	migration := &createQualityProfiles{migrationBase: NewMigrationBase(37, "create_quality_profiles")}
+	require.NoError(t, migration.Up(tx))
+	require.NoError(t, tx.Commit())
+}
+
+// Existing libraries come out with no profile, so nothing is evaluated until
+// someone assigns one.
+func TestCreateQualityProfiles_LeavesLibrariesUnassigned(t *testing.T) {
+	db := setupMediaLibrariesTable(t)
+	defer db.Close()
+	_, err := db.Exec(`CREATE TABLE requests (id TEXT PRIMARY KEY, tmdb_id INTEGER NOT NULL)`)
+	require.NoError(t, err)
+
+	runQualityProfilesMigration(t, db)
+
+	var profileID sql.NullString
+	require.NoError(t, db.QueryRow(`SELECT quality_profile_id FROM media_libraries WHERE id = 'lib-1'`).Scan(&profileID))
+	assert.False(t, profileID.Valid)
+
+	_, err = db.Exec(`INSERT INTO requests (id, tmdb_id, upgrade_cutoff) VALUES ('r1', 550, '1080p')`)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO quality_profiles (id, name) VALUES ('p1', 'HD')`)
+	assert.NoError(t, err)
+
+	assert.NotPanics(t, func() { runQualityProfilesMigration(t, db) }, "re-running must be a no-op")
+}
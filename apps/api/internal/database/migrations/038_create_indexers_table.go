@@ -0,0 +1,39 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&createIndexersTable{
+		migrationBase: NewMigrationBase(38, "create_indexers_table"),
+	})
+}
+
+// createIndexersTable adds the release sources of the built-in fulfilment
+// path: Torznab/Newznab endpoints and plain RSS feeds.
+//
+// categories is a JSON array of Newznab category numbers. As with
+// notification targets, the API key is not stored here but in the secrets
+// table, keyed by the indexer ID.
+type createIndexersTable struct {
+	migrationBase
+}
+
+func (m *createIndexersTable) Up(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS indexers (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL CHECK(type IN ('torznab','newznab','rss')),
+			url TEXT NOT NULL,
+			categories TEXT NOT NULL DEFAULT '[]',
+			enabled INTEGER NOT NULL DEFAULT 1,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+func (m *createIndexersTable) Down(tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS indexers`)
+	return err
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&createDownloadImportsTable{
+		migrationBase: NewMigrationBase(39, "create_download_imports_table"),
+	})
+}
+
+// createDownloadImportsTable adds the post-download importer's ledger: one
+// row per finished torrent, so a torrent left seeding is imported once and
+// not again on every poll or after a restart.
+//
+// request_id is set when the torrent was grabbed for a request, NULL when
+// the release name was parsed instead. target_path is the imported video (the
+// first one, for a season pack).
+type createDownloadImportsTable struct {
+	migrationBase
+}
+
+func (m *createDownloadImportsTable) Up(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS download_imports (
+			torrent_hash TEXT PRIMARY KEY,
+			torrent_name TEXT NOT NULL,
+			status TEXT NOT NULL CHECK(status IN ('imported','skipped','failed')),
+			request_id TEXT,
+			library_id TEXT,
+			target_path TEXT,
+			files_imported INTEGER NOT NULL DEFAULT 0,
+			hardlinked INTEGER NOT NULL DEFAULT 0,
+			message TEXT,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+func (m *createDownloadImportsTable) Down(tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS download_imports`)
+	return err
+}
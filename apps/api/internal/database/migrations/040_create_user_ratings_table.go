@@ -0,0 +1,52 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&createUserRatingsTable{
+		migrationBase: NewMigrationBase(40, "create_user_ratings_table"),
+	})
+}
+
+// createUserRatingsTable adds each user's own 1–10 rating of a movie or a
+// show, as imported from Trakt.
+//
+// user_id follows watch_state: empty on an install without accounts, cleared
+// by trigger when an account is deleted. source records where the rating
+// came from so a later in-app rating can tell the two apart.
+type createUserRatingsTable struct {
+	migrationBase
+}
+
+func (m *createUserRatingsTable) Up(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS user_ratings (
+			user_id TEXT NOT NULL DEFAULT '',
+			media_type TEXT NOT NULL CHECK(media_type IN ('movie','series')),
+			media_id TEXT NOT NULL,
+			rating INTEGER NOT NULL CHECK(rating BETWEEN 1 AND 10),
+			source TEXT NOT NULL DEFAULT 'vido',
+			rated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, media_type, media_id)
+		)`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		CREATE TRIGGER IF NOT EXISTS trg_user_ratings_user_deleted
+		AFTER DELETE ON users
+		BEGIN
+			DELETE FROM user_ratings WHERE user_id = OLD.id;
+		END`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *createUserRatingsTable) Down(tx *sql.Tx) error {
+	if _, err := tx.Exec(`DROP TRIGGER IF EXISTS trg_user_ratings_user_deleted`); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DROP TABLE IF EXISTS user_ratings`)
+	return err
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&createAIUsageTable{
+		migrationBase: NewMigrationBase(41, "create_ai_usage_table"),
+	})
+}
+
+// createAIUsageTable adds the AI spend ledger: one row per metered LLM call or
+// transcription, kept after the run that made it is gone, so the cost of a
+// busy week can be read back and the global daily/monthly caps enforced.
+//
+// usage_date is the server-local calendar day of the call (YYYY-MM-DD); the
+// caps and the day/month rollups group on it so a day means the admin's day,
+// not UTC's. media_id and run_id are empty for calls made outside a media run
+// (filename parsing, keyword generation).
+type createAIUsageTable struct {
+	migrationBase
+}
+
+func (m *createAIUsageTable) Up(tx *sql.Tx) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS ai_usage (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			provider TEXT NOT NULL,
+			model TEXT NOT NULL,
+			feature TEXT NOT NULL DEFAULT '',
+			media_id TEXT NOT NULL DEFAULT '',
+			run_id TEXT NOT NULL DEFAULT '',
+			input_tokens INTEGER NOT NULL DEFAULT 0,
+			output_tokens INTEGER NOT NULL DEFAULT 0,
+			audio_seconds REAL NOT NULL DEFAULT 0,
+			cost_usd REAL NOT NULL DEFAULT 0,
+			usage_date TEXT NOT NULL,
+			recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_ai_usage_date ON ai_usage(usage_date)`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *createAIUsageTable) Down(tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS ai_usage`)
+	return err
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&addSubtitleOutputMode{
+		migrationBase: NewMigrationBase(42, "add_subtitle_output_mode"),
+	})
+}
+
+// addSubtitleOutputMode adds the bilingual delivery switch in two places:
+// the per-library default (media_libraries.subtitle_output_mode) and the
+// layout each run actually delivered (subtitle_runs.output_mode), which joins
+// the resume tuple so a bilingual run is never skipped on the strength of an
+// earlier translated-only one.
+//
+// Both default to empty — translated only, exactly what every existing
+// library and run means — so nothing changes until someone opts in.
+type addSubtitleOutputMode struct {
+	migrationBase
+}
+
+func (m *addSubtitleOutputMode) Up(tx *sql.Tx) error {
+	if !columnExists(tx, "media_libraries", "subtitle_output_mode") {
+		if _, err := tx.Exec("ALTER TABLE media_libraries ADD COLUMN subtitle_output_mode TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	if !columnExists(tx, "subtitle_runs", "output_mode") {
+		if _, err := tx.Exec("ALTER TABLE subtitle_runs ADD COLUMN output_mode TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *addSubtitleOutputMode) Down(tx *sql.Tx) error {
+	// Empty is the shipped behaviour and harmless if left in place; SQLite
+	// DROP COLUMN support is version-dependent (mirrors migration 031).
+	return nil
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Existing libraries and runs must come out translated-only — the empty mode —
+// so nothing is delivered differently until someone opts in.
+func TestAddSubtitleOutputMode_DefaultsEmptyAndIsIdempotent(t *testing.T) {
+	db := setupSubtitleRunsMigration(t)
+	defer db.Close()
+	_, err := db.Exec(`CREATE TABLE media_libraries (id TEXT PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO media_libraries (id, name) VALUES ('lib-1', '影集')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO subtitle_runs (id, media_id, media_type) VALUES ('run1', 'm1', 'movie')`)
+	require.NoError(t, err)
+
+	migration := &addSubtitleOutputMode{migrationBase: NewMigrationBase(42, "add_subtitle_output_mode")}
+	for range 2 {
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, migration.Up(tx), "re-running must be a no-op — migrations replay on every boot")
+		require.NoError(t, tx.Commit())
+	}
+
+	var libraryMode, runMode string
+	require.NoError(t, db.QueryRow(`SELECT subtitle_output_mode FROM media_libraries WHERE id = 'lib-1'`).Scan(&libraryMode))
+	require.NoError(t, db.QueryRow(`SELECT output_mode FROM subtitle_runs WHERE id = 'run1'`).Scan(&runMode))
+	assert.Empty(t, libraryMode)
+	assert.Empty(t, runMode)
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import "database/sql"
+
+func init() {
+	Register(&createOrganizedSourcesTable{
+		migrationBase: NewMigrationBase(43, "create_organized_sources_table"),
+	})
+}
+
+// createOrganizedSourcesTable adds the ledger of files the organizer copied or
+// hardlinked into place and left behind. The original still sits under the
+// library root — that is the point of those modes, it keeps seeding — but its
+// row now points at the organized copy, so without this ledger the next scan
+// finds an unknown file and inserts a second row for the same movie.
+//
+// source_path is the left-behind file; target_path is where its row went.
+type createOrganizedSourcesTable struct {
+	migrationBase
+}
+
+func (m *createOrganizedSourcesTable) Up(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS organized_sources (
+		source_path TEXT PRIMARY KEY,
+		target_path TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	return err
+}
+
+func (m *createOrganizedSourcesTable) Down(tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS organized_sources`)
+	return err
+}
@@ -0,0 +1,312 @@
+// Package deluge is a client for the Deluge 2.x Web UI JSON-RPC API that
+// speaks the download subsystem's torrent model (qbittorrent.Torrent), so
+// Vido can use Deluge in place of qBittorrent.
+//
+// The Web UI is a proxy: after logging in, the session must also be
+// connected to a daemon. The client connects to the first configured host
+// when the Web UI is not connected yet.
+//
+// Reference: https://deluge.readthedocs.io/en/latest/reference/webapi.html
+package deluge
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vido/api/internal/qbittorrent"
+)
+
+// errCodeNotAuthenticated is the JSON-RPC error code Deluge answers with
+// when the session cookie is missing or expired.
+const errCodeNotAuthenticated = 1
+
+// Config holds a Deluge Web UI connection. Host is the Web UI URL
+// (http://nas:8112) and Password its password; the Web UI has no user
+// names.
+type Config struct {
+	Host     string
+	Password string
+	BasePath string
+	Timeout  time.Duration
+}
+
+// Client talks to one Deluge Web UI. It is safe for concurrent use.
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	nextID     atomic.Int64
+
+	// mu serialises logins so concurrent calls on an expired session do not
+	// all log in at once.
+	mu       sync.Mutex
+	loggedIn bool
+}
+
+// NewClient creates a Client. The default timeout is 10 seconds, as for
+// qBittorrent.
+func NewClient(config *Config) *Client {
+	jar, _ := cookiejar.New(nil)
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	return &Client{config: config, httpClient: &http.Client{Timeout: timeout, Jar: jar}}
+}
+
+func (c *Client) rpcURL() string {
+	return strings.TrimSuffix(c.config.Host, "/") + strings.TrimSuffix(c.config.BasePath, "/") + "/json"
+}
+
+type rpcRequest struct {
+	Method string `json:"method"`
+	Params []any  `json:"params"`
+	ID     int64  `json:"id"`
+}
+
+type rpcError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// rawCall runs one JSON-RPC method without session handling.
+func (c *Client) rawCall(ctx context.Context, method string, params []any, out any) (*rpcError, error) {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(rpcRequest{Method: method, Params: params, ID: c.nextID.Add(1)})
+	if err != nil {
+		return nil, fmt.Errorf("deluge %s: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.rpcURL(), bytes.NewReader(body))
+	if err != nil {
+		return nil, &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeConnectionFailed, Message: "failed to create request", Cause: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		code := qbittorrent.ErrCodeConnectionFailed
+		if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "Client.Timeout") {
+			code = qbittorrent.ErrCodeTimeout
+		}
+		return nil, &qbittorrent.ConnectionError{Code: code, Message: "cannot reach Deluge", Cause: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &qbittorrent.ConnectionError{
+			Code:    qbittorrent.ErrCodeConnectionFailed,
+			Message: fmt.Sprintf("deluge %s failed with status %d", method, resp.StatusCode),
+		}
+	}
+
+	var rpcResp rpcResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 32<<20)).Decode(&rpcResp); err != nil {
+		return nil, &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeConnectionFailed, Message: "failed to decode Deluge response", Cause: err}
+	}
+	if rpcResp.Error != nil {
+		return rpcResp.Error, nil
+	}
+	if out != nil && len(rpcResp.Result) > 0 {
+		if err := json.Unmarshal(rpcResp.Result, out); err != nil {
+			return nil, &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeConnectionFailed, Message: "failed to decode Deluge result", Cause: err}
+		}
+	}
+	return nil, nil
+}
+
+// login authenticates the session and makes sure the Web UI is connected
+// to a daemon.
+func (c *Client) login(ctx context.Context) error {
+	var ok bool
+	if rpcErr, err := c.rawCall(ctx, "auth.login", []any{c.config.Password}, &ok); err != nil {
+		return err
+	} else if rpcErr != nil || !ok {
+		return &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeAuthFailed, Message: "Deluge rejected the password"}
+	}
+
+	var connected bool
+	if _, err := c.rawCall(ctx, "web.connected", nil, &connected); err != nil {
+		return err
+	}
+	if connected {
+		return nil
+	}
+	var hosts [][]any
+	if _, err := c.rawCall(ctx, "web.get_hosts", nil, &hosts); err != nil {
+		return err
+	}
+	if len(hosts) == 0 || len(hosts[0]) == 0 {
+		return &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeConnectionFailed, Message: "Deluge Web UI has no daemon configured"}
+	}
+	if rpcErr, err := c.rawCall(ctx, "web.connect", []any{hosts[0][0]}, nil); err != nil {
+		return err
+	} else if rpcErr != nil {
+		return &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeConnectionFailed, Message: "Deluge daemon connect failed: " + rpcErr.Message}
+	}
+	return nil
+}
+
+// call runs a method on a logged-in session, logging in first when needed
+// and once more if Deluge reports the session expired.
+func (c *Client) call(ctx context.Context, method string, params []any, out any) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		c.mu.Lock()
+		if !c.loggedIn {
+			if err := c.login(ctx); err != nil {
+				c.mu.Unlock()
+				return err
+			}
+			c.loggedIn = true
+		}
+		c.mu.Unlock()
+
+		rpcErr, err := c.rawCall(ctx, method, params, out)
+		if err != nil {
+			return err
+		}
+		if rpcErr == nil {
+			return nil
+		}
+		if rpcErr.Code != errCodeNotAuthenticated {
+			return &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeRequestRejected, Message: "Deluge: " + rpcErr.Message}
+		}
+		c.mu.Lock()
+		c.loggedIn = false
+		c.mu.Unlock()
+	}
+	return &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeAuthFailed, Message: "Deluge session could not be authenticated"}
+}
+
+// Ping checks Deluge is reachable with the configured password.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.TestConnection(ctx)
+	return err
+}
+
+// TestConnection logs in afresh and returns the daemon version.
+func (c *Client) TestConnection(ctx context.Context) (*qbittorrent.VersionInfo, error) {
+	c.mu.Lock()
+	c.loggedIn = false
+	c.mu.Unlock()
+
+	var version string
+	if err := c.call(ctx, "daemon.info", nil, &version); err != nil {
+		return nil, err
+	}
+	return &qbittorrent.VersionInfo{AppVersion: version}, nil
+}
+
+// statusKeys are requested by every core.get_torrents_status.
+var statusKeys = []string{
+	"name", "total_size", "progress", "download_payload_rate", "upload_payload_rate",
+	"eta", "state", "time_added", "completed_time", "num_seeds", "num_peers",
+	"total_done", "total_uploaded", "ratio", "download_location", "label",
+	"piece_length", "comment", "creator", "total_wasted", "active_time", "seeding_time",
+}
+
+func (c *Client) getTorrents(ctx context.Context, hashes []string) (map[string]dlTorrent, error) {
+	filter := map[string]any{}
+	if hashes != nil {
+		filter["id"] = hashes
+	}
+	var out map[string]dlTorrent
+	if err := c.call(ctx, "core.get_torrents_status", []any{filter, statusKeys}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTorrents lists torrents, filtered and sorted client-side.
+func (c *Client) GetTorrents(ctx context.Context, opts *qbittorrent.ListTorrentsOptions) ([]qbittorrent.Torrent, error) {
+	raw, err := c.getTorrents(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	torrents := make([]qbittorrent.Torrent, 0, len(raw))
+	for hash, t := range raw {
+		torrents = append(torrents, t.toTorrent(hash))
+	}
+	// Deluge answers with a map, so without a sort field the order would
+	// change on every poll.
+	sorted := qbittorrent.ListTorrentsOptions{}
+	if opts != nil {
+		sorted = *opts
+	}
+	if sorted.Sort == "" {
+		sorted.Sort = qbittorrent.SortAddedOn
+	}
+	return qbittorrent.ApplyListOptions(torrents, &sorted), nil
+}
+
+// GetTorrentDetails returns one torrent.
+func (c *Client) GetTorrentDetails(ctx context.Context, hash string) (*qbittorrent.TorrentDetails, error) {
+	raw, err := c.getTorrents(ctx, []string{hash})
+	if err != nil {
+		return nil, err
+	}
+	for id, t := range raw {
+		return t.toDetails(id), nil
+	}
+	return nil, &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeTorrentNotFound, Message: "torrent not found: " + hash}
+}
+
+// PauseTorrents pauses torrents.
+func (c *Client) PauseTorrents(ctx context.Context, hashes []string) error {
+	return c.call(ctx, "core.pause_torrents", []any{hashes}, nil)
+}
+
+// ResumeTorrents resumes torrents.
+func (c *Client) ResumeTorrents(ctx context.Context, hashes []string) error {
+	return c.call(ctx, "core.resume_torrents", []any{hashes}, nil)
+}
+
+// DeleteTorrents removes torrents, with their data when deleteFiles is set.
+func (c *Client) DeleteTorrents(ctx context.Context, hashes []string, deleteFiles bool) error {
+	return c.call(ctx, "core.remove_torrents", []any{hashes, deleteFiles}, nil)
+}
+
+// AddTorrent adds each URL and the torrent file, if any. The category and
+// tags are not sent: labels need Deluge's optional Label plugin, so request
+// tracking falls back to the info-hash.
+func (c *Client) AddTorrent(ctx context.Context, opts qbittorrent.AddTorrentOptions) error {
+	if len(opts.URLs) == 0 && len(opts.Torrent) == 0 {
+		return fmt.Errorf("add torrent: no URL or torrent file given")
+	}
+	options := map[string]any{"add_paused": opts.Paused}
+	if opts.SavePath != "" {
+		options["download_location"] = opts.SavePath
+	}
+	for _, u := range opts.URLs {
+		method := "core.add_torrent_url"
+		if strings.HasPrefix(u, "magnet:") {
+			method = "core.add_torrent_magnet"
+		}
+		if err := c.call(ctx, method, []any{u, options}, nil); err != nil {
+			return err
+		}
+	}
+	if len(opts.Torrent) > 0 {
+		name := opts.FileName
+		if name == "" {
+			name = "upload.torrent"
+		}
+		return c.call(ctx, "core.add_torrent_file", []any{name, base64.StdEncoding.EncodeToString(opts.Torrent), options}, nil)
+	}
+	return nil
+}
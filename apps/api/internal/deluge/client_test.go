@@ -0,0 +1,151 @@
+package deluge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/qbittorrent"
+)
+
+// fakeDeluge is a Web UI that starts disconnected from its daemon and can
+// expire the session.
+type fakeDeluge struct {
+	connected bool
+	expire    bool
+	logins    int
+	calls     []rpcCall
+	torrents  string
+}
+
+type rpcCall struct {
+	Method string `json:"method"`
+	Params []any  `json:"params"`
+}
+
+func (f *fakeDeluge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var call rpcCall
+	_ = json.NewDecoder(r.Body).Decode(&call)
+	reply := func(result string) { fmt.Fprintf(w, `{"result":%s,"error":null,"id":1}`, result) }
+
+	if call.Method == "auth.login" {
+		f.logins++
+		if call.Params[0] != "deluge" {
+			reply("false")
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "_session_id", Value: fmt.Sprint(f.logins)})
+		reply("true")
+		return
+	}
+	cookie, err := r.Cookie("_session_id")
+	if err != nil || (f.expire && cookie.Value == "1") {
+		fmt.Fprint(w, `{"result":null,"error":{"message":"Not authenticated","code":1},"id":1}`)
+		return
+	}
+	f.calls = append(f.calls, call)
+	switch call.Method {
+	case "web.connected":
+		reply(fmt.Sprint(f.connected))
+	case "web.get_hosts":
+		reply(`[["h1","127.0.0.1",58846,"localclient"]]`)
+	case "web.connect":
+		f.connected = true
+		reply("[]")
+	case "daemon.info":
+		reply(`"2.1.1"`)
+	case "core.get_torrents_status":
+		reply(f.torrents)
+	default:
+		reply("null")
+	}
+}
+
+func newFakeClient(t *testing.T, password string) (*Client, *fakeDeluge) {
+	fake := &fakeDeluge{torrents: `{}`}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return NewClient(&Config{Host: server.URL, Password: password}), fake
+}
+
+func TestClient_TestConnection(t *testing.T) {
+	client, fake := newFakeClient(t, "deluge")
+	info, err := client.TestConnection(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "2.1.1", info.AppVersion)
+	assert.True(t, fake.connected, "connects the Web UI to its daemon")
+
+	t.Run("wrong password", func(t *testing.T) {
+		client, _ := newFakeClient(t, "nope")
+		_, err := client.TestConnection(context.Background())
+		var connErr *qbittorrent.ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Equal(t, qbittorrent.ErrCodeAuthFailed, connErr.Code)
+	})
+}
+
+func TestClient_GetTorrents(t *testing.T) {
+	client, fake := newFakeClient(t, "deluge")
+	fake.torrents = `{
+		"BBBB":{"name":"Later","progress":100,"state":"Paused","time_added":1700000500.5,"completed_time":1700000900,"ratio":-1},
+		"aaaa":{"name":"Earlier","progress":25,"state":"Downloading","num_seeds":3,"download_payload_rate":10,"time_added":1700000000,"label":"tv"}
+	}`
+
+	torrents, err := client.GetTorrents(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, torrents, 2)
+	assert.Equal(t, "aaaa", torrents[0].Hash, "sorted by added time")
+	assert.InDelta(t, 0.25, torrents[0].Progress, 0.001)
+	assert.Equal(t, qbittorrent.StatusDownloading, torrents[0].Status)
+	assert.Equal(t, "tv", torrents[0].Category)
+	assert.Equal(t, "bbbb", torrents[1].Hash)
+	assert.Equal(t, qbittorrent.StatusCompleted, torrents[1].Status)
+	assert.Zero(t, torrents[1].Ratio)
+
+	t.Run("unknown hash is not found", func(t *testing.T) {
+		fake.torrents = `{}`
+		_, err := client.GetTorrentDetails(context.Background(), "cccc")
+		var connErr *qbittorrent.ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Equal(t, qbittorrent.ErrCodeTorrentNotFound, connErr.Code)
+	})
+}
+
+func TestClient_ExpiredSessionLogsInAgain(t *testing.T) {
+	client, fake := newFakeClient(t, "deluge")
+	fake.connected = true
+	require.NoError(t, client.PauseTorrents(context.Background(), []string{"aaaa"}))
+	fake.expire = true
+
+	require.NoError(t, client.ResumeTorrents(context.Background(), []string{"aaaa"}))
+	assert.Equal(t, 2, fake.logins)
+	last := fake.calls[len(fake.calls)-1]
+	assert.Equal(t, "core.resume_torrents", last.Method)
+}
+
+func TestClient_AddTorrent(t *testing.T) {
+	client, fake := newFakeClient(t, "deluge")
+	fake.connected = true
+	require.NoError(t, client.AddTorrent(context.Background(), qbittorrent.AddTorrentOptions{
+		URLs:     []string{"magnet:?xt=urn:btih:aaaa", "https://example.com/a.torrent"},
+		Torrent:  []byte("d4:infoe"),
+		SavePath: "/data",
+		Paused:   true,
+	}))
+
+	var methods []string
+	for _, c := range fake.calls {
+		methods = append(methods, c.Method)
+	}
+	assert.Equal(t, []string{"web.connected", "core.add_torrent_magnet", "core.add_torrent_url", "core.add_torrent_file"}, methods)
+	options := fake.calls[1].Params[1].(map[string]any)
+	assert.Equal(t, "/data", options["download_location"])
+	assert.Equal(t, true, options["add_paused"])
+	assert.Equal(t, "ZDQ6aW5mb2U=", fake.calls[3].Params[1])
+}
@@ -0,0 +1,109 @@
+package deluge
+
+import (
+	"strings"
+	"time"
+
+	"github.com/vido/api/internal/qbittorrent"
+)
+
+// dlTorrent is one core.get_torrents_status entry. Progress is a
+// percentage; the times are float Unix seconds.
+type dlTorrent struct {
+	Name                string  `json:"name"`
+	TotalSize           int64   `json:"total_size"`
+	Progress            float64 `json:"progress"`
+	DownloadPayloadRate int64   `json:"download_payload_rate"`
+	UploadPayloadRate   int64   `json:"upload_payload_rate"`
+	ETA                 int64   `json:"eta"`
+	State               string  `json:"state"`
+	TimeAdded           float64 `json:"time_added"`
+	CompletedTime       float64 `json:"completed_time"`
+	NumSeeds            int     `json:"num_seeds"`
+	NumPeers            int     `json:"num_peers"`
+	TotalDone           int64   `json:"total_done"`
+	TotalUploaded       int64   `json:"total_uploaded"`
+	Ratio               float64 `json:"ratio"`
+	DownloadLocation    string  `json:"download_location"`
+	Label               string  `json:"label"`
+	PieceLength         int64   `json:"piece_length"`
+	Comment             string  `json:"comment"`
+	Creator             string  `json:"creator"`
+	TotalWasted         int64   `json:"total_wasted"`
+	ActiveTime          int64   `json:"active_time"`
+	SeedingTime         int64   `json:"seeding_time"`
+}
+
+// mapState normalises a Deluge state. A paused torrent is "paused" until it
+// has all its data and "completed" after, matching qBittorrent's
+// pausedDL/pausedUP split.
+func mapState(t dlTorrent) qbittorrent.TorrentStatus {
+	switch t.State {
+	case "Downloading":
+		if t.NumSeeds == 0 && t.DownloadPayloadRate == 0 {
+			return qbittorrent.StatusStalled
+		}
+		return qbittorrent.StatusDownloading
+	case "Seeding":
+		return qbittorrent.StatusSeeding
+	case "Paused":
+		if t.Progress >= 100 {
+			return qbittorrent.StatusCompleted
+		}
+		return qbittorrent.StatusPaused
+	case "Checking", "Moving", "Allocating":
+		return qbittorrent.StatusChecking
+	case "Queued":
+		return qbittorrent.StatusQueued
+	case "Error":
+		return qbittorrent.StatusError
+	default:
+		return qbittorrent.StatusDownloading
+	}
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.Unix(int64(seconds), 0).UTC()
+}
+
+func (t dlTorrent) toTorrent(hash string) qbittorrent.Torrent {
+	out := qbittorrent.Torrent{
+		Hash:          strings.ToLower(hash),
+		Name:          t.Name,
+		Size:          t.TotalSize,
+		Progress:      t.Progress / 100,
+		DownloadSpeed: t.DownloadPayloadRate,
+		UploadSpeed:   t.UploadPayloadRate,
+		ETA:           t.ETA,
+		Status:        mapState(t),
+		AddedOn:       unixTime(t.TimeAdded),
+		Seeds:         t.NumSeeds,
+		Peers:         t.NumPeers,
+		Downloaded:    t.TotalDone,
+		Uploaded:      t.TotalUploaded,
+		Ratio:         t.Ratio,
+		SavePath:      t.DownloadLocation,
+		Category:      t.Label,
+	}
+	// ratio is -1 before anything was downloaded.
+	if out.Ratio < 0 {
+		out.Ratio = 0
+	}
+	if t.CompletedTime > 0 {
+		doneOn := unixTime(t.CompletedTime)
+		out.CompletedOn = &doneOn
+	}
+	return out
+}
+
+func (t dlTorrent) toDetails(hash string) *qbittorrent.TorrentDetails {
+	return &qbittorrent.TorrentDetails{
+		Torrent:     t.toTorrent(hash),
+		PieceSize:   t.PieceLength,
+		Comment:     t.Comment,
+		CreatedBy:   t.Creator,
+		TotalWasted: t.TotalWasted,
+		TimeElapsed: t.ActiveTime,
+		SeedingTime: t.SeedingTime,
+	}
+}
@@ -0,0 +1,121 @@
+// Package handlers — AIUsageHandler.
+//
+// The AI spend ledger and its global caps: what has been spent today and this
+// month against the caps, the caps themselves, and rollups of the ledger by
+// day, month, provider, model, feature, media item or run. Everything lives
+// under /settings/ai-usage, so the routes are admin-only through AdminRoutes.
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/ai"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/services"
+)
+
+// AIUsageHandler handles HTTP requests for AI spend.
+type AIUsageHandler struct {
+	service services.AISpendServiceInterface
+}
+
+// NewAIUsageHandler creates a new AIUsageHandler.
+func NewAIUsageHandler(service services.AISpendServiceInterface) *AIUsageHandler {
+	return &AIUsageHandler{service: service}
+}
+
+// RegisterRoutes registers the AI spend routes.
+func (h *AIUsageHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	group := rg.Group("/settings/ai-usage")
+	{
+		group.GET("", h.GetStatus)
+		group.PUT("/caps", h.SaveCaps)
+		group.GET("/rollup", h.Rollup)
+	}
+}
+
+// GetStatus handles GET /api/v1/settings/ai-usage
+// @Summary Get the AI spend caps and today's and this month's spend
+// @Tags ai-usage
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.AISpendStatus}
+// @Router /api/v1/settings/ai-usage [get]
+func (h *AIUsageHandler) GetStatus(c *gin.Context) {
+	status, err := h.service.GetStatus(c.Request.Context())
+	if err != nil {
+		slog.Error("Failed to load AI spend", "error", err)
+		InternalServerError(c, "Failed to load AI spend")
+		return
+	}
+	SuccessResponse(c, status)
+}
+
+// SaveCaps handles PUT /api/v1/settings/ai-usage/caps
+// @Summary Save the global daily and monthly AI spend caps
+// @Description USD; 0 means no cap.
+// @Tags ai-usage
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.AISpendStatus}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR | VALIDATION_FAILED"
+// @Router /api/v1/settings/ai-usage/caps [put]
+func (h *AIUsageHandler) SaveCaps(c *gin.Context) {
+	var caps services.AISpendCaps
+	if err := c.ShouldBindJSON(&caps); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+	if err := h.service.SaveCaps(c.Request.Context(), caps); err != nil {
+		var validationErr *models.ValidationError
+		if errors.As(err, &validationErr) {
+			BadRequestError(c, "VALIDATION_FAILED", err.Error())
+			return
+		}
+		slog.Error("Failed to save AI spend caps", "error", err)
+		InternalServerError(c, "Failed to save AI spend caps")
+		return
+	}
+	h.GetStatus(c)
+}
+
+// Rollup handles GET /api/v1/settings/ai-usage/rollup
+// @Summary Sum AI spend by day, month, provider, model, feature, media or run
+// @Tags ai-usage
+// @Produce json
+// @Param group_by query string false "day (default), month, provider, model, feature, media, run"
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD"
+// @Success 200 {object} APIResponse{data=[]models.AIUsageRollup}
+// @Failure 400 {object} APIResponse "VALIDATION_FAILED"
+// @Router /api/v1/settings/ai-usage/rollup [get]
+func (h *AIUsageHandler) Rollup(c *gin.Context) {
+	rollup, err := h.service.Rollup(c.Request.Context(),
+		c.DefaultQuery("group_by", "day"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		var validationErr *models.ValidationError
+		if errors.As(err, &validationErr) {
+			BadRequestError(c, "VALIDATION_FAILED", err.Error())
+			return
+		}
+		slog.Error("Failed to roll up AI spend", "error", err)
+		InternalServerError(c, "Failed to roll up AI spend")
+		return
+	}
+	SuccessResponse(c, rollup)
+}
+
+// respondSpendCapReached writes 429 AI_SPEND_CAP_REACHED when err is a global
+// AI spend cap refusal, and reports whether it did.
+func respondSpendCapReached(c *gin.Context, err error) bool {
+	if !errors.Is(err, ai.ErrSpendCapReached) {
+		return false
+	}
+	ErrorResponse(c, http.StatusTooManyRequests, "AI_SPEND_CAP_REACHED",
+		"已達 AI 花費上限："+err.Error(),
+		"Wait for the daily or monthly cap to reset, or raise it under Settings → AI usage.")
+	return true
+}
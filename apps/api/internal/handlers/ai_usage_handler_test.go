@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/services"
+)
+
+// fakeAISpend implements services.AISpendServiceInterface.
+type fakeAISpend struct {
+	caps services.AISpendCaps
+}
+
+func (f *fakeAISpend) GetStatus(ctx context.Context) (*services.AISpendStatus, error) {
+	return &services.AISpendStatus{Caps: f.caps, Today: "2026-10-16", TodaySpentUSD: 1.25}, nil
+}
+func (f *fakeAISpend) SaveCaps(ctx context.Context, caps services.AISpendCaps) error {
+	if err := caps.Validate(); err != nil {
+		return err
+	}
+	f.caps = caps
+	return nil
+}
+func (f *fakeAISpend) Rollup(ctx context.Context, groupBy, fromDate, toDate string) ([]models.AIUsageRollup, error) {
+	if groupBy != "day" {
+		return nil, &models.ValidationError{Field: "group_by", Message: "group_by must be one of day"}
+	}
+	return []models.AIUsageRollup{{Key: "2026-10-16", Calls: 3, CostUSD: 1.25}}, nil
+}
+func (f *fakeAISpend) CheckSpend(ctx context.Context) error { return nil }
+
+var _ services.AISpendServiceInterface = (*fakeAISpend)(nil)
+
+func setupAIUsageRouter(svc *fakeAISpend) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewAIUsageHandler(svc).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestAIUsageHandler_Caps(t *testing.T) {
+	svc := &fakeAISpend{}
+	router := setupAIUsageRouter(svc)
+
+	w := sendJSON(router, http.MethodPut, "/api/v1/settings/ai-usage/caps", `{"daily_usd":2,"monthly_usd":30}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"monthly_usd":30`)
+	assert.Contains(t, w.Body.String(), `"today_spent_usd":1.25`)
+
+	w = sendJSON(router, http.MethodPut, "/api/v1/settings/ai-usage/caps", `{"daily_usd":-1}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "VALIDATION_FAILED")
+}
+
+func TestAIUsageHandler_Rollup(t *testing.T) {
+	router := setupAIUsageRouter(&fakeAISpend{})
+
+	w := sendJSON(router, http.MethodGet, "/api/v1/settings/ai-usage/rollup", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"calls":3`)
+
+	w = sendJSON(router, http.MethodGet, "/api/v1/settings/ai-usage/rollup?group_by=colour", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
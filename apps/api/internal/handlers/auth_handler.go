@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/services"
+)
+
+// AuthServiceInterface defines the contract for accounts and sessions.
+type AuthServiceInterface interface {
+	AuthResolver
+	SessionTTL() time.Duration
+	Bootstrap(ctx context.Context, username, password, userAgent string) (*services.AuthSession, error)
+	Login(ctx context.Context, username, password, userAgent string) (*services.AuthSession, error)
+	Logout(ctx context.Context, token string) error
+	ChangePassword(ctx context.Context, userID, currentToken, currentPassword, newPassword string) error
+	ListUsers(ctx context.Context) ([]models.User, error)
+	CreateUser(ctx context.Context, req services.CreateUserRequest) (*models.User, error)
+	UpdateUser(ctx context.Context, id string, req services.UpdateUserRequest) (*models.User, error)
+	DeleteUser(ctx context.Context, id, actingUserID string) error
+}
+
+// LoginRequest is the body of POST /auth/login and /auth/bootstrap.
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// ChangePasswordRequest is the body of PUT /auth/password.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// AuthHandler handles login, logout and account management.
+type AuthHandler struct {
+	service AuthServiceInterface
+}
+
+// NewAuthHandler creates a new AuthHandler.
+func NewAuthHandler(service AuthServiceInterface) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+// RegisterRoutes registers the auth and user-management routes. /users is
+// admin-only through AdminRoutes.
+func (h *AuthHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	auth := rg.Group("/auth")
+	{
+		auth.GET("/status", h.GetStatus)
+		auth.POST("/bootstrap", h.Bootstrap)
+		auth.POST("/login", h.Login)
+		auth.POST("/logout", h.Logout)
+		auth.GET("/me", h.Me)
+		auth.PUT("/password", h.ChangePassword)
+	}
+	users := rg.Group("/users")
+	{
+		users.GET("", h.ListUsers)
+		users.POST("", h.CreateUser)
+		users.PUT("/:id", h.UpdateUser)
+		users.DELETE("/:id", h.DeleteUser)
+	}
+}
+
+// GetStatus handles GET /api/v1/auth/status
+// Tells the UI whether to show a login form, the first-admin form, or nothing.
+func (h *AuthHandler) GetStatus(c *gin.Context) {
+	required, err := h.service.AuthRequired(c.Request.Context())
+	if err != nil {
+		slog.Error("Failed to check authentication state", "error", err)
+		InternalServerError(c, "Failed to check authentication state")
+		return
+	}
+
+	status := gin.H{
+		"auth_required":   required,
+		"needs_bootstrap": !required,
+		"authenticated":   false,
+	}
+	if required {
+		if user, err := h.service.ResolveSession(c.Request.Context(), sessionToken(c)); err == nil {
+			status["authenticated"] = true
+			status["user"] = user
+		}
+	}
+	SuccessResponse(c, status)
+}
+
+// Bootstrap handles POST /api/v1/auth/bootstrap
+// Creates the first admin while the install has no accounts, and signs them in.
+func (h *AuthHandler) Bootstrap(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	session, err := h.service.Bootstrap(c.Request.Context(), req.Username, req.Password, c.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, services.ErrAuthAlreadyBootstrapped) {
+			ErrorResponse(c, http.StatusConflict, "AUTH_ALREADY_BOOTSTRAPPED",
+				"An admin account already exists",
+				"Sign in with an existing account instead.")
+			return
+		}
+		handleAuthError(c, "Failed to create admin account", err)
+		return
+	}
+
+	setSessionCookie(c, session.Token, int(h.service.SessionTTL().Seconds()))
+	CreatedResponse(c, session)
+}
+
+// Login handles POST /api/v1/auth/login
+// Sets the session cookie and also returns the token for non-browser clients.
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	session, err := h.service.Login(c.Request.Context(), req.Username, req.Password, c.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			slog.Warn("Failed login attempt", "username", req.Username, "client_ip", c.ClientIP())
+			ErrorResponse(c, http.StatusUnauthorized, "AUTH_INVALID_CREDENTIALS",
+				"Invalid username or password",
+				"Check your credentials and try again.")
+			return
+		}
+		handleAuthError(c, "Failed to sign in", err)
+		return
+	}
+
+	setSessionCookie(c, session.Token, int(h.service.SessionTTL().Seconds()))
+	SuccessResponse(c, session)
+}
+
+// Logout handles POST /api/v1/auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.service.Logout(c.Request.Context(), sessionToken(c)); err != nil {
+		slog.Error("Failed to sign out", "error", err)
+		InternalServerError(c, "Failed to sign out")
+		return
+	}
+	setSessionCookie(c, "", -1)
+	SuccessResponse(c, gin.H{"message": "Signed out"})
+}
+
+// Me handles GET /api/v1/auth/me
+func (h *AuthHandler) Me(c *gin.Context) {
+	user := requireSignedIn(c)
+	if user == nil {
+		return
+	}
+	SuccessResponse(c, user)
+}
+
+// ChangePassword handles PUT /api/v1/auth/password
+// Other sessions of the account are signed out; this one stays.
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	user := requireSignedIn(c)
+	if user == nil {
+		return
+	}
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	err := h.service.ChangePassword(c.Request.Context(), user.ID, sessionToken(c), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			BadRequestError(c, "AUTH_INVALID_CREDENTIALS", "Current password is incorrect")
+			return
+		}
+		handleAuthError(c, "Failed to change password", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Password changed"})
+}
+
+// ListUsers handles GET /api/v1/users
+func (h *AuthHandler) ListUsers(c *gin.Context) {
+	if requireSignedIn(c) == nil {
+		return
+	}
+	users, err := h.service.ListUsers(c.Request.Context())
+	if err != nil {
+		handleAuthError(c, "Failed to list users", err)
+		return
+	}
+	SuccessResponse(c, users)
+}
+
+// CreateUser handles POST /api/v1/users
+func (h *AuthHandler) CreateUser(c *gin.Context) {
+	if requireSignedIn(c) == nil {
+		return
+	}
+	var req services.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	user, err := h.service.CreateUser(c.Request.Context(), req)
+	if err != nil {
+		handleAuthError(c, "Failed to create user", err)
+		return
+	}
+	CreatedResponse(c, user)
+}
+
+// UpdateUser handles PUT /api/v1/users/:id
+func (h *AuthHandler) UpdateUser(c *gin.Context) {
+	if requireSignedIn(c) == nil {
+		return
+	}
+	var req services.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	user, err := h.service.UpdateUser(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		handleAuthError(c, "Failed to update user", err)
+		return
+	}
+	SuccessResponse(c, user)
+}
+
+// DeleteUser handles DELETE /api/v1/users/:id
+func (h *AuthHandler) DeleteUser(c *gin.Context) {
+	actor := requireSignedIn(c)
+	if actor == nil {
+		return
+	}
+	if err := h.service.DeleteUser(c.Request.Context(), c.Param("id"), actor.ID); err != nil {
+		handleAuthError(c, "Failed to delete user", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "User deleted"})
+}
+
+// requireSignedIn returns the signed-in user or answers 401. Account routes
+// need a real account even on an install that is still open — the first one
+// is made through /auth/bootstrap.
+func requireSignedIn(c *gin.Context) *models.User {
+	user := CurrentUser(c)
+	if user == nil {
+		ErrorResponse(c, http.StatusUnauthorized, "AUTH_REQUIRED",
+			"Authentication required",
+			"Create the first admin account, then sign in.")
+	}
+	return user
+}
+
+func handleAuthError(c *gin.Context, message string, err error) {
+	var validationErr *models.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		BadRequestError(c, "VALIDATION_FAILED", err.Error())
+	case errors.Is(err, repository.ErrUserNotFound):
+		NotFoundError(c, "user")
+	case errors.Is(err, services.ErrLastAdmin):
+		ErrorResponse(c, http.StatusConflict, "AUTH_LAST_ADMIN",
+			"Cannot remove the last admin",
+			"Promote another account to admin first.")
+	case errors.Is(err, services.ErrCannotDeleteSelf):
+		BadRequestError(c, "AUTH_CANNOT_DELETE_SELF", "You cannot delete the account you are signed in with")
+	default:
+		slog.Error(message, "error", err)
+		InternalServerError(c, message)
+	}
+}
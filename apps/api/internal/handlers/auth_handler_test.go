@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/services"
+)
+
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) AuthRequired(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthService) ResolveSession(ctx context.Context, token string) (*models.User, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) SessionTTL() time.Duration { return time.Hour }
+
+func (m *MockAuthService) Bootstrap(ctx context.Context, username, password, userAgent string) (*services.AuthSession, error) {
+	args := m.Called(ctx, username, password, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.AuthSession), args.Error(1)
+}
+
+func (m *MockAuthService) Login(ctx context.Context, username, password, userAgent string) (*services.AuthSession, error) {
+	args := m.Called(ctx, username, password, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.AuthSession), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, token string) error {
+	return m.Called(ctx, token).Error(0)
+}
+
+func (m *MockAuthService) ChangePassword(ctx context.Context, userID, currentToken, currentPassword, newPassword string) error {
+	return m.Called(ctx, userID, currentToken, currentPassword, newPassword).Error(0)
+}
+
+func (m *MockAuthService) ListUsers(ctx context.Context) ([]models.User, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockAuthService) CreateUser(ctx context.Context, req services.CreateUserRequest) (*models.User, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) UpdateUser(ctx context.Context, id string, req services.UpdateUserRequest) (*models.User, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) DeleteUser(ctx context.Context, id, actingUserID string) error {
+	return m.Called(ctx, id, actingUserID).Error(0)
+}
+
+func setupAuthRouter(svc AuthServiceInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api/v1", RequireAuth(svc, AdminRoutes))
+	NewAuthHandler(svc).RegisterRoutes(api)
+	return router
+}
+
+func TestAuthHandler_LoginSetsSessionCookie(t *testing.T) {
+	svc := new(MockAuthService)
+	user := &models.User{ID: "u1", Username: "alice", Role: models.UserRoleUser}
+	svc.On("Login", mock.Anything, "alice", "correct-horse", mock.Anything).
+		Return(&services.AuthSession{Token: "tok", User: user, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login",
+		strings.NewReader(`{"username":"alice","password":"correct-horse"}`))
+	req.Header.Set("Content-Type", "application/json")
+	setupAuthRouter(svc).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, SessionCookieName, cookies[0].Name)
+	assert.Equal(t, "tok", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.NotContains(t, w.Body.String(), "password_hash")
+}
+
+func TestAuthHandler_LoginRejectsBadCredentials(t *testing.T) {
+	svc := new(MockAuthService)
+	svc.On("Login", mock.Anything, "alice", "nope-nope", mock.Anything).Return(nil, services.ErrInvalidCredentials)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login",
+		strings.NewReader(`{"username":"alice","password":"nope-nope"}`))
+	req.Header.Set("Content-Type", "application/json")
+	setupAuthRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "AUTH_INVALID_CREDENTIALS")
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestAuthHandler_BootstrapAfterFirstAdminConflicts(t *testing.T) {
+	svc := new(MockAuthService)
+	svc.On("Bootstrap", mock.Anything, "admin", "correct-horse", mock.Anything).Return(nil, services.ErrAuthAlreadyBootstrapped)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/bootstrap",
+		strings.NewReader(`{"username":"admin","password":"correct-horse"}`))
+	req.Header.Set("Content-Type", "application/json")
+	setupAuthRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "AUTH_ALREADY_BOOTSTRAPPED")
+}
+
+func TestAuthHandler_UsersAreAdminOnly(t *testing.T) {
+	svc := new(MockAuthService)
+	svc.On("AuthRequired", mock.Anything).Return(true, nil)
+	svc.On("ResolveSession", mock.Anything, "user-token").
+		Return(&models.User{ID: "u1", Username: "alice", Role: models.UserRoleUser}, nil)
+	svc.On("ResolveSession", mock.Anything, "admin-token").
+		Return(&models.User{ID: "a1", Username: "admin", Role: models.UserRoleAdmin}, nil)
+	svc.On("DeleteUser", mock.Anything, "a1", "a1").Return(services.ErrCannotDeleteSelf)
+	svc.On("DeleteUser", mock.Anything, "a2", "a1").Return(services.ErrLastAdmin)
+	router := setupAuthRouter(svc)
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusForbidden, do(http.MethodDelete, "/api/v1/users/a1", "user-token").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/api/v1/users/a1", "admin-token").Code)
+	assert.Equal(t, http.StatusConflict, do(http.MethodDelete, "/api/v1/users/a2", "admin-token").Code)
+	svc.AssertNotCalled(t, "DeleteUser", mock.Anything, "a1", "u1")
+}
+
+func TestAuthHandler_MeOnOpenInstallNeedsBootstrap(t *testing.T) {
+	svc := new(MockAuthService)
+	svc.On("AuthRequired", mock.Anything).Return(false, nil)
+
+	w := httptest.NewRecorder()
+	setupAuthRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/services"
+)
+
+// SessionCookieName is the cookie carrying the session token. API clients
+// that cannot keep cookies send the same token as `Authorization: Bearer`.
+const SessionCookieName = "vido_session"
+
+// userContextKey is where RequireAuth stores the signed-in *models.User.
+const userContextKey = "auth.user"
+
+// AuthResolver is the slice of the auth service the middleware needs.
+type AuthResolver interface {
+	AuthRequired(ctx context.Context) (bool, error)
+	ResolveSession(ctx context.Context, token string) (*models.User, error)
+}
+
+// AccessRule marks routes as admin-only. Method "" matches every method;
+// PathPrefix matches the route template (gin's FullPath) itself or anything
+// below it, so "/api/v1/settings" covers "/api/v1/settings/backups/:id" but
+// not a hypothetical "/api/v1/settings-preview".
+type AccessRule struct {
+	Method     string
+	PathPrefix string
+}
+
+func (r AccessRule) matches(method, fullPath string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+	return fullPath == r.PathPrefix || strings.HasPrefix(fullPath, r.PathPrefix+"/")
+}
+
+// AdminRoutes is the role split for /api/v1: everything that reconfigures the
+// install or destroys data. Kept in one table rather than spread across
+// RegisterRoutes so the whole policy can be reviewed at a glance.
+var AdminRoutes = []AccessRule{
+	// Every settings page, including API keys, backups/restore, logs, cache,
+	// export and the Radarr/Sonarr (DVR) connections.
+	{PathPrefix: "/api/v1/settings"},
+	{PathPrefix: "/api/v1/users"},
+	{Method: http.MethodPost, PathPrefix: "/api/v1/setup/complete"},
+	// Library folders and the organizer, which renames files on disk.
+	{Method: http.MethodPost, PathPrefix: "/api/v1/libraries"},
+	{Method: http.MethodPut, PathPrefix: "/api/v1/libraries"},
+	{Method: http.MethodDelete, PathPrefix: "/api/v1/libraries"},
+	// Library deletes, including DELETE /library/batch.
+	{Method: http.MethodDelete, PathPrefix: "/api/v1/library"},
+	{Method: http.MethodDelete, PathPrefix: "/api/v1/movies"},
+	{Method: http.MethodDelete, PathPrefix: "/api/v1/series"},
+	// Removing a download can take its files with it (?deleteFiles=true).
+	{Method: http.MethodDelete, PathPrefix: "/api/v1/downloads"},
+	// Resolving duplicates deletes or trashes files on disk.
+	{Method: http.MethodPost, PathPrefix: "/api/v1/library/duplicates"},
+	{Method: http.MethodPut, PathPrefix: "/api/v1/scanner/schedule"},
+	// The collection backfill walks the whole library against TMDb.
+	{Method: http.MethodPost, PathPrefix: "/api/v1/collections/sync"},
+	// qBittorrent-wide settings: categories carry save paths, and the global
+	// speed limits apply to every download.
+	{Method: http.MethodPost, PathPrefix: "/api/v1/downloads/categories"},
+	{Method: http.MethodDelete, PathPrefix: "/api/v1/downloads/categories"},
+	{Method: http.MethodPut, PathPrefix: "/api/v1/downloads/speed-limits"},
+	// Per-torrent controls that move files (a category's save path) or
+	// change what and how fast a torrent downloads.
+	{Method: http.MethodPut, PathPrefix: "/api/v1/downloads/:hash/category"},
+	{Method: http.MethodPut, PathPrefix: "/api/v1/downloads/:hash/files/priority"},
+	{Method: http.MethodPut, PathPrefix: "/api/v1/downloads/:hash/speed-limits"},
+	// A new calendar feed token cuts off every subscribed calendar app.
+	{Method: http.MethodPost, PathPrefix: "/api/v1/calendar/feed/rotate"},
+}
+
+// publicRoutes answer without a session: what a login page needs.
+var publicRoutes = map[string]bool{
+	"/api/v1/auth/status":    true,
+	"/api/v1/auth/login":     true,
+	"/api/v1/auth/bootstrap": true,
+}
+
+// RequireAuth authenticates every request once an account exists and enforces
+// rules for admin-only routes. An install without accounts stays open so an
+// upgrade never locks the operator out; the auth status endpoint tells the UI
+// to offer the first-admin bootstrap.
+func RequireAuth(resolver AuthResolver, rules []AccessRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fullPath := c.FullPath()
+		if publicRoutes[fullPath] || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		required, err := resolver.AuthRequired(c.Request.Context())
+		if err != nil {
+			slog.Error("Failed to check authentication state", "error", err)
+			InternalServerError(c, "Failed to check authentication state")
+			c.Abort()
+			return
+		}
+		if !required {
+			c.Next()
+			return
+		}
+
+		user, err := resolver.ResolveSession(c.Request.Context(), sessionToken(c))
+		if err != nil {
+			if !errors.Is(err, services.ErrSessionInvalid) {
+				slog.Error("Failed to resolve session", "error", err)
+				InternalServerError(c, "Failed to resolve session")
+				c.Abort()
+				return
+			}
+			clearSessionCookie(c)
+			ErrorResponse(c, http.StatusUnauthorized, "AUTH_REQUIRED",
+				"Authentication required",
+				"Sign in and try again.")
+			c.Abort()
+			return
+		}
+		c.Set(userContextKey, user)
+
+		if !user.IsAdmin() {
+			for _, rule := range rules {
+				if rule.matches(c.Request.Method, fullPath) {
+					ErrorResponse(c, http.StatusForbidden, "AUTH_FORBIDDEN",
+						"This action requires an admin account",
+						"Ask an admin to perform it or to change your role.")
+					c.Abort()
+					return
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
+// CurrentUser returns the signed-in user, or nil on an open install.
+func CurrentUser(c *gin.Context) *models.User {
+	if v, ok := c.Get(userContextKey); ok {
+		if user, ok := v.(*models.User); ok {
+			return user
+		}
+	}
+	return nil
+}
+
+// sessionToken reads the token from the Authorization header, falling back to
+// the session cookie.
+func sessionToken(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); header != "" {
+		if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	token, _ := c.Cookie(SessionCookieName)
+	return token
+}
+
+func setSessionCookie(c *gin.Context, token string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookieName, token, maxAge, "/", "", isSecureRequest(c), true)
+}
+
+func clearSessionCookie(c *gin.Context) {
+	if _, err := c.Cookie(SessionCookieName); err == nil {
+		setSessionCookie(c, "", -1)
+	}
+}
+
+// isSecureRequest reports whether the client reached us over HTTPS, directly
+// or through a reverse proxy; the cookie is marked Secure only then, because
+// most NAS installs are plain HTTP on the LAN.
+func isSecureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/services"
+)
+
+type fakeAuthResolver struct {
+	required bool
+	users    map[string]*models.User // token → user
+}
+
+func (f *fakeAuthResolver) AuthRequired(context.Context) (bool, error) { return f.required, nil }
+
+func (f *fakeAuthResolver) ResolveSession(_ context.Context, token string) (*models.User, error) {
+	if user, ok := f.users[token]; ok {
+		return user, nil
+	}
+	return nil, services.ErrSessionInvalid
+}
+
+func setupAuthMiddlewareRouter(resolver AuthResolver) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api/v1", RequireAuth(resolver, AdminRoutes))
+	ok := func(c *gin.Context) { SuccessResponse(c, gin.H{"user": CurrentUser(c)}) }
+	api.GET("/auth/status", ok)
+	api.GET("/movies", ok)
+	api.DELETE("/library/batch", ok)
+	api.GET("/settings/keys", ok)
+	api.POST("/settings/backups/:id/restore", ok)
+	api.PUT("/settings/radarr", ok)
+	api.POST("/libraries/:id/organize", ok)
+	api.POST("/downloads/:hash/pause", ok)
+	api.DELETE("/downloads/:hash", ok)
+	api.GET("/calendar/feed", ok)
+	api.POST("/calendar/feed/rotate", ok)
+	return router
+}
+
+func TestRequireAuth_OpenInstallPassesThrough(t *testing.T) {
+	router := setupAuthMiddlewareRouter(&fakeAuthResolver{required: false})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/library/batch", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "no accounts yet means no authentication")
+}
+
+func TestRequireAuth_RoleSplit(t *testing.T) {
+	resolver := &fakeAuthResolver{required: true, users: map[string]*models.User{
+		"admin-token": {ID: "a", Username: "admin", Role: models.UserRoleAdmin},
+		"user-token":  {ID: "u", Username: "alice", Role: models.UserRoleUser},
+	}}
+	router := setupAuthMiddlewareRouter(resolver)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"public status needs no session", http.MethodGet, "/api/v1/auth/status", "", http.StatusOK},
+		{"anonymous read is rejected", http.MethodGet, "/api/v1/movies", "", http.StatusUnauthorized},
+		{"revoked token is rejected", http.MethodGet, "/api/v1/movies", "stale", http.StatusUnauthorized},
+		{"user can browse", http.MethodGet, "/api/v1/movies", "user-token", http.StatusOK},
+		{"user cannot batch delete", http.MethodDelete, "/api/v1/library/batch", "user-token", http.StatusForbidden},
+		{"user cannot read API keys", http.MethodGet, "/api/v1/settings/keys", "user-token", http.StatusForbidden},
+		{"user cannot restore a backup", http.MethodPost, "/api/v1/settings/backups/b1/restore", "user-token", http.StatusForbidden},
+		{"user cannot change DVR config", http.MethodPut, "/api/v1/settings/radarr", "user-token", http.StatusForbidden},
+		{"user cannot organize a library", http.MethodPost, "/api/v1/libraries/l1/organize", "user-token", http.StatusForbidden},
+		{"user can pause a download", http.MethodPost, "/api/v1/downloads/abc/pause", "user-token", http.StatusOK},
+		{"user cannot remove a download", http.MethodDelete, "/api/v1/downloads/abc", "user-token", http.StatusForbidden},
+		{"user can read the calendar feed address", http.MethodGet, "/api/v1/calendar/feed", "user-token", http.StatusOK},
+		{"user cannot rotate the calendar feed token", http.MethodPost, "/api/v1/calendar/feed/rotate", "user-token", http.StatusForbidden},
+		{"admin can batch delete", http.MethodDelete, "/api/v1/library/batch", "admin-token", http.StatusOK},
+		{"admin can restore a backup", http.MethodPost, "/api/v1/settings/backups/b1/restore", "admin-token", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestRequireAuth_AcceptsSessionCookie(t *testing.T) {
+	resolver := &fakeAuthResolver{required: true, users: map[string]*models.User{
+		"cookie-token": {ID: "u", Username: "alice", Role: models.UserRoleUser},
+	}}
+	router := setupAuthMiddlewareRouter(resolver)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/movies", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "cookie-token"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"username":"alice"`)
+}
+
+func TestAccessRule_MatchesOnSegmentBoundary(t *testing.T) {
+	rule := AccessRule{PathPrefix: "/api/v1/settings"}
+	assert.True(t, rule.matches(http.MethodGet, "/api/v1/settings"))
+	assert.True(t, rule.matches(http.MethodGet, "/api/v1/settings/backups/:id"))
+	assert.False(t, rule.matches(http.MethodGet, "/api/v1/settings-preview"))
+
+	deleteOnly := AccessRule{Method: http.MethodDelete, PathPrefix: "/api/v1/library"}
+	assert.False(t, deleteOnly.matches(http.MethodGet, "/api/v1/library"))
+	assert.False(t, deleteOnly.matches(http.MethodDelete, "/api/v1/libraries/:id"))
+}
@@ -0,0 +1,171 @@
+// Package handlers — CalendarHandler.
+//
+// The episode calendar as JSON for the UI, plus the same window as an iCal
+// feed a calendar app can subscribe to.
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/services"
+)
+
+// CalendarHandler handles HTTP requests for the episode calendar.
+type CalendarHandler struct {
+	service services.CalendarServiceInterface
+}
+
+// NewCalendarHandler creates a new CalendarHandler.
+func NewCalendarHandler(service services.CalendarServiceInterface) *CalendarHandler {
+	return &CalendarHandler{service: service}
+}
+
+// RegisterRoutes registers the calendar routes.
+func (h *CalendarHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/calendar", h.GetCalendar)
+	rg.GET("/calendar/feed", h.GetFeed)
+	rg.POST("/calendar/feed/rotate", h.RotateFeed)
+}
+
+// CalendarFeedResponse is the subscription address the calendar page shows.
+// Path is relative to the server; Pinned means the token is set by
+// VIDO_CALENDAR_TOKEN and cannot be rotated here.
+type CalendarFeedResponse struct {
+	Path   string `json:"path"`
+	Token  string `json:"token"`
+	Pinned bool   `json:"pinned"`
+}
+
+// GetCalendar handles GET /api/v1/calendar
+// @Summary Upcoming and recently-aired-but-missing episodes of library series
+// @Tags calendar
+// @Produce json
+// @Param days query int false "Look-ahead window in days (default 14, max 90)"
+// @Param past_days query int false "Look-back window for missing episodes (default 7, max 30)"
+// @Success 200 {object} APIResponse{data=services.Calendar}
+// @Failure 400 {object} APIResponse "VALIDATION_INVALID_FORMAT"
+// @Router /api/v1/calendar [get]
+func (h *CalendarHandler) GetCalendar(c *gin.Context) {
+	opts, ok := calendarOptions(c)
+	if !ok {
+		return
+	}
+	cal, err := h.service.Calendar(c.Request.Context(), opts)
+	if err != nil {
+		slog.Error("Failed to build calendar", "error", err)
+		InternalServerError(c, "Failed to build calendar")
+		return
+	}
+	SuccessResponse(c, cal)
+}
+
+// GetFeed handles GET /api/v1/calendar/feed
+// @Summary The calendar's iCal subscription address, with its token
+// @Tags calendar
+// @Produce json
+// @Success 200 {object} APIResponse{data=CalendarFeedResponse}
+// @Router /api/v1/calendar/feed [get]
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	token, err := h.service.FeedToken(c.Request.Context())
+	if err != nil {
+		slog.Error("Failed to read calendar feed token", "error", err)
+		InternalServerError(c, "Failed to read calendar feed token")
+		return
+	}
+	SuccessResponse(c, calendarFeedResponse(token, h.service.FeedTokenPinned()))
+}
+
+// RotateFeed handles POST /api/v1/calendar/feed/rotate (admin only)
+// @Summary Replace the calendar feed token, cutting off existing subscriptions
+// @Tags calendar
+// @Produce json
+// @Success 200 {object} APIResponse{data=CalendarFeedResponse}
+// @Failure 409 {object} APIResponse "CALENDAR_TOKEN_PINNED"
+// @Router /api/v1/calendar/feed/rotate [post]
+func (h *CalendarHandler) RotateFeed(c *gin.Context) {
+	token, err := h.service.RotateFeedToken(c.Request.Context())
+	switch {
+	case err == nil:
+		SuccessResponse(c, calendarFeedResponse(token, false))
+	case errors.Is(err, services.ErrCalendarFeedTokenPinned):
+		ErrorResponse(c, http.StatusConflict, "CALENDAR_TOKEN_PINNED",
+			"行事曆訂閱權杖由 VIDO_CALENDAR_TOKEN 設定，無法在此更換。",
+			"修改環境變數後重新啟動服務。")
+	default:
+		slog.Error("Failed to rotate calendar feed token", "error", err)
+		InternalServerError(c, "Failed to rotate calendar feed token")
+	}
+}
+
+func calendarFeedResponse(token string, pinned bool) CalendarFeedResponse {
+	return CalendarFeedResponse{Path: "/calendar.ics?token=" + url.QueryEscape(token), Token: token, Pinned: pinned}
+}
+
+// CalendarFeedHandler serves the calendar as text/calendar. Like /metrics it
+// sits outside /api/v1 because calendar apps carry no session, so the
+// subscription URL always carries the feed token as ?token= — the generated
+// one GET /api/v1/calendar/feed hands out, or VIDO_CALENDAR_TOKEN when set.
+// An install with accounts must not list its shows to anyone on the LAN.
+func CalendarFeedHandler(service services.CalendarServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, err := service.FeedToken(c.Request.Context())
+		if err != nil {
+			slog.Error("Failed to read calendar feed token", "error", err)
+			InternalServerError(c, "Failed to read calendar feed token")
+			return
+		}
+		presented := c.Query("token")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			ErrorResponse(c, http.StatusUnauthorized, "AUTH_REQUIRED",
+				"Calendar token required",
+				"Copy the subscription URL from the calendar page; it carries the feed token.")
+			return
+		}
+		opts, ok := calendarOptions(c)
+		if !ok {
+			return
+		}
+		cal, err := service.Calendar(c.Request.Context(), opts)
+		if err != nil {
+			slog.Error("Failed to build calendar feed", "error", err)
+			InternalServerError(c, "Failed to build calendar feed")
+			return
+		}
+		c.Header("Content-Type", services.ICSContentType)
+		c.Header("Content-Disposition", `inline; filename="vido.ics"`)
+		c.Status(http.StatusOK)
+		if err := services.WriteICS(c.Writer, cal, time.Now()); err != nil {
+			_ = c.Error(err)
+		}
+	}
+}
+
+// calendarOptions reads the optional days / past_days query parameters.
+func calendarOptions(c *gin.Context) (services.CalendarOptions, bool) {
+	var opts services.CalendarOptions
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{{"days", &opts.Days}, {"past_days", &opts.PastDays}} {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			ErrorResponse(c, http.StatusBadRequest, "VALIDATION_INVALID_FORMAT",
+				p.name+" 必須是正整數", "省略此參數即使用預設範圍。")
+			return opts, false
+		}
+		*p.dst = n
+	}
+	return opts, true
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vido/api/internal/services"
+)
+
+// mockCalendarService records the options it was asked for.
+type mockCalendarService struct {
+	got    services.CalendarOptions
+	token  string
+	pinned bool
+}
+
+func (m *mockCalendarService) FeedToken(ctx context.Context) (string, error) {
+	if m.token == "" {
+		m.token = "generated"
+	}
+	return m.token, nil
+}
+
+func (m *mockCalendarService) RotateFeedToken(ctx context.Context) (string, error) {
+	if m.pinned {
+		return "", services.ErrCalendarFeedTokenPinned
+	}
+	m.token = "rotated"
+	return m.token, nil
+}
+
+func (m *mockCalendarService) FeedTokenPinned() bool { return m.pinned }
+
+func (m *mockCalendarService) Calendar(ctx context.Context, opts services.CalendarOptions) (*services.Calendar, error) {
+	m.got = opts
+	return &services.Calendar{
+		From: "2026-10-09", To: "2026-10-30",
+		Upcoming: []services.CalendarEpisode{{SeriesTitle: "葬送的芙莉蓮", SeriesTMDbID: 209867, SeasonNumber: 2, EpisodeNumber: 1, AirDate: "2026-10-20"}},
+		Missing:  []services.CalendarEpisode{},
+	}, nil
+}
+
+func TestCalendarHandler_GetCalendar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockCalendarService{}
+	router := gin.New()
+	NewCalendarHandler(svc).RegisterRoutes(router.Group("/api/v1"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/calendar?days=30&past_days=3", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"series_tmdb_id":209867`)
+	assert.Equal(t, services.CalendarOptions{Days: 30, PastDays: 3}, svc.got)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/calendar?days=soon", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "VALIDATION_INVALID_FORMAT")
+}
+
+func TestCalendarFeedHandler_Token(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/calendar.ics", CalendarFeedHandler(&mockCalendarService{token: "s3cret"}))
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+	}{
+		{"missing token", "/calendar.ics", http.StatusUnauthorized},
+		{"wrong token", "/calendar.ics?token=nope", http.StatusUnauthorized},
+		{"valid token", "/calendar.ics?token=s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, services.ICSContentType, w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Body.String(), "UID:vido-tv-209867-s02e01@vido")
+			}
+		})
+	}
+}
+
+func TestCalendarHandler_Feed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockCalendarService{}
+	router := gin.New()
+	NewCalendarHandler(svc).RegisterRoutes(router.Group("/api/v1"))
+	router.GET("/calendar.ics", CalendarFeedHandler(svc))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/calendar/feed", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"path":"/calendar.ics?token=generated"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "no token, no feed — even before any account exists")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/calendar/feed/rotate", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"rotated"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar.ics?token=generated", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "the old subscription is cut off")
+
+	svc.pinned = true
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/calendar/feed/rotate", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "CALENDAR_TOKEN_PINNED")
+}
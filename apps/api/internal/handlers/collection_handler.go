@@ -0,0 +1,143 @@
+// Package handlers — CollectionHandler.
+//
+// Movie collections (franchises) with owned/missing parts, a one-click
+// request for the missing ones, and an admin backfill for movies enriched
+// before collections were recorded.
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/services"
+)
+
+// errCodeCollectionSyncRunning is a code-list addition under the registered
+// LIBRARY_ prefix.
+const errCodeCollectionSyncRunning = "LIBRARY_COLLECTION_SYNC_RUNNING"
+
+// CollectionHandler handles HTTP requests for movie collections.
+type CollectionHandler struct {
+	service services.CollectionServiceInterface
+}
+
+// NewCollectionHandler creates a new CollectionHandler.
+func NewCollectionHandler(service services.CollectionServiceInterface) *CollectionHandler {
+	return &CollectionHandler{service: service}
+}
+
+// RegisterRoutes registers the collection routes.
+func (h *CollectionHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	group := rg.Group("/collections")
+	{
+		group.GET("", h.ListCollections)
+		group.POST("/sync", h.StartSync)
+		group.GET("/:id", h.GetCollection)
+		group.POST("/:id/request-missing", h.RequestMissing)
+	}
+}
+
+// ListCollections handles GET /api/v1/collections
+// @Summary List movie collections the library owns part of, with owned/missing parts
+// @Tags collections
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]services.CollectionSummary}
+// @Router /api/v1/collections [get]
+func (h *CollectionHandler) ListCollections(c *gin.Context) {
+	collections, err := h.service.ListCollections(c.Request.Context())
+	if err != nil {
+		handleCollectionError(c, "Failed to list collections", err)
+		return
+	}
+	SuccessResponse(c, collections)
+}
+
+// GetCollection handles GET /api/v1/collections/:id
+// @Summary Get one movie collection with owned/missing parts
+// @Tags collections
+// @Produce json
+// @Param id path int true "TMDb collection id"
+// @Success 200 {object} APIResponse{data=services.CollectionSummary}
+// @Failure 400 {object} APIResponse "VALIDATION_INVALID_FORMAT"
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/collections/{id} [get]
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	id, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+	collection, err := h.service.GetCollection(c.Request.Context(), id)
+	if err != nil {
+		handleCollectionError(c, "Failed to load collection", err)
+		return
+	}
+	SuccessResponse(c, collection)
+}
+
+// RequestMissing handles POST /api/v1/collections/:id/request-missing
+// @Summary Request every released part of a collection the library does not own
+// @Description Each part goes through the regular request create path; parts already requested, unreleased or failing are reported under skipped.
+// @Tags collections
+// @Produce json
+// @Param id path int true "TMDb collection id"
+// @Success 200 {object} APIResponse{data=services.CollectionRequestResult}
+// @Failure 400 {object} APIResponse "VALIDATION_INVALID_FORMAT"
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/collections/{id}/request-missing [post]
+func (h *CollectionHandler) RequestMissing(c *gin.Context) {
+	id, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+	result, err := h.service.RequestMissing(c.Request.Context(), id)
+	if err != nil {
+		handleCollectionError(c, "Failed to request missing collection parts", err)
+		return
+	}
+	SuccessResponse(c, result)
+}
+
+// StartSync handles POST /api/v1/collections/sync
+// @Summary Backfill collections for every enriched movie (admin)
+// @Description Returns immediately; the sweep runs in the background.
+// @Tags collections
+// @Produce json
+// @Success 202 {object} APIResponse "{started:true}"
+// @Failure 409 {object} APIResponse "LIBRARY_COLLECTION_SYNC_RUNNING"
+// @Router /api/v1/collections/sync [post]
+func (h *CollectionHandler) StartSync(c *gin.Context) {
+	if err := h.service.StartSync(); err != nil {
+		if errors.Is(err, services.ErrCollectionSyncRunning) {
+			ErrorResponse(c, http.StatusConflict, errCodeCollectionSyncRunning,
+				"系列同步正在進行中。", "等待目前的同步結束後再試。")
+			return
+		}
+		handleCollectionError(c, "Failed to start collection sync", err)
+		return
+	}
+	c.JSON(http.StatusAccepted, APIResponse{Success: true, Data: gin.H{"started": true}})
+}
+
+func collectionIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		ErrorResponse(c, http.StatusBadRequest, "VALIDATION_INVALID_FORMAT",
+			"id 必須是正整數", "請確認網址中的系列 id。")
+		return 0, false
+	}
+	return id, true
+}
+
+func handleCollectionError(c *gin.Context, message string, err error) {
+	if errors.Is(err, repository.ErrCollectionNotFound) {
+		NotFoundError(c, "collection")
+		return
+	}
+	slog.Error(message, "error", err)
+	InternalServerError(c, message)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/services"
+)
+
+// mockCollectionService implements services.CollectionServiceInterface via
+// swappable funcs.
+type mockCollectionService struct {
+	list           func(ctx context.Context) ([]services.CollectionSummary, error)
+	get            func(ctx context.Context, id int64) (*services.CollectionSummary, error)
+	requestMissing func(ctx context.Context, id int64) (*services.CollectionRequestResult, error)
+	startSync      func() error
+}
+
+func (m *mockCollectionService) ListCollections(ctx context.Context) ([]services.CollectionSummary, error) {
+	return m.list(ctx)
+}
+func (m *mockCollectionService) GetCollection(ctx context.Context, id int64) (*services.CollectionSummary, error) {
+	return m.get(ctx, id)
+}
+func (m *mockCollectionService) RequestMissing(ctx context.Context, id int64) (*services.CollectionRequestResult, error) {
+	return m.requestMissing(ctx, id)
+}
+func (m *mockCollectionService) StartSync() error { return m.startSync() }
+
+var _ services.CollectionServiceInterface = (*mockCollectionService)(nil)
+
+func setupCollectionRouter(svc services.CollectionServiceInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewCollectionHandler(svc).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestCollectionHandler_GetCollection(t *testing.T) {
+	svc := &mockCollectionService{
+		get: func(ctx context.Context, id int64) (*services.CollectionSummary, error) {
+			if id != 1241 {
+				return nil, repository.ErrCollectionNotFound
+			}
+			return &services.CollectionSummary{ID: 1241, Name: "哈利波特（系列）", OwnedCount: 8, TotalCount: 8}, nil
+		},
+	}
+	router := setupCollectionRouter(svc)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"found", "/api/v1/collections/1241", http.StatusOK, `"owned_count":8`},
+		{"unknown", "/api/v1/collections/7", http.StatusNotFound, "DB_NOT_FOUND"},
+		{"bad id", "/api/v1/collections/abc", http.StatusBadRequest, "VALIDATION_INVALID_FORMAT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestCollectionHandler_RequestMissing(t *testing.T) {
+	svc := &mockCollectionService{
+		requestMissing: func(ctx context.Context, id int64) (*services.CollectionRequestResult, error) {
+			return &services.CollectionRequestResult{
+				Requested: []models.Request{{TMDbID: 672, MediaType: "movie"}},
+				Skipped:   []services.CollectionRequestSkip{{TMDbID: 9999, Reason: services.CollectionSkipUnreleased}},
+			}, nil
+		},
+	}
+	w := httptest.NewRecorder()
+	setupCollectionRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/collections/1241/request-missing", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data services.CollectionRequestResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Requested, 1)
+	assert.Equal(t, "unreleased", resp.Data.Skipped[0].Reason)
+}
+
+func TestCollectionHandler_StartSync(t *testing.T) {
+	running := false
+	svc := &mockCollectionService{
+		startSync: func() error {
+			if running {
+				return services.ErrCollectionSyncRunning
+			}
+			running = true
+			return nil
+		},
+	}
+	router := setupCollectionRouter(svc)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/collections/sync", nil))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/collections/sync", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), errCodeCollectionSyncRunning)
+}
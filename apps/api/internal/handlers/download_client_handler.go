@@ -0,0 +1,179 @@
+// Package handlers — DownloadClientHandler.
+//
+// Picks which torrent client the downloads page and the built-in
+// fulfilment path talk to, and stores each client's connection. Everything
+// lives under /settings/download-clients, so the routes are admin-only
+// through AdminRoutes. /settings/qbittorrent stays for the setup wizard.
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/qbittorrent"
+	"github.com/vido/api/internal/services"
+)
+
+// DownloadClientHandler handles HTTP requests for download client settings.
+type DownloadClientHandler struct {
+	service services.DownloadClientServiceInterface
+}
+
+// NewDownloadClientHandler creates a new DownloadClientHandler.
+func NewDownloadClientHandler(service services.DownloadClientServiceInterface) *DownloadClientHandler {
+	return &DownloadClientHandler{service: service}
+}
+
+// DownloadClientsResponse is the GET /settings/download-clients body.
+type DownloadClientsResponse struct {
+	Active  string                        `json:"active"`
+	Clients []services.DownloadClientInfo `json:"clients"`
+}
+
+// SetActiveDownloadClientRequest is the PUT /settings/download-clients/active body.
+type SetActiveDownloadClientRequest struct {
+	Type string `json:"type" binding:"required"`
+}
+
+// RegisterRoutes registers the download client settings routes.
+func (h *DownloadClientHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	group := rg.Group("/settings/download-clients")
+	{
+		group.GET("", h.ListClients)
+		group.PUT("/active", h.SetActive)
+		group.GET("/:type", h.GetSettings)
+		group.PUT("/:type", h.SaveSettings)
+		group.POST("/:type/test", h.TestClient)
+	}
+}
+
+// ListClients handles GET /api/v1/settings/download-clients
+// @Summary List download clients and the active one
+// @Tags settings
+// @Produce json
+// @Success 200 {object} APIResponse{data=DownloadClientsResponse}
+// @Router /api/v1/settings/download-clients [get]
+func (h *DownloadClientHandler) ListClients(c *gin.Context) {
+	clients, err := h.service.ListClients(c.Request.Context())
+	if err != nil {
+		handleDownloadClientError(c, "Failed to list download clients", err)
+		return
+	}
+	SuccessResponse(c, DownloadClientsResponse{
+		Active:  h.service.GetActiveType(c.Request.Context()),
+		Clients: clients,
+	})
+}
+
+// SetActive handles PUT /api/v1/settings/download-clients/active
+// @Summary Select the active download client
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR | VALIDATION_FAILED"
+// @Router /api/v1/settings/download-clients/active [put]
+func (h *DownloadClientHandler) SetActive(c *gin.Context) {
+	var req SetActiveDownloadClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+	if err := h.service.SetActiveType(c.Request.Context(), req.Type); err != nil {
+		handleDownloadClientError(c, "Failed to select download client", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"active": req.Type})
+}
+
+// GetSettings handles GET /api/v1/settings/download-clients/:type
+// @Summary Get one download client's connection
+// @Description The password is never returned; has_password says whether one is stored.
+// @Tags settings
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.DownloadClientSettings}
+// @Failure 400 {object} APIResponse "VALIDATION_FAILED"
+// @Router /api/v1/settings/download-clients/{type} [get]
+func (h *DownloadClientHandler) GetSettings(c *gin.Context) {
+	settings, err := h.service.GetClientSettings(c.Request.Context(), c.Param("type"))
+	if err != nil {
+		handleDownloadClientError(c, "Failed to load download client settings", err)
+		return
+	}
+	SuccessResponse(c, settings)
+}
+
+// SaveSettings handles PUT /api/v1/settings/download-clients/:type
+// @Summary Save one download client's connection
+// @Description An empty password keeps the stored one.
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR | VALIDATION_FAILED"
+// @Router /api/v1/settings/download-clients/{type} [put]
+func (h *DownloadClientHandler) SaveSettings(c *gin.Context) {
+	var req services.DownloadClientSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+	if err := h.service.SaveClientSettings(c.Request.Context(), c.Param("type"), req); err != nil {
+		handleDownloadClientError(c, "Failed to save download client settings", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Configuration saved"})
+}
+
+// TestClient handles POST /api/v1/settings/download-clients/:type/test
+// @Summary Test a download client connection
+// @Description With a body the given settings are tested without saving; an empty
+// @Description password uses the stored one. Without a body the stored settings are tested.
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse{data=qbittorrent.VersionInfo}
+// @Failure 400 {object} APIResponse
+// @Router /api/v1/settings/download-clients/{type}/test [post]
+func (h *DownloadClientHandler) TestClient(c *gin.Context) {
+	var settings *services.DownloadClientSettings
+	var req services.DownloadClientSettings
+	if c.ShouldBindJSON(&req) == nil && req.Host != "" {
+		settings = &req
+	}
+
+	info, err := h.service.TestClient(c.Request.Context(), c.Param("type"), settings)
+	if err != nil {
+		var validationErr *models.ValidationError
+		if errors.As(err, &validationErr) {
+			BadRequestError(c, "VALIDATION_FAILED", err.Error())
+			return
+		}
+		slog.Error("Download client connection test failed", "type", c.Param("type"), "error", err)
+
+		// Same shape as /settings/qbittorrent/test: a failed test is a 400
+		// carrying the client's error code.
+		code := qbittorrent.ErrCodeConnectionFailed
+		var connErr *qbittorrent.ConnectionError
+		if errors.As(err, &connErr) {
+			code = connErr.Code
+		}
+		ErrorResponse(c, http.StatusBadRequest, code, "無法連線到下載用戶端", err.Error())
+		return
+	}
+	SuccessResponse(c, info)
+}
+
+func handleDownloadClientError(c *gin.Context, message string, err error) {
+	var validationErr *models.ValidationError
+	if errors.As(err, &validationErr) {
+		BadRequestError(c, "VALIDATION_FAILED", err.Error())
+		return
+	}
+	slog.Error(message, "error", err)
+	InternalServerError(c, message)
+}
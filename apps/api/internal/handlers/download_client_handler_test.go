@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/qbittorrent"
+	"github.com/vido/api/internal/services"
+)
+
+// fakeDownloadClients implements services.DownloadClientServiceInterface.
+type fakeDownloadClients struct {
+	active string
+	saved  services.DownloadClientSettings
+	tested *services.DownloadClientSettings
+}
+
+func (f *fakeDownloadClients) ListClients(ctx context.Context) ([]services.DownloadClientInfo, error) {
+	return []services.DownloadClientInfo{{Type: "qbittorrent", Active: f.active == "qbittorrent"}}, nil
+}
+func (f *fakeDownloadClients) GetActiveType(ctx context.Context) string { return f.active }
+func (f *fakeDownloadClients) SetActiveType(ctx context.Context, clientType string) error {
+	if clientType == "rtorrent" {
+		return &models.ValidationError{Field: "type", Message: "type must be one of qbittorrent, transmission, deluge"}
+	}
+	f.active = clientType
+	return nil
+}
+func (f *fakeDownloadClients) GetClientSettings(ctx context.Context, clientType string) (*services.DownloadClientSettings, error) {
+	return &services.DownloadClientSettings{Host: f.saved.Host, HasPassword: f.saved.Password != ""}, nil
+}
+func (f *fakeDownloadClients) SaveClientSettings(ctx context.Context, clientType string, settings services.DownloadClientSettings) error {
+	f.saved = settings
+	return nil
+}
+func (f *fakeDownloadClients) TestClient(ctx context.Context, clientType string, settings *services.DownloadClientSettings) (*qbittorrent.VersionInfo, error) {
+	f.tested = settings
+	if settings == nil {
+		return nil, &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeNotConfigured, Message: "Deluge not configured"}
+	}
+	return &qbittorrent.VersionInfo{AppVersion: "2.1.1"}, nil
+}
+
+var _ services.DownloadClientServiceInterface = (*fakeDownloadClients)(nil)
+
+func setupDownloadClientRouter(svc *fakeDownloadClients) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewDownloadClientHandler(svc).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestDownloadClientHandler_SelectAndSave(t *testing.T) {
+	svc := &fakeDownloadClients{active: "qbittorrent"}
+	router := setupDownloadClientRouter(svc)
+
+	w := sendJSON(router, http.MethodPut, "/api/v1/settings/download-clients/active", `{"type":"rtorrent"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "VALIDATION_FAILED")
+
+	w = sendJSON(router, http.MethodPut, "/api/v1/settings/download-clients/active", `{"type":"deluge"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "deluge", svc.active)
+
+	w = sendJSON(router, http.MethodPut, "/api/v1/settings/download-clients/deluge", `{"host":"http://nas:8112","password":"pw"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = sendJSON(router, http.MethodGet, "/api/v1/settings/download-clients/deluge", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"has_password":true`)
+	assert.NotContains(t, w.Body.String(), `"pw"`)
+
+	w = sendJSON(router, http.MethodGet, "/api/v1/settings/download-clients", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"active":"deluge"`)
+}
+
+func TestDownloadClientHandler_TestClient(t *testing.T) {
+	svc := &fakeDownloadClients{}
+	router := setupDownloadClientRouter(svc)
+
+	w := sendJSON(router, http.MethodPost, "/api/v1/settings/download-clients/deluge/test", `{"host":"http://nas:8112"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, svc.tested)
+	assert.Equal(t, "http://nas:8112", svc.tested.Host)
+
+	w = sendJSON(router, http.MethodPost, "/api/v1/settings/download-clients/deluge/test", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), qbittorrent.ErrCodeNotConfigured)
+	assert.Nil(t, svc.tested, "no body tests the stored settings")
+}
@@ -0,0 +1,520 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/indexer"
+	"github.com/vido/api/internal/qbittorrent"
+	"github.com/vido/api/internal/services"
+)
+
+// maxTorrentUpload caps an uploaded .torrent file. Real ones are a few
+// hundred KB even for large season packs.
+const maxTorrentUpload = 10 << 20
+
+// DownloadControlHandler exposes adding torrents and organising them
+// (categories, tags, file priorities, speed limits), turning the downloads
+// page into a qBittorrent front-end. Errors share writeQBTActionError with
+// the pause/resume/remove actions.
+type DownloadControlHandler struct {
+	service services.DownloadControlServiceInterface
+}
+
+// NewDownloadControlHandler creates a new DownloadControlHandler.
+func NewDownloadControlHandler(service services.DownloadControlServiceInterface) *DownloadControlHandler {
+	return &DownloadControlHandler{service: service}
+}
+
+// RegisterRoutes registers the control routes alongside DownloadHandler's
+// under /downloads.
+func (h *DownloadControlHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	downloads := rg.Group("/downloads")
+	{
+		downloads.POST("", h.AddDownload)
+		downloads.GET("/categories", h.ListCategories)
+		downloads.POST("/categories", h.CreateCategory)
+		downloads.DELETE("/categories/:name", h.RemoveCategory)
+		downloads.GET("/tags", h.ListTags)
+		downloads.POST("/tags", h.CreateTags)
+		downloads.DELETE("/tags/:tag", h.DeleteTag)
+		downloads.GET("/speed-limits", h.GetSpeedLimits)
+		downloads.PUT("/speed-limits", h.SetSpeedLimits)
+		downloads.PUT("/:hash/category", h.SetCategory)
+		downloads.POST("/:hash/tags", h.AddTags)
+		downloads.DELETE("/:hash/tags/:tag", h.RemoveTag)
+		downloads.GET("/:hash/files", h.ListFiles)
+		downloads.PUT("/:hash/files/priority", h.SetFilePriority)
+		downloads.PUT("/:hash/speed-limits", h.SetDownloadSpeedLimits)
+	}
+}
+
+// addDownloadRequest is the JSON form of POST /downloads. The multipart form
+// carries the same fields plus a "file" upload; there urls is
+// newline-separated and tags comma-separated.
+type addDownloadRequest struct {
+	URLs     []string `json:"urls"`
+	SavePath string   `json:"save_path"`
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+	Paused   bool     `json:"paused"`
+}
+
+// addDownloadResponse lists the info-hashes of what was added, as far as
+// they can be known up front: a magnet's is in its URI and a file's is
+// computed from it, while an http link is only resolved by qBittorrent.
+type addDownloadResponse struct {
+	Hashes []string `json:"hashes"`
+}
+
+// AddDownload handles POST /api/v1/downloads
+// @Summary Add a download
+// @Description Adds magnets/links (JSON or multipart "urls") or an uploaded .torrent ("file")
+// @Description with an optional save path, category and tags.
+// @Tags downloads
+// @Accept json,mpfd
+// @Produce json
+// @Success 201 {object} APIResponse{data=addDownloadResponse}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR"
+// @Failure 403 {object} APIResponse "AUTH_FORBIDDEN — save_path from a non-admin"
+// @Failure 502 {object} APIResponse
+// @Failure 503 {object} APIResponse
+// @Router /api/v1/downloads [post]
+func (h *DownloadControlHandler) AddDownload(c *gin.Context) {
+	var opts qbittorrent.AddTorrentOptions
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxTorrentUpload+1<<20)
+		if err := c.Request.ParseMultipartForm(maxTorrentUpload); err != nil {
+			ValidationError(c, "invalid upload: "+err.Error())
+			return
+		}
+		opts = qbittorrent.AddTorrentOptions{
+			URLs:     splitList(c.PostForm("urls"), "\n"),
+			SavePath: c.PostForm("save_path"),
+			Category: c.PostForm("category"),
+			Tags:     splitList(c.PostForm("tags"), ","),
+		}
+		opts.Paused, _ = strconv.ParseBool(c.PostForm("paused"))
+		if file, header, err := c.Request.FormFile("file"); err == nil {
+			data, err := io.ReadAll(io.LimitReader(file, maxTorrentUpload+1))
+			file.Close()
+			if err != nil || len(data) > maxTorrentUpload {
+				ValidationError(c, "torrent file is unreadable or too large")
+				return
+			}
+			opts.Torrent, opts.FileName = data, header.Filename
+		}
+	} else {
+		var req addDownloadRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ValidationError(c, "invalid request body: "+err.Error())
+			return
+		}
+		opts = qbittorrent.AddTorrentOptions{
+			URLs:     req.URLs,
+			SavePath: req.SavePath,
+			Category: req.Category,
+			Tags:     req.Tags,
+			Paused:   req.Paused,
+		}
+	}
+
+	if len(opts.URLs) == 0 && len(opts.Torrent) == 0 {
+		ValidationError(c, "a magnet link, URL or torrent file is required")
+		return
+	}
+	// A save path is written wherever qBittorrent's user can write, so only
+	// an admin picks one; everyone else lands in the default folder or a
+	// category's. Adding itself stays open to every account.
+	if user := CurrentUser(c); opts.SavePath != "" && user != nil && !user.IsAdmin() {
+		ErrorResponse(c, http.StatusForbidden, "AUTH_FORBIDDEN",
+			"Choosing a save path requires an admin account",
+			"Leave the save path empty or pick a category.")
+		return
+	}
+	resp := addDownloadResponse{Hashes: []string{}}
+	for _, u := range opts.URLs {
+		switch {
+		case strings.HasPrefix(u, "magnet:"):
+			if hash := indexer.MagnetInfoHash(u); hash != "" {
+				resp.Hashes = append(resp.Hashes, hash)
+			}
+		case strings.HasPrefix(u, "http://"), strings.HasPrefix(u, "https://"):
+		default:
+			ValidationError(c, "urls must be magnet, http or https links")
+			return
+		}
+	}
+	if len(opts.Torrent) > 0 {
+		hash, err := indexer.InfoHash(opts.Torrent)
+		if err != nil {
+			ValidationError(c, "file is not a valid .torrent")
+			return
+		}
+		resp.Hashes = append(resp.Hashes, hash)
+	}
+	if !validTags(opts.Tags) {
+		ValidationError(c, "tags must be non-empty and must not contain commas")
+		return
+	}
+
+	if err := h.service.AddTorrent(c.Request.Context(), opts); err != nil {
+		writeQBTActionError(c, err, "add download")
+		return
+	}
+	CreatedResponse(c, resp)
+}
+
+// ListCategories handles GET /api/v1/downloads/categories
+// @Summary List download categories
+// @Tags downloads
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]qbittorrent.Category}
+// @Router /api/v1/downloads/categories [get]
+func (h *DownloadControlHandler) ListCategories(c *gin.Context) {
+	categories, err := h.service.ListCategories(c.Request.Context())
+	if err != nil {
+		writeQBTActionError(c, err, "list categories")
+		return
+	}
+	if categories == nil {
+		categories = []qbittorrent.Category{}
+	}
+	SuccessResponse(c, categories)
+}
+
+// CreateCategory handles POST /api/v1/downloads/categories
+// @Summary Create a download category
+// @Tags downloads
+// @Accept json
+// @Produce json
+// @Param body body qbittorrent.Category true "Name and optional save path"
+// @Success 201 {object} APIResponse{data=qbittorrent.Category}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR"
+// @Failure 409 {object} APIResponse "QBITTORRENT_REQUEST_REJECTED — name taken or invalid"
+// @Router /api/v1/downloads/categories [post]
+func (h *DownloadControlHandler) CreateCategory(c *gin.Context) {
+	var req qbittorrent.Category
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "invalid request body: "+err.Error())
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		ValidationError(c, "category name is required")
+		return
+	}
+	if err := h.service.CreateCategory(c.Request.Context(), req.Name, req.SavePath); err != nil {
+		writeQBTActionError(c, err, "create category")
+		return
+	}
+	CreatedResponse(c, req)
+}
+
+// RemoveCategory handles DELETE /api/v1/downloads/categories/:name
+// @Summary Delete a download category
+// @Description Its torrents are kept and become uncategorised.
+// @Tags downloads
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Router /api/v1/downloads/categories/{name} [delete]
+func (h *DownloadControlHandler) RemoveCategory(c *gin.Context) {
+	if err := h.service.RemoveCategory(c.Request.Context(), c.Param("name")); err != nil {
+		writeQBTActionError(c, err, "remove category")
+		return
+	}
+	SuccessResponse(c, nil)
+}
+
+// setCategoryRequest is the body of PUT /downloads/:hash/category.
+type setCategoryRequest struct {
+	Category string `json:"category"`
+}
+
+// SetCategory handles PUT /api/v1/downloads/:hash/category
+// @Summary Set a download's category
+// @Description An empty category clears it. The category must exist.
+// @Tags downloads
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 409 {object} APIResponse "QBITTORRENT_REQUEST_REJECTED — unknown category"
+// @Router /api/v1/downloads/{hash}/category [put]
+func (h *DownloadControlHandler) SetCategory(c *gin.Context) {
+	var req setCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "invalid request body: "+err.Error())
+		return
+	}
+	if err := h.service.SetDownloadCategory(c.Request.Context(), c.Param("hash"), req.Category); err != nil {
+		writeQBTActionError(c, err, "set download category")
+		return
+	}
+	SuccessResponse(c, nil)
+}
+
+// tagsRequest is the body of the tag-creating endpoints.
+type tagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// bindTags reads a tagsRequest and rejects an empty list or a tag
+// qBittorrent would split apart.
+func bindTags(c *gin.Context) ([]string, bool) {
+	var req tagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "invalid request body: "+err.Error())
+		return nil, false
+	}
+	if len(req.Tags) == 0 || !validTags(req.Tags) {
+		ValidationError(c, "tags must be a non-empty list of tags without commas")
+		return nil, false
+	}
+	return req.Tags, true
+}
+
+// ListTags handles GET /api/v1/downloads/tags
+// @Summary List download tags
+// @Tags downloads
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]string}
+// @Router /api/v1/downloads/tags [get]
+func (h *DownloadControlHandler) ListTags(c *gin.Context) {
+	tags, err := h.service.ListTags(c.Request.Context())
+	if err != nil {
+		writeQBTActionError(c, err, "list tags")
+		return
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	SuccessResponse(c, tags)
+}
+
+// CreateTags handles POST /api/v1/downloads/tags
+// @Summary Create download tags
+// @Tags downloads
+// @Accept json
+// @Produce json
+// @Success 201 {object} APIResponse
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR"
+// @Router /api/v1/downloads/tags [post]
+func (h *DownloadControlHandler) CreateTags(c *gin.Context) {
+	tags, ok := bindTags(c)
+	if !ok {
+		return
+	}
+	if err := h.service.CreateTags(c.Request.Context(), tags); err != nil {
+		writeQBTActionError(c, err, "create tags")
+		return
+	}
+	CreatedResponse(c, tagsRequest{Tags: tags})
+}
+
+// DeleteTag handles DELETE /api/v1/downloads/tags/:tag
+// @Summary Delete a download tag
+// @Description Removes the tag from qBittorrent and every torrent carrying it.
+// @Tags downloads
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Router /api/v1/downloads/tags/{tag} [delete]
+func (h *DownloadControlHandler) DeleteTag(c *gin.Context) {
+	if err := h.service.DeleteTag(c.Request.Context(), c.Param("tag")); err != nil {
+		writeQBTActionError(c, err, "delete tag")
+		return
+	}
+	SuccessResponse(c, nil)
+}
+
+// AddTags handles POST /api/v1/downloads/:hash/tags
+// @Summary Tag a download
+// @Tags downloads
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR"
+// @Router /api/v1/downloads/{hash}/tags [post]
+func (h *DownloadControlHandler) AddTags(c *gin.Context) {
+	tags, ok := bindTags(c)
+	if !ok {
+		return
+	}
+	if err := h.service.AddDownloadTags(c.Request.Context(), c.Param("hash"), tags); err != nil {
+		writeQBTActionError(c, err, "tag download")
+		return
+	}
+	SuccessResponse(c, nil)
+}
+
+// RemoveTag handles DELETE /api/v1/downloads/:hash/tags/:tag
+// @Summary Untag a download
+// @Tags downloads
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Router /api/v1/downloads/{hash}/tags/{tag} [delete]
+func (h *DownloadControlHandler) RemoveTag(c *gin.Context) {
+	if err := h.service.RemoveDownloadTags(c.Request.Context(), c.Param("hash"), []string{c.Param("tag")}); err != nil {
+		writeQBTActionError(c, err, "untag download")
+		return
+	}
+	SuccessResponse(c, nil)
+}
+
+// ListFiles handles GET /api/v1/downloads/:hash/files
+// @Summary List a download's files
+// @Description Empty while a magnet's metadata is still being fetched.
+// @Tags downloads
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]qbittorrent.TorrentFile}
+// @Failure 404 {object} APIResponse "QBITTORRENT_TORRENT_NOT_FOUND"
+// @Router /api/v1/downloads/{hash}/files [get]
+func (h *DownloadControlHandler) ListFiles(c *gin.Context) {
+	files, err := h.service.GetDownloadFiles(c.Request.Context(), c.Param("hash"))
+	if err != nil {
+		writeQBTActionError(c, err, "list download files")
+		return
+	}
+	if files == nil {
+		files = []qbittorrent.TorrentFile{}
+	}
+	SuccessResponse(c, files)
+}
+
+// filePriorityRequest is the body of PUT /downloads/:hash/files/priority.
+// Priority is 0 (skip), 1 (normal), 6 (high) or 7 (maximum).
+type filePriorityRequest struct {
+	FileIDs  []int `json:"file_ids"`
+	Priority *int  `json:"priority"`
+}
+
+// SetFilePriority handles PUT /api/v1/downloads/:hash/files/priority
+// @Summary Set file priorities
+// @Description Priority 0 skips the files; 1, 6 and 7 are normal, high and maximum.
+// @Tags downloads
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR"
+// @Failure 404 {object} APIResponse "QBITTORRENT_TORRENT_NOT_FOUND"
+// @Failure 409 {object} APIResponse "QBITTORRENT_REQUEST_REJECTED — metadata not downloaded yet or unknown file id"
+// @Router /api/v1/downloads/{hash}/files/priority [put]
+func (h *DownloadControlHandler) SetFilePriority(c *gin.Context) {
+	var req filePriorityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "invalid request body: "+err.Error())
+		return
+	}
+	if len(req.FileIDs) == 0 {
+		ValidationError(c, "file_ids is required")
+		return
+	}
+	if req.Priority == nil || !qbittorrent.ValidFilePriority(*req.Priority) {
+		ValidationError(c, "priority must be 0, 1, 6 or 7")
+		return
+	}
+	if err := h.service.SetFilePriority(c.Request.Context(), c.Param("hash"), req.FileIDs, *req.Priority); err != nil {
+		writeQBTActionError(c, err, "set file priority")
+		return
+	}
+	SuccessResponse(c, nil)
+}
+
+// GetSpeedLimits handles GET /api/v1/downloads/speed-limits
+// @Summary Get global speed limits
+// @Tags downloads
+// @Produce json
+// @Success 200 {object} APIResponse{data=qbittorrent.SpeedLimits}
+// @Router /api/v1/downloads/speed-limits [get]
+func (h *DownloadControlHandler) GetSpeedLimits(c *gin.Context) {
+	limits, err := h.service.GetSpeedLimits(c.Request.Context())
+	if err != nil {
+		writeQBTActionError(c, err, "get speed limits")
+		return
+	}
+	SuccessResponse(c, limits)
+}
+
+// SetSpeedLimits handles PUT /api/v1/downloads/speed-limits
+// @Summary Set global speed limits
+// @Description Limits are bytes per second, 0 for unlimited. alternative_mode selects the
+// @Description alternative limit set, which the limits are then written to.
+// @Tags downloads
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse{data=qbittorrent.SpeedLimits}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR"
+// @Router /api/v1/downloads/speed-limits [put]
+func (h *DownloadControlHandler) SetSpeedLimits(c *gin.Context) {
+	var req qbittorrent.SpeedLimits
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "invalid request body: "+err.Error())
+		return
+	}
+	if req.DownloadLimit < 0 || req.UploadLimit < 0 {
+		ValidationError(c, "speed limits must not be negative")
+		return
+	}
+	if err := h.service.SetSpeedLimits(c.Request.Context(), req); err != nil {
+		writeQBTActionError(c, err, "set speed limits")
+		return
+	}
+	SuccessResponse(c, req)
+}
+
+// downloadSpeedLimitsRequest is the body of PUT /downloads/:hash/speed-limits.
+type downloadSpeedLimitsRequest struct {
+	DownloadLimit int64 `json:"download_limit"`
+	UploadLimit   int64 `json:"upload_limit"`
+}
+
+// SetDownloadSpeedLimits handles PUT /api/v1/downloads/:hash/speed-limits
+// @Summary Set a download's speed limits
+// @Description Bytes per second, 0 for no per-torrent cap.
+// @Tags downloads
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR"
+// @Router /api/v1/downloads/{hash}/speed-limits [put]
+func (h *DownloadControlHandler) SetDownloadSpeedLimits(c *gin.Context) {
+	var req downloadSpeedLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "invalid request body: "+err.Error())
+		return
+	}
+	if req.DownloadLimit < 0 || req.UploadLimit < 0 {
+		ValidationError(c, "speed limits must not be negative")
+		return
+	}
+	if err := h.service.SetDownloadSpeedLimits(c.Request.Context(), c.Param("hash"), req.DownloadLimit, req.UploadLimit); err != nil {
+		writeQBTActionError(c, err, "set download speed limits")
+		return
+	}
+	SuccessResponse(c, nil)
+}
+
+// splitList splits a multipart list field, dropping blanks.
+func splitList(value, sep string) []string {
+	var out []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// validTags rejects tags qBittorrent cannot store: it joins tag lists with
+// commas, so a comma would split one tag into two.
+func validTags(tags []string) bool {
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" || strings.Contains(tag, ",") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/qbittorrent"
+	"github.com/vido/api/internal/services"
+)
+
+// fakeDownloadControl records the last call; err, when set, is returned by
+// every method.
+type fakeDownloadControl struct {
+	added    qbittorrent.AddTorrentOptions
+	hash     string
+	tags     []string
+	category string
+	indexes  []int
+	priority int
+	limits   qbittorrent.SpeedLimits
+	err      error
+}
+
+func (f *fakeDownloadControl) AddTorrent(ctx context.Context, opts qbittorrent.AddTorrentOptions) error {
+	f.added = opts
+	return f.err
+}
+func (f *fakeDownloadControl) ListCategories(ctx context.Context) ([]qbittorrent.Category, error) {
+	return nil, f.err
+}
+func (f *fakeDownloadControl) CreateCategory(ctx context.Context, name, savePath string) error {
+	f.category = name
+	return f.err
+}
+func (f *fakeDownloadControl) RemoveCategory(ctx context.Context, name string) error {
+	f.category = name
+	return f.err
+}
+func (f *fakeDownloadControl) SetDownloadCategory(ctx context.Context, hash, category string) error {
+	f.hash, f.category = hash, category
+	return f.err
+}
+func (f *fakeDownloadControl) ListTags(ctx context.Context) ([]string, error) { return nil, f.err }
+func (f *fakeDownloadControl) CreateTags(ctx context.Context, tags []string) error {
+	f.tags = tags
+	return f.err
+}
+func (f *fakeDownloadControl) DeleteTag(ctx context.Context, tag string) error {
+	f.tags = []string{tag}
+	return f.err
+}
+func (f *fakeDownloadControl) AddDownloadTags(ctx context.Context, hash string, tags []string) error {
+	f.hash, f.tags = hash, tags
+	return f.err
+}
+func (f *fakeDownloadControl) RemoveDownloadTags(ctx context.Context, hash string, tags []string) error {
+	f.hash, f.tags = hash, tags
+	return f.err
+}
+func (f *fakeDownloadControl) GetDownloadFiles(ctx context.Context, hash string) ([]qbittorrent.TorrentFile, error) {
+	f.hash = hash
+	return nil, f.err
+}
+func (f *fakeDownloadControl) SetFilePriority(ctx context.Context, hash string, indexes []int, priority int) error {
+	f.hash, f.indexes, f.priority = hash, indexes, priority
+	return f.err
+}
+func (f *fakeDownloadControl) GetSpeedLimits(ctx context.Context) (*qbittorrent.SpeedLimits, error) {
+	return &f.limits, f.err
+}
+func (f *fakeDownloadControl) SetSpeedLimits(ctx context.Context, limits qbittorrent.SpeedLimits) error {
+	f.limits = limits
+	return f.err
+}
+func (f *fakeDownloadControl) SetDownloadSpeedLimits(ctx context.Context, hash string, downloadLimit, uploadLimit int64) error {
+	f.hash = hash
+	f.limits = qbittorrent.SpeedLimits{DownloadLimit: downloadLimit, UploadLimit: uploadLimit}
+	return f.err
+}
+
+var _ services.DownloadControlServiceInterface = (*fakeDownloadControl)(nil)
+
+// setupDownloadControlRouter mounts both download handlers, as main does, so
+// a route clash between them panics here.
+func setupDownloadControlRouter(svc *fakeDownloadControl) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	apiV1 := router.Group("/api/v1")
+	NewDownloadHandler(new(MockDownloadService)).RegisterRoutes(apiV1)
+	NewDownloadControlHandler(svc).RegisterRoutes(apiV1)
+	return router
+}
+
+func sendJSON(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDownloadControlHandler_AddDownload(t *testing.T) {
+	const magnet = "magnet:?xt=urn:btih:c12fe1c06bba254a9dc9f519b335aa7c1367a88a&dn=x"
+
+	t.Run("magnet via JSON returns its hash", func(t *testing.T) {
+		svc := &fakeDownloadControl{}
+		w := sendJSON(setupDownloadControlRouter(svc), http.MethodPost, "/api/v1/downloads",
+			`{"urls":["`+magnet+`"],"category":"tv","tags":["vido"],"save_path":"/data/tv"}`)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		assert.Equal(t, "tv", svc.added.Category)
+		assert.Equal(t, []string{"vido"}, svc.added.Tags)
+		assert.Equal(t, "/data/tv", svc.added.SavePath)
+		assert.Contains(t, w.Body.String(), `"hashes":["c12fe1c06bba254a9dc9f519b335aa7c1367a88a"]`)
+	})
+
+	t.Run("torrent file via multipart", func(t *testing.T) {
+		svc := &fakeDownloadControl{}
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, _ := mw.CreateFormFile("file", "a.torrent")
+		_, _ = part.Write([]byte("d4:infod4:name1:aee"))
+		_ = mw.WriteField("tags", "vido, 4k")
+		_ = mw.WriteField("paused", "true")
+		require.NoError(t, mw.Close())
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/downloads", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		setupDownloadControlRouter(svc).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		assert.Equal(t, "a.torrent", svc.added.FileName)
+		assert.Equal(t, []string{"vido", "4k"}, svc.added.Tags)
+		assert.True(t, svc.added.Paused)
+	})
+
+	for name, body := range map[string]string{
+		"nothing to add":   `{}`,
+		"unsupported url":  `{"urls":["ftp://x"]}`,
+		"tag with a comma": `{"urls":["` + magnet + `"],"tags":["a,b"]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc := &fakeDownloadControl{}
+			w := sendJSON(setupDownloadControlRouter(svc), http.MethodPost, "/api/v1/downloads", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Empty(t, svc.added.URLs)
+		})
+	}
+
+	t.Run("qBittorrent not configured", func(t *testing.T) {
+		svc := &fakeDownloadControl{err: &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeNotConfigured}}
+		w := sendJSON(setupDownloadControlRouter(svc), http.MethodPost, "/api/v1/downloads", `{"urls":["`+magnet+`"]}`)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+// TestDownloadControlHandler_NonAdminLimits — behind RequireAuth, as main
+// mounts it: any account may add a download, only an admin may say where it
+// goes or reshape a torrent already running.
+func TestDownloadControlHandler_NonAdminLimits(t *testing.T) {
+	const magnet = "magnet:?xt=urn:btih:c12fe1c06bba254a9dc9f519b335aa7c1367a88a&dn=x"
+	resolver := &fakeAuthResolver{required: true, users: map[string]*models.User{
+		"admin-token": {ID: "a", Username: "admin", Role: models.UserRoleAdmin},
+		"user-token":  {ID: "u", Username: "alice", Role: models.UserRoleUser},
+	}}
+	svc := &fakeDownloadControl{}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	apiV1 := router.Group("/api/v1", RequireAuth(resolver, AdminRoutes))
+	NewDownloadHandler(new(MockDownloadService)).RegisterRoutes(apiV1)
+	NewDownloadControlHandler(svc).RegisterRoutes(apiV1)
+
+	send := func(method, path, token, body string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/downloads", "user-token", `{"urls":["`+magnet+`"]}`))
+	assert.Equal(t, http.StatusForbidden,
+		send(http.MethodPost, "/api/v1/downloads", "user-token", `{"urls":["`+magnet+`"],"save_path":"/etc"}`))
+	assert.Equal(t, http.StatusCreated,
+		send(http.MethodPost, "/api/v1/downloads", "admin-token", `{"urls":["`+magnet+`"],"save_path":"/data/tv"}`))
+	assert.Equal(t, "/data/tv", svc.added.SavePath)
+
+	for _, path := range []string{
+		"/api/v1/downloads/abc/category",
+		"/api/v1/downloads/abc/files/priority",
+		"/api/v1/downloads/abc/speed-limits",
+	} {
+		assert.Equal(t, http.StatusForbidden, send(http.MethodPut, path, "user-token", `{}`), path)
+	}
+}
+
+func TestDownloadControlHandler_Organise(t *testing.T) {
+	svc := &fakeDownloadControl{}
+	router := setupDownloadControlRouter(svc)
+
+	w := sendJSON(router, http.MethodPut, "/api/v1/downloads/abc/category", `{"category":"tv"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abc", svc.hash)
+	assert.Equal(t, "tv", svc.category)
+
+	w = sendJSON(router, http.MethodPost, "/api/v1/downloads/abc/tags", `{"tags":["vido","4k"]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"vido", "4k"}, svc.tags)
+
+	w = sendJSON(router, http.MethodDelete, "/api/v1/downloads/abc/tags/4k", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"4k"}, svc.tags)
+
+	w = sendJSON(router, http.MethodPost, "/api/v1/downloads/categories", `{"name":" anime ","save_path":"/data/anime"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "anime", svc.category)
+
+	w = sendJSON(router, http.MethodPut, "/api/v1/downloads/speed-limits", `{"download_limit":1000,"upload_limit":-1}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = sendJSON(router, http.MethodPut, "/api/v1/downloads/speed-limits", `{"download_limit":1000,"alternative_mode":true}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, qbittorrent.SpeedLimits{DownloadLimit: 1000, AlternativeMode: true}, svc.limits)
+
+	w = sendJSON(router, http.MethodPut, "/api/v1/downloads/abc/speed-limits", `{"upload_limit":500}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(500), svc.limits.UploadLimit)
+}
+
+func TestDownloadControlHandler_FilePriority(t *testing.T) {
+	t.Run("sets the priority", func(t *testing.T) {
+		svc := &fakeDownloadControl{}
+		w := sendJSON(setupDownloadControlRouter(svc), http.MethodPut, "/api/v1/downloads/abc/files/priority", `{"file_ids":[0,2],"priority":0}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []int{0, 2}, svc.indexes)
+		assert.Equal(t, qbittorrent.FilePriorityDoNotDownload, svc.priority)
+	})
+
+	for name, body := range map[string]string{
+		"missing priority": `{"file_ids":[0]}`,
+		"invalid priority": `{"file_ids":[0],"priority":3}`,
+		"no files":         `{"file_ids":[],"priority":1}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc := &fakeDownloadControl{}
+			w := sendJSON(setupDownloadControlRouter(svc), http.MethodPut, "/api/v1/downloads/abc/files/priority", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Empty(t, svc.hash)
+		})
+	}
+
+	t.Run("qBittorrent errors keep their status", func(t *testing.T) {
+		for code, status := range map[string]int{
+			qbittorrent.ErrCodeTorrentNotFound: http.StatusNotFound,
+			qbittorrent.ErrCodeRequestRejected: http.StatusConflict,
+		} {
+			svc := &fakeDownloadControl{err: &qbittorrent.ConnectionError{Code: code, Message: "x"}}
+			w := sendJSON(setupDownloadControlRouter(svc), http.MethodPut, "/api/v1/downloads/abc/files/priority", `{"file_ids":[0],"priority":1}`)
+			require.Equal(t, status, w.Code, code)
+			var resp APIResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, code, resp.Error.Code)
+		}
+	})
+}
@@ -30,6 +30,10 @@ func qbtErrorToHTTPStatus(code string) int {
 		return http.StatusGatewayTimeout
 	case qbittorrent.ErrCodeAuthFailed, qbittorrent.ErrCodeConnectionFailed:
 		return http.StatusBadGateway
+	case qbittorrent.ErrCodeTorrentNotFound:
+		return http.StatusNotFound
+	case qbittorrent.ErrCodeRequestRejected:
+		return http.StatusConflict
 	default:
 		slog.Warn("unknown qBT error code mapped to 502", "code", code)
 		return http.StatusBadGateway
@@ -266,8 +270,15 @@ func (h *DownloadHandler) GetDownloadCounts(c *gin.Context) {
 // the correct HTTP response, reusing the qbtErrorToHTTPStatus contract shared by
 // the GET endpoints (bugfix-10-2 [@contract-v1]). No TorrentNotFound branch —
 // qBittorrent's pause/resume/delete are idempotent and 200 even for unknown
-// hashes, so there is no not-found surface for these actions.
+// hashes, so there is no not-found surface for these actions. The control
+// endpoints (files, categories) do have one; writeQBTActionError covers it.
 func (h *DownloadHandler) writeActionError(c *gin.Context, err error, action string) {
+	writeQBTActionError(c, err, action+" download")
+}
+
+// writeQBTActionError is writeActionError for any handler talking to
+// qBittorrent through DownloadService; action reads "Failed to <action>".
+func writeQBTActionError(c *gin.Context, err error, action string) {
 	slog.Error("download action failed", "action", action, "error", err)
 
 	var connErr *qbittorrent.ConnectionError
@@ -278,13 +289,21 @@ func (h *DownloadHandler) writeActionError(c *gin.Context, err error, action str
 			ErrorResponse(c, status, connErr.Code, "qBittorrent 尚未設定", "請先設定 qBittorrent 連線。"+SetupRequiredMarker)
 		case qbittorrent.ErrCodeAuthFailed:
 			ErrorResponse(c, status, connErr.Code, "qBittorrent 認證失敗", "請檢查帳號密碼是否正確。")
+		case qbittorrent.ErrCodeTorrentNotFound:
+			ErrorResponse(c, status, connErr.Code, "找不到此下載項目", "請確認該下載項目仍在 qBittorrent 中。")
+		case qbittorrent.ErrCodeRequestRejected:
+			ErrorResponse(c, status, connErr.Code, "qBittorrent 拒絕了此操作", connErr.Error())
 		default:
 			ErrorResponse(c, status, connErr.Code, "無法連線到 qBittorrent", connErr.Error())
 		}
 		return
 	}
+	if errors.Is(err, services.ErrDownloadClientUnsupported) {
+		ErrorResponse(c, http.StatusNotImplemented, "DOWNLOAD_CLIENT_UNSUPPORTED", "目前的下載用戶端不支援此操作", "分類、標籤、檔案優先順序與速度限制僅適用於 qBittorrent。")
+		return
+	}
 
-	InternalServerError(c, "Failed to "+action+" download")
+	InternalServerError(c, "Failed to "+action)
 }
 
 // PauseDownload handles POST /api/v1/downloads/:hash/pause
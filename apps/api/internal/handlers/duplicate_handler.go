@@ -0,0 +1,98 @@
+// Package handlers — DuplicateHandler.
+//
+// Review of titles owned more than once, ranked by quality, and the resolve
+// action that keeps one copy and deletes or trashes the rest.
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/services"
+)
+
+// errCodeDuplicateMismatch and errCodeDuplicateSameFile are code-list
+// additions under the registered LIBRARY_ prefix.
+const (
+	errCodeDuplicateMismatch = "LIBRARY_DUPLICATE_MISMATCH"
+	errCodeDuplicateSameFile = "LIBRARY_DUPLICATE_SAME_FILE"
+)
+
+// DuplicateHandler handles HTTP requests for duplicate review.
+type DuplicateHandler struct {
+	service services.DuplicateServiceInterface
+}
+
+// NewDuplicateHandler creates a new DuplicateHandler.
+func NewDuplicateHandler(service services.DuplicateServiceInterface) *DuplicateHandler {
+	return &DuplicateHandler{service: service}
+}
+
+// RegisterRoutes registers the duplicate routes.
+func (h *DuplicateHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	group := rg.Group("/library/duplicates")
+	{
+		group.GET("", h.ListDuplicates)
+		group.POST("/resolve", h.Resolve)
+	}
+}
+
+// ListDuplicates handles GET /api/v1/library/duplicates
+// @Summary Titles owned more than once, copies ranked best first
+// @Tags library
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.DuplicateReport}
+// @Router /api/v1/library/duplicates [get]
+func (h *DuplicateHandler) ListDuplicates(c *gin.Context) {
+	report, err := h.service.FindDuplicates(c.Request.Context())
+	if err != nil {
+		slog.Error("Failed to find duplicates", "error", err)
+		InternalServerError(c, "Failed to find duplicates")
+		return
+	}
+	SuccessResponse(c, report)
+}
+
+// Resolve handles POST /api/v1/library/duplicates/resolve
+// @Summary Keep one copy of a duplicated title and delete or trash the others (admin)
+// @Description Removes files from disk: the body must carry confirm=true. Every copy must belong to one current duplicate group.
+// @Tags library
+// @Accept json
+// @Produce json
+// @Param request body services.ResolveDuplicatesRequest true "Copies to keep and remove"
+// @Success 200 {object} APIResponse{data=services.DuplicateResolution}
+// @Failure 400 {object} APIResponse "VALIDATION_REQUIRED_FIELD / VALIDATION_INVALID_FORMAT"
+// @Failure 409 {object} APIResponse "LIBRARY_DUPLICATE_MISMATCH / LIBRARY_DUPLICATE_SAME_FILE"
+// @Router /api/v1/library/duplicates/resolve [post]
+func (h *DuplicateHandler) Resolve(c *gin.Context) {
+	var req services.ResolveDuplicatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := h.service.Resolve(c.Request.Context(), req)
+	switch {
+	case err == nil:
+		SuccessResponse(c, result)
+	case errors.Is(err, services.ErrDuplicateConfirmationRequired):
+		ErrorResponse(c, http.StatusBadRequest, "VALIDATION_REQUIRED_FIELD",
+			"此操作會刪除磁碟上的檔案，需要確認。", "確認後以 confirm: true 重新送出。")
+	case errors.Is(err, services.ErrDuplicateInvalidRequest):
+		ErrorResponse(c, http.StatusBadRequest, "VALIDATION_INVALID_FORMAT",
+			"請指定要保留與移除的版本，動作須為 delete 或 trash。", "重新整理重複項目清單後再試。")
+	case errors.Is(err, services.ErrDuplicateGroupMismatch):
+		ErrorResponse(c, http.StatusConflict, errCodeDuplicateMismatch,
+			"所選版本不屬於同一組重複項目。", "重新整理重複項目清單後再試。")
+	case errors.Is(err, services.ErrDuplicateSameFile):
+		ErrorResponse(c, http.StatusConflict, errCodeDuplicateSameFile,
+			"要移除的版本與保留的版本是同一個檔案（相同路徑或硬連結）。",
+			"只移除實際不同的檔案。")
+	default:
+		slog.Error("Failed to resolve duplicates", "error", err)
+		InternalServerError(c, "Failed to resolve duplicates")
+	}
+}
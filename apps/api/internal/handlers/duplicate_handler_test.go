@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vido/api/internal/services"
+)
+
+// mockDuplicateService applies the service's guards without touching disk.
+type mockDuplicateService struct{}
+
+func (m *mockDuplicateService) FindDuplicates(ctx context.Context) (*services.DuplicateReport, error) {
+	return &services.DuplicateReport{Groups: []services.DuplicateGroup{{Key: "movie:tmdb:438631"}}}, nil
+}
+
+func (m *mockDuplicateService) Resolve(ctx context.Context, req services.ResolveDuplicatesRequest) (*services.DuplicateResolution, error) {
+	switch {
+	case !req.Confirm:
+		return nil, services.ErrDuplicateConfirmationRequired
+	case req.Action != services.DuplicateActionDelete && req.Action != services.DuplicateActionTrash:
+		return nil, services.ErrDuplicateInvalidRequest
+	case req.KeepID != "m-remux":
+		return nil, services.ErrDuplicateGroupMismatch
+	case len(req.RemoveIDs) > 0 && req.RemoveIDs[0] == "m-link":
+		return nil, services.ErrDuplicateSameFile
+	}
+	return &services.DuplicateResolution{KeptID: req.KeepID}, nil
+}
+
+func TestDuplicateHandler_Resolve(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewDuplicateHandler(&mockDuplicateService{}).RegisterRoutes(router.Group("/api/v1"))
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{"confirmed", `{"keep_id":"m-remux","remove_ids":["m-web"],"action":"trash","confirm":true}`, http.StatusOK, `"kept_id":"m-remux"`},
+		{"unconfirmed", `{"keep_id":"m-remux","remove_ids":["m-web"],"action":"trash"}`, http.StatusBadRequest, "VALIDATION_REQUIRED_FIELD"},
+		{"bad action", `{"keep_id":"m-remux","remove_ids":["m-web"],"action":"shred","confirm":true}`, http.StatusBadRequest, "VALIDATION_INVALID_FORMAT"},
+		{"other group", `{"keep_id":"m-solo","remove_ids":["m-web"],"action":"delete","confirm":true}`, http.StatusConflict, errCodeDuplicateMismatch},
+		{"hardlink of the kept file", `{"keep_id":"m-remux","remove_ids":["m-link"],"action":"delete","confirm":true}`, http.StatusConflict, errCodeDuplicateSameFile},
+		{"malformed", `{`, http.StatusBadRequest, "VALIDATION_ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/library/duplicates/resolve", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/library/duplicates", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "movie:tmdb:438631")
+}
@@ -170,6 +170,11 @@ func (h *DVRSettingsHandler) getRootFolders(c *gin.Context, plugin string) {
 // failures are 400s. Expected 4xx flows log at Debug (CR M2 precedent),
 // server faults at Error.
 func (h *DVRSettingsHandler) respondError(c *gin.Context, plugin string, err error, message string) {
+	respondPluginError(c, plugin, err, message)
+}
+
+// respondPluginError is respondError for any plugin settings handler.
+func respondPluginError(c *gin.Context, plugin string, err error, message string) {
 	var pluginErr *plugins.PluginError
 	if errors.As(err, &pluginErr) {
 		status := http.StatusBadRequest
@@ -180,17 +185,17 @@ func (h *DVRSettingsHandler) respondError(c *gin.Context, plugin string, err err
 			status = http.StatusInternalServerError
 		}
 		if status == http.StatusInternalServerError {
-			slog.Error("DVR settings operation failed",
+			slog.Error("Plugin settings operation failed",
 				"plugin", plugin, "code", pluginErr.Code, "error", err)
 		} else {
-			slog.Debug("DVR settings operation rejected",
+			slog.Debug("Plugin settings operation rejected",
 				"plugin", plugin, "code", pluginErr.Code, "error", err)
 		}
 		ErrorResponse(c, status, pluginErr.Code, message, err.Error())
 		return
 	}
 
-	slog.Error("DVR settings operation failed", "plugin", plugin, "error", err)
+	slog.Error("Plugin settings operation failed", "plugin", plugin, "error", err)
 	InternalServerError(c, message)
 }
 
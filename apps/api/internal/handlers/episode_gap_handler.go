@@ -0,0 +1,140 @@
+// Package handlers — EpisodeGapHandler.
+//
+// Missing episodes per series and across the library, and bulk requests for
+// them through the partial-request path.
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/services"
+)
+
+// errCodeSeriesUnmatched is a code-list addition under the registered
+// LIBRARY_ prefix.
+const errCodeSeriesUnmatched = "LIBRARY_SERIES_UNMATCHED"
+
+// EpisodeGapHandler handles HTTP requests for episode gap detection.
+type EpisodeGapHandler struct {
+	service services.EpisodeGapServiceInterface
+}
+
+// NewEpisodeGapHandler creates a new EpisodeGapHandler.
+func NewEpisodeGapHandler(service services.EpisodeGapServiceInterface) *EpisodeGapHandler {
+	return &EpisodeGapHandler{service: service}
+}
+
+// RequestGapsRequest is the optional POST /api/v1/series/gaps/request body.
+type RequestGapsRequest struct {
+	SeriesIDs []string `json:"series_ids"`
+}
+
+// RegisterRoutes registers the gap routes.
+func (h *EpisodeGapHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	series := rg.Group("/series")
+	{
+		series.GET("/gaps", h.LibraryGaps)
+		series.POST("/gaps/request", h.RequestLibraryGaps)
+		series.GET("/:id/gaps", h.SeriesGaps)
+		series.POST("/:id/gaps/request", h.RequestSeriesGaps)
+	}
+}
+
+// LibraryGaps handles GET /api/v1/series/gaps
+// @Summary Every series with aired episodes missing from the library
+// @Tags series
+// @Produce json
+// @Param include_specials query bool false "Also diff season 0"
+// @Success 200 {object} APIResponse{data=[]services.SeriesGaps}
+// @Router /api/v1/series/gaps [get]
+func (h *EpisodeGapHandler) LibraryGaps(c *gin.Context) {
+	gaps, err := h.service.LibraryGaps(c.Request.Context(), gapOptions(c))
+	if err != nil {
+		handleGapError(c, "Failed to detect library gaps", err)
+		return
+	}
+	SuccessResponse(c, gaps)
+}
+
+// SeriesGaps handles GET /api/v1/series/:id/gaps
+// @Summary Missing episodes of one series, with the matching partial-request selection
+// @Tags series
+// @Produce json
+// @Param id path string true "Series ID"
+// @Param include_specials query bool false "Also diff season 0"
+// @Success 200 {object} APIResponse{data=services.SeriesGaps}
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Failure 422 {object} APIResponse "LIBRARY_SERIES_UNMATCHED"
+// @Router /api/v1/series/{id}/gaps [get]
+func (h *EpisodeGapHandler) SeriesGaps(c *gin.Context) {
+	gaps, err := h.service.SeriesGaps(c.Request.Context(), c.Param("id"), gapOptions(c))
+	if err != nil {
+		handleGapError(c, "Failed to detect series gaps", err)
+		return
+	}
+	SuccessResponse(c, gaps)
+}
+
+// RequestSeriesGaps handles POST /api/v1/series/:id/gaps/request
+// @Summary Request every missing aired episode of one series
+// @Tags series
+// @Produce json
+// @Param id path string true "Series ID"
+// @Param include_specials query bool false "Also request missing specials"
+// @Success 200 {object} APIResponse{data=services.GapRequestResult}
+// @Router /api/v1/series/{id}/gaps/request [post]
+func (h *EpisodeGapHandler) RequestSeriesGaps(c *gin.Context) {
+	h.requestGaps(c, []string{c.Param("id")})
+}
+
+// RequestLibraryGaps handles POST /api/v1/series/gaps/request
+// @Summary Request missing episodes in bulk
+// @Description One partial request per series. Without series_ids every series with gaps is requested.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param request body RequestGapsRequest false "Series to request"
+// @Param include_specials query bool false "Also request missing specials"
+// @Success 200 {object} APIResponse{data=services.GapRequestResult}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR"
+// @Router /api/v1/series/gaps/request [post]
+func (h *EpisodeGapHandler) RequestLibraryGaps(c *gin.Context) {
+	var req RequestGapsRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+	h.requestGaps(c, req.SeriesIDs)
+}
+
+func (h *EpisodeGapHandler) requestGaps(c *gin.Context, seriesIDs []string) {
+	result, err := h.service.RequestGaps(c.Request.Context(), seriesIDs, gapOptions(c))
+	if err != nil {
+		handleGapError(c, "Failed to request missing episodes", err)
+		return
+	}
+	SuccessResponse(c, result)
+}
+
+func gapOptions(c *gin.Context) services.GapOptions {
+	return services.GapOptions{IncludeSpecials: c.Query("include_specials") == "true"}
+}
+
+func handleGapError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		NotFoundError(c, "Series")
+	case errors.Is(err, services.ErrSeriesUnmatched):
+		ErrorResponse(c, http.StatusUnprocessableEntity, errCodeSeriesUnmatched,
+			"此影集尚未對應到 TMDb，無法比對缺集。", "先為影集補上中繼資料後再試。")
+	default:
+		slog.Error(message, "error", err)
+		InternalServerError(c, message)
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vido/api/internal/services"
+)
+
+// mockEpisodeGapService answers from fixed data and records request calls.
+type mockEpisodeGapService struct {
+	requestedIDs []string
+	opts         services.GapOptions
+}
+
+func (m *mockEpisodeGapService) SeriesGaps(ctx context.Context, seriesID string, opts services.GapOptions) (*services.SeriesGaps, error) {
+	switch seriesID {
+	case "s1":
+		return &services.SeriesGaps{SeriesID: "s1", MissingCount: 2, RequestSeasons: []int{2}}, nil
+	case "s3":
+		return nil, fmt.Errorf("series s3: %w", services.ErrSeriesUnmatched)
+	}
+	return nil, fmt.Errorf("series with id %s not found: %w", seriesID, sql.ErrNoRows)
+}
+
+func (m *mockEpisodeGapService) LibraryGaps(ctx context.Context, opts services.GapOptions) ([]services.SeriesGaps, error) {
+	m.opts = opts
+	return []services.SeriesGaps{}, nil
+}
+
+func (m *mockEpisodeGapService) RequestGaps(ctx context.Context, seriesIDs []string, opts services.GapOptions) (*services.GapRequestResult, error) {
+	m.requestedIDs = seriesIDs
+	return &services.GapRequestResult{}, nil
+}
+
+func setupEpisodeGapRouter(svc services.EpisodeGapServiceInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewEpisodeGapHandler(svc).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestEpisodeGapHandler_SeriesGaps(t *testing.T) {
+	router := setupEpisodeGapRouter(&mockEpisodeGapService{})
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"found", "/api/v1/series/s1/gaps", http.StatusOK, `"request_seasons":[2]`},
+		{"unknown", "/api/v1/series/nope/gaps", http.StatusNotFound, "DB_NOT_FOUND"},
+		{"unmatched", "/api/v1/series/s3/gaps", http.StatusUnprocessableEntity, errCodeSeriesUnmatched},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestEpisodeGapHandler_LibraryRoutes(t *testing.T) {
+	svc := &mockEpisodeGapService{}
+	router := setupEpisodeGapRouter(svc)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/series/gaps?include_specials=true", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, svc.opts.IncludeSpecials)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/series/gaps/request", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "an empty body requests the whole library")
+	assert.Empty(t, svc.requestedIDs)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/series/gaps/request",
+		strings.NewReader(`{"series_ids":["s1","s2"]}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"s1", "s2"}, svc.requestedIDs)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/series/s1/gaps/request", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"s1"}, svc.requestedIDs)
+}
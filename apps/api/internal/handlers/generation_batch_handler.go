@@ -72,6 +72,7 @@ type GenerationBatchStartRequest struct {
 // @Success 200 {object} APIResponse "scope=missing resolved to 0 items: {total_items:0, items:[]}"
 // @Failure 400 {object} APIResponse "validation failed (bad scope / missing media_ids / unknown id / budget_usd <= 0)"
 // @Failure 409 {object} APIResponse "TRANSCRIPTION_BATCH_RUNNING — current progress in error body data"
+// @Failure 429 {object} APIResponse "AI_SPEND_CAP_REACHED"
 // @Failure 500 {object} APIResponse "TRANSCRIPTION_BATCH_START_FAILED"
 // @Failure 503 {object} APIResponse "TRANSCRIPTION_DISABLED"
 // @Router /api/v1/subtitles/generation-batch [post]
@@ -142,6 +143,9 @@ func (h *GenerationBatchHandler) StartGenerationBatch(c *gin.Context) {
 				"media_ids 含無法生成字幕的項目（查無此電影或影集，或沒有媒體檔案）："+err.Error())
 			return
 		}
+		if respondSpendCapReached(c, err) {
+			return
+		}
 		ErrorResponse(c, http.StatusInternalServerError, "TRANSCRIPTION_BATCH_START_FAILED",
 			"字幕生成批次啟動失敗："+err.Error(),
 			"請確認媒體資料庫可讀取後再試一次。")
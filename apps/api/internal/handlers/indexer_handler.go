@@ -0,0 +1,203 @@
+// Package handlers — IndexerHandler.
+//
+// CRUD for the Torznab/Newznab/RSS indexers the built-in fulfilment path
+// reads, a connection test, and an on-demand grab pass. Everything lives
+// under /settings/indexers, so the routes are admin-only through
+// AdminRoutes.
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/indexer"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/services"
+)
+
+// errCodeBuiltinFulfilmentRunning is a code-list addition under the
+// INDEXER_ prefix the indexer package registers.
+const errCodeBuiltinFulfilmentRunning = "INDEXER_RUN_IN_PROGRESS"
+
+// IndexerHandler handles HTTP requests for indexers.
+type IndexerHandler struct {
+	service services.IndexerServiceInterface
+	runner  services.BuiltinFulfilmentServiceInterface
+}
+
+// NewIndexerHandler creates a new IndexerHandler.
+func NewIndexerHandler(service services.IndexerServiceInterface, runner services.BuiltinFulfilmentServiceInterface) *IndexerHandler {
+	return &IndexerHandler{service: service, runner: runner}
+}
+
+// RegisterRoutes registers the indexer routes.
+func (h *IndexerHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	group := rg.Group("/settings/indexers")
+	{
+		group.GET("", h.ListIndexers)
+		group.POST("", h.CreateIndexer)
+		group.POST("/run", h.Run)
+		group.GET("/:id", h.GetIndexer)
+		group.PUT("/:id", h.UpdateIndexer)
+		group.DELETE("/:id", h.DeleteIndexer)
+		group.POST("/:id/test", h.TestIndexer)
+	}
+}
+
+// ListIndexers handles GET /api/v1/settings/indexers
+// @Summary List indexers
+// @Tags indexers
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]models.Indexer}
+// @Router /api/v1/settings/indexers [get]
+func (h *IndexerHandler) ListIndexers(c *gin.Context) {
+	indexers, err := h.service.ListIndexers(c.Request.Context())
+	if err != nil {
+		handleIndexerError(c, "Failed to list indexers", err)
+		return
+	}
+	if indexers == nil {
+		indexers = []models.Indexer{}
+	}
+	SuccessResponse(c, indexers)
+}
+
+// GetIndexer handles GET /api/v1/settings/indexers/:id
+// @Summary Get one indexer
+// @Tags indexers
+// @Produce json
+// @Success 200 {object} APIResponse{data=models.Indexer}
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/settings/indexers/{id} [get]
+func (h *IndexerHandler) GetIndexer(c *gin.Context) {
+	ix, err := h.service.GetIndexer(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleIndexerError(c, "Failed to load indexer", err)
+		return
+	}
+	SuccessResponse(c, ix)
+}
+
+// CreateIndexer handles POST /api/v1/settings/indexers
+// @Summary Create an indexer
+// @Description The API key is stored in the secrets store and never returned.
+// @Tags indexers
+// @Accept json
+// @Produce json
+// @Success 201 {object} APIResponse{data=models.Indexer}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR | VALIDATION_FAILED"
+// @Router /api/v1/settings/indexers [post]
+func (h *IndexerHandler) CreateIndexer(c *gin.Context) {
+	var input services.IndexerInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	ix, err := h.service.CreateIndexer(c.Request.Context(), input)
+	if err != nil {
+		handleIndexerError(c, "Failed to create indexer", err)
+		return
+	}
+	CreatedResponse(c, ix)
+}
+
+// UpdateIndexer handles PUT /api/v1/settings/indexers/:id
+// @Summary Replace an indexer's settings
+// @Description An empty api_key keeps the stored one; the type cannot change.
+// @Tags indexers
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse{data=models.Indexer}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR | VALIDATION_FAILED"
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/settings/indexers/{id} [put]
+func (h *IndexerHandler) UpdateIndexer(c *gin.Context) {
+	var input services.IndexerInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	ix, err := h.service.UpdateIndexer(c.Request.Context(), c.Param("id"), input)
+	if err != nil {
+		handleIndexerError(c, "Failed to update indexer", err)
+		return
+	}
+	SuccessResponse(c, ix)
+}
+
+// DeleteIndexer handles DELETE /api/v1/settings/indexers/:id
+// @Summary Delete an indexer and its API key
+// @Tags indexers
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/settings/indexers/{id} [delete]
+func (h *IndexerHandler) DeleteIndexer(c *gin.Context) {
+	if err := h.service.DeleteIndexer(c.Request.Context(), c.Param("id")); err != nil {
+		handleIndexerError(c, "Failed to delete indexer", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Indexer deleted"})
+}
+
+// TestIndexer handles POST /api/v1/settings/indexers/:id/test
+// @Summary Check that a saved indexer answers and accepts its API key
+// @Tags indexers
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Failure 502 {object} APIResponse "INDEXER_REQUEST_FAILED | INDEXER_AUTH_FAILED | INDEXER_INVALID_RESPONSE"
+// @Router /api/v1/settings/indexers/{id}/test [post]
+func (h *IndexerHandler) TestIndexer(c *gin.Context) {
+	if err := h.service.TestIndexer(c.Request.Context(), c.Param("id")); err != nil {
+		handleIndexerError(c, "Failed to test indexer", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Indexer is reachable"})
+}
+
+// Run handles POST /api/v1/settings/indexers/run
+// @Summary Match waiting requests against the indexers now and grab the best releases
+// @Description Runs synchronously; the same pass also runs every 15 minutes.
+// @Tags indexers
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.BuiltinFulfilmentRun}
+// @Failure 409 {object} APIResponse "INDEXER_RUN_IN_PROGRESS"
+// @Router /api/v1/settings/indexers/run [post]
+func (h *IndexerHandler) Run(c *gin.Context) {
+	run, err := h.runner.RunOnce(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrBuiltinFulfilmentRunning) {
+			ErrorResponse(c, http.StatusConflict, errCodeBuiltinFulfilmentRunning,
+				"內建下載正在比對中。", "等待目前的比對結束後再試。")
+			return
+		}
+		handleIndexerError(c, "Failed to run built-in fulfilment", err)
+		return
+	}
+	SuccessResponse(c, run)
+}
+
+func handleIndexerError(c *gin.Context, message string, err error) {
+	var validationErr *models.ValidationError
+	var indexerErr *indexer.Error
+	switch {
+	case errors.As(err, &validationErr):
+		BadRequestError(c, "VALIDATION_FAILED", err.Error())
+	case errors.Is(err, repository.ErrIndexerNotFound):
+		NotFoundError(c, "indexer")
+	case errors.As(err, &indexerErr):
+		ErrorResponse(c, http.StatusBadGateway, indexerErr.Code,
+			"索引器連線失敗："+indexerErr.Message,
+			"Check the indexer's URL and API key, then test again.")
+	default:
+		slog.Error(message, "error", err)
+		InternalServerError(c, message)
+	}
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/indexer"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/services"
+)
+
+// mockIndexerService implements services.IndexerServiceInterface and
+// services.BuiltinFulfilmentServiceInterface via swappable funcs.
+type mockIndexerService struct {
+	listIndexers  func(ctx context.Context) ([]models.Indexer, error)
+	getIndexer    func(ctx context.Context, id string) (*models.Indexer, error)
+	createIndexer func(ctx context.Context, input services.IndexerInput) (*models.Indexer, error)
+	updateIndexer func(ctx context.Context, id string, input services.IndexerInput) (*models.Indexer, error)
+	deleteIndexer func(ctx context.Context, id string) error
+	testIndexer   func(ctx context.Context, id string) error
+	runOnce       func(ctx context.Context) (*services.BuiltinFulfilmentRun, error)
+}
+
+func (m *mockIndexerService) ListIndexers(ctx context.Context) ([]models.Indexer, error) {
+	return m.listIndexers(ctx)
+}
+func (m *mockIndexerService) GetIndexer(ctx context.Context, id string) (*models.Indexer, error) {
+	return m.getIndexer(ctx, id)
+}
+func (m *mockIndexerService) CreateIndexer(ctx context.Context, input services.IndexerInput) (*models.Indexer, error) {
+	return m.createIndexer(ctx, input)
+}
+func (m *mockIndexerService) UpdateIndexer(ctx context.Context, id string, input services.IndexerInput) (*models.Indexer, error) {
+	return m.updateIndexer(ctx, id, input)
+}
+func (m *mockIndexerService) DeleteIndexer(ctx context.Context, id string) error {
+	return m.deleteIndexer(ctx, id)
+}
+func (m *mockIndexerService) TestIndexer(ctx context.Context, id string) error {
+	return m.testIndexer(ctx, id)
+}
+func (m *mockIndexerService) RunOnce(ctx context.Context) (*services.BuiltinFulfilmentRun, error) {
+	return m.runOnce(ctx)
+}
+
+var (
+	_ services.IndexerServiceInterface           = (*mockIndexerService)(nil)
+	_ services.BuiltinFulfilmentServiceInterface = (*mockIndexerService)(nil)
+)
+
+func setupIndexerRouter(svc *mockIndexerService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewIndexerHandler(svc, svc).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestIndexerHandler_CreateIndexer(t *testing.T) {
+	var captured services.IndexerInput
+	svc := &mockIndexerService{
+		createIndexer: func(ctx context.Context, input services.IndexerInput) (*models.Indexer, error) {
+			captured = input
+			if input.Type == "usenet" {
+				return nil, &models.ValidationError{Field: "type", Message: "type must be one of torznab, newznab, rss"}
+			}
+			return &models.Indexer{ID: "ix1", Name: input.Name, Type: input.Type, HasAPIKey: input.APIKey != ""}, nil
+		},
+	}
+	router := setupIndexerRouter(svc)
+
+	t.Run("created without echoing the key", func(t *testing.T) {
+		body := `{"name":"Jackett","type":"torznab","url":"http://jackett:9117/api/v2.0/indexers/all/results/torznab","categories":[2000],"enabled":true,"api_key":"secret"}`
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/settings/indexers", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, []int{2000}, captured.Categories)
+		assert.Equal(t, "secret", captured.APIKey)
+		assert.NotContains(t, w.Body.String(), "secret")
+		assert.Contains(t, w.Body.String(), `"has_api_key":true`)
+	})
+
+	t.Run("validation failure is 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/settings/indexers", bytes.NewBufferString(`{"name":"X","type":"usenet"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var resp APIResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "VALIDATION_FAILED", resp.Error.Code)
+	})
+}
+
+func TestIndexerHandler_NotFound(t *testing.T) {
+	notFound := fmt.Errorf("indexer with id nope: %w", repository.ErrIndexerNotFound)
+	svc := &mockIndexerService{
+		getIndexer:    func(ctx context.Context, id string) (*models.Indexer, error) { return nil, notFound },
+		deleteIndexer: func(ctx context.Context, id string) error { return notFound },
+	}
+	router := setupIndexerRouter(svc)
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/api/v1/settings/indexers/nope", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, method)
+	}
+}
+
+func TestIndexerHandler_TestIndexer(t *testing.T) {
+	svc := &mockIndexerService{
+		testIndexer: func(ctx context.Context, id string) error {
+			if id == "bad" {
+				return &indexer.Error{Code: indexer.ErrCodeAuthFailed, Message: "Incorrect user credentials"}
+			}
+			return nil
+		},
+	}
+	router := setupIndexerRouter(svc)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/settings/indexers/ok/test", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/settings/indexers/bad/test", nil))
+	require.Equal(t, http.StatusBadGateway, w.Code)
+	var resp APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, indexer.ErrCodeAuthFailed, resp.Error.Code)
+}
+
+func TestIndexerHandler_Run(t *testing.T) {
+	t.Run("returns the pass", func(t *testing.T) {
+		svc := &mockIndexerService{
+			runOnce: func(ctx context.Context) (*services.BuiltinFulfilmentRun, error) {
+				return &services.BuiltinFulfilmentRun{Considered: 2, Grabs: []services.BuiltinGrab{
+					{RequestID: "r1", Release: "Fight.Club.1999.1080p.BluRay.x264", Indexer: "Jackett", InfoHash: "abc"},
+				}}, nil
+			},
+		}
+		w := httptest.NewRecorder()
+		setupIndexerRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/settings/indexers/run", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"request_id":"r1"`)
+	})
+
+	t.Run("a pass in progress is 409", func(t *testing.T) {
+		svc := &mockIndexerService{
+			runOnce: func(ctx context.Context) (*services.BuiltinFulfilmentRun, error) {
+				return nil, services.ErrBuiltinFulfilmentRunning
+			},
+		}
+		w := httptest.NewRecorder()
+		setupIndexerRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/settings/indexers/run", nil))
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		var resp APIResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, errCodeBuiltinFulfilmentRunning, resp.Error.Code)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/vido/api/internal/models"
 	"github.com/vido/api/internal/services"
 )
 
@@ -22,7 +23,7 @@ func NewLibraryHandler(service services.LibraryServiceInterface) *LibraryHandler
 
 // ListLibrary handles GET /api/v1/library
 // Returns a paginated list of library items (movies + series combined)
-// Supports filters: genre, year_min, year_max via query params
+// Supports filters: genre, year_min, year_max, watch_status via query params
 func (h *LibraryHandler) ListLibrary(c *gin.Context) {
 	params := parseListParams(c)
 
@@ -75,6 +76,15 @@ func (h *LibraryHandler) ListLibrary(c *gin.Context) {
 		params.Filters["unmatched"] = true
 	}
 
+	if status := c.Query("watch_status"); status != "" {
+		if !models.IsValidWatchStatus(status) {
+			BadRequestError(c, "VALIDATION_INVALID_FORMAT", "watch_status must be 'watched', 'unwatched', or 'in_progress'")
+			return
+		}
+		params.Filters["watch_status"] = status
+		params.Filters["watch_user"] = watchUserID(c)
+	}
+
 	result, err := h.service.ListLibrary(c.Request.Context(), params, mediaType)
 	if err != nil {
 		slog.Error("Failed to list library", "error", err, "type", mediaType)
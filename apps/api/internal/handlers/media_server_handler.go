@@ -0,0 +1,178 @@
+// Package handlers — MediaServerHandler.
+//
+// Settings + actions for the media-server plugins (Jellyfin, Emby, Plex; for
+// Plex the api_key is the X-Plex-Token). Same shape
+// as DVRSettingsHandler (static per-plugin routes under /settings, so they are
+// admin-only through AdminRoutes) plus refresh and watch-state import.
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/plugins"
+	"github.com/vido/api/internal/services"
+)
+
+// MediaServerHandler handles HTTP requests for media-server plugins.
+type MediaServerHandler struct {
+	service     services.MediaServerServiceInterface
+	pluginNames []string
+}
+
+// NewMediaServerHandler creates a handler serving the given plugin names.
+func NewMediaServerHandler(service services.MediaServerServiceInterface, pluginNames ...string) *MediaServerHandler {
+	return &MediaServerHandler{service: service, pluginNames: pluginNames}
+}
+
+// RegisterRoutes registers the settings and action routes for every served
+// plugin.
+func (h *MediaServerHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	for _, name := range h.pluginNames {
+		plugin := name // capture per iteration
+		group := rg.Group("/settings/" + plugin)
+		{
+			group.GET("", func(c *gin.Context) { h.getConfig(c, plugin) })
+			group.PUT("", func(c *gin.Context) { h.saveConfig(c, plugin) })
+			group.POST("/test", func(c *gin.Context) { h.testConnection(c, plugin) })
+			group.GET("/users", func(c *gin.Context) { h.listUsers(c, plugin) })
+			group.POST("/refresh", func(c *gin.Context) { h.refreshLibrary(c, plugin) })
+			group.POST("/import-watch-state", func(c *gin.Context) { h.importWatchState(c, plugin) })
+		}
+	}
+}
+
+// getConfig handles GET /api/v1/settings/{plugin}
+// @Summary Get media-server configuration (sans API key) + live health block
+// @Tags media-server
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.MediaServerConfigStatus}
+// @Router /api/v1/settings/jellyfin [get]
+// @Router /api/v1/settings/emby [get]
+// @Router /api/v1/settings/plex [get]
+func (h *MediaServerHandler) getConfig(c *gin.Context, plugin string) {
+	status, err := h.service.GetConfig(c.Request.Context(), plugin)
+	if err != nil {
+		respondPluginError(c, plugin, err, "無法載入 "+pluginDisplayName(plugin)+" 設定")
+		return
+	}
+	SuccessResponse(c, status)
+}
+
+// saveConfig handles PUT /api/v1/settings/{plugin}
+// @Summary Save media-server configuration (server-side test-before-save guard)
+// @Tags media-server
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse "VALIDATION_INVALID_FORMAT | VALIDATION_FAILED"
+// @Failure 409 {object} APIResponse "DVR_TEST_FAILED — connection test failed, config not saved"
+// @Router /api/v1/settings/jellyfin [put]
+// @Router /api/v1/settings/emby [put]
+// @Router /api/v1/settings/plex [put]
+func (h *MediaServerHandler) saveConfig(c *gin.Context, plugin string) {
+	var input services.MediaServerConfigInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+	if input.URL == "" {
+		ValidationError(c, "url is required")
+		return
+	}
+
+	if err := h.service.SaveConfig(c.Request.Context(), plugin, input); err != nil {
+		h.respondError(c, plugin, err, "儲存 "+pluginDisplayName(plugin)+" 設定失敗")
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Configuration saved"})
+}
+
+// testConnection handles POST /api/v1/settings/{plugin}/test
+// @Summary Test media-server connection (body config if provided, else saved)
+// @Tags media-server
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse "MEDIA_SERVER_CONNECTION_FAILED | MEDIA_SERVER_AUTH_FAILED | MEDIA_SERVER_TIMEOUT | DVR_NOT_CONFIGURED"
+// @Router /api/v1/settings/jellyfin/test [post]
+// @Router /api/v1/settings/emby/test [post]
+// @Router /api/v1/settings/plex/test [post]
+func (h *MediaServerHandler) testConnection(c *gin.Context, plugin string) {
+	var input *services.MediaServerConfigInput
+	var req dvrTestRequest
+	if c.ShouldBindJSON(&req) == nil && req.URL != "" {
+		input = &services.MediaServerConfigInput{URL: req.URL, APIKey: req.APIKey}
+	}
+
+	if err := h.service.TestConnection(c.Request.Context(), plugin, input); err != nil {
+		h.respondError(c, plugin, err, "無法連線到 "+pluginDisplayName(plugin))
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Connection successful"})
+}
+
+// listUsers handles GET /api/v1/settings/{plugin}/users
+// @Summary List the media server's accounts (to choose the watch-import user)
+// @Tags media-server
+// @Produce json
+// @Success 200 {object} APIResponse{data=object}
+// @Router /api/v1/settings/jellyfin/users [get]
+// @Router /api/v1/settings/emby/users [get]
+func (h *MediaServerHandler) listUsers(c *gin.Context, plugin string) {
+	users, err := h.service.ListServerUsers(c.Request.Context(), plugin)
+	if err != nil {
+		h.respondError(c, plugin, err, "無法載入 "+pluginDisplayName(plugin)+" 使用者")
+		return
+	}
+	if users == nil {
+		users = []plugins.MediaServerUser{}
+	}
+	SuccessResponse(c, gin.H{"users": users})
+}
+
+// refreshLibrary handles POST /api/v1/settings/{plugin}/refresh
+// @Summary Ask the media server to rescan every library
+// @Tags media-server
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Router /api/v1/settings/jellyfin/refresh [post]
+// @Router /api/v1/settings/emby/refresh [post]
+// @Router /api/v1/settings/plex/refresh [post]
+func (h *MediaServerHandler) refreshLibrary(c *gin.Context, plugin string) {
+	if err := h.service.RefreshLibrary(c.Request.Context(), plugin); err != nil {
+		h.respondError(c, plugin, err, "無法要求 "+pluginDisplayName(plugin)+" 重新掃描")
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Library refresh requested"})
+}
+
+// importWatchState handles POST /api/v1/settings/{plugin}/import-watch-state
+// @Summary Import played and part-played items into Vido's watch history
+// @Tags media-server
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.WatchImportResult}
+// @Failure 400 {object} APIResponse "VALIDATION_FAILED — no import user / no matching accounts / server without watch-state export (Plex)"
+// @Router /api/v1/settings/jellyfin/import-watch-state [post]
+// @Router /api/v1/settings/emby/import-watch-state [post]
+func (h *MediaServerHandler) importWatchState(c *gin.Context, plugin string) {
+	result, err := h.service.ImportWatchState(c.Request.Context(), plugin)
+	if err != nil {
+		h.respondError(c, plugin, err, "匯入 "+pluginDisplayName(plugin)+" 觀看紀錄失敗")
+		return
+	}
+	SuccessResponse(c, result)
+}
+
+// respondError maps validation failures to 400 and everything else through
+// respondPluginError.
+func (h *MediaServerHandler) respondError(c *gin.Context, plugin string, err error, message string) {
+	var validationErr *models.ValidationError
+	if errors.As(err, &validationErr) {
+		BadRequestError(c, "VALIDATION_FAILED", err.Error())
+		return
+	}
+	respondPluginError(c, plugin, err, message)
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/plugins"
+	"github.com/vido/api/internal/services"
+)
+
+// mockMediaServerService implements services.MediaServerServiceInterface via
+// swappable funcs.
+type mockMediaServerService struct {
+	getConfig        func(ctx context.Context, plugin string) (*services.MediaServerConfigStatus, error)
+	saveConfig       func(ctx context.Context, plugin string, input services.MediaServerConfigInput) error
+	testConnection   func(ctx context.Context, plugin string, input *services.MediaServerConfigInput) error
+	listServerUsers  func(ctx context.Context, plugin string) ([]plugins.MediaServerUser, error)
+	refreshLibrary   func(ctx context.Context, plugin string) error
+	importWatchState func(ctx context.Context, plugin string) (*services.WatchImportResult, error)
+}
+
+func (m *mockMediaServerService) GetConfig(ctx context.Context, plugin string) (*services.MediaServerConfigStatus, error) {
+	return m.getConfig(ctx, plugin)
+}
+func (m *mockMediaServerService) SaveConfig(ctx context.Context, plugin string, input services.MediaServerConfigInput) error {
+	return m.saveConfig(ctx, plugin, input)
+}
+func (m *mockMediaServerService) TestConnection(ctx context.Context, plugin string, input *services.MediaServerConfigInput) error {
+	return m.testConnection(ctx, plugin, input)
+}
+func (m *mockMediaServerService) ListServerUsers(ctx context.Context, plugin string) ([]plugins.MediaServerUser, error) {
+	return m.listServerUsers(ctx, plugin)
+}
+func (m *mockMediaServerService) RefreshLibrary(ctx context.Context, plugin string) error {
+	return m.refreshLibrary(ctx, plugin)
+}
+func (m *mockMediaServerService) ImportWatchState(ctx context.Context, plugin string) (*services.WatchImportResult, error) {
+	return m.importWatchState(ctx, plugin)
+}
+
+var _ services.MediaServerServiceInterface = (*mockMediaServerService)(nil)
+
+func setupMediaServerRouter(svc services.MediaServerServiceInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	apiV1 := router.Group("/api/v1")
+	NewMediaServerHandler(svc, "jellyfin", "emby").RegisterRoutes(apiV1)
+	return router
+}
+
+func TestMediaServerHandler_SaveConfig(t *testing.T) {
+	var captured services.MediaServerConfigInput
+	svc := &mockMediaServerService{
+		saveConfig: func(ctx context.Context, plugin string, input services.MediaServerConfigInput) error {
+			assert.Equal(t, "emby", plugin)
+			captured = input
+			return nil
+		},
+	}
+	router := setupMediaServerRouter(svc)
+
+	body := `{"url":"http://nas:8096","api_key":"k","enabled":true,"local_path_prefix":"/data","server_path_prefix":"/media","watch_import_user":"alice"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/settings/emby", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, services.MediaServerConfigInput{
+		URL: "http://nas:8096", APIKey: "k", Enabled: true,
+		LocalPathPrefix: "/data", ServerPathPrefix: "/media", WatchImportUser: "alice",
+	}, captured)
+}
+
+func TestMediaServerHandler_ImportWatchState(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc := &mockMediaServerService{
+			importWatchState: func(ctx context.Context, plugin string) (*services.WatchImportResult, error) {
+				return &services.WatchImportResult{Users: 1, Imported: 4, Unmatched: 2}, nil
+			},
+		}
+		router := setupMediaServerRouter(svc)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/settings/jellyfin/import-watch-state", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data services.WatchImportResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 4, resp.Data.Imported)
+	})
+
+	t.Run("no import user is 400", func(t *testing.T) {
+		svc := &mockMediaServerService{
+			importWatchState: func(ctx context.Context, plugin string) (*services.WatchImportResult, error) {
+				return nil, &models.ValidationError{Field: "watch_import_user", Message: "choose which server account to import"}
+			},
+		}
+		router := setupMediaServerRouter(svc)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/settings/jellyfin/import-watch-state", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "VALIDATION_FAILED")
+	})
+}
+
+func TestMediaServerHandler_RefreshNotConfigured(t *testing.T) {
+	svc := &mockMediaServerService{
+		refreshLibrary: func(ctx context.Context, plugin string) error {
+			return &plugins.PluginError{Code: plugins.ErrCodeNotConfigured, Message: "jellyfin is not configured"}
+		},
+	}
+	router := setupMediaServerRouter(svc)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/settings/jellyfin/refresh", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), plugins.ErrCodeNotConfigured)
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/metrics"
+)
+
+// MetricsHandler serves reg in the Prometheus text format. It sits outside
+// /api/v1 so scrapers need no session; when token is set the scraper must
+// send it as a bearer token instead.
+func MetricsHandler(reg *metrics.Registry, token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token != "" {
+			presented := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+				ErrorResponse(c, http.StatusUnauthorized, "AUTH_REQUIRED",
+					"Metrics token required",
+					"Configure the scraper to send VIDO_METRICS_TOKEN as a bearer token.")
+				return
+			}
+		}
+		c.Header("Content-Type", metrics.ContentType)
+		c.Status(http.StatusOK)
+		if err := reg.WriteText(c.Writer); err != nil {
+			_ = c.Error(err)
+		}
+	}
+}
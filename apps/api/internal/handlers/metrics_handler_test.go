@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vido/api/internal/metrics"
+)
+
+func TestMetricsHandler_Token(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := metrics.NewRegistry()
+	reg.NewCounterVec("vido_test_total", "Test.").WithLabelValues().Inc()
+
+	tests := []struct {
+		name     string
+		token    string
+		header   string
+		wantCode int
+	}{
+		{"open without a token", "", "", http.StatusOK},
+		{"missing bearer", "s3cret", "", http.StatusUnauthorized},
+		{"wrong bearer", "s3cret", "Bearer nope", http.StatusUnauthorized},
+		{"matching bearer", "s3cret", "Bearer s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/metrics", MetricsHandler(reg, tt.token))
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, metrics.ContentType, w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Body.String(), "vido_test_total 1")
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Package handlers — NotificationHandler.
+//
+// CRUD for outbound notification targets plus a test send. Everything lives
+// under /settings/notifications, so the routes are admin-only through
+// AdminRoutes.
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/retry"
+	"github.com/vido/api/internal/services"
+)
+
+// NotificationHandler handles HTTP requests for notification targets.
+type NotificationHandler struct {
+	service services.NotificationServiceInterface
+}
+
+// NewNotificationHandler creates a new NotificationHandler.
+func NewNotificationHandler(service services.NotificationServiceInterface) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// RegisterRoutes registers the notification routes.
+func (h *NotificationHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	group := rg.Group("/settings/notifications")
+	{
+		group.GET("/events", h.ListEventTypes)
+		group.GET("/targets", h.ListTargets)
+		group.POST("/targets", h.CreateTarget)
+		group.GET("/targets/:id", h.GetTarget)
+		group.PUT("/targets/:id", h.UpdateTarget)
+		group.DELETE("/targets/:id", h.DeleteTarget)
+		group.POST("/targets/:id/test", h.TestTarget)
+	}
+}
+
+// ListEventTypes handles GET /api/v1/settings/notifications/events
+// @Summary List the event types a target can subscribe to
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} APIResponse{data=object}
+// @Router /api/v1/settings/notifications/events [get]
+func (h *NotificationHandler) ListEventTypes(c *gin.Context) {
+	SuccessResponse(c, gin.H{"events": models.NotificationEventTypes()})
+}
+
+// ListTargets handles GET /api/v1/settings/notifications/targets
+// @Summary List notification targets (credentials reported as has_secret only)
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]models.NotificationTarget}
+// @Router /api/v1/settings/notifications/targets [get]
+func (h *NotificationHandler) ListTargets(c *gin.Context) {
+	targets, err := h.service.ListTargets(c.Request.Context())
+	if err != nil {
+		handleNotificationError(c, "Failed to list notification targets", err)
+		return
+	}
+	if targets == nil {
+		targets = []models.NotificationTarget{}
+	}
+	SuccessResponse(c, targets)
+}
+
+// GetTarget handles GET /api/v1/settings/notifications/targets/:id
+// @Summary Get one notification target
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} APIResponse{data=models.NotificationTarget}
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/settings/notifications/targets/{id} [get]
+func (h *NotificationHandler) GetTarget(c *gin.Context) {
+	target, err := h.service.GetTarget(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleNotificationError(c, "Failed to load notification target", err)
+		return
+	}
+	SuccessResponse(c, target)
+}
+
+// CreateTarget handles POST /api/v1/settings/notifications/targets
+// @Summary Create a notification target
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Success 201 {object} APIResponse{data=models.NotificationTarget}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR | VALIDATION_FAILED"
+// @Router /api/v1/settings/notifications/targets [post]
+func (h *NotificationHandler) CreateTarget(c *gin.Context) {
+	var input services.NotificationTargetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	target, err := h.service.CreateTarget(c.Request.Context(), input)
+	if err != nil {
+		handleNotificationError(c, "Failed to create notification target", err)
+		return
+	}
+	CreatedResponse(c, target)
+}
+
+// UpdateTarget handles PUT /api/v1/settings/notifications/targets/:id
+// @Summary Replace a notification target's settings (empty secret keeps the stored one)
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse{data=models.NotificationTarget}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR | VALIDATION_FAILED"
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/settings/notifications/targets/{id} [put]
+func (h *NotificationHandler) UpdateTarget(c *gin.Context) {
+	var input services.NotificationTargetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	target, err := h.service.UpdateTarget(c.Request.Context(), c.Param("id"), input)
+	if err != nil {
+		handleNotificationError(c, "Failed to update notification target", err)
+		return
+	}
+	SuccessResponse(c, target)
+}
+
+// DeleteTarget handles DELETE /api/v1/settings/notifications/targets/:id
+// @Summary Delete a notification target and its credential
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/settings/notifications/targets/{id} [delete]
+func (h *NotificationHandler) DeleteTarget(c *gin.Context) {
+	if err := h.service.DeleteTarget(c.Request.Context(), c.Param("id")); err != nil {
+		handleNotificationError(c, "Failed to delete notification target", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Notification target deleted"})
+}
+
+// TestTarget handles POST /api/v1/settings/notifications/targets/:id/test
+// @Summary Send a test notification to a saved target (enabled or not)
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Failure 502 {object} APIResponse "NOTIFICATION_DELIVERY_FAILED — the target refused or could not be reached"
+// @Router /api/v1/settings/notifications/targets/{id}/test [post]
+func (h *NotificationHandler) TestTarget(c *gin.Context) {
+	if err := h.service.TestTarget(c.Request.Context(), c.Param("id")); err != nil {
+		handleNotificationError(c, "Failed to send test notification", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Test notification sent"})
+}
+
+func handleNotificationError(c *gin.Context, message string, err error) {
+	var validationErr *models.ValidationError
+	var deliveryErr *retry.RetryableError
+	switch {
+	case errors.As(err, &validationErr):
+		BadRequestError(c, "VALIDATION_FAILED", err.Error())
+	case errors.Is(err, repository.ErrNotificationTargetNotFound):
+		NotFoundError(c, "notification target")
+	case errors.As(err, &deliveryErr):
+		ErrorResponse(c, http.StatusBadGateway, deliveryErr.Code,
+			"通知傳送失敗："+deliveryErr.Message,
+			"Check the target's URL, token and recipient, then test again.")
+	default:
+		slog.Error(message, "error", err)
+		InternalServerError(c, message)
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/retry"
+	"github.com/vido/api/internal/services"
+)
+
+// mockNotificationService implements services.NotificationServiceInterface
+// via swappable funcs.
+type mockNotificationService struct {
+	listTargets  func(ctx context.Context) ([]models.NotificationTarget, error)
+	getTarget    func(ctx context.Context, id string) (*models.NotificationTarget, error)
+	createTarget func(ctx context.Context, input services.NotificationTargetInput) (*models.NotificationTarget, error)
+	updateTarget func(ctx context.Context, id string, input services.NotificationTargetInput) (*models.NotificationTarget, error)
+	deleteTarget func(ctx context.Context, id string) error
+	testTarget   func(ctx context.Context, id string) error
+}
+
+func (m *mockNotificationService) ListTargets(ctx context.Context) ([]models.NotificationTarget, error) {
+	return m.listTargets(ctx)
+}
+func (m *mockNotificationService) GetTarget(ctx context.Context, id string) (*models.NotificationTarget, error) {
+	return m.getTarget(ctx, id)
+}
+func (m *mockNotificationService) CreateTarget(ctx context.Context, input services.NotificationTargetInput) (*models.NotificationTarget, error) {
+	return m.createTarget(ctx, input)
+}
+func (m *mockNotificationService) UpdateTarget(ctx context.Context, id string, input services.NotificationTargetInput) (*models.NotificationTarget, error) {
+	return m.updateTarget(ctx, id, input)
+}
+func (m *mockNotificationService) DeleteTarget(ctx context.Context, id string) error {
+	return m.deleteTarget(ctx, id)
+}
+func (m *mockNotificationService) TestTarget(ctx context.Context, id string) error {
+	return m.testTarget(ctx, id)
+}
+
+var _ services.NotificationServiceInterface = (*mockNotificationService)(nil)
+
+func setupNotificationRouter(svc services.NotificationServiceInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewNotificationHandler(svc).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestNotificationHandler_CreateTarget(t *testing.T) {
+	var captured services.NotificationTargetInput
+	svc := &mockNotificationService{
+		createTarget: func(ctx context.Context, input services.NotificationTargetInput) (*models.NotificationTarget, error) {
+			captured = input
+			if input.Name == "" {
+				return nil, &models.ValidationError{Field: "name", Message: "name is required"}
+			}
+			return &models.NotificationTarget{ID: "t1", Name: input.Name, HasSecret: true}, nil
+		},
+	}
+	router := setupNotificationRouter(svc)
+
+	t.Run("created", func(t *testing.T) {
+		body := `{"name":"ntfy","type":"ntfy","enabled":true,"events":["backup_failed"],"config":{"topic":"vido"},"secret":"tk"}`
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/settings/notifications/targets", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, []models.NotificationEventType{models.NotificationEventBackupFailed}, captured.Events)
+		assert.Equal(t, "vido", captured.Config["topic"])
+		assert.NotContains(t, w.Body.String(), `"tk"`, "the secret is never echoed")
+	})
+
+	t.Run("validation failure is 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/settings/notifications/targets", bytes.NewBufferString(`{"type":"ntfy"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "VALIDATION_FAILED")
+	})
+}
+
+func TestNotificationHandler_TestTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{"sent", nil, http.StatusOK, "Test notification sent"},
+		{"unknown target", repository.ErrNotificationTargetNotFound, http.StatusNotFound, "DB_NOT_FOUND"},
+		{"target refused", retry.NewRetryableError("NOTIFICATION_DELIVERY_FAILED", "telegram returned status 401", false, 401),
+			http.StatusBadGateway, "NOTIFICATION_DELIVERY_FAILED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockNotificationService{
+				testTarget: func(ctx context.Context, id string) error {
+					assert.Equal(t, "t1", id)
+					return tt.err
+				},
+			}
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/settings/notifications/targets/t1/test", nil)
+			setupNotificationRouter(svc).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestNotificationHandler_ListEventTypes(t *testing.T) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/settings/notifications/events", nil)
+	setupNotificationRouter(&mockNotificationService{}).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data struct {
+			Events []string `json:"events"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Data.Events, "subtitle_failed")
+	assert.NotContains(t, resp.Data.Events, "test")
+}
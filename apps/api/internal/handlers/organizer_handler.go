@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/services"
+)
+
+// OrganizerServiceInterface defines the contract for library organize runs.
+type OrganizerServiceInterface interface {
+	Organize(ctx context.Context, libraryID string, mode services.OrganizeMode) (*services.OrganizeResult, error)
+}
+
+// OrganizerHandler handles HTTP requests that rename a library's files into
+// its naming template.
+type OrganizerHandler struct {
+	service OrganizerServiceInterface
+}
+
+// NewOrganizerHandler creates a new OrganizerHandler.
+func NewOrganizerHandler(service OrganizerServiceInterface) *OrganizerHandler {
+	return &OrganizerHandler{service: service}
+}
+
+// RegisterRoutes registers organizer routes on the given router group.
+func (h *OrganizerHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/libraries/:id/organize", h.Organize)
+}
+
+// Organize handles POST /api/v1/libraries/:id/organize
+//
+// Body: {"mode": "move" | "copy" | "hardlink" | "dry_run"}. An empty body is
+// a dry run, so the UI can preview the plan with the same call it confirms
+// with.
+func (h *OrganizerHandler) Organize(c *gin.Context) {
+	libraryID := c.Param("id")
+
+	var req struct {
+		Mode string `json:"mode"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ValidationError(c, "Invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	mode, err := services.ParseOrganizeMode(req.Mode)
+	if err != nil {
+		BadRequestError(c, "ORGANIZE_INVALID_MODE", "mode must be move, copy, hardlink or dry_run")
+		return
+	}
+
+	result, err := h.service.Organize(c.Request.Context(), libraryID, mode)
+	if err != nil {
+		var validationErr *models.ValidationError
+		switch {
+		case errors.Is(err, repository.ErrLibraryNotFound):
+			NotFoundError(c, "library")
+		case errors.Is(err, services.ErrOrganizeAlreadyRunning):
+			ErrorResponse(c, http.StatusConflict, "ORGANIZE_ALREADY_RUNNING",
+				"An organize run is already in progress",
+				"Wait for the current run to finish.")
+		case errors.As(err, &validationErr):
+			BadRequestError(c, "VALIDATION_FAILED", err.Error())
+		default:
+			slog.Error("Failed to organize library", "library_id", libraryID, "mode", mode, "error", err)
+			InternalServerError(c, "Failed to organize library")
+		}
+		return
+	}
+
+	SuccessResponse(c, result)
+}
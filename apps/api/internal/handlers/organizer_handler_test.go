@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/services"
+)
+
+type MockOrganizerService struct {
+	mock.Mock
+}
+
+func (m *MockOrganizerService) Organize(ctx context.Context, libraryID string, mode services.OrganizeMode) (*services.OrganizeResult, error) {
+	args := m.Called(ctx, libraryID, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.OrganizeResult), args.Error(1)
+}
+
+func setupOrganizerRouter(svc OrganizerServiceInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewOrganizerHandler(svc).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestOrganizerHandler_EmptyBodyIsDryRun(t *testing.T) {
+	svc := new(MockOrganizerService)
+	svc.On("Organize", mock.Anything, "lib-1", services.OrganizeModeDryRun).
+		Return(&services.OrganizeResult{LibraryID: "lib-1", Mode: services.OrganizeModeDryRun, Planned: 3}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/libraries/lib-1/organize", nil)
+	setupOrganizerRouter(svc).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Success bool                    `json:"success"`
+		Data    services.OrganizeResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	assert.Equal(t, 3, resp.Data.Planned)
+	svc.AssertExpectations(t)
+}
+
+func TestOrganizerHandler_PassesMode(t *testing.T) {
+	svc := new(MockOrganizerService)
+	svc.On("Organize", mock.Anything, "lib-1", services.OrganizeModeHardlink).
+		Return(&services.OrganizeResult{Mode: services.OrganizeModeHardlink}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/libraries/lib-1/organize", strings.NewReader(`{"mode":"hardlink"}`))
+	req.Header.Set("Content-Type", "application/json")
+	setupOrganizerRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestOrganizerHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		svcErr   error
+		wantCode int
+	}{
+		{"invalid mode", `{"mode":"symlink"}`, nil, http.StatusBadRequest},
+		{"library not found", `{"mode":"move"}`, fmt.Errorf("get library: %w", repository.ErrLibraryNotFound), http.StatusNotFound},
+		{"already running", `{"mode":"move"}`, services.ErrOrganizeAlreadyRunning, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockOrganizerService)
+			if tt.svcErr != nil {
+				svc.On("Organize", mock.Anything, "lib-1", mock.Anything).Return(nil, tt.svcErr)
+			}
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/libraries/lib-1/organize", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			setupOrganizerRouter(svc).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package handlers — QualityProfileHandler.
+//
+// CRUD for Vido-native quality profiles plus an on-demand evaluation pass.
+// Everything lives under /settings/quality-profiles, so the routes are
+// admin-only through AdminRoutes. A profile is assigned to a library through
+// the media-library PUT (quality_profile_id).
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/services"
+)
+
+// errCodeQualityEvaluationRunning is a code-list addition under the
+// registered LIBRARY_ prefix.
+const errCodeQualityEvaluationRunning = "LIBRARY_QUALITY_EVALUATION_RUNNING"
+
+// QualityProfileHandler handles HTTP requests for quality profiles.
+type QualityProfileHandler struct {
+	service   services.QualityProfileServiceInterface
+	evaluator services.QualityUpgradeServiceInterface
+}
+
+// NewQualityProfileHandler creates a new QualityProfileHandler.
+func NewQualityProfileHandler(service services.QualityProfileServiceInterface, evaluator services.QualityUpgradeServiceInterface) *QualityProfileHandler {
+	return &QualityProfileHandler{service: service, evaluator: evaluator}
+}
+
+// RegisterRoutes registers the quality profile routes.
+func (h *QualityProfileHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	group := rg.Group("/settings/quality-profiles")
+	{
+		group.GET("", h.ListProfiles)
+		group.POST("", h.CreateProfile)
+		group.POST("/evaluate", h.Evaluate)
+		group.GET("/:id", h.GetProfile)
+		group.PUT("/:id", h.UpdateProfile)
+		group.DELETE("/:id", h.DeleteProfile)
+	}
+}
+
+// ListProfiles handles GET /api/v1/settings/quality-profiles
+// @Summary List quality profiles
+// @Tags quality-profiles
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]models.QualityProfile}
+// @Router /api/v1/settings/quality-profiles [get]
+func (h *QualityProfileHandler) ListProfiles(c *gin.Context) {
+	profiles, err := h.service.ListProfiles(c.Request.Context())
+	if err != nil {
+		handleQualityProfileError(c, "Failed to list quality profiles", err)
+		return
+	}
+	if profiles == nil {
+		profiles = []models.QualityProfile{}
+	}
+	SuccessResponse(c, profiles)
+}
+
+// GetProfile handles GET /api/v1/settings/quality-profiles/:id
+// @Summary Get one quality profile
+// @Tags quality-profiles
+// @Produce json
+// @Success 200 {object} APIResponse{data=models.QualityProfile}
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/settings/quality-profiles/{id} [get]
+func (h *QualityProfileHandler) GetProfile(c *gin.Context) {
+	profile, err := h.service.GetProfile(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleQualityProfileError(c, "Failed to load quality profile", err)
+		return
+	}
+	SuccessResponse(c, profile)
+}
+
+// CreateProfile handles POST /api/v1/settings/quality-profiles
+// @Summary Create a quality profile
+// @Tags quality-profiles
+// @Accept json
+// @Produce json
+// @Success 201 {object} APIResponse{data=models.QualityProfile}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR | VALIDATION_FAILED"
+// @Router /api/v1/settings/quality-profiles [post]
+func (h *QualityProfileHandler) CreateProfile(c *gin.Context) {
+	var input services.QualityProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	profile, err := h.service.CreateProfile(c.Request.Context(), input)
+	if err != nil {
+		handleQualityProfileError(c, "Failed to create quality profile", err)
+		return
+	}
+	CreatedResponse(c, profile)
+}
+
+// UpdateProfile handles PUT /api/v1/settings/quality-profiles/:id
+// @Summary Replace a quality profile's settings
+// @Tags quality-profiles
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse{data=models.QualityProfile}
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR | VALIDATION_FAILED"
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/settings/quality-profiles/{id} [put]
+func (h *QualityProfileHandler) UpdateProfile(c *gin.Context) {
+	var input services.QualityProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	profile, err := h.service.UpdateProfile(c.Request.Context(), c.Param("id"), input)
+	if err != nil {
+		handleQualityProfileError(c, "Failed to update quality profile", err)
+		return
+	}
+	SuccessResponse(c, profile)
+}
+
+// DeleteProfile handles DELETE /api/v1/settings/quality-profiles/:id
+// @Summary Delete a quality profile; libraries using it are left without one
+// @Tags quality-profiles
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 404 {object} APIResponse "DB_NOT_FOUND"
+// @Router /api/v1/settings/quality-profiles/{id} [delete]
+func (h *QualityProfileHandler) DeleteProfile(c *gin.Context) {
+	if err := h.service.DeleteProfile(c.Request.Context(), c.Param("id")); err != nil {
+		handleQualityProfileError(c, "Failed to delete quality profile", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Quality profile deleted"})
+}
+
+// Evaluate handles POST /api/v1/settings/quality-profiles/evaluate
+// @Summary Judge every owned file against its library's profile now and act on shortfalls
+// @Description Runs synchronously; the same pass also runs once a day.
+// @Tags quality-profiles
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.QualityEvaluation}
+// @Failure 409 {object} APIResponse "LIBRARY_QUALITY_EVALUATION_RUNNING"
+// @Router /api/v1/settings/quality-profiles/evaluate [post]
+func (h *QualityProfileHandler) Evaluate(c *gin.Context) {
+	result, err := h.evaluator.Evaluate(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrQualityEvaluationRunning) {
+			ErrorResponse(c, http.StatusConflict, errCodeQualityEvaluationRunning,
+				"品質評估正在進行中。", "等待目前的評估結束後再試。")
+			return
+		}
+		handleQualityProfileError(c, "Failed to evaluate library quality", err)
+		return
+	}
+	SuccessResponse(c, result)
+}
+
+func handleQualityProfileError(c *gin.Context, message string, err error) {
+	var validationErr *models.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		BadRequestError(c, "VALIDATION_FAILED", err.Error())
+	case errors.Is(err, repository.ErrQualityProfileNotFound):
+		NotFoundError(c, "quality profile")
+	default:
+		slog.Error(message, "error", err)
+		InternalServerError(c, message)
+	}
+}
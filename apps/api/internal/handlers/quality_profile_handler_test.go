@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/services"
+)
+
+// mockQualityProfileService implements services.QualityProfileServiceInterface
+// and services.QualityUpgradeServiceInterface via swappable funcs.
+type mockQualityProfileService struct {
+	listProfiles  func(ctx context.Context) ([]models.QualityProfile, error)
+	getProfile    func(ctx context.Context, id string) (*models.QualityProfile, error)
+	createProfile func(ctx context.Context, input services.QualityProfileInput) (*models.QualityProfile, error)
+	updateProfile func(ctx context.Context, id string, input services.QualityProfileInput) (*models.QualityProfile, error)
+	deleteProfile func(ctx context.Context, id string) error
+	evaluate      func(ctx context.Context) (*services.QualityEvaluation, error)
+}
+
+func (m *mockQualityProfileService) ListProfiles(ctx context.Context) ([]models.QualityProfile, error) {
+	return m.listProfiles(ctx)
+}
+func (m *mockQualityProfileService) GetProfile(ctx context.Context, id string) (*models.QualityProfile, error) {
+	return m.getProfile(ctx, id)
+}
+func (m *mockQualityProfileService) CreateProfile(ctx context.Context, input services.QualityProfileInput) (*models.QualityProfile, error) {
+	return m.createProfile(ctx, input)
+}
+func (m *mockQualityProfileService) UpdateProfile(ctx context.Context, id string, input services.QualityProfileInput) (*models.QualityProfile, error) {
+	return m.updateProfile(ctx, id, input)
+}
+func (m *mockQualityProfileService) DeleteProfile(ctx context.Context, id string) error {
+	return m.deleteProfile(ctx, id)
+}
+func (m *mockQualityProfileService) Evaluate(ctx context.Context) (*services.QualityEvaluation, error) {
+	return m.evaluate(ctx)
+}
+
+var (
+	_ services.QualityProfileServiceInterface = (*mockQualityProfileService)(nil)
+	_ services.QualityUpgradeServiceInterface = (*mockQualityProfileService)(nil)
+)
+
+func setupQualityProfileRouter(svc *mockQualityProfileService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewQualityProfileHandler(svc, svc).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestQualityProfileHandler_CreateProfile(t *testing.T) {
+	var captured services.QualityProfileInput
+	svc := &mockQualityProfileService{
+		createProfile: func(ctx context.Context, input services.QualityProfileInput) (*models.QualityProfile, error) {
+			captured = input
+			if input.Cutoff == "8k" {
+				return nil, &models.ValidationError{Field: "cutoff", Message: "unknown cutoff resolution: 8k"}
+			}
+			return &models.QualityProfile{ID: "p1", Name: input.Name, Cutoff: input.Cutoff}, nil
+		},
+	}
+	router := setupQualityProfileRouter(svc)
+
+	t.Run("created", func(t *testing.T) {
+		body := `{"name":"HD","resolutions":["1080p","2160p"],"codecs":["x265"],"cutoff":"1080p"}`
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/settings/quality-profiles", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, []string{"1080p", "2160p"}, captured.Resolutions)
+		assert.Equal(t, []string{"x265"}, captured.Codecs)
+	})
+
+	t.Run("validation failure is 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/settings/quality-profiles", bytes.NewBufferString(`{"name":"X","cutoff":"8k"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var resp APIResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "VALIDATION_FAILED", resp.Error.Code)
+	})
+}
+
+func TestQualityProfileHandler_NotFound(t *testing.T) {
+	notFound := fmt.Errorf("quality profile with id nope: %w", repository.ErrQualityProfileNotFound)
+	svc := &mockQualityProfileService{
+		getProfile:    func(ctx context.Context, id string) (*models.QualityProfile, error) { return nil, notFound },
+		deleteProfile: func(ctx context.Context, id string) error { return notFound },
+	}
+	router := setupQualityProfileRouter(svc)
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/api/v1/settings/quality-profiles/nope", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, method)
+	}
+}
+
+func TestQualityProfileHandler_Evaluate(t *testing.T) {
+	t.Run("returns the pass", func(t *testing.T) {
+		svc := &mockQualityProfileService{
+			evaluate: func(ctx context.Context) (*services.QualityEvaluation, error) {
+				return &services.QualityEvaluation{EvaluatedFiles: 3, Upgrades: []services.QualityUpgrade{
+					{MediaType: models.RequestMediaTypeMovie, TMDbID: 550, Action: services.UpgradeActionRequested},
+				}}, nil
+			},
+		}
+		w := httptest.NewRecorder()
+		setupQualityProfileRouter(svc).ServeHTTP(w,
+			httptest.NewRequest(http.MethodPost, "/api/v1/settings/quality-profiles/evaluate", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"evaluated_files":3`)
+		assert.Contains(t, w.Body.String(), `"action":"upgrade_requested"`)
+	})
+
+	t.Run("a pass in progress is 409", func(t *testing.T) {
+		svc := &mockQualityProfileService{
+			evaluate: func(ctx context.Context) (*services.QualityEvaluation, error) {
+				return nil, services.ErrQualityEvaluationRunning
+			},
+		}
+		w := httptest.NewRecorder()
+		setupQualityProfileRouter(svc).ServeHTTP(w,
+			httptest.NewRequest(http.MethodPost, "/api/v1/settings/quality-profiles/evaluate", nil))
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), errCodeQualityEvaluationRunning)
+	})
+}
@@ -31,6 +31,7 @@ type SubtitleHandler struct {
 	movieRepo      subtitle.SubtitleStatusUpdater
 	seriesRepo     subtitle.SubtitleStatusUpdater
 	batchProcessor *subtitle.BatchProcessor
+	aligner        subtitle.SubtitleAligner
 }
 
 // NewSubtitleHandler creates a new SubtitleHandler.
@@ -59,6 +60,12 @@ func (h *SubtitleHandler) SetBatchProcessor(bp *subtitle.BatchProcessor) {
 	h.batchProcessor = bp
 }
 
+// SetAligner sets the optional timing aligner: a manual download is re-timed
+// against its media file before placement, and /subtitles/sync is enabled.
+func (h *SubtitleHandler) SetAligner(a subtitle.SubtitleAligner) {
+	h.aligner = a
+}
+
 // RegisterRoutes registers subtitle routes on the given router group.
 func (h *SubtitleHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	subtitles := rg.Group("/subtitles")
@@ -67,6 +74,7 @@ func (h *SubtitleHandler) RegisterRoutes(rg *gin.RouterGroup) {
 		subtitles.POST("/download", h.DownloadSubtitle)
 		subtitles.POST("/preview", h.PreviewSubtitle)
 		subtitles.POST("/convert", h.ConvertSubtitle)
+		subtitles.POST("/sync", h.SyncSubtitle)
 		subtitles.POST("/batch", h.StartBatch)
 		subtitles.GET("/batch/status", h.GetBatchStatus)
 		subtitles.POST("/batch/cancel", h.CancelBatch)
@@ -81,6 +89,9 @@ type SubtitleSearchRequest struct {
 	MediaType string   `json:"media_type" binding:"required,oneof=movie series"`
 	Providers []string `json:"providers"`
 	Query     string   `json:"query"`
+	// MediaFilePath is optional; when set, the file's release hash is sent to
+	// hash-capable providers so results for the exact release rank first.
+	MediaFilePath string `json:"media_file_path"`
 }
 
 // SubtitleSearchResultDTO is the snake_case JSON response for a scored result.
@@ -96,11 +107,13 @@ type SubtitleSearchResultDTO struct {
 	Format         string                     `json:"format"`
 	Score          float64                    `json:"score"`
 	ScoreBreakdown *SubtitleScoreBreakdownDTO `json:"score_breakdown"`
+	HashMatch      bool                       `json:"hash_match"`
 }
 
 // SubtitleScoreBreakdownDTO is the snake_case JSON score breakdown.
 type SubtitleScoreBreakdownDTO struct {
 	Language    float64 `json:"language"`
+	Hash        float64 `json:"hash"`
 	Resolution  float64 `json:"resolution"`
 	SourceTrust float64 `json:"source_trust"`
 	Group       float64 `json:"group"`
@@ -135,6 +148,36 @@ type SubtitleConvertRequest struct {
 	SourceLanguage string `json:"source_language"`
 }
 
+// SubtitleSyncRequest is the request body for re-timing an existing sidecar
+// against its media file. Reference is auto (an embedded text track),
+// embedded, or audio — naming audio is the consent to wait on an ASR run over
+// the whole file, and to pay for it when the ASR is not self-hosted. DryRun
+// reports the fit without writing it.
+type SubtitleSyncRequest struct {
+	MediaID       string `json:"media_id" binding:"required"`
+	MediaType     string `json:"media_type" binding:"required,oneof=movie series"`
+	MediaFilePath string `json:"media_file_path" binding:"required"`
+	SubtitlePath  string `json:"subtitle_path" binding:"required"`
+	Reference     string `json:"reference" binding:"omitempty,oneof=auto embedded audio"`
+	DryRun        bool   `json:"dry_run"`
+}
+
+// SubtitleSyncResponse is the fit a sync found and whether it was written.
+// Confidence is 0..1; a fit below the aligner's threshold is reported but
+// never applied.
+type SubtitleSyncResponse struct {
+	SubtitlePath     string  `json:"subtitle_path"`
+	BackupPath       string  `json:"backup_path,omitempty"`
+	Reference        string  `json:"reference"`
+	Method           string  `json:"method"`
+	OffsetMs         int64   `json:"offset_ms"`
+	Scale            float64 `json:"scale"`
+	Segments         int     `json:"segments"`
+	Confidence       float64 `json:"confidence"`
+	BeforeConfidence float64 `json:"before_confidence"`
+	Applied          bool    `json:"applied"`
+}
+
 // --- Handlers ---
 
 // SearchSubtitles handles POST /api/v1/subtitles/search
@@ -159,6 +202,7 @@ func (h *SubtitleHandler) SearchSubtitles(c *gin.Context) {
 	if query.Title == "" {
 		query.Title = req.MediaID
 	}
+	query = subtitle.AttachFileHash(query, req.MediaFilePath)
 
 	// Search all selected providers in parallel (AC #2)
 	var (
@@ -202,8 +246,10 @@ func (h *SubtitleHandler) SearchSubtitles(c *gin.Context) {
 			Resolution:  s.Resolution,
 			Format:      s.Format,
 			Score:       s.Score,
+			HashMatch:   s.HashMatch,
 			ScoreBreakdown: &SubtitleScoreBreakdownDTO{
 				Language:    s.ScoreBreakdown.Language,
+				Hash:        s.ScoreBreakdown.Hash,
 				Resolution:  s.ScoreBreakdown.Resolution,
 				SourceTrust: s.ScoreBreakdown.SourceTrust,
 				Group:       s.ScoreBreakdown.Group,
@@ -271,6 +317,23 @@ func (h *SubtitleHandler) DownloadSubtitle(c *gin.Context) {
 		}
 	}
 
+	// Re-time onto this release when an embedded track allows it; anything
+	// short of a confident fit places the subtitle as downloaded. The speech
+	// reference is left to /subtitles/sync — it would hold this request for
+	// a transcription of the whole film.
+	if h.aligner != nil {
+		out, alignErr := h.aligner.Align(c.Request.Context(), subtitle.AlignRequest{
+			MediaID:   req.MediaID,
+			MediaPath: req.MediaFilePath,
+			Data:      finalData,
+		})
+		if alignErr != nil {
+			slog.Debug("Manual download placed without alignment", "media_id", req.MediaID, "error", alignErr)
+		} else {
+			finalData = out.Data
+		}
+	}
+
 	// Place the subtitle file (AC #5, #8)
 	h.broadcastStatus(req.MediaID, req.MediaType, "placing", "Placing subtitle file...")
 
@@ -461,6 +524,101 @@ func (h *SubtitleHandler) ConvertSubtitle(c *gin.Context) {
 	})
 }
 
+// SyncSubtitle handles POST /api/v1/subtitles/sync.
+//
+// It re-times an existing sidecar against the media file it sits beside and,
+// unless dry_run is set, rewrites it in place (the old file kept as .bak).
+// The subtitle must live in the media file's directory and carry a subtitle
+// extension, so the endpoint cannot be pointed at any other file.
+func (h *SubtitleHandler) SyncSubtitle(c *gin.Context) {
+	var req SubtitleSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if h.aligner == nil {
+		ErrorResponse(c, 503, "SUBTITLE_SYNC_UNAVAILABLE",
+			"字幕時間軸校正目前無法使用",
+			"請確認伺服器已啟用 ffprobe/ffmpeg。")
+		return
+	}
+
+	mediaPath := filepath.Clean(req.MediaFilePath)
+	subtitlePath := filepath.Clean(req.SubtitlePath)
+	if !filepath.IsAbs(mediaPath) || !filepath.IsAbs(subtitlePath) {
+		BadRequestError(c, "VALIDATION_INVALID_FORMAT", "media_file_path and subtitle_path must be absolute paths")
+		return
+	}
+	if filepath.Dir(subtitlePath) != filepath.Dir(mediaPath) {
+		BadRequestError(c, "VALIDATION_INVALID_FORMAT", "subtitle_path must be in the same directory as the media file")
+		return
+	}
+	format, ok := subtitle.ParseCueFormat(filepath.Ext(subtitlePath))
+	if !ok {
+		BadRequestError(c, "VALIDATION_INVALID_FORMAT", "subtitle_path must be an .srt, .ass, .ssa or .vtt file")
+		return
+	}
+
+	data, err := os.ReadFile(subtitlePath)
+	if err != nil {
+		ErrorResponse(c, 404, "SUBTITLE_NOT_FOUND",
+			"找不到要校正的字幕檔",
+			"請確認字幕檔存在於媒體檔案旁。")
+		return
+	}
+
+	out, err := h.aligner.Align(c.Request.Context(), subtitle.AlignRequest{
+		MediaID:   req.MediaID,
+		MediaPath: mediaPath,
+		Data:      data,
+		Format:    string(format),
+		Reference: req.Reference,
+		AllowPaid: req.Reference == subtitle.ReferenceAudio,
+		AllowSlow: req.Reference == subtitle.ReferenceAudio,
+	})
+	if errors.Is(err, subtitle.ErrSubtitleNoTimingReference) {
+		suggestion := "此檔案沒有內嵌文字字幕可供對時；可改用 reference=audio 以語音辨識對時（可能產生 AI 費用）。"
+		if req.Reference == subtitle.ReferenceAudio {
+			suggestion = "請確認已設定語音辨識服務，且 AI 花費尚未達到上限。"
+		}
+		ErrorResponse(c, 422, "SUBTITLE_NO_TIMING_REFERENCE",
+			"找不到可用來對時的參考："+err.Error(), suggestion)
+		return
+	}
+	if err != nil {
+		ErrorResponse(c, 422, "SUBTITLE_SYNC_FAILED",
+			"無法解析字幕檔："+err.Error(),
+			"請確認字幕檔格式正確。")
+		return
+	}
+
+	resp := SubtitleSyncResponse{
+		SubtitlePath:     subtitlePath,
+		Reference:        out.Reference,
+		Method:           string(out.Method),
+		OffsetMs:         out.Offset.Milliseconds(),
+		Scale:            out.Scale,
+		Segments:         out.Segments,
+		Confidence:       out.Confidence,
+		BeforeConfidence: out.Before,
+	}
+	if out.Applied && !req.DryRun {
+		result, err := h.placer.Rewrite(subtitlePath, out.Data)
+		if err != nil {
+			slog.Error("Failed to rewrite synced subtitle", "path", subtitlePath, "error", err)
+			ErrorResponse(c, 500, "SUBTITLE_PLACE_FAILED",
+				"無法寫入校正後的字幕檔："+err.Error(),
+				"請確認檔案權限與磁碟空間。")
+			return
+		}
+		resp.BackupPath = result.BackupPath
+		resp.Applied = true
+	}
+
+	SuccessResponse(c, resp)
+}
+
 // --- CN Conversion Policy (AC #9, #10, #11) ---
 
 // shouldConvert determines whether to apply S→T conversion.
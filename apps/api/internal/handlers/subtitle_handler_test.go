@@ -1321,3 +1321,145 @@ func convErrCode(t *testing.T, w *httptest.ResponseRecorder) string {
 	require.NotNil(t, resp.Error)
 	return resp.Error.Code
 }
+
+// --- POST /subtitles/sync ---
+
+type stubSyncAligner struct {
+	out  *subtitle.AlignOutcome
+	err  error
+	reqs []subtitle.AlignRequest
+}
+
+func (s *stubSyncAligner) Align(_ context.Context, req subtitle.AlignRequest) (*subtitle.AlignOutcome, error) {
+	s.reqs = append(s.reqs, req)
+	return s.out, s.err
+}
+
+func setupSyncHandler(t *testing.T, aligner subtitle.SubtitleAligner) (*gin.Engine, string) {
+	t.Helper()
+	router, _, mediaPath := setupConvertHandler(t, false)
+	if aligner != nil {
+		handler := NewSubtitleHandler(nil, subtitle.NewScorer(subtitle.NewDefaultScorerConfig()),
+			nil, subtitle.NewPlacer(subtitle.DefaultPlacerConfig()), nil, &mockStatusUpdater{}, nil)
+		handler.SetAligner(aligner)
+		router = gin.New()
+		handler.RegisterRoutes(router.Group("/api/v1"))
+	}
+	return router, mediaPath
+}
+
+func doSync(t *testing.T, router *gin.Engine, req SubtitleSyncRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/subtitles/sync", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+	return w
+}
+
+func appliedOutcome(data string) *subtitle.AlignOutcome {
+	return &subtitle.AlignOutcome{
+		Alignment: subtitle.Alignment{
+			Method: subtitle.AlignOffset, Offset: -2500 * time.Millisecond, Scale: 1,
+			Confidence: 0.87, Before: 0.12,
+		},
+		Reference: subtitle.ReferenceEmbedded,
+		Applied:   true,
+		Data:      []byte(data),
+	}
+}
+
+func TestSubtitleHandler_Sync_RewritesTheSidecarWithABackup(t *testing.T) {
+	aligner := &stubSyncAligner{out: appliedOutcome("retimed")}
+	router, mediaPath := setupSyncHandler(t, aligner)
+	subPath := writeSidecar(t, mediaPath, "zh-Hant", "srt", "original")
+
+	w := doSync(t, router, SubtitleSyncRequest{
+		MediaID: "movie-1", MediaType: "movie", MediaFilePath: mediaPath, SubtitlePath: subPath,
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data SubtitleSyncResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.Applied)
+	assert.Equal(t, "offset", resp.Data.Method)
+	assert.Equal(t, int64(-2500), resp.Data.OffsetMs)
+	assert.Equal(t, 0.87, resp.Data.Confidence)
+	assert.Equal(t, subPath+".bak", resp.Data.BackupPath)
+
+	written, _ := os.ReadFile(subPath)
+	assert.Equal(t, "retimed", string(written))
+	require.Len(t, aligner.reqs, 1)
+	assert.Equal(t, "srt", aligner.reqs[0].Format)
+	assert.False(t, aligner.reqs[0].AllowPaid, "auto uses the free references only")
+	assert.False(t, aligner.reqs[0].AllowSlow, "auto never waits on a transcription")
+}
+
+func TestSubtitleHandler_Sync_DryRunWritesNothing(t *testing.T) {
+	router, mediaPath := setupSyncHandler(t, &stubSyncAligner{out: appliedOutcome("retimed")})
+	subPath := writeSidecar(t, mediaPath, "zh-Hant", "ass", "original")
+
+	w := doSync(t, router, SubtitleSyncRequest{
+		MediaID: "movie-1", MediaType: "movie", MediaFilePath: mediaPath, SubtitlePath: subPath, DryRun: true,
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	written, _ := os.ReadFile(subPath)
+	assert.Equal(t, "original", string(written))
+	assert.NoFileExists(t, subPath+".bak")
+}
+
+// TestSubtitleHandler_Sync_AudioIsTheConsentToSpend — naming the audio
+// reference is what lets a paid ASR run.
+func TestSubtitleHandler_Sync_AudioIsTheConsentToSpend(t *testing.T) {
+	aligner := &stubSyncAligner{err: subtitle.ErrSubtitleNoTimingReference}
+	router, mediaPath := setupSyncHandler(t, aligner)
+	subPath := writeSidecar(t, mediaPath, "zh-Hant", "srt", "original")
+
+	w := doSync(t, router, SubtitleSyncRequest{
+		MediaID: "movie-1", MediaType: "movie", MediaFilePath: mediaPath, SubtitlePath: subPath,
+		Reference: "audio",
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "SUBTITLE_NO_TIMING_REFERENCE", convErrCode(t, w))
+	require.Len(t, aligner.reqs, 1)
+	assert.True(t, aligner.reqs[0].AllowPaid)
+	assert.True(t, aligner.reqs[0].AllowSlow)
+	assert.Equal(t, subtitle.ReferenceAudio, aligner.reqs[0].Reference)
+}
+
+func TestSubtitleHandler_Sync_RejectsFilesOutsideTheMediaDirectory(t *testing.T) {
+	aligner := &stubSyncAligner{out: appliedOutcome("retimed")}
+	router, mediaPath := setupSyncHandler(t, aligner)
+	dir := filepath.Dir(mediaPath)
+
+	for name, subPath := range map[string]string{
+		"other directory": filepath.Join(t.TempDir(), "movie.zh-Hant.srt"),
+		"traversal":       filepath.Join(dir, "..", "movie.zh-Hant.srt"),
+		"not a subtitle":  filepath.Join(dir, "movie.nfo"),
+		"relative":        "movie.zh-Hant.srt",
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := doSync(t, router, SubtitleSyncRequest{
+				MediaID: "movie-1", MediaType: "movie", MediaFilePath: mediaPath, SubtitlePath: subPath,
+			})
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+	assert.Empty(t, aligner.reqs)
+}
+
+func TestSubtitleHandler_Sync_Unavailable(t *testing.T) {
+	router, mediaPath := setupSyncHandler(t, nil)
+
+	w := doSync(t, router, SubtitleSyncRequest{
+		MediaID: "movie-1", MediaType: "movie", MediaFilePath: mediaPath,
+		SubtitlePath: filepath.Join(filepath.Dir(mediaPath), "movie.zh-Hant.srt"),
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "SUBTITLE_SYNC_UNAVAILABLE", convErrCode(t, w))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/vido/api/internal/models"
 	"github.com/vido/api/internal/subtitle"
 )
 
@@ -62,6 +63,9 @@ type SubtitlePipelineRunRequest struct {
 	// Force is FR32's re-run switch: bypass the P5 pre-flight and the segment
 	// cache READS for this one item.
 	Force bool `json:"force"`
+	// OutputMode overrides the library's subtitle layout for this run:
+	// translated, bilingual_srt or bilingual_ass. Empty uses the library's.
+	OutputMode models.SubtitleOutputMode `json:"output_mode"`
 }
 
 // SubtitlePipelineRunResponse is the 202 payload.
@@ -78,9 +82,9 @@ type SubtitlePipelineRunResponse struct {
 // @Tags subtitles
 // @Accept json
 // @Produce json
-// @Param request body SubtitlePipelineRunRequest true "media_id + media_type (movie|series|episode); force re-runs an item that already has a sidecar"
+// @Param request body SubtitlePipelineRunRequest true "media_id + media_type (movie|series|episode); force re-runs an item that already has a sidecar; output_mode overrides the library's layout"
 // @Success 202 {object} APIResponse "queued: {status: queued|already_queued, media_id}"
-// @Failure 400 {object} APIResponse "VALIDATION_INVALID_FORMAT — missing media_id or unknown media_type"
+// @Failure 400 {object} APIResponse "VALIDATION_INVALID_FORMAT — missing media_id, unknown media_type or unknown output_mode"
 // @Failure 404 {object} APIResponse "DB_NOT_FOUND — no such media row"
 // @Failure 409 {object} APIResponse "AI_NOT_CONFIGURED — no translation key, or the pipeline is not enabled"
 // @Failure 500 {object} APIResponse "DB_QUERY_FAILED — the media lookup itself failed"
@@ -93,6 +97,11 @@ func (h *SubtitlePipelineHandler) RunPipeline(c *gin.Context) {
 			"請求格式錯誤：media_id 為必填，media_type 必須是 movie、series 或 episode")
 		return
 	}
+	if !req.OutputMode.IsValid() {
+		BadRequestError(c, "VALIDATION_INVALID_FORMAT",
+			"請求格式錯誤：output_mode 必須是 translated、bilingual_srt 或 bilingual_ass")
+		return
+	}
 
 	// ── AC #5: the capability gate, checked BEFORE any DB work ──────────────
 	// An unconfigured install must not pay a query per request, and must never
@@ -135,7 +144,7 @@ func (h *SubtitlePipelineHandler) RunPipeline(c *gin.Context) {
 	// promise that the work is happening, so it is reserved for the genuine
 	// duplicate — a dropped or refused item must never wear it.
 	var status string
-	switch h.queue.EnqueueItem(ref, subtitle.ProcessItemOptions{Force: req.Force}) {
+	switch h.queue.EnqueueItem(ref, subtitle.ProcessItemOptions{Force: req.Force, OutputMode: req.OutputMode}) {
 	case subtitle.EnqueueAccepted:
 		status = "queued"
 	case subtitle.EnqueueDuplicate:
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/vido/api/internal/models"
 	"github.com/vido/api/internal/subtitle"
 )
 
@@ -152,6 +153,28 @@ func TestRunPipeline_ForceRidesThroughToTheQueue(t *testing.T) {
 	assert.True(t, queue.opts[0].Force, "FR32's re-run switch must reach ProcessItem")
 }
 
+func TestRunPipeline_OutputModeRidesThroughToTheQueue(t *testing.T) {
+	queue := runningQueue()
+	h := NewSubtitlePipelineHandler(queue, foundMedia(), func() bool { return true })
+
+	w := postRun(t, h, `{"media_id":"`+runMovieID+`","media_type":"movie","output_mode":"bilingual_ass"}`)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, queue.opts, 1)
+	assert.Equal(t, models.SubtitleOutputBilingualASS, queue.opts[0].OutputMode)
+}
+
+func TestRunPipeline_RejectsUnknownOutputMode(t *testing.T) {
+	queue := runningQueue()
+	h := NewSubtitlePipelineHandler(queue, foundMedia(), func() bool { return true })
+
+	w := postRun(t, h, `{"media_id":"`+runMovieID+`","media_type":"movie","output_mode":"dual"}`)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, queue.calls)
+	assert.Equal(t, "VALIDATION_INVALID_FORMAT", decodeEnvelope(t, w).Error.Code)
+}
+
 func TestRunPipeline_RejectsUnknownMediaType(t *testing.T) {
 	queue := runningQueue()
 	h := NewSubtitlePipelineHandler(queue, foundMedia(), func() bool { return true })
@@ -0,0 +1,175 @@
+// Package handlers — TraktHandler.
+//
+// Trakt.tv settings and actions: the API application, linking an account
+// through the device-code flow, unlinking, and a sync on demand. Everything
+// lives under /settings/trakt, so the routes are admin-only through
+// AdminRoutes. The account is linked to the admin who entered the code.
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/services"
+	"github.com/vido/api/internal/trakt"
+)
+
+// TraktHandler handles HTTP requests for the Trakt integration.
+type TraktHandler struct {
+	service services.TraktServiceInterface
+}
+
+// NewTraktHandler creates a new TraktHandler.
+func NewTraktHandler(service services.TraktServiceInterface) *TraktHandler {
+	return &TraktHandler{service: service}
+}
+
+// RegisterRoutes registers the Trakt routes.
+func (h *TraktHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	group := rg.Group("/settings/trakt")
+	{
+		group.GET("", h.GetStatus)
+		group.PUT("", h.SaveConfig)
+		group.POST("/device-code", h.StartDeviceAuth)
+		group.POST("/device-token", h.PollDeviceAuth)
+		group.DELETE("/connection", h.Disconnect)
+		group.POST("/sync", h.Sync)
+	}
+}
+
+// GetStatus handles GET /api/v1/settings/trakt
+// @Summary Get the Trakt application and link state
+// @Tags trakt
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.TraktStatus}
+// @Router /api/v1/settings/trakt [get]
+func (h *TraktHandler) GetStatus(c *gin.Context) {
+	status, err := h.service.GetStatus(c.Request.Context())
+	if err != nil {
+		handleTraktError(c, "Failed to load Trakt settings", err)
+		return
+	}
+	SuccessResponse(c, status)
+}
+
+// SaveConfig handles PUT /api/v1/settings/trakt
+// @Summary Save the Trakt API application
+// @Description An empty client_secret keeps the stored one.
+// @Tags trakt
+// @Accept json
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse "VALIDATION_ERROR | VALIDATION_FAILED"
+// @Router /api/v1/settings/trakt [put]
+func (h *TraktHandler) SaveConfig(c *gin.Context) {
+	var input services.TraktConfigInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+	if err := h.service.SaveConfig(c.Request.Context(), input); err != nil {
+		handleTraktError(c, "Failed to save Trakt settings", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Configuration saved"})
+}
+
+// StartDeviceAuth handles POST /api/v1/settings/trakt/device-code
+// @Summary Start linking a Trakt account
+// @Description Returns the code to enter at verification_url. Poll device-token
+// @Description every interval seconds until it answers 200.
+// @Tags trakt
+// @Produce json
+// @Success 200 {object} APIResponse{data=trakt.DeviceCode}
+// @Failure 400 {object} APIResponse "TRAKT_NOT_CONFIGURED"
+// @Router /api/v1/settings/trakt/device-code [post]
+func (h *TraktHandler) StartDeviceAuth(c *gin.Context) {
+	code, err := h.service.StartDeviceAuth(c.Request.Context(), watchUserID(c))
+	if err != nil {
+		handleTraktError(c, "Failed to start Trakt authorization", err)
+		return
+	}
+	SuccessResponse(c, code)
+}
+
+// PollDeviceAuth handles POST /api/v1/settings/trakt/device-token
+// @Summary Check whether the Trakt code was entered
+// @Description 202 while waiting for the user; 200 with the linked status once done.
+// @Tags trakt
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.TraktStatus}
+// @Success 202 {object} APIResponse
+// @Failure 400 {object} APIResponse "TRAKT_AUTH_EXPIRED | TRAKT_AUTH_DENIED | VALIDATION_FAILED"
+// @Router /api/v1/settings/trakt/device-token [post]
+func (h *TraktHandler) PollDeviceAuth(c *gin.Context) {
+	status, err := h.service.PollDeviceAuth(c.Request.Context())
+	if errors.Is(err, services.ErrTraktAuthorizationPending) {
+		c.JSON(http.StatusAccepted, APIResponse{Success: true, Data: gin.H{"pending": true}})
+		return
+	}
+	if err != nil {
+		handleTraktError(c, "Failed to complete Trakt authorization", err)
+		return
+	}
+	SuccessResponse(c, status)
+}
+
+// Disconnect handles DELETE /api/v1/settings/trakt/connection
+// @Summary Unlink the Trakt account
+// @Description Imported history and ratings are kept.
+// @Tags trakt
+// @Produce json
+// @Success 200 {object} APIResponse
+// @Router /api/v1/settings/trakt/connection [delete]
+func (h *TraktHandler) Disconnect(c *gin.Context) {
+	if err := h.service.Disconnect(c.Request.Context()); err != nil {
+		handleTraktError(c, "Failed to unlink Trakt", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "Trakt account unlinked"})
+}
+
+// Sync handles POST /api/v1/settings/trakt/sync
+// @Summary Sync with Trakt now
+// @Tags trakt
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.TraktSyncResult}
+// @Failure 400 {object} APIResponse "TRAKT_NOT_CONNECTED"
+// @Failure 502 {object} APIResponse "TRAKT_UNAUTHORIZED | TRAKT_UNAVAILABLE | TRAKT_RATE_LIMIT"
+// @Router /api/v1/settings/trakt/sync [post]
+func (h *TraktHandler) Sync(c *gin.Context) {
+	result, err := h.service.Sync(c.Request.Context())
+	if err != nil {
+		handleTraktError(c, "Trakt sync failed", err)
+		return
+	}
+	SuccessResponse(c, result)
+}
+
+func handleTraktError(c *gin.Context, message string, err error) {
+	var validationErr *models.ValidationError
+	var traktErr *trakt.Error
+	switch {
+	case errors.As(err, &validationErr):
+		BadRequestError(c, "VALIDATION_FAILED", err.Error())
+	case errors.Is(err, services.ErrTraktNotConnected):
+		BadRequestError(c, "TRAKT_NOT_CONNECTED", "尚未連結 Trakt 帳號")
+	case errors.Is(err, trakt.ErrDeviceCodeExpired), errors.Is(err, trakt.ErrInvalidDeviceCode):
+		ErrorResponse(c, http.StatusBadRequest, "TRAKT_AUTH_EXPIRED", "Trakt 驗證碼已過期", "Request a new code and try again.")
+	case errors.Is(err, trakt.ErrAccessDenied):
+		BadRequestError(c, "TRAKT_AUTH_DENIED", "Trakt 授權被拒絕")
+	case errors.As(err, &traktErr) && traktErr.Code == trakt.ErrCodeNotConfigured:
+		ErrorResponse(c, http.StatusBadRequest, traktErr.Code, "尚未設定 Trakt 應用程式", "Enter the client ID and secret of your Trakt API application.")
+	case errors.As(err, &traktErr):
+		ErrorResponse(c, http.StatusBadGateway, traktErr.Code,
+			"Trakt 連線失敗："+traktErr.Message,
+			"If the token was revoked on trakt.tv, unlink and link the account again.")
+	default:
+		slog.Error(message, "error", err)
+		InternalServerError(c, message)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/services"
+	"github.com/vido/api/internal/trakt"
+)
+
+// fakeTrakt implements services.TraktServiceInterface.
+type fakeTrakt struct {
+	polls   int
+	synced  bool
+	pollErr error
+}
+
+func (f *fakeTrakt) GetStatus(ctx context.Context) (*services.TraktStatus, error) {
+	return &services.TraktStatus{Configured: true}, nil
+}
+func (f *fakeTrakt) SaveConfig(ctx context.Context, input services.TraktConfigInput) error {
+	return nil
+}
+func (f *fakeTrakt) StartDeviceAuth(ctx context.Context, userID string) (*trakt.DeviceCode, error) {
+	return &trakt.DeviceCode{UserCode: "ABCD1234", VerificationURL: "https://trakt.tv/activate", Interval: 5}, nil
+}
+func (f *fakeTrakt) PollDeviceAuth(ctx context.Context) (*services.TraktStatus, error) {
+	f.polls++
+	if f.pollErr != nil {
+		return nil, f.pollErr
+	}
+	if f.polls == 1 {
+		return nil, services.ErrTraktAuthorizationPending
+	}
+	return &services.TraktStatus{Connected: true, Username: "sean"}, nil
+}
+func (f *fakeTrakt) Disconnect(ctx context.Context) error { return nil }
+func (f *fakeTrakt) Sync(ctx context.Context) (*services.TraktSyncResult, error) {
+	if !f.synced {
+		return nil, &trakt.Error{Code: trakt.ErrCodeUnauthorized, Message: "Trakt rejected the access token"}
+	}
+	return &services.TraktSyncResult{RequestsCreated: 2}, nil
+}
+
+var _ services.TraktServiceInterface = (*fakeTrakt)(nil)
+
+func setupTraktRouter(svc *fakeTrakt) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewTraktHandler(svc).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestTraktHandler_DeviceAuth(t *testing.T) {
+	svc := &fakeTrakt{}
+	router := setupTraktRouter(svc)
+
+	w := sendJSON(router, http.MethodPost, "/api/v1/settings/trakt/device-code", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"user_code":"ABCD1234"`)
+
+	w = sendJSON(router, http.MethodPost, "/api/v1/settings/trakt/device-token", "")
+	assert.Equal(t, http.StatusAccepted, w.Code, "pending until the code is entered")
+
+	w = sendJSON(router, http.MethodPost, "/api/v1/settings/trakt/device-token", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"username":"sean"`)
+
+	svc.pollErr = trakt.ErrDeviceCodeExpired
+	w = sendJSON(router, http.MethodPost, "/api/v1/settings/trakt/device-token", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "TRAKT_AUTH_EXPIRED")
+}
+
+func TestTraktHandler_Sync(t *testing.T) {
+	svc := &fakeTrakt{}
+	router := setupTraktRouter(svc)
+
+	w := sendJSON(router, http.MethodPost, "/api/v1/settings/trakt/sync", "")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Contains(t, w.Body.String(), trakt.ErrCodeUnauthorized)
+
+	svc.synced = true
+	w = sendJSON(router, http.MethodPost, "/api/v1/settings/trakt/sync", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"requests_created":2`)
+}
@@ -148,6 +148,9 @@ func (h *TranscriptionHandler) TranscribeMovie(c *gin.Context) {
 				"Wait for the current transcription to complete.")
 			return
 		}
+		if respondSpendCapReached(c, err) {
+			return
+		}
 		slog.Error("Failed to start transcription", "movie_id", id, "error", err)
 		InternalServerError(c, "Failed to start transcription")
 		return
@@ -175,6 +178,7 @@ func (h *TranscriptionHandler) TranscribeMovie(c *gin.Context) {
 // @Failure      400  {object}  APIResponse  "VALIDATION_INVALID_FORMAT (empty id) / VALIDATION_REQUIRED_FIELD (no file path, or file missing on disk)"
 // @Failure      404  {object}  APIResponse  "episode not found"
 // @Failure      409  {object}  APIResponse  "TRANSCRIPTION_IN_PROGRESS — a run for this episode is already in flight"
+// @Failure      429  {object}  APIResponse  "AI_SPEND_CAP_REACHED — the global daily or monthly AI spend cap is used up"
 // @Failure      500  {object}  APIResponse  "failed to start"
 // @Failure      503  {object}  APIResponse  "TRANSCRIPTION_DISABLED — no ASR capability AND this episode cannot resume translate-only"
 // @Router       /api/v1/episodes/{id}/transcribe [post]
@@ -254,6 +258,9 @@ func (h *TranscriptionHandler) TranscribeEpisode(c *gin.Context) {
 				"請等待目前的生成完成。")
 			return
 		}
+		if respondSpendCapReached(c, err) {
+			return
+		}
 		slog.Error("Failed to start episode transcription", "episode_id", id, "error", err)
 		InternalServerError(c, "Failed to start transcription")
 		return
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/services"
+)
+
+// WatchServiceInterface defines the contract for watch history.
+type WatchServiceInterface interface {
+	GetState(ctx context.Context, userID, mediaType, mediaID string) (*models.WatchState, error)
+	ReportProgress(ctx context.Context, userID, mediaType, mediaID string, position, duration float64) (*models.WatchState, error)
+	SetWatched(ctx context.Context, userID, mediaType, mediaID string, watched bool) (*models.WatchState, error)
+	SetSeriesWatched(ctx context.Context, userID, seriesID string, season *int, watched bool) (*services.SeriesWatchProgress, error)
+	SeriesProgress(ctx context.Context, userID, seriesID string) (*services.SeriesWatchProgress, error)
+	NextEpisode(ctx context.Context, userID, seriesID string) (*models.Episode, error)
+	ContinueWatching(ctx context.Context, userID string, limit int) ([]services.ContinueWatchingItem, error)
+}
+
+// ReportProgressRequest is the body of PUT /watch/{movies,episodes}/:id/progress.
+type ReportProgressRequest struct {
+	PositionSeconds float64 `json:"position_seconds"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// WatchHandler handles per-user watch state.
+type WatchHandler struct {
+	service WatchServiceInterface
+}
+
+// NewWatchHandler creates a new WatchHandler.
+func NewWatchHandler(service WatchServiceInterface) *WatchHandler {
+	return &WatchHandler{service: service}
+}
+
+// RegisterRoutes registers the watch-state routes.
+func (h *WatchHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	watch := rg.Group("/watch")
+	{
+		watch.GET("/continue", h.ContinueWatching)
+
+		for path, mediaType := range map[string]string{
+			"/movies":   models.WatchMediaMovie,
+			"/episodes": models.WatchMediaEpisode,
+		} {
+			mediaType := mediaType
+			items := watch.Group(path)
+			items.GET("/:id", func(c *gin.Context) { h.getState(c, mediaType) })
+			items.PUT("/:id/progress", func(c *gin.Context) { h.reportProgress(c, mediaType) })
+			items.POST("/:id/watched", func(c *gin.Context) { h.setWatched(c, mediaType, true) })
+			items.DELETE("/:id/watched", func(c *gin.Context) { h.setWatched(c, mediaType, false) })
+		}
+
+		series := watch.Group("/series")
+		{
+			series.GET("/:id", h.GetSeriesProgress)
+			series.GET("/:id/next", h.GetNextEpisode)
+			series.POST("/:id/watched", func(c *gin.Context) { h.setSeriesWatched(c, true) })
+			series.DELETE("/:id/watched", func(c *gin.Context) { h.setSeriesWatched(c, false) })
+		}
+	}
+}
+
+// ContinueWatching handles GET /api/v1/watch/continue
+func (h *WatchHandler) ContinueWatching(c *gin.Context) {
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 100 {
+			BadRequestError(c, "VALIDATION_INVALID_FORMAT", "limit must be a number between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+
+	items, err := h.service.ContinueWatching(c.Request.Context(), watchUserID(c), limit)
+	if err != nil {
+		handleWatchError(c, "Failed to load continue watching", err)
+		return
+	}
+	SuccessResponse(c, items)
+}
+
+// getState handles GET /api/v1/watch/{movies,episodes}/:id
+func (h *WatchHandler) getState(c *gin.Context, mediaType string) {
+	state, err := h.service.GetState(c.Request.Context(), watchUserID(c), mediaType, c.Param("id"))
+	if err != nil {
+		handleWatchError(c, "Failed to load watch state", err)
+		return
+	}
+	SuccessResponse(c, state)
+}
+
+// reportProgress handles PUT /api/v1/watch/{movies,episodes}/:id/progress
+func (h *WatchHandler) reportProgress(c *gin.Context, mediaType string) {
+	var req ReportProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ValidationError(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	state, err := h.service.ReportProgress(c.Request.Context(), watchUserID(c), mediaType, c.Param("id"),
+		req.PositionSeconds, req.DurationSeconds)
+	if err != nil {
+		handleWatchError(c, "Failed to save watch progress", err)
+		return
+	}
+	SuccessResponse(c, state)
+}
+
+// setWatched handles POST/DELETE /api/v1/watch/{movies,episodes}/:id/watched
+func (h *WatchHandler) setWatched(c *gin.Context, mediaType string, watched bool) {
+	state, err := h.service.SetWatched(c.Request.Context(), watchUserID(c), mediaType, c.Param("id"), watched)
+	if err != nil {
+		handleWatchError(c, "Failed to update watched flag", err)
+		return
+	}
+	SuccessResponse(c, state)
+}
+
+// GetSeriesProgress handles GET /api/v1/watch/series/:id
+func (h *WatchHandler) GetSeriesProgress(c *gin.Context) {
+	progress, err := h.service.SeriesProgress(c.Request.Context(), watchUserID(c), c.Param("id"))
+	if err != nil {
+		handleWatchError(c, "Failed to load series progress", err)
+		return
+	}
+	SuccessResponse(c, progress)
+}
+
+// GetNextEpisode handles GET /api/v1/watch/series/:id/next
+// data is null once the show is finished.
+func (h *WatchHandler) GetNextEpisode(c *gin.Context) {
+	episode, err := h.service.NextEpisode(c.Request.Context(), watchUserID(c), c.Param("id"))
+	if err != nil {
+		handleWatchError(c, "Failed to find next episode", err)
+		return
+	}
+	SuccessResponse(c, gin.H{"episode": episode})
+}
+
+// setSeriesWatched handles POST/DELETE /api/v1/watch/series/:id/watched
+// ?season=N limits the change to one season.
+func (h *WatchHandler) setSeriesWatched(c *gin.Context, watched bool) {
+	var season *int
+	if seasonStr := c.Query("season"); seasonStr != "" {
+		n, err := strconv.Atoi(seasonStr)
+		if err != nil || n < 0 {
+			BadRequestError(c, "VALIDATION_INVALID_FORMAT", "season must be a non-negative number")
+			return
+		}
+		season = &n
+	}
+
+	progress, err := h.service.SetSeriesWatched(c.Request.Context(), watchUserID(c), c.Param("id"), season, watched)
+	if err != nil {
+		handleWatchError(c, "Failed to update series watched state", err)
+		return
+	}
+	SuccessResponse(c, progress)
+}
+
+// watchUserID keys watch history by the signed-in account; an install
+// without accounts shares the "" history.
+func watchUserID(c *gin.Context) string {
+	if user := CurrentUser(c); user != nil {
+		return user.ID
+	}
+	return ""
+}
+
+func handleWatchError(c *gin.Context, message string, err error) {
+	var validationErr *models.ValidationError
+	switch {
+	case errors.Is(err, services.ErrMediaNotFound):
+		NotFoundError(c, "media")
+	case errors.Is(err, services.ErrInvalidWatchProgress):
+		BadRequestError(c, "VALIDATION_INVALID_FORMAT", err.Error())
+	case errors.As(err, &validationErr):
+		BadRequestError(c, "VALIDATION_FAILED", err.Error())
+	default:
+		slog.Error(message, "error", err)
+		InternalServerError(c, message)
+	}
+}
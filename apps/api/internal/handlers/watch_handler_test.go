@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/services"
+)
+
+type MockWatchService struct {
+	mock.Mock
+}
+
+func (m *MockWatchService) GetState(ctx context.Context, userID, mediaType, mediaID string) (*models.WatchState, error) {
+	args := m.Called(ctx, userID, mediaType, mediaID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WatchState), args.Error(1)
+}
+
+func (m *MockWatchService) ReportProgress(ctx context.Context, userID, mediaType, mediaID string, position, duration float64) (*models.WatchState, error) {
+	args := m.Called(ctx, userID, mediaType, mediaID, position, duration)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WatchState), args.Error(1)
+}
+
+func (m *MockWatchService) SetWatched(ctx context.Context, userID, mediaType, mediaID string, watched bool) (*models.WatchState, error) {
+	args := m.Called(ctx, userID, mediaType, mediaID, watched)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WatchState), args.Error(1)
+}
+
+func (m *MockWatchService) SetSeriesWatched(ctx context.Context, userID, seriesID string, season *int, watched bool) (*services.SeriesWatchProgress, error) {
+	args := m.Called(ctx, userID, seriesID, season, watched)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.SeriesWatchProgress), args.Error(1)
+}
+
+func (m *MockWatchService) SeriesProgress(ctx context.Context, userID, seriesID string) (*services.SeriesWatchProgress, error) {
+	args := m.Called(ctx, userID, seriesID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.SeriesWatchProgress), args.Error(1)
+}
+
+func (m *MockWatchService) NextEpisode(ctx context.Context, userID, seriesID string) (*models.Episode, error) {
+	args := m.Called(ctx, userID, seriesID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Episode), args.Error(1)
+}
+
+func (m *MockWatchService) ContinueWatching(ctx context.Context, userID string, limit int) ([]services.ContinueWatchingItem, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]services.ContinueWatchingItem), args.Error(1)
+}
+
+// setupWatchRouter mounts the handler behind a stub that signs in user (nil
+// leaves the request anonymous, as on an open install).
+func setupWatchRouter(svc *MockWatchService, user *models.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api/v1", func(c *gin.Context) {
+		if user != nil {
+			c.Set(userContextKey, user)
+		}
+		c.Next()
+	})
+	NewWatchHandler(svc).RegisterRoutes(api)
+	return router
+}
+
+func TestWatchHandler_ReportProgress(t *testing.T) {
+	svc := new(MockWatchService)
+	router := setupWatchRouter(svc, &models.User{ID: "u1"})
+	svc.On("ReportProgress", mock.Anything, "u1", models.WatchMediaEpisode, "ep1", 120.0, 1800.0).
+		Return(&models.WatchState{MediaID: "ep1", PositionSeconds: 120}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/watch/episodes/ep1/progress",
+		strings.NewReader(`{"position_seconds":120,"duration_seconds":1800}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestWatchHandler_OpenInstallUsesSharedHistory(t *testing.T) {
+	svc := new(MockWatchService)
+	router := setupWatchRouter(svc, nil)
+	svc.On("SetWatched", mock.Anything, "", models.WatchMediaMovie, "m1", false).
+		Return(&models.WatchState{MediaID: "m1"}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/watch/movies/m1/watched", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestWatchHandler_ErrorMapping(t *testing.T) {
+	svc := new(MockWatchService)
+	router := setupWatchRouter(svc, nil)
+	svc.On("GetState", mock.Anything, "", models.WatchMediaMovie, "missing").
+		Return(nil, services.ErrMediaNotFound)
+	svc.On("ReportProgress", mock.Anything, "", models.WatchMediaMovie, "m1", -5.0, 0.0).
+		Return(nil, services.ErrInvalidWatchProgress)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/watch/movies/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/watch/movies/m1/progress",
+		strings.NewReader(`{"position_seconds":-5}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWatchHandler_SeriesWatchedSeason(t *testing.T) {
+	svc := new(MockWatchService)
+	router := setupWatchRouter(svc, &models.User{ID: "u1"})
+	season := 2
+	svc.On("SetSeriesWatched", mock.Anything, "u1", "s1", &season, true).
+		Return(&services.SeriesWatchProgress{SeriesID: "s1", Status: models.WatchStatusInProgress}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/watch/series/s1/watched?season=2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/watch/series/s1/watched?season=x", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWatchHandler_ContinueWatching(t *testing.T) {
+	svc := new(MockWatchService)
+	router := setupWatchRouter(svc, &models.User{ID: "u1"})
+	svc.On("ContinueWatching", mock.Anything, "u1", 5).Return([]services.ContinueWatchingItem{
+		{Type: models.WatchMediaMovie, Movie: &models.Movie{ID: "m1"}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/watch/continue?limit=5", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data []services.ContinueWatchingItem `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "m1", resp.Data[0].Movie.ID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/watch/continue?limit=0", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -12,11 +12,14 @@ type Pingable interface {
 
 // ServiceHealthChecker implements HealthChecker for actual service clients
 type ServiceHealthChecker struct {
-	tmdb        Pingable
-	douban      Pingable
-	wikipedia   Pingable
-	ai          Pingable
-	qbittorrent Pingable
+	tmdb         Pingable
+	douban       Pingable
+	wikipedia    Pingable
+	ai           Pingable
+	qbittorrent  Pingable
+	plex         Pingable
+	transmission Pingable
+	deluge       Pingable
 }
 
 // NewServiceHealthChecker creates a new ServiceHealthChecker
@@ -34,6 +37,21 @@ func (c *ServiceHealthChecker) SetQBittorrent(qb Pingable) {
 	c.qbittorrent = qb
 }
 
+// SetPlex sets the Plex pingable for health checking
+func (c *ServiceHealthChecker) SetPlex(plex Pingable) {
+	c.plex = plex
+}
+
+// SetTransmission sets the Transmission pingable for health checking
+func (c *ServiceHealthChecker) SetTransmission(transmission Pingable) {
+	c.transmission = transmission
+}
+
+// SetDeluge sets the Deluge pingable for health checking
+func (c *ServiceHealthChecker) SetDeluge(deluge Pingable) {
+	c.deluge = deluge
+}
+
 // CheckTMDb checks the health of TMDb API
 func (c *ServiceHealthChecker) CheckTMDb(ctx context.Context) error {
 	if c.tmdb == nil {
@@ -74,6 +92,30 @@ func (c *ServiceHealthChecker) CheckQBittorrent(ctx context.Context) error {
 	return c.qbittorrent.Ping(ctx)
 }
 
+// CheckPlex checks the health of Plex Media Server
+func (c *ServiceHealthChecker) CheckPlex(ctx context.Context) error {
+	if c.plex == nil {
+		return errors.New("Plex not configured")
+	}
+	return c.plex.Ping(ctx)
+}
+
+// CheckTransmission checks the health of Transmission
+func (c *ServiceHealthChecker) CheckTransmission(ctx context.Context) error {
+	if c.transmission == nil {
+		return errors.New("Transmission not configured")
+	}
+	return c.transmission.Ping(ctx)
+}
+
+// CheckDeluge checks the health of Deluge
+func (c *ServiceHealthChecker) CheckDeluge(ctx context.Context) error {
+	if c.deluge == nil {
+		return errors.New("Deluge not configured")
+	}
+	return c.deluge.Ping(ctx)
+}
+
 // StubHealthChecker implements HealthChecker with all services reporting healthy.
 // Used when actual service health checking is not yet implemented.
 type StubHealthChecker struct{}
@@ -108,6 +150,29 @@ func (c *StubHealthChecker) CheckQBittorrent(ctx context.Context) error {
 	return nil
 }
 
+// CheckPlex always returns healthy.
+func (c *StubHealthChecker) CheckPlex(ctx context.Context) error {
+	return nil
+}
+
+// CheckTransmission always returns healthy.
+func (c *StubHealthChecker) CheckTransmission(ctx context.Context) error {
+	return nil
+}
+
+// CheckDeluge always returns healthy.
+func (c *StubHealthChecker) CheckDeluge(ctx context.Context) error {
+	return nil
+}
+
+// PingFunc adapts a plain function to the Pingable interface.
+type PingFunc func(ctx context.Context) error
+
+// Ping calls f.
+func (f PingFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
 // QBPingable adapts a qBittorrent service to the Pingable interface for health checks.
 // Uses function closures to avoid circular package dependencies.
 type QBPingable struct {
@@ -20,6 +20,9 @@ type HealthChecker interface {
 	CheckWikipedia(ctx context.Context) error
 	CheckAI(ctx context.Context) error
 	CheckQBittorrent(ctx context.Context) error
+	CheckPlex(ctx context.Context) error
+	CheckTransmission(ctx context.Context) error
+	CheckDeluge(ctx context.Context) error
 }
 
 // HealthMonitor tracks the health of external services
@@ -29,6 +32,12 @@ type HealthMonitor struct {
 	checker     HealthChecker
 	historyRepo repository.ConnectionHistoryRepositoryInterface
 	logger      *slog.Logger
+
+	// onDegradation is called when a full check moves the overall level.
+	// lastLevel/levelSeeded track the level the handler last saw.
+	onDegradation func(prev, next models.DegradationLevel, message string)
+	lastLevel     models.DegradationLevel
+	levelSeeded   bool
 }
 
 // NewHealthMonitor creates a new HealthMonitor
@@ -45,6 +54,16 @@ func (m *HealthMonitor) SetHistoryRepo(repo repository.ConnectionHistoryReposito
 	m.historyRepo = repo
 }
 
+// SetDegradationHandler registers a callback for overall degradation level
+// changes, evaluated after each CheckAllServices round. The first round only
+// records the starting level, so a restart during an outage does not report
+// the outage again as a change.
+func (m *HealthMonitor) SetDegradationHandler(fn func(prev, next models.DegradationLevel, message string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onDegradation = fn
+}
+
 // GetDegradationLevel returns the current degradation level based on service health
 func (m *HealthMonitor) GetDegradationLevel() models.DegradationLevel {
 	m.mu.RLock()
@@ -121,6 +140,24 @@ func (m *HealthMonitor) CheckAllServices(ctx context.Context) {
 	}
 
 	wg.Wait()
+	m.evaluateDegradation()
+}
+
+// evaluateDegradation compares the overall level with the one last seen and
+// calls the degradation handler, outside the lock, when it moved.
+func (m *HealthMonitor) evaluateDegradation() {
+	m.mu.Lock()
+	next := m.getDegradationLevelUnlocked()
+	prev, seeded := m.lastLevel, m.levelSeeded
+	m.lastLevel, m.levelSeeded = next, true
+	handler := m.onDegradation
+	message := m.generateStatusMessage(next)
+	m.mu.Unlock()
+
+	if handler == nil || !seeded || prev == next {
+		return
+	}
+	handler(prev, next, message)
 }
 
 // GetAllServiceStatuses returns all service statuses in the settings dashboard format
@@ -174,8 +211,9 @@ func (m *HealthMonitor) UpdateServiceHealth(name models.ServiceName, err error)
 		}
 	}
 
-	// Record status change events to connection history
-	if previousStatus != svc.Status && previousStatus != "" && m.historyRepo != nil {
+	// Record status change events to connection history. Plex is skipped:
+	// its entry mirrors plugins.Manager, which records the transitions.
+	if previousStatus != svc.Status && previousStatus != "" && m.historyRepo != nil && name != models.ServiceNamePlex {
 		var eventType models.ConnectionEventType
 		switch {
 		case svc.Status == models.ServiceStatusHealthy:
@@ -301,23 +339,43 @@ func (m *HealthMonitor) StartMonitoring(ctx context.Context, interval time.Durat
 
 // StartQBMonitoring starts a dedicated monitor for qBittorrent with 30s interval (NFR-R6)
 func (m *HealthMonitor) StartQBMonitoring(ctx context.Context) {
-	ticker := time.NewTicker(30 * time.Second)
+	m.monitorService(ctx, models.ServiceNameQBittorrent, m.checker.CheckQBittorrent, 30*time.Second)
+}
+
+// StartPlexMonitoring starts the same 30s monitor for Plex Media Server.
+func (m *HealthMonitor) StartPlexMonitoring(ctx context.Context) {
+	m.monitorService(ctx, models.ServiceNamePlex, m.checker.CheckPlex, 30*time.Second)
+}
+
+// StartTransmissionMonitoring starts the same 30s monitor for Transmission.
+func (m *HealthMonitor) StartTransmissionMonitoring(ctx context.Context) {
+	m.monitorService(ctx, models.ServiceNameTransmission, m.checker.CheckTransmission, 30*time.Second)
+}
+
+// StartDelugeMonitoring starts the same 30s monitor for Deluge.
+func (m *HealthMonitor) StartDelugeMonitoring(ctx context.Context) {
+	m.monitorService(ctx, models.ServiceNameDeluge, m.checker.CheckDeluge, 30*time.Second)
+}
+
+// monitorService checks one service immediately, then every interval.
+func (m *HealthMonitor) monitorService(ctx context.Context, name models.ServiceName, check func(context.Context) error, interval time.Duration) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	// Perform initial check
 	start := time.Now()
-	err := m.checker.CheckQBittorrent(ctx)
-	m.UpdateServiceHealthWithTime(models.ServiceNameQBittorrent, err, time.Since(start).Milliseconds())
+	err := check(ctx)
+	m.UpdateServiceHealthWithTime(name, err, time.Since(start).Milliseconds())
 
 	for {
 		select {
 		case <-ctx.Done():
-			m.logger.Info("qBittorrent health monitoring stopped")
+			m.logger.Info("Service health monitoring stopped", "service", name)
 			return
 		case <-ticker.C:
 			s := time.Now()
-			err := m.checker.CheckQBittorrent(ctx)
-			m.UpdateServiceHealthWithTime(models.ServiceNameQBittorrent, err, time.Since(s).Milliseconds())
+			err := check(ctx)
+			m.UpdateServiceHealthWithTime(name, err, time.Since(s).Milliseconds())
 		}
 	}
 }
@@ -14,12 +14,15 @@ import (
 
 // MockHealthChecker is a mock implementation of HealthChecker for testing
 type MockHealthChecker struct {
-	mu             sync.RWMutex
-	tmdbErr        error
-	doubanErr      error
-	wikipediaErr   error
-	aiErr          error
-	qbittorrentErr error
+	mu              sync.RWMutex
+	tmdbErr         error
+	doubanErr       error
+	wikipediaErr    error
+	aiErr           error
+	qbittorrentErr  error
+	plexErr         error
+	transmissionErr error
+	delugeErr       error
 }
 
 func (m *MockHealthChecker) CheckTMDb(ctx context.Context) error {
@@ -52,6 +55,24 @@ func (m *MockHealthChecker) CheckQBittorrent(ctx context.Context) error {
 	return m.qbittorrentErr
 }
 
+func (m *MockHealthChecker) CheckPlex(ctx context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.plexErr
+}
+
+func (m *MockHealthChecker) CheckTransmission(ctx context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.transmissionErr
+}
+
+func (m *MockHealthChecker) CheckDeluge(ctx context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.delugeErr
+}
+
 func (m *MockHealthChecker) SetTMDbError(err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -82,6 +103,18 @@ func (m *MockHealthChecker) SetQBittorrentError(err error) {
 	m.qbittorrentErr = err
 }
 
+func (m *MockHealthChecker) SetPlexError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.plexErr = err
+}
+
+func (m *MockHealthChecker) SetTransmissionError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transmissionErr = err
+}
+
 func TestNewHealthMonitor(t *testing.T) {
 	checker := &MockHealthChecker{}
 	monitor := NewHealthMonitor(checker)
@@ -220,6 +253,35 @@ func TestHealthMonitor_CheckAllServices_WithErrors(t *testing.T) {
 	assert.Equal(t, models.ServiceStatusDegraded, monitor.services.AI.Status)
 }
 
+func TestHealthMonitor_DegradationHandler(t *testing.T) {
+	checker := &MockHealthChecker{}
+	monitor := NewHealthMonitor(checker)
+
+	type change struct{ prev, next models.DegradationLevel }
+	var changes []change
+	monitor.SetDegradationHandler(func(prev, next models.DegradationLevel, message string) {
+		changes = append(changes, change{prev, next})
+		if next != models.DegradationNormal {
+			assert.Contains(t, message, "TMDb")
+		}
+	})
+
+	ctx := context.Background()
+	monitor.CheckAllServices(ctx)
+	assert.Empty(t, changes, "the first round only seeds the level")
+
+	checker.SetTMDbError(errors.New("connection refused"))
+	monitor.CheckAllServices(ctx)
+	monitor.CheckAllServices(ctx)
+	assert.Equal(t, []change{{models.DegradationNormal, models.DegradationPartial}}, changes,
+		"an unchanged level is not reported twice")
+
+	checker.SetTMDbError(nil)
+	monitor.CheckAllServices(ctx)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, models.DegradationNormal, changes[1].next)
+}
+
 func TestHealthMonitor_UpdateServiceHealth_Success(t *testing.T) {
 	checker := &MockHealthChecker{}
 	monitor := NewHealthMonitor(checker)
@@ -337,7 +399,7 @@ func TestHealthMonitor_GetAllServiceStatuses(t *testing.T) {
 	monitor.UpdateServiceHealth(models.ServiceNameAI, errors.New("quota exceeded"))
 
 	statuses := monitor.GetAllServiceStatuses()
-	assert.Len(t, statuses, 5)
+	assert.Len(t, statuses, 8)
 
 	// TMDb should be connected
 	assert.Equal(t, models.StatusConnected, statuses[0].Status)
@@ -408,3 +470,58 @@ func TestHealthMonitor_CheckAllServices_QBittorrentError(t *testing.T) {
 	assert.Equal(t, models.ServiceStatusDegraded, monitor.services.QBittorrent.Status)
 	assert.Equal(t, models.ServiceStatusHealthy, monitor.services.TMDb.Status)
 }
+
+// serviceStatusOf reads a status under the monitor's lock. GetServiceHealth
+// hands back the live struct, so polling it while a monitoring loop writes it
+// is a data race.
+func serviceStatusOf(m *HealthMonitor, name models.ServiceName) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.services.GetService(name).Status
+}
+
+func TestHealthMonitor_StartPlexMonitoring(t *testing.T) {
+	checker := &MockHealthChecker{}
+	checker.SetPlexError(errors.New("plex health check failed"))
+	monitor := NewHealthMonitor(checker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		monitor.StartPlexMonitoring(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		return serviceStatusOf(monitor, models.ServiceNamePlex) == models.ServiceStatusDegraded
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, models.DegradationNormal, monitor.GetDegradationLevel(),
+		"a media server being away degrades nothing")
+	assert.Empty(t, monitor.GetHealthStatus().Message)
+}
+
+func TestHealthMonitor_StartTransmissionMonitoring(t *testing.T) {
+	checker := &MockHealthChecker{}
+	checker.SetTransmissionError(errors.New("transmission unreachable"))
+	monitor := NewHealthMonitor(checker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		monitor.StartTransmissionMonitoring(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		return serviceStatusOf(monitor, models.ServiceNameTransmission) == models.ServiceStatusDegraded
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, models.DegradationNormal, monitor.GetDegradationLevel(),
+		"an alternative download client is outside the degradation count")
+	assert.Equal(t, models.ServiceStatusHealthy, monitor.GetServiceHealth(models.ServiceNameDeluge).Status)
+}
@@ -0,0 +1,120 @@
+package indexer
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiErrorDoc is the Torznab/Newznab error document: <error code=".."
+// description=".."/> served with a 200.
+type apiErrorDoc struct {
+	XMLName     xml.Name `xml:"error"`
+	Code        string   `xml:"code,attr"`
+	Description string   `xml:"description,attr"`
+}
+
+// apiError returns the *Error an error document describes, nil for any other
+// body. Newznab codes 100–199 are account problems (bad or missing key,
+// suspended account).
+func apiError(body []byte) error {
+	var doc apiErrorDoc
+	if xml.Unmarshal(body, &doc) != nil {
+		return nil
+	}
+	code, _ := strconv.Atoi(doc.Code)
+	if code >= 100 && code < 200 {
+		return &Error{Code: ErrCodeAuthFailed, Message: doc.Description}
+	}
+	return &Error{Code: ErrCodeRequestFailed, Message: "indexer error " + doc.Code + ": " + doc.Description}
+}
+
+type rssDocument struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+// rssItem covers plain RSS and the Torznab/Newznab extensions; the untagged
+// namespace on attr matches both torznab:attr and newznab:attr.
+type rssItem struct {
+	Title     string `xml:"title"`
+	GUID      string `xml:"guid"`
+	Link      string `xml:"link"`
+	PubDate   string `xml:"pubDate"`
+	Size      int64  `xml:"size"`
+	Enclosure struct {
+		URL    string `xml:"url,attr"`
+		Length int64  `xml:"length,attr"`
+	} `xml:"enclosure"`
+	Attrs []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"attr"`
+}
+
+func (it rssItem) attr(name string) string {
+	for _, a := range it.Attrs {
+		if strings.EqualFold(a.Name, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func parseFeed(body []byte) ([]Release, error) {
+	var doc rssDocument
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, &Error{Code: ErrCodeInvalidResponse, Message: "indexer response is not an RSS feed", Cause: err}
+	}
+	releases := make([]Release, 0, len(doc.Channel.Items))
+	for _, it := range doc.Channel.Items {
+		r := Release{
+			Title:   strings.TrimSpace(it.Title),
+			GUID:    strings.TrimSpace(it.GUID),
+			Seeders: -1,
+		}
+		// A magnet beats a .torrent link: it needs no second request.
+		switch {
+		case it.attr("magneturl") != "":
+			r.Link = it.attr("magneturl")
+		case it.Enclosure.URL != "":
+			r.Link = it.Enclosure.URL
+		default:
+			r.Link = strings.TrimSpace(it.Link)
+		}
+		if r.Title == "" || r.Link == "" {
+			continue
+		}
+		if r.GUID == "" {
+			r.GUID = r.Link
+		}
+		r.InfoHash = strings.ToLower(it.attr("infohash"))
+		if r.InfoHash == "" {
+			r.InfoHash = MagnetInfoHash(r.Link)
+		}
+		if seeders, err := strconv.Atoi(it.attr("seeders")); err == nil {
+			r.Seeders = seeders
+		}
+		r.Size = it.Size
+		if size, err := strconv.ParseInt(it.attr("size"), 10, 64); err == nil && size > 0 {
+			r.Size = size
+		} else if r.Size == 0 {
+			r.Size = it.Enclosure.Length
+		}
+		r.PublishedAt = parsePubDate(it.PubDate)
+		releases = append(releases, r)
+	}
+	return releases, nil
+}
+
+func parsePubDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
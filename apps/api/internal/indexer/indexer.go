@@ -0,0 +1,277 @@
+// Package indexer talks to release indexers: the Torznab and Newznab search
+// APIs (Jackett, Prowlarr, NZBHydra and most private trackers) and plain RSS
+// feeds. It returns Releases and nothing more — matching them to requests and
+// judging their quality is the built-in fulfilment service's job.
+//
+// Torznab and Newznab share one wire format (RSS 2.0 plus namespaced <attr>
+// elements), so one parser serves all three types; RSS feeds simply cannot
+// be searched, only read.
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vido/api/internal/models"
+)
+
+// Error codes for indexer operations.
+const (
+	ErrCodeRequestFailed   = "INDEXER_REQUEST_FAILED"
+	ErrCodeAuthFailed      = "INDEXER_AUTH_FAILED"
+	ErrCodeInvalidResponse = "INDEXER_INVALID_RESPONSE"
+	ErrCodeUnsupported     = "INDEXER_SEARCH_UNSUPPORTED"
+)
+
+// maxResponseBytes bounds every indexer response and .torrent download.
+const maxResponseBytes = 10 << 20
+
+// Error is an indexer failure with a stable code.
+type Error struct {
+	Code    string
+	Message string
+	Cause   error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s: %s", e.Code, e.Message, e.Cause.Error())
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Config is one indexer as the client needs it. URL is the Torznab/Newznab
+// endpoint (with or without the trailing /api) or the RSS feed address.
+type Config struct {
+	Type       string
+	URL        string
+	APIKey     string
+	Categories []int
+}
+
+// SearchQuery is one search. Season and Episode narrow a tvsearch; zero
+// means unset.
+type SearchQuery struct {
+	MediaType string
+	Query     string
+	Season    int
+	Episode   int
+}
+
+// Release is one item an indexer returned. Link is the .torrent or magnet
+// link; InfoHash is filled whenever the indexer or the magnet states it.
+// Seeders is -1 when the indexer does not report it (plain RSS).
+type Release struct {
+	Title       string    `json:"title"`
+	GUID        string    `json:"guid"`
+	Link        string    `json:"link"`
+	InfoHash    string    `json:"info_hash,omitempty"`
+	Size        int64     `json:"size"`
+	Seeders     int       `json:"seeders"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Download is what a release link resolved to: a magnet URI or the bytes of
+// a .torrent file (exactly one is set).
+type Download struct {
+	Magnet  string
+	Torrent []byte
+}
+
+// Client queries indexers. One reused http.Client (Rule 14); indexers that
+// fan out to many trackers are slow, hence the 30s timeout.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient creates an indexer client.
+func NewClient() *Client {
+	return &Client{httpClient: &http.Client{
+		Timeout: 30 * time.Second,
+		// Jackett and Prowlarr answer a download link for a magnet-only
+		// release with a redirect to the magnet URI; stop there and read it.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme == "magnet" {
+				return http.ErrUseLastResponse
+			}
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			return nil
+		},
+	}}
+}
+
+// Search runs a query against a Torznab or Newznab indexer. RSS feeds cannot
+// be searched and return ErrCodeUnsupported.
+func (c *Client) Search(ctx context.Context, cfg Config, q SearchQuery) ([]Release, error) {
+	if cfg.Type == models.IndexerTypeRSS {
+		return nil, &Error{Code: ErrCodeUnsupported, Message: "RSS feeds cannot be searched"}
+	}
+	params := url.Values{"t": {"search"}}
+	switch q.MediaType {
+	case models.RequestMediaTypeMovie:
+		params.Set("t", "movie")
+	case models.RequestMediaTypeTV:
+		params.Set("t", "tvsearch")
+		if q.Season > 0 {
+			params.Set("season", strconv.Itoa(q.Season))
+		}
+		if q.Episode > 0 {
+			params.Set("ep", strconv.Itoa(q.Episode))
+		}
+	}
+	if q.Query != "" {
+		params.Set("q", q.Query)
+	}
+	return c.fetchFeed(ctx, apiURL(cfg, params))
+}
+
+// Recent returns the indexer's latest releases: the RSS feed itself, or an
+// empty-query Torznab/Newznab search (which is what those APIs serve as RSS).
+func (c *Client) Recent(ctx context.Context, cfg Config) ([]Release, error) {
+	if cfg.Type == models.IndexerTypeRSS {
+		return c.fetchFeed(ctx, cfg.URL)
+	}
+	return c.fetchFeed(ctx, apiURL(cfg, url.Values{"t": {"search"}}))
+}
+
+// Test checks that the indexer answers: a caps request for Torznab/Newznab
+// (which also validates the API key), a feed read for RSS.
+func (c *Client) Test(ctx context.Context, cfg Config) error {
+	if cfg.Type == models.IndexerTypeRSS {
+		_, err := c.fetchFeed(ctx, cfg.URL)
+		return err
+	}
+	body, err := c.get(ctx, apiURL(cfg, url.Values{"t": {"caps"}}))
+	if err != nil {
+		return err
+	}
+	return apiError(body)
+}
+
+// Download resolves a release link to a magnet or .torrent bytes.
+func (c *Client) Download(ctx context.Context, link string) (*Download, error) {
+	if strings.HasPrefix(link, "magnet:") {
+		return &Download{Magnet: link}, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, &Error{Code: ErrCodeRequestFailed, Message: "invalid download link", Cause: redactAPIKey(err)}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &Error{Code: ErrCodeRequestFailed, Message: "download failed", Cause: redactAPIKey(err)}
+	}
+	defer resp.Body.Close()
+
+	if loc := resp.Header.Get("Location"); resp.StatusCode >= 300 && resp.StatusCode < 400 && strings.HasPrefix(loc, "magnet:") {
+		return &Download{Magnet: loc}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, &Error{Code: ErrCodeRequestFailed, Message: "download failed", Cause: err}
+	}
+	return &Download{Torrent: data}, nil
+}
+
+// apiURL builds a Torznab/Newznab API call. Jackett and Prowlarr hand out
+// the endpoint without the final /api segment, Newznab sites with it.
+func apiURL(cfg Config, params url.Values) string {
+	base := strings.TrimSuffix(cfg.URL, "/")
+	if !strings.HasSuffix(base, "/api") {
+		base += "/api"
+	}
+	if cfg.APIKey != "" {
+		params.Set("apikey", cfg.APIKey)
+	}
+	if len(cfg.Categories) > 0 {
+		cats := make([]string, len(cfg.Categories))
+		for i, cat := range cfg.Categories {
+			cats[i] = strconv.Itoa(cat)
+		}
+		params.Set("cat", strings.Join(cats, ","))
+	}
+	return base + "?" + params.Encode()
+}
+
+func (c *Client) get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, &Error{Code: ErrCodeRequestFailed, Message: "invalid indexer URL", Cause: redactAPIKey(err)}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &Error{Code: ErrCodeRequestFailed, Message: "indexer unreachable", Cause: redactAPIKey(err)}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, &Error{Code: ErrCodeRequestFailed, Message: "failed to read indexer response", Cause: err}
+	}
+	return body, nil
+}
+
+// redactAPIKey blanks the API key in the URL a *url.Error quotes, so a
+// timeout or refused connection can be logged and shown in the indexer test
+// result without leaking the key. apikey= also covers Jackett's
+// jackett_apikey= in download links.
+func redactAPIKey(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactAPIKeyParam(urlErr.URL)
+	}
+	return err
+}
+
+func redactAPIKeyParam(rawURL string) string {
+	const param = "apikey="
+	for from := 0; ; {
+		idx := strings.Index(rawURL[from:], param)
+		if idx < 0 {
+			return rawURL
+		}
+		start := from + idx + len(param)
+		end := strings.IndexAny(rawURL[start:], "&# ")
+		if end < 0 {
+			end = len(rawURL) - start
+		}
+		rawURL = rawURL[:start] + "REDACTED" + rawURL[start+end:]
+		from = start + len("REDACTED")
+	}
+}
+
+func statusError(status int) *Error {
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return &Error{Code: ErrCodeAuthFailed, Message: fmt.Sprintf("indexer rejected the credentials (status %d)", status)}
+	}
+	return &Error{Code: ErrCodeRequestFailed, Message: fmt.Sprintf("indexer returned status %d", status)}
+}
+
+func (c *Client) fetchFeed(ctx context.Context, rawURL string) ([]Release, error) {
+	body, err := c.get(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := apiError(body); err != nil {
+		return nil, err
+	}
+	return parseFeed(body)
+}
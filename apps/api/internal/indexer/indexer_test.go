@@ -0,0 +1,195 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+)
+
+const torznabFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed">
+<channel>
+  <item>
+    <title>Fight.Club.1999.1080p.BluRay.x264-SPARKS</title>
+    <guid>https://tracker/details/1</guid>
+    <link>https://tracker/download/1</link>
+    <pubDate>Sat, 03 Oct 2026 10:00:00 +0000</pubDate>
+    <enclosure url="https://tracker/download/1" length="8000000000" type="application/x-bittorrent"/>
+    <torznab:attr name="seeders" value="42"/>
+    <torznab:attr name="infohash" value="ABCDEF0123456789ABCDEF0123456789ABCDEF01"/>
+  </item>
+  <item>
+    <title>Fight.Club.1999.720p.WEB-DL.x264</title>
+    <guid>https://tracker/details/2</guid>
+    <enclosure url="https://tracker/download/2" length="3000000000" type="application/x-bittorrent"/>
+    <torznab:attr name="magneturl" value="magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&amp;dn=x"/>
+    <torznab:attr name="size" value="3100000000"/>
+  </item>
+  <item>
+    <title></title>
+    <link>https://tracker/download/3</link>
+  </item>
+</channel>
+</rss>`
+
+func TestClient_Search_Torznab(t *testing.T) {
+	var query map[string][]string
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.Query()
+		fmt.Fprint(w, torznabFeed)
+	}))
+	defer server.Close()
+
+	cfg := Config{Type: models.IndexerTypeTorznab, URL: server.URL + "/torznab/", APIKey: "k", Categories: []int{2000, 2040}}
+	releases, err := NewClient().Search(context.Background(), cfg, SearchQuery{
+		MediaType: models.RequestMediaTypeMovie, Query: "Fight Club 1999",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/torznab/api", path)
+	assert.Equal(t, []string{"movie"}, query["t"])
+	assert.Equal(t, []string{"Fight Club 1999"}, query["q"])
+	assert.Equal(t, []string{"k"}, query["apikey"])
+	assert.Equal(t, []string{"2000,2040"}, query["cat"])
+
+	require.Len(t, releases, 2, "an item without a title is dropped")
+	assert.Equal(t, "https://tracker/download/1", releases[0].Link)
+	assert.Equal(t, "abcdef0123456789abcdef0123456789abcdef01", releases[0].InfoHash)
+	assert.Equal(t, 42, releases[0].Seeders)
+	assert.Equal(t, int64(8000000000), releases[0].Size)
+	assert.Equal(t, 2026, releases[0].PublishedAt.Year())
+
+	assert.Contains(t, releases[1].Link, "magnet:", "a magnet beats the .torrent link")
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", releases[1].InfoHash)
+	assert.Equal(t, -1, releases[1].Seeders)
+	assert.Equal(t, int64(3100000000), releases[1].Size)
+}
+
+func TestClient_Search_TVParams(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `<rss><channel></channel></rss>`)
+	}))
+	defer server.Close()
+
+	cfg := Config{Type: models.IndexerTypeNewznab, URL: server.URL + "/api"}
+	releases, err := NewClient().Search(context.Background(), cfg, SearchQuery{
+		MediaType: models.RequestMediaTypeTV, Query: "The Bear", Season: 2, Episode: 3,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, releases)
+	assert.Equal(t, []string{"tvsearch"}, query["t"])
+	assert.Equal(t, []string{"2"}, query["season"])
+	assert.Equal(t, []string{"3"}, query["ep"])
+}
+
+func TestClient_ErrorDocument(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0"?><error code="100" description="Incorrect user credentials"/>`)
+	}))
+	defer server.Close()
+
+	cfg := Config{Type: models.IndexerTypeTorznab, URL: server.URL}
+	client := NewClient()
+
+	_, err := client.Search(context.Background(), cfg, SearchQuery{Query: "x"})
+	var ixErr *Error
+	require.ErrorAs(t, err, &ixErr)
+	assert.Equal(t, ErrCodeAuthFailed, ixErr.Code)
+
+	err = client.Test(context.Background(), cfg)
+	require.ErrorAs(t, err, &ixErr)
+	assert.Equal(t, ErrCodeAuthFailed, ixErr.Code)
+}
+
+func TestClient_RSS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.URL.RawQuery, "an RSS feed is read as configured")
+		fmt.Fprint(w, `<rss><channel><item><title>The.Bear.S02E03.1080p.WEB-DL</title><link>https://feed/3.torrent</link></item></channel></rss>`)
+	}))
+	defer server.Close()
+
+	cfg := Config{Type: models.IndexerTypeRSS, URL: server.URL + "/feed"}
+	client := NewClient()
+
+	releases, err := client.Recent(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	assert.Equal(t, "https://feed/3.torrent", releases[0].GUID, "the link stands in for a missing guid")
+
+	_, err = client.Search(context.Background(), cfg, SearchQuery{Query: "x"})
+	var ixErr *Error
+	require.ErrorAs(t, err, &ixErr)
+	assert.Equal(t, ErrCodeUnsupported, ixErr.Code)
+
+	assert.NoError(t, client.Test(context.Background(), cfg))
+}
+
+func TestClient_StatusErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := NewClient().Test(context.Background(), Config{Type: models.IndexerTypeTorznab, URL: server.URL})
+	var ixErr *Error
+	require.ErrorAs(t, err, &ixErr)
+	assert.Equal(t, ErrCodeAuthFailed, ixErr.Code)
+}
+
+// TestClient_UnreachableErrorHidesAPIKey — a transport failure quotes the
+// request URL, and that text reaches logs and the indexer test result.
+func TestClient_UnreachableErrorHidesAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	client := NewClient()
+
+	err := client.Test(context.Background(), Config{Type: models.IndexerTypeTorznab, URL: server.URL, APIKey: "s3cr3t-key"})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "s3cr3t-key")
+	assert.Contains(t, err.Error(), "apikey=REDACTED")
+	var urlErr *url.Error
+	assert.ErrorAs(t, err, &urlErr, "the cause stays inspectable")
+
+	_, err = client.Download(context.Background(), server.URL+"/dl/1?jackett_apikey=s3cr3t-key&path=abc")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "s3cr3t-key")
+	assert.Contains(t, err.Error(), "path=abc")
+}
+
+func TestClient_Download(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "d4:infod4:name1:aee")
+	})
+	mux.HandleFunc("/magnet", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := NewClient()
+
+	dl, err := client.Download(context.Background(), server.URL+"/file")
+	require.NoError(t, err)
+	assert.Equal(t, "d4:infod4:name1:aee", string(dl.Torrent))
+
+	dl, err = client.Download(context.Background(), server.URL+"/magnet")
+	require.NoError(t, err)
+	assert.Equal(t, "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567", dl.Magnet)
+	assert.Nil(t, dl.Torrent)
+
+	dl, err = client.Download(context.Background(), "magnet:?xt=urn:btih:x")
+	require.NoError(t, err)
+	assert.Equal(t, "magnet:?xt=urn:btih:x", dl.Magnet)
+}
@@ -0,0 +1,113 @@
+package indexer
+
+import (
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// errMalformedTorrent is returned for bytes that are not a bencoded
+// dictionary with an info key.
+var errMalformedTorrent = errors.New("malformed torrent file")
+
+// InfoHash returns the lowercase hex v1 info-hash of a .torrent file: the
+// SHA-1 of the bencoded info dictionary exactly as it appears in the file.
+// It is what qBittorrent reports as the torrent's hash.
+func InfoHash(data []byte) (string, error) {
+	if len(data) == 0 || data[0] != 'd' {
+		return "", errMalformedTorrent
+	}
+	i := 1
+	for i < len(data) && data[i] != 'e' {
+		keyStart := i
+		keyEnd, err := bencodeEnd(data, i)
+		if err != nil || data[keyStart] < '0' || data[keyStart] > '9' {
+			return "", errMalformedTorrent
+		}
+		valueEnd, err := bencodeEnd(data, keyEnd)
+		if err != nil {
+			return "", errMalformedTorrent
+		}
+		colon := strings.IndexByte(string(data[keyStart:keyEnd]), ':')
+		if string(data[keyStart+colon+1:keyEnd]) == "info" {
+			sum := sha1.Sum(data[keyEnd:valueEnd])
+			return hex.EncodeToString(sum[:]), nil
+		}
+		i = valueEnd
+	}
+	return "", errMalformedTorrent
+}
+
+// bencodeEnd returns the index just past the bencoded value starting at i.
+func bencodeEnd(data []byte, i int) (int, error) {
+	if i >= len(data) {
+		return 0, errMalformedTorrent
+	}
+	switch c := data[i]; {
+	case c == 'i':
+		end := strings.IndexByte(string(data[i:]), 'e')
+		if end < 0 {
+			return 0, errMalformedTorrent
+		}
+		return i + end + 1, nil
+	case c == 'l' || c == 'd':
+		i++
+		for i < len(data) && data[i] != 'e' {
+			next, err := bencodeEnd(data, i)
+			if err != nil {
+				return 0, err
+			}
+			i = next
+		}
+		if i >= len(data) {
+			return 0, errMalformedTorrent
+		}
+		return i + 1, nil
+	case c >= '0' && c <= '9':
+		n, j := 0, i
+		for j < len(data) && data[j] >= '0' && data[j] <= '9' {
+			n = n*10 + int(data[j]-'0')
+			if n > len(data) {
+				return 0, errMalformedTorrent
+			}
+			j++
+		}
+		if j >= len(data) || data[j] != ':' || j+1+n > len(data) {
+			return 0, errMalformedTorrent
+		}
+		return j + 1 + n, nil
+	}
+	return 0, errMalformedTorrent
+}
+
+// MagnetInfoHash returns the lowercase hex info-hash of a magnet URI's
+// urn:btih topic (hex or base32 form), or "" when there is none.
+func MagnetInfoHash(uri string) string {
+	if !strings.HasPrefix(uri, "magnet:?") {
+		return ""
+	}
+	params, err := url.ParseQuery(strings.TrimPrefix(uri, "magnet:?"))
+	if err != nil {
+		return ""
+	}
+	for _, xt := range params["xt"] {
+		if !strings.HasPrefix(strings.ToLower(xt), "urn:btih:") {
+			continue
+		}
+		hash := xt[len("urn:btih:"):]
+		switch len(hash) {
+		case 40:
+			if _, err := hex.DecodeString(hash); err == nil {
+				return strings.ToLower(hash)
+			}
+		case 32:
+			if raw, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+				return hex.EncodeToString(raw)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package indexer
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoHash(t *testing.T) {
+	info := "d6:lengthi1024e4:name8:file.mkv12:piece lengthi16384e6:pieces20:aaaaaaaaaaaaaaaaaaaae"
+	data := []byte("d8:announce15:http://tracker/4:info" + info + "e")
+	want := sha1.Sum([]byte(info))
+
+	got, err := InfoHash(data)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(want[:]), got)
+
+	for _, bad := range []string{"", "not bencode", "d8:announce3:abce", "d4:infod4:name1:a"} {
+		_, err := InfoHash([]byte(bad))
+		assert.Error(t, err, "%q", bad)
+	}
+}
+
+func TestMagnetInfoHash(t *testing.T) {
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567",
+		MagnetInfoHash("magnet:?xt=urn:btih:0123456789ABCDEF0123456789ABCDEF01234567&dn=x"))
+	// base32 form of the same 20 bytes.
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567",
+		MagnetInfoHash("magnet:?xt=urn:btih:AERUKZ4JVPG66AJDIVTYTK6N54ASGRLH"))
+	assert.Empty(t, MagnetInfoHash("https://tracker/download/1"))
+	assert.Empty(t, MagnetInfoHash("magnet:?dn=no-topic"))
+}
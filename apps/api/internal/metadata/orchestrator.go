@@ -2,12 +2,14 @@ package metadata
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/vido/api/internal/ai"
+	"github.com/vido/api/internal/metrics"
 	"github.com/vido/api/internal/models"
 )
 
@@ -464,6 +466,7 @@ func (o *Orchestrator) tryProvider(ctx context.Context, provider MetadataProvide
 	// Execute search
 	result, err := o.executeSearchWithCircuitBreaker(ctx, provider, req)
 	attempt.Duration = time.Since(startTime)
+	observeProviderAttempt(provider.Name(), result, err, attempt.Duration)
 
 	if err != nil {
 		attempt.Success = false
@@ -494,6 +497,21 @@ func (o *Orchestrator) tryProvider(ctx context.Context, provider MetadataProvide
 	return attempt
 }
 
+// observeProviderAttempt records one executed provider search on /metrics.
+// Skipped providers never reach here: no request was made.
+func observeProviderAttempt(provider string, result *SearchResult, err error, d time.Duration) {
+	outcome := "success"
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		outcome = "circuit_open"
+	case err != nil:
+		outcome = "error"
+	case result == nil || !result.HasResults():
+		outcome = "empty"
+	}
+	metrics.ProviderRequestDuration.WithLabelValues(provider, outcome).Observe(d.Seconds())
+}
+
 // executeSearch executes a search on a provider
 func (o *Orchestrator) executeSearch(ctx context.Context, provider MetadataProvider, req *SearchRequest) (*SearchResult, error) {
 	return provider.Search(ctx, req)
@@ -542,6 +560,19 @@ func (o *Orchestrator) GetCircuitBreakerState(providerName string) (CircuitState
 	return cb.State(), true
 }
 
+// CircuitBreakerStates returns every provider breaker's current state, keyed
+// by provider name. Empty when circuit breaking is disabled.
+func (o *Orchestrator) CircuitBreakerStates() map[string]CircuitState {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	states := make(map[string]CircuitState, len(o.circuitBreakers))
+	for name, cb := range o.circuitBreakers {
+		states[name] = cb.State()
+	}
+	return states
+}
+
 // ResetCircuitBreaker resets the circuit breaker for a provider
 func (o *Orchestrator) ResetCircuitBreaker(providerName string) {
 	o.mu.RLock()
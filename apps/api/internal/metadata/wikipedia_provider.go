@@ -498,6 +498,11 @@ func (p *WikipediaProvider) ResetCircuitBreaker() {
 	p.circuitBreaker.Reset()
 }
 
+// GetClientMetrics returns the HTTP client metrics
+func (p *WikipediaProvider) GetClientMetrics() wikipedia.ClientMetrics {
+	return p.client.GetMetrics()
+}
+
 // GetCacheStats returns the cache statistics (nil if cache is not enabled)
 func (p *WikipediaProvider) GetCacheStats(ctx context.Context) (*WikipediaCacheStats, error) {
 	if p.cache == nil {
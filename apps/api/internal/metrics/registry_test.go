@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func render(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	require.NoError(t, r.WriteText(&b))
+	return b.String()
+}
+
+func TestRegistry_CounterAndGauge(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("vido_things_total", "Things done.", "kind")
+	g := r.NewGaugeVec("vido_level", "A level.")
+	c.WithLabelValues("b").Add(2)
+	c.WithLabelValues("a").Inc()
+	g.WithLabelValues().Set(1.5)
+
+	assert.Equal(t, `# HELP vido_level A level.
+# TYPE vido_level gauge
+vido_level 1.5
+# HELP vido_things_total Things done.
+# TYPE vido_things_total counter
+vido_things_total{kind="a"} 1
+vido_things_total{kind="b"} 2
+`, render(t, r), "families and series are sorted")
+}
+
+func TestRegistry_HistogramIsCumulative(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("vido_latency_seconds", "Latency.", []float64{1, 0.1}, "provider")
+	s := h.WithLabelValues("tmdb")
+	s.Observe(0.05)
+	s.Observe(0.1) // upper bounds are inclusive
+	s.Observe(0.5)
+	s.Observe(3)
+
+	out := render(t, r)
+	assert.Contains(t, out, `vido_latency_seconds_bucket{provider="tmdb",le="0.1"} 2`)
+	assert.Contains(t, out, `vido_latency_seconds_bucket{provider="tmdb",le="1"} 3`)
+	assert.Contains(t, out, `vido_latency_seconds_bucket{provider="tmdb",le="+Inf"} 4`)
+	assert.Contains(t, out, `vido_latency_seconds_sum{provider="tmdb"} 3.65`)
+	assert.Contains(t, out, `vido_latency_seconds_count{provider="tmdb"} 4`)
+}
+
+func TestRegistry_Escaping(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("vido_escaped_total", "Line one\nback\\slash.", "title").
+		WithLabelValues(`say "hi"` + "\n").Inc()
+
+	out := render(t, r)
+	assert.Contains(t, out, `# HELP vido_escaped_total Line one\nback\\slash.`)
+	assert.Contains(t, out, `vido_escaped_total{title="say \"hi\"\n"} 1`)
+}
+
+func TestRegistry_FuncFamiliesReadAtScrape(t *testing.T) {
+	r := NewRegistry()
+	depth := 3
+	r.NewGaugeFunc("vido_queue_depth", "Queue depth.", []string{"task_type"}, func(emit Emit) {
+		emit(float64(depth), "parse")
+	})
+
+	assert.Contains(t, render(t, r), `vido_queue_depth{task_type="parse"} 3`)
+	depth = 0
+	assert.Contains(t, render(t, r), `vido_queue_depth{task_type="parse"} 0`)
+}
+
+func TestRegistry_Misuse(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("vido_dup_total", "Dup.", "a")
+	assert.Panics(t, func() { r.NewGaugeVec("vido_dup_total", "Dup.") }, "duplicate name")
+	assert.Panics(t, func() { c.WithLabelValues("x", "y") }, "label count mismatch")
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("vido_hits_total", "Hits.").WithLabelValues().Inc()
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "vido_hits_total 1")
+}
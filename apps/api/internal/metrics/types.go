@@ -0,0 +1,224 @@
+package metrics
+
+import (
+	"bufio"
+	"sort"
+	"sync"
+)
+
+// ─── counters and gauges ────────────────────────────────────────────────────
+
+// Value is one series of a CounterVec or GaugeVec.
+type Value struct {
+	mu     sync.Mutex
+	values []string
+	v      float64
+}
+
+// Add adds delta to the series. Counters must only be given delta >= 0.
+func (s *Value) Add(delta float64) {
+	s.mu.Lock()
+	s.v += delta
+	s.mu.Unlock()
+}
+
+// Inc adds one.
+func (s *Value) Inc() { s.Add(1) }
+
+// Set replaces the value. Only meaningful for gauges.
+func (s *Value) Set(v float64) {
+	s.mu.Lock()
+	s.v = v
+	s.mu.Unlock()
+}
+
+func (s *Value) get() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.v
+}
+
+type valueVec struct {
+	desc
+	mu     sync.Mutex
+	series map[string]*Value
+}
+
+func (v *valueVec) describe() desc { return v.desc }
+
+func (v *valueVec) with(values []string) *Value {
+	checkLabels(v.desc, values)
+	key := seriesKey(values)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	s, ok := v.series[key]
+	if !ok {
+		s = &Value{values: append([]string(nil), values...)}
+		v.series[key] = s
+	}
+	return s
+}
+
+func (v *valueVec) write(w *bufio.Writer) {
+	v.mu.Lock()
+	keys := sortedKeys(v.series)
+	series := make([]*Value, len(keys))
+	for i, k := range keys {
+		series[i] = v.series[k]
+	}
+	v.mu.Unlock()
+	for _, s := range series {
+		writeSample(w, v.name, v.labels, s.values, "", "", s.get())
+	}
+}
+
+// CounterVec is a monotonically increasing metric partitioned by labels.
+type CounterVec struct{ vec *valueVec }
+
+// NewCounterVec registers a counter. Without labels it has a single series,
+// reached with WithLabelValues().
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	vec := &valueVec{desc: desc{name: name, help: help, typ: "counter", labels: labels}, series: map[string]*Value{}}
+	r.register(vec)
+	return &CounterVec{vec: vec}
+}
+
+// WithLabelValues returns the series for values, creating it at zero.
+func (c *CounterVec) WithLabelValues(values ...string) *Value { return c.vec.with(values) }
+
+// GaugeVec is a metric that can go up and down, partitioned by labels.
+type GaugeVec struct{ vec *valueVec }
+
+// NewGaugeVec registers a gauge.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	vec := &valueVec{desc: desc{name: name, help: help, typ: "gauge", labels: labels}, series: map[string]*Value{}}
+	r.register(vec)
+	return &GaugeVec{vec: vec}
+}
+
+// WithLabelValues returns the series for values, creating it at zero.
+func (g *GaugeVec) WithLabelValues(values ...string) *Value { return g.vec.with(values) }
+
+// ─── histograms ─────────────────────────────────────────────────────────────
+
+// Histogram is one series of a HistogramVec.
+type Histogram struct {
+	mu      sync.Mutex
+	values  []string
+	upper   []float64
+	buckets []uint64 // non-cumulative; rendered cumulatively
+	count   uint64
+	sum     float64
+}
+
+// Observe records one value.
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.upper, v)
+	h.mu.Lock()
+	if i < len(h.buckets) {
+		h.buckets[i]++
+	}
+	h.count++
+	h.sum += v
+	h.mu.Unlock()
+}
+
+// HistogramVec samples observations into buckets, partitioned by labels.
+type HistogramVec struct {
+	desc
+	upper  []float64
+	mu     sync.Mutex
+	series map[string]*Histogram
+}
+
+// NewHistogramVec registers a histogram with the given upper bounds (sorted
+// ascending; +Inf is implicit).
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	upper := append([]float64(nil), buckets...)
+	sort.Float64s(upper)
+	h := &HistogramVec{
+		desc:   desc{name: name, help: help, typ: "histogram", labels: labels},
+		upper:  upper,
+		series: map[string]*Histogram{},
+	}
+	r.register(h)
+	return h
+}
+
+// WithLabelValues returns the series for values, creating it empty.
+func (h *HistogramVec) WithLabelValues(values ...string) *Histogram {
+	checkLabels(h.desc, values)
+	key := seriesKey(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &Histogram{
+			values:  append([]string(nil), values...),
+			upper:   h.upper,
+			buckets: make([]uint64, len(h.upper)),
+		}
+		h.series[key] = s
+	}
+	return s
+}
+
+func (h *HistogramVec) describe() desc { return h.desc }
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	keys := sortedKeys(h.series)
+	series := make([]*Histogram, len(keys))
+	for i, k := range keys {
+		series[i] = h.series[k]
+	}
+	h.mu.Unlock()
+
+	for _, s := range series {
+		s.mu.Lock()
+		buckets := append([]uint64(nil), s.buckets...)
+		count, sum := s.count, s.sum
+		s.mu.Unlock()
+
+		var cumulative uint64
+		for i, upper := range h.upper {
+			cumulative += buckets[i]
+			writeSample(w, h.name+"_bucket", h.labels, s.values, "le", formatFloat(upper), float64(cumulative))
+		}
+		writeSample(w, h.name+"_bucket", h.labels, s.values, "le", "+Inf", float64(count))
+		writeSample(w, h.name+"_sum", h.labels, s.values, "", "", sum)
+		writeSample(w, h.name+"_count", h.labels, s.values, "", "", float64(count))
+	}
+}
+
+// ─── scrape-time functions ──────────────────────────────────────────────────
+
+// Emit reports one sample from a scrape-time function: the value and one
+// label value per declared label.
+type Emit func(value float64, labelValues ...string)
+
+type funcFamily struct {
+	desc
+	collect func(emit Emit)
+}
+
+func (f *funcFamily) describe() desc { return f.desc }
+
+func (f *funcFamily) write(w *bufio.Writer) {
+	f.collect(func(value float64, labelValues ...string) {
+		checkLabels(f.desc, labelValues)
+		writeSample(w, f.name, f.labels, labelValues, "", "", value)
+	})
+}
+
+// NewGaugeFunc registers a gauge whose samples are computed on every scrape —
+// for state another component already owns (queue depth, client count).
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func(emit Emit)) {
+	r.register(&funcFamily{desc: desc{name: name, help: help, typ: "gauge", labels: labels}, collect: collect})
+}
+
+// NewCounterFunc registers a counter read from a total another component
+// already keeps (a client's request counters).
+func (r *Registry) NewCounterFunc(name, help string, labels []string, collect func(emit Emit)) {
+	r.register(&funcFamily{desc: desc{name: name, help: help, typ: "counter", labels: labels}, collect: collect})
+}
@@ -0,0 +1,71 @@
+package metrics
+
+// Vido's push-style metrics, declared once in Default. State that another
+// component already owns (retry queue depth, SSE clients, circuit breakers,
+// the Douban/Wikipedia client counters) is registered as scrape-time
+// functions at wiring time instead.
+var (
+	// ScanDuration times library scans; kind is "full" or "watcher".
+	ScanDuration = Default.NewHistogramVec("vido_scan_duration_seconds",
+		"Duration of library scans.",
+		ExponentialBuckets(1, 2, 12), "kind")
+
+	// ScanFiles counts files a scan created, updated or removed, and errors.
+	ScanFiles = Default.NewCounterVec("vido_scan_files_total",
+		"Files touched by library scans, by outcome.",
+		"kind", "outcome")
+
+	// ParseOutcomes counts metadata lookups for parsed files by the
+	// MetadataSource that matched ("none" when nothing did).
+	ParseOutcomes = Default.NewCounterVec("vido_parse_outcomes_total",
+		"Metadata lookups for parsed files, by matching source and outcome.",
+		"source", "outcome")
+
+	// ProviderRequestDuration times each metadata provider attempt.
+	ProviderRequestDuration = Default.NewHistogramVec("vido_metadata_provider_request_duration_seconds",
+		"Latency of metadata provider searches, by provider and outcome.",
+		DefBuckets, "provider", "outcome")
+
+	// SubtitleRuns counts finished subtitle runs; engine is "pipeline" or
+	// "search", status the terminal stage.
+	SubtitleRuns = Default.NewCounterVec("vido_subtitle_runs_total",
+		"Finished subtitle runs, by engine and terminal status.",
+		"engine", "status")
+
+	// AITokens counts LLM tokens; direction is "input" or "output".
+	AITokens = Default.NewCounterVec("vido_ai_tokens_total",
+		"LLM tokens used, by provider, model and direction.",
+		"provider", "model", "direction")
+
+	// AICost is the metered USD cost of AI calls, LLM and ASR alike.
+	AICost = Default.NewCounterVec("vido_ai_cost_usd_total",
+		"Metered AI spend in USD, by provider and model.",
+		"provider", "model")
+
+	// ASRMinutes counts transcribed audio; endpoint is "hosted" or
+	// "self_hosted".
+	ASRMinutes = Default.NewCounterVec("vido_asr_audio_minutes_total",
+		"Audio minutes sent to speech recognition, by endpoint.",
+		"endpoint")
+)
+
+// AIUsage feeds AITokens, AICost and ASRMinutes. It satisfies ai.UsageObserver
+// without this package importing ai.
+type AIUsage struct{}
+
+// ObserveLLM records one LLM call.
+func (AIUsage) ObserveLLM(provider, model string, inputTokens, outputTokens int64, costUSD float64) {
+	AITokens.WithLabelValues(provider, model, "input").Add(float64(inputTokens))
+	AITokens.WithLabelValues(provider, model, "output").Add(float64(outputTokens))
+	AICost.WithLabelValues(provider, model).Add(costUSD)
+}
+
+// ObserveASR records one transcription.
+func (AIUsage) ObserveASR(model string, audioSeconds, costUSD float64, selfHosted bool) {
+	endpoint := "hosted"
+	if selfHosted {
+		endpoint = "self_hosted"
+	}
+	ASRMinutes.WithLabelValues(endpoint).Add(audioSeconds / 60)
+	AICost.WithLabelValues("asr", model).Add(costUSD)
+}
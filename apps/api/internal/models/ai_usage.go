@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// AIUsage is one row of the AI spend ledger: a single metered LLM call or
+// transcription, priced when it was made.
+type AIUsage struct {
+	ID           int64     `db:"id" json:"id"`
+	Provider     string    `db:"provider" json:"provider"`
+	Model        string    `db:"model" json:"model"`
+	Feature      string    `db:"feature" json:"feature"`
+	MediaID      string    `db:"media_id" json:"media_id,omitempty"`
+	RunID        string    `db:"run_id" json:"run_id,omitempty"`
+	InputTokens  int64     `db:"input_tokens" json:"input_tokens"`
+	OutputTokens int64     `db:"output_tokens" json:"output_tokens"`
+	AudioSeconds float64   `db:"audio_seconds" json:"audio_seconds"`
+	CostUSD      float64   `db:"cost_usd" json:"cost_usd"`
+	UsageDate    string    `db:"usage_date" json:"usage_date"`
+	RecordedAt   time.Time `db:"recorded_at" json:"recorded_at"`
+}
+
+// AIUsageRollup sums the ledger rows that share a key — a day, a month, a
+// provider, a model, a feature, a media item or a run.
+type AIUsageRollup struct {
+	Key          string  `json:"key"`
+	Calls        int     `json:"calls"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	AudioSeconds float64 `json:"audio_seconds"`
+	CostUSD      float64 `json:"cost_usd"`
+}
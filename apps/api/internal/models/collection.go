@@ -0,0 +1,51 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Collection is a TMDb movie collection (a franchise such as "Harry Potter")
+// that at least one library movie belongs to. Parts is TMDb's member list,
+// captured at enrichment time so listing collections needs no TMDb round
+// trip; which parts are owned is answered per request, never stored.
+type Collection struct {
+	ID           int64            `db:"id" json:"id"` // the TMDb collection id
+	Name         string           `db:"name" json:"name"`
+	Overview     string           `db:"overview" json:"overview,omitempty"`
+	PosterPath   string           `db:"poster_path" json:"poster_path,omitempty"`
+	BackdropPath string           `db:"backdrop_path" json:"backdrop_path,omitempty"`
+	Parts        []CollectionPart `db:"parts" json:"parts"`
+	CreatedAt    time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// CollectionPart is one movie of a collection.
+type CollectionPart struct {
+	TMDbID      int64  `json:"tmdb_id"`
+	Title       string `json:"title"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	PosterPath  string `json:"poster_path,omitempty"`
+}
+
+// Released reports whether the part has come out by now. Announced sequels
+// carry no date or a future one.
+func (p CollectionPart) Released(now time.Time) bool {
+	if p.ReleaseDate == "" {
+		return false
+	}
+	date, err := time.Parse("2006-01-02", p.ReleaseDate)
+	return err == nil && !date.After(now)
+}
+
+// SortCollectionParts orders parts by release date, undated announcements
+// last — the order a franchise is watched in.
+func SortCollectionParts(parts []CollectionPart) {
+	sort.SliceStable(parts, func(i, j int) bool {
+		a, b := parts[i].ReleaseDate, parts[j].ReleaseDate
+		if a == "" || b == "" {
+			return a != "" && b == ""
+		}
+		return a < b
+	})
+}
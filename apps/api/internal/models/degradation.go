@@ -36,6 +36,16 @@ const (
 	// ServicesHealth model below; plugin health lives in plugins.Manager.
 	ServiceNameRadarr ServiceName = "radarr"
 	ServiceNameSonarr ServiceName = "sonarr"
+	// Media-server plugins, same arrangement as the DVR plugins.
+	ServiceNameJellyfin ServiceName = "jellyfin"
+	ServiceNameEmby     ServiceName = "emby"
+	// Plex is a media-server plugin too, but its health is also shown in
+	// ServicesHealth, outside the degradation count (see AllServices).
+	ServiceNamePlex ServiceName = "plex"
+	// The alternative download clients. They are shown in ServicesHealth
+	// like Plex, outside the degradation count.
+	ServiceNameTransmission ServiceName = "transmission"
+	ServiceNameDeluge       ServiceName = "deluge"
 )
 
 // Service status constants
@@ -200,8 +210,8 @@ func isUnconfiguredError(msg string) bool {
 
 // AllServiceStatuses returns all service statuses in the settings dashboard format
 func (s *ServicesHealth) AllServiceStatuses() []ServiceStatus {
-	statuses := make([]ServiceStatus, 0, 5)
-	for _, svc := range s.AllServices() {
+	statuses := make([]ServiceStatus, 0, 8)
+	for _, svc := range append(s.AllServices(), s.Plex, s.Transmission, s.Deluge) {
 		statuses = append(statuses, svc.ToServiceStatus())
 	}
 	return statuses
@@ -240,21 +250,27 @@ type FieldAvailability struct {
 
 // ServicesHealth holds health status for all external services
 type ServicesHealth struct {
-	TMDb        *ServiceHealth `json:"tmdb"`
-	Douban      *ServiceHealth `json:"douban"`
-	Wikipedia   *ServiceHealth `json:"wikipedia"`
-	AI          *ServiceHealth `json:"ai"`
-	QBittorrent *ServiceHealth `json:"qbittorrent"`
+	TMDb         *ServiceHealth `json:"tmdb"`
+	Douban       *ServiceHealth `json:"douban"`
+	Wikipedia    *ServiceHealth `json:"wikipedia"`
+	AI           *ServiceHealth `json:"ai"`
+	QBittorrent  *ServiceHealth `json:"qbittorrent"`
+	Plex         *ServiceHealth `json:"plex"`
+	Transmission *ServiceHealth `json:"transmission"`
+	Deluge       *ServiceHealth `json:"deluge"`
 }
 
 // NewServicesHealth creates a new ServicesHealth with all services healthy
 func NewServicesHealth() *ServicesHealth {
 	return &ServicesHealth{
-		TMDb:        NewServiceHealth(string(ServiceNameTMDb), "TMDb API"),
-		Douban:      NewServiceHealth(string(ServiceNameDouban), "Douban Scraper"),
-		Wikipedia:   NewServiceHealth(string(ServiceNameWikipedia), "Wikipedia API"),
-		AI:          NewServiceHealth(string(ServiceNameAI), "AI Parser"),
-		QBittorrent: NewServiceHealth(string(ServiceNameQBittorrent), "qBittorrent"),
+		TMDb:         NewServiceHealth(string(ServiceNameTMDb), "TMDb API"),
+		Douban:       NewServiceHealth(string(ServiceNameDouban), "Douban Scraper"),
+		Wikipedia:    NewServiceHealth(string(ServiceNameWikipedia), "Wikipedia API"),
+		AI:           NewServiceHealth(string(ServiceNameAI), "AI Parser"),
+		QBittorrent:  NewServiceHealth(string(ServiceNameQBittorrent), "qBittorrent"),
+		Plex:         NewServiceHealth(string(ServiceNamePlex), "Plex Media Server"),
+		Transmission: NewServiceHealth(string(ServiceNameTransmission), "Transmission"),
+		Deluge:       NewServiceHealth(string(ServiceNameDeluge), "Deluge"),
 	}
 }
 
@@ -271,12 +287,22 @@ func (s *ServicesHealth) GetService(name ServiceName) *ServiceHealth {
 		return s.AI
 	case ServiceNameQBittorrent:
 		return s.QBittorrent
+	case ServiceNamePlex:
+		return s.Plex
+	case ServiceNameTransmission:
+		return s.Transmission
+	case ServiceNameDeluge:
+		return s.Deluge
 	default:
 		return nil
 	}
 }
 
-// AllServices returns all service health statuses as a slice
+// AllServices returns the health of the services the degradation level is
+// computed from. Plex is left out: a media server being away makes no
+// metadata unavailable. Transmission and Deluge are left out too; the
+// qBittorrent entry stands for downloads, as it did before there was a
+// choice of client.
 func (s *ServicesHealth) AllServices() []*ServiceHealth {
 	return []*ServiceHealth{s.TMDb, s.Douban, s.Wikipedia, s.AI, s.QBittorrent}
 }
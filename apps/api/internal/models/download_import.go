@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Download import statuses (migration 039 CHECK enum).
+const (
+	DownloadImportImported = "imported"
+	DownloadImportSkipped  = "skipped"
+	DownloadImportFailed   = "failed"
+)
+
+// DownloadImport records what the post-download importer did with one
+// finished torrent. Skipped means there was nothing to import or nowhere to
+// put it (no video, no matching library, an unidentifiable name); failed
+// means files could not be linked or copied. Both are final until the row
+// is deleted, which makes the importer try again.
+type DownloadImport struct {
+	TorrentHash   string     `db:"torrent_hash" json:"torrent_hash"`
+	TorrentName   string     `db:"torrent_name" json:"torrent_name"`
+	Status        string     `db:"status" json:"status"`
+	RequestID     NullString `db:"request_id" json:"request_id"`
+	LibraryID     NullString `db:"library_id" json:"library_id"`
+	TargetPath    NullString `db:"target_path" json:"target_path"`
+	FilesImported int        `db:"files_imported" json:"files_imported"`
+	// Hardlinked is false when at least one file had to be copied, which
+	// costs the space of the release a second time.
+	Hardlinked bool       `db:"hardlinked" json:"hardlinked"`
+	Message    NullString `db:"message" json:"message"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+}
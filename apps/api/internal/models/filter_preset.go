@@ -19,6 +19,7 @@ const (
 // P2-015). Filters is stored as an opaque JSON string matching the URL search
 // param format (e.g. {"genre":"28","year_gte":"2024","region":"KR"}); the
 // frontend owns its serialization so the API never key-transforms its contents.
+// watch_status (watched|unwatched|in_progress) is applied per signed-in user.
 type FilterPreset struct {
 	ID        string    `db:"id" json:"id"`
 	Name      string    `db:"name" json:"name"`
@@ -43,5 +44,14 @@ func (p *FilterPreset) Validate() error {
 	if !json.Valid([]byte(p.Filters)) {
 		return &ValidationError{Field: "filters", Message: "filters must be valid JSON"}
 	}
+	// The contents stay opaque except for keys the API itself filters on, so
+	// a preset cannot save a value the library list would reject.
+	var known struct {
+		WatchStatus *string `json:"watch_status"`
+	}
+	if json.Unmarshal([]byte(p.Filters), &known) == nil && known.WatchStatus != nil &&
+		*known.WatchStatus != "" && !IsValidWatchStatus(*known.WatchStatus) {
+		return &ValidationError{Field: "filters", Message: "watch_status must be watched, unwatched or in_progress"}
+	}
 	return nil
 }
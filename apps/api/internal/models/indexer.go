@@ -0,0 +1,72 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Indexer types (migration 038 CHECK enum).
+const (
+	IndexerTypeTorznab = "torznab"
+	IndexerTypeNewznab = "newznab"
+	IndexerTypeRSS     = "rss"
+)
+
+// IsValidIndexerType reports whether t is a supported indexer type.
+func IsValidIndexerType(t string) bool {
+	switch t {
+	case IndexerTypeTorznab, IndexerTypeNewznab, IndexerTypeRSS:
+		return true
+	}
+	return false
+}
+
+// Indexer is one release source for the built-in fulfilment path. URL is the
+// Torznab/Newznab endpoint or the RSS feed address; Categories narrows a
+// Torznab/Newznab query (empty = the indexer's default). The API key lives in
+// the secrets store and is only reported back as HasAPIKey.
+type Indexer struct {
+	ID         string    `db:"id" json:"id"`
+	Name       string    `db:"name" json:"name"`
+	Type       string    `db:"type" json:"type"`
+	URL        string    `db:"url" json:"url"`
+	Categories []int     `db:"categories" json:"categories"`
+	Enabled    bool      `db:"enabled" json:"enabled"`
+	HasAPIKey  bool      `db:"-" json:"has_api_key"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Validate checks the indexer fields.
+func (i *Indexer) Validate() error {
+	if strings.TrimSpace(i.Name) == "" {
+		return &ValidationError{Field: "name", Message: "name is required"}
+	}
+	if !IsValidIndexerType(i.Type) {
+		return &ValidationError{Field: "type", Message: "type must be one of torznab, newznab, rss"}
+	}
+	u, err := url.Parse(i.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &ValidationError{Field: "url", Message: "url must be an http(s) address"}
+	}
+	for _, cat := range i.Categories {
+		if cat <= 0 {
+			return &ValidationError{Field: "categories", Message: "categories must be positive numbers"}
+		}
+	}
+	return nil
+}
+
+// Searchable reports whether the indexer answers queries; RSS feeds can only
+// be read.
+func (i *Indexer) Searchable() bool {
+	return i.Type == IndexerTypeTorznab || i.Type == IndexerTypeNewznab
+}
+
+// Grabbable reports whether the built-in path can hand the indexer's releases
+// to the download client. That client is qBittorrent, and Newznab releases
+// are NZBs for a Usenet downloader.
+func (i *Indexer) Grabbable() bool {
+	return i.Type != IndexerTypeNewznab
+}
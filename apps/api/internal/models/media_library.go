@@ -34,10 +34,21 @@ type MediaLibrary struct {
 	// delivered as-is, or a Simplified one converted locally. Anything that
 	// would bill (LLM translation, speech recognition) still waits for explicit
 	// consent on the estimate screen.
-	AutoSubtitle bool      `db:"auto_subtitle" json:"auto_subtitle"`
-	SortOrder    int       `db:"sort_order" json:"sort_order"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	AutoSubtitle bool `db:"auto_subtitle" json:"auto_subtitle"`
+	// OrganizeTemplate is the layout the organizer renames files into, relative
+	// to the library path that holds them. Empty selects the built-in template
+	// for the content type (services.DefaultOrganizeTemplate).
+	OrganizeTemplate string `db:"organize_template" json:"organize_template"`
+	// SubtitleOutputMode is how generated translations are delivered: empty
+	// (translated only) or one of the bilingual layouts, for households that
+	// want the source line kept under the translation.
+	SubtitleOutputMode SubtitleOutputMode `db:"subtitle_output_mode" json:"subtitle_output_mode"`
+	// QualityProfileID is the quality profile this library's files are judged
+	// against by the upgrade evaluator. NULL means none: nothing is evaluated.
+	QualityProfileID NullString `db:"quality_profile_id" json:"quality_profile_id"`
+	SortOrder        int        `db:"sort_order" json:"sort_order"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
 }
 
 // MediaLibraryPath represents a filesystem path belonging to a library.
@@ -68,6 +79,9 @@ func (ml *MediaLibrary) Validate() error {
 	if ml.ContentType != ContentTypeMovie && ml.ContentType != ContentTypeSeries {
 		return &ValidationError{Field: "content_type", Message: "content type must be 'movie' or 'series'"}
 	}
+	if !ml.SubtitleOutputMode.IsValid() {
+		return &ValidationError{Field: "subtitle_output_mode", Message: "subtitle_output_mode must be empty, 'translated', 'bilingual_srt' or 'bilingual_ass'"}
+	}
 	return nil
 }
 
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// NotificationEventType names what happened. The values reuse the SSE event
+// names where one exists, so a subscription reads the same as the live stream.
+type NotificationEventType string
+
+const (
+	NotificationEventScanComplete       NotificationEventType = "scan_complete"
+	NotificationEventSubtitleComplete   NotificationEventType = "subtitle_complete"
+	NotificationEventSubtitleFailed     NotificationEventType = "subtitle_failed"
+	NotificationEventRequestStatus      NotificationEventType = "request_status"
+	NotificationEventBackupFailed       NotificationEventType = "backup_failed"
+	NotificationEventDegradationChanged NotificationEventType = "degradation_changed"
+	// NotificationEventTest is only ever sent by the test-send endpoint; it
+	// cannot be subscribed to.
+	NotificationEventTest NotificationEventType = "test"
+)
+
+// NotificationEventTypes lists the subscribable events, in display order.
+func NotificationEventTypes() []NotificationEventType {
+	return []NotificationEventType{
+		NotificationEventScanComplete,
+		NotificationEventSubtitleComplete,
+		NotificationEventSubtitleFailed,
+		NotificationEventRequestStatus,
+		NotificationEventBackupFailed,
+		NotificationEventDegradationChanged,
+	}
+}
+
+// IsValidNotificationEventType reports whether t can be subscribed to.
+func IsValidNotificationEventType(t NotificationEventType) bool {
+	for _, known := range NotificationEventTypes() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification target types (migration 035 CHECK enum).
+const (
+	NotificationTargetWebhook  = "webhook"
+	NotificationTargetDiscord  = "discord"
+	NotificationTargetTelegram = "telegram"
+	NotificationTargetNtfy     = "ntfy"
+	NotificationTargetGotify   = "gotify"
+	NotificationTargetSMTP     = "smtp"
+)
+
+// IsValidNotificationTargetType reports whether t is a supported target type.
+func IsValidNotificationTargetType(t string) bool {
+	switch t {
+	case NotificationTargetWebhook, NotificationTargetDiscord, NotificationTargetTelegram,
+		NotificationTargetNtfy, NotificationTargetGotify, NotificationTargetSMTP:
+		return true
+	}
+	return false
+}
+
+// NotificationEvent is one occurrence handed to the notification service.
+// Title and Message are the default zh-TW rendering; Data carries the fields
+// a per-target template may use instead.
+type NotificationEvent struct {
+	ID         string                 `json:"id"`
+	Type       NotificationEventType  `json:"type"`
+	Title      string                 `json:"title"`
+	Message    string                 `json:"message"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+// NotificationTarget is one configured destination. Config holds the
+// non-secret, type-specific settings (URLs, chat IDs, SMTP host); the one
+// credential a type needs lives in the secrets store and is only reported
+// back as HasSecret. Empty templates fall back to the event's own title and
+// message.
+type NotificationTarget struct {
+	ID            string                  `db:"id" json:"id"`
+	Name          string                  `db:"name" json:"name"`
+	Type          string                  `db:"type" json:"type"`
+	Enabled       bool                    `db:"enabled" json:"enabled"`
+	Events        []NotificationEventType `db:"events" json:"events"`
+	Config        map[string]string       `db:"config" json:"config"`
+	TitleTemplate string                  `db:"title_template" json:"title_template"`
+	BodyTemplate  string                  `db:"body_template" json:"body_template"`
+	HasSecret     bool                    `db:"-" json:"has_secret"`
+	CreatedAt     time.Time               `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time               `db:"updated_at" json:"updated_at"`
+}
+
+// Validate checks the fields common to every target type.
+func (t *NotificationTarget) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return &ValidationError{Field: "name", Message: "name is required"}
+	}
+	if !IsValidNotificationTargetType(t.Type) {
+		return &ValidationError{Field: "type", Message: "type must be one of webhook, discord, telegram, ntfy, gotify, smtp"}
+	}
+	if len(t.Events) == 0 {
+		return &ValidationError{Field: "events", Message: "subscribe to at least one event"}
+	}
+	for _, e := range t.Events {
+		if !IsValidNotificationEventType(e) {
+			return &ValidationError{Field: "events", Message: "unknown event type: " + string(e)}
+		}
+	}
+	return nil
+}
+
+// Subscribes reports whether the target wants events of type e.
+func (t *NotificationTarget) Subscribes(e NotificationEventType) bool {
+	for _, s := range t.Events {
+		if s == e {
+			return true
+		}
+	}
+	return false
+}
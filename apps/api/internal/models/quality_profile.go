@@ -0,0 +1,111 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// QualityResolutions lists the resolution labels a quality profile speaks,
+// best first. They are the parser's normalized labels, and the same buckets
+// ffprobe's WxH is folded into.
+func QualityResolutions() []string {
+	return []string{"2160p", "1080p", "720p", "576p", "480p"}
+}
+
+// QualityResolutionRank orders resolution labels; 0 means unknown.
+func QualityResolutionRank(label string) int {
+	resolutions := QualityResolutions()
+	for i, r := range resolutions {
+		if r == label {
+			return len(resolutions) - i
+		}
+	}
+	return 0
+}
+
+// QualityProfile is a Vido-native quality profile assigned per media
+// library. Resolutions, Sources and Codecs are allow-lists (empty = any);
+// Sources and Codecs use the parser's vocabulary ("BluRay", "WEB-DL",
+// "x265") and match case-insensitively. Cutoff is the resolution at which a
+// file stops being upgraded; empty means the profile never asks for one.
+type QualityProfile struct {
+	ID          string    `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Resolutions []string  `db:"resolutions" json:"resolutions"`
+	Sources     []string  `db:"sources" json:"sources"`
+	Codecs      []string  `db:"codecs" json:"codecs"`
+	Cutoff      string    `db:"cutoff" json:"cutoff"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Validate checks the profile fields.
+func (p *QualityProfile) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return &ValidationError{Field: "name", Message: "name is required"}
+	}
+	if len(p.Name) > 255 {
+		return &ValidationError{Field: "name", Message: "name must be 255 characters or fewer"}
+	}
+	for _, r := range p.Resolutions {
+		if QualityResolutionRank(r) == 0 {
+			return &ValidationError{Field: "resolutions", Message: "unknown resolution: " + r}
+		}
+	}
+	if p.Cutoff != "" {
+		if QualityResolutionRank(p.Cutoff) == 0 {
+			return &ValidationError{Field: "cutoff", Message: "unknown cutoff resolution: " + p.Cutoff}
+		}
+		if len(p.Resolutions) > 0 && !containsFold(p.Resolutions, p.Cutoff) {
+			return &ValidationError{Field: "cutoff", Message: "cutoff must be one of the allowed resolutions"}
+		}
+	}
+	return nil
+}
+
+// MediaQuality is what is known about one file's quality. Empty fields are
+// unknown and never count against a profile.
+type MediaQuality struct {
+	Resolution string `json:"resolution,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Codec      string `json:"codec,omitempty"`
+}
+
+// Quality verdict reasons, in the order Evaluate checks them.
+const (
+	QualityReasonBelowCutoff          = "below_cutoff"
+	QualityReasonResolutionNotAllowed = "resolution_not_allowed"
+	QualityReasonSourceNotAllowed     = "source_not_allowed"
+	QualityReasonCodecNotAllowed      = "codec_not_allowed"
+)
+
+// Evaluate returns why q falls short of the profile; nil means it meets it.
+// Only the cutoff and the allow-lists are judged — a file at or above the
+// cutoff with an allowed source and codec is done, however good the
+// alternatives.
+func (p *QualityProfile) Evaluate(q MediaQuality) []string {
+	var reasons []string
+	if p.Cutoff != "" && q.Resolution != "" &&
+		QualityResolutionRank(q.Resolution) < QualityResolutionRank(p.Cutoff) {
+		reasons = append(reasons, QualityReasonBelowCutoff)
+	}
+	if q.Resolution != "" && len(p.Resolutions) > 0 && !containsFold(p.Resolutions, q.Resolution) {
+		reasons = append(reasons, QualityReasonResolutionNotAllowed)
+	}
+	if q.Source != "" && len(p.Sources) > 0 && !containsFold(p.Sources, q.Source) {
+		reasons = append(reasons, QualityReasonSourceNotAllowed)
+	}
+	if q.Codec != "" && len(p.Codecs) > 0 && !containsFold(p.Codecs, q.Codec) {
+		reasons = append(reasons, QualityReasonCodecNotAllowed)
+	}
+	return reasons
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
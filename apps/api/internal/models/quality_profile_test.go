@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQualityProfile_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile QualityProfile
+		field   string
+	}{
+		{"valid", QualityProfile{Name: "HD", Resolutions: []string{"1080p", "720p"}, Cutoff: "1080p"}, ""},
+		{"no cutoff", QualityProfile{Name: "Any"}, ""},
+		{"empty name", QualityProfile{Name: " "}, "name"},
+		{"unknown resolution", QualityProfile{Name: "X", Resolutions: []string{"8k"}}, "resolutions"},
+		{"unknown cutoff", QualityProfile{Name: "X", Cutoff: "1440p"}, "cutoff"},
+		{"cutoff not allowed", QualityProfile{Name: "X", Resolutions: []string{"720p"}, Cutoff: "1080p"}, "cutoff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Validate()
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var ve *ValidationError
+			require.ErrorAs(t, err, &ve)
+			assert.Equal(t, tt.field, ve.Field)
+		})
+	}
+}
+
+func TestQualityProfile_Evaluate(t *testing.T) {
+	profile := QualityProfile{
+		Name:        "HD",
+		Resolutions: []string{"2160p", "1080p", "720p"},
+		Sources:     []string{"BluRay", "WEB-DL"},
+		Codecs:      []string{"x264", "x265"},
+		Cutoff:      "1080p",
+	}
+
+	assert.Empty(t, profile.Evaluate(MediaQuality{Resolution: "1080p", Source: "bluray", Codec: "x265"}))
+	assert.Empty(t, profile.Evaluate(MediaQuality{}), "unknown quality is never held against a file")
+	assert.Equal(t, []string{QualityReasonBelowCutoff},
+		profile.Evaluate(MediaQuality{Resolution: "720p", Source: "WEB-DL"}))
+	assert.Equal(t, []string{QualityReasonBelowCutoff, QualityReasonResolutionNotAllowed, QualityReasonSourceNotAllowed},
+		profile.Evaluate(MediaQuality{Resolution: "480p", Source: "HDTV"}))
+	assert.Equal(t, []string{QualityReasonCodecNotAllowed},
+		profile.Evaluate(MediaQuality{Resolution: "2160p", Codec: "XviD"}))
+}
@@ -34,6 +34,11 @@ const (
 // Request records a user's intent to acquire a title (Story 13-1a, G-1/P3-001).
 // Rows are born pending; fulfilment (13-4) and status transitions (13-3a)
 // happen downstream. The JSON shape carries [@contract-v1] (13-1a AC #2/#3).
+//
+// UpgradeCutoff is additive: it marks a quality upgrade of an already-owned
+// title raised by the quality evaluator, and holds the resolution the file
+// must reach before the request counts as done. NULL on every ordinary
+// acquisition request.
 type Request struct {
 	ID               string     `db:"id" json:"id"`
 	TMDbID           int64      `db:"tmdb_id" json:"tmdb_id"`
@@ -45,6 +50,7 @@ type Request struct {
 	Seasons          NullString `db:"seasons" json:"seasons"`
 	Episodes         NullString `db:"episodes" json:"episodes"`
 	ErrorMessage     NullString `db:"error_message" json:"error_message"`
+	UpgradeCutoff    NullString `db:"upgrade_cutoff" json:"upgrade_cutoff"`
 	RequestedAt      time.Time  `db:"requested_at" json:"requested_at"`
 	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
 }
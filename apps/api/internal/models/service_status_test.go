@@ -121,7 +121,7 @@ func TestServicesHealth_AllServiceStatuses(t *testing.T) {
 	services.AI.RecordError("not configured")
 
 	statuses := services.AllServiceStatuses()
-	require.Len(t, statuses, 5)
+	require.Len(t, statuses, 8, "the five degradation services plus Plex, Transmission and Deluge")
 
 	// Find TMDb
 	var tmdb *ServiceStatus
@@ -57,6 +57,40 @@ func (s SubtitleRunStatus) IsValid() bool {
 	return false
 }
 
+// SubtitleOutputMode is the layout a translated subtitle is delivered in.
+// It is chosen per library and may be overridden per run.
+type SubtitleOutputMode string
+
+const (
+	// SubtitleOutputDefault on a library means "translated only"; on a run
+	// request it means "use the library's mode".
+	SubtitleOutputDefault SubtitleOutputMode = ""
+	// SubtitleOutputTranslated is the shipped behaviour: one zh-Hant sidecar
+	// that replaces the source text.
+	SubtitleOutputTranslated SubtitleOutputMode = "translated"
+	// SubtitleOutputBilingualSRT stacks the translated line over the source
+	// line in every cue of one SRT.
+	SubtitleOutputBilingualSRT SubtitleOutputMode = "bilingual_srt"
+	// SubtitleOutputBilingualASS writes an ASS script with the translation and
+	// the source as separate top and bottom styles.
+	SubtitleOutputBilingualASS SubtitleOutputMode = "bilingual_ass"
+)
+
+// IsValid reports whether m is a known mode (the empty default included).
+func (m SubtitleOutputMode) IsValid() bool {
+	switch m {
+	case SubtitleOutputDefault, SubtitleOutputTranslated, SubtitleOutputBilingualSRT, SubtitleOutputBilingualASS:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsBilingual reports whether m delivers source and translation together.
+func (m SubtitleOutputMode) IsBilingual() bool {
+	return m == SubtitleOutputBilingualSRT || m == SubtitleOutputBilingualASS
+}
+
 // RunVersion is the identity of "which inputs produced this translation".
 //
 // [@contract-v1] (story sub-1-2 AC #4) — consumed by sub-1-5b, which composes
@@ -82,6 +116,12 @@ type RunVersion struct {
 	PromptVersion string
 	// ModelID is the model that produced the translation, e.g. "claude-haiku-4-5".
 	ModelID string
+	// OutputMode is the bilingual layout the run delivered; "" for the
+	// translated-only sidecar every run before bilingual mode wrote. It is part
+	// of the RESUME identity but deliberately NOT of the segment-cache key: a
+	// cue's translation is the same whichever layout it is delivered in, so
+	// switching a library to bilingual reuses every cached cue.
+	OutputMode string
 }
 
 // Equal reports tuple equality — the resume predicate. Any single differing
@@ -91,7 +131,8 @@ func (v RunVersion) Equal(other RunVersion) bool {
 	return v.MetadataHash == other.MetadataHash &&
 		v.GlossaryVersion == other.GlossaryVersion &&
 		v.PromptVersion == other.PromptVersion &&
-		v.ModelID == other.ModelID
+		v.ModelID == other.ModelID &&
+		v.OutputMode == other.OutputMode
 }
 
 // SubtitleRun is one item-grain provenance record: which inputs produced one
@@ -109,6 +150,7 @@ type SubtitleRun struct {
 	GlossaryVersion string            `db:"glossary_version" json:"glossary_version"`
 	PromptVersion   string            `db:"prompt_version" json:"prompt_version"`
 	ModelID         string            `db:"model_id" json:"model_id"`
+	OutputMode      string            `db:"output_mode" json:"output_mode,omitempty"`
 	Status          SubtitleRunStatus `db:"status" json:"status"`
 	SourceLanguage  string            `db:"source_language" json:"source_language,omitempty"`
 	OutputPath      string            `db:"output_path" json:"output_path,omitempty"`
@@ -145,5 +187,6 @@ func (r *SubtitleRun) Version() RunVersion {
 		GlossaryVersion: r.GlossaryVersion,
 		PromptVersion:   r.PromptVersion,
 		ModelID:         r.ModelID,
+		OutputMode:      r.OutputMode,
 	}
 }
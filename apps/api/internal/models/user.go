@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// User roles (migration 033 CHECK enum). An admin manages the install —
+// settings, API keys, backups, libraries and other accounts; a user browses
+// and requests.
+const (
+	UserRoleAdmin = "admin"
+	UserRoleUser  = "user"
+)
+
+// IsValidUserRole reports whether role is one of the enum values.
+func IsValidUserRole(role string) bool {
+	return role == UserRoleAdmin || role == UserRoleUser
+}
+
+// User is an API account. PasswordHash never leaves the server.
+type User struct {
+	ID           string     `db:"id" json:"id"`
+	Username     string     `db:"username" json:"username"`
+	PasswordHash string     `db:"password_hash" json:"-"`
+	Role         string     `db:"role" json:"role"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
+	LastLoginAt  *time.Time `db:"last_login_at" json:"last_login_at,omitempty"`
+}
+
+// IsAdmin reports whether the user holds the admin role.
+func (u *User) IsAdmin() bool {
+	return u != nil && u.Role == UserRoleAdmin
+}
+
+// Session is a server-side login. ID is the SHA-256 of the token handed to the
+// client, never the token itself.
+type Session struct {
+	ID        string    `db:"id" json:"-"`
+	UserID    string    `db:"user_id" json:"user_id"`
+	UserAgent string    `db:"user_agent" json:"user_agent"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+}
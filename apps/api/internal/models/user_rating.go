@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// User rating media types (migration 040 CHECK enum). Shows are rated as a
+// whole, in the library's own vocabulary.
+const (
+	RatingMediaMovie  = "movie"
+	RatingMediaSeries = "series"
+)
+
+// User rating sources.
+const (
+	RatingSourceVido  = "vido"
+	RatingSourceTrakt = "trakt"
+)
+
+// UserRating is one user's own rating of a movie or a show, 1 to 10 as on
+// Trakt.
+type UserRating struct {
+	UserID    string    `db:"user_id" json:"-"`
+	MediaType string    `db:"media_type" json:"media_type"`
+	MediaID   string    `db:"media_id" json:"media_id"`
+	Rating    int       `db:"rating" json:"rating"`
+	Source    string    `db:"source" json:"source"`
+	RatedAt   time.Time `db:"rated_at" json:"rated_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
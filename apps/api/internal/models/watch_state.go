@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Watch-state media types (migration 034 CHECK enum). Series progress is
+// derived from their episodes, never stored.
+const (
+	WatchMediaMovie   = "movie"
+	WatchMediaEpisode = "episode"
+)
+
+// Watch status filter values, accepted as `watch_status` by the library list
+// and inside saved filter presets.
+const (
+	WatchStatusWatched    = "watched"
+	WatchStatusUnwatched  = "unwatched"
+	WatchStatusInProgress = "in_progress"
+)
+
+// IsValidWatchStatus reports whether s is a watch_status filter value.
+func IsValidWatchStatus(s string) bool {
+	return s == WatchStatusWatched || s == WatchStatusUnwatched || s == WatchStatusInProgress
+}
+
+// WatchState is one user's play state for a movie or an episode. Watched is
+// sticky across a rewatch: PositionSeconds > 0 on a watched item means it is
+// being played again.
+type WatchState struct {
+	UserID          string     `db:"user_id" json:"-"`
+	MediaType       string     `db:"media_type" json:"media_type"`
+	MediaID         string     `db:"media_id" json:"media_id"`
+	SeriesID        NullString `db:"series_id" json:"series_id,omitempty"`
+	PositionSeconds float64    `db:"position_seconds" json:"position_seconds"`
+	DurationSeconds float64    `db:"duration_seconds" json:"duration_seconds"`
+	Watched         bool       `db:"watched" json:"watched"`
+	WatchCount      int        `db:"watch_count" json:"watch_count"`
+	LastWatchedAt   *time.Time `db:"last_watched_at" json:"last_watched_at,omitempty"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// InProgress reports whether playback stopped part-way through.
+func (w *WatchState) InProgress() bool {
+	return w != nil && w.PositionSeconds > 0
+}
+
+// Progress is the played fraction in [0, 1], or 0 when the duration is unknown.
+func (w *WatchState) Progress() float64 {
+	if w == nil || w.DurationSeconds <= 0 {
+		return 0
+	}
+	return min(w.PositionSeconds/w.DurationSeconds, 1)
+}
@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vido/api/internal/retry"
+)
+
+// Default public endpoints; both can be overridden per target for a
+// self-hosted server (and by tests).
+const (
+	defaultTelegramAPI = "https://api.telegram.org"
+	defaultNtfyServer  = "https://ntfy.sh"
+)
+
+// webhookPayload is the generic webhook body. Its keys are the contract for
+// anyone consuming Vido webhooks.
+type webhookPayload struct {
+	Event      string                 `json:"event"`
+	Title      string                 `json:"title"`
+	Message    string                 `json:"message"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	OccurredAt string                 `json:"occurred_at"`
+}
+
+// sendWebhook POSTs the message as JSON to config url. The secret, when set,
+// is sent verbatim as the Authorization header.
+func (d *Dispatcher) sendWebhook(ctx context.Context, dest Destination, msg Message) error {
+	headers := map[string]string{}
+	if dest.Secret != "" {
+		headers["Authorization"] = dest.Secret
+	}
+	return d.postJSON(ctx, "webhook", dest.config("url"), headers, webhookPayload{
+		Event:      msg.Event,
+		Title:      msg.Title,
+		Message:    msg.Body,
+		Data:       msg.Data,
+		OccurredAt: msg.OccurredAt.UTC().Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// sendDiscord posts one embed to the webhook URL held in the secret.
+func (d *Dispatcher) sendDiscord(ctx context.Context, dest Destination, msg Message) error {
+	type embed struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Timestamp   string `json:"timestamp"`
+	}
+	payload := struct {
+		Username string  `json:"username,omitempty"`
+		Embeds   []embed `json:"embeds"`
+	}{
+		Username: dest.config("username"),
+		Embeds: []embed{{
+			Title:       truncate(msg.Title, 256),
+			Description: truncate(msg.Body, 4096),
+			Timestamp:   msg.OccurredAt.UTC().Format("2006-01-02T15:04:05Z07:00"),
+		}},
+	}
+	return d.postJSON(ctx, "discord", dest.Secret, nil, payload)
+}
+
+// sendTelegram calls the Bot API sendMessage for config chat_id. Plain text:
+// a Markdown parse mode would reject titles containing unbalanced * or _.
+func (d *Dispatcher) sendTelegram(ctx context.Context, dest Destination, msg Message) error {
+	api := dest.config("api_url")
+	if api == "" {
+		api = defaultTelegramAPI
+	}
+	text := msg.Title
+	if msg.Body != "" {
+		text += "\n\n" + msg.Body
+	}
+	endpoint := strings.TrimSuffix(api, "/") + "/bot" + dest.Secret + "/sendMessage"
+	return d.postJSON(ctx, "telegram", endpoint, nil, map[string]string{
+		"chat_id": dest.config("chat_id"),
+		"text":    text,
+	})
+}
+
+// sendNtfy publishes as JSON to the server root, which (unlike the header
+// form) carries a UTF-8 title intact. The secret is an access token.
+func (d *Dispatcher) sendNtfy(ctx context.Context, dest Destination, msg Message) error {
+	server := dest.config("url")
+	if server == "" {
+		server = defaultNtfyServer
+	}
+	payload := map[string]interface{}{
+		"topic":   dest.config("topic"),
+		"title":   msg.Title,
+		"message": msg.Body,
+	}
+	if p, err := strconv.Atoi(dest.config("priority")); err == nil && p >= 1 && p <= 5 {
+		payload["priority"] = p
+	}
+	headers := map[string]string{}
+	if dest.Secret != "" {
+		headers["Authorization"] = "Bearer " + dest.Secret
+	}
+	return d.postJSON(ctx, "ntfy", strings.TrimSuffix(server, "/")+"/", headers, payload)
+}
+
+// sendGotify posts to {url}/message with the application token.
+func (d *Dispatcher) sendGotify(ctx context.Context, dest Destination, msg Message) error {
+	payload := map[string]interface{}{
+		"title":   msg.Title,
+		"message": msg.Body,
+	}
+	if p, err := strconv.Atoi(dest.config("priority")); err == nil {
+		payload["priority"] = p
+	}
+	return d.postJSON(ctx, "gotify", strings.TrimSuffix(dest.config("url"), "/")+"/message",
+		map[string]string{"X-Gotify-Key": dest.Secret}, payload)
+}
+
+// postJSON POSTs body as JSON and classifies the outcome.
+func (d *Dispatcher) postJSON(ctx context.Context, target, url string, headers map[string]string, body interface{}) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return transportError(target, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(raw))
+	if err != nil {
+		// A malformed URL never gets better on retry. The parse error is not
+		// echoed: it quotes the URL, credential included.
+		return retry.NewRetryableError(ErrCodeDeliveryFailed, target+": invalid target URL", false, 0)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return transportError(target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return statusError(target, resp.StatusCode, string(respBody))
+	}
+	return nil
+}
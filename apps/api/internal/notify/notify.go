@@ -0,0 +1,163 @@
+// Package notify delivers rendered notifications to outbound targets:
+// generic JSON webhooks, Discord, Telegram, ntfy, Gotify and SMTP.
+//
+// It knows nothing about subscriptions or templates — the notification
+// service decides what to send and where. Failures come back as
+// *retry.RetryableError so the caller can hand transient ones to the retry
+// queue and drop the rest: a 5xx, 429 or transport error is retryable, any
+// other 4xx (bad token, unknown chat) is not.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/retry"
+)
+
+// ErrCodeDeliveryFailed is the RetryableError code for every failed send.
+const ErrCodeDeliveryFailed = "NOTIFICATION_DELIVERY_FAILED"
+
+// Message is one rendered notification.
+type Message struct {
+	Event      string
+	Title      string
+	Body       string
+	Data       map[string]interface{}
+	OccurredAt time.Time
+}
+
+// Destination is where a Message goes. Config holds the type's plain
+// settings; Secret is the one credential the type uses (see SecretRequired).
+type Destination struct {
+	Type   string
+	Config map[string]string
+	Secret string
+}
+
+func (d Destination) config(key string) string {
+	return strings.TrimSpace(d.Config[key])
+}
+
+// Dispatcher sends Messages. One reused http.Client (Rule 14) with a 10s
+// timeout serves every HTTP target.
+type Dispatcher struct {
+	httpClient *http.Client
+	// sendMail is swapped in tests; production is deliverSMTP.
+	sendMail func(ctx context.Context, dest Destination, msg Message) error
+}
+
+// NewDispatcher creates a Dispatcher.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		sendMail:   deliverSMTP,
+	}
+}
+
+// Send delivers msg to dest. The destination is validated first, so a
+// half-configured target fails as non-retryable instead of filling the queue.
+func (d *Dispatcher) Send(ctx context.Context, dest Destination, msg Message) error {
+	if err := Validate(dest); err != nil {
+		return retry.WrapNonRetryable(err, ErrCodeDeliveryFailed)
+	}
+	switch dest.Type {
+	case models.NotificationTargetWebhook:
+		return d.sendWebhook(ctx, dest, msg)
+	case models.NotificationTargetDiscord:
+		return d.sendDiscord(ctx, dest, msg)
+	case models.NotificationTargetTelegram:
+		return d.sendTelegram(ctx, dest, msg)
+	case models.NotificationTargetNtfy:
+		return d.sendNtfy(ctx, dest, msg)
+	case models.NotificationTargetGotify:
+		return d.sendGotify(ctx, dest, msg)
+	case models.NotificationTargetSMTP:
+		return d.sendMail(ctx, dest, msg)
+	}
+	return retry.NewRetryableError(ErrCodeDeliveryFailed, "unsupported target type "+dest.Type, false, 0)
+}
+
+// SecretRequired reports whether a target type cannot send without its
+// credential: the Discord webhook URL, the Telegram bot token and the Gotify
+// application token. Webhook (Authorization header), ntfy (access token) and
+// SMTP (password) use theirs when set.
+func SecretRequired(targetType string) bool {
+	switch targetType {
+	case models.NotificationTargetDiscord, models.NotificationTargetTelegram, models.NotificationTargetGotify:
+		return true
+	}
+	return false
+}
+
+// Validate checks the type-specific settings of dest.
+func Validate(dest Destination) error {
+	missing := func(field string) error {
+		return &models.ValidationError{Field: field, Message: fmt.Sprintf("%s is required for %s targets", field, dest.Type)}
+	}
+	if SecretRequired(dest.Type) && strings.TrimSpace(dest.Secret) == "" {
+		return missing("secret")
+	}
+	switch dest.Type {
+	case models.NotificationTargetWebhook, models.NotificationTargetGotify:
+		if dest.config("url") == "" {
+			return missing("url")
+		}
+	case models.NotificationTargetTelegram:
+		if dest.config("chat_id") == "" {
+			return missing("chat_id")
+		}
+	case models.NotificationTargetNtfy:
+		if dest.config("topic") == "" {
+			return missing("topic")
+		}
+	case models.NotificationTargetSMTP:
+		for _, field := range []string{"host", "from", "to"} {
+			if dest.config(field) == "" {
+				return missing(field)
+			}
+		}
+		switch dest.config("security") {
+		case "", smtpSecurityStartTLS, smtpSecurityTLS, smtpSecurityNone:
+		default:
+			return &models.ValidationError{Field: "security", Message: "security must be starttls, tls or none"}
+		}
+	case models.NotificationTargetDiscord:
+	default:
+		return &models.ValidationError{Field: "type", Message: "unsupported target type " + dest.Type}
+	}
+	return nil
+}
+
+// statusError classifies a non-2xx HTTP answer.
+func statusError(target string, status int, body string) error {
+	retryable := status == http.StatusTooManyRequests || status >= 500
+	return retry.NewRetryableError(ErrCodeDeliveryFailed,
+		fmt.Sprintf("%s returned status %d: %s", target, status, truncate(body, 200)), retryable, status)
+}
+
+// transportError wraps a failure to reach the target at all; always retryable.
+// A *url.Error is unwrapped first: its text repeats the request URL, which for
+// Discord and Telegram embeds the credential.
+func transportError(target string, err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return retry.NewRetryableError(ErrCodeDeliveryFailed, fmt.Sprintf("%s unreachable: %v", target, err), true, 0)
+}
+
+// truncate bounds upstream error bodies (rune-safe).
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
@@ -0,0 +1,209 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/retry"
+)
+
+var testMessage = Message{
+	Event:      "subtitle_complete",
+	Title:      "字幕已生成",
+	Body:       "寄生上流 (2019)",
+	Data:       map[string]interface{}{"media_id": "m1"},
+	OccurredAt: time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC),
+}
+
+// capture records the one request a target makes.
+type capture struct {
+	path    string
+	headers http.Header
+	body    map[string]interface{}
+}
+
+func captureServer(t *testing.T, status int) (*httptest.Server, *capture) {
+	t.Helper()
+	got := &capture{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path = r.URL.Path
+		got.headers = r.Header.Clone()
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got.body))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, got
+}
+
+func TestDispatcher_HTTPTargets(t *testing.T) {
+	t.Run("webhook", func(t *testing.T) {
+		server, got := captureServer(t, http.StatusNoContent)
+		err := NewDispatcher().Send(context.Background(), Destination{
+			Type:   models.NotificationTargetWebhook,
+			Config: map[string]string{"url": server.URL + "/hook"},
+			Secret: "Bearer abc",
+		}, testMessage)
+
+		require.NoError(t, err)
+		assert.Equal(t, "/hook", got.path)
+		assert.Equal(t, "Bearer abc", got.headers.Get("Authorization"))
+		assert.Equal(t, "subtitle_complete", got.body["event"])
+		assert.Equal(t, "寄生上流 (2019)", got.body["message"])
+		assert.Equal(t, "2026-10-16T08:00:00Z", got.body["occurred_at"])
+	})
+
+	t.Run("discord", func(t *testing.T) {
+		server, got := captureServer(t, http.StatusNoContent)
+		err := NewDispatcher().Send(context.Background(), Destination{
+			Type:   models.NotificationTargetDiscord,
+			Secret: server.URL + "/api/webhooks/1/token",
+		}, testMessage)
+
+		require.NoError(t, err)
+		embeds := got.body["embeds"].([]interface{})
+		require.Len(t, embeds, 1)
+		assert.Equal(t, "字幕已生成", embeds[0].(map[string]interface{})["title"])
+	})
+
+	t.Run("telegram", func(t *testing.T) {
+		server, got := captureServer(t, http.StatusOK)
+		err := NewDispatcher().Send(context.Background(), Destination{
+			Type:   models.NotificationTargetTelegram,
+			Config: map[string]string{"chat_id": "-100", "api_url": server.URL},
+			Secret: "123:abc",
+		}, testMessage)
+
+		require.NoError(t, err)
+		assert.Equal(t, "/bot123:abc/sendMessage", got.path)
+		assert.Equal(t, "-100", got.body["chat_id"])
+		assert.Equal(t, "字幕已生成\n\n寄生上流 (2019)", got.body["text"])
+	})
+
+	t.Run("ntfy", func(t *testing.T) {
+		server, got := captureServer(t, http.StatusOK)
+		err := NewDispatcher().Send(context.Background(), Destination{
+			Type:   models.NotificationTargetNtfy,
+			Config: map[string]string{"url": server.URL, "topic": "vido", "priority": "4"},
+			Secret: "tk_1",
+		}, testMessage)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer tk_1", got.headers.Get("Authorization"))
+		assert.Equal(t, "vido", got.body["topic"])
+		assert.Equal(t, float64(4), got.body["priority"])
+	})
+
+	t.Run("gotify", func(t *testing.T) {
+		server, got := captureServer(t, http.StatusOK)
+		err := NewDispatcher().Send(context.Background(), Destination{
+			Type:   models.NotificationTargetGotify,
+			Config: map[string]string{"url": server.URL + "/"},
+			Secret: "app-token",
+		}, testMessage)
+
+		require.NoError(t, err)
+		assert.Equal(t, "/message", got.path)
+		assert.Equal(t, "app-token", got.headers.Get("X-Gotify-Key"))
+	})
+}
+
+func TestDispatcher_ClassifiesFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		retryable bool
+	}{
+		{"server error is retried", http.StatusBadGateway, true},
+		{"rate limit is retried", http.StatusTooManyRequests, true},
+		{"rejected token is not", http.StatusUnauthorized, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := captureServer(t, tt.status)
+			err := NewDispatcher().Send(context.Background(), Destination{
+				Type:   models.NotificationTargetWebhook,
+				Config: map[string]string{"url": server.URL},
+			}, testMessage)
+
+			var retryErr *retry.RetryableError
+			require.ErrorAs(t, err, &retryErr)
+			assert.Equal(t, tt.retryable, retryErr.IsRetryable())
+			assert.Equal(t, tt.status, retryErr.StatusCode)
+		})
+	}
+
+	t.Run("unreachable target is retried without leaking the credential", func(t *testing.T) {
+		err := NewDispatcher().Send(context.Background(), Destination{
+			Type:   models.NotificationTargetTelegram,
+			Config: map[string]string{"chat_id": "1", "api_url": "http://127.0.0.1:1"},
+			Secret: "123:secret-token",
+		}, testMessage)
+
+		var retryErr *retry.RetryableError
+		require.ErrorAs(t, err, &retryErr)
+		assert.True(t, retryErr.IsRetryable())
+		assert.NotContains(t, err.Error(), "secret-token")
+	})
+
+	t.Run("incomplete destination is not retried", func(t *testing.T) {
+		err := NewDispatcher().Send(context.Background(), Destination{Type: models.NotificationTargetGotify,
+			Config: map[string]string{"url": "http://gotify"}}, testMessage)
+
+		var retryErr *retry.RetryableError
+		require.ErrorAs(t, err, &retryErr)
+		assert.False(t, retryErr.IsRetryable())
+	})
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		dest  Destination
+		field string
+	}{
+		{"webhook needs a url", Destination{Type: models.NotificationTargetWebhook}, "url"},
+		{"discord needs its webhook url secret", Destination{Type: models.NotificationTargetDiscord}, "secret"},
+		{"telegram needs a chat", Destination{Type: models.NotificationTargetTelegram, Secret: "t"}, "chat_id"},
+		{"ntfy needs a topic", Destination{Type: models.NotificationTargetNtfy}, "topic"},
+		{"smtp needs a recipient", Destination{Type: models.NotificationTargetSMTP,
+			Config: map[string]string{"host": "mail", "from": "vido@nas"}}, "to"},
+		{"smtp security is an enum", Destination{Type: models.NotificationTargetSMTP,
+			Config: map[string]string{"host": "mail", "from": "a", "to": "b", "security": "ssl"}}, "security"},
+		{"ntfy token is optional", Destination{Type: models.NotificationTargetNtfy,
+			Config: map[string]string{"topic": "vido"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.dest)
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var valErr *models.ValidationError
+			require.ErrorAs(t, err, &valErr)
+			assert.Equal(t, tt.field, valErr.Field)
+		})
+	}
+}
+
+func TestBuildMail(t *testing.T) {
+	raw := string(buildMail("vido@nas", recipients("a@x, b@x,"), Message{
+		Title:      "備份失敗",
+		Body:       "line 1\nline 2",
+		OccurredAt: testMessage.OccurredAt,
+	}))
+
+	assert.Contains(t, raw, "To: a@x, b@x\r\n")
+	assert.Contains(t, raw, "Subject: =?utf-8?q?")
+	assert.NotContains(t, raw, "備份失敗", "a non-ASCII subject must be encoded")
+	assert.True(t, strings.HasSuffix(raw, "\r\n\r\nline 1\r\nline 2\r\n"))
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/vido/api/internal/retry"
+)
+
+// SMTP security modes (config "security"); STARTTLS on 587 is the default.
+const (
+	smtpSecurityStartTLS = "starttls"
+	smtpSecurityTLS      = "tls"
+	smtpSecurityNone     = "none"
+)
+
+// deliverSMTP sends msg as a plain-text mail. net/smtp takes no context, so
+// the context deadline (or 30s) is applied to the connection instead.
+func deliverSMTP(ctx context.Context, dest Destination, msg Message) error {
+	host := dest.config("host")
+	security := dest.config("security")
+	if security == "" {
+		security = smtpSecurityStartTLS
+	}
+	port := dest.config("port")
+	if port == "" {
+		port = "587"
+		if security == smtpSecurityTLS {
+			port = "465"
+		}
+	}
+	addr := net.JoinHostPort(host, port)
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(30 * time.Second)
+	}
+	dialer := &net.Dialer{Deadline: deadline}
+	var conn net.Conn
+	var err error
+	if security == smtpSecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return transportError("smtp", err)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return transportError("smtp", err)
+	}
+	defer client.Close()
+
+	if security == smtpSecurityStartTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return smtpError(err)
+		}
+	}
+	if user := dest.config("username"); user != "" {
+		if err := client.Auth(smtp.PlainAuth("", user, dest.Secret, host)); err != nil {
+			return smtpError(err)
+		}
+	}
+
+	from := dest.config("from")
+	to := recipients(dest.config("to"))
+	if err := client.Mail(from); err != nil {
+		return smtpError(err)
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return smtpError(err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := w.Write(buildMail(from, to, msg)); err != nil {
+		return smtpError(err)
+	}
+	if err := w.Close(); err != nil {
+		return smtpError(err)
+	}
+	if err := client.Quit(); err != nil {
+		return smtpError(err)
+	}
+	return nil
+}
+
+// recipients splits a comma-separated "to" list.
+func recipients(list string) []string {
+	var out []string
+	for _, r := range strings.Split(list, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// buildMail renders the RFC 5322 message; the subject is RFC 2047 encoded so
+// a zh-TW title survives any relay.
+func buildMail(from string, to []string, msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", msg.OccurredAt.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+// smtpError classifies an SMTP reply: 4xx is a transient server condition,
+// 5xx (bad credentials, rejected recipient) is permanent, and an error with
+// no reply code is a dropped connection.
+func smtpError(err error) error {
+	var reply *textproto.Error
+	if !errors.As(err, &reply) {
+		return transportError("smtp", err)
+	}
+	return retry.NewRetryableError(ErrCodeDeliveryFailed,
+		fmt.Sprintf("smtp replied %d: %s", reply.Code, reply.Msg), reply.Code < 500, reply.Code)
+}
@@ -19,6 +19,18 @@ const (
 	// fulfilment error that is terminal: the request row goes 'failed',
 	// never stranded 'pending' (retrying cannot fix TVDB absence).
 	ErrCodeTVDBNotFound = "DVR_TVDB_NOT_FOUND"
+	// ErrCodeNotFound — the title is not in the DVR's own library, so there
+	// is nothing for it to re-search (UpgradeSearcher).
+	ErrCodeNotFound = "DVR_NOT_FOUND"
+
+	// MEDIA_SERVER_* codes are the media-server twins of the DVR transport
+	// codes above.
+	ErrCodeMediaServerConnectionFailed = "MEDIA_SERVER_CONNECTION_FAILED"
+	ErrCodeMediaServerAuthFailed       = "MEDIA_SERVER_AUTH_FAILED"
+	ErrCodeMediaServerTimeout          = "MEDIA_SERVER_TIMEOUT"
+	// ErrCodeMediaServerPathUnmapped — none of the refreshed paths is inside
+	// a library the server knows, usually a missing path-prefix mapping.
+	ErrCodeMediaServerPathUnmapped = "MEDIA_SERVER_PATH_UNMAPPED"
 
 	ErrCodePluginInitFailed        = "PLUGIN_INIT_FAILED"
 	ErrCodePluginHealthCheckFailed = "PLUGIN_HEALTH_CHECK_FAILED"
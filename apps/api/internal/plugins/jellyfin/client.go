@@ -0,0 +1,372 @@
+// Package jellyfin implements plugins.MediaServerPlugin against the Jellyfin
+// API, and against Emby, which Jellyfin forked from and still mirrors for
+// every endpoint used here. The two differ only in the API prefix and the
+// auth header, captured by Flavor.
+package jellyfin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/vido/api/internal/plugins"
+)
+
+// requestsPerSecond / burst — media servers publish no rate ceiling; the
+// radarr LAN-local ceiling is reused.
+const (
+	requestsPerSecond = 10
+	burstSize         = 10
+)
+
+const (
+	// itemsPageSize is the StartIndex/Limit page used for played-state export.
+	itemsPageSize = 500
+	// maxItemPages bounds the export loop against a pathological
+	// TotalRecordCount, the radarr maxQueuePages guard.
+	maxItemPages = 200
+	// ticksPerSecond converts the servers' 100ns ticks.
+	ticksPerSecond = 10_000_000
+)
+
+// Flavor selects the server dialect.
+type Flavor string
+
+const (
+	FlavorJellyfin Flavor = "jellyfin"
+	FlavorEmby     Flavor = "emby"
+)
+
+// Client talks to one Jellyfin or Emby server. One reused http.Client
+// (Rule 14) with a 10s timeout, one *rate.Limiter for process life.
+type Client struct {
+	config     plugins.PluginConfig
+	flavor     Flavor
+	httpClient *http.Client
+	limiter    *rate.Limiter
+}
+
+// Compile-time interface verification.
+var (
+	_ plugins.MediaServerPlugin = (*Client)(nil)
+	_ plugins.WatchStateSource  = (*Client)(nil)
+)
+
+// NewClient creates a client for the given config and flavor.
+func NewClient(config plugins.PluginConfig, flavor Flavor) *Client {
+	return &Client{
+		config: config,
+		flavor: flavor,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burstSize),
+	}
+}
+
+// Name returns the plugin name.
+func (c *Client) Name() string {
+	return string(c.flavor)
+}
+
+// buildURL constructs the full API URL for path against baseURL. Emby serves
+// its API under /emby; Jellyfin dropped the prefix.
+func (c *Client) buildURL(baseURL, path string) string {
+	base := strings.TrimSuffix(baseURL, "/")
+	if c.flavor == FlavorEmby && !strings.HasSuffix(base, "/emby") {
+		base += "/emby"
+	}
+	return base + path
+}
+
+// systemInfo is the subset of GET /System/Info we validate.
+type systemInfo struct {
+	ServerName string `json:"ServerName"`
+	Version    string `json:"Version"`
+}
+
+// TestConnection verifies connectivity and the API key using the GIVEN
+// config. /System/Info (unlike /System/Info/Public) requires a valid key, so a
+// pass proves both.
+func (c *Client) TestConnection(ctx context.Context, config plugins.PluginConfig) error {
+	body, err := c.doRequest(ctx, http.MethodGet, c.buildURL(config.URL, "/System/Info"), config.APIKey, nil)
+	if err != nil {
+		return err
+	}
+
+	var info systemInfo
+	if err := json.Unmarshal(body, &info); err != nil || info.Version == "" {
+		return &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerConnectionFailed,
+			Message: fmt.Sprintf("%s System/Info returned no parseable version", c.flavor),
+			Cause:   err,
+		}
+	}
+	return nil
+}
+
+// RefreshLibrary queues a refresh of every library.
+func (c *Client) RefreshLibrary(ctx context.Context) error {
+	_, err := c.doRequest(ctx, http.MethodPost, c.buildURL(c.config.URL, "/Library/Refresh"), c.config.APIKey, []byte{})
+	return err
+}
+
+// mediaUpdate is one entry of the POST /Library/Media/Updated body.
+type mediaUpdate struct {
+	Path       string `json:"Path"`
+	UpdateType string `json:"UpdateType"`
+}
+
+// RefreshPaths reports changed files. The server walks up from each path to
+// the nearest item it knows and refreshes that, which is how a new sidecar
+// subtitle or NFO gets picked up without a library scan.
+func (c *Client) RefreshPaths(ctx context.Context, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	updates := make([]mediaUpdate, 0, len(paths))
+	for _, p := range paths {
+		updates = append(updates, mediaUpdate{Path: p, UpdateType: "Modified"})
+	}
+	payload, err := json.Marshal(struct {
+		Updates []mediaUpdate `json:"Updates"`
+	}{updates})
+	if err != nil {
+		return &plugins.PluginError{Code: plugins.ErrCodeMediaServerConnectionFailed, Message: "encode media-updated request", Cause: err}
+	}
+
+	_, err = c.doRequest(ctx, http.MethodPost, c.buildURL(c.config.URL, "/Library/Media/Updated"), c.config.APIKey, payload)
+	return err
+}
+
+// ListUsers lists the server accounts (an admin API key sees all of them).
+func (c *Client) ListUsers(ctx context.Context) ([]plugins.MediaServerUser, error) {
+	body, err := c.doRequest(ctx, http.MethodGet, c.buildURL(c.config.URL, "/Users"), c.config.APIKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []struct {
+		ID   string `json:"Id"`
+		Name string `json:"Name"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerConnectionFailed,
+			Message: fmt.Sprintf("%s users response is not parseable", c.flavor),
+			Cause:   err,
+		}
+	}
+	users := make([]plugins.MediaServerUser, 0, len(raw))
+	for _, u := range raw {
+		users = append(users, plugins.MediaServerUser{ID: u.ID, Name: u.Name})
+	}
+	return users, nil
+}
+
+// itemsPage is the GET /Users/{id}/Items envelope.
+type itemsPage struct {
+	Items            []item `json:"Items"`
+	TotalRecordCount int    `json:"TotalRecordCount"`
+}
+
+type item struct {
+	Type         string            `json:"Type"`
+	Path         string            `json:"Path"`
+	RunTimeTicks int64             `json:"RunTimeTicks"`
+	ProviderIDs  map[string]string `json:"ProviderIds"`
+	UserData     struct {
+		PlaybackPositionTicks int64      `json:"PlaybackPositionTicks"`
+		PlayCount             int        `json:"PlayCount"`
+		Played                bool       `json:"Played"`
+		LastPlayedDate        *time.Time `json:"LastPlayedDate"`
+	} `json:"UserData"`
+}
+
+// GetPlayedItems returns the user's played movies and episodes, then the
+// part-played (resumable) ones. An item in both lists — a rewatch in progress
+// — appears twice; the importer merges by path.
+func (c *Client) GetPlayedItems(ctx context.Context, userID string) ([]plugins.PlayedItem, error) {
+	var out []plugins.PlayedItem
+	for _, filter := range []string{"IsPlayed", "IsResumable"} {
+		items, err := c.listItems(ctx, userID, filter)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+	}
+	return out, nil
+}
+
+func (c *Client) listItems(ctx context.Context, userID, filter string) ([]plugins.PlayedItem, error) {
+	var out []plugins.PlayedItem
+	for page := 0; page < maxItemPages; page++ {
+		query := url.Values{
+			"Recursive":        {"true"},
+			"IncludeItemTypes": {"Movie,Episode"},
+			"Filters":          {filter},
+			"Fields":           {"Path,ProviderIds"},
+			"EnableUserData":   {"true"},
+			"StartIndex":       {strconv.Itoa(page * itemsPageSize)},
+			"Limit":            {strconv.Itoa(itemsPageSize)},
+		}
+		fullURL := c.buildURL(c.config.URL, "/Users/"+url.PathEscape(userID)+"/Items") + "?" + query.Encode()
+		body, err := c.doRequest(ctx, http.MethodGet, fullURL, c.config.APIKey, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var envelope itemsPage
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return nil, &plugins.PluginError{
+				Code:    plugins.ErrCodeMediaServerConnectionFailed,
+				Message: fmt.Sprintf("%s items response is not parseable", c.flavor),
+				Cause:   err,
+			}
+		}
+		for _, it := range envelope.Items {
+			if played, ok := toPlayedItem(it); ok {
+				out = append(out, played)
+			}
+		}
+		if len(envelope.Items) < itemsPageSize || (page+1)*itemsPageSize >= envelope.TotalRecordCount {
+			return out, nil
+		}
+	}
+	return out, nil
+}
+
+// toPlayedItem normalizes a server item; items without a file path (virtual
+// or missing episodes) cannot be matched and are dropped.
+func toPlayedItem(it item) (plugins.PlayedItem, bool) {
+	var itemType string
+	switch it.Type {
+	case "Movie":
+		itemType = plugins.MediaServerItemMovie
+	case "Episode":
+		itemType = plugins.MediaServerItemEpisode
+	default:
+		return plugins.PlayedItem{}, false
+	}
+	if it.Path == "" {
+		return plugins.PlayedItem{}, false
+	}
+
+	played := plugins.PlayedItem{
+		Type:            itemType,
+		Path:            it.Path,
+		Played:          it.UserData.Played,
+		PlayCount:       it.UserData.PlayCount,
+		PositionSeconds: float64(it.UserData.PlaybackPositionTicks) / ticksPerSecond,
+		RuntimeSeconds:  float64(it.RunTimeTicks) / ticksPerSecond,
+		LastPlayedAt:    it.UserData.LastPlayedDate,
+	}
+	if itemType == plugins.MediaServerItemMovie {
+		for key, value := range it.ProviderIDs {
+			if strings.EqualFold(key, "tmdb") {
+				played.TMDbID, _ = strconv.ParseInt(value, 10, 64)
+			}
+		}
+	}
+	return played, true
+}
+
+// doRequest performs a rate-limited authenticated request and maps transport/
+// status failures to typed PluginErrors. Success bodies are returned raw.
+// A non-nil payload (even empty) makes the request a JSON POST.
+func (c *Client) doRequest(ctx context.Context, method, fullURL, apiKey string, payload []byte) ([]byte, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, mapTransportError(err, "rate limiter wait aborted")
+	}
+
+	var bodyReader io.Reader
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bodyReader)
+	if err != nil {
+		return nil, &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerConnectionFailed,
+			Message: fmt.Sprintf("create %s request", c.flavor),
+			Cause:   err,
+		}
+	}
+	c.setAuth(req, apiKey)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, mapTransportError(err, fmt.Sprintf("%s request failed", c.flavor))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerConnectionFailed,
+			Message: fmt.Sprintf("read %s response", c.flavor),
+			Cause:   err,
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerAuthFailed,
+			Message: fmt.Sprintf("%s rejected the API key (status %d)", c.flavor, resp.StatusCode),
+		}
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerConnectionFailed,
+			Message: fmt.Sprintf("%s returned status %d: %s", c.flavor, resp.StatusCode, truncate(string(body), 200)),
+		}
+	}
+	return body, nil
+}
+
+// setAuth applies the flavor's API-key header. Jellyfin is retiring the
+// legacy X-Emby-Token header in favour of the MediaBrowser scheme; Emby only
+// knows the former.
+func (c *Client) setAuth(req *http.Request, apiKey string) {
+	if c.flavor == FlavorEmby {
+		req.Header.Set("X-Emby-Token", apiKey)
+		return
+	}
+	req.Header.Set("Authorization", fmt.Sprintf(`MediaBrowser Client="Vido", Token="%s"`, apiKey))
+}
+
+// mapTransportError distinguishes deadline/timeout failures from plain
+// connectivity failures.
+func mapTransportError(err error, message string) *plugins.PluginError {
+	code := plugins.ErrCodeMediaServerConnectionFailed
+	var netErr interface{ Timeout() bool }
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		code = plugins.ErrCodeMediaServerTimeout
+	}
+	return &plugins.PluginError{Code: code, Message: message, Cause: err}
+}
+
+// truncate bounds upstream error bodies (rune-safe).
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
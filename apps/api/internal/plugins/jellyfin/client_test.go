@@ -0,0 +1,132 @@
+package jellyfin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/plugins"
+)
+
+const testAPIKey = "test-api-key"
+
+func testConfig(url string) plugins.PluginConfig {
+	return plugins.PluginConfig{URL: url, APIKey: testAPIKey}
+}
+
+func TestClient_BuildURL(t *testing.T) {
+	jf := NewClient(testConfig(""), FlavorJellyfin)
+	emby := NewClient(testConfig(""), FlavorEmby)
+
+	assert.Equal(t, "http://nas:8096/System/Info", jf.buildURL("http://nas:8096/", "/System/Info"))
+	assert.Equal(t, "http://nas:8096/emby/System/Info", emby.buildURL("http://nas:8096", "/System/Info"))
+	assert.Equal(t, "http://nas:8096/emby/System/Info", emby.buildURL("http://nas:8096/emby/", "/System/Info"),
+		"a URL that already carries /emby is not doubled")
+	assert.Equal(t, "jellyfin", jf.Name())
+	assert.Equal(t, "emby", emby.Name())
+}
+
+func TestClient_TestConnection(t *testing.T) {
+	t.Run("jellyfin authorization header", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/System/Info", r.URL.Path)
+			assert.Contains(t, r.Header.Get("Authorization"), `Token="`+testAPIKey+`"`)
+			fmt.Fprint(w, `{"ServerName":"nas","Version":"10.10.3"}`)
+		}))
+		defer server.Close()
+
+		client := NewClient(testConfig(server.URL), FlavorJellyfin)
+		assert.NoError(t, client.TestConnection(context.Background(), testConfig(server.URL)))
+	})
+
+	t.Run("emby token header", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/emby/System/Info", r.URL.Path)
+			assert.Equal(t, testAPIKey, r.Header.Get("X-Emby-Token"))
+			fmt.Fprint(w, `{"ServerName":"nas","Version":"4.8.10.0"}`)
+		}))
+		defer server.Close()
+
+		client := NewClient(testConfig(server.URL), FlavorEmby)
+		assert.NoError(t, client.TestConnection(context.Background(), testConfig(server.URL)))
+	})
+
+	t.Run("rejected key", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		client := NewClient(testConfig(server.URL), FlavorJellyfin)
+		err := client.TestConnection(context.Background(), testConfig(server.URL))
+
+		var pluginErr *plugins.PluginError
+		require.ErrorAs(t, err, &pluginErr)
+		assert.Equal(t, plugins.ErrCodeMediaServerAuthFailed, pluginErr.Code)
+	})
+}
+
+func TestClient_RefreshPaths(t *testing.T) {
+	var got struct {
+		Updates []mediaUpdate `json:"Updates"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/Library/Media/Updated", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(testConfig(server.URL), FlavorJellyfin)
+	require.NoError(t, client.RefreshPaths(context.Background(), []string{"/data/movies/A/A.zh-Hant.srt", "/data/movies/B/B.nfo"}))
+
+	require.Len(t, got.Updates, 2)
+	assert.Equal(t, "/data/movies/A/A.zh-Hant.srt", got.Updates[0].Path)
+	assert.Equal(t, "Modified", got.Updates[0].UpdateType)
+
+	assert.NoError(t, client.RefreshPaths(context.Background(), nil), "nothing to report makes no request")
+}
+
+func TestClient_GetPlayedItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/Users/u1/Items", r.URL.Path)
+		assert.Equal(t, "Movie,Episode", r.URL.Query().Get("IncludeItemTypes"))
+		switch r.URL.Query().Get("Filters") {
+		case "IsPlayed":
+			fmt.Fprint(w, `{"TotalRecordCount":3,"Items":[
+				{"Type":"Movie","Path":"/data/A.mkv","RunTimeTicks":72000000000,"ProviderIds":{"Tmdb":"603"},
+				 "UserData":{"Played":true,"PlayCount":2,"LastPlayedDate":"2026-09-01T20:00:00Z"}},
+				{"Type":"Episode","Path":"/data/S01E01.mkv","UserData":{"Played":true,"PlayCount":1}},
+				{"Type":"Episode","UserData":{"Played":true}}]}`)
+		case "IsResumable":
+			fmt.Fprint(w, `{"TotalRecordCount":1,"Items":[
+				{"Type":"Episode","Path":"/data/S01E02.mkv","RunTimeTicks":24000000000,
+				 "UserData":{"PlaybackPositionTicks":6000000000}}]}`)
+		default:
+			t.Errorf("unexpected filter %q", r.URL.Query().Get("Filters"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(testConfig(server.URL), FlavorJellyfin)
+	items, err := client.GetPlayedItems(context.Background(), "u1")
+	require.NoError(t, err)
+	require.Len(t, items, 3, "items without a path are dropped")
+
+	assert.Equal(t, plugins.MediaServerItemMovie, items[0].Type)
+	assert.Equal(t, int64(603), items[0].TMDbID)
+	assert.Equal(t, 2, items[0].PlayCount)
+	assert.InDelta(t, 7200, items[0].RuntimeSeconds, 0.01)
+	require.NotNil(t, items[0].LastPlayedAt)
+
+	assert.Equal(t, plugins.MediaServerItemEpisode, items[2].Type)
+	assert.False(t, items[2].Played)
+	assert.InDelta(t, 600, items[2].PositionSeconds, 0.01)
+}
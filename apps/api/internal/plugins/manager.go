@@ -39,6 +39,13 @@ func SettingKeyQualityProfileID(plugin string) string {
 }
 func SettingKeyRootFolderPath(plugin string) string { return plugin + ".root_folder_path" }
 
+// Media-server keys: the path prefix pair that translates a Vido path into
+// the server's view of the same file (Docker mounts rarely agree), and the
+// server account whose played state an install without Vido accounts imports.
+func SettingKeyLocalPathPrefix(plugin string) string  { return plugin + ".local_path_prefix" }
+func SettingKeyServerPathPrefix(plugin string) string { return plugin + ".server_path_prefix" }
+func SettingKeyWatchImportUser(plugin string) string  { return plugin + ".watch_import_user" }
+
 // PluginHealth is the live health block shape consumed by the settings GET
 // endpoint (AC #4) and the 13-6 settings UI.
 type PluginHealth struct {
@@ -50,9 +57,14 @@ type PluginHealth struct {
 // ClientFactory builds a DVRPlugin client for a loaded config.
 type ClientFactory func(config PluginConfig) DVRPlugin
 
+// MediaServerFactory builds a MediaServerPlugin client for a loaded config.
+type MediaServerFactory func(config PluginConfig) MediaServerPlugin
+
 // Manager owns plugin registration, per-plugin config loading (settings +
 // secrets), fingerprint-cached clients (Rule 14), health state, and the 60s
-// health-check scheduler (retry/scheduler.go lifecycle).
+// health-check scheduler (retry/scheduler.go lifecycle). DVR plugins and
+// media servers live in separate registries that share the config, client
+// cache and health machinery.
 type Manager struct {
 	settingsRepo repository.SettingsRepositoryInterface
 	secrets      secrets.SecretsServiceInterface
@@ -62,7 +74,8 @@ type Manager struct {
 
 	mu           sync.Mutex
 	factories    map[string]ClientFactory
-	clients      map[string]DVRPlugin
+	mediaServers map[string]MediaServerFactory
+	clients      map[string]Plugin
 	fingerprints map[string]string
 	health       map[string]PluginHealth
 
@@ -93,7 +106,8 @@ func NewManager(
 		logger:       logger,
 		interval:     interval,
 		factories:    map[string]ClientFactory{},
-		clients:      map[string]DVRPlugin{},
+		mediaServers: map[string]MediaServerFactory{},
+		clients:      map[string]Plugin{},
 		fingerprints: map[string]string{},
 		health:       map[string]PluginHealth{},
 	}
@@ -106,18 +120,50 @@ func (m *Manager) Register(name string, factory ClientFactory) {
 	m.factories[name] = factory
 }
 
-// RegisteredPlugins returns the sorted names of all registered plugins.
+// RegisterMediaServer adds a media-server plugin factory under its name.
+func (m *Manager) RegisterMediaServer(name string, factory MediaServerFactory) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mediaServers[name] = factory
+}
+
+// RegisteredPlugins returns the sorted names of all registered DVR plugins.
 func (m *Manager) RegisteredPlugins() []string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	names := make([]string, 0, len(m.factories))
-	for name := range m.factories {
+	return sortedKeys(m.factories)
+}
+
+// RegisteredMediaServers returns the sorted names of all registered media
+// servers.
+func (m *Manager) RegisteredMediaServers() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedKeys(m.mediaServers)
+}
+
+func sortedKeys[V any](registry map[string]V) []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
 		names = append(names, name)
 	}
 	sort.Strings(names)
 	return names
 }
 
+// builder returns the factory registered under name in either registry.
+func (m *Manager) builder(name string) (func(PluginConfig) Plugin, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if factory, ok := m.factories[name]; ok {
+		return func(config PluginConfig) Plugin { return factory(config) }, true
+	}
+	if factory, ok := m.mediaServers[name]; ok {
+		return func(config PluginConfig) Plugin { return factory(config) }, true
+	}
+	return nil, false
+}
+
 // LoadConfig reads a plugin's config from settings (url, enabled) + secrets
 // (api_key). Missing keys resolve to zero values, mirroring the qBittorrent
 // GetConfig tolerance for a partially-configured plugin.
@@ -158,6 +204,35 @@ func (m *Manager) GetClient(ctx context.Context, name string) (DVRPlugin, error)
 		}
 	}
 
+	client, err := m.cachedClient(ctx, name, func(config PluginConfig) Plugin { return factory(config) })
+	if err != nil {
+		return nil, err
+	}
+	return client.(DVRPlugin), nil
+}
+
+// GetMediaServer is GetClient for the media-server registry.
+func (m *Manager) GetMediaServer(ctx context.Context, name string) (MediaServerPlugin, error) {
+	m.mu.Lock()
+	factory, registered := m.mediaServers[name]
+	m.mu.Unlock()
+	if !registered {
+		return nil, &PluginError{
+			Code:    ErrCodePluginInitFailed,
+			Message: fmt.Sprintf("media server %q is not registered", name),
+		}
+	}
+
+	client, err := m.cachedClient(ctx, name, func(config PluginConfig) Plugin { return factory(config) })
+	if err != nil {
+		return nil, err
+	}
+	return client.(MediaServerPlugin), nil
+}
+
+// cachedClient loads the config and returns the cached client for its
+// fingerprint, building a new one with build when the config changed.
+func (m *Manager) cachedClient(ctx context.Context, name string, build func(PluginConfig) Plugin) (Plugin, error) {
 	config, enabled, err := m.LoadConfig(ctx, name)
 	if err != nil {
 		return nil, &PluginError{Code: ErrCodePluginInitFailed, Message: "load plugin config", Cause: err}
@@ -176,7 +251,7 @@ func (m *Manager) GetClient(ctx context.Context, name string) (DVRPlugin, error)
 	if client, ok := m.clients[name]; ok && m.fingerprints[name] == fingerprint {
 		return client, nil
 	}
-	client := factory(config)
+	client := build(config)
 	m.clients[name] = client
 	m.fingerprints[name] = fingerprint
 	return client, nil
@@ -186,9 +261,7 @@ func (m *Manager) GetClient(ctx context.Context, name string) (DVRPlugin, error)
 // throwaway client — the settings PUT test-before-save guard (AC #4, §7
 // "must pass before save").
 func (m *Manager) TestConfig(ctx context.Context, name string, config PluginConfig) error {
-	m.mu.Lock()
-	factory, registered := m.factories[name]
-	m.mu.Unlock()
+	build, registered := m.builder(name)
 	if !registered {
 		return &PluginError{
 			Code:    ErrCodePluginInitFailed,
@@ -201,7 +274,7 @@ func (m *Manager) TestConfig(ctx context.Context, name string, config PluginConf
 			Message: "url and api key are required",
 		}
 	}
-	return factory(config).TestConnection(ctx, config)
+	return build(config).TestConnection(ctx, config)
 }
 
 // Health returns the last known health for a plugin. Before the first check
@@ -242,9 +315,19 @@ func (m *Manager) CheckHealth(ctx context.Context, name string) PluginHealth {
 		return next
 	}
 
-	// GetClient re-reads config internally on purpose — that is what keeps
+	// cachedClient re-reads config internally on purpose — that is what keeps
 	// the fingerprint cache coherent when a save races this check.
-	client, err := m.GetClient(ctx, name)
+	build, registered := m.builder(name)
+	if !registered {
+		next.Status = HealthStatusUnhealthy
+		next.Message = (&PluginError{
+			Code:    ErrCodePluginInitFailed,
+			Message: fmt.Sprintf("plugin %q is not registered", name),
+		}).Error()
+		m.storeHealth(ctx, name, next)
+		return next
+	}
+	client, err := m.cachedClient(ctx, name, build)
 	if err != nil {
 		next.Status = HealthStatusUnhealthy
 		next.Message = (&PluginError{Code: ErrCodePluginInitFailed, Message: "client init failed", Cause: err}).Error()
@@ -279,7 +362,7 @@ func (m *Manager) storeHealth(ctx context.Context, name string, next PluginHealt
 
 // recordTransition writes healthy↔unhealthy transition events to the
 // connection_history table so GET /health/services/:service/history works
-// for radarr/sonarr (ValidServiceNames extended in this story).
+// for radarr/sonarr and the media servers (ValidServiceNames lists them).
 func (m *Manager) recordTransition(ctx context.Context, name, prev string, next PluginHealth) {
 	if m.historyRepo == nil || prev == next.Status {
 		return
@@ -315,9 +398,9 @@ func (m *Manager) recordTransition(ctx context.Context, name, prev string, next
 	}
 }
 
-// checkAll checks every registered plugin once.
+// checkAll checks every registered plugin and media server once.
 func (m *Manager) checkAll(ctx context.Context) {
-	for _, name := range m.RegisteredPlugins() {
+	for _, name := range append(m.RegisteredPlugins(), m.RegisteredMediaServers()...) {
 		m.CheckHealth(ctx, name)
 	}
 }
@@ -404,3 +404,45 @@ func TestSettingKeys(t *testing.T) {
 	assert.Equal(t, "radarr.quality_profile_id", SettingKeyQualityProfileID("radarr"))
 	assert.Equal(t, "radarr.root_folder_path", SettingKeyRootFolderPath("radarr"))
 }
+
+// stubMediaServer is a MediaServerPlugin double.
+type stubMediaServer struct {
+	stubPlugin
+}
+
+func (s *stubMediaServer) Name() string                                       { return "jellyfin" }
+func (s *stubMediaServer) RefreshLibrary(ctx context.Context) error           { return nil }
+func (s *stubMediaServer) RefreshPaths(ctx context.Context, p []string) error { return nil }
+
+func TestManager_MediaServerRegistry(t *testing.T) {
+	mgr, settings, secretsSvc, _, _ := configuredManager(t)
+	ctx := context.Background()
+	server := &stubMediaServer{}
+	mgr.RegisterMediaServer("jellyfin", func(config PluginConfig) MediaServerPlugin { return server })
+
+	assert.Equal(t, []string{"radarr"}, mgr.RegisteredPlugins(), "media servers stay out of the DVR list")
+	assert.Equal(t, []string{"jellyfin"}, mgr.RegisteredMediaServers())
+
+	var pluginErr *PluginError
+	_, err := mgr.GetClient(ctx, "jellyfin")
+	require.ErrorAs(t, err, &pluginErr)
+	assert.Equal(t, ErrCodePluginInitFailed, pluginErr.Code)
+
+	_, err = mgr.GetMediaServer(ctx, "jellyfin")
+	require.ErrorAs(t, err, &pluginErr)
+	assert.Equal(t, ErrCodeNotConfigured, pluginErr.Code)
+
+	require.NoError(t, settings.SetString(ctx, SettingKeyURL("jellyfin"), "http://jellyfin:8096"))
+	require.NoError(t, settings.SetBool(ctx, SettingKeyEnabled("jellyfin"), true))
+	require.NoError(t, secretsSvc.Store(ctx, SettingKeyAPIKey("jellyfin"), "jf-key"))
+
+	client, err := mgr.GetMediaServer(ctx, "jellyfin")
+	require.NoError(t, err)
+	assert.Same(t, server, client)
+
+	// The health sweep covers both registries.
+	mgr.checkAll(ctx)
+	assert.Equal(t, HealthStatusHealthy, mgr.Health("jellyfin").Status)
+	assert.Equal(t, 1, server.testedCount())
+	assert.NoError(t, mgr.TestConfig(ctx, "jellyfin", PluginConfig{URL: "http://other:8096", APIKey: "k"}))
+}
@@ -0,0 +1,300 @@
+// Package plex implements plugins.MediaServerPlugin against Plex Media
+// Server. Plex only notices new sidecar files on a scan, but it can scan a
+// single folder of a library section; the client resolves every changed
+// path to the section whose location holds it and scans just that folder.
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/vido/api/internal/plugins"
+)
+
+// requestsPerSecond / burst — Plex publishes no rate ceiling for a local
+// server; the radarr LAN-local ceiling is reused.
+const (
+	requestsPerSecond = 10
+	burstSize         = 10
+)
+
+// clientIdentifier names Vido in Plex's "authorized devices" list.
+const clientIdentifier = "vido"
+
+// Client talks to one Plex Media Server with an X-Plex-Token. One reused
+// http.Client (Rule 14) with a 10s timeout, one *rate.Limiter for process
+// life.
+type Client struct {
+	config     plugins.PluginConfig
+	httpClient *http.Client
+	limiter    *rate.Limiter
+}
+
+// Compile-time interface verification.
+var _ plugins.MediaServerPlugin = (*Client)(nil)
+
+// NewClient creates a client for the given config; config.APIKey is the
+// X-Plex-Token.
+func NewClient(config plugins.PluginConfig) *Client {
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burstSize),
+	}
+}
+
+// Name returns the plugin name.
+func (c *Client) Name() string {
+	return "plex"
+}
+
+// buildURL constructs the full API URL for path against baseURL.
+func buildURL(baseURL, path string) string {
+	return strings.TrimSuffix(baseURL, "/") + path
+}
+
+// serverInfo is the subset of GET / we validate.
+type serverInfo struct {
+	MediaContainer struct {
+		FriendlyName string `json:"friendlyName"`
+		Version      string `json:"version"`
+	} `json:"MediaContainer"`
+}
+
+// TestConnection verifies connectivity and the token using the GIVEN config.
+// The server root (unlike /identity) requires a valid token, so a pass
+// proves both.
+func (c *Client) TestConnection(ctx context.Context, config plugins.PluginConfig) error {
+	body, err := c.doRequest(ctx, buildURL(config.URL, "/"), config.APIKey)
+	if err != nil {
+		return err
+	}
+
+	var info serverInfo
+	if err := json.Unmarshal(body, &info); err != nil || info.MediaContainer.Version == "" {
+		return &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerConnectionFailed,
+			Message: "plex server root returned no parseable version",
+			Cause:   err,
+		}
+	}
+	return nil
+}
+
+// RefreshLibrary queues a scan of every library section.
+func (c *Client) RefreshLibrary(ctx context.Context) error {
+	_, err := c.doRequest(ctx, buildURL(c.config.URL, "/library/sections/all/refresh"), c.config.APIKey)
+	return err
+}
+
+// section is a Plex library section and the folders it scans.
+type section struct {
+	Key       string
+	Title     string
+	Type      string // "movie" | "show" | ...
+	Locations []string
+}
+
+// sectionsResponse is the GET /library/sections envelope.
+type sectionsResponse struct {
+	MediaContainer struct {
+		Directory []struct {
+			Key      string `json:"key"`
+			Title    string `json:"title"`
+			Type     string `json:"type"`
+			Location []struct {
+				Path string `json:"path"`
+			} `json:"Location"`
+		} `json:"Directory"`
+	} `json:"MediaContainer"`
+}
+
+// sections lists the server's library sections with their folders.
+func (c *Client) sections(ctx context.Context) ([]section, error) {
+	body, err := c.doRequest(ctx, buildURL(c.config.URL, "/library/sections"), c.config.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw sectionsResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerConnectionFailed,
+			Message: "plex sections response is not parseable",
+			Cause:   err,
+		}
+	}
+	out := make([]section, 0, len(raw.MediaContainer.Directory))
+	for _, d := range raw.MediaContainer.Directory {
+		s := section{Key: d.Key, Title: d.Title, Type: d.Type}
+		for _, loc := range d.Location {
+			s.Locations = append(s.Locations, path.Clean(loc.Path))
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// partialScan is one folder of one section.
+type partialScan struct {
+	sectionKey string
+	folder     string
+}
+
+// RefreshPaths scans the folder holding each changed file, once per folder.
+// Paths outside every section are skipped; when none maps, the call fails
+// with MEDIA_SERVER_PATH_UNMAPPED so a missing path-prefix mapping shows up
+// in the logs instead of as subtitles Plex never finds.
+func (c *Client) RefreshPaths(ctx context.Context, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	sections, err := c.sections(ctx)
+	if err != nil {
+		return err
+	}
+
+	scans := planScans(sections, paths)
+	if len(scans) == 0 {
+		return &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerPathUnmapped,
+			Message: fmt.Sprintf("none of %d path(s) is inside a plex library section (first: %s)", len(paths), paths[0]),
+		}
+	}
+	for _, scan := range scans {
+		query := url.Values{"path": {scan.folder}}
+		fullURL := buildURL(c.config.URL, "/library/sections/"+url.PathEscape(scan.sectionKey)+"/refresh") + "?" + query.Encode()
+		if _, err := c.doRequest(ctx, fullURL, c.config.APIKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planScans maps each file to its folder and the section with the deepest
+// location holding it, deduplicated and in a stable order.
+func planScans(sections []section, paths []string) []partialScan {
+	seen := make(map[partialScan]bool)
+	var scans []partialScan
+	for _, p := range paths {
+		folder := path.Dir(path.Clean(p))
+		key, ok := owningSection(sections, folder)
+		if !ok {
+			continue
+		}
+		scan := partialScan{sectionKey: key, folder: folder}
+		if !seen[scan] {
+			seen[scan] = true
+			scans = append(scans, scan)
+		}
+	}
+	sort.Slice(scans, func(i, j int) bool {
+		if scans[i].sectionKey != scans[j].sectionKey {
+			return scans[i].sectionKey < scans[j].sectionKey
+		}
+		return scans[i].folder < scans[j].folder
+	})
+	return scans
+}
+
+// owningSection returns the key of the section whose location is the
+// deepest whole-segment prefix of folder.
+func owningSection(sections []section, folder string) (string, bool) {
+	bestKey, bestLen := "", -1
+	for _, s := range sections {
+		for _, loc := range s.Locations {
+			inside := folder == loc || loc == "/" || strings.HasPrefix(folder, loc+"/")
+			if inside && len(loc) > bestLen {
+				bestKey, bestLen = s.Key, len(loc)
+			}
+		}
+	}
+	return bestKey, bestLen >= 0
+}
+
+// doRequest performs a rate-limited authenticated GET — every Plex endpoint
+// used here, the refresh triggers included, is a GET — and maps transport/
+// status failures to typed PluginErrors. Success bodies are returned raw.
+func (c *Client) doRequest(ctx context.Context, fullURL, token string) ([]byte, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, mapTransportError(err, "rate limiter wait aborted")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerConnectionFailed,
+			Message: "create plex request",
+			Cause:   err,
+		}
+	}
+	req.Header.Set("X-Plex-Token", token)
+	req.Header.Set("X-Plex-Client-Identifier", clientIdentifier)
+	req.Header.Set("X-Plex-Product", "Vido")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, mapTransportError(err, "plex request failed")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerConnectionFailed,
+			Message: "read plex response",
+			Cause:   err,
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerAuthFailed,
+			Message: fmt.Sprintf("plex rejected the token (status %d)", resp.StatusCode),
+		}
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, &plugins.PluginError{
+			Code:    plugins.ErrCodeMediaServerConnectionFailed,
+			Message: fmt.Sprintf("plex returned status %d: %s", resp.StatusCode, truncate(string(body), 200)),
+		}
+	}
+	return body, nil
+}
+
+// mapTransportError distinguishes deadline/timeout failures from plain
+// connectivity failures.
+func mapTransportError(err error, message string) *plugins.PluginError {
+	code := plugins.ErrCodeMediaServerConnectionFailed
+	var netErr interface{ Timeout() bool }
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		code = plugins.ErrCodeMediaServerTimeout
+	}
+	return &plugins.PluginError{Code: code, Message: message, Cause: err}
+}
+
+// truncate bounds upstream error bodies (rune-safe).
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
@@ -0,0 +1,127 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/plugins"
+)
+
+const testToken = "test-token"
+
+func testConfig(url string) plugins.PluginConfig {
+	return plugins.PluginConfig{URL: url, APIKey: testToken}
+}
+
+const sectionsJSON = `{"MediaContainer":{"Directory":[
+	{"key":"1","title":"Movies","type":"movie","Location":[{"id":1,"path":"/media/movies"}]},
+	{"key":"2","title":"TV","type":"show","Location":[{"id":2,"path":"/media/tv"},{"id":3,"path":"/media/tv/anime"}]},
+	{"key":"3","title":"Anime","type":"show","Location":[{"id":4,"path":"/media/tv/anime/ongoing"}]}]}}`
+
+func TestClient_TestConnection(t *testing.T) {
+	t.Run("token accepted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/", r.URL.Path)
+			assert.Equal(t, testToken, r.Header.Get("X-Plex-Token"))
+			fmt.Fprint(w, `{"MediaContainer":{"friendlyName":"nas","version":"1.41.0"}}`)
+		}))
+		defer server.Close()
+
+		client := NewClient(testConfig(server.URL))
+		assert.NoError(t, client.TestConnection(context.Background(), testConfig(server.URL)))
+	})
+
+	t.Run("rejected token", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		client := NewClient(testConfig(server.URL))
+		err := client.TestConnection(context.Background(), testConfig(server.URL))
+
+		var pluginErr *plugins.PluginError
+		require.ErrorAs(t, err, &pluginErr)
+		assert.Equal(t, plugins.ErrCodeMediaServerAuthFailed, pluginErr.Code)
+	})
+}
+
+func TestClient_ListSections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/library/sections", r.URL.Path)
+		fmt.Fprint(w, sectionsJSON)
+	}))
+	defer server.Close()
+
+	sections, err := NewClient(testConfig(server.URL)).sections(context.Background())
+	require.NoError(t, err)
+	require.Len(t, sections, 3)
+	assert.Equal(t, section{Key: "2", Title: "TV", Type: "show", Locations: []string{"/media/tv", "/media/tv/anime"}}, sections[1])
+}
+
+func TestPlanScans(t *testing.T) {
+	sections := []section{
+		{Key: "1", Locations: []string{"/media/movies"}},
+		{Key: "2", Locations: []string{"/media/tv"}},
+		{Key: "3", Locations: []string{"/media/tv/anime"}},
+	}
+
+	scans := planScans(sections, []string{
+		"/media/movies/A (2020)/A.zh-Hant.srt",
+		"/media/movies/A (2020)/A.nfo",
+		"/media/tv/anime/Show/Season 01/S01E01.zh-Hant.ass",
+		"/media/tv/Drama/Season 02/S02E03.nfo",
+		"/media/movies2/B/B.srt",
+	})
+
+	assert.Equal(t, []partialScan{
+		{sectionKey: "1", folder: "/media/movies/A (2020)"},
+		{sectionKey: "2", folder: "/media/tv/Drama/Season 02"},
+		{sectionKey: "3", folder: "/media/tv/anime/Show/Season 01"},
+	}, scans, "one scan per folder, deepest section wins, /media/movies2 is not /media/movies")
+}
+
+func TestClient_RefreshPaths(t *testing.T) {
+	var mu sync.Mutex
+	var refreshed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/library/sections":
+			fmt.Fprint(w, sectionsJSON)
+		default:
+			assert.Equal(t, testToken, r.Header.Get("X-Plex-Token"))
+			mu.Lock()
+			refreshed = append(refreshed, r.URL.Path+"?"+r.URL.Query().Get("path"))
+			mu.Unlock()
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(testConfig(server.URL))
+	require.NoError(t, client.RefreshPaths(context.Background(), []string{
+		"/media/movies/A (2020)/A.zh-Hant.srt",
+		"/media/tv/anime/ongoing/Show/S01E01.srt",
+	}))
+
+	sort.Strings(refreshed)
+	assert.Equal(t, []string{
+		"/library/sections/1/refresh?/media/movies/A (2020)",
+		"/library/sections/3/refresh?/media/tv/anime/ongoing/Show",
+	}, refreshed)
+
+	t.Run("nothing inside a section", func(t *testing.T) {
+		err := client.RefreshPaths(context.Background(), []string{"/data/movies/A/A.srt"})
+
+		var pluginErr *plugins.PluginError
+		require.ErrorAs(t, err, &pluginErr)
+		assert.Equal(t, plugins.ErrCodeMediaServerPathUnmapped, pluginErr.Code)
+	})
+}
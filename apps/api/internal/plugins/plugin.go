@@ -5,7 +5,10 @@
 // Rule 20 bump + downstream stale-mark.
 package plugins
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // PluginConfig holds per-plugin connection configuration. The API key is
 // never serialized or logged — json:"-" is the guard (the slog masking
@@ -78,3 +81,69 @@ type ProfileLister interface {
 	GetQualityProfiles(ctx context.Context) ([]QualityProfile, error)
 	GetRootFolders(ctx context.Context) ([]RootFolder, error)
 }
+
+// UpgradeSearcher is the optional DVR capability behind the quality-profile
+// evaluator, the ProfileLister arrangement again: a client that can re-search
+// a title it already manages implements it additionally. The *arr applies its
+// own profile to whatever it grabs — Vido only asks it to look.
+type UpgradeSearcher interface {
+	// SearchUpgrade triggers a search for a better release of a title the DVR
+	// already has. A title it does not manage is a typed DVR_NOT_FOUND.
+	SearchUpgrade(ctx context.Context, tmdbID int64) error
+}
+
+// Plugin is the surface the manager needs from every integration: health
+// checks and the test-before-save guard only probe the connection. DVRPlugin
+// and MediaServerPlugin both satisfy it.
+type Plugin interface {
+	Name() string
+	TestConnection(ctx context.Context, config PluginConfig) error
+}
+
+// Media types reported by a media server's played-state export.
+const (
+	MediaServerItemMovie   = "movie"
+	MediaServerItemEpisode = "episode"
+)
+
+// MediaServerUser is an account on the media server.
+type MediaServerUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PlayedItem is one movie or episode a media-server user has played or part
+// played. Path is the file path as the media server sees it.
+type PlayedItem struct {
+	Type            string // MediaServerItemMovie | MediaServerItemEpisode
+	Path            string
+	TMDbID          int64 // movies only; 0 when the server has no TMDb id
+	Played          bool
+	PlayCount       int
+	PositionSeconds float64
+	RuntimeSeconds  float64
+	LastPlayedAt    *time.Time
+}
+
+// MediaServerPlugin is a player-side server (Jellyfin, Emby, Plex) that
+// consumes the NFOs and subtitles Vido writes. It is registered separately
+// from the DVR plugins, so the request pipeline never asks it for a download
+// queue.
+type MediaServerPlugin interface {
+	Plugin
+	// RefreshLibrary queues a scan of every library on the server.
+	RefreshLibrary(ctx context.Context) error
+	// RefreshPaths reports changed files (server-side paths); the server
+	// refreshes the items that own them.
+	RefreshPaths(ctx context.Context, paths []string) error
+}
+
+// WatchStateSource is the optional media-server capability behind the
+// watch-state import, the ProfileLister arrangement: clients that can export
+// per-user played state (Jellyfin, Emby) implement it additionally.
+type WatchStateSource interface {
+	ListUsers(ctx context.Context) ([]MediaServerUser, error)
+	// GetPlayedItems returns the user's played and part-played movies and
+	// episodes.
+	GetPlayedItems(ctx context.Context, userID string) ([]PlayedItem, error)
+}
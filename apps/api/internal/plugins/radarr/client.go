@@ -42,8 +42,9 @@ type Client struct {
 
 // Compile-time interface verification.
 var (
-	_ plugins.DVRPlugin     = (*Client)(nil)
-	_ plugins.ProfileLister = (*Client)(nil)
+	_ plugins.DVRPlugin       = (*Client)(nil)
+	_ plugins.ProfileLister   = (*Client)(nil)
+	_ plugins.UpgradeSearcher = (*Client)(nil)
 )
 
 // NewClient creates a new Radarr API client for the given config.
@@ -253,6 +254,43 @@ func (c *Client) GetRootFolders(ctx context.Context) ([]plugins.RootFolder, erro
 	return folders, nil
 }
 
+// movieRef is the subset of a Radarr movie resource SearchUpgrade needs.
+type movieRef struct {
+	ID int64 `json:"id"`
+}
+
+// SearchUpgrade asks Radarr to search again for a movie it already manages
+// (plugins.UpgradeSearcher): GET /movie?tmdbId= for Radarr's own id, then a
+// MoviesSearch command. Radarr's profile decides whether a find is an upgrade.
+func (c *Client) SearchUpgrade(ctx context.Context, tmdbID int64) error {
+	body, err := c.doRequest(ctx, http.MethodGet,
+		c.buildURL(fmt.Sprintf("/movie?tmdbId=%d", tmdbID)), c.config.APIKey, nil)
+	if err != nil {
+		return err
+	}
+	var movies []movieRef
+	if err := json.Unmarshal(body, &movies); err != nil {
+		return &plugins.PluginError{
+			Code:    plugins.ErrCodeConnectionFailed,
+			Message: "radarr movie lookup response is not parseable",
+			Cause:   err,
+		}
+	}
+	if len(movies) == 0 || movies[0].ID == 0 {
+		return &plugins.PluginError{
+			Code:    plugins.ErrCodeNotFound,
+			Message: fmt.Sprintf("tmdb movie %d is not in radarr", tmdbID),
+		}
+	}
+
+	payload, err := json.Marshal(map[string]any{"name": "MoviesSearch", "movieIds": []int64{movies[0].ID}})
+	if err != nil {
+		return &plugins.PluginError{Code: plugins.ErrCodeAddFailed, Message: "encode radarr command", Cause: err}
+	}
+	_, err = c.doRequest(ctx, http.MethodPost, c.buildURL("/command"), c.config.APIKey, payload)
+	return err
+}
+
 // doRequest performs a rate-limited authenticated request and maps transport/
 // status failures to typed PluginErrors. Success bodies are returned raw.
 func (c *Client) doRequest(ctx context.Context, method, fullURL, apiKey string, payload []byte) ([]byte, error) {
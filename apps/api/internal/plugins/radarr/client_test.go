@@ -344,6 +344,39 @@ func TestClient_Timeout(t *testing.T) {
 	assert.True(t, errors.Is(err, context.DeadlineExceeded) || pluginErr.Cause != nil)
 }
 
+func TestClient_SearchUpgrade(t *testing.T) {
+	var command map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/movie", requireAPIKey(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("tmdbId") == "550" {
+			fmt.Fprint(w, `[{"id": 42, "title": "Fight Club"}]`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	}))
+	mux.HandleFunc("/api/v3/command", requireAPIKey(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&command))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 1}`)
+	}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(testConfig(server.URL))
+	require.NoError(t, client.SearchUpgrade(context.Background(), 550))
+	assert.Equal(t, "MoviesSearch", command["name"])
+	assert.Equal(t, []any{float64(42)}, command["movieIds"])
+
+	err := client.SearchUpgrade(context.Background(), 551)
+	var pluginErr *plugins.PluginError
+	require.ErrorAs(t, err, &pluginErr)
+	assert.Equal(t, plugins.ErrCodeNotFound, pluginErr.Code)
+}
+
 func TestClient_ImplementsDVRPlugin(t *testing.T) {
 	var _ plugins.DVRPlugin = (*Client)(nil)
+	var _ plugins.UpgradeSearcher = (*Client)(nil)
 }
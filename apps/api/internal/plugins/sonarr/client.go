@@ -70,8 +70,9 @@ type Client struct {
 
 // Compile-time interface verification.
 var (
-	_ plugins.DVRPlugin     = (*Client)(nil)
-	_ plugins.ProfileLister = (*Client)(nil)
+	_ plugins.DVRPlugin       = (*Client)(nil)
+	_ plugins.ProfileLister   = (*Client)(nil)
+	_ plugins.UpgradeSearcher = (*Client)(nil)
 )
 
 // NewClient creates a new Sonarr API client for the given config.
@@ -379,6 +380,54 @@ func (c *Client) applySelection(ctx context.Context, seriesID int64, opts plugin
 	return nil
 }
 
+// seriesRef is the subset of a Sonarr series resource SearchUpgrade needs.
+type seriesRef struct {
+	ID int64 `json:"id"`
+}
+
+// SearchUpgrade asks Sonarr to search again for a series it already manages
+// (plugins.UpgradeSearcher). Sonarr keys series by TVDB id, so the TMDB id
+// goes through the resolver first, then GET /series?tvdbId= and a
+// SeriesSearch command — which covers cutoff-unmet episodes as well as
+// missing ones under Sonarr's own profile.
+func (c *Client) SearchUpgrade(ctx context.Context, tmdbID int64) error {
+	tvdbID, err := c.resolver.ResolveTVDBID(ctx, tmdbID)
+	if err != nil {
+		return &plugins.PluginError{
+			Code:    plugins.ErrCodeConnectionFailed,
+			Message: fmt.Sprintf("resolve tvdb id for tmdb %d", tmdbID),
+			Cause:   err,
+		}
+	}
+	if tvdbID == 0 {
+		return &plugins.PluginError{
+			Code:    plugins.ErrCodeTVDBNotFound,
+			Message: fmt.Sprintf("tmdb series %d has no TVDB entry; sonarr cannot search it", tmdbID),
+		}
+	}
+
+	body, err := c.doRequest(ctx, http.MethodGet,
+		c.buildURL(fmt.Sprintf("/series?tvdbId=%d", tvdbID)), c.config.APIKey, nil)
+	if err != nil {
+		return err
+	}
+	var series []seriesRef
+	if err := json.Unmarshal(body, &series); err != nil {
+		return &plugins.PluginError{
+			Code:    plugins.ErrCodeConnectionFailed,
+			Message: "sonarr series response is not parseable",
+			Cause:   err,
+		}
+	}
+	if len(series) == 0 || series[0].ID == 0 {
+		return &plugins.PluginError{
+			Code:    plugins.ErrCodeNotFound,
+			Message: fmt.Sprintf("tvdb series %d is not in sonarr", tvdbID),
+		}
+	}
+	return c.postCommand(ctx, map[string]any{"name": "SeriesSearch", "seriesId": series[0].ID})
+}
+
 // postCommand POSTs one Sonarr command payload.
 func (c *Client) postCommand(ctx context.Context, command map[string]any) error {
 	payload, err := json.Marshal(command)
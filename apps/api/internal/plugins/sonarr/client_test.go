@@ -317,6 +317,35 @@ func TestClient_GetQualityProfilesAndRootFolders(t *testing.T) {
 func TestClient_ImplementsInterfaces(t *testing.T) {
 	var _ plugins.DVRPlugin = (*Client)(nil)
 	var _ plugins.ProfileLister = (*Client)(nil)
+	var _ plugins.UpgradeSearcher = (*Client)(nil)
+}
+
+func TestClient_SearchUpgrade(t *testing.T) {
+	var command map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/series", requireAPIKey(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "121361", r.URL.Query().Get("tvdbId"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"id": 7, "title": "Game of Thrones"}]`)
+	}))
+	mux.HandleFunc("/api/v3/command", requireAPIKey(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&command))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 1}`)
+	}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(testConfig(server.URL), staticResolver(121361, nil))
+	require.NoError(t, client.SearchUpgrade(context.Background(), 1399))
+	assert.Equal(t, "SeriesSearch", command["name"])
+	assert.Equal(t, float64(7), command["seriesId"])
+
+	err := NewClient(testConfig(server.URL), staticResolver(0, nil)).SearchUpgrade(context.Background(), 1399)
+	var pluginErr *plugins.PluginError
+	require.ErrorAs(t, err, &pluginErr)
+	assert.Equal(t, plugins.ErrCodeTVDBNotFound, pluginErr.Code)
 }
 
 // --- 13-2a: selection-aware AddSeries ([@contract-v2]) ---
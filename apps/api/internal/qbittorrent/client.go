@@ -1,11 +1,13 @@
 package qbittorrent
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
@@ -265,6 +267,14 @@ func (c *Client) GetTorrents(ctx context.Context, opts *ListTorrentsOptions) ([]
 		}
 		if opts.Reverse {
 			apiURL += fmt.Sprintf("%sreverse=true", sep)
+			sep = "&"
+		}
+		if opts.Category != "" {
+			apiURL += fmt.Sprintf("%scategory=%s", sep, url.QueryEscape(opts.Category))
+			sep = "&"
+		}
+		if opts.Tag != "" {
+			apiURL += fmt.Sprintf("%stag=%s", sep, url.QueryEscape(opts.Tag))
 		}
 	}
 
@@ -473,13 +483,28 @@ func (c *Client) doFormAction(ctx context.Context, path string, form url.Values)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return &ConnectionError{
-			Code:    ErrCodeConnectionFailed,
+			Code:    actionErrorCode(resp.StatusCode),
 			Message: fmt.Sprintf("action failed with status %d", resp.StatusCode),
 		}
 	}
 	return nil
 }
 
+// actionErrorCode classifies a non-2xx action reply. qBittorrent answers 404
+// for an unknown hash on the per-torrent endpoints (files, filePrio) and
+// 400/409 when it refuses the arguments (an invalid category name, a file
+// index of a torrent whose metadata has not arrived yet).
+func actionErrorCode(status int) string {
+	switch status {
+	case http.StatusNotFound:
+		return ErrCodeTorrentNotFound
+	case http.StatusBadRequest, http.StatusConflict:
+		return ErrCodeRequestRejected
+	default:
+		return ErrCodeConnectionFailed
+	}
+}
+
 // PauseTorrents pauses the given torrents. Accepts a slice so batch operations
 // reuse the same method (hashes are pipe-joined per the qBittorrent convention).
 // qBT 4.x uses POST /torrents/pause; qBT 5.0+ renamed it to /torrents/stop.
@@ -518,3 +543,90 @@ func (c *Client) DeleteTorrents(ctx context.Context, hashes []string, deleteFile
 		"deleteFiles": {strconv.FormatBool(deleteFiles)},
 	})
 }
+
+// AddTorrentOptions describes one POST /torrents/add. Set URLs (magnet or
+// http links qBittorrent fetches itself) or Torrent (the bytes of a .torrent
+// file, sent as an upload named FileName). Empty SavePath and Category use
+// qBittorrent's defaults; an unknown category is created, and so are unknown
+// Tags.
+type AddTorrentOptions struct {
+	URLs     []string
+	Torrent  []byte
+	FileName string
+	SavePath string
+	Category string
+	Tags     []string
+	Paused   bool
+}
+
+// AddTorrent adds a torrent. qBittorrent answers 200 with "Fails." when it
+// rejects the torrent (a duplicate, an unreadable file), which is reported
+// as an error too. The paused flag is sent under both its 4.x ("paused")
+// and 5.0+ ("stopped") names.
+func (c *Client) AddTorrent(ctx context.Context, opts AddTorrentOptions) error {
+	if len(opts.URLs) == 0 && len(opts.Torrent) == 0 {
+		return fmt.Errorf("add torrent: no URL or torrent file given")
+	}
+	if err := c.ensureAuth(ctx); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if len(opts.URLs) > 0 {
+		_ = mw.WriteField("urls", strings.Join(opts.URLs, "\n"))
+	}
+	if len(opts.Torrent) > 0 {
+		name := opts.FileName
+		if name == "" {
+			name = "upload.torrent"
+		}
+		part, err := mw.CreateFormFile("torrents", name)
+		if err != nil {
+			return fmt.Errorf("add torrent: %w", err)
+		}
+		_, _ = part.Write(opts.Torrent)
+	}
+	if opts.SavePath != "" {
+		_ = mw.WriteField("savepath", opts.SavePath)
+	}
+	if opts.Category != "" {
+		_ = mw.WriteField("category", opts.Category)
+	}
+	if len(opts.Tags) > 0 {
+		_ = mw.WriteField("tags", strings.Join(opts.Tags, ","))
+	}
+	if opts.Paused {
+		_ = mw.WriteField("paused", "true")
+		_ = mw.WriteField("stopped", "true")
+	}
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("add torrent: %w", err)
+	}
+
+	// A *bytes.Reader body gives the request a GetBody, so a re-auth retry
+	// re-sends the upload intact (see doFormAction).
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/torrents/add"), bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return &ConnectionError{Code: ErrCodeConnectionFailed, Message: "failed to create add request", Cause: err}
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := c.doWithAuth(ctx, req)
+	if err != nil {
+		return &ConnectionError{Code: ErrCodeConnectionFailed, Message: "add request failed", Cause: err}
+	}
+	defer resp.Body.Close()
+
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &ConnectionError{
+			Code:    ErrCodeConnectionFailed,
+			Message: fmt.Sprintf("add torrent failed with status %d", resp.StatusCode),
+		}
+	}
+	if strings.TrimSpace(string(reply)) == "Fails." {
+		return &ConnectionError{Code: ErrCodeConnectionFailed, Message: "qBittorrent rejected the torrent"}
+	}
+	return nil
+}
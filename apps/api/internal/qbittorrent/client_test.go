@@ -747,3 +747,74 @@ func TestClient_DeleteTorrents_NonSuccessStatus(t *testing.T) {
 	assert.ErrorAs(t, err, &connErr)
 	assert.Equal(t, ErrCodeConnectionFailed, connErr.Code)
 }
+
+func TestClient_AddTorrent(t *testing.T) {
+	t.Run("magnet with category and tags", func(t *testing.T) {
+		var urls, category, tags, paused string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v2/auth/login", qbtLoginOK)
+		mux.HandleFunc("/api/v2/torrents/add", func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			urls = r.FormValue("urls")
+			category = r.FormValue("category")
+			tags = r.FormValue("tags")
+			paused = r.FormValue("paused")
+			fmt.Fprint(w, "Ok.")
+		})
+		server := newTestServer(t, mux)
+		defer server.Close()
+
+		client := NewClient(&Config{Host: server.URL, Username: "admin", Password: "password"})
+		err := client.AddTorrent(context.Background(), AddTorrentOptions{
+			URLs: []string{"magnet:?xt=urn:btih:abc"}, Category: "vido", Tags: []string{"a", "b"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "magnet:?xt=urn:btih:abc", urls)
+		assert.Equal(t, "vido", category)
+		assert.Equal(t, "a,b", tags)
+		assert.Empty(t, paused)
+	})
+
+	t.Run("file upload", func(t *testing.T) {
+		var got []byte
+		var name string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v2/auth/login", qbtLoginOK)
+		mux.HandleFunc("/api/v2/torrents/add", func(w http.ResponseWriter, r *http.Request) {
+			file, header, err := r.FormFile("torrents")
+			require.NoError(t, err)
+			got, _ = io.ReadAll(file)
+			name = header.Filename
+			fmt.Fprint(w, "Ok.")
+		})
+		server := newTestServer(t, mux)
+		defer server.Close()
+
+		client := NewClient(&Config{Host: server.URL, Username: "admin", Password: "password"})
+		err := client.AddTorrent(context.Background(), AddTorrentOptions{Torrent: []byte("d4:infod4:name1:aee"), FileName: "a.torrent"})
+		require.NoError(t, err)
+		assert.Equal(t, "d4:infod4:name1:aee", string(got))
+		assert.Equal(t, "a.torrent", name)
+	})
+
+	t.Run("rejected torrent", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v2/auth/login", qbtLoginOK)
+		mux.HandleFunc("/api/v2/torrents/add", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "Fails.")
+		})
+		server := newTestServer(t, mux)
+		defer server.Close()
+
+		client := NewClient(&Config{Host: server.URL, Username: "admin", Password: "password"})
+		err := client.AddTorrent(context.Background(), AddTorrentOptions{URLs: []string{"magnet:?xt=urn:btih:abc"}})
+		var connErr *ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Contains(t, connErr.Message, "rejected")
+	})
+
+	t.Run("nothing to add", func(t *testing.T) {
+		client := NewClient(&Config{Host: "http://unused"})
+		assert.Error(t, client.AddTorrent(context.Background(), AddTorrentOptions{}))
+	})
+}
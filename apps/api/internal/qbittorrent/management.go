@@ -0,0 +1,271 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Category is a qBittorrent category: a label plus the save path torrents in
+// it download to ("" means the default save path).
+type Category struct {
+	Name     string `json:"name"`
+	SavePath string `json:"save_path"`
+}
+
+// File priorities accepted by POST /torrents/filePrio. qBittorrent rejects
+// any other value with 400.
+const (
+	FilePriorityDoNotDownload = 0
+	FilePriorityNormal        = 1
+	FilePriorityHigh          = 6
+	FilePriorityMaximum       = 7
+)
+
+// ValidFilePriority reports whether p is one of the FilePriority constants.
+func ValidFilePriority(p int) bool {
+	switch p {
+	case FilePriorityDoNotDownload, FilePriorityNormal, FilePriorityHigh, FilePriorityMaximum:
+		return true
+	}
+	return false
+}
+
+// TorrentFile is one file inside a torrent. Index is the id SetFilePriority
+// takes.
+type TorrentFile struct {
+	Index    int     `json:"index"`
+	Name     string  `json:"name"`
+	Size     int64   `json:"size"`
+	Progress float64 `json:"progress"`
+	Priority int     `json:"priority"`
+}
+
+// qbTorrentFile mirrors one /torrents/files entry. index only exists from
+// WebAPI 2.8.2; older servers number files by their position in the list.
+type qbTorrentFile struct {
+	Index    *int    `json:"index"`
+	Name     string  `json:"name"`
+	Size     int64   `json:"size"`
+	Progress float64 `json:"progress"`
+	Priority int     `json:"priority"`
+}
+
+// SpeedLimits are qBittorrent's global transfer limits in bytes per second
+// (0 = unlimited). AlternativeMode reports whether the alternative
+// ("turtle") limits are active; while they are, the values qBittorrent
+// reports and sets are the alternative ones.
+type SpeedLimits struct {
+	DownloadLimit   int64 `json:"download_limit"`
+	UploadLimit     int64 `json:"upload_limit"`
+	AlternativeMode bool  `json:"alternative_mode"`
+}
+
+// getJSON runs an authenticated GET and decodes the JSON reply into out.
+// Non-2xx replies are classified like the form actions (see actionErrorCode).
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	if err := c.ensureAuth(ctx); err != nil {
+		return err
+	}
+
+	apiURL := c.buildURL(path)
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return &ConnectionError{Code: ErrCodeConnectionFailed, Message: "failed to create request", Cause: err}
+	}
+
+	resp, err := c.doWithAuth(ctx, req)
+	if err != nil {
+		return &ConnectionError{Code: ErrCodeConnectionFailed, Message: "request failed", Cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &ConnectionError{
+			Code:    actionErrorCode(resp.StatusCode),
+			Message: fmt.Sprintf("%s failed with status %d", path, resp.StatusCode),
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return &ConnectionError{Code: ErrCodeConnectionFailed, Message: "failed to decode " + path + " response", Cause: err}
+	}
+	return nil
+}
+
+// GetCategories lists the categories, sorted by name.
+func (c *Client) GetCategories(ctx context.Context) ([]Category, error) {
+	var raw map[string]struct {
+		Name     string `json:"name"`
+		SavePath string `json:"savePath"`
+	}
+	if err := c.getJSON(ctx, "/torrents/categories", nil, &raw); err != nil {
+		return nil, err
+	}
+	categories := make([]Category, 0, len(raw))
+	for name, cat := range raw {
+		categories = append(categories, Category{Name: name, SavePath: cat.SavePath})
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+// CreateCategory adds a category. qBittorrent answers 409 when the name is
+// invalid or already taken.
+func (c *Client) CreateCategory(ctx context.Context, name, savePath string) error {
+	return c.doFormAction(ctx, "/torrents/createCategory", url.Values{
+		"category": {name},
+		"savePath": {savePath},
+	})
+}
+
+// RemoveCategories deletes categories. Their torrents are kept and become
+// uncategorised.
+func (c *Client) RemoveCategories(ctx context.Context, names []string) error {
+	return c.doFormAction(ctx, "/torrents/removeCategories", url.Values{
+		"categories": {strings.Join(names, "\n")},
+	})
+}
+
+// SetTorrentCategory moves torrents into category; "" clears it. The
+// category must already exist (409 otherwise).
+func (c *Client) SetTorrentCategory(ctx context.Context, hashes []string, category string) error {
+	return c.doFormAction(ctx, "/torrents/setCategory", url.Values{
+		"hashes":   {strings.Join(hashes, "|")},
+		"category": {category},
+	})
+}
+
+// GetTags lists every tag known to qBittorrent, sorted.
+func (c *Client) GetTags(ctx context.Context) ([]string, error) {
+	var tags []string
+	if err := c.getJSON(ctx, "/torrents/tags", nil, &tags); err != nil {
+		return nil, err
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// CreateTags registers tags without attaching them to a torrent.
+func (c *Client) CreateTags(ctx context.Context, tags []string) error {
+	return c.doFormAction(ctx, "/torrents/createTags", url.Values{"tags": {strings.Join(tags, ",")}})
+}
+
+// DeleteTags removes tags from qBittorrent and from every torrent carrying
+// them.
+func (c *Client) DeleteTags(ctx context.Context, tags []string) error {
+	return c.doFormAction(ctx, "/torrents/deleteTags", url.Values{"tags": {strings.Join(tags, ",")}})
+}
+
+// AddTorrentTags attaches tags to torrents, creating unknown tags.
+func (c *Client) AddTorrentTags(ctx context.Context, hashes, tags []string) error {
+	return c.doFormAction(ctx, "/torrents/addTags", url.Values{
+		"hashes": {strings.Join(hashes, "|")},
+		"tags":   {strings.Join(tags, ",")},
+	})
+}
+
+// RemoveTorrentTags detaches tags from torrents; the tags themselves stay.
+func (c *Client) RemoveTorrentTags(ctx context.Context, hashes, tags []string) error {
+	return c.doFormAction(ctx, "/torrents/removeTags", url.Values{
+		"hashes": {strings.Join(hashes, "|")},
+		"tags":   {strings.Join(tags, ",")},
+	})
+}
+
+// GetTorrentFiles lists a torrent's files. A magnet whose metadata has not
+// arrived yet has none.
+func (c *Client) GetTorrentFiles(ctx context.Context, hash string) ([]TorrentFile, error) {
+	var raw []qbTorrentFile
+	if err := c.getJSON(ctx, "/torrents/files", url.Values{"hash": {hash}}, &raw); err != nil {
+		return nil, err
+	}
+	files := make([]TorrentFile, len(raw))
+	for i, f := range raw {
+		index := i
+		if f.Index != nil {
+			index = *f.Index
+		}
+		files[i] = TorrentFile{Index: index, Name: f.Name, Size: f.Size, Progress: f.Progress, Priority: f.Priority}
+	}
+	return files, nil
+}
+
+// SetFilePriority sets the priority of the given files of one torrent.
+func (c *Client) SetFilePriority(ctx context.Context, hash string, indexes []int, priority int) error {
+	if !ValidFilePriority(priority) {
+		return &ConnectionError{Code: ErrCodeRequestRejected, Message: fmt.Sprintf("invalid file priority %d", priority)}
+	}
+	ids := make([]string, len(indexes))
+	for i, idx := range indexes {
+		ids[i] = strconv.Itoa(idx)
+	}
+	return c.doFormAction(ctx, "/torrents/filePrio", url.Values{
+		"hash":     {hash},
+		"id":       {strings.Join(ids, "|")},
+		"priority": {strconv.Itoa(priority)},
+	})
+}
+
+// SetTorrentSpeedLimits caps the transfer rate of individual torrents in
+// bytes per second; 0 removes the cap.
+func (c *Client) SetTorrentSpeedLimits(ctx context.Context, hashes []string, downloadLimit, uploadLimit int64) error {
+	joined := strings.Join(hashes, "|")
+	if err := c.doFormAction(ctx, "/torrents/setDownloadLimit", url.Values{
+		"hashes": {joined},
+		"limit":  {strconv.FormatInt(downloadLimit, 10)},
+	}); err != nil {
+		return err
+	}
+	return c.doFormAction(ctx, "/torrents/setUploadLimit", url.Values{
+		"hashes": {joined},
+		"limit":  {strconv.FormatInt(uploadLimit, 10)},
+	})
+}
+
+// GetSpeedLimits reads the global limits and which limit set is active.
+func (c *Client) GetSpeedLimits(ctx context.Context) (*SpeedLimits, error) {
+	var limits SpeedLimits
+	if err := c.getJSON(ctx, "/transfer/downloadLimit", nil, &limits.DownloadLimit); err != nil {
+		return nil, err
+	}
+	if err := c.getJSON(ctx, "/transfer/uploadLimit", nil, &limits.UploadLimit); err != nil {
+		return nil, err
+	}
+	var mode int
+	if err := c.getJSON(ctx, "/transfer/speedLimitsMode", nil, &mode); err != nil {
+		return nil, err
+	}
+	limits.AlternativeMode = mode == 1
+	return &limits, nil
+}
+
+// SetSpeedLimits switches to the requested limit set and then writes the
+// limits into it. qBittorrent only offers a toggle for the mode, so the
+// current mode is read first.
+func (c *Client) SetSpeedLimits(ctx context.Context, limits SpeedLimits) error {
+	var mode int
+	if err := c.getJSON(ctx, "/transfer/speedLimitsMode", nil, &mode); err != nil {
+		return err
+	}
+	if (mode == 1) != limits.AlternativeMode {
+		if err := c.doFormAction(ctx, "/transfer/toggleSpeedLimitsMode", url.Values{}); err != nil {
+			return err
+		}
+	}
+	if err := c.doFormAction(ctx, "/transfer/setDownloadLimit", url.Values{
+		"limit": {strconv.FormatInt(limits.DownloadLimit, 10)},
+	}); err != nil {
+		return err
+	}
+	return c.doFormAction(ctx, "/transfer/setUploadLimit", url.Values{
+		"limit": {strconv.FormatInt(limits.UploadLimit, 10)},
+	})
+}
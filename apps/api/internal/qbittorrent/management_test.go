@@ -0,0 +1,187 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newManagementClient(t *testing.T, mux *http.ServeMux) *Client {
+	mux.HandleFunc("/api/v2/auth/login", qbtLoginOK)
+	server := newTestServer(t, mux)
+	t.Cleanup(server.Close)
+	return NewClient(&Config{Host: server.URL, Username: "admin", Password: "password"})
+}
+
+func TestClient_Categories(t *testing.T) {
+	var created, removed, moved string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/torrents/categories", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tv":{"name":"tv","savePath":"/data/tv"},"movies":{"name":"movies","savePath":""}}`)
+	})
+	mux.HandleFunc("/api/v2/torrents/createCategory", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.FormValue("category") == "tv" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		created = r.FormValue("category") + "=" + r.FormValue("savePath")
+	})
+	mux.HandleFunc("/api/v2/torrents/removeCategories", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		removed = r.FormValue("categories")
+	})
+	mux.HandleFunc("/api/v2/torrents/setCategory", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		moved = r.FormValue("hashes") + ":" + r.FormValue("category")
+	})
+	client := newManagementClient(t, mux)
+	ctx := context.Background()
+
+	categories, err := client.GetCategories(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Category{{Name: "movies"}, {Name: "tv", SavePath: "/data/tv"}}, categories)
+
+	require.NoError(t, client.CreateCategory(ctx, "anime", "/data/anime"))
+	assert.Equal(t, "anime=/data/anime", created)
+
+	err = client.CreateCategory(ctx, "tv", "")
+	var connErr *ConnectionError
+	require.ErrorAs(t, err, &connErr)
+	assert.Equal(t, ErrCodeRequestRejected, connErr.Code)
+
+	require.NoError(t, client.RemoveCategories(ctx, []string{"a", "b"}))
+	assert.Equal(t, "a\nb", removed)
+
+	require.NoError(t, client.SetTorrentCategory(ctx, []string{"h1", "h2"}, "tv"))
+	assert.Equal(t, "h1|h2:tv", moved)
+}
+
+func TestClient_Tags(t *testing.T) {
+	var added string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/torrents/tags", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `["vido","4k"]`)
+	})
+	mux.HandleFunc("/api/v2/torrents/addTags", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		added = r.FormValue("hashes") + ":" + r.FormValue("tags")
+	})
+	client := newManagementClient(t, mux)
+	ctx := context.Background()
+
+	tags, err := client.GetTags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4k", "vido"}, tags)
+
+	require.NoError(t, client.AddTorrentTags(ctx, []string{"h1"}, []string{"vido", "4k"}))
+	assert.Equal(t, "h1:vido,4k", added)
+}
+
+func TestClient_GetTorrents_CategoryAndTags(t *testing.T) {
+	var query string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/torrents/info", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `[{"hash":"h1","name":"a","state":"downloading","category":"tv","tags":"vido, 4k"},{"hash":"h2","name":"b","state":"downloading","tags":""}]`)
+	})
+	client := newManagementClient(t, mux)
+
+	torrents, err := client.GetTorrents(context.Background(), &ListTorrentsOptions{Category: "tv shows", Tag: "vido"})
+	require.NoError(t, err)
+	assert.Equal(t, "category=tv+shows&tag=vido", query)
+	require.Len(t, torrents, 2)
+	assert.Equal(t, "tv", torrents[0].Category)
+	assert.Equal(t, []string{"vido", "4k"}, torrents[0].Tags)
+	assert.Nil(t, torrents[1].Tags)
+}
+
+func TestClient_TorrentFiles(t *testing.T) {
+	var prio string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/torrents/files", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("hash") {
+		case "new":
+			fmt.Fprint(w, `[{"index":0,"name":"a.mkv","size":10,"progress":0.5,"priority":1},{"index":1,"name":"a.nfo","size":1,"progress":0,"priority":0}]`)
+		case "old":
+			fmt.Fprint(w, `[{"name":"a.mkv","size":10},{"name":"b.mkv","size":20}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc("/api/v2/torrents/filePrio", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		prio = r.FormValue("hash") + ":" + r.FormValue("id") + ":" + r.FormValue("priority")
+	})
+	client := newManagementClient(t, mux)
+	ctx := context.Background()
+
+	files, err := client.GetTorrentFiles(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, TorrentFile{Index: 1, Name: "a.nfo", Size: 1}, files[1])
+
+	t.Run("pre-2.8.2 servers are numbered by position", func(t *testing.T) {
+		files, err := client.GetTorrentFiles(ctx, "old")
+		require.NoError(t, err)
+		assert.Equal(t, 1, files[1].Index)
+	})
+
+	t.Run("unknown hash", func(t *testing.T) {
+		_, err := client.GetTorrentFiles(ctx, "nope")
+		var connErr *ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Equal(t, ErrCodeTorrentNotFound, connErr.Code)
+	})
+
+	require.NoError(t, client.SetFilePriority(ctx, "new", []int{0, 1}, FilePriorityHigh))
+	assert.Equal(t, "new:0|1:6", prio)
+
+	t.Run("invalid priority never reaches qBittorrent", func(t *testing.T) {
+		prio = ""
+		err := client.SetFilePriority(ctx, "new", []int{0}, 3)
+		var connErr *ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Equal(t, ErrCodeRequestRejected, connErr.Code)
+		assert.Empty(t, prio)
+	})
+}
+
+func TestClient_SpeedLimits(t *testing.T) {
+	mode, toggles := 0, 0
+	var download, upload string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/transfer/downloadLimit", func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "1048576") })
+	mux.HandleFunc("/api/v2/transfer/uploadLimit", func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "0") })
+	mux.HandleFunc("/api/v2/transfer/speedLimitsMode", func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, mode) })
+	mux.HandleFunc("/api/v2/transfer/toggleSpeedLimitsMode", func(w http.ResponseWriter, r *http.Request) {
+		toggles++
+		mode = 1 - mode
+	})
+	mux.HandleFunc("/api/v2/transfer/setDownloadLimit", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		download = r.FormValue("limit")
+	})
+	mux.HandleFunc("/api/v2/transfer/setUploadLimit", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		upload = r.FormValue("limit")
+	})
+	client := newManagementClient(t, mux)
+	ctx := context.Background()
+
+	limits, err := client.GetSpeedLimits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &SpeedLimits{DownloadLimit: 1048576}, limits)
+
+	require.NoError(t, client.SetSpeedLimits(ctx, SpeedLimits{DownloadLimit: 500, UploadLimit: 100, AlternativeMode: true}))
+	assert.Equal(t, 1, toggles)
+	assert.Equal(t, "500", download)
+	assert.Equal(t, "100", upload)
+
+	// Already in alternative mode: no second toggle.
+	require.NoError(t, client.SetSpeedLimits(ctx, SpeedLimits{AlternativeMode: true}))
+	assert.Equal(t, 1, toggles)
+}
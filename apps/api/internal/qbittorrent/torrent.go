@@ -1,7 +1,10 @@
 package qbittorrent
 
 import (
+	"cmp"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -86,10 +89,13 @@ const (
 )
 
 // ListTorrentsOptions configures the torrent list request.
+// Category and Tag narrow the list to one category or tag.
 type ListTorrentsOptions struct {
-	Filter  TorrentsFilter
-	Sort    TorrentsSort
-	Reverse bool
+	Filter   TorrentsFilter
+	Sort     TorrentsSort
+	Reverse  bool
+	Category string
+	Tag      string
 }
 
 // Torrent represents a torrent with its current status and progress.
@@ -110,6 +116,8 @@ type Torrent struct {
 	Uploaded      int64         `json:"uploaded"`
 	Ratio         float64       `json:"ratio"`
 	SavePath      string        `json:"save_path"`
+	Category      string        `json:"category,omitempty"`
+	Tags          []string      `json:"tags,omitempty"`
 }
 
 // TorrentDetails extends Torrent with additional properties.
@@ -167,6 +175,8 @@ type qbTorrentInfo struct {
 	Downloaded   int64   `json:"downloaded"`
 	Uploaded     int64   `json:"uploaded"`
 	Ratio        float64 `json:"ratio"`
+	Category     string  `json:"category"`
+	Tags         string  `json:"tags"`
 }
 
 // qbTorrentProperties is the internal representation from /torrents/properties.
@@ -207,6 +217,8 @@ func mapQBTorrentInfo(qbt qbTorrentInfo) Torrent {
 		Uploaded:      qbt.Uploaded,
 		Ratio:         qbt.Ratio,
 		SavePath:      qbt.SavePath,
+		Category:      qbt.Category,
+		Tags:          splitTags(qbt.Tags),
 	}
 
 	if qbt.CompletionOn > 0 {
@@ -217,6 +229,81 @@ func mapQBTorrentInfo(qbt qbTorrentInfo) Torrent {
 	return t
 }
 
+// ApplyListOptions filters and sorts torrents the way qBittorrent's
+// /torrents/info does, for download clients whose API returns everything
+// unsorted (Transmission, Deluge).
+func ApplyListOptions(torrents []Torrent, opts *ListTorrentsOptions) []Torrent {
+	if opts == nil {
+		return torrents
+	}
+	out := make([]Torrent, 0, len(torrents))
+	for _, t := range torrents {
+		if !filterMatches(opts.Filter, t.Status) {
+			continue
+		}
+		if opts.Category != "" && t.Category != opts.Category {
+			continue
+		}
+		if opts.Tag != "" && !slices.Contains(t.Tags, opts.Tag) {
+			continue
+		}
+		out = append(out, t)
+	}
+
+	var less func(a, b Torrent) int
+	switch opts.Sort {
+	case SortName:
+		less = func(a, b Torrent) int { return strings.Compare(a.Name, b.Name) }
+	case SortSize:
+		less = func(a, b Torrent) int { return cmp.Compare(a.Size, b.Size) }
+	case SortProgress:
+		less = func(a, b Torrent) int { return cmp.Compare(a.Progress, b.Progress) }
+	case SortAddedOn:
+		less = func(a, b Torrent) int { return a.AddedOn.Compare(b.AddedOn) }
+	}
+	if less != nil {
+		slices.SortStableFunc(out, func(a, b Torrent) int {
+			if opts.Reverse {
+				return less(b, a)
+			}
+			return less(a, b)
+		})
+	}
+	return out
+}
+
+// filterMatches mirrors qBittorrent's status filters over the normalised
+// statuses: "downloading" covers everything still fetching data and
+// "completed" everything that has finished, seeding or not.
+func filterMatches(filter TorrentsFilter, status TorrentStatus) bool {
+	switch filter {
+	case FilterDownloading:
+		return status == StatusDownloading || status == StatusStalled || status == StatusQueued || status == StatusChecking
+	case FilterPaused:
+		return status == StatusPaused
+	case FilterCompleted:
+		return status == StatusCompleted || status == StatusSeeding
+	case FilterSeeding:
+		return status == StatusSeeding
+	case FilterErrored:
+		return status == StatusError
+	default:
+		return true
+	}
+}
+
+// splitTags parses qBittorrent's tag list, which arrives as one ", "-joined
+// string ("" when the torrent has none).
+func splitTags(tags string) []string {
+	var out []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
 // mapTorrentDetails combines Torrent and properties into TorrentDetails.
 func mapTorrentDetails(torrent *Torrent, props qbTorrentProperties) *TorrentDetails {
 	return &TorrentDetails{
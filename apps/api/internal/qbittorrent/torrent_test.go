@@ -587,3 +587,28 @@ func TestClient_GetTorrentDetails_PropertiesError(t *testing.T) {
 	assert.Nil(t, details)
 	assert.Error(t, err)
 }
+
+func TestApplyListOptions(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	torrents := []Torrent{
+		{Hash: "a", Name: "b", Size: 3, Status: StatusStalled, AddedOn: base, Tags: []string{"vido"}},
+		{Hash: "b", Name: "a", Size: 1, Status: StatusSeeding, AddedOn: base.Add(time.Hour), Category: "tv"},
+		{Hash: "c", Name: "c", Size: 2, Status: StatusPaused, AddedOn: base.Add(2 * time.Hour)},
+	}
+	hashes := func(ts []Torrent) []string {
+		var out []string
+		for _, t := range ts {
+			out = append(out, t.Hash)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, hashes(ApplyListOptions(torrents, nil)))
+	assert.Equal(t, []string{"a"}, hashes(ApplyListOptions(torrents, &ListTorrentsOptions{Filter: FilterDownloading})))
+	assert.Equal(t, []string{"b"}, hashes(ApplyListOptions(torrents, &ListTorrentsOptions{Filter: FilterCompleted})))
+	assert.Equal(t, []string{"b"}, hashes(ApplyListOptions(torrents, &ListTorrentsOptions{Category: "tv"})))
+	assert.Equal(t, []string{"a"}, hashes(ApplyListOptions(torrents, &ListTorrentsOptions{Tag: "vido"})))
+	assert.Equal(t, []string{"b", "a", "c"}, hashes(ApplyListOptions(torrents, &ListTorrentsOptions{Sort: SortName})))
+	assert.Equal(t, []string{"a", "c", "b"}, hashes(ApplyListOptions(torrents, &ListTorrentsOptions{Sort: SortSize, Reverse: true})))
+	assert.Equal(t, []string{"c", "b", "a"}, hashes(ApplyListOptions(torrents, &ListTorrentsOptions{Sort: SortAddedOn, Reverse: true})))
+}
@@ -44,4 +44,5 @@ const (
 	ErrCodeAuthFailed       = "QBITTORRENT_AUTH_FAILED"
 	ErrCodeTimeout          = "QBITTORRENT_TIMEOUT"
 	ErrCodeNotConfigured    = "QBITTORRENT_NOT_CONFIGURED"
+	ErrCodeRequestRejected  = "QBITTORRENT_REQUEST_REJECTED"
 )
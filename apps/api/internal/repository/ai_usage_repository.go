@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vido/api/internal/models"
+)
+
+// AIUsageDateLayout is the format of ai_usage.usage_date and of the date
+// bounds the ledger queries take.
+const AIUsageDateLayout = "2006-01-02"
+
+// aiUsageGroupColumns maps a rollup grouping to the column expression it sums
+// by. Anything not listed is rejected, so the grouping never reaches the SQL
+// as caller text.
+var aiUsageGroupColumns = map[string]string{
+	"day":      "usage_date",
+	"month":    "substr(usage_date, 1, 7)",
+	"provider": "provider",
+	"model":    "model",
+	"feature":  "feature",
+	"media":    "media_id",
+	"run":      "run_id",
+}
+
+// IsAIUsageGrouping reports whether Rollup accepts groupBy.
+func IsAIUsageGrouping(groupBy string) bool {
+	_, ok := aiUsageGroupColumns[groupBy]
+	return ok
+}
+
+// AIUsageRepositoryInterface defines the contract for the AI spend ledger.
+type AIUsageRepositoryInterface interface {
+	// Record inserts one ledger row. UsageDate and RecordedAt default to now.
+	Record(ctx context.Context, usage *models.AIUsage) error
+	// SpendSince sums cost_usd over the rows dated fromDate or later.
+	SpendSince(ctx context.Context, fromDate string) (float64, error)
+	// Rollup sums the rows dated between fromDate and toDate (inclusive; an
+	// empty bound is open) per groupBy key, largest spend first.
+	Rollup(ctx context.Context, groupBy, fromDate, toDate string) ([]models.AIUsageRollup, error)
+}
+
+// AIUsageRepository provides SQLite data access for the AI spend ledger.
+type AIUsageRepository struct {
+	db *sql.DB
+}
+
+// NewAIUsageRepository creates a new AIUsageRepository.
+func NewAIUsageRepository(db *sql.DB) *AIUsageRepository {
+	return &AIUsageRepository{db: db}
+}
+
+// Compile-time interface verification.
+var _ AIUsageRepositoryInterface = (*AIUsageRepository)(nil)
+
+func (r *AIUsageRepository) Record(ctx context.Context, usage *models.AIUsage) error {
+	if usage == nil {
+		return fmt.Errorf("ai usage cannot be nil")
+	}
+	if usage.RecordedAt.IsZero() {
+		usage.RecordedAt = time.Now()
+	}
+	if usage.UsageDate == "" {
+		usage.UsageDate = usage.RecordedAt.Local().Format(AIUsageDateLayout)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO ai_usage (provider, model, feature, media_id, run_id,
+			input_tokens, output_tokens, audio_seconds, cost_usd, usage_date, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, usage.Provider, usage.Model, usage.Feature, usage.MediaID, usage.RunID,
+		usage.InputTokens, usage.OutputTokens, usage.AudioSeconds, usage.CostUSD, usage.UsageDate, usage.RecordedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record ai usage: %w", err)
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		usage.ID = id
+	}
+	return nil
+}
+
+func (r *AIUsageRepository) SpendSince(ctx context.Context, fromDate string) (float64, error) {
+	var spent float64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(cost_usd), 0) FROM ai_usage WHERE usage_date >= ?`, fromDate).Scan(&spent)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum ai usage: %w", err)
+	}
+	return spent, nil
+}
+
+func (r *AIUsageRepository) Rollup(ctx context.Context, groupBy, fromDate, toDate string) ([]models.AIUsageRollup, error) {
+	column, ok := aiUsageGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown ai usage grouping %q", groupBy)
+	}
+
+	query := `SELECT ` + column + `, COUNT(*), SUM(input_tokens), SUM(output_tokens),
+		SUM(audio_seconds), SUM(cost_usd) FROM ai_usage WHERE 1 = 1`
+	var args []interface{}
+	if fromDate != "" {
+		query += ` AND usage_date >= ?`
+		args = append(args, fromDate)
+	}
+	if toDate != "" {
+		query += ` AND usage_date <= ?`
+		args = append(args, toDate)
+	}
+	query += ` GROUP BY 1 ORDER BY 6 DESC, 1`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll up ai usage: %w", err)
+	}
+	defer rows.Close()
+
+	rollups := []models.AIUsageRollup{}
+	for rows.Next() {
+		var row models.AIUsageRollup
+		if err := rows.Scan(&row.Key, &row.Calls, &row.InputTokens, &row.OutputTokens,
+			&row.AudioSeconds, &row.CostUSD); err != nil {
+			return nil, fmt.Errorf("failed to scan ai usage rollup: %w", err)
+		}
+		rollups = append(rollups, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ai usage rollup: %w", err)
+	}
+	return rollups, nil
+}
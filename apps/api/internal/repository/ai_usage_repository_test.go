@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/models"
+)
+
+func TestAIUsageRepository_SpendAndRollup(t *testing.T) {
+	repo := NewAIUsageRepository(setupUsersDB(t))
+	ctx := context.Background()
+
+	for _, u := range []models.AIUsage{
+		{Provider: "claude", Model: "claude-haiku-4-5", Feature: "translate", MediaID: "m1", RunID: "r1", InputTokens: 1000, CostUSD: 0.50, UsageDate: "2026-09-30"},
+		{Provider: "whisper", Model: "whisper-1", Feature: "transcribe", MediaID: "m1", RunID: "r1", AudioSeconds: 600, CostUSD: 0.06, UsageDate: "2026-10-01"},
+		{Provider: "claude", Model: "claude-haiku-4-5", Feature: "parse", InputTokens: 200, CostUSD: 0.01, UsageDate: "2026-10-02"},
+	} {
+		require.NoError(t, repo.Record(ctx, &u))
+		assert.NotZero(t, u.ID)
+	}
+
+	spent, err := repo.SpendSince(ctx, "2026-10-01")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.07, spent, 1e-9)
+
+	byMonth, err := repo.Rollup(ctx, "month", "", "")
+	require.NoError(t, err)
+	require.Len(t, byMonth, 2)
+	assert.Equal(t, "2026-09", byMonth[0].Key, "largest spend first")
+	assert.Equal(t, "2026-10", byMonth[1].Key)
+	assert.Equal(t, 2, byMonth[1].Calls)
+	assert.InDelta(t, 600, byMonth[1].AudioSeconds, 1e-9)
+
+	byProvider, err := repo.Rollup(ctx, "provider", "2026-10-01", "2026-10-01")
+	require.NoError(t, err)
+	require.Len(t, byProvider, 1)
+	assert.Equal(t, "whisper", byProvider[0].Key)
+
+	_, err = repo.Rollup(ctx, "cost_usd; DROP TABLE ai_usage", "", "")
+	assert.Error(t, err)
+}
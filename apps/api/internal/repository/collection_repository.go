@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vido/api/internal/models"
+)
+
+// ErrCollectionNotFound is returned when a collection lookup finds no row.
+var ErrCollectionNotFound = errors.New("collection not found")
+
+// CollectionRepositoryInterface defines the contract for movie collection
+// data access.
+type CollectionRepositoryInterface interface {
+	// Upsert inserts the collection or replaces every field of the stored one
+	// except created_at.
+	Upsert(ctx context.Context, collection *models.Collection) error
+	GetByID(ctx context.Context, id int64) (*models.Collection, error)
+	List(ctx context.Context) ([]models.Collection, error)
+}
+
+// CollectionRepository provides SQLite data access for movie collections.
+type CollectionRepository struct {
+	db *sql.DB
+}
+
+// NewCollectionRepository creates a new CollectionRepository.
+func NewCollectionRepository(db *sql.DB) *CollectionRepository {
+	return &CollectionRepository{db: db}
+}
+
+// Compile-time interface verification.
+var _ CollectionRepositoryInterface = (*CollectionRepository)(nil)
+
+const collectionColumns = `id, name, overview, poster_path, backdrop_path, parts, created_at, updated_at`
+
+func scanCollection(row rowScanner) (*models.Collection, error) {
+	c := &models.Collection{}
+	var partsJSON string
+	if err := row.Scan(&c.ID, &c.Name, &c.Overview, &c.PosterPath, &c.BackdropPath, &partsJSON,
+		&c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(partsJSON), &c.Parts); err != nil {
+		return nil, fmt.Errorf("decode parts of collection %d: %w", c.ID, err)
+	}
+	if c.Parts == nil {
+		c.Parts = []models.CollectionPart{}
+	}
+	return c, nil
+}
+
+func (r *CollectionRepository) Upsert(ctx context.Context, collection *models.Collection) error {
+	if collection == nil {
+		return fmt.Errorf("collection cannot be nil")
+	}
+	parts := collection.Parts
+	if parts == nil {
+		parts = []models.CollectionPart{}
+	}
+	partsJSON, err := json.Marshal(parts)
+	if err != nil {
+		return fmt.Errorf("encode parts: %w", err)
+	}
+
+	now := time.Now()
+	collection.UpdatedAt = now
+	if collection.CreatedAt.IsZero() {
+		collection.CreatedAt = now
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO collections (`+collectionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			overview = excluded.overview,
+			poster_path = excluded.poster_path,
+			backdrop_path = excluded.backdrop_path,
+			parts = excluded.parts,
+			updated_at = excluded.updated_at
+	`, collection.ID, collection.Name, collection.Overview, collection.PosterPath, collection.BackdropPath,
+		string(partsJSON), collection.CreatedAt, collection.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert collection: %w", err)
+	}
+	return nil
+}
+
+func (r *CollectionRepository) GetByID(ctx context.Context, id int64) (*models.Collection, error) {
+	c, err := scanCollection(r.db.QueryRowContext(ctx,
+		`SELECT `+collectionColumns+` FROM collections WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("collection with id %d: %w", id, ErrCollectionNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find collection: %w", err)
+	}
+	return c, nil
+}
+
+func (r *CollectionRepository) List(ctx context.Context) ([]models.Collection, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+collectionColumns+` FROM collections ORDER BY name, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	defer rows.Close()
+
+	var collections []models.Collection
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection: %w", err)
+		}
+		collections = append(collections, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating collections: %w", err)
+	}
+	return collections, nil
+}
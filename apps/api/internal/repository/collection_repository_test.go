@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+)
+
+func TestCollectionRepository_Upsert(t *testing.T) {
+	repo := NewCollectionRepository(setupUsersDB(t))
+	ctx := context.Background()
+
+	hp := &models.Collection{
+		ID:   1241,
+		Name: "哈利波特（系列）",
+		Parts: []models.CollectionPart{
+			{TMDbID: 671, Title: "哈利波特：神秘的魔法石", ReleaseDate: "2001-11-16"},
+		},
+	}
+	require.NoError(t, repo.Upsert(ctx, hp))
+	require.NoError(t, repo.Upsert(ctx, &models.Collection{ID: 10, Name: "Star Wars Collection"}))
+
+	t.Run("round-trips the parts", func(t *testing.T) {
+		got, err := repo.GetByID(ctx, 1241)
+		require.NoError(t, err)
+		assert.Equal(t, hp.Parts, got.Parts)
+	})
+
+	t.Run("upsert replaces the stored row", func(t *testing.T) {
+		created, err := repo.GetByID(ctx, 1241)
+		require.NoError(t, err)
+
+		hp.Parts = append(hp.Parts, models.CollectionPart{TMDbID: 672, Title: "哈利波特：消失的密室"})
+		hp.PosterPath = "/hp.jpg"
+		hp.CreatedAt = time.Time{}
+		require.NoError(t, repo.Upsert(ctx, hp))
+
+		got, err := repo.GetByID(ctx, 1241)
+		require.NoError(t, err)
+		assert.Len(t, got.Parts, 2)
+		assert.Equal(t, "/hp.jpg", got.PosterPath)
+		assert.WithinDuration(t, created.CreatedAt, got.CreatedAt, 0)
+	})
+
+	t.Run("list orders by name and never returns nil parts", func(t *testing.T) {
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, int64(10), list[0].ID)
+		assert.NotNil(t, list[0].Parts)
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, 999)
+		assert.ErrorIs(t, err, ErrCollectionNotFound)
+	})
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vido/api/internal/models"
+)
+
+// DownloadImportRepositoryInterface defines the contract for the post-download
+// importer's ledger.
+type DownloadImportRepositoryInterface interface {
+	// Save inserts the record for a torrent, or replaces the one it has.
+	Save(ctx context.Context, record *models.DownloadImport) error
+	// GetByHash returns nil, nil when the torrent has no record.
+	GetByHash(ctx context.Context, torrentHash string) (*models.DownloadImport, error)
+	// ListRecent returns the newest records first.
+	ListRecent(ctx context.Context, limit int) ([]models.DownloadImport, error)
+	Delete(ctx context.Context, torrentHash string) error
+}
+
+// DownloadImportRepository provides SQLite data access for download imports.
+type DownloadImportRepository struct {
+	db *sql.DB
+}
+
+// NewDownloadImportRepository creates a new DownloadImportRepository.
+func NewDownloadImportRepository(db *sql.DB) *DownloadImportRepository {
+	return &DownloadImportRepository{db: db}
+}
+
+// Compile-time interface verification.
+var _ DownloadImportRepositoryInterface = (*DownloadImportRepository)(nil)
+
+const downloadImportColumns = `torrent_hash, torrent_name, status, request_id, library_id, target_path,
+	files_imported, hardlinked, message, created_at, updated_at`
+
+func scanDownloadImport(row rowScanner) (*models.DownloadImport, error) {
+	r := &models.DownloadImport{}
+	if err := row.Scan(&r.TorrentHash, &r.TorrentName, &r.Status, &r.RequestID, &r.LibraryID, &r.TargetPath,
+		&r.FilesImported, &r.Hardlinked, &r.Message, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *DownloadImportRepository) Save(ctx context.Context, record *models.DownloadImport) error {
+	if record == nil {
+		return fmt.Errorf("download import cannot be nil")
+	}
+	now := time.Now()
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+	record.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO download_imports (`+downloadImportColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(torrent_hash) DO UPDATE SET
+			torrent_name = excluded.torrent_name,
+			status = excluded.status,
+			request_id = excluded.request_id,
+			library_id = excluded.library_id,
+			target_path = excluded.target_path,
+			files_imported = excluded.files_imported,
+			hardlinked = excluded.hardlinked,
+			message = excluded.message,
+			updated_at = excluded.updated_at
+	`, record.TorrentHash, record.TorrentName, record.Status, record.RequestID, record.LibraryID, record.TargetPath,
+		record.FilesImported, record.Hardlinked, record.Message, record.CreatedAt, record.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save download import: %w", err)
+	}
+	return nil
+}
+
+func (r *DownloadImportRepository) GetByHash(ctx context.Context, torrentHash string) (*models.DownloadImport, error) {
+	record, err := scanDownloadImport(r.db.QueryRowContext(ctx,
+		`SELECT `+downloadImportColumns+` FROM download_imports WHERE torrent_hash = ?`, torrentHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find download import: %w", err)
+	}
+	return record, nil
+}
+
+func (r *DownloadImportRepository) ListRecent(ctx context.Context, limit int) ([]models.DownloadImport, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+downloadImportColumns+` FROM download_imports ORDER BY updated_at DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list download imports: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.DownloadImport
+	for rows.Next() {
+		record, err := scanDownloadImport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan download import: %w", err)
+		}
+		records = append(records, *record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating download imports: %w", err)
+	}
+	return records, nil
+}
+
+func (r *DownloadImportRepository) Delete(ctx context.Context, torrentHash string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM download_imports WHERE torrent_hash = ?`, torrentHash); err != nil {
+		return fmt.Errorf("failed to delete download import: %w", err)
+	}
+	return nil
+}
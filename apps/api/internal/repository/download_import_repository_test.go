@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+)
+
+func TestDownloadImportRepository_SaveAndGet(t *testing.T) {
+	repo := NewDownloadImportRepository(setupUsersDB(t))
+	ctx := context.Background()
+
+	missing, err := repo.GetByHash(ctx, "aaaa")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	record := &models.DownloadImport{
+		TorrentHash: "aaaa", TorrentName: "Movie.2024.1080p", Status: models.DownloadImportFailed,
+		Message: models.NewNullString("no space left on device"),
+	}
+	require.NoError(t, repo.Save(ctx, record))
+
+	record.Status = models.DownloadImportImported
+	record.TargetPath = models.NewNullString("/media/movies/Movie (2024)/Movie (2024).mkv")
+	record.FilesImported = 2
+	record.Hardlinked = true
+	record.Message = models.NullString{}
+	require.NoError(t, repo.Save(ctx, record), "saving again replaces the record")
+
+	got, err := repo.GetByHash(ctx, "aaaa")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, models.DownloadImportImported, got.Status)
+	assert.Equal(t, 2, got.FilesImported)
+	assert.True(t, got.Hardlinked)
+	assert.False(t, got.Message.Valid)
+
+	recent, err := repo.ListRecent(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, recent, 1)
+
+	require.NoError(t, repo.Delete(ctx, "aaaa"))
+	got, err = repo.GetByHash(ctx, "aaaa")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -120,6 +120,24 @@ func (r *EpisodeRepository) FindByID(ctx context.Context, id string) (*models.Ep
 	return episode, nil
 }
 
+// FindByFilePath retrieves an episode by its file path. Like the movie twin it
+// returns (nil, nil) when no episode owns the file.
+func (r *EpisodeRepository) FindByFilePath(ctx context.Context, filePath string) (*models.Episode, error) {
+	query := `SELECT ` + episodeSelectColumns + ` FROM episodes WHERE file_path = ?`
+
+	episode := &models.Episode{}
+	err := scanEpisode(r.db.QueryRowContext(ctx, query, filePath), episode)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find episode by file_path: %w", err)
+	}
+
+	return episode, nil
+}
+
 // FindBySeriesID retrieves all episodes for a series
 func (r *EpisodeRepository) FindBySeriesID(ctx context.Context, seriesID string) ([]models.Episode, error) {
 	query := `SELECT ` + episodeSelectColumns + `
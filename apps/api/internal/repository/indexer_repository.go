@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vido/api/internal/models"
+)
+
+// ErrIndexerNotFound is returned when an indexer lookup finds no row.
+var ErrIndexerNotFound = errors.New("indexer not found")
+
+// IndexerRepositoryInterface defines the contract for indexer data access.
+type IndexerRepositoryInterface interface {
+	Create(ctx context.Context, indexer *models.Indexer) error
+	GetByID(ctx context.Context, id string) (*models.Indexer, error)
+	List(ctx context.Context) ([]models.Indexer, error)
+	// ListEnabled returns the indexers the built-in fulfilment path may use.
+	ListEnabled(ctx context.Context) ([]models.Indexer, error)
+	Update(ctx context.Context, indexer *models.Indexer) error
+	Delete(ctx context.Context, id string) error
+}
+
+// IndexerRepository provides SQLite data access for indexers.
+type IndexerRepository struct {
+	db *sql.DB
+}
+
+// NewIndexerRepository creates a new IndexerRepository.
+func NewIndexerRepository(db *sql.DB) *IndexerRepository {
+	return &IndexerRepository{db: db}
+}
+
+// Compile-time interface verification.
+var _ IndexerRepositoryInterface = (*IndexerRepository)(nil)
+
+const indexerColumns = `id, name, type, url, categories, enabled, created_at, updated_at`
+
+func scanIndexer(row rowScanner) (*models.Indexer, error) {
+	ix := &models.Indexer{}
+	var categories string
+	if err := row.Scan(&ix.ID, &ix.Name, &ix.Type, &ix.URL, &categories, &ix.Enabled,
+		&ix.CreatedAt, &ix.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(categories), &ix.Categories); err != nil {
+		return nil, fmt.Errorf("decode categories of indexer %s: %w", ix.ID, err)
+	}
+	return ix, nil
+}
+
+// encodeCategories stores a nil list as an empty JSON array so reads never
+// see JSON null.
+func encodeCategories(categories []int) (string, error) {
+	if categories == nil {
+		categories = []int{}
+	}
+	b, err := json.Marshal(categories)
+	if err != nil {
+		return "", fmt.Errorf("encode categories: %w", err)
+	}
+	return string(b), nil
+}
+
+func (r *IndexerRepository) Create(ctx context.Context, indexer *models.Indexer) error {
+	if indexer == nil {
+		return fmt.Errorf("indexer cannot be nil")
+	}
+	if indexer.ID == "" {
+		indexer.ID = uuid.New().String()
+	}
+	now := time.Now()
+	indexer.CreatedAt = now
+	indexer.UpdatedAt = now
+
+	categories, err := encodeCategories(indexer.Categories)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO indexers (`+indexerColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, indexer.ID, indexer.Name, indexer.Type, indexer.URL, categories, indexer.Enabled,
+		indexer.CreatedAt, indexer.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create indexer: %w", err)
+	}
+	return nil
+}
+
+func (r *IndexerRepository) GetByID(ctx context.Context, id string) (*models.Indexer, error) {
+	ix, err := scanIndexer(r.db.QueryRowContext(ctx,
+		`SELECT `+indexerColumns+` FROM indexers WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("indexer with id %s: %w", id, ErrIndexerNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find indexer: %w", err)
+	}
+	return ix, nil
+}
+
+func (r *IndexerRepository) List(ctx context.Context) ([]models.Indexer, error) {
+	return r.list(ctx, `SELECT `+indexerColumns+` FROM indexers ORDER BY created_at, name`)
+}
+
+func (r *IndexerRepository) ListEnabled(ctx context.Context) ([]models.Indexer, error) {
+	return r.list(ctx, `SELECT `+indexerColumns+` FROM indexers WHERE enabled = 1 ORDER BY created_at, name`)
+}
+
+func (r *IndexerRepository) list(ctx context.Context, query string) ([]models.Indexer, error) {
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexers: %w", err)
+	}
+	defer rows.Close()
+
+	var indexers []models.Indexer
+	for rows.Next() {
+		ix, err := scanIndexer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan indexer: %w", err)
+		}
+		indexers = append(indexers, *ix)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating indexers: %w", err)
+	}
+	return indexers, nil
+}
+
+// Update writes every mutable field; the type of an indexer is fixed at
+// create.
+func (r *IndexerRepository) Update(ctx context.Context, indexer *models.Indexer) error {
+	if indexer == nil {
+		return fmt.Errorf("indexer cannot be nil")
+	}
+	indexer.UpdatedAt = time.Now()
+
+	categories, err := encodeCategories(indexer.Categories)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE indexers
+		SET name = ?, url = ?, categories = ?, enabled = ?, updated_at = ?
+		WHERE id = ?
+	`, indexer.Name, indexer.URL, categories, indexer.Enabled, indexer.UpdatedAt, indexer.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update indexer: %w", err)
+	}
+	return requireRowAffected(result, fmt.Errorf("indexer with id %s: %w", indexer.ID, ErrIndexerNotFound))
+}
+
+func (r *IndexerRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM indexers WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete indexer: %w", err)
+	}
+	return requireRowAffected(result, fmt.Errorf("indexer with id %s: %w", id, ErrIndexerNotFound))
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+)
+
+func TestIndexerRepository_CRUD(t *testing.T) {
+	db := setupUsersDB(t)
+	repo := NewIndexerRepository(db)
+	ctx := context.Background()
+
+	jackett := &models.Indexer{Name: "Jackett", Type: models.IndexerTypeTorznab,
+		URL: "http://jackett:9117/api/v2.0/indexers/all/results/torznab", Categories: []int{2000, 5000}, Enabled: true}
+	feed := &models.Indexer{Name: "Feed", Type: models.IndexerTypeRSS, URL: "https://example.org/rss"}
+	require.NoError(t, repo.Create(ctx, jackett))
+	require.NoError(t, repo.Create(ctx, feed))
+
+	got, err := repo.GetByID(ctx, jackett.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2000, 5000}, got.Categories)
+	assert.True(t, got.Enabled)
+
+	enabled, err := repo.ListEnabled(ctx)
+	require.NoError(t, err)
+	require.Len(t, enabled, 1)
+	assert.Equal(t, jackett.ID, enabled[0].ID)
+
+	feed.Enabled = true
+	feed.Categories = nil
+	require.NoError(t, repo.Update(ctx, feed))
+	all, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.True(t, all[1].Enabled)
+	assert.NotNil(t, all[1].Categories, "a nil list is stored as an empty one")
+
+	require.NoError(t, repo.Delete(ctx, jackett.ID))
+	_, err = repo.GetByID(ctx, jackett.ID)
+	assert.ErrorIs(t, err, ErrIndexerNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, jackett.ID), ErrIndexerNotFound)
+}
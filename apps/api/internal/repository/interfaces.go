@@ -97,6 +97,11 @@ type MovieRepositoryInterface interface {
 	// Needed by: Story 7-2 (detect removed files during incremental scan)
 	FindAllWithFilePath(ctx context.Context) ([]models.Movie, error)
 
+	// FindAllWithFilePathByTMDbID retrieves every on-disk copy of one title
+	// (FindByTMDbID returns an arbitrary one).
+	// Needed by: quality upgrades (judge the best copy)
+	FindAllWithFilePathByTMDbID(ctx context.Context, tmdbID int64) ([]models.Movie, error)
+
 	// GetStats returns aggregate statistics including total and unmatched counts
 	// Needed by: Story 9c-4 (unmatched filter count badge)
 	GetStats(ctx context.Context) (*MediaStats, error)
@@ -245,6 +250,10 @@ type EpisodeRepositoryInterface interface {
 	// FindBySeriesSeasonEpisode retrieves an episode by series ID, season, and episode number
 	FindBySeriesSeasonEpisode(ctx context.Context, seriesID string, season, episode int) (*models.Episode, error)
 
+	// FindByFilePath retrieves the episode whose media file is at filePath,
+	// or nil when none is — the media-server watch-state import matches on it.
+	FindByFilePath(ctx context.Context, filePath string) (*models.Episode, error)
+
 	// FindMissingZhHantSubtitle retrieves episodes with a media file but no
 	// zh-Hant subtitle — the episode-grain twin of the movie enumeration.
 	// Needed by: Story sub-1-6 AC #11 (scanner auto-enqueue). 9R-16 shipped
@@ -29,6 +29,9 @@ func setupLibraryTestDB(t *testing.T) *sql.DB {
 			content_type TEXT NOT NULL CHECK(content_type IN ('movie', 'series')),
 			auto_detect INTEGER NOT NULL DEFAULT 0,
 			auto_subtitle INTEGER NOT NULL DEFAULT 0,
+			subtitle_output_mode TEXT NOT NULL DEFAULT '',
+			organize_template TEXT NOT NULL DEFAULT '',
+			quality_profile_id TEXT,
 			sort_order INTEGER NOT NULL DEFAULT 0,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
@@ -422,3 +425,78 @@ func TestMediaLibraryRepository_AutoSubtitleRoundTrip(t *testing.T) {
 		assert.True(t, got.AutoSubtitle, "a missing INSERT column silently drops the value")
 	})
 }
+
+// TestMediaLibraryRepository_OrganizeTemplateRoundTrip threads the organizer
+// template through the same four CRUD sites as auto_subtitle.
+func TestMediaLibraryRepository_OrganizeTemplateRoundTrip(t *testing.T) {
+	db := setupLibraryTestDB(t)
+	defer db.Close()
+	repo := NewMediaLibraryRepository(db)
+	ctx := context.Background()
+
+	const tpl = "{title} ({year})/Season {season:02}/{title} - S{season:02}E{episode:02}"
+	lib := &models.MediaLibrary{ID: "lib-tv", Name: "我的影集", ContentType: models.ContentTypeSeries, OrganizeTemplate: tpl}
+	require.NoError(t, repo.Create(ctx, lib))
+
+	got, err := repo.GetByID(ctx, "lib-tv")
+	require.NoError(t, err)
+	assert.Equal(t, tpl, got.OrganizeTemplate)
+
+	lib.OrganizeTemplate = ""
+	require.NoError(t, repo.Update(ctx, lib))
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Empty(t, all[0].OrganizeTemplate, "clearing the template must fall back to the built-in layout")
+}
+
+// TestMediaLibraryRepository_QualityProfileRoundTrip threads the quality
+// profile assignment through the same CRUD sites.
+func TestMediaLibraryRepository_QualityProfileRoundTrip(t *testing.T) {
+	db := setupLibraryTestDB(t)
+	defer db.Close()
+	repo := NewMediaLibraryRepository(db)
+	ctx := context.Background()
+
+	lib := &models.MediaLibrary{ID: "lib-mv", Name: "我的電影", ContentType: models.ContentTypeMovie,
+		QualityProfileID: models.NewNullString("profile-hd")}
+	require.NoError(t, repo.Create(ctx, lib))
+
+	got, err := repo.GetByID(ctx, "lib-mv")
+	require.NoError(t, err)
+	assert.Equal(t, "profile-hd", got.QualityProfileID.String)
+
+	lib.QualityProfileID = models.NullString{}
+	require.NoError(t, repo.Update(ctx, lib))
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.False(t, all[0].QualityProfileID.Valid)
+}
+
+// TestMediaLibraryRepository_SubtitleOutputModeRoundTrip threads the
+// bilingual output mode through the same CRUD sites.
+func TestMediaLibraryRepository_SubtitleOutputModeRoundTrip(t *testing.T) {
+	db := setupLibraryTestDB(t)
+	defer db.Close()
+	repo := NewMediaLibraryRepository(db)
+	ctx := context.Background()
+
+	lib := &models.MediaLibrary{ID: "lib-anime", Name: "我的動畫", ContentType: models.ContentTypeSeries,
+		SubtitleOutputMode: models.SubtitleOutputBilingualASS}
+	require.NoError(t, repo.Create(ctx, lib))
+
+	got, err := repo.GetByID(ctx, "lib-anime")
+	require.NoError(t, err)
+	assert.Equal(t, models.SubtitleOutputBilingualASS, got.SubtitleOutputMode)
+
+	lib.SubtitleOutputMode = models.SubtitleOutputDefault
+	require.NoError(t, repo.Update(ctx, lib))
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, models.SubtitleOutputDefault, all[0].SubtitleOutputMode, "clearing the mode must fall back to translated-only")
+}
@@ -353,6 +353,16 @@ func (r *MovieRepository) List(ctx context.Context, params ListParams) ([]models
 		conditions = append(conditions, "(tmdb_id IS NULL OR tmdb_id = 0)")
 	}
 
+	// watch_status is per user; the handler supplies watch_user ('' on an
+	// install without accounts).
+	if status, ok := params.Filters["watch_status"].(string); ok && status != "" {
+		userID, _ := params.Filters["watch_user"].(string)
+		if cond, condArgs := movieWatchCondition(status, userID); cond != "" {
+			conditions = append(conditions, cond)
+			args = append(args, condArgs...)
+		}
+	}
+
 	whereClause := "WHERE " + conditions[0]
 	for _, c := range conditions[1:] {
 		whereClause += " AND " + c
@@ -1061,6 +1071,32 @@ func (r *MovieRepository) FindAllWithFilePath(ctx context.Context) ([]models.Mov
 	return movies, nil
 }
 
+// FindAllWithFilePathByTMDbID retrieves every movie row of a TMDb ID that has
+// a file on disk — a title can be owned more than once (a 720p and a 2160p,
+// or one copy per library).
+func (r *MovieRepository) FindAllWithFilePathByTMDbID(ctx context.Context, tmdbID int64) ([]models.Movie, error) {
+	query := fmt.Sprintf(`SELECT %s FROM movies WHERE tmdb_id = ? AND file_path IS NOT NULL AND file_path != '' AND is_removed = 0 ORDER BY created_at`, movieSelectColumns)
+
+	rows, err := r.db.QueryContext(ctx, query, tmdbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query movies by tmdb_id: %w", err)
+	}
+	defer rows.Close()
+
+	var movies []models.Movie
+	for rows.Next() {
+		movie, err := scanMovie(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan movie: %w", err)
+		}
+		movies = append(movies, movie)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating movies: %w", err)
+	}
+	return movies, nil
+}
+
 // Upsert creates or updates a movie based on TMDb ID
 func (r *MovieRepository) Upsert(ctx context.Context, movie *models.Movie) error {
 	if movie == nil {
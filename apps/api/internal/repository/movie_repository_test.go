@@ -1272,6 +1272,45 @@ func TestMovieFindByTMDbIDNotFound(t *testing.T) {
 	}
 }
 
+// TestMovieFindAllWithFilePathByTMDbID returns every on-disk copy of a title
+// and leaves out removed and file-less rows.
+func TestMovieFindAllWithFilePathByTMDbID(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewMovieRepository(db)
+	ctx := context.Background()
+
+	for _, m := range []*models.Movie{
+		{ID: "m-720", Title: "Fight Club", ReleaseDate: "1999-10-15", TMDbID: models.NewNullInt64(550),
+			FilePath: models.NewNullString("/m/Fight.Club.1999.720p.mkv")},
+		{ID: "m-2160", Title: "Fight Club", ReleaseDate: "1999-10-15", TMDbID: models.NewNullInt64(550),
+			FilePath: models.NewNullString("/m/Fight.Club.1999.2160p.mkv")},
+		{ID: "m-gone", Title: "Fight Club", ReleaseDate: "1999-10-15", TMDbID: models.NewNullInt64(550),
+			FilePath: models.NewNullString("/m/Fight.Club.1999.480p.mkv"), IsRemoved: true},
+		{ID: "m-nofile", Title: "Fight Club", ReleaseDate: "1999-10-15", TMDbID: models.NewNullInt64(550)},
+		{ID: "m-other", Title: "Heat", ReleaseDate: "1995-12-15", TMDbID: models.NewNullInt64(949),
+			FilePath: models.NewNullString("/m/Heat.1995.mkv")},
+	} {
+		if err := repo.Create(ctx, m); err != nil {
+			t.Fatalf("Create %s: %v", m.ID, err)
+		}
+	}
+
+	copies, err := repo.FindAllWithFilePathByTMDbID(ctx, 550)
+	if err != nil {
+		t.Fatalf("FindAllWithFilePathByTMDbID: %v", err)
+	}
+	if len(copies) != 2 {
+		t.Fatalf("Expected 2 copies, got %d", len(copies))
+	}
+	for _, c := range copies {
+		if c.ID != "m-720" && c.ID != "m-2160" {
+			t.Errorf("Unexpected copy %s", c.ID)
+		}
+	}
+}
+
 // TestMovieFindOwnedTMDbIDs verifies batch ownership lookup semantics (Story 10-4).
 func TestMovieFindOwnedTMDbIDs(t *testing.T) {
 	db := setupTestDB(t)
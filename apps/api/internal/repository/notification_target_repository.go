@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vido/api/internal/models"
+)
+
+// ErrNotificationTargetNotFound is returned when a target lookup finds no row.
+var ErrNotificationTargetNotFound = errors.New("notification target not found")
+
+// NotificationTargetRepositoryInterface defines the contract for notification
+// target data access.
+type NotificationTargetRepositoryInterface interface {
+	Create(ctx context.Context, target *models.NotificationTarget) error
+	GetByID(ctx context.Context, id string) (*models.NotificationTarget, error)
+	List(ctx context.Context) ([]models.NotificationTarget, error)
+	Update(ctx context.Context, target *models.NotificationTarget) error
+	Delete(ctx context.Context, id string) error
+}
+
+// NotificationTargetRepository provides SQLite data access for notification
+// targets.
+type NotificationTargetRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationTargetRepository creates a new NotificationTargetRepository.
+func NewNotificationTargetRepository(db *sql.DB) *NotificationTargetRepository {
+	return &NotificationTargetRepository{db: db}
+}
+
+// Compile-time interface verification.
+var _ NotificationTargetRepositoryInterface = (*NotificationTargetRepository)(nil)
+
+const notificationTargetColumns = `id, name, type, enabled, events, config, title_template, body_template, created_at, updated_at`
+
+func scanNotificationTarget(row rowScanner) (*models.NotificationTarget, error) {
+	t := &models.NotificationTarget{}
+	var eventsJSON, configJSON string
+	if err := row.Scan(&t.ID, &t.Name, &t.Type, &t.Enabled, &eventsJSON, &configJSON,
+		&t.TitleTemplate, &t.BodyTemplate, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(eventsJSON), &t.Events); err != nil {
+		return nil, fmt.Errorf("decode events of target %s: %w", t.ID, err)
+	}
+	if err := json.Unmarshal([]byte(configJSON), &t.Config); err != nil {
+		return nil, fmt.Errorf("decode config of target %s: %w", t.ID, err)
+	}
+	return t, nil
+}
+
+// encodeNotificationTarget returns the JSON columns of t; a nil slice or map
+// is stored as an empty one so reads never see JSON null.
+func encodeNotificationTarget(t *models.NotificationTarget) (string, string, error) {
+	events := t.Events
+	if events == nil {
+		events = []models.NotificationEventType{}
+	}
+	config := t.Config
+	if config == nil {
+		config = map[string]string{}
+	}
+	eventsJSON, err := json.Marshal(events)
+	if err != nil {
+		return "", "", fmt.Errorf("encode events: %w", err)
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return "", "", fmt.Errorf("encode config: %w", err)
+	}
+	return string(eventsJSON), string(configJSON), nil
+}
+
+func (r *NotificationTargetRepository) Create(ctx context.Context, target *models.NotificationTarget) error {
+	if target == nil {
+		return fmt.Errorf("notification target cannot be nil")
+	}
+	if target.ID == "" {
+		target.ID = uuid.New().String()
+	}
+	now := time.Now()
+	target.CreatedAt = now
+	target.UpdatedAt = now
+
+	eventsJSON, configJSON, err := encodeNotificationTarget(target)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO notification_targets (`+notificationTargetColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, target.ID, target.Name, target.Type, target.Enabled, eventsJSON, configJSON,
+		target.TitleTemplate, target.BodyTemplate, target.CreatedAt, target.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create notification target: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationTargetRepository) GetByID(ctx context.Context, id string) (*models.NotificationTarget, error) {
+	t, err := scanNotificationTarget(r.db.QueryRowContext(ctx,
+		`SELECT `+notificationTargetColumns+` FROM notification_targets WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("notification target with id %s: %w", id, ErrNotificationTargetNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification target: %w", err)
+	}
+	return t, nil
+}
+
+func (r *NotificationTargetRepository) List(ctx context.Context) ([]models.NotificationTarget, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+notificationTargetColumns+` FROM notification_targets ORDER BY created_at, name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []models.NotificationTarget
+	for rows.Next() {
+		t, err := scanNotificationTarget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification target: %w", err)
+		}
+		targets = append(targets, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification targets: %w", err)
+	}
+	return targets, nil
+}
+
+// Update writes every mutable field; the type of a target is fixed at create.
+func (r *NotificationTargetRepository) Update(ctx context.Context, target *models.NotificationTarget) error {
+	if target == nil {
+		return fmt.Errorf("notification target cannot be nil")
+	}
+	target.UpdatedAt = time.Now()
+
+	eventsJSON, configJSON, err := encodeNotificationTarget(target)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE notification_targets
+		SET name = ?, enabled = ?, events = ?, config = ?, title_template = ?, body_template = ?, updated_at = ?
+		WHERE id = ?
+	`, target.Name, target.Enabled, eventsJSON, configJSON,
+		target.TitleTemplate, target.BodyTemplate, target.UpdatedAt, target.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update notification target: %w", err)
+	}
+	return requireRowAffected(result, fmt.Errorf("notification target with id %s: %w", target.ID, ErrNotificationTargetNotFound))
+}
+
+func (r *NotificationTargetRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM notification_targets WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification target: %w", err)
+	}
+	return requireRowAffected(result, fmt.Errorf("notification target with id %s: %w", id, ErrNotificationTargetNotFound))
+}
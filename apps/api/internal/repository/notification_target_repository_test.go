@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+)
+
+func TestNotificationTargetRepository_CRUD(t *testing.T) {
+	repo := NewNotificationTargetRepository(setupUsersDB(t))
+	ctx := context.Background()
+
+	target := &models.NotificationTarget{
+		Name:          "Family Discord",
+		Type:          models.NotificationTargetDiscord,
+		Enabled:       true,
+		Events:        []models.NotificationEventType{models.NotificationEventSubtitleComplete},
+		Config:        map[string]string{"username": "Vido"},
+		TitleTemplate: "{{.Title}}",
+	}
+	require.NoError(t, repo.Create(ctx, target))
+	assert.NotEmpty(t, target.ID)
+
+	t.Run("round-trips the JSON columns", func(t *testing.T) {
+		got, err := repo.GetByID(ctx, target.ID)
+		require.NoError(t, err)
+		assert.Equal(t, target.Events, got.Events)
+		assert.Equal(t, target.Config, got.Config)
+		assert.Equal(t, "{{.Title}}", got.TitleTemplate)
+		assert.True(t, got.Enabled)
+	})
+
+	t.Run("nil config is stored as an empty map", func(t *testing.T) {
+		bare := &models.NotificationTarget{Name: "hook", Type: models.NotificationTargetWebhook,
+			Events: []models.NotificationEventType{models.NotificationEventBackupFailed}}
+		require.NoError(t, repo.Create(ctx, bare))
+
+		got, err := repo.GetByID(ctx, bare.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, got.Config)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		target.Enabled = false
+		target.Events = append(target.Events, models.NotificationEventSubtitleFailed)
+		require.NoError(t, repo.Update(ctx, target))
+
+		targets, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, targets, 2)
+		assert.False(t, targets[0].Enabled)
+		assert.Len(t, targets[0].Events, 2)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, target.ID))
+		_, err := repo.GetByID(ctx, target.ID)
+		assert.ErrorIs(t, err, ErrNotificationTargetNotFound)
+		assert.ErrorIs(t, repo.Delete(ctx, target.ID), ErrNotificationTargetNotFound)
+	})
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// OrganizedSourceRepositoryInterface defines the contract for the ledger of
+// files a copy or hardlink organize left in place.
+type OrganizedSourceRepositoryInterface interface {
+	// Record notes that source was organized into target, replacing any
+	// earlier entry for source.
+	Record(ctx context.Context, source, target string) error
+	// Forget drops the entry for source; a source with none is not an error.
+	Forget(ctx context.Context, source string) error
+	// IsOrganizedSource reports whether path was left behind by an organize.
+	IsOrganizedSource(ctx context.Context, path string) (bool, error)
+}
+
+// OrganizedSourceRepository provides SQLite data access for organized sources.
+type OrganizedSourceRepository struct {
+	db *sql.DB
+}
+
+// NewOrganizedSourceRepository creates a new OrganizedSourceRepository.
+func NewOrganizedSourceRepository(db *sql.DB) *OrganizedSourceRepository {
+	return &OrganizedSourceRepository{db: db}
+}
+
+// Compile-time interface verification.
+var _ OrganizedSourceRepositoryInterface = (*OrganizedSourceRepository)(nil)
+
+func (r *OrganizedSourceRepository) Record(ctx context.Context, source, target string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO organized_sources (source_path, target_path) VALUES (?, ?)
+		ON CONFLICT(source_path) DO UPDATE SET target_path = excluded.target_path
+	`, source, target)
+	if err != nil {
+		return fmt.Errorf("failed to record organized source: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizedSourceRepository) Forget(ctx context.Context, source string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM organized_sources WHERE source_path = ?`, source); err != nil {
+		return fmt.Errorf("failed to forget organized source: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizedSourceRepository) IsOrganizedSource(ctx context.Context, path string) (bool, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM organized_sources WHERE source_path = ?`, path).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to check organized source: %w", err)
+	}
+	return n > 0, nil
+}
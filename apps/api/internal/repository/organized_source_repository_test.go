@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizedSourceRepository_RecordCheckForget(t *testing.T) {
+	repo := NewOrganizedSourceRepository(setupUsersDB(t))
+	ctx := context.Background()
+	source := "/media/movies/Arrival.2016.1080p.BluRay.x264-SPARKS/Arrival.2016.1080p.BluRay.x264-SPARKS.mkv"
+
+	got, err := repo.IsOrganizedSource(ctx, source)
+	require.NoError(t, err)
+	assert.False(t, got)
+
+	require.NoError(t, repo.Record(ctx, source, "/media/movies/Arrival (2016)/Arrival (2016).mkv"))
+	require.NoError(t, repo.Record(ctx, source, "/media/movies/異星入境 (2016)/異星入境 (2016).mkv"), "recording again replaces the entry")
+	got, err = repo.IsOrganizedSource(ctx, source)
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	require.NoError(t, repo.Forget(ctx, source))
+	require.NoError(t, repo.Forget(ctx, source), "forgetting twice is fine")
+	got, err = repo.IsOrganizedSource(ctx, source)
+	require.NoError(t, err)
+	assert.False(t, got)
+}
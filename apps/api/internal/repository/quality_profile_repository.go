@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vido/api/internal/models"
+)
+
+// ErrQualityProfileNotFound is returned when a profile lookup finds no row.
+var ErrQualityProfileNotFound = errors.New("quality profile not found")
+
+// QualityProfileRepositoryInterface defines the contract for quality profile
+// data access.
+type QualityProfileRepositoryInterface interface {
+	Create(ctx context.Context, profile *models.QualityProfile) error
+	GetByID(ctx context.Context, id string) (*models.QualityProfile, error)
+	List(ctx context.Context) ([]models.QualityProfile, error)
+	Update(ctx context.Context, profile *models.QualityProfile) error
+	// Delete removes a profile and unassigns it from every library in the
+	// same transaction (migration 037 has no foreign key to do it).
+	Delete(ctx context.Context, id string) error
+}
+
+// QualityProfileRepository provides SQLite data access for quality profiles.
+type QualityProfileRepository struct {
+	db *sql.DB
+}
+
+// NewQualityProfileRepository creates a new QualityProfileRepository.
+func NewQualityProfileRepository(db *sql.DB) *QualityProfileRepository {
+	return &QualityProfileRepository{db: db}
+}
+
+// Compile-time interface verification.
+var _ QualityProfileRepositoryInterface = (*QualityProfileRepository)(nil)
+
+const qualityProfileColumns = `id, name, resolutions, sources, codecs, cutoff, created_at, updated_at`
+
+func scanQualityProfile(row rowScanner) (*models.QualityProfile, error) {
+	p := &models.QualityProfile{}
+	var resolutions, sources, codecs string
+	if err := row.Scan(&p.ID, &p.Name, &resolutions, &sources, &codecs, &p.Cutoff,
+		&p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	for _, col := range []struct {
+		raw string
+		dst *[]string
+	}{{resolutions, &p.Resolutions}, {sources, &p.Sources}, {codecs, &p.Codecs}} {
+		if err := json.Unmarshal([]byte(col.raw), col.dst); err != nil {
+			return nil, fmt.Errorf("decode allow-list of profile %s: %w", p.ID, err)
+		}
+	}
+	return p, nil
+}
+
+// encodeAllowList stores a nil list as an empty JSON array so reads never see
+// JSON null.
+func encodeAllowList(list []string) (string, error) {
+	if list == nil {
+		list = []string{}
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		return "", fmt.Errorf("encode allow-list: %w", err)
+	}
+	return string(b), nil
+}
+
+func encodeQualityProfile(p *models.QualityProfile) (resolutions, sources, codecs string, err error) {
+	if resolutions, err = encodeAllowList(p.Resolutions); err != nil {
+		return
+	}
+	if sources, err = encodeAllowList(p.Sources); err != nil {
+		return
+	}
+	codecs, err = encodeAllowList(p.Codecs)
+	return
+}
+
+func (r *QualityProfileRepository) Create(ctx context.Context, profile *models.QualityProfile) error {
+	if profile == nil {
+		return fmt.Errorf("quality profile cannot be nil")
+	}
+	if profile.ID == "" {
+		profile.ID = uuid.New().String()
+	}
+	now := time.Now()
+	profile.CreatedAt = now
+	profile.UpdatedAt = now
+
+	resolutions, sources, codecs, err := encodeQualityProfile(profile)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO quality_profiles (`+qualityProfileColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, profile.ID, profile.Name, resolutions, sources, codecs, profile.Cutoff,
+		profile.CreatedAt, profile.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create quality profile: %w", err)
+	}
+	return nil
+}
+
+func (r *QualityProfileRepository) GetByID(ctx context.Context, id string) (*models.QualityProfile, error) {
+	p, err := scanQualityProfile(r.db.QueryRowContext(ctx,
+		`SELECT `+qualityProfileColumns+` FROM quality_profiles WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("quality profile with id %s: %w", id, ErrQualityProfileNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find quality profile: %w", err)
+	}
+	return p, nil
+}
+
+func (r *QualityProfileRepository) List(ctx context.Context) ([]models.QualityProfile, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+qualityProfileColumns+` FROM quality_profiles ORDER BY created_at, name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quality profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []models.QualityProfile
+	for rows.Next() {
+		p, err := scanQualityProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quality profile: %w", err)
+		}
+		profiles = append(profiles, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating quality profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+func (r *QualityProfileRepository) Update(ctx context.Context, profile *models.QualityProfile) error {
+	if profile == nil {
+		return fmt.Errorf("quality profile cannot be nil")
+	}
+	profile.UpdatedAt = time.Now()
+
+	resolutions, sources, codecs, err := encodeQualityProfile(profile)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE quality_profiles
+		SET name = ?, resolutions = ?, sources = ?, codecs = ?, cutoff = ?, updated_at = ?
+		WHERE id = ?
+	`, profile.Name, resolutions, sources, codecs, profile.Cutoff, profile.UpdatedAt, profile.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update quality profile: %w", err)
+	}
+	return requireRowAffected(result, fmt.Errorf("quality profile with id %s: %w", profile.ID, ErrQualityProfileNotFound))
+}
+
+func (r *QualityProfileRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE media_libraries SET quality_profile_id = NULL WHERE quality_profile_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to unassign quality profile: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM quality_profiles WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete quality profile: %w", err)
+	}
+	if err := requireRowAffected(result, fmt.Errorf("quality profile with id %s: %w", id, ErrQualityProfileNotFound)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+)
+
+func TestQualityProfileRepository_CRUD(t *testing.T) {
+	db := setupUsersDB(t)
+	repo := NewQualityProfileRepository(db)
+	libraries := NewMediaLibraryRepository(db)
+	ctx := context.Background()
+
+	profile := &models.QualityProfile{
+		Name:        "HD",
+		Resolutions: []string{"1080p", "720p"},
+		Sources:     []string{"BluRay", "WEB-DL"},
+		Cutoff:      "1080p",
+	}
+	require.NoError(t, repo.Create(ctx, profile))
+	assert.NotEmpty(t, profile.ID)
+
+	t.Run("round-trips the allow-lists", func(t *testing.T) {
+		got, err := repo.GetByID(ctx, profile.ID)
+		require.NoError(t, err)
+		assert.Equal(t, profile.Resolutions, got.Resolutions)
+		assert.Equal(t, profile.Sources, got.Sources)
+		assert.NotNil(t, got.Codecs, "a nil list is stored as an empty one")
+		assert.Equal(t, "1080p", got.Cutoff)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		profile.Cutoff = "720p"
+		require.NoError(t, repo.Update(ctx, profile))
+
+		profiles, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, profiles, 1)
+		assert.Equal(t, "720p", profiles[0].Cutoff)
+	})
+
+	t.Run("delete unassigns libraries", func(t *testing.T) {
+		lib := &models.MediaLibrary{Name: "我的電影", ContentType: models.ContentTypeMovie,
+			QualityProfileID: models.NewNullString(profile.ID)}
+		require.NoError(t, libraries.Create(ctx, lib))
+
+		require.NoError(t, repo.Delete(ctx, profile.ID))
+		_, err := repo.GetByID(ctx, profile.ID)
+		assert.ErrorIs(t, err, ErrQualityProfileNotFound)
+		assert.ErrorIs(t, repo.Delete(ctx, profile.ID), ErrQualityProfileNotFound)
+
+		got, err := libraries.GetByID(ctx, lib.ID)
+		require.NoError(t, err)
+		assert.False(t, got.QualityProfileID.Valid)
+	})
+}
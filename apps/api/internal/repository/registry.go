@@ -27,6 +27,17 @@ type Repositories struct {
 	Requests          RequestRepositoryInterface
 	Glossary          GlossaryRepositoryInterface
 	SubtitleRuns      SubtitleRunRepositoryInterface
+	Users             UserRepositoryInterface
+	Sessions          SessionRepositoryInterface
+	WatchState        WatchStateRepositoryInterface
+	Notifications     NotificationTargetRepositoryInterface
+	Collections       CollectionRepositoryInterface
+	QualityProfiles   QualityProfileRepositoryInterface
+	Indexers          IndexerRepositoryInterface
+	DownloadImports   DownloadImportRepositoryInterface
+	UserRatings       UserRatingRepositoryInterface
+	AIUsage           AIUsageRepositoryInterface
+	OrganizedSources  OrganizedSourceRepositoryInterface
 }
 
 // NewRepositories creates all repository implementations for the given database connection.
@@ -59,6 +70,17 @@ func NewRepositories(db *sql.DB) *Repositories {
 		Requests:          NewRequestRepository(db),
 		Glossary:          NewGlossaryRepository(db),
 		SubtitleRuns:      NewSubtitleRunRepository(db),
+		Users:             NewUserRepository(db),
+		Sessions:          NewSessionRepository(db),
+		WatchState:        NewWatchStateRepository(db),
+		Notifications:     NewNotificationTargetRepository(db),
+		Collections:       NewCollectionRepository(db),
+		QualityProfiles:   NewQualityProfileRepository(db),
+		Indexers:          NewIndexerRepository(db),
+		DownloadImports:   NewDownloadImportRepository(db),
+		UserRatings:       NewUserRatingRepository(db),
+		AIUsage:           NewAIUsageRepository(db),
+		OrganizedSources:  NewOrganizedSourceRepository(db),
 	}
 }
 
@@ -91,5 +113,16 @@ func NewRepositoriesWithCache(db *sql.DB) *Repositories {
 		Requests:          NewRequestRepository(db),
 		Glossary:          NewGlossaryRepository(db),
 		SubtitleRuns:      NewSubtitleRunRepository(db),
+		Users:             NewUserRepository(db),
+		Sessions:          NewSessionRepository(db),
+		WatchState:        NewWatchStateRepository(db),
+		Notifications:     NewNotificationTargetRepository(db),
+		Collections:       NewCollectionRepository(db),
+		QualityProfiles:   NewQualityProfileRepository(db),
+		Indexers:          NewIndexerRepository(db),
+		DownloadImports:   NewDownloadImportRepository(db),
+		UserRatings:       NewUserRatingRepository(db),
+		AIUsage:           NewAIUsageRepository(db),
+		OrganizedSources:  NewOrganizedSourceRepository(db),
 	}
 }
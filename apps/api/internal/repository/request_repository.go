@@ -59,14 +59,14 @@ var _ RequestRepositoryInterface = (*RequestRepository)(nil)
 
 // requestColumns is the canonical column list — INSERT, SELECT, and scan stay
 // in sync through it (Rule 15 DB Column Sync).
-const requestColumns = `id, tmdb_id, media_type, title, status, fulfilment_source, external_id, seasons, episodes, error_message, requested_at, updated_at`
+const requestColumns = `id, tmdb_id, media_type, title, status, fulfilment_source, external_id, seasons, episodes, error_message, upgrade_cutoff, requested_at, updated_at`
 
 func scanRequest(scanner interface{ Scan(dest ...any) error }) (models.Request, error) {
 	var r models.Request
 	err := scanner.Scan(
 		&r.ID, &r.TMDbID, &r.MediaType, &r.Title, &r.Status,
 		&r.FulfilmentSource, &r.ExternalID, &r.Seasons, &r.Episodes,
-		&r.ErrorMessage, &r.RequestedAt, &r.UpdatedAt,
+		&r.ErrorMessage, &r.UpgradeCutoff, &r.RequestedAt, &r.UpdatedAt,
 	)
 	return r, err
 }
@@ -85,11 +85,11 @@ func (r *RequestRepository) Create(ctx context.Context, request *models.Request)
 	request.RequestedAt = now
 	request.UpdatedAt = now
 
-	query := `INSERT INTO requests (` + requestColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO requests (` + requestColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	_, err := r.db.ExecContext(ctx, query,
 		request.ID, request.TMDbID, request.MediaType, request.Title, request.Status,
 		request.FulfilmentSource, request.ExternalID, request.Seasons, request.Episodes,
-		request.ErrorMessage, request.RequestedAt, request.UpdatedAt,
+		request.ErrorMessage, request.UpgradeCutoff, request.RequestedAt, request.UpdatedAt,
 	)
 	if err != nil {
 		// The partial unique index (idx_requests_active_unique) rejects a second
@@ -52,6 +52,22 @@ func TestRequestRepository_Create(t *testing.T) {
 	})
 }
 
+// TestRequestRepository_UpgradeCutoffRoundTrip covers the migration-037
+// column through requestColumns (Rule 15): an upgrade row keeps its cutoff.
+func TestRequestRepository_UpgradeCutoffRoundTrip(t *testing.T) {
+	repo := NewRequestRepository(setupRequestsDB(t))
+	ctx := context.Background()
+
+	req := &models.Request{TMDbID: 550, MediaType: models.RequestMediaTypeMovie, Title: "鬥陣俱樂部",
+		FulfilmentSource: models.NewNullString(models.RequestFulfilmentSourceBuiltin),
+		UpgradeCutoff:    models.NewNullString("1080p")}
+	require.NoError(t, repo.Create(ctx, req))
+
+	got, err := repo.FindActiveByTMDbID(ctx, 550, models.RequestMediaTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, "1080p", got.UpgradeCutoff.String)
+}
+
 func TestRequestRepository_List(t *testing.T) {
 	repo := NewRequestRepository(setupRequestsDB(t))
 	ctx := context.Background()
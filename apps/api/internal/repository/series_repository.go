@@ -349,6 +349,16 @@ func (r *SeriesRepository) List(ctx context.Context, params ListParams) ([]model
 		conditions = append(conditions, "(tmdb_id IS NULL OR tmdb_id = 0)")
 	}
 
+	// watch_status is per user; the handler supplies watch_user ('' on an
+	// install without accounts).
+	if status, ok := params.Filters["watch_status"].(string); ok && status != "" {
+		userID, _ := params.Filters["watch_user"].(string)
+		if cond, condArgs := seriesWatchCondition(status, userID); cond != "" {
+			conditions = append(conditions, cond)
+			args = append(args, condArgs...)
+		}
+	}
+
 	whereClause := "WHERE " + conditions[0]
 	for _, c := range conditions[1:] {
 		whereClause += " AND " + c
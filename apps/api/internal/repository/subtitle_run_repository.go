@@ -49,24 +49,26 @@ func NewSubtitleRunRepository(db *sql.DB) *SubtitleRunRepository {
 var _ SubtitleRunRepositoryInterface = (*SubtitleRunRepository)(nil)
 
 // subtitleRunColumns keeps INSERT/UPDATE/SELECT/scan in sync (Rule 15 DB Column
-// Sync). All 16 columns of migration 030, in table order. The bugfix-20-1
+// Sync). The 16 columns of migration 030 in table order, then migration 042's
+// output_mode. The bugfix-20-1
 // precedent — series.seasons was never added to the select list, so GetSeasons
 // silently returned [] for every series — is why this is one constant used
 // everywhere rather than four hand-written lists.
 const subtitleRunColumns = `id, media_id, media_type, tmdb_id, metadata_hash, glossary_version, ` +
 	`prompt_version, model_id, status, source_language, output_path, cue_count, ` +
-	`cache_enabled, error_message, started_at, completed_at`
+	`cache_enabled, error_message, started_at, completed_at, output_mode`
 
-// subtitleRunInsertPlaceholders matches subtitleRunColumns 1:1 (16 values).
-const subtitleRunInsertPlaceholders = `?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?`
+// subtitleRunInsertPlaceholders matches subtitleRunColumns 1:1 (17 values).
+const subtitleRunInsertPlaceholders = `?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?`
 
 // subtitleRunUpdateAssignments covers every column except the id key, so an
 // Update can never leave a column stale.
 const subtitleRunUpdateAssignments = `media_id = ?, media_type = ?, tmdb_id = ?, metadata_hash = ?, ` +
 	`glossary_version = ?, prompt_version = ?, model_id = ?, status = ?, source_language = ?, ` +
-	`output_path = ?, cue_count = ?, cache_enabled = ?, error_message = ?, started_at = ?, completed_at = ?`
+	`output_path = ?, cue_count = ?, cache_enabled = ?, error_message = ?, started_at = ?, completed_at = ?, ` +
+	`output_mode = ?`
 
-// subtitleRunValues returns the 16 column values in subtitleRunColumns order.
+// subtitleRunValues returns the 17 column values in subtitleRunColumns order.
 // Both time columns are normalized to UTC before storage: the driver stores a
 // time.Time as text, and FindCompletedRun / ListByStatus ORDER BY that text —
 // a local-time value ("… +0800 CST") would compare by wall-clock digits and
@@ -80,11 +82,11 @@ func subtitleRunValues(run *models.SubtitleRun) []any {
 	return []any{
 		run.ID, run.MediaID, run.MediaType, run.TMDbID, run.MetadataHash, run.GlossaryVersion,
 		run.PromptVersion, run.ModelID, run.Status, run.SourceLanguage, run.OutputPath, run.CueCount,
-		run.CacheEnabled, run.ErrorMessage, run.StartedAt.UTC(), completedAt,
+		run.CacheEnabled, run.ErrorMessage, run.StartedAt.UTC(), completedAt, run.OutputMode,
 	}
 }
 
-// scanSubtitleRun reads all 16 columns in subtitleRunColumns order. The four
+// scanSubtitleRun reads all 17 columns in subtitleRunColumns order. The four
 // nullable TEXT/INTEGER columns go through sql.Null* so a row written by any
 // other path (e.g. a bare INSERT) still scans; the two nullable columns modelled
 // as pointers stay pointers so "unset" survives the round trip.
@@ -96,7 +98,7 @@ func scanSubtitleRun(scanner interface{ Scan(dest ...any) error }) (models.Subti
 	err := scanner.Scan(
 		&run.ID, &run.MediaID, &run.MediaType, &run.TMDbID, &run.MetadataHash, &run.GlossaryVersion,
 		&run.PromptVersion, &run.ModelID, &run.Status, &sourceLanguage, &outputPath, &cueCount,
-		&run.CacheEnabled, &errorMessage, &run.StartedAt, &run.CompletedAt,
+		&run.CacheEnabled, &errorMessage, &run.StartedAt, &run.CompletedAt, &run.OutputMode,
 	)
 	if err != nil {
 		return run, err
@@ -147,7 +149,7 @@ func (r *SubtitleRunRepository) Update(ctx context.Context, run *models.Subtitle
 	if run.Status == "" {
 		return &models.ValidationError{Field: "status", Message: "status is required to update a subtitle run"}
 	}
-	// Update overwrites all 15 non-id columns, so a sparsely-populated struct
+	// Update overwrites all 16 non-id columns, so a sparsely-populated struct
 	// would silently zero started_at and corrupt the ORDER BY started_at
 	// resume/listing semantics.
 	if run.StartedAt.IsZero() {
@@ -189,18 +191,19 @@ func (r *SubtitleRunRepository) FindByID(ctx context.Context, id string) (*model
 }
 
 func (r *SubtitleRunRepository) FindCompletedRun(ctx context.Context, mediaID, mediaType string, v models.RunVersion) (*models.SubtitleRun, error) {
-	// All four tuple columns participate. Dropping any one of them would let a
+	// All five tuple columns participate. Dropping any one of them would let a
 	// re-run with a bumped prompt or model match a stale row and be skipped —
 	// exactly the silent-failure trap the M1 pilot instrumentation exists to
 	// avoid.
 	query := `SELECT ` + subtitleRunColumns + ` FROM subtitle_runs
 		WHERE media_id = ? AND media_type = ? AND status = ?
 		  AND metadata_hash = ? AND glossary_version = ? AND prompt_version = ? AND model_id = ?
+		  AND output_mode = ?
 		ORDER BY started_at DESC LIMIT 1`
 
 	run, err := scanSubtitleRun(r.db.QueryRowContext(ctx, query,
 		mediaID, mediaType, models.SubtitleRunCompleted,
-		v.MetadataHash, v.GlossaryVersion, v.PromptVersion, v.ModelID,
+		v.MetadataHash, v.GlossaryVersion, v.PromptVersion, v.ModelID, v.OutputMode,
 	))
 	if errors.Is(err, sql.ErrNoRows) {
 		// Intentional swallow: "no prior matching run" is the normal first-run
@@ -43,6 +43,7 @@ func fullyPopulatedRun() *models.SubtitleRun {
 		GlossaryVersion: "glossary-v1",
 		PromptVersion:   "prompt-v3",
 		ModelID:         "claude-haiku-4-5",
+		OutputMode:      string(models.SubtitleOutputBilingualASS),
 		Status:          models.SubtitleRunCompleted,
 		SourceLanguage:  "eng",
 		OutputPath:      "/media/tv/show/S01E01.zh-Hant.srt",
@@ -89,6 +90,7 @@ func TestSubtitleRunRepository_RoundTripsAllSixteenColumns(t *testing.T) {
 	assert.WithinDuration(t, want.StartedAt, got.StartedAt, time.Second)       // 15
 	require.NotNil(t, got.CompletedAt, "completed_at must survive")            // 16
 	assert.WithinDuration(t, *want.CompletedAt, *got.CompletedAt, time.Second) //
+	assert.Equal(t, want.OutputMode, got.OutputMode)                           // 17 (migration 042)
 
 	// The version tuple must reassemble from the persisted columns — this is
 	// what sub-1-5b hashes into the cue-grain cache key.
@@ -325,6 +327,11 @@ func TestSubtitleRunRepository_FindCompletedRun_AnySingleTupleFieldMisses(t *tes
 			v.ModelID = "claude-sonnet-5"
 			return v
 		},
+		// A bilingual run must never resume-skip on a translated-only one.
+		"OutputMode": func(v models.RunVersion) models.RunVersion {
+			v.OutputMode = string(models.SubtitleOutputBilingualSRT)
+			return v
+		},
 	}
 
 	for field, mutate := range mutations {
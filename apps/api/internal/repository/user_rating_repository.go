@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vido/api/internal/models"
+)
+
+// UserRatingRepositoryInterface defines the contract for users' own ratings.
+type UserRatingRepositoryInterface interface {
+	// Upsert saves a rating, replacing the user's earlier one of the item.
+	Upsert(ctx context.Context, rating *models.UserRating) error
+	// Get returns nil, nil when the user has not rated the item.
+	Get(ctx context.Context, userID, mediaType, mediaID string) (*models.UserRating, error)
+	ListByUser(ctx context.Context, userID string) ([]models.UserRating, error)
+}
+
+// UserRatingRepository provides SQLite data access for user ratings.
+type UserRatingRepository struct {
+	db *sql.DB
+}
+
+// NewUserRatingRepository creates a new UserRatingRepository.
+func NewUserRatingRepository(db *sql.DB) *UserRatingRepository {
+	return &UserRatingRepository{db: db}
+}
+
+// Compile-time interface verification.
+var _ UserRatingRepositoryInterface = (*UserRatingRepository)(nil)
+
+const userRatingColumns = `user_id, media_type, media_id, rating, source, rated_at, updated_at`
+
+func scanUserRating(row rowScanner) (*models.UserRating, error) {
+	r := &models.UserRating{}
+	if err := row.Scan(&r.UserID, &r.MediaType, &r.MediaID, &r.Rating, &r.Source, &r.RatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *UserRatingRepository) Upsert(ctx context.Context, rating *models.UserRating) error {
+	if rating == nil {
+		return fmt.Errorf("user rating cannot be nil")
+	}
+	rating.UpdatedAt = time.Now().UTC()
+	if rating.RatedAt.IsZero() {
+		rating.RatedAt = rating.UpdatedAt
+	}
+	if rating.Source == "" {
+		rating.Source = models.RatingSourceVido
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_ratings (`+userRatingColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, media_type, media_id) DO UPDATE SET
+			rating = excluded.rating,
+			source = excluded.source,
+			rated_at = excluded.rated_at,
+			updated_at = excluded.updated_at
+	`, rating.UserID, rating.MediaType, rating.MediaID, rating.Rating, rating.Source,
+		rating.RatedAt.UTC(), rating.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save user rating: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRatingRepository) Get(ctx context.Context, userID, mediaType, mediaID string) (*models.UserRating, error) {
+	rating, err := scanUserRating(r.db.QueryRowContext(ctx, `
+		SELECT `+userRatingColumns+` FROM user_ratings
+		WHERE user_id = ? AND media_type = ? AND media_id = ?
+	`, userID, mediaType, mediaID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user rating: %w", err)
+	}
+	return rating, nil
+}
+
+func (r *UserRatingRepository) ListByUser(ctx context.Context, userID string) ([]models.UserRating, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+userRatingColumns+` FROM user_ratings
+		WHERE user_id = ?
+		ORDER BY rated_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user ratings: %w", err)
+	}
+	defer rows.Close()
+
+	var ratings []models.UserRating
+	for rows.Next() {
+		rating, err := scanUserRating(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user rating: %w", err)
+		}
+		ratings = append(ratings, *rating)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user ratings: %w", err)
+	}
+	return ratings, nil
+}
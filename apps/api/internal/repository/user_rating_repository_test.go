@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/models"
+)
+
+func TestUserRatingRepository_UpsertAndGet(t *testing.T) {
+	repo := NewUserRatingRepository(setupUsersDB(t))
+	ctx := context.Background()
+
+	got, err := repo.Get(ctx, "", models.RatingMediaMovie, "m1")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	require.NoError(t, repo.Upsert(ctx, &models.UserRating{MediaType: models.RatingMediaMovie, MediaID: "m1", Rating: 7, Source: models.RatingSourceTrakt}))
+	require.NoError(t, repo.Upsert(ctx, &models.UserRating{MediaType: models.RatingMediaMovie, MediaID: "m1", Rating: 9}))
+
+	got, err = repo.Get(ctx, "", models.RatingMediaMovie, "m1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 9, got.Rating)
+	assert.Equal(t, models.RatingSourceVido, got.Source)
+
+	assert.Error(t, repo.Upsert(ctx, &models.UserRating{MediaType: models.RatingMediaSeries, MediaID: "s1", Rating: 11}), "ratings run 1 to 10")
+
+	ratings, err := repo.ListByUser(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, ratings, 1)
+	ratings, err = repo.ListByUser(ctx, "someone-else")
+	require.NoError(t, err)
+	assert.Empty(t, ratings, "ratings are per user")
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vido/api/internal/models"
+)
+
+// ErrUserNotFound is returned when a user lookup finds no matching record.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrUsernameTaken is returned when a username is already in use (usernames
+// compare case-insensitively).
+var ErrUsernameTaken = errors.New("username already taken")
+
+// ErrUsersExist is returned by CreateFirst when the install already has an
+// account.
+var ErrUsersExist = errors.New("an account already exists")
+
+// ErrSessionNotFound is returned when a session lookup finds no matching record.
+var ErrSessionNotFound = errors.New("session not found")
+
+// UserRepositoryInterface defines the contract for user account data access.
+type UserRepositoryInterface interface {
+	Create(ctx context.Context, user *models.User) error
+	// CreateFirst inserts the user only while the table is empty, in one
+	// statement, so two racing first-admin bootstraps cannot both succeed.
+	// It returns ErrUsersExist otherwise.
+	CreateFirst(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	List(ctx context.Context) ([]models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	TouchLastLogin(ctx context.Context, id string, at time.Time) error
+	Delete(ctx context.Context, id string) error
+	Count(ctx context.Context) (int, error)
+	CountByRole(ctx context.Context, role string) (int, error)
+}
+
+// SessionRepositoryInterface defines the contract for login session data access.
+type SessionRepositoryInterface interface {
+	Create(ctx context.Context, session *models.Session) error
+	Get(ctx context.Context, id string) (*models.Session, error)
+	Delete(ctx context.Context, id string) error
+	// DeleteByUser revokes every session of a user except exceptID (empty
+	// revokes all), so a password change can keep the session that made it.
+	DeleteByUser(ctx context.Context, userID, exceptID string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// UserRepository provides SQLite data access for user accounts.
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new UserRepository.
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// SessionRepository provides SQLite data access for login sessions.
+type SessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository creates a new SessionRepository.
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Compile-time interface verification.
+var (
+	_ UserRepositoryInterface    = (*UserRepository)(nil)
+	_ SessionRepositoryInterface = (*SessionRepository)(nil)
+)
+
+const userColumns = `id, username, password_hash, role, created_at, updated_at, last_login_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	u := &models.User{}
+	var lastLogin sql.NullTime
+	if err := row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt, &lastLogin); err != nil {
+		return nil, err
+	}
+	if lastLogin.Valid {
+		t := lastLogin.Time
+		u.LastLoginAt = &t
+	}
+	return u, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if user == nil {
+		return fmt.Errorf("user cannot be nil")
+	}
+	if user.ID == "" {
+		user.ID = uuid.New().String()
+	}
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, password_hash, role, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, user.ID, user.Username, user.PasswordHash, user.Role, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return fmt.Errorf("username %q: %w", user.Username, ErrUsernameTaken)
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) CreateFirst(ctx context.Context, user *models.User) error {
+	if user == nil {
+		return fmt.Errorf("user cannot be nil")
+	}
+	if user.ID == "" {
+		user.ID = uuid.New().String()
+	}
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, password_hash, role, created_at, updated_at)
+		SELECT ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM users)
+	`, user.ID, user.Username, user.PasswordHash, user.Role, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create first user: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to create first user: %w", err)
+	}
+	if n == 0 {
+		return ErrUsersExist
+	}
+	return nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	u, err := scanUser(r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user with id %s: %w", id, ErrUserNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return u, nil
+}
+
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	u, err := scanUser(r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE username = ?`, username))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user %q: %w", username, ErrUserNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return u, nil
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]models.User, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+userColumns+` FROM users ORDER BY created_at, username`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+	return users, nil
+}
+
+// Update writes the mutable fields: username, password hash and role.
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	if user == nil {
+		return fmt.Errorf("user cannot be nil")
+	}
+	user.UpdatedAt = time.Now()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET username = ?, password_hash = ?, role = ?, updated_at = ?
+		WHERE id = ?
+	`, user.Username, user.PasswordHash, user.Role, user.UpdatedAt, user.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return fmt.Errorf("username %q: %w", user.Username, ErrUsernameTaken)
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return requireRowAffected(result, fmt.Errorf("user with id %s: %w", user.ID, ErrUserNotFound))
+}
+
+func (r *UserRepository) TouchLastLogin(ctx context.Context, id string, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE users SET last_login_at = ? WHERE id = ?`, at, id); err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return requireRowAffected(result, fmt.Errorf("user with id %s: %w", id, ErrUserNotFound))
+}
+
+func (r *UserRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+func (r *UserRepository) CountByRole(ctx context.Context, role string) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE role = ?`, role).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	if session == nil {
+		return fmt.Errorf("session cannot be nil")
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+	// UTC on both sides keeps DeleteExpired's text comparison in SQLite
+	// chronological.
+	session.CreatedAt = session.CreatedAt.UTC()
+	session.ExpiresAt = session.ExpiresAt.UTC()
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO sessions (id, user_id, user_agent, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, session.ID, session.UserID, session.UserAgent, session.CreatedAt, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) Get(ctx context.Context, id string) (*models.Session, error) {
+	s := &models.Session{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, user_agent, created_at, expires_at FROM sessions WHERE id = ?
+	`, id).Scan(&s.ID, &s.UserID, &s.UserAgent, &s.CreatedAt, &s.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+	return s, nil
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) DeleteByUser(ctx context.Context, userID, exceptID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = ? AND id <> ?`, userID, exceptID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at <= ?`, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// requireRowAffected returns notFound when result touched no row.
+func requireRowAffected(result sql.Result, notFound error) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
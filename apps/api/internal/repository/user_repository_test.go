@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/database/migrations"
+	"github.com/vido/api/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+func setupUsersDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	runner, err := migrations.NewRunner(db)
+	require.NoError(t, err)
+	require.NoError(t, runner.RegisterAll(migrations.GetAll()))
+	require.NoError(t, runner.Up(context.Background()))
+	return db
+}
+
+func TestUserRepository_CreateFirstOnlyOnEmptyTable(t *testing.T) {
+	repo := NewUserRepository(setupUsersDB(t))
+	ctx := context.Background()
+
+	require.NoError(t, repo.CreateFirst(ctx, &models.User{Username: "admin", PasswordHash: "hash", Role: models.UserRoleAdmin}))
+	err := repo.CreateFirst(ctx, &models.User{Username: "second", PasswordHash: "hash", Role: models.UserRoleAdmin})
+	assert.ErrorIs(t, err, ErrUsersExist)
+
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestUserRepository_CRUD(t *testing.T) {
+	repo := NewUserRepository(setupUsersDB(t))
+	ctx := context.Background()
+
+	user := &models.User{Username: "Alice", PasswordHash: "hash", Role: models.UserRoleAdmin}
+	require.NoError(t, repo.Create(ctx, user))
+	assert.NotEmpty(t, user.ID)
+
+	t.Run("username lookup ignores case", func(t *testing.T) {
+		got, err := repo.GetByUsername(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, got.ID)
+		assert.Nil(t, got.LastLoginAt)
+	})
+
+	t.Run("duplicate username maps to ErrUsernameTaken", func(t *testing.T) {
+		err := repo.Create(ctx, &models.User{Username: "ALICE", PasswordHash: "x", Role: models.UserRoleUser})
+		assert.ErrorIs(t, err, ErrUsernameTaken)
+	})
+
+	t.Run("update and last login round-trip", func(t *testing.T) {
+		user.Role = models.UserRoleUser
+		user.PasswordHash = "new-hash"
+		require.NoError(t, repo.Update(ctx, user))
+		require.NoError(t, repo.TouchLastLogin(ctx, user.ID, time.Now()))
+
+		got, err := repo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UserRoleUser, got.Role)
+		assert.Equal(t, "new-hash", got.PasswordHash)
+		assert.NotNil(t, got.LastLoginAt)
+	})
+
+	t.Run("counts", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, &models.User{Username: "bob", PasswordHash: "x", Role: models.UserRoleAdmin}))
+		total, err := repo.Count(ctx)
+		require.NoError(t, err)
+		admins, err := repo.CountByRole(ctx, models.UserRoleAdmin)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, 1, admins)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, user.ID))
+		_, err := repo.GetByID(ctx, user.ID)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		assert.ErrorIs(t, repo.Delete(ctx, user.ID), ErrUserNotFound)
+	})
+}
+
+func TestSessionRepository_Lifecycle(t *testing.T) {
+	db := setupUsersDB(t)
+	users := NewUserRepository(db)
+	sessions := NewSessionRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Username: "alice", PasswordHash: "x", Role: models.UserRoleUser}
+	require.NoError(t, users.Create(ctx, user))
+
+	now := time.Now()
+	require.NoError(t, sessions.Create(ctx, &models.Session{ID: "live", UserID: user.ID, ExpiresAt: now.Add(time.Hour)}))
+	require.NoError(t, sessions.Create(ctx, &models.Session{ID: "other", UserID: user.ID, ExpiresAt: now.Add(time.Hour)}))
+	require.NoError(t, sessions.Create(ctx, &models.Session{ID: "stale", UserID: user.ID, ExpiresAt: now.Add(-time.Minute)}))
+
+	t.Run("expired sessions are swept", func(t *testing.T) {
+		n, err := sessions.DeleteExpired(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		_, err = sessions.Get(ctx, "stale")
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+	})
+
+	t.Run("revoking a user's sessions can keep one", func(t *testing.T) {
+		require.NoError(t, sessions.DeleteByUser(ctx, user.ID, "live"))
+		_, err := sessions.Get(ctx, "other")
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+
+		got, err := sessions.Get(ctx, "live")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, got.UserID)
+	})
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vido/api/internal/models"
+)
+
+// ErrWatchStateNotFound is returned when an item has never been played.
+var ErrWatchStateNotFound = errors.New("watch state not found")
+
+// WatchStateRepositoryInterface defines the contract for watch-history data access.
+type WatchStateRepositoryInterface interface {
+	Get(ctx context.Context, userID, mediaType, mediaID string) (*models.WatchState, error)
+	Upsert(ctx context.Context, state *models.WatchState) error
+	ListBySeries(ctx context.Context, userID, seriesID string) ([]models.WatchState, error)
+	// ListRecent returns the user's rows with any activity (watched or
+	// part-played), most recently updated first.
+	ListRecent(ctx context.Context, userID string, limit int) ([]models.WatchState, error)
+}
+
+// WatchStateRepository provides SQLite data access for watch history.
+type WatchStateRepository struct {
+	db *sql.DB
+}
+
+// NewWatchStateRepository creates a new WatchStateRepository.
+func NewWatchStateRepository(db *sql.DB) *WatchStateRepository {
+	return &WatchStateRepository{db: db}
+}
+
+// Compile-time interface verification.
+var _ WatchStateRepositoryInterface = (*WatchStateRepository)(nil)
+
+const watchStateColumns = `user_id, media_type, media_id, series_id, position_seconds, duration_seconds,
+	watched, watch_count, last_watched_at, updated_at`
+
+func scanWatchState(row rowScanner) (*models.WatchState, error) {
+	w := &models.WatchState{}
+	var lastWatched sql.NullTime
+	if err := row.Scan(&w.UserID, &w.MediaType, &w.MediaID, &w.SeriesID,
+		&w.PositionSeconds, &w.DurationSeconds, &w.Watched, &w.WatchCount,
+		&lastWatched, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if lastWatched.Valid {
+		t := lastWatched.Time
+		w.LastWatchedAt = &t
+	}
+	return w, nil
+}
+
+func (r *WatchStateRepository) Get(ctx context.Context, userID, mediaType, mediaID string) (*models.WatchState, error) {
+	w, err := scanWatchState(r.db.QueryRowContext(ctx, `
+		SELECT `+watchStateColumns+` FROM watch_state
+		WHERE user_id = ? AND media_type = ? AND media_id = ?
+	`, userID, mediaType, mediaID))
+	if err == sql.ErrNoRows {
+		return nil, ErrWatchStateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find watch state: %w", err)
+	}
+	return w, nil
+}
+
+func (r *WatchStateRepository) Upsert(ctx context.Context, state *models.WatchState) error {
+	if state == nil {
+		return fmt.Errorf("watch state cannot be nil")
+	}
+	state.UpdatedAt = time.Now().UTC()
+
+	var lastWatched interface{}
+	if state.LastWatchedAt != nil {
+		lastWatched = state.LastWatchedAt.UTC()
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO watch_state (`+watchStateColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, media_type, media_id) DO UPDATE SET
+			series_id = excluded.series_id,
+			position_seconds = excluded.position_seconds,
+			duration_seconds = excluded.duration_seconds,
+			watched = excluded.watched,
+			watch_count = excluded.watch_count,
+			last_watched_at = excluded.last_watched_at,
+			updated_at = excluded.updated_at
+	`, state.UserID, state.MediaType, state.MediaID, state.SeriesID,
+		state.PositionSeconds, state.DurationSeconds, state.Watched, state.WatchCount,
+		lastWatched, state.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save watch state: %w", err)
+	}
+	return nil
+}
+
+func (r *WatchStateRepository) ListBySeries(ctx context.Context, userID, seriesID string) ([]models.WatchState, error) {
+	return r.list(ctx, `
+		SELECT `+watchStateColumns+` FROM watch_state
+		WHERE user_id = ? AND series_id = ? AND media_type = 'episode'
+	`, userID, seriesID)
+}
+
+func (r *WatchStateRepository) ListRecent(ctx context.Context, userID string, limit int) ([]models.WatchState, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	return r.list(ctx, `
+		SELECT `+watchStateColumns+` FROM watch_state
+		WHERE user_id = ? AND (watched = 1 OR position_seconds > 0)
+		ORDER BY updated_at DESC
+		LIMIT ?
+	`, userID, limit)
+}
+
+func (r *WatchStateRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.WatchState, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watch state: %w", err)
+	}
+	defer rows.Close()
+
+	var states []models.WatchState
+	for rows.Next() {
+		w, err := scanWatchState(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan watch state: %w", err)
+		}
+		states = append(states, *w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating watch state: %w", err)
+	}
+	return states, nil
+}
+
+// movieWatchCondition turns the `watch_status` list filter into a WHERE
+// fragment over the movies table. An unknown status adds nothing.
+func movieWatchCondition(status, userID string) (string, []interface{}) {
+	const row = `SELECT 1 FROM watch_state w
+		WHERE w.user_id = ? AND w.media_type = 'movie' AND w.media_id = movies.id`
+	switch status {
+	case models.WatchStatusWatched:
+		return "EXISTS (" + row + " AND w.watched = 1)", []interface{}{userID}
+	case models.WatchStatusUnwatched:
+		return "NOT EXISTS (" + row + " AND w.watched = 1)", []interface{}{userID}
+	case models.WatchStatusInProgress:
+		return "EXISTS (" + row + " AND w.position_seconds > 0)", []interface{}{userID}
+	default:
+		return "", nil
+	}
+}
+
+// seriesWatchCondition is the series twin of movieWatchCondition. A show is
+// watched once every regular episode on disk is (specials do not count),
+// unwatched while none has been touched, and in progress in between.
+func seriesWatchCondition(status, userID string) (string, []interface{}) {
+	const onDisk = `e.series_id = series.id AND e.season_number > 0
+		AND e.file_path IS NOT NULL AND e.file_path != ''`
+	const total = `(SELECT COUNT(*) FROM episodes e WHERE ` + onDisk + `)`
+	const watched = `(SELECT COUNT(*) FROM episodes e
+		JOIN watch_state w ON w.media_type = 'episode' AND w.media_id = e.id
+			AND w.user_id = ? AND w.watched = 1
+		WHERE ` + onDisk + `)`
+	const touched = `EXISTS (SELECT 1 FROM watch_state w
+		WHERE w.user_id = ? AND w.series_id = series.id
+			AND (w.watched = 1 OR w.position_seconds > 0))`
+
+	switch status {
+	case models.WatchStatusWatched:
+		return total + " > 0 AND " + watched + " = " + total, []interface{}{userID}
+	case models.WatchStatusUnwatched:
+		return "NOT " + touched, []interface{}{userID}
+	case models.WatchStatusInProgress:
+		return touched + " AND " + watched + " < " + total, []interface{}{userID, userID}
+	default:
+		return "", nil
+	}
+}
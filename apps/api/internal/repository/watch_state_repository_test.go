@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/models"
+)
+
+func TestWatchStateRepository_UpsertAndGet(t *testing.T) {
+	repo := NewWatchStateRepository(setupUsersDB(t))
+	ctx := context.Background()
+
+	_, err := repo.Get(ctx, "", models.WatchMediaMovie, "m1")
+	assert.ErrorIs(t, err, ErrWatchStateNotFound)
+
+	state := &models.WatchState{MediaType: models.WatchMediaMovie, MediaID: "m1", PositionSeconds: 120, DurationSeconds: 6000}
+	require.NoError(t, repo.Upsert(ctx, state))
+	state.PositionSeconds = 240
+	require.NoError(t, repo.Upsert(ctx, state))
+
+	got, err := repo.Get(ctx, "", models.WatchMediaMovie, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, 240.0, got.PositionSeconds)
+	assert.Nil(t, got.LastWatchedAt)
+
+	_, err = repo.Get(ctx, "someone-else", models.WatchMediaMovie, "m1")
+	assert.ErrorIs(t, err, ErrWatchStateNotFound, "history is per user")
+
+	recent, err := repo.ListRecent(ctx, "", 10)
+	require.NoError(t, err)
+	assert.Len(t, recent, 1)
+}
+
+func TestMovieRepository_ListWatchStatusFilter(t *testing.T) {
+	db := setupUsersDB(t)
+	movies := NewMovieRepository(db)
+	states := NewWatchStateRepository(db)
+	ctx := context.Background()
+
+	for _, id := range []string{"seen", "half", "fresh"} {
+		require.NoError(t, movies.Create(ctx, &models.Movie{ID: id, Title: id}))
+	}
+	require.NoError(t, states.Upsert(ctx, &models.WatchState{UserID: "u1", MediaType: models.WatchMediaMovie, MediaID: "seen", Watched: true, WatchCount: 1}))
+	require.NoError(t, states.Upsert(ctx, &models.WatchState{UserID: "u1", MediaType: models.WatchMediaMovie, MediaID: "half", PositionSeconds: 300}))
+
+	list := func(status, user string) []string {
+		params := NewListParams()
+		params.Filters["watch_status"] = status
+		params.Filters["watch_user"] = user
+		got, _, err := movies.List(ctx, params)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, m := range got {
+			ids = append(ids, m.ID)
+		}
+		return ids
+	}
+
+	assert.ElementsMatch(t, []string{"seen"}, list(models.WatchStatusWatched, "u1"))
+	assert.ElementsMatch(t, []string{"half", "fresh"}, list(models.WatchStatusUnwatched, "u1"))
+	assert.ElementsMatch(t, []string{"half"}, list(models.WatchStatusInProgress, "u1"))
+	assert.ElementsMatch(t, []string{"seen", "half", "fresh"}, list(models.WatchStatusUnwatched, "u2"))
+}
+
+func TestSeriesRepository_ListWatchStatusFilter(t *testing.T) {
+	db := setupUsersDB(t)
+	series := NewSeriesRepository(db)
+	episodes := NewEpisodeRepository(db)
+	states := NewWatchStateRepository(db)
+	ctx := context.Background()
+
+	for _, id := range []string{"done", "started", "untouched"} {
+		require.NoError(t, series.Create(ctx, &models.Series{ID: id, Title: id}))
+		for ep := 1; ep <= 2; ep++ {
+			require.NoError(t, episodes.Create(ctx, &models.Episode{
+				ID: id + "-e" + string(rune('0'+ep)), SeriesID: id, SeasonNumber: 1, EpisodeNumber: ep,
+				FilePath: models.NewNullString("/tv/" + id),
+			}))
+		}
+	}
+	watched := func(epID, seriesID string) {
+		require.NoError(t, states.Upsert(ctx, &models.WatchState{
+			MediaType: models.WatchMediaEpisode, MediaID: epID, SeriesID: models.NewNullString(seriesID), Watched: true,
+		}))
+	}
+	watched("done-e1", "done")
+	watched("done-e2", "done")
+	watched("started-e1", "started")
+
+	list := func(status string) []string {
+		params := NewListParams()
+		params.Filters["watch_status"] = status
+		params.Filters["watch_user"] = ""
+		got, _, err := series.List(ctx, params)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, s := range got {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	assert.ElementsMatch(t, []string{"done"}, list(models.WatchStatusWatched))
+	assert.ElementsMatch(t, []string{"started"}, list(models.WatchStatusInProgress))
+	assert.ElementsMatch(t, []string{"untouched"}, list(models.WatchStatusUnwatched))
+}
@@ -13,9 +13,16 @@ type MetadataSearcher interface {
 	SearchByTaskPayload(ctx context.Context, payload json.RawMessage) error
 }
 
+// NotificationRedeliverer re-sends a queued notification delivery
+type NotificationRedeliverer interface {
+	// RedeliverNotification sends the delivery described by the retry item's payload
+	RedeliverNotification(ctx context.Context, payload json.RawMessage) error
+}
+
 // RetryExecutor implements TaskExecutor for retry operations
 type RetryExecutor struct {
 	metadataSearcher MetadataSearcher
+	notifications    NotificationRedeliverer
 	logger           *slog.Logger
 }
 
@@ -30,6 +37,11 @@ func NewRetryExecutor(metadataSearcher MetadataSearcher, logger *slog.Logger) *R
 	}
 }
 
+// SetNotificationRedeliverer enables retries of failed notification deliveries
+func (e *RetryExecutor) SetNotificationRedeliverer(r NotificationRedeliverer) {
+	e.notifications = r
+}
+
 // Execute implements TaskExecutor interface
 // It executes the retry task based on its type
 func (e *RetryExecutor) Execute(ctx context.Context, item *RetryItem) error {
@@ -49,6 +61,8 @@ func (e *RetryExecutor) Execute(ctx context.Context, item *RetryItem) error {
 		return e.executeParse(ctx, item)
 	case TaskTypeMetadataFetch:
 		return e.executeMetadataFetch(ctx, item)
+	case TaskTypeNotification:
+		return e.executeNotification(ctx, item)
 	default:
 		return fmt.Errorf("unknown task type: %s", item.TaskType)
 	}
@@ -74,6 +88,15 @@ func (e *RetryExecutor) executeMetadataFetch(ctx context.Context, item *RetryIte
 	return e.metadataSearcher.SearchByTaskPayload(ctx, item.Payload)
 }
 
+// executeNotification handles retry for notification deliveries
+func (e *RetryExecutor) executeNotification(ctx context.Context, item *RetryItem) error {
+	if e.notifications == nil {
+		return fmt.Errorf("notification redeliverer not configured")
+	}
+
+	return e.notifications.RedeliverNotification(ctx, item.Payload)
+}
+
 // Compile-time interface verification
 var _ TaskExecutor = (*RetryExecutor)(nil)
 
@@ -136,6 +136,35 @@ func TestRetryExecutor_Execute_SearchFails(t *testing.T) {
 	assert.Contains(t, err.Error(), "search failed")
 }
 
+// MockNotificationRedeliverer implements NotificationRedeliverer for testing
+type MockNotificationRedeliverer struct {
+	lastPayload json.RawMessage
+}
+
+func (m *MockNotificationRedeliverer) RedeliverNotification(ctx context.Context, payload json.RawMessage) error {
+	m.lastPayload = payload
+	return nil
+}
+
+func TestRetryExecutor_Execute_Notification(t *testing.T) {
+	executor := NewRetryExecutor(nil, nil)
+
+	item := &RetryItem{
+		ID:       "test-1",
+		TaskID:   "notification:t1:e1",
+		TaskType: TaskTypeNotification,
+		Payload:  []byte(`{"target_id":"t1"}`),
+	}
+
+	err := executor.Execute(context.Background(), item)
+	assert.ErrorContains(t, err, "not configured")
+
+	redeliverer := &MockNotificationRedeliverer{}
+	executor.SetNotificationRedeliverer(redeliverer)
+	require.NoError(t, executor.Execute(context.Background(), item))
+	assert.JSONEq(t, `{"target_id":"t1"}`, string(redeliverer.lastPayload))
+}
+
 func TestParsePayload(t *testing.T) {
 	tests := []struct {
 		name    string
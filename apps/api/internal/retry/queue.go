@@ -10,7 +10,7 @@ import (
 type RetryItem struct {
 	ID            string          `json:"id" db:"id"`
 	TaskID        string          `json:"task_id" db:"task_id"`
-	TaskType      string          `json:"task_type" db:"task_type"` // "parse", "metadata_fetch"
+	TaskType      string          `json:"task_type" db:"task_type"` // "parse", "metadata_fetch", "notification"
 	Payload       json.RawMessage `json:"payload" db:"payload"`    // Task-specific data
 	AttemptCount  int             `json:"attempt_count" db:"attempt_count"`
 	MaxAttempts   int             `json:"max_attempts" db:"max_attempts"`
@@ -133,6 +133,7 @@ func WrapNonRetryable(err error, code string) *RetryableError {
 const (
 	TaskTypeParse         = "parse"
 	TaskTypeMetadataFetch = "metadata_fetch"
+	TaskTypeNotification  = "notification"
 )
 
 // MaxRetryAttempts is the default maximum number of retry attempts
@@ -8,6 +8,10 @@
 // observability counter (non-capping) is tracked as
 // backlog-parse-path-ai-metering. Do NOT quietly attach a Budget here — a
 // capped scan would strand a library half-parsed with no screen to say why.
+//
+// The GLOBAL spend caps are different: they are an explicit admin setting
+// with a settings page that shows them reached, so they do apply here. Every
+// call still lands in the spend ledger, tagged parse or keyword.
 package services
 
 import (
@@ -63,9 +67,10 @@ type AIServiceInterface interface {
 
 // AIService orchestrates AI parsing with caching.
 type AIService struct {
-	provider ai.Provider
-	cache    *ai.Cache
-	cfg      *config.Config
+	provider   ai.Provider
+	cache      *ai.Cache
+	cfg        *config.Config
+	spendGuard ai.SpendGuard
 }
 
 // Compile-time interface verification.
@@ -91,6 +96,10 @@ func NewAIService(cfg *config.Config, db *sql.DB, governor *ai.Governor) (*AISer
 		ClaudeAPIKey: cfg.GetClaudeAPIKey(),
 		ClaudeModel:  cfg.GetClaudeModel(),
 		Governor:     governor,
+
+		OpenAICompatBaseURL: cfg.AIBaseURL,
+		OpenAICompatModel:   cfg.AIModel,
+		OpenAICompatAPIKey:  cfg.AIAPIKey,
 	}
 
 	provider, err := ai.NewProvider(factoryCfg)
@@ -116,6 +125,23 @@ func NewAIServiceWithProvider(provider ai.Provider, cache *ai.Cache) *AIService
 	}
 }
 
+// SetSpendGuard wires the global AI spend caps: once one is used up, cache
+// misses are refused instead of sent to the provider. Nil-safe.
+func (s *AIService) SetSpendGuard(g ai.SpendGuard) {
+	s.spendGuard = g
+}
+
+// parse sends req to the provider under the spend caps, tagging the call with
+// its ledger feature.
+func (s *AIService) parse(ctx context.Context, feature string, req *ai.ParseRequest) (*ai.ParseResponse, error) {
+	if s.spendGuard != nil {
+		if err := s.spendGuard.CheckSpend(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return s.provider.Parse(ai.WithUsageFeature(ctx, feature), req)
+}
+
 // ParseFilename parses a filename using AI with cache-first strategy.
 func (s *AIService) ParseFilename(ctx context.Context, filename string) (*ai.ParseResponse, error) {
 	if s.provider == nil {
@@ -143,7 +169,7 @@ func (s *AIService) ParseFilename(ctx context.Context, filename string) (*ai.Par
 		Filename: filename,
 	}
 
-	response, err := s.provider.Parse(ctx, req)
+	response, err := s.parse(ctx, ai.FeatureParse, req)
 	if err != nil {
 		return nil, err
 	}
@@ -209,7 +235,7 @@ func (s *AIService) ParseFansubFilename(ctx context.Context, filename string) (*
 		"timeout_seconds", FansubParsingTimeout.Seconds(),
 	)
 
-	response, err := s.provider.Parse(ctx, req)
+	response, err := s.parse(ctx, ai.FeatureParse, req)
 	if err != nil {
 		duration := time.Since(start)
 		slog.Error("AI fansub parsing failed",
@@ -317,7 +343,7 @@ func (s *AIService) GenerateKeywords(ctx context.Context, title, language string
 		"timeout_seconds", KeywordGenerationTimeout.Seconds(),
 	)
 
-	response, err := s.provider.Parse(ctx, req)
+	response, err := s.parse(ctx, ai.FeatureKeyword, req)
 	if err != nil {
 		duration := time.Since(start)
 		slog.Error("AI keyword generation failed",
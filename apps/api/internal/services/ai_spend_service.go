@@ -0,0 +1,240 @@
+// Package services — AISpendService.
+//
+// ai.Budget meters one run and forgets it when the run ends. This service is
+// the memory behind it: every metered call (LLM or transcription) lands in
+// the ai_usage ledger tagged with media, run, provider, model and feature,
+// and the ledger answers "what did this week cost?" through rollups.
+//
+// It also holds the global daily and monthly USD caps. CheckSpend is the one
+// gate: the ai package consults it before every governed call, and the
+// transcription service, the AI parse service and the generation batch ask it
+// before they start, so a run that would begin over the cap is refused rather
+// than started and paused. The caps are plain settings under "ai."; 0 (or
+// unset) means no cap.
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/vido/api/internal/ai"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+)
+
+// AI spend cap settings keys (USD; 0 or unset = no cap).
+const (
+	SettingAIDailyCapUSD   = "ai.daily_cap_usd"
+	SettingAIMonthlyCapUSD = "ai.monthly_cap_usd"
+)
+
+// AISpendCaps are the global USD caps; 0 means no cap.
+type AISpendCaps struct {
+	DailyUSD   float64 `json:"daily_usd"`
+	MonthlyUSD float64 `json:"monthly_usd"`
+}
+
+// Validate rejects negative caps.
+func (c AISpendCaps) Validate() error {
+	if c.DailyUSD < 0 {
+		return &models.ValidationError{Field: "daily_usd", Message: "daily_usd must be 0 (no cap) or more"}
+	}
+	if c.MonthlyUSD < 0 {
+		return &models.ValidationError{Field: "monthly_usd", Message: "monthly_usd must be 0 (no cap) or more"}
+	}
+	return nil
+}
+
+// AISpendStatus is the caps with what has been spent against them.
+type AISpendStatus struct {
+	Caps          AISpendCaps `json:"caps"`
+	Today         string      `json:"today"`
+	TodaySpentUSD float64     `json:"today_spent_usd"`
+	MonthSpentUSD float64     `json:"month_spent_usd"`
+	// CapReached is true when new AI work is being refused.
+	CapReached bool `json:"cap_reached"`
+}
+
+// SpendCapError reports which cap refused an AI call. It matches both
+// ai.ErrSpendCapReached and ai.ErrBudgetExceeded, so batches pause on it the
+// way they pause on a run budget ceiling.
+type SpendCapError struct {
+	Period   string // "daily" or "monthly"
+	CapUSD   float64
+	SpentUSD float64
+}
+
+func (e *SpendCapError) Error() string {
+	return fmt.Sprintf("%v: %s cap $%.2f used ($%.2f spent)", ai.ErrSpendCapReached, e.Period, e.CapUSD, e.SpentUSD)
+}
+
+func (e *SpendCapError) Unwrap() []error {
+	return []error{ai.ErrSpendCapReached, ai.ErrBudgetExceeded}
+}
+
+// AISpendServiceInterface defines the contract for the AI spend ledger and
+// caps.
+type AISpendServiceInterface interface {
+	GetStatus(ctx context.Context) (*AISpendStatus, error)
+	SaveCaps(ctx context.Context, caps AISpendCaps) error
+	// Rollup sums the ledger per groupBy (day, month, provider, model,
+	// feature, media, run) between two YYYY-MM-DD dates, inclusive.
+	Rollup(ctx context.Context, groupBy, fromDate, toDate string) ([]models.AIUsageRollup, error)
+	// CheckSpend returns a *SpendCapError once a cap is used up.
+	CheckSpend(ctx context.Context) error
+}
+
+// aiSpendSettings reads and writes the cap settings.
+type aiSpendSettings interface {
+	GetString(ctx context.Context, key string) (string, error)
+	SetString(ctx context.Context, key, value string) error
+}
+
+// AISpendService records AI usage and enforces the global caps.
+type AISpendService struct {
+	settings aiSpendSettings
+	ledger   repository.AIUsageRepositoryInterface
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// Compile-time interface verification.
+var (
+	_ AISpendServiceInterface = (*AISpendService)(nil)
+	_ ai.UsageLedger          = (*AISpendService)(nil)
+	_ ai.SpendGuard           = (*AISpendService)(nil)
+)
+
+// NewAISpendService creates a new AISpendService.
+func NewAISpendService(settings aiSpendSettings, ledger repository.AIUsageRepositoryInterface, logger *slog.Logger) *AISpendService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AISpendService{
+		settings: settings,
+		ledger:   ledger,
+		logger:   logger.With("service", "ai_spend"),
+		now:      time.Now,
+	}
+}
+
+// RecordUsage implements ai.UsageLedger. The call has already been paid for,
+// so a failed write is logged, never returned, and a cancelled ctx does not
+// stop the write.
+func (s *AISpendService) RecordUsage(ctx context.Context, entry ai.UsageEntry) {
+	usage := &models.AIUsage{
+		Provider:     entry.Provider,
+		Model:        entry.Model,
+		Feature:      entry.Feature,
+		MediaID:      entry.MediaID,
+		RunID:        entry.RunID,
+		InputTokens:  entry.InputTokens,
+		OutputTokens: entry.OutputTokens,
+		AudioSeconds: entry.AudioSeconds,
+		CostUSD:      entry.CostUSD,
+		RecordedAt:   s.now(),
+	}
+	if err := s.ledger.Record(context.WithoutCancel(ctx), usage); err != nil {
+		s.logger.Error("Failed to record AI usage", "provider", entry.Provider, "model", entry.Model,
+			"cost_usd", entry.CostUSD, "error", err)
+	}
+}
+
+// CheckSpend implements ai.SpendGuard. A ledger that cannot be read does not
+// block AI work: the caps are a brake, not a dependency.
+func (s *AISpendService) CheckSpend(ctx context.Context) error {
+	status, err := s.GetStatus(ctx)
+	if err != nil {
+		s.logger.Warn("AI spend caps could not be checked — allowing the call", "error", err)
+		return nil
+	}
+	return status.capError()
+}
+
+func (st *AISpendStatus) capError() error {
+	if st.Caps.DailyUSD > 0 && st.TodaySpentUSD >= st.Caps.DailyUSD {
+		return &SpendCapError{Period: "daily", CapUSD: st.Caps.DailyUSD, SpentUSD: st.TodaySpentUSD}
+	}
+	if st.Caps.MonthlyUSD > 0 && st.MonthSpentUSD >= st.Caps.MonthlyUSD {
+		return &SpendCapError{Period: "monthly", CapUSD: st.Caps.MonthlyUSD, SpentUSD: st.MonthSpentUSD}
+	}
+	return nil
+}
+
+// GetStatus returns the caps and today's and this month's spend.
+func (s *AISpendService) GetStatus(ctx context.Context) (*AISpendStatus, error) {
+	now := s.now().Local()
+	status := &AISpendStatus{
+		Caps:  s.loadCaps(ctx),
+		Today: now.Format(repository.AIUsageDateLayout),
+	}
+
+	var err error
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if status.MonthSpentUSD, err = s.ledger.SpendSince(ctx, monthStart.Format(repository.AIUsageDateLayout)); err != nil {
+		return nil, err
+	}
+	if status.TodaySpentUSD, err = s.ledger.SpendSince(ctx, status.Today); err != nil {
+		return nil, err
+	}
+	status.CapReached = status.capError() != nil
+	return status, nil
+}
+
+// SaveCaps stores the caps; 0 is stored as is and means no cap.
+func (s *AISpendService) SaveCaps(ctx context.Context, caps AISpendCaps) error {
+	if err := caps.Validate(); err != nil {
+		return err
+	}
+	for key, value := range map[string]float64{
+		SettingAIDailyCapUSD:   caps.DailyUSD,
+		SettingAIMonthlyCapUSD: caps.MonthlyUSD,
+	} {
+		if err := s.settings.SetString(ctx, key, strconv.FormatFloat(value, 'f', -1, 64)); err != nil {
+			return fmt.Errorf("save %s: %w", key, err)
+		}
+	}
+	s.logger.Info("AI spend caps saved", "daily_usd", caps.DailyUSD, "monthly_usd", caps.MonthlyUSD)
+	return nil
+}
+
+// Rollup sums the ledger per groupBy between two dates.
+func (s *AISpendService) Rollup(ctx context.Context, groupBy, fromDate, toDate string) ([]models.AIUsageRollup, error) {
+	if !repository.IsAIUsageGrouping(groupBy) {
+		return nil, &models.ValidationError{Field: "group_by",
+			Message: "group_by must be one of day, month, provider, model, feature, media, run"}
+	}
+	for field, date := range map[string]string{"from": fromDate, "to": toDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(repository.AIUsageDateLayout, date); err != nil {
+			return nil, &models.ValidationError{Field: field, Message: field + " must be a YYYY-MM-DD date"}
+		}
+	}
+	return s.ledger.Rollup(ctx, groupBy, fromDate, toDate)
+}
+
+// loadCaps reads the cap settings; a missing or unreadable one is no cap.
+func (s *AISpendService) loadCaps(ctx context.Context) AISpendCaps {
+	return AISpendCaps{
+		DailyUSD:   s.capSetting(ctx, SettingAIDailyCapUSD),
+		MonthlyUSD: s.capSetting(ctx, SettingAIMonthlyCapUSD),
+	}
+}
+
+func (s *AISpendService) capSetting(ctx context.Context, key string) float64 {
+	value, err := s.settings.GetString(ctx, key)
+	if err != nil || value == "" {
+		return 0
+	}
+	usd, err := strconv.ParseFloat(value, 64)
+	if err != nil || usd < 0 {
+		s.logger.Warn("Ignoring invalid AI spend cap", "key", key, "value", value)
+		return 0
+	}
+	return usd
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/ai"
+	"github.com/vido/api/internal/database/migrations"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+)
+
+func setupAISpendService(t *testing.T) (*AISpendService, *fakeDVRSettingsRepo) {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	runner, err := migrations.NewRunner(db)
+	require.NoError(t, err)
+	require.NoError(t, runner.RegisterAll(migrations.GetAll()))
+	require.NoError(t, runner.Up(context.Background()))
+
+	settings := newFakeDVRSettingsRepo()
+	svc := NewAISpendService(settings, repository.NewAIUsageRepository(db), nil)
+	svc.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local) }
+	return svc, settings
+}
+
+func TestAISpendService_Caps(t *testing.T) {
+	svc, _ := setupAISpendService(t)
+	ctx := context.Background()
+
+	// Earlier this month, then today.
+	svc.now = func() time.Time { return time.Date(2026, 10, 2, 9, 0, 0, 0, time.Local) }
+	svc.RecordUsage(ctx, ai.UsageEntry{Provider: "claude", Model: "claude-haiku-4-5", Feature: ai.FeatureTranslate, CostUSD: 4})
+	svc.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local) }
+	svc.RecordUsage(ctx, ai.UsageEntry{Provider: "whisper", Model: "whisper-1", Feature: ai.FeatureTranscribe, CostUSD: 1})
+
+	assert.NoError(t, svc.CheckSpend(ctx), "no caps, no limit")
+
+	require.NoError(t, svc.SaveCaps(ctx, AISpendCaps{DailyUSD: 2, MonthlyUSD: 10}))
+	status, err := svc.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-16", status.Today)
+	assert.InDelta(t, 1, status.TodaySpentUSD, 1e-9)
+	assert.InDelta(t, 5, status.MonthSpentUSD, 1e-9)
+	assert.False(t, status.CapReached)
+
+	svc.RecordUsage(ctx, ai.UsageEntry{Provider: "claude", Model: "claude-haiku-4-5", CostUSD: 1})
+	err = svc.CheckSpend(ctx)
+	var capErr *SpendCapError
+	require.ErrorAs(t, err, &capErr)
+	assert.Equal(t, "daily", capErr.Period)
+	assert.ErrorIs(t, err, ai.ErrSpendCapReached)
+	assert.ErrorIs(t, err, ai.ErrBudgetExceeded, "batches pause on it like on their own ceiling")
+
+	require.NoError(t, svc.SaveCaps(ctx, AISpendCaps{MonthlyUSD: 6}))
+	err = svc.CheckSpend(ctx)
+	require.ErrorAs(t, err, &capErr)
+	assert.Equal(t, "monthly", capErr.Period)
+
+	require.NoError(t, svc.SaveCaps(ctx, AISpendCaps{}))
+	assert.NoError(t, svc.CheckSpend(ctx), "0 removes the caps")
+
+	var validationErr *models.ValidationError
+	assert.ErrorAs(t, svc.SaveCaps(ctx, AISpendCaps{DailyUSD: -1}), &validationErr)
+}
+
+func TestAISpendService_Rollup(t *testing.T) {
+	svc, _ := setupAISpendService(t)
+	ctx := context.Background()
+
+	svc.RecordUsage(ctx, ai.UsageEntry{Provider: "claude", Model: "claude-haiku-4-5", Feature: ai.FeatureParse, CostUSD: 0.01})
+	svc.RecordUsage(ctx, ai.UsageEntry{Provider: "claude", Model: "claude-haiku-4-5", Feature: ai.FeatureTranslate, MediaID: "m1", RunID: "r1", CostUSD: 0.5})
+	svc.RecordUsage(ctx, ai.UsageEntry{Provider: "claude", Model: "claude-haiku-4-5", Feature: ai.FeatureTranslate, MediaID: "m1", RunID: "r1", CostUSD: 0.25})
+
+	rollup, err := svc.Rollup(ctx, "feature", "2026-10-01", "2026-10-31")
+	require.NoError(t, err)
+	require.Len(t, rollup, 2)
+	assert.Equal(t, models.AIUsageRollup{Key: ai.FeatureTranslate, Calls: 2, CostUSD: 0.75}, rollup[0])
+
+	var validationErr *models.ValidationError
+	_, err = svc.Rollup(ctx, "colour", "", "")
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = svc.Rollup(ctx, "day", "16/10/2026", "")
+	assert.ErrorAs(t, err, &validationErr)
+}
@@ -0,0 +1,468 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+)
+
+const (
+	// DefaultSessionTTL is how long a login lasts when no TTL is configured.
+	DefaultSessionTTL = 30 * 24 * time.Hour
+
+	minPasswordLength = 8
+	// bcrypt silently ignores everything past 72 bytes; rejecting longer
+	// passwords beats letting two that share a prefix both work.
+	maxPasswordBytes  = 72
+	maxUsernameLength = 64
+)
+
+var (
+	// ErrInvalidCredentials covers both an unknown username and a wrong
+	// password, so a login form cannot be used to enumerate accounts.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrSessionInvalid is returned for a missing, expired or revoked session.
+	ErrSessionInvalid = errors.New("session invalid or expired")
+	// ErrAuthAlreadyBootstrapped is returned when the first-admin bootstrap is
+	// attempted on an install that already has accounts.
+	ErrAuthAlreadyBootstrapped = errors.New("an admin account already exists")
+	// ErrLastAdmin guards against demoting or deleting the only admin, which
+	// would lock every destructive route for good.
+	ErrLastAdmin = errors.New("cannot remove the last admin")
+	// ErrCannotDeleteSelf keeps an admin from deleting the account they are
+	// signed in with.
+	ErrCannotDeleteSelf = errors.New("cannot delete your own account")
+)
+
+// AuthSession is a freshly issued login. Token is only ever returned here —
+// the server keeps its hash.
+type AuthSession struct {
+	Token     string       `json:"token"`
+	User      *models.User `json:"user"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// CreateUserRequest is the payload for an admin creating an account.
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// UpdateUserRequest changes an account. Nil fields are left unchanged.
+type UpdateUserRequest struct {
+	Role     *string `json:"role,omitempty"`
+	Password *string `json:"password,omitempty"`
+}
+
+// AuthService owns accounts, password hashing and login sessions.
+//
+// An install with no accounts is open, exactly as before this service
+// existed, so upgrading never locks anyone out. Creating the first admin
+// (Bootstrap, or VIDO_ADMIN_USERNAME/PASSWORD at startup) switches the API to
+// authenticated for good: the last admin cannot be deleted.
+type AuthService struct {
+	users    repository.UserRepositoryInterface
+	sessions repository.SessionRepositoryInterface
+	ttl      time.Duration
+	now      func() time.Time
+	logger   *slog.Logger
+
+	// hasUsers caches "at least one account exists". It only ever flips to
+	// true, so once set the per-request count query is skipped.
+	hasUsers atomic.Bool
+}
+
+// NewAuthService creates an AuthService. ttl <= 0 selects DefaultSessionTTL.
+func NewAuthService(users repository.UserRepositoryInterface, sessions repository.SessionRepositoryInterface, ttl time.Duration, logger *slog.Logger) *AuthService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	return &AuthService{
+		users:    users,
+		sessions: sessions,
+		ttl:      ttl,
+		now:      time.Now,
+		logger:   logger.With("service", "auth"),
+	}
+}
+
+// SessionTTL is how long an issued session stays valid.
+func (s *AuthService) SessionTTL() time.Duration {
+	return s.ttl
+}
+
+// AuthRequired reports whether requests must carry a session, i.e. whether
+// any account exists yet.
+func (s *AuthService) AuthRequired(ctx context.Context) (bool, error) {
+	if s.hasUsers.Load() {
+		return true, nil
+	}
+	count, err := s.users.Count(ctx)
+	if err != nil {
+		return false, fmt.Errorf("check accounts: %w", err)
+	}
+	if count > 0 {
+		s.hasUsers.Store(true)
+	}
+	return count > 0, nil
+}
+
+// Bootstrap creates the first admin on an install with no accounts and signs
+// them in. The emptiness check and the insert are one statement
+// (CreateFirst): checking first and inserting after would let two requests
+// racing an open install both come away with an admin account.
+func (s *AuthService) Bootstrap(ctx context.Context, username, password, userAgent string) (*AuthSession, error) {
+	required, err := s.AuthRequired(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		return nil, ErrAuthAlreadyBootstrapped
+	}
+	if _, err := s.createFirstAdmin(ctx, username, password); err != nil {
+		return nil, err
+	}
+	return s.Login(ctx, username, password, userAgent)
+}
+
+// EnsureAdmin seeds an admin from configuration when no account exists yet,
+// so a headless deployment is locked from its first boot. It is a no-op once
+// any account exists — the configured password never overwrites one changed
+// through the API.
+func (s *AuthService) EnsureAdmin(ctx context.Context, username, password string) error {
+	if strings.TrimSpace(username) == "" || password == "" {
+		return nil
+	}
+	required, err := s.AuthRequired(ctx)
+	if err != nil || required {
+		return err
+	}
+	if _, err := s.createFirstAdmin(ctx, username, password); err != nil {
+		if errors.Is(err, ErrAuthAlreadyBootstrapped) {
+			return nil
+		}
+		return fmt.Errorf("seed admin account: %w", err)
+	}
+	s.logger.Info("Admin account created from configuration", "username", username)
+	return nil
+}
+
+// createFirstAdmin validates and inserts the first account, which is always an
+// admin. ErrAuthAlreadyBootstrapped means another account got there first.
+func (s *AuthService) createFirstAdmin(ctx context.Context, username, password string) (*models.User, error) {
+	username = strings.TrimSpace(username)
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user := &models.User{Username: username, PasswordHash: hash, Role: models.UserRoleAdmin}
+	if err := s.users.CreateFirst(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrUsersExist) {
+			s.hasUsers.Store(true)
+			return nil, ErrAuthAlreadyBootstrapped
+		}
+		return nil, err
+	}
+	s.hasUsers.Store(true)
+	s.logger.Info("User account created", "user_id", user.ID, "username", user.Username, "role", user.Role)
+	return user, nil
+}
+
+// dummyHash is compared against when the username does not exist, so an
+// unknown account costs the same bcrypt time as a wrong password.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("vido-timing-equaliser"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Login checks credentials and issues a session.
+func (s *AuthService) Login(ctx context.Context, username, password, userAgent string) (*AuthSession, error) {
+	user, err := s.users.GetByUsername(ctx, strings.TrimSpace(username))
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	now := s.now()
+	token, err := newSessionToken()
+	if err != nil {
+		return nil, err
+	}
+	session := &models.Session{
+		ID:        hashSessionToken(token),
+		UserID:    user.ID,
+		UserAgent: truncateRunes(userAgent, 255),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := s.users.TouchLastLogin(ctx, user.ID, now); err != nil {
+		s.logger.Warn("failed to record last login", "user_id", user.ID, "error", err)
+	} else {
+		user.LastLoginAt = &now
+	}
+	// Logins are rare enough that sweeping here keeps the table bounded
+	// without a scheduler of its own.
+	if _, err := s.sessions.DeleteExpired(ctx, now); err != nil {
+		s.logger.Warn("failed to sweep expired sessions", "error", err)
+	}
+
+	return &AuthSession{Token: token, User: user, ExpiresAt: session.ExpiresAt}, nil
+}
+
+// Logout revokes the session behind token. An unknown token is not an error.
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+	return s.sessions.Delete(ctx, hashSessionToken(token))
+}
+
+// ResolveSession returns the account behind a session token. The user row is
+// re-read on every call, so a role change or deletion applies immediately.
+func (s *AuthService) ResolveSession(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, ErrSessionInvalid
+	}
+	session, err := s.sessions.Get(ctx, hashSessionToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil, ErrSessionInvalid
+		}
+		return nil, err
+	}
+	if !s.now().Before(session.ExpiresAt) {
+		_ = s.sessions.Delete(ctx, session.ID)
+		return nil, ErrSessionInvalid
+	}
+	user, err := s.users.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrSessionInvalid
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// ListUsers returns every account.
+func (s *AuthService) ListUsers(ctx context.Context) ([]models.User, error) {
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if users == nil {
+		users = []models.User{}
+	}
+	return users, nil
+}
+
+// CreateUser adds an account. An empty role creates a regular user.
+func (s *AuthService) CreateUser(ctx context.Context, req CreateUserRequest) (*models.User, error) {
+	username := strings.TrimSpace(req.Username)
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	role := req.Role
+	if role == "" {
+		role = models.UserRoleUser
+	}
+	if !models.IsValidUserRole(role) {
+		return nil, &models.ValidationError{Field: "role", Message: "role must be admin or user"}
+	}
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{Username: username, PasswordHash: hash, Role: role}
+	if err := s.users.Create(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrUsernameTaken) {
+			return nil, &models.ValidationError{Field: "username", Message: "username is already taken"}
+		}
+		return nil, err
+	}
+	s.hasUsers.Store(true)
+	s.logger.Info("User account created", "user_id", user.ID, "username", user.Username, "role", user.Role)
+	return user, nil
+}
+
+// UpdateUser changes an account's role and/or password. A new password
+// revokes every session of that account.
+func (s *AuthService) UpdateUser(ctx context.Context, id string, req UpdateUserRequest) (*models.User, error) {
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Role != nil && *req.Role != user.Role {
+		if !models.IsValidUserRole(*req.Role) {
+			return nil, &models.ValidationError{Field: "role", Message: "role must be admin or user"}
+		}
+		if user.Role == models.UserRoleAdmin {
+			if err := s.ensureAnotherAdmin(ctx); err != nil {
+				return nil, err
+			}
+		}
+		user.Role = *req.Role
+	}
+	passwordChanged := false
+	if req.Password != nil {
+		hash, err := hashPassword(*req.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = hash
+		passwordChanged = true
+	}
+
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if passwordChanged {
+		if err := s.sessions.DeleteByUser(ctx, user.ID, ""); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// ChangePassword lets a signed-in user replace their own password. Every
+// other session of the account is revoked; the one making the change stays.
+func (s *AuthService) ChangePassword(ctx context.Context, userID, currentToken, currentPassword, newPassword string) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)) != nil {
+		return ErrInvalidCredentials
+	}
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = hash
+	if err := s.users.Update(ctx, user); err != nil {
+		return err
+	}
+	return s.sessions.DeleteByUser(ctx, user.ID, hashSessionToken(currentToken))
+}
+
+// DeleteUser removes an account and its sessions.
+func (s *AuthService) DeleteUser(ctx context.Context, id, actingUserID string) error {
+	if id == actingUserID {
+		return ErrCannotDeleteSelf
+	}
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.Role == models.UserRoleAdmin {
+		if err := s.ensureAnotherAdmin(ctx); err != nil {
+			return err
+		}
+	}
+	// The FK cascades in production; revoking explicitly keeps this correct
+	// on a connection without foreign_keys enabled.
+	if err := s.sessions.DeleteByUser(ctx, id, ""); err != nil {
+		return err
+	}
+	if err := s.users.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("User account deleted", "user_id", id, "username", user.Username)
+	return nil
+}
+
+func (s *AuthService) ensureAnotherAdmin(ctx context.Context) error {
+	admins, err := s.users.CountByRole(ctx, models.UserRoleAdmin)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+func validateUsername(username string) error {
+	if username == "" {
+		return &models.ValidationError{Field: "username", Message: "username is required"}
+	}
+	if len([]rune(username)) > maxUsernameLength {
+		return &models.ValidationError{Field: "username", Message: fmt.Sprintf("username must be %d characters or fewer", maxUsernameLength)}
+	}
+	for _, r := range username {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return &models.ValidationError{Field: "username", Message: "username may not contain spaces"}
+		}
+	}
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	if len([]rune(password)) < minPasswordLength {
+		return "", &models.ValidationError{Field: "password", Message: fmt.Sprintf("password must be at least %d characters", minPasswordLength)}
+	}
+	if len(password) > maxPasswordBytes {
+		return "", &models.ValidationError{Field: "password", Message: fmt.Sprintf("password must be at most %d bytes", maxPasswordBytes)}
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// newSessionToken returns 256 bits of randomness, URL-safe so it survives a
+// cookie or an Authorization header untouched.
+func newSessionToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
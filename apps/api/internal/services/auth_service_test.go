@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/database/migrations"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	_ "modernc.org/sqlite"
+)
+
+func setupAuthService(t *testing.T) *AuthService {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	// Every :memory: connection is its own database; the concurrent
+	// bootstrap test must share one.
+	db.SetMaxOpenConns(1)
+
+	runner, err := migrations.NewRunner(db)
+	require.NoError(t, err)
+	require.NoError(t, runner.RegisterAll(migrations.GetAll()))
+	require.NoError(t, runner.Up(context.Background()))
+
+	return NewAuthService(repository.NewUserRepository(db), repository.NewSessionRepository(db), time.Hour, nil)
+}
+
+func TestAuthService_OpenUntilBootstrapped(t *testing.T) {
+	svc := setupAuthService(t)
+	ctx := context.Background()
+
+	required, err := svc.AuthRequired(ctx)
+	require.NoError(t, err)
+	assert.False(t, required, "an install without accounts must stay open")
+
+	session, err := svc.Bootstrap(ctx, "admin", "correct-horse", "test")
+	require.NoError(t, err)
+	assert.NotEmpty(t, session.Token)
+	assert.Equal(t, models.UserRoleAdmin, session.User.Role)
+
+	required, err = svc.AuthRequired(ctx)
+	require.NoError(t, err)
+	assert.True(t, required)
+
+	_, err = svc.Bootstrap(ctx, "second", "correct-horse", "test")
+	assert.ErrorIs(t, err, ErrAuthAlreadyBootstrapped)
+}
+
+// TestAuthService_ConcurrentBootstrapCreatesOneAdmin — two requests racing an
+// open install must not both come away with an admin account.
+func TestAuthService_ConcurrentBootstrapCreatesOneAdmin(t *testing.T) {
+	svc := setupAuthService(t)
+	ctx := context.Background()
+
+	const racers = 8
+	errs := make(chan error, racers)
+	var wg sync.WaitGroup
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := svc.Bootstrap(ctx, fmt.Sprintf("admin%d", i), "correct-horse", "test")
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrAuthAlreadyBootstrapped)
+	}
+	assert.Equal(t, 1, succeeded)
+	users, err := svc.ListUsers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+}
+
+func TestAuthService_LoginAndResolve(t *testing.T) {
+	svc := setupAuthService(t)
+	ctx := context.Background()
+	_, err := svc.CreateUser(ctx, CreateUserRequest{Username: "alice", Password: "correct-horse"})
+	require.NoError(t, err)
+
+	t.Run("wrong password and unknown user look the same", func(t *testing.T) {
+		_, err := svc.Login(ctx, "alice", "wrong-password", "")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		_, err = svc.Login(ctx, "nobody", "correct-horse", "")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	session, err := svc.Login(ctx, "ALICE", "correct-horse", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.UserRoleUser, session.User.Role)
+
+	t.Run("token resolves to its user", func(t *testing.T) {
+		user, err := svc.ResolveSession(ctx, session.Token)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", user.Username)
+	})
+
+	t.Run("expired session is rejected", func(t *testing.T) {
+		svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		defer func() { svc.now = time.Now }()
+		_, err := svc.ResolveSession(ctx, session.Token)
+		assert.ErrorIs(t, err, ErrSessionInvalid)
+	})
+
+	t.Run("logout revokes the token", func(t *testing.T) {
+		fresh, err := svc.Login(ctx, "alice", "correct-horse", "")
+		require.NoError(t, err)
+		require.NoError(t, svc.Logout(ctx, fresh.Token))
+		_, err = svc.ResolveSession(ctx, fresh.Token)
+		assert.ErrorIs(t, err, ErrSessionInvalid)
+	})
+}
+
+func TestAuthService_ChangePasswordKeepsCurrentSession(t *testing.T) {
+	svc := setupAuthService(t)
+	ctx := context.Background()
+	user, err := svc.CreateUser(ctx, CreateUserRequest{Username: "alice", Password: "correct-horse"})
+	require.NoError(t, err)
+
+	current, err := svc.Login(ctx, "alice", "correct-horse", "")
+	require.NoError(t, err)
+	other, err := svc.Login(ctx, "alice", "correct-horse", "")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.ChangePassword(ctx, user.ID, current.Token, "wrong-password", "battery-staple"), ErrInvalidCredentials)
+	require.NoError(t, svc.ChangePassword(ctx, user.ID, current.Token, "correct-horse", "battery-staple"))
+
+	_, err = svc.ResolveSession(ctx, current.Token)
+	assert.NoError(t, err, "the session that changed the password stays signed in")
+	_, err = svc.ResolveSession(ctx, other.Token)
+	assert.ErrorIs(t, err, ErrSessionInvalid)
+	_, err = svc.Login(ctx, "alice", "battery-staple", "")
+	assert.NoError(t, err)
+}
+
+func TestAuthService_LastAdminIsProtected(t *testing.T) {
+	svc := setupAuthService(t)
+	ctx := context.Background()
+	admin, err := svc.CreateUser(ctx, CreateUserRequest{Username: "admin", Password: "correct-horse", Role: models.UserRoleAdmin})
+	require.NoError(t, err)
+	user, err := svc.CreateUser(ctx, CreateUserRequest{Username: "alice", Password: "correct-horse"})
+	require.NoError(t, err)
+
+	demote := models.UserRoleUser
+	_, err = svc.UpdateUser(ctx, admin.ID, UpdateUserRequest{Role: &demote})
+	assert.ErrorIs(t, err, ErrLastAdmin)
+	assert.ErrorIs(t, svc.DeleteUser(ctx, admin.ID, user.ID), ErrLastAdmin)
+	assert.ErrorIs(t, svc.DeleteUser(ctx, admin.ID, admin.ID), ErrCannotDeleteSelf)
+
+	promote := models.UserRoleAdmin
+	_, err = svc.UpdateUser(ctx, user.ID, UpdateUserRequest{Role: &promote})
+	require.NoError(t, err)
+	assert.NoError(t, svc.DeleteUser(ctx, admin.ID, user.ID), "a second admin makes the first removable")
+}
+
+func TestAuthService_CreateUserValidation(t *testing.T) {
+	svc := setupAuthService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		req   CreateUserRequest
+		field string
+	}{
+		{"empty username", CreateUserRequest{Username: "  ", Password: "correct-horse"}, "username"},
+		{"username with spaces", CreateUserRequest{Username: "a b", Password: "correct-horse"}, "username"},
+		{"short password", CreateUserRequest{Username: "alice", Password: "short"}, "password"},
+		{"unknown role", CreateUserRequest{Username: "alice", Password: "correct-horse", Role: "owner"}, "role"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateUser(ctx, tt.req)
+			var validationErr *models.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+
+	_, err := svc.CreateUser(ctx, CreateUserRequest{Username: "alice", Password: "correct-horse"})
+	require.NoError(t, err)
+	_, err = svc.CreateUser(ctx, CreateUserRequest{Username: "Alice", Password: "correct-horse"})
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr, "a taken username is a validation failure, not a 500")
+}
+
+func TestAuthService_EnsureAdminOnlySeedsEmptyInstall(t *testing.T) {
+	svc := setupAuthService(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.EnsureAdmin(ctx, "", ""), "no configured admin is a no-op")
+	required, err := svc.AuthRequired(ctx)
+	require.NoError(t, err)
+	assert.False(t, required)
+
+	require.NoError(t, svc.EnsureAdmin(ctx, "admin", "correct-horse"))
+	require.NoError(t, svc.EnsureAdmin(ctx, "admin", "a-different-password"))
+
+	_, err = svc.Login(ctx, "admin", "correct-horse", "")
+	assert.NoError(t, err, "a later boot must not reset the password")
+}
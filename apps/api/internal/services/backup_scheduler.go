@@ -55,6 +55,7 @@ type BackupScheduler struct {
 	stopCh        chan struct{}
 	stopped       bool
 	lastRunDate   string // "2006-01-02" format to prevent duplicate runs
+	notifier      EventNotifier
 }
 
 // Compile-time interface verification
@@ -74,6 +75,12 @@ func NewBackupScheduler(
 	}
 }
 
+// SetNotifier reports failed scheduled backups to the outbound notification
+// targets. Manual backups are left out: the user is looking at the result.
+func (s *BackupScheduler) SetNotifier(n EventNotifier) {
+	s.notifier = n
+}
+
 // Start begins the scheduler loop that checks schedule every minute
 func (s *BackupScheduler) Start(ctx context.Context) {
 	slog.Info("Backup scheduler started")
@@ -199,6 +206,17 @@ func (s *BackupScheduler) checkAndRun(ctx context.Context, now time.Time) {
 	backup, err := s.backupService.CreateBackup(ctx)
 	if err != nil {
 		slog.Error("Scheduled backup failed", "error", err)
+		if s.notifier != nil {
+			s.notifier.Notify(ctx, models.NotificationEvent{
+				Type:    models.NotificationEventBackupFailed,
+				Title:   "排程備份失敗",
+				Message: err.Error(),
+				Data: map[string]interface{}{
+					"frequency": schedule.Frequency,
+					"error":     err.Error(),
+				},
+			})
+		}
 		return
 	}
 
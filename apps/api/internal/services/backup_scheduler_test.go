@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/vido/api/internal/models"
 )
 
@@ -532,6 +533,25 @@ func TestBackupScheduler_CheckAndRun(t *testing.T) {
 		// Retention should NOT be called since backup failed
 		backupSvc.AssertNotCalled(t, "DeleteBackup", mock.Anything, mock.Anything)
 	})
+
+	t.Run("notifies when a scheduled backup fails", func(t *testing.T) {
+		backupSvc := new(MockBackupSvc)
+		settingsRepo := new(MockSchedulerSettingsRepo)
+		svc := NewBackupScheduler(backupSvc, settingsRepo, nil)
+		notifier := &recordingEventNotifier{}
+		svc.SetNotifier(notifier)
+
+		scheduleJSON, _ := json.Marshal(BackupSchedule{Enabled: true, Frequency: "daily", Hour: 3})
+		settingsRepo.On("GetString", ctx, settingsKeyBackupSchedule).Return(string(scheduleJSON), nil)
+		backupSvc.On("CreateBackup", ctx).Return((*models.Backup)(nil), fmt.Errorf("disk full"))
+
+		svc.checkAndRun(ctx, time.Date(2026, 3, 20, 3, 0, 0, 0, time.UTC))
+
+		events := notifier.all()
+		require.Len(t, events, 1)
+		assert.Equal(t, models.NotificationEventBackupFailed, events[0].Type)
+		assert.Equal(t, "disk full", events[0].Message)
+	})
 }
 
 func TestBackupScheduler_ApplyRetentionPolicy_Errors(t *testing.T) {
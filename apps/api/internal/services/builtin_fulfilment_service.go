@@ -0,0 +1,700 @@
+// Package services — BuiltinFulfilmentService.
+//
+// Without Radarr/Sonarr a request could only be fulfilled by someone adding
+// a torrent to qBittorrent by hand. This service is the built-in path: on a
+// tick (and on demand) it reads every enabled indexer's recent releases,
+// searches the searchable ones for each waiting request, parses the release
+// titles with internal/parser, and hands the best match to qBittorrent.
+//
+// A row is the built-in path's to serve when it is pending with no external
+// id and either is claimed as builtin (quality upgrades) or its *arr is not
+// configured. A grab moves it to downloading with fulfilment_source=builtin
+// and the torrent's info-hash as external_id; from there the request poller
+// follows the torrent in qBittorrent.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/vido/api/internal/indexer"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/parser"
+	"github.com/vido/api/internal/qbittorrent"
+	"github.com/vido/api/internal/secrets"
+	"github.com/vido/api/internal/tmdb"
+)
+
+// defaultBuiltinFulfilmentInterval is the grab cadence — indexer RSS feeds
+// refresh on roughly this scale, and searches are not free for them.
+const defaultBuiltinFulfilmentInterval = 15 * time.Minute
+
+// SettingKeyBuiltinQualityProfileID names the quality profile built-in grabs
+// must satisfy. Unset means any quality, best first.
+const SettingKeyBuiltinQualityProfileID = "builtin.quality_profile_id"
+
+// builtinTorrentCategory is the qBittorrent category built-in grabs land in.
+const builtinTorrentCategory = "vido"
+
+// RequestTorrentTag is the qBittorrent tag a built-in grab carries, naming
+// the request it fulfils. The request poller finds the torrent by it, so a
+// re-check or a hash qBittorrent reports differently (a v2 torrent) does not
+// lose track of the grab.
+func RequestTorrentTag(requestID string) string {
+	return "vido-request:" + requestID
+}
+
+// ErrBuiltinFulfilmentRunning is returned when a pass is requested while one
+// is already in progress.
+var ErrBuiltinFulfilmentRunning = errors.New("built-in fulfilment already running")
+
+// seasonPackPattern reads "Show.Name.S02.1080p…" — a whole-season release,
+// which the TV parser (episode-shaped) does not cover.
+var seasonPackPattern = regexp.MustCompile(`(?i)^(.+?)[ ._\-\[(]+S(?:eason[ ._]?)?(\d{1,2})(?:[ ._\-\])]|$)`)
+
+// BuiltinFulfilmentServiceInterface is the on-demand face of the grabber.
+type BuiltinFulfilmentServiceInterface interface {
+	// RunOnce runs one pass over the waiting requests.
+	RunOnce(ctx context.Context) (*BuiltinFulfilmentRun, error)
+}
+
+// BuiltinIndexerReader lists the indexers a pass reads.
+type BuiltinIndexerReader interface {
+	ListEnabled(ctx context.Context) ([]models.Indexer, error)
+}
+
+// BuiltinRequestStore is the slice of the request repository a pass uses.
+type BuiltinRequestStore interface {
+	ListActive(ctx context.Context) ([]models.Request, error)
+	UpdateFulfilment(ctx context.Context, id string, status string, fulfilmentSource, externalID, errorMessage models.NullString) (time.Time, error)
+}
+
+// ReleaseSource queries indexers. *indexer.Client satisfies it.
+type ReleaseSource interface {
+	Search(ctx context.Context, cfg indexer.Config, q indexer.SearchQuery) ([]indexer.Release, error)
+	Recent(ctx context.Context, cfg indexer.Config) ([]indexer.Release, error)
+	Download(ctx context.Context, link string) (*indexer.Download, error)
+}
+
+// TorrentAdder hands a grab to the download client. *DownloadService
+// satisfies it.
+type TorrentAdder interface {
+	AddTorrent(ctx context.Context, opts qbittorrent.AddTorrentOptions) error
+}
+
+// BuiltinTitleSource resolves the names a release may carry.
+type BuiltinTitleSource interface {
+	GetMovieDetails(ctx context.Context, movieID int) (*tmdb.MovieDetails, error)
+	GetTVShowDetails(ctx context.Context, tvID int) (*tmdb.TVShowDetails, error)
+}
+
+// dvrConfiguredChecker reports whether an *arr is set up. *plugins.Manager
+// satisfies it.
+type dvrConfiguredChecker interface {
+	IsConfigured(ctx context.Context, name string) bool
+}
+
+// builtinSettingsReader reads the quality profile setting.
+type builtinSettingsReader interface {
+	GetString(ctx context.Context, key string) (string, error)
+}
+
+// BuiltinGrab is one release handed to the download client.
+type BuiltinGrab struct {
+	RequestID string `json:"request_id"`
+	Release   string `json:"release"`
+	Indexer   string `json:"indexer"`
+	InfoHash  string `json:"info_hash"`
+}
+
+// BuiltinFulfilmentRun is the result of one pass.
+type BuiltinFulfilmentRun struct {
+	Considered int           `json:"considered"`
+	Releases   int           `json:"releases"`
+	Grabs      []BuiltinGrab `json:"grabs"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+}
+
+// sourcedRelease is a release and the indexer it came from.
+type sourcedRelease struct {
+	indexer.Release
+	indexerName string
+}
+
+// builtinTarget is what a request asks the built-in path for.
+type builtinTarget struct {
+	mediaType string
+	names     []string // normalized titles
+	year      int      // movies; 0 = unknown
+	season    int      // tv
+	episode   int      // tv; 0 = the whole season
+	minRank   int      // upgrade rows: the cutoff's resolution rank
+}
+
+// BuiltinFulfilmentService implements BuiltinFulfilmentServiceInterface.
+type BuiltinFulfilmentService struct {
+	indexers BuiltinIndexerReader
+	secrets  secrets.SecretsServiceInterface
+	releases ReleaseSource
+	requests BuiltinRequestStore
+	titles   BuiltinTitleSource
+	adder    TorrentAdder
+	dvr      dvrConfiguredChecker // nil = treat every *arr as unconfigured
+	settings builtinSettingsReader
+	profiles QualityProfileReader
+	logger   *slog.Logger
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex // guards failed and running
+	failed  map[string]bool
+	running bool
+
+	stopMu  sync.Mutex
+	stopCh  chan struct{}
+	stopped bool
+}
+
+// Compile-time interface verification.
+var _ BuiltinFulfilmentServiceInterface = (*BuiltinFulfilmentService)(nil)
+
+// NewBuiltinFulfilmentService creates the built-in grabber.
+func NewBuiltinFulfilmentService(
+	indexers BuiltinIndexerReader,
+	secretsService secrets.SecretsServiceInterface,
+	releases ReleaseSource,
+	requests BuiltinRequestStore,
+	titles BuiltinTitleSource,
+	adder TorrentAdder,
+	dvr dvrConfiguredChecker,
+	settings builtinSettingsReader,
+	profiles QualityProfileReader,
+	logger *slog.Logger,
+) *BuiltinFulfilmentService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &BuiltinFulfilmentService{
+		indexers: indexers,
+		secrets:  secretsService,
+		releases: releases,
+		requests: requests,
+		titles:   titles,
+		adder:    adder,
+		dvr:      dvr,
+		settings: settings,
+		profiles: profiles,
+		logger:   logger,
+		interval: defaultBuiltinFulfilmentInterval,
+		now:      time.Now,
+		failed:   map[string]bool{},
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start runs a pass now and then on every tick until ctx is cancelled or
+// Stop is called. It blocks; main.go runs it in a goroutine.
+func (s *BuiltinFulfilmentService) Start(ctx context.Context) {
+	s.logger.Info("Built-in fulfilment started", "interval", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && !errors.Is(err, ErrBuiltinFulfilmentRunning) {
+			s.logger.Error("Built-in fulfilment pass failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Built-in fulfilment stopped (context cancelled)")
+			return
+		case <-s.stopCh:
+			s.logger.Info("Built-in fulfilment stopped (stop signal)")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops the loop. Idempotent.
+func (s *BuiltinFulfilmentService) Stop() {
+	s.stopMu.Lock()
+	defer s.stopMu.Unlock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.stopCh)
+	}
+}
+
+// RunOnce runs one pass. Indexers are only contacted when some request is
+// waiting; one indexer's or one row's failure never stops the pass, but an
+// unavailable download client does.
+func (s *BuiltinFulfilmentService) RunOnce(ctx context.Context) (*BuiltinFulfilmentRun, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, ErrBuiltinFulfilmentRunning
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	run := &BuiltinFulfilmentRun{Grabs: []BuiltinGrab{}, StartedAt: s.now()}
+	defer func() { run.FinishedAt = s.now() }()
+
+	active, err := s.requests.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list active requests: %w", err)
+	}
+	var waiting []models.Request
+	for _, row := range active {
+		if s.eligible(ctx, &row) {
+			waiting = append(waiting, row)
+		}
+	}
+	run.Considered = len(waiting)
+	if len(waiting) == 0 {
+		return run, nil
+	}
+
+	enabled, err := s.indexers.ListEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list indexers: %w", err)
+	}
+	var indexers []models.Indexer
+	for _, ix := range enabled {
+		if !ix.Grabbable() {
+			s.logger.Debug("Built-in fulfilment skipped indexer: its releases are not torrents",
+				"indexer", ix.Name, "type", ix.Type)
+			continue
+		}
+		indexers = append(indexers, ix)
+	}
+	if len(indexers) == 0 {
+		return run, nil
+	}
+	configs := make([]indexer.Config, len(indexers))
+	for i := range indexers {
+		if configs[i], err = indexerConfig(ctx, s.secrets, &indexers[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	profile := s.qualityProfile(ctx)
+	recent := s.recentReleases(ctx, indexers, configs)
+	run.Releases = len(recent)
+
+	for i := range waiting {
+		row := &waiting[i]
+		target, ok := s.target(ctx, row)
+		if !ok {
+			continue
+		}
+		candidates := append(s.searchReleases(ctx, indexers, configs, row, target), recent...)
+		grab, err := s.grabBest(ctx, row, target, profile, candidates)
+		if err != nil {
+			return run, err
+		}
+		if grab != nil {
+			run.Grabs = append(run.Grabs, *grab)
+		}
+	}
+	s.logger.Info("Built-in fulfilment pass finished",
+		"considered", run.Considered, "releases", run.Releases, "grabbed", len(run.Grabs))
+	return run, nil
+}
+
+// eligible reports whether a row is the built-in path's to serve.
+func (s *BuiltinFulfilmentService) eligible(ctx context.Context, row *models.Request) bool {
+	if row.Status != models.RequestStatusPending || row.ExternalID.Valid {
+		return false
+	}
+	if row.FulfilmentSource.String == models.RequestFulfilmentSourceBuiltin {
+		return true
+	}
+	if s.dvr == nil {
+		return true
+	}
+	plugin := dvrMoviePlugin
+	if row.MediaType == models.RequestMediaTypeTV {
+		plugin = dvrSeriesPlugin
+	}
+	return !s.dvr.IsConfigured(ctx, plugin)
+}
+
+// qualityProfile loads the configured profile; a missing or dangling one
+// means any quality.
+func (s *BuiltinFulfilmentService) qualityProfile(ctx context.Context) *models.QualityProfile {
+	if s.settings == nil || s.profiles == nil {
+		return nil
+	}
+	id, err := s.settings.GetString(ctx, SettingKeyBuiltinQualityProfileID)
+	if err != nil || id == "" {
+		return nil
+	}
+	profile, err := s.profiles.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Warn("Built-in fulfilment quality profile unavailable", "profile_id", id, "error", err)
+		return nil
+	}
+	return profile
+}
+
+// target resolves what a row asks for. TV rows are only served when one
+// release can cover the whole selection: a single episode, or a single
+// whole season.
+func (s *BuiltinFulfilmentService) target(ctx context.Context, row *models.Request) (*builtinTarget, bool) {
+	t := &builtinTarget{mediaType: row.MediaType}
+	if row.UpgradeCutoff.Valid {
+		t.minRank = models.QualityResolutionRank(row.UpgradeCutoff.String)
+	}
+	names := []string{row.Title}
+
+	if row.MediaType == models.RequestMediaTypeMovie {
+		details, err := s.titles.GetMovieDetails(ctx, int(row.TMDbID))
+		if err != nil {
+			s.logger.Warn("Built-in fulfilment skipped request: TMDb lookup failed",
+				"request_id", row.ID, "tmdb_id", row.TMDbID, "error", err)
+			return nil, false
+		}
+		names = append(names, details.Title, details.OriginalTitle)
+		t.year = yearOf(details.ReleaseDate)
+		t.names = normalizedNames(names)
+		return t, true
+	}
+
+	details, err := s.titles.GetTVShowDetails(ctx, int(row.TMDbID))
+	if err != nil {
+		s.logger.Warn("Built-in fulfilment skipped request: TMDb lookup failed",
+			"request_id", row.ID, "tmdb_id", row.TMDbID, "error", err)
+		return nil, false
+	}
+	names = append(names, details.Name, details.OriginalName)
+	t.names = normalizedNames(names)
+
+	sel, err := parseSelectionColumns(row.Seasons, row.Episodes)
+	if err != nil {
+		return nil, false
+	}
+	switch {
+	case sel == nil && details.NumberOfSeasons == 1:
+		t.season = 1
+	case sel != nil && len(sel.Seasons) == 1 && len(sel.Episodes) == 0:
+		t.season = sel.Seasons[0]
+	case sel != nil && len(sel.Seasons) == 0 && len(sel.Episodes) == 1:
+		for season, episodes := range sel.Episodes {
+			if len(episodes) != 1 {
+				return nil, false
+			}
+			t.season, t.episode = season, episodes[0]
+		}
+	default:
+		s.logger.Debug("Built-in fulfilment skipped request: selection spans several releases",
+			"request_id", row.ID)
+		return nil, false
+	}
+	return t, true
+}
+
+// recentReleases reads every enabled indexer's latest releases once per pass.
+func (s *BuiltinFulfilmentService) recentReleases(ctx context.Context, indexers []models.Indexer, configs []indexer.Config) []sourcedRelease {
+	var out []sourcedRelease
+	for i, ix := range indexers {
+		releases, err := s.releases.Recent(ctx, configs[i])
+		if err != nil {
+			s.logger.Warn("Indexer feed read failed", "indexer", ix.Name, "error", err)
+			continue
+		}
+		for _, r := range releases {
+			out = append(out, sourcedRelease{Release: r, indexerName: ix.Name})
+		}
+	}
+	return out
+}
+
+// searchReleases asks every searchable indexer for one row.
+func (s *BuiltinFulfilmentService) searchReleases(
+	ctx context.Context,
+	indexers []models.Indexer,
+	configs []indexer.Config,
+	row *models.Request,
+	target *builtinTarget,
+) []sourcedRelease {
+	query := indexer.SearchQuery{
+		MediaType: row.MediaType,
+		Query:     searchTitle(row, target),
+		Season:    target.season,
+		Episode:   target.episode,
+	}
+	var out []sourcedRelease
+	for i, ix := range indexers {
+		if !ix.Searchable() {
+			continue
+		}
+		releases, err := s.releases.Search(ctx, configs[i], query)
+		if err != nil {
+			s.logger.Warn("Indexer search failed", "indexer", ix.Name, "request_id", row.ID, "error", err)
+			continue
+		}
+		for _, r := range releases {
+			out = append(out, sourcedRelease{Release: r, indexerName: ix.Name})
+		}
+	}
+	return out
+}
+
+// grabBest hands the best matching release to the download client, falling
+// back to the next one when a release cannot be fetched or added. The
+// returned error is set only when the download client is unavailable.
+func (s *BuiltinFulfilmentService) grabBest(
+	ctx context.Context,
+	row *models.Request,
+	target *builtinTarget,
+	profile *models.QualityProfile,
+	candidates []sourcedRelease,
+) (*BuiltinGrab, error) {
+	for _, r := range s.rank(target, profile, candidates) {
+		hash, err := s.add(ctx, row.ID, r.Release)
+		if err != nil {
+			var connErr *qbittorrent.ConnectionError
+			if errors.As(err, &connErr) {
+				return nil, fmt.Errorf("add torrent: %w", err)
+			}
+			s.logger.Warn("Built-in grab failed; trying the next release",
+				"request_id", row.ID, "release", r.Title, "indexer", r.indexerName, "error", err)
+			s.mu.Lock()
+			s.failed[r.GUID] = true
+			s.mu.Unlock()
+			continue
+		}
+
+		status := models.RequestStatusDownloading
+		source := models.NewNullString(models.RequestFulfilmentSourceBuiltin)
+		external := models.NewNullString(hash)
+		if _, err := s.requests.UpdateFulfilment(ctx, row.ID, status, source, external, models.NullString{}); err != nil {
+			// The torrent is in qBittorrent but the row does not know it;
+			// the next pass would add it again, which qBittorrent ignores.
+			s.logger.Error("Built-in grab added but not recorded",
+				"request_id", row.ID, "info_hash", hash, "error", err)
+			return nil, nil
+		}
+		s.logger.Info("Built-in fulfilment grabbed release",
+			"request_id", row.ID, "release", r.Title, "indexer", r.indexerName, "info_hash", hash)
+		return &BuiltinGrab{RequestID: row.ID, Release: r.Title, Indexer: r.indexerName, InfoHash: hash}, nil
+	}
+	return nil, nil
+}
+
+// add resolves a release link and adds it tagged with the request, returning
+// the info-hash recorded as the request's external id.
+func (s *BuiltinFulfilmentService) add(ctx context.Context, requestID string, r indexer.Release) (string, error) {
+	dl, err := s.releases.Download(ctx, r.Link)
+	if err != nil {
+		return "", err
+	}
+	opts := qbittorrent.AddTorrentOptions{
+		Category: builtinTorrentCategory,
+		Tags:     []string{RequestTorrentTag(requestID)},
+	}
+	var hash string
+	if dl.Magnet != "" {
+		hash = indexer.MagnetInfoHash(dl.Magnet)
+		if hash == "" {
+			hash = r.InfoHash
+		}
+		opts.URLs = []string{dl.Magnet}
+	} else {
+		if hash, err = indexer.InfoHash(dl.Torrent); err != nil {
+			return "", err
+		}
+		opts.Torrent = dl.Torrent
+		opts.FileName = "release.torrent"
+	}
+	if hash == "" {
+		return "", errors.New("release has no info-hash to follow")
+	}
+	if err := s.adder.AddTorrent(ctx, opts); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// rank returns the releases matching target and profile, best first:
+// resolution, then seeders, then size. Releases known to have no seeders,
+// and ones that already failed, are dropped.
+func (s *BuiltinFulfilmentService) rank(target *builtinTarget, profile *models.QualityProfile, candidates []sourcedRelease) []sourcedRelease {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := map[string]bool{}
+	var matches []sourcedRelease
+	for _, r := range candidates {
+		if seen[r.GUID] || s.failed[r.GUID] || r.Seeders == 0 {
+			continue
+		}
+		seen[r.GUID] = true
+		if matchesTarget(r.Title, target) && acceptableQuality(r.Title, target, profile) {
+			matches = append(matches, r)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		ri := models.QualityResolutionRank(parser.DetectQuality(matches[i].Title))
+		rj := models.QualityResolutionRank(parser.DetectQuality(matches[j].Title))
+		if ri != rj {
+			return ri > rj
+		}
+		if matches[i].Seeders != matches[j].Seeders {
+			return matches[i].Seeders > matches[j].Seeders
+		}
+		return matches[i].Size > matches[j].Size
+	})
+	return matches
+}
+
+// matchesTarget parses a release title and compares it with the target.
+func matchesTarget(title string, target *builtinTarget) bool {
+	if target.mediaType == models.RequestMediaTypeMovie {
+		parsed := parser.NewMovieParser().Parse(title)
+		if parsed.Status != parser.ParseStatusSuccess || !containsName(target.names, parsed.Title) {
+			return false
+		}
+		if target.year == 0 || parsed.Year == 0 {
+			return true
+		}
+		diff := parsed.Year - target.year
+		return diff >= -1 && diff <= 1
+	}
+
+	if parsed := parser.NewTVParser().Parse(title); parsed.Status == parser.ParseStatusSuccess && parsed.Episode > 0 {
+		if target.episode == 0 || parsed.Season != target.season || !containsName(target.names, parsed.Title) {
+			return false
+		}
+		last := parsed.EpisodeEnd
+		if last < parsed.Episode {
+			last = parsed.Episode
+		}
+		return parsed.Episode <= target.episode && target.episode <= last
+	}
+
+	m := seasonPackPattern.FindStringSubmatch(title)
+	if m == nil || target.episode != 0 {
+		return false
+	}
+	season, _ := strconv.Atoi(m[2])
+	return season == target.season && containsName(target.names, m[1])
+}
+
+// acceptableQuality judges a release title against the profile's
+// allow-lists, and an upgrade row's release against its cutoff. The cutoff
+// itself does not bar an ordinary grab: something below it beats nothing.
+func acceptableQuality(title string, target *builtinTarget, profile *models.QualityProfile) bool {
+	q := models.MediaQuality{
+		Resolution: parser.DetectQuality(title),
+		Source:     parser.DetectSource(title),
+		Codec:      parser.DetectVideoCodec(title),
+	}
+	if target.minRank > 0 && models.QualityResolutionRank(q.Resolution) < target.minRank {
+		return false
+	}
+	if profile == nil {
+		return true
+	}
+	for _, reason := range profile.Evaluate(q) {
+		if reason != models.QualityReasonBelowCutoff {
+			return false
+		}
+	}
+	return true
+}
+
+// searchTitle is the query sent to indexers: release names are mostly
+// English or the original title, never the zh-TW one.
+func searchTitle(row *models.Request, target *builtinTarget) string {
+	title := row.Title
+	for _, n := range target.names {
+		if isASCII(n) {
+			title = n
+			break
+		}
+	}
+	if target.year > 0 {
+		return title + " " + strconv.Itoa(target.year)
+	}
+	return title
+}
+
+// normalizeReleaseTitle folds a title for comparison: lowercase letters and
+// digits, every run of anything else one space.
+func normalizeReleaseTitle(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		if r == '\'' {
+			continue // "Schindler's" == "Schindlers"
+		}
+		space = true
+	}
+	return b.String()
+}
+
+func normalizedNames(names []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, n := range names {
+		norm := normalizeReleaseTitle(n)
+		if norm != "" && !seen[norm] {
+			seen[norm] = true
+			out = append(out, norm)
+		}
+	}
+	return out
+}
+
+func containsName(names []string, title string) bool {
+	norm := normalizeReleaseTitle(title)
+	for _, n := range names {
+		if n == norm {
+			return true
+		}
+	}
+	return false
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// yearOf reads the year of a TMDb "YYYY-MM-DD" date; 0 when absent.
+func yearOf(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	year, _ := strconv.Atoi(date[:4])
+	return year
+}
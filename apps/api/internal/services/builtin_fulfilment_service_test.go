@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/indexer"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/qbittorrent"
+	"github.com/vido/api/internal/tmdb"
+)
+
+// builtinTorrentFile is a minimal .torrent; its info dict is "d4:name1:xe".
+const builtinTorrentFile = "d8:announce3:url4:infod4:name1:xee"
+
+// fakeIndexerServer is a local Torznab endpoint serving one feed for every
+// query, plus the .torrent behind /download/.
+type fakeIndexerServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	feed     string
+	requests []string
+}
+
+func newFakeIndexerServer(t *testing.T) *fakeIndexerServer {
+	t.Helper()
+	f := &fakeIndexerServer{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.requests = append(f.requests, r.URL.RequestURI())
+		feed := f.feed
+		f.mu.Unlock()
+		if strings.HasPrefix(r.URL.Path, "/download/") {
+			fmt.Fprint(w, builtinTorrentFile)
+			return
+		}
+		fmt.Fprint(w, feed)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// serve sets the feed. An item "torrent:<title>:<seeders>" becomes a release
+// linking to the server's .torrent; anything else is used verbatim.
+func (f *fakeIndexerServer) serve(items ...string) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0"?><rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed"><channel>`)
+	for _, it := range items {
+		if rest, ok := strings.CutPrefix(it, "torrent:"); ok {
+			idx := strings.LastIndex(rest, ":")
+			title := rest[:idx]
+			fmt.Fprintf(&b, `<item><title>%s</title><guid>%s</guid><enclosure url="%s/download/%s"/>`+
+				`<torznab:attr name="seeders" value="%s"/></item>`, title, title, f.URL, title, rest[idx+1:])
+			continue
+		}
+		b.WriteString(it)
+	}
+	b.WriteString(`</channel></rss>`)
+	f.mu.Lock()
+	f.feed = b.String()
+	f.mu.Unlock()
+}
+
+func (f *fakeIndexerServer) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+func magnetItem(title, hash string, seeders int) string {
+	return fmt.Sprintf(`<item><title>%s</title><guid>%s</guid>`+
+		`<torznab:attr name="magneturl" value="magnet:?xt=urn:btih:%s&amp;dn=x"/>`+
+		`<torznab:attr name="seeders" value="%d"/></item>`, title, title, hash, seeders)
+}
+
+type fakeBuiltinIndexers []models.Indexer
+
+func (f fakeBuiltinIndexers) ListEnabled(ctx context.Context) ([]models.Indexer, error) {
+	return f, nil
+}
+
+type fulfilmentWrite struct {
+	id, status, source, externalID string
+}
+
+type fakeBuiltinRequests struct {
+	mu     sync.Mutex
+	rows   []models.Request
+	writes []fulfilmentWrite
+}
+
+func (f *fakeBuiltinRequests) ListActive(ctx context.Context) ([]models.Request, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]models.Request(nil), f.rows...), nil
+}
+
+func (f *fakeBuiltinRequests) UpdateFulfilment(ctx context.Context, id string, status string, fulfilmentSource, externalID, errorMessage models.NullString) (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writes = append(f.writes, fulfilmentWrite{id, status, fulfilmentSource.String, externalID.String})
+	for i := range f.rows {
+		if f.rows[i].ID == id {
+			f.rows[i].Status = status
+			f.rows[i].FulfilmentSource = fulfilmentSource
+			f.rows[i].ExternalID = externalID
+		}
+	}
+	return time.Now(), nil
+}
+
+type fakeBuiltinTitles struct{}
+
+func (fakeBuiltinTitles) GetMovieDetails(ctx context.Context, movieID int) (*tmdb.MovieDetails, error) {
+	if movieID != 550 {
+		return nil, errors.New("not found")
+	}
+	return &tmdb.MovieDetails{Movie: tmdb.Movie{Title: "鬥陣俱樂部", OriginalTitle: "Fight Club", ReleaseDate: "1999-10-15"}}, nil
+}
+
+func (fakeBuiltinTitles) GetTVShowDetails(ctx context.Context, tvID int) (*tmdb.TVShowDetails, error) {
+	return &tmdb.TVShowDetails{TVShow: tmdb.TVShow{Name: "熊家餐館", OriginalName: "The Bear"}, NumberOfSeasons: 3}, nil
+}
+
+type fakeTorrentAdder struct {
+	added []qbittorrent.AddTorrentOptions
+	errs  []error // returned in order, then nil
+}
+
+func (f *fakeTorrentAdder) AddTorrent(ctx context.Context, opts qbittorrent.AddTorrentOptions) error {
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return err
+	}
+	f.added = append(f.added, opts)
+	return nil
+}
+
+type fakeDVRConfigured map[string]bool
+
+func (f fakeDVRConfigured) IsConfigured(ctx context.Context, name string) bool { return f[name] }
+
+type builtinFixture struct {
+	service  *BuiltinFulfilmentService
+	server   *fakeIndexerServer
+	requests *fakeBuiltinRequests
+	adder    *fakeTorrentAdder
+	dvr      fakeDVRConfigured
+	settings *fakeDVRSettingsRepo
+	profiles qualityProfileMap
+}
+
+func newBuiltinFixture(t *testing.T, rows []models.Request, items ...string) *builtinFixture {
+	t.Helper()
+	f := &builtinFixture{
+		requests: &fakeBuiltinRequests{rows: rows},
+		adder:    &fakeTorrentAdder{},
+		dvr:      fakeDVRConfigured{},
+		settings: newFakeDVRSettingsRepo(),
+		profiles: qualityProfileMap{},
+	}
+	f.server = newFakeIndexerServer(t)
+	f.server.serve(items...)
+	indexers := fakeBuiltinIndexers{{ID: "ix1", Name: "Jackett", Type: models.IndexerTypeTorznab, URL: f.server.URL, Enabled: true}}
+	f.service = NewBuiltinFulfilmentService(indexers, newFakeDVRSecrets(), indexer.NewClient(),
+		f.requests, fakeBuiltinTitles{}, f.adder, f.dvr, f.settings, f.profiles, nil)
+	return f
+}
+
+func pendingMovie(id string, tmdbID int64) models.Request {
+	return models.Request{ID: id, TMDbID: tmdbID, MediaType: models.RequestMediaTypeMovie, Title: "鬥陣俱樂部", Status: models.RequestStatusPending}
+}
+
+func pendingShow(id, seasons, episodes string) models.Request {
+	row := models.Request{ID: id, TMDbID: 136315, MediaType: models.RequestMediaTypeTV, Title: "熊家餐館", Status: models.RequestStatusPending}
+	if seasons != "" {
+		row.Seasons = models.NewNullString(seasons)
+	}
+	if episodes != "" {
+		row.Episodes = models.NewNullString(episodes)
+	}
+	return row
+}
+
+func TestBuiltinFulfilment_GrabsBestMatchingRelease(t *testing.T) {
+	f := newBuiltinFixture(t, []models.Request{pendingMovie("r1", 550)},
+		"torrent:Fight.Club.1999.1080p.BluRay.x264-SPARKS:42",
+		magnetItem("Fight.Club.1999.720p.WEB-DL.x264", "0123456789abcdef0123456789abcdef01234567", 300),
+		magnetItem("Fight.Club.1999.2160p.UHD.BluRay.x265", "1111111111111111111111111111111111111111", 0),
+		magnetItem("Fight.Club.2.2030.1080p.WEB-DL", "2222222222222222222222222222222222222222", 900),
+		magnetItem("Fight.Night.1999.1080p.BluRay", "3333333333333333333333333333333333333333", 900),
+	)
+
+	run, err := f.service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, run.Considered)
+	require.Len(t, run.Grabs, 1)
+	assert.Equal(t, "Fight.Club.1999.1080p.BluRay.x264-SPARKS", run.Grabs[0].Release, "2160p has no seeders; 1080p beats 720p")
+	require.Len(t, f.adder.added, 1)
+	assert.Equal(t, []byte(builtinTorrentFile), f.adder.added[0].Torrent)
+	assert.Equal(t, "vido", f.adder.added[0].Category)
+	assert.Equal(t, []string{"vido-request:r1"}, f.adder.added[0].Tags)
+
+	hash, err := indexer.InfoHash([]byte(builtinTorrentFile))
+	require.NoError(t, err)
+	require.Len(t, f.requests.writes, 1)
+	assert.Equal(t, fulfilmentWrite{"r1", models.RequestStatusDownloading, models.RequestFulfilmentSourceBuiltin, hash}, f.requests.writes[0])
+
+	t.Run("a grabbed row is not served again", func(t *testing.T) {
+		run, err := f.service.RunOnce(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, run.Considered)
+		assert.Len(t, f.adder.added, 1)
+	})
+}
+
+func TestBuiltinFulfilment_QualityProfileFiltersReleases(t *testing.T) {
+	f := newBuiltinFixture(t, []models.Request{pendingMovie("r1", 550)},
+		"torrent:Fight.Club.1999.1080p.BluRay.x264-SPARKS:42",
+		magnetItem("Fight.Club.1999.720p.WEB-DL.x264", "0123456789ABCDEF0123456789ABCDEF01234567", 3),
+	)
+	f.profiles["p1"] = &models.QualityProfile{ID: "p1", Resolutions: []string{"720p"}, Cutoff: "720p"}
+	f.settings.strings[SettingKeyBuiltinQualityProfileID] = "p1"
+
+	run, err := f.service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, run.Grabs, 1)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", run.Grabs[0].InfoHash)
+	require.Len(t, f.adder.added, 1)
+	assert.Len(t, f.adder.added[0].URLs, 1)
+}
+
+func TestBuiltinFulfilment_UpgradeRowNeedsItsCutoff(t *testing.T) {
+	row := pendingMovie("r1", 550)
+	row.FulfilmentSource = models.NewNullString(models.RequestFulfilmentSourceBuiltin)
+	row.UpgradeCutoff = models.NewNullString("2160p")
+	f := newBuiltinFixture(t, []models.Request{row}, "torrent:Fight.Club.1999.1080p.BluRay.x264-SPARKS:42")
+	f.dvr[dvrMoviePlugin] = true // builtin rows are served even beside a configured *arr
+
+	run, err := f.service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, run.Considered)
+	assert.Empty(t, run.Grabs)
+	assert.Empty(t, f.requests.writes)
+}
+
+func TestBuiltinFulfilment_ArrRowsAreLeftToTheArr(t *testing.T) {
+	f := newBuiltinFixture(t, []models.Request{pendingMovie("r1", 550)}, "torrent:Fight.Club.1999.1080p.BluRay.x264-SPARKS:42")
+	f.dvr[dvrMoviePlugin] = true
+
+	run, err := f.service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Zero(t, run.Considered)
+	assert.Zero(t, f.server.calls(), "no waiting row, no indexer traffic")
+}
+
+func TestBuiltinFulfilment_TVSelections(t *testing.T) {
+	items := []string{
+		magnetItem("The.Bear.S02E03.1080p.WEB.h264-GRP", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", 10),
+		magnetItem("The.Bear.S02.1080p.WEB.h264-GRP", "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", 10),
+		magnetItem("The.Bear.S03E01.1080p.WEB.h264-GRP", "cccccccccccccccccccccccccccccccccccccccc", 10),
+	}
+	cases := []struct {
+		name     string
+		row      models.Request
+		wantHash string
+	}{
+		{"single episode takes the episode", pendingShow("r1", "", `{"2":[3]}`), "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+		{"single season takes the season pack", pendingShow("r1", "[2]", ""), "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+		{"several seasons stay pending", pendingShow("r1", "[2,3]", ""), ""},
+		{"whole multi-season show stays pending", pendingShow("r1", "", ""), ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newBuiltinFixture(t, []models.Request{tc.row}, items...)
+
+			run, err := f.service.RunOnce(context.Background())
+			require.NoError(t, err)
+
+			if tc.wantHash == "" {
+				assert.Empty(t, run.Grabs)
+				return
+			}
+			require.Len(t, run.Grabs, 1)
+			assert.Equal(t, tc.wantHash, run.Grabs[0].InfoHash)
+		})
+	}
+}
+
+func TestBuiltinFulfilment_AddFailures(t *testing.T) {
+	items := []string{
+		"torrent:Fight.Club.1999.1080p.BluRay.x264-SPARKS:42",
+		magnetItem("Fight.Club.1999.720p.WEB-DL.x264", "0123456789abcdef0123456789abcdef01234567", 3),
+	}
+
+	t.Run("a rejected release falls back to the next", func(t *testing.T) {
+		f := newBuiltinFixture(t, []models.Request{pendingMovie("r1", 550)}, items...)
+		f.adder.errs = []error{errors.New("torrent rejected")}
+
+		run, err := f.service.RunOnce(context.Background())
+		require.NoError(t, err)
+		require.Len(t, run.Grabs, 1)
+		assert.Equal(t, "Fight.Club.1999.720p.WEB-DL.x264", run.Grabs[0].Release)
+	})
+
+	t.Run("an unavailable download client stops the pass", func(t *testing.T) {
+		f := newBuiltinFixture(t, []models.Request{pendingMovie("r1", 550)}, items...)
+		f.adder.errs = []error{&qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeNotConfigured, Message: "qBittorrent not configured"}}
+
+		_, err := f.service.RunOnce(context.Background())
+		var connErr *qbittorrent.ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Empty(t, f.requests.writes)
+	})
+}
+
+// TestBuiltinFulfilment_NewznabIsNotGrabbed — Newznab releases are NZBs,
+// which qBittorrent cannot take, so the pass does not query those indexers.
+func TestBuiltinFulfilment_NewznabIsNotGrabbed(t *testing.T) {
+	f := newBuiltinFixture(t, []models.Request{pendingMovie("r1", 550)},
+		"torrent:Fight.Club.1999.1080p.BluRay.x264-SPARKS:42")
+	indexers := fakeBuiltinIndexers{{ID: "ix1", Name: "NZBGeek", Type: models.IndexerTypeNewznab, URL: f.server.URL, Enabled: true}}
+	f.service = NewBuiltinFulfilmentService(indexers, newFakeDVRSecrets(), indexer.NewClient(),
+		f.requests, fakeBuiltinTitles{}, f.adder, f.dvr, f.settings, f.profiles, nil)
+
+	run, err := f.service.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, run.Grabs)
+	assert.Zero(t, f.server.calls())
+	assert.Empty(t, f.adder.added)
+}
+
+func TestMatchesTarget_TitleNormalization(t *testing.T) {
+	target := &builtinTarget{
+		mediaType: models.RequestMediaTypeMovie,
+		names:     normalizedNames([]string{"Schindler's List"}),
+		year:      1993,
+	}
+	assert.True(t, matchesTarget("Schindlers.List.1993.1080p.BluRay.x264", target))
+	assert.True(t, matchesTarget("Schindlers List (1994) 720p", target), "a year off by one still matches")
+	assert.False(t, matchesTarget("Schindlers.List.2003.1080p.BluRay.x264", target))
+}
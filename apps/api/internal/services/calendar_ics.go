@@ -0,0 +1,79 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ICSContentType is the media type of the calendar feed.
+const ICSContentType = "text/calendar; charset=utf-8"
+
+// WriteICS renders the calendar as an RFC 5545 feed: one all-day event per
+// episode, upcoming and missing alike. UIDs are stable per series/episode so a
+// subscribed calendar updates events in place when an air date moves.
+func WriteICS(w io.Writer, cal *Calendar, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+
+	writeICSLine(bw, "BEGIN:VCALENDAR")
+	writeICSLine(bw, "VERSION:2.0")
+	writeICSLine(bw, "PRODID:-//Vido//Episode Calendar//EN")
+	writeICSLine(bw, "CALSCALE:GREGORIAN")
+	writeICSLine(bw, "METHOD:PUBLISH")
+	writeICSLine(bw, "X-WR-CALNAME:"+escapeICSText("Vido 播出時間表"))
+
+	for _, list := range [][]CalendarEpisode{cal.Missing, cal.Upcoming} {
+		for _, ep := range list {
+			day, err := time.Parse(time.DateOnly, ep.AirDate)
+			if err != nil {
+				continue
+			}
+			summary := ep.SeriesTitle + " " + ep.Code()
+			if ep.Title != "" {
+				summary += " " + ep.Title
+			}
+
+			writeICSLine(bw, "BEGIN:VEVENT")
+			writeICSLine(bw, fmt.Sprintf("UID:vido-tv-%d-%s@vido", ep.SeriesTMDbID, strings.ToLower(ep.Code())))
+			writeICSLine(bw, "DTSTAMP:"+dtstamp)
+			writeICSLine(bw, "DTSTART;VALUE=DATE:"+day.Format("20060102"))
+			writeICSLine(bw, "DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"))
+			writeICSLine(bw, "SUMMARY:"+escapeICSText(summary))
+			if ep.Overview != "" {
+				writeICSLine(bw, "DESCRIPTION:"+escapeICSText(ep.Overview))
+			}
+			writeICSLine(bw, "TRANSP:TRANSPARENT")
+			writeICSLine(bw, "END:VEVENT")
+		}
+	}
+
+	writeICSLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICSText(s string) string { return icsTextEscaper.Replace(s) }
+
+// writeICSLine writes one content line with CRLF, folding it at 75 octets
+// without splitting a UTF-8 sequence.
+func writeICSLine(w *bufio.Writer, line string) {
+	const limit = 75
+	width := limit
+	for len(line) > width {
+		cut := width
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines spend one octet on the leading space.
+		width = limit - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,370 @@
+// Package services CalendarService — what airs next for series in the library.
+//
+// For every enriched series the calendar reads the show's season list and the
+// episodes of its latest two seasons (the current one and an announced next
+// one) through the TMDb cache, so a calendar app polling the .ics feed costs
+// cache reads, not API calls. Episodes airing inside the look-ahead window
+// are "upcoming"; episodes that aired inside the look-back window without a
+// file on disk are "missing".
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/tmdb"
+)
+
+// Calendar window bounds, in days.
+const (
+	CalendarDefaultDays     = 14
+	CalendarMaxDays         = 90
+	CalendarDefaultPastDays = 7
+	CalendarMaxPastDays     = 30
+)
+
+// calendarSeasons is how many of a show's latest seasons are scanned.
+const calendarSeasons = 2
+
+// SettingKeyCalendarFeedToken holds the generated token /calendar.ics
+// checks. It is per install: any account can read the calendar anyway.
+const SettingKeyCalendarFeedToken = "calendar.feed_token"
+
+// ErrCalendarFeedTokenPinned is returned by RotateFeedToken when the token
+// comes from VIDO_CALENDAR_TOKEN and can only change there.
+var ErrCalendarFeedTokenPinned = errors.New("calendar feed token is set by VIDO_CALENDAR_TOKEN")
+
+// CalendarServiceInterface defines the contract for the episode calendar.
+type CalendarServiceInterface interface {
+	Calendar(ctx context.Context, opts CalendarOptions) (*Calendar, error)
+	// FeedToken returns the token /calendar.ics requires, generating and
+	// storing one on first use.
+	FeedToken(ctx context.Context) (string, error)
+	// RotateFeedToken replaces the stored token, cutting off every
+	// subscription made with the old one.
+	RotateFeedToken(ctx context.Context) (string, error)
+	// FeedTokenPinned reports whether the token comes from configuration.
+	FeedTokenPinned() bool
+}
+
+// CalendarSettingsStore is the settings-repo subset the feed token lives in.
+// *repository.SettingsRepository satisfies it.
+type CalendarSettingsStore interface {
+	GetString(ctx context.Context, key string) (string, error)
+	SetString(ctx context.Context, key, value string) error
+}
+
+// CalendarTMDbReader is the narrow TMDb port the calendar reads through.
+// TMDbServiceInterface satisfies it; both calls are cached.
+type CalendarTMDbReader interface {
+	GetTVShowDetails(ctx context.Context, tvID int) (*tmdb.TVShowDetails, error)
+	GetSeasonDetails(ctx context.Context, tvID int, seasonNumber int) (*tmdb.SeasonDetails, error)
+}
+
+// CalendarSeriesLister is the series-repo method the calendar walks.
+// *repository.SeriesRepository satisfies it.
+type CalendarSeriesLister interface {
+	FindByParseStatus(ctx context.Context, status models.ParseStatus) ([]models.Series, error)
+}
+
+// CalendarOptions bounds the window. Zero values take the defaults; larger
+// values are clamped.
+type CalendarOptions struct {
+	Days     int
+	PastDays int
+}
+
+func (o CalendarOptions) normalized() CalendarOptions {
+	if o.Days <= 0 {
+		o.Days = CalendarDefaultDays
+	}
+	o.Days = min(o.Days, CalendarMaxDays)
+	if o.PastDays <= 0 {
+		o.PastDays = CalendarDefaultPastDays
+	}
+	o.PastDays = min(o.PastDays, CalendarMaxPastDays)
+	return o
+}
+
+// CalendarEpisode is one episode on the calendar.
+type CalendarEpisode struct {
+	SeriesID      string `json:"series_id"`
+	SeriesTitle   string `json:"series_title"`
+	SeriesTMDbID  int64  `json:"series_tmdb_id"`
+	SeasonNumber  int    `json:"season_number"`
+	EpisodeNumber int    `json:"episode_number"`
+	Title         string `json:"title,omitempty"`
+	Overview      string `json:"overview,omitempty"`
+	AirDate       string `json:"air_date"`
+	Runtime       int    `json:"runtime,omitempty"`
+	StillPath     string `json:"still_path,omitempty"`
+	PosterPath    string `json:"poster_path,omitempty"`
+	Owned         bool   `json:"owned"`
+}
+
+// Code returns the S01E02 form of the episode number.
+func (e CalendarEpisode) Code() string {
+	return fmt.Sprintf("S%02dE%02d", e.SeasonNumber, e.EpisodeNumber)
+}
+
+// Calendar is the computed window. Both lists are in air-date order.
+type Calendar struct {
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Upcoming []CalendarEpisode `json:"upcoming"`
+	Missing  []CalendarEpisode `json:"missing"`
+}
+
+// CalendarService implements CalendarServiceInterface.
+type CalendarService struct {
+	series   CalendarSeriesLister
+	episodes EpisodeOwnershipReader
+	tmdb     CalendarTMDbReader
+	logger   *slog.Logger
+	now      func() time.Time
+
+	// tokenMu serialises first-use generation, so two feed requests racing
+	// on a fresh install do not each store a different token.
+	tokenMu     sync.Mutex
+	settings    CalendarSettingsStore
+	pinnedToken string
+}
+
+// Compile-time verification.
+var _ CalendarServiceInterface = (*CalendarService)(nil)
+
+// NewCalendarService builds a CalendarService.
+func NewCalendarService(
+	series CalendarSeriesLister,
+	episodes EpisodeOwnershipReader,
+	tmdbReader CalendarTMDbReader,
+	logger *slog.Logger,
+) *CalendarService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CalendarService{
+		series: series, episodes: episodes, tmdb: tmdbReader,
+		logger: logger.With("service", "calendar"),
+		now:    time.Now,
+	}
+}
+
+// SetFeedTokenStore wires where the generated feed token is kept. A non-empty
+// pinned token (VIDO_CALENDAR_TOKEN) is used instead and never rotated.
+func (s *CalendarService) SetFeedTokenStore(settings CalendarSettingsStore, pinned string) {
+	s.settings = settings
+	s.pinnedToken = pinned
+}
+
+// FeedTokenPinned implements CalendarServiceInterface.
+func (s *CalendarService) FeedTokenPinned() bool {
+	return s.pinnedToken != ""
+}
+
+// FeedToken implements CalendarServiceInterface.
+func (s *CalendarService) FeedToken(ctx context.Context) (string, error) {
+	if s.pinnedToken != "" {
+		return s.pinnedToken, nil
+	}
+	if s.settings == nil {
+		return "", errors.New("calendar feed token store not configured")
+	}
+	s.tokenMu.Lock()
+	defer s.tokenMu.Unlock()
+	// A read error reads as "no token yet", as the other settings do; the
+	// write below fails too if the store is really down.
+	if token, err := s.settings.GetString(ctx, SettingKeyCalendarFeedToken); err == nil && token != "" {
+		return token, nil
+	}
+	return s.storeNewFeedToken(ctx)
+}
+
+// RotateFeedToken implements CalendarServiceInterface.
+func (s *CalendarService) RotateFeedToken(ctx context.Context) (string, error) {
+	if s.pinnedToken != "" {
+		return "", ErrCalendarFeedTokenPinned
+	}
+	if s.settings == nil {
+		return "", errors.New("calendar feed token store not configured")
+	}
+	s.tokenMu.Lock()
+	defer s.tokenMu.Unlock()
+	return s.storeNewFeedToken(ctx)
+}
+
+func (s *CalendarService) storeNewFeedToken(ctx context.Context) (string, error) {
+	token, err := newSessionToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.settings.SetString(ctx, SettingKeyCalendarFeedToken, token); err != nil {
+		return "", fmt.Errorf("store calendar feed token: %w", err)
+	}
+	s.logger.Info("calendar feed token generated")
+	return token, nil
+}
+
+// Calendar computes upcoming and recently-aired-but-missing episodes. A
+// series TMDb cannot answer for is logged and left out rather than failing
+// the whole calendar.
+func (s *CalendarService) Calendar(ctx context.Context, opts CalendarOptions) (*Calendar, error) {
+	opts = opts.normalized()
+	today := s.now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	todayStr := today.Format(time.DateOnly)
+	from := today.AddDate(0, 0, -opts.PastDays).Format(time.DateOnly)
+	to := today.AddDate(0, 0, opts.Days).Format(time.DateOnly)
+
+	seriesList, err := s.series.FindByParseStatus(ctx, models.ParseStatusSuccess)
+	if err != nil {
+		return nil, fmt.Errorf("list series: %w", err)
+	}
+
+	cal := &Calendar{From: from, To: to, Upcoming: []CalendarEpisode{}, Missing: []CalendarEpisode{}}
+	for i := range seriesList {
+		series := &seriesList[i]
+		if !series.TMDbID.Valid || series.TMDbID.Int64 <= 0 || !calendarRelevant(series, from) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		episodes, err := s.seriesEpisodes(ctx, series, from, to)
+		if err != nil {
+			s.logger.Warn("calendar: skipping series", "series_id", series.ID, "tmdb_id", series.TMDbID.Int64, "error", err)
+			continue
+		}
+		for _, ep := range episodes {
+			switch {
+			case ep.AirDate >= todayStr:
+				cal.Upcoming = append(cal.Upcoming, ep)
+			case !ep.Owned:
+				cal.Missing = append(cal.Missing, ep)
+			}
+		}
+	}
+
+	sortCalendarEpisodes(cal.Upcoming)
+	sortCalendarEpisodes(cal.Missing)
+	return cal, nil
+}
+
+// calendarRelevant skips shows that finished before the window opened. A
+// show with no production flag is kept: the TMDb lookup decides.
+func calendarRelevant(series *models.Series, from string) bool {
+	if !series.InProduction.Valid || series.InProduction.Bool {
+		return true
+	}
+	return series.LastAirDate.Valid && series.LastAirDate.String >= from
+}
+
+// seriesEpisodes returns the episodes of the series' latest seasons airing in
+// [from, to], each marked owned when a file for it is on disk.
+func (s *CalendarService) seriesEpisodes(ctx context.Context, series *models.Series, from, to string) ([]CalendarEpisode, error) {
+	tvID := int(series.TMDbID.Int64)
+	show, err := s.tmdb.GetTVShowDetails(ctx, tvID)
+	if err != nil {
+		return nil, fmt.Errorf("show details: %w", err)
+	}
+
+	var inWindow []CalendarEpisode
+	for _, seasonNumber := range latestSeasonNumbers(show.Seasons, calendarSeasons) {
+		season, err := s.tmdb.GetSeasonDetails(ctx, tvID, seasonNumber)
+		if err != nil {
+			return nil, fmt.Errorf("season %d: %w", seasonNumber, err)
+		}
+		for _, ep := range season.Episodes {
+			if ep.AirDate == nil || *ep.AirDate < from || *ep.AirDate > to {
+				continue
+			}
+			inWindow = append(inWindow, calendarEpisode(series, ep))
+		}
+	}
+	if len(inWindow) == 0 {
+		return nil, nil
+	}
+
+	local, err := s.episodes.FindBySeriesID(ctx, series.ID)
+	if err != nil {
+		return nil, fmt.Errorf("local episodes: %w", err)
+	}
+	owned := ownedEpisodeKeys(local)
+	for i := range inWindow {
+		inWindow[i].Owned = owned[episodeKey{inWindow[i].SeasonNumber, inWindow[i].EpisodeNumber}]
+	}
+	return inWindow, nil
+}
+
+// latestSeasonNumbers returns up to n of the highest regular season numbers
+// (specials, season 0, are left out).
+func latestSeasonNumbers(seasons []tmdb.Season, n int) []int {
+	numbers := make([]int, 0, len(seasons))
+	for _, season := range seasons {
+		if season.SeasonNumber > 0 {
+			numbers = append(numbers, season.SeasonNumber)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(numbers)))
+	if len(numbers) > n {
+		numbers = numbers[:n]
+	}
+	return numbers
+}
+
+type episodeKey struct{ season, episode int }
+
+// ownedEpisodeKeys indexes the local episodes that have a file.
+func ownedEpisodeKeys(episodes []models.Episode) map[episodeKey]bool {
+	owned := make(map[episodeKey]bool, len(episodes))
+	for _, ep := range episodes {
+		if ep.FilePath.Valid && ep.FilePath.String != "" {
+			owned[episodeKey{ep.SeasonNumber, ep.EpisodeNumber}] = true
+		}
+	}
+	return owned
+}
+
+func calendarEpisode(series *models.Series, ep tmdb.EpisodeInfo) CalendarEpisode {
+	out := CalendarEpisode{
+		SeriesID:      series.ID,
+		SeriesTitle:   series.Title,
+		SeriesTMDbID:  series.TMDbID.Int64,
+		SeasonNumber:  ep.SeasonNumber,
+		EpisodeNumber: ep.EpisodeNumber,
+		Title:         ep.Name,
+		Overview:      ep.Overview,
+		AirDate:       *ep.AirDate,
+		StillPath:     derefPath(ep.StillPath),
+	}
+	if ep.Runtime != nil {
+		out.Runtime = *ep.Runtime
+	}
+	if series.PosterPath.Valid {
+		out.PosterPath = series.PosterPath.String
+	}
+	return out
+}
+
+func sortCalendarEpisodes(episodes []CalendarEpisode) {
+	sort.SliceStable(episodes, func(i, j int) bool {
+		a, b := episodes[i], episodes[j]
+		if a.AirDate != b.AirDate {
+			return a.AirDate < b.AirDate
+		}
+		if a.SeriesTitle != b.SeriesTitle {
+			return a.SeriesTitle < b.SeriesTitle
+		}
+		if a.SeasonNumber != b.SeasonNumber {
+			return a.SeasonNumber < b.SeasonNumber
+		}
+		return a.EpisodeNumber < b.EpisodeNumber
+	})
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/tmdb"
+)
+
+type calendarSeries []models.Series
+
+func (c calendarSeries) FindByParseStatus(ctx context.Context, status models.ParseStatus) ([]models.Series, error) {
+	return c, nil
+}
+
+type calendarEpisodes map[string][]models.Episode
+
+func (c calendarEpisodes) FindBySeriesID(ctx context.Context, seriesID string) ([]models.Episode, error) {
+	return c[seriesID], nil
+}
+
+type fakeCalendarTMDb struct {
+	shows        map[int]*tmdb.TVShowDetails
+	seasons      map[[2]int]*tmdb.SeasonDetails
+	seasonsAsked []int
+}
+
+func (f *fakeCalendarTMDb) GetTVShowDetails(ctx context.Context, tvID int) (*tmdb.TVShowDetails, error) {
+	if show, ok := f.shows[tvID]; ok {
+		return show, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeCalendarTMDb) GetSeasonDetails(ctx context.Context, tvID int, seasonNumber int) (*tmdb.SeasonDetails, error) {
+	f.seasonsAsked = append(f.seasonsAsked, seasonNumber)
+	if season, ok := f.seasons[[2]int{tvID, seasonNumber}]; ok {
+		return season, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func airing(season, episode int, date, name string) tmdb.EpisodeInfo {
+	return tmdb.EpisodeInfo{SeasonNumber: season, EpisodeNumber: episode, AirDate: &date, Name: name}
+}
+
+func TestCalendarService_Calendar(t *testing.T) {
+	reader := &fakeCalendarTMDb{
+		shows: map[int]*tmdb.TVShowDetails{
+			100: {Seasons: []tmdb.Season{{SeasonNumber: 0}, {SeasonNumber: 1}, {SeasonNumber: 2}, {SeasonNumber: 3}}},
+		},
+		seasons: map[[2]int]*tmdb.SeasonDetails{
+			{100, 2}: {Episodes: []tmdb.EpisodeInfo{
+				airing(2, 7, "2026-09-20", "too old"),
+				airing(2, 8, "2026-10-10", "owned"),
+				airing(2, 9, "2026-10-13", "missing"),
+			}},
+			{100, 3}: {Episodes: []tmdb.EpisodeInfo{
+				airing(3, 1, "2026-10-16", "today"),
+				airing(3, 2, "2026-10-23", "next week"),
+				airing(3, 3, "2026-12-01", "too far"),
+				{SeasonNumber: 3, EpisodeNumber: 4},
+			}},
+		},
+	}
+	series := calendarSeries{
+		{ID: "s1", Title: "葬送的芙莉蓮", TMDbID: models.NewNullInt64(100)},
+		{ID: "s2", Title: "Ended long ago", TMDbID: models.NewNullInt64(200),
+			InProduction: models.NullBool{NullBool: sql.NullBool{Bool: false, Valid: true}},
+			LastAirDate:  models.NewNullString("2019-05-19")},
+		{ID: "s3", Title: "Unknown to TMDb", TMDbID: models.NewNullInt64(300)},
+		{ID: "s4", Title: "Never matched"},
+	}
+	episodes := calendarEpisodes{"s1": {
+		{SeasonNumber: 2, EpisodeNumber: 8, FilePath: models.NewNullString("/tv/frieren/S02E08.mkv")},
+		{SeasonNumber: 2, EpisodeNumber: 9},
+	}}
+
+	svc := NewCalendarService(series, episodes, reader, nil)
+	svc.now = func() time.Time { return time.Date(2026, 10, 16, 20, 30, 0, 0, time.Local) }
+
+	cal, err := svc.Calendar(context.Background(), CalendarOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-10-09", cal.From)
+	assert.Equal(t, "2026-10-30", cal.To)
+	assert.Equal(t, []int{3, 2}, reader.seasonsAsked, "only the latest two regular seasons are read")
+
+	require.Len(t, cal.Upcoming, 2)
+	assert.Equal(t, "S03E01", cal.Upcoming[0].Code())
+	assert.Equal(t, "S03E02", cal.Upcoming[1].Code())
+	require.Len(t, cal.Missing, 1, "owned episodes drop off the missing list")
+	assert.Equal(t, "S02E09", cal.Missing[0].Code())
+	assert.Equal(t, "葬送的芙莉蓮", cal.Missing[0].SeriesTitle)
+}
+
+func TestCalendarService_FeedToken(t *testing.T) {
+	ctx := context.Background()
+	settings := newFakeDVRSettingsRepo()
+	svc := NewCalendarService(nil, nil, nil, nil)
+	svc.SetFeedTokenStore(settings, "")
+
+	token, err := svc.FeedToken(ctx)
+	require.NoError(t, err)
+	assert.Len(t, token, 43, "256 random bits, URL-safe")
+	again, err := svc.FeedToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, token, again, "generated once, then read back")
+	stored, _ := settings.GetString(ctx, SettingKeyCalendarFeedToken)
+	assert.Equal(t, token, stored)
+
+	rotated, err := svc.RotateFeedToken(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, rotated)
+	assert.False(t, svc.FeedTokenPinned())
+
+	svc.SetFeedTokenStore(settings, "from-env")
+	token, err = svc.FeedToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", token)
+	assert.True(t, svc.FeedTokenPinned())
+	_, err = svc.RotateFeedToken(ctx)
+	assert.ErrorIs(t, err, ErrCalendarFeedTokenPinned)
+}
+
+func TestCalendarOptions_Normalized(t *testing.T) {
+	assert.Equal(t, CalendarOptions{Days: 14, PastDays: 7}, CalendarOptions{}.normalized())
+	assert.Equal(t, CalendarOptions{Days: 90, PastDays: 30}, CalendarOptions{Days: 365, PastDays: 365}.normalized())
+}
+
+func TestWriteICS(t *testing.T) {
+	cal := &Calendar{
+		Upcoming: []CalendarEpisode{{
+			SeriesTitle: "Frieren; Beyond, Journey", SeriesTMDbID: 100, SeasonNumber: 3, EpisodeNumber: 1,
+			AirDate:  "2026-10-16",
+			Overview: strings.Repeat("勇者一行人打倒魔王之後", 4),
+		}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteICS(&buf, cal, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, out, "UID:vido-tv-100-s03e01@vido\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20261016\r\nDTEND;VALUE=DATE:20261017\r\n")
+	assert.Contains(t, out, `SUMMARY:Frieren\; Beyond\, Journey S03E01`)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "content lines are folded at 75 octets")
+		assert.True(t, utf8.ValidString(line), "folding keeps multi-byte characters whole")
+	}
+}
@@ -0,0 +1,384 @@
+// Package services CollectionService — movie collections (franchises).
+//
+// TMDb reports the collection a movie belongs to on its details payload.
+// Enrichment hands that reference here; the collection and its member list
+// are stored once and ownership is joined at read time through the
+// AvailabilityService, so "Harry Potter (8/8 owned)" stays correct as movies
+// come and go without touching the stored rows. Missing parts can be
+// requested in one call, each through the regular RequestService create path.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/tmdb"
+)
+
+// ErrCollectionSyncRunning is returned when a backfill is requested while one
+// is already in flight.
+var ErrCollectionSyncRunning = errors.New("collection sync already running")
+
+// Skip reasons reported by RequestMissing.
+const (
+	CollectionSkipUnreleased       = "unreleased"
+	CollectionSkipAlreadyRequested = "already_requested"
+	CollectionSkipInLibrary        = "in_library"
+	CollectionSkipFailed           = "failed"
+)
+
+// CollectionServiceInterface defines the contract for the collections view.
+type CollectionServiceInterface interface {
+	// ListCollections returns every collection the library owns at least one
+	// part of, with per-part ownership.
+	ListCollections(ctx context.Context) ([]CollectionSummary, error)
+	GetCollection(ctx context.Context, id int64) (*CollectionSummary, error)
+	// RequestMissing creates a movie request for every released part of the
+	// collection the library does not own.
+	RequestMissing(ctx context.Context, id int64) (*CollectionRequestResult, error)
+	// StartSync backfills collections for movies enriched before collections
+	// were recorded. It returns immediately; the sweep runs in the background.
+	StartSync() error
+}
+
+// CollectionRecorder is the port EnrichmentService records a movie's
+// collection through. *CollectionService satisfies it.
+type CollectionRecorder interface {
+	RecordMovieCollection(ctx context.Context, details *tmdb.MovieDetails) error
+}
+
+// CollectionTMDbReader is the narrow TMDb port this service needs.
+// TMDbServiceInterface satisfies it.
+type CollectionTMDbReader interface {
+	GetMovieDetails(ctx context.Context, movieID int) (*tmdb.MovieDetails, error)
+	GetCollection(ctx context.Context, collectionID int) (*tmdb.CollectionDetails, error)
+}
+
+// CollectionMovieLister is the movie-repo method the backfill walks.
+// *repository.MovieRepository satisfies it.
+type CollectionMovieLister interface {
+	FindByParseStatus(ctx context.Context, status models.ParseStatus) ([]models.Movie, error)
+}
+
+// CollectionPartStatus is one part of a collection with its library state.
+type CollectionPartStatus struct {
+	models.CollectionPart
+	Owned    bool `json:"owned"`
+	Released bool `json:"released"`
+}
+
+// CollectionSummary is a collection with ownership joined in. The counts
+// cover released parts plus any owned ones; an announced sequel is listed but
+// never counted as missing.
+type CollectionSummary struct {
+	ID           int64                  `json:"id"`
+	Name         string                 `json:"name"`
+	Overview     string                 `json:"overview,omitempty"`
+	PosterPath   string                 `json:"poster_path,omitempty"`
+	BackdropPath string                 `json:"backdrop_path,omitempty"`
+	OwnedCount   int                    `json:"owned_count"`
+	TotalCount   int                    `json:"total_count"`
+	Parts        []CollectionPartStatus `json:"parts"`
+}
+
+// CollectionRequestSkip is a part RequestMissing did not request, and why.
+type CollectionRequestSkip struct {
+	TMDbID int64  `json:"tmdb_id"`
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// CollectionRequestResult is the outcome of RequestMissing.
+type CollectionRequestResult struct {
+	Requested []models.Request        `json:"requested"`
+	Skipped   []CollectionRequestSkip `json:"skipped"`
+}
+
+// CollectionService implements CollectionServiceInterface and CollectionRecorder.
+type CollectionService struct {
+	repo         repository.CollectionRepositoryInterface
+	tmdb         CollectionTMDbReader
+	availability AvailabilityServiceInterface
+	requests     RequestServiceInterface
+	movies       CollectionMovieLister
+	logger       *slog.Logger
+	now          func() time.Time
+
+	mu      sync.Mutex
+	syncing bool
+}
+
+// Compile-time verification.
+var (
+	_ CollectionServiceInterface = (*CollectionService)(nil)
+	_ CollectionRecorder         = (*CollectionService)(nil)
+)
+
+// NewCollectionService builds a CollectionService. Ownership goes through the
+// AvailabilityService and requests through the RequestService so both keep a
+// single implementation.
+func NewCollectionService(
+	repo repository.CollectionRepositoryInterface,
+	tmdbReader CollectionTMDbReader,
+	availability AvailabilityServiceInterface,
+	requests RequestServiceInterface,
+	movies CollectionMovieLister,
+	logger *slog.Logger,
+) *CollectionService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CollectionService{
+		repo: repo, tmdb: tmdbReader, availability: availability, requests: requests, movies: movies,
+		logger: logger.With("service", "collections"),
+		now:    time.Now,
+	}
+}
+
+// RecordMovieCollection stores the collection details belongs to, refreshing
+// its member list from TMDb. A standalone movie is a no-op.
+func (s *CollectionService) RecordMovieCollection(ctx context.Context, details *tmdb.MovieDetails) error {
+	if details == nil || details.BelongsToCollection == nil || details.BelongsToCollection.ID <= 0 {
+		return nil
+	}
+	ref := details.BelongsToCollection
+
+	collection, err := s.tmdb.GetCollection(ctx, ref.ID)
+	if err != nil {
+		return fmt.Errorf("tmdb get collection %d: %w", ref.ID, err)
+	}
+	record := collectionFromTMDb(collection)
+	if record.Name == "" {
+		record.Name = ref.Name
+	}
+	if err := s.repo.Upsert(ctx, record); err != nil {
+		return fmt.Errorf("store collection %d: %w", ref.ID, err)
+	}
+
+	s.logger.Debug("collection recorded",
+		"collection_id", record.ID, "name", record.Name, "parts", len(record.Parts), "movie_tmdb_id", details.ID)
+	return nil
+}
+
+// collectionFromTMDb converts a TMDb collection to the stored model, parts in
+// release order.
+func collectionFromTMDb(c *tmdb.CollectionDetails) *models.Collection {
+	record := &models.Collection{
+		ID:           int64(c.ID),
+		Name:         c.Name,
+		Overview:     c.Overview,
+		PosterPath:   derefPath(c.PosterPath),
+		BackdropPath: derefPath(c.BackdropPath),
+		Parts:        make([]models.CollectionPart, 0, len(c.Parts)),
+	}
+	for _, p := range c.Parts {
+		title := p.Title
+		if title == "" {
+			title = p.OriginalTitle
+		}
+		record.Parts = append(record.Parts, models.CollectionPart{
+			TMDbID:      int64(p.ID),
+			Title:       title,
+			ReleaseDate: p.ReleaseDate,
+			PosterPath:  derefPath(p.PosterPath),
+		})
+	}
+	models.SortCollectionParts(record.Parts)
+	return record
+}
+
+func derefPath(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
+func (s *CollectionService) ListCollections(ctx context.Context) ([]CollectionSummary, error) {
+	collections, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owned, err := s.ownedParts(ctx, collections)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]CollectionSummary, 0, len(collections))
+	for i := range collections {
+		summary := s.summarize(&collections[i], owned)
+		// A collection whose movies have all left the library is not part of
+		// the library any more.
+		if summary.OwnedCount == 0 {
+			continue
+		}
+		summaries = append(summaries, *summary)
+	}
+	return summaries, nil
+}
+
+func (s *CollectionService) GetCollection(ctx context.Context, id int64) (*CollectionSummary, error) {
+	collection, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	collections := []models.Collection{*collection}
+	owned, err := s.ownedParts(ctx, collections)
+	if err != nil {
+		return nil, err
+	}
+	return s.summarize(collection, owned), nil
+}
+
+// ownedParts answers ownership for every part of every collection in one
+// availability call.
+func (s *CollectionService) ownedParts(ctx context.Context, collections []models.Collection) (map[int64]bool, error) {
+	var ids []int64
+	for _, c := range collections {
+		for _, p := range c.Parts {
+			ids = append(ids, p.TMDbID)
+		}
+	}
+	ownedIDs, err := s.availability.CheckOwnedByType(ctx, models.RequestMediaTypeMovie, ids)
+	if err != nil {
+		return nil, fmt.Errorf("check owned parts: %w", err)
+	}
+	owned := make(map[int64]bool, len(ownedIDs))
+	for _, id := range ownedIDs {
+		owned[id] = true
+	}
+	return owned, nil
+}
+
+func (s *CollectionService) summarize(c *models.Collection, owned map[int64]bool) *CollectionSummary {
+	now := s.now()
+	summary := &CollectionSummary{
+		ID: c.ID, Name: c.Name, Overview: c.Overview,
+		PosterPath: c.PosterPath, BackdropPath: c.BackdropPath,
+		Parts: make([]CollectionPartStatus, 0, len(c.Parts)),
+	}
+	for _, p := range c.Parts {
+		part := CollectionPartStatus{CollectionPart: p, Owned: owned[p.TMDbID], Released: p.Released(now)}
+		if part.Released || part.Owned {
+			summary.TotalCount++
+		}
+		if part.Owned {
+			summary.OwnedCount++
+		}
+		summary.Parts = append(summary.Parts, part)
+	}
+	return summary
+}
+
+// RequestMissing requests every released, unowned part. One part failing
+// does not stop the others; each outcome is reported.
+func (s *CollectionService) RequestMissing(ctx context.Context, id int64) (*CollectionRequestResult, error) {
+	summary, err := s.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CollectionRequestResult{Requested: []models.Request{}, Skipped: []CollectionRequestSkip{}}
+	for _, part := range summary.Parts {
+		if part.Owned {
+			continue
+		}
+		skip := CollectionRequestSkip{TMDbID: part.TMDbID, Title: part.Title}
+		if !part.Released {
+			skip.Reason = CollectionSkipUnreleased
+			result.Skipped = append(result.Skipped, skip)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		request, err := s.requests.CreateRequest(ctx, CreateMediaRequestRequest{
+			TMDbID: part.TMDbID, MediaType: models.RequestMediaTypeMovie,
+		})
+		switch {
+		case err == nil:
+			result.Requested = append(result.Requested, *request)
+			continue
+		case errors.Is(err, repository.ErrRequestDuplicate):
+			skip.Reason = CollectionSkipAlreadyRequested
+		case errors.Is(err, ErrRequestAlreadyInLibrary):
+			skip.Reason = CollectionSkipInLibrary
+		default:
+			s.logger.Warn("failed to request collection part",
+				"collection_id", id, "tmdb_id", part.TMDbID, "error", err)
+			skip.Reason = CollectionSkipFailed
+		}
+		result.Skipped = append(result.Skipped, skip)
+	}
+
+	s.logger.Info("collection missing parts requested",
+		"collection_id", id, "requested", len(result.Requested), "skipped", len(result.Skipped))
+	return result, nil
+}
+
+func (s *CollectionService) StartSync() error {
+	s.mu.Lock()
+	if s.syncing {
+		s.mu.Unlock()
+		return ErrCollectionSyncRunning
+	}
+	s.syncing = true
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			s.syncing = false
+			s.mu.Unlock()
+		}()
+		if err := s.sync(context.Background()); err != nil {
+			s.logger.Error("collection sync failed", "error", err)
+		}
+	}()
+	return nil
+}
+
+// sync records the collection of every enriched movie with a TMDb id. Each
+// collection is fetched and stored once per sweep however many of its parts
+// the library holds.
+func (s *CollectionService) sync(ctx context.Context) error {
+	movies, err := s.movies.FindByParseStatus(ctx, models.ParseStatusSuccess)
+	if err != nil {
+		return fmt.Errorf("list enriched movies: %w", err)
+	}
+
+	seen := make(map[int]bool)
+	var recorded, failed int
+	for _, movie := range movies {
+		if !movie.TMDbID.Valid || movie.TMDbID.Int64 <= 0 {
+			continue
+		}
+		details, err := s.tmdb.GetMovieDetails(ctx, int(movie.TMDbID.Int64))
+		if err != nil {
+			failed++
+			s.logger.Warn("collection sync: movie details failed", "movie_id", movie.ID, "error", err)
+			continue
+		}
+		ref := details.BelongsToCollection
+		if ref == nil || seen[ref.ID] {
+			continue
+		}
+		seen[ref.ID] = true
+		if err := s.RecordMovieCollection(ctx, details); err != nil {
+			failed++
+			s.logger.Warn("collection sync: record failed", "collection_id", ref.ID, "error", err)
+			continue
+		}
+		recorded++
+	}
+
+	s.logger.Info("collection sync complete", "movies", len(movies), "collections", recorded, "failed", failed)
+	return nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/tmdb"
+)
+
+type fakeCollectionRepo struct {
+	rows map[int64]models.Collection
+}
+
+func (f *fakeCollectionRepo) Upsert(ctx context.Context, c *models.Collection) error {
+	if f.rows == nil {
+		f.rows = map[int64]models.Collection{}
+	}
+	f.rows[c.ID] = *c
+	return nil
+}
+
+func (f *fakeCollectionRepo) GetByID(ctx context.Context, id int64) (*models.Collection, error) {
+	c, ok := f.rows[id]
+	if !ok {
+		return nil, repository.ErrCollectionNotFound
+	}
+	return &c, nil
+}
+
+func (f *fakeCollectionRepo) List(ctx context.Context) ([]models.Collection, error) {
+	var list []models.Collection
+	for _, c := range f.rows {
+		list = append(list, c)
+	}
+	return list, nil
+}
+
+type fakeCollectionTMDb struct {
+	collections map[int]*tmdb.CollectionDetails
+	movies      map[int]*tmdb.MovieDetails
+	fetched     int
+}
+
+func (f *fakeCollectionTMDb) GetMovieDetails(ctx context.Context, movieID int) (*tmdb.MovieDetails, error) {
+	if d, ok := f.movies[movieID]; ok {
+		return d, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeCollectionTMDb) GetCollection(ctx context.Context, collectionID int) (*tmdb.CollectionDetails, error) {
+	f.fetched++
+	if c, ok := f.collections[collectionID]; ok {
+		return c, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type fakeCollectionOwnership struct{ owned []int64 }
+
+func (f *fakeCollectionOwnership) CheckOwned(ctx context.Context, ids []int64) ([]int64, error) {
+	return f.owned, nil
+}
+
+func (f *fakeCollectionOwnership) CheckOwnedByType(ctx context.Context, mediaType string, ids []int64) ([]int64, error) {
+	return f.owned, nil
+}
+
+type fakeCollectionRequester struct {
+	errs    map[int64]error
+	created []int64
+}
+
+func (f *fakeCollectionRequester) CreateRequest(ctx context.Context, req CreateMediaRequestRequest) (*models.Request, error) {
+	if err := f.errs[req.TMDbID]; err != nil {
+		return nil, err
+	}
+	f.created = append(f.created, req.TMDbID)
+	return &models.Request{TMDbID: req.TMDbID, MediaType: req.MediaType, Status: models.RequestStatusPending}, nil
+}
+
+func (f *fakeCollectionRequester) ListRequests(ctx context.Context) ([]models.Request, error) {
+	return nil, nil
+}
+
+func (f *fakeCollectionRequester) TVCoverage(ctx context.Context, tmdbID int64) (*RequestCoverage, error) {
+	return nil, nil
+}
+
+type enrichedMovies []models.Movie
+
+func (m enrichedMovies) FindByParseStatus(ctx context.Context, status models.ParseStatus) ([]models.Movie, error) {
+	return m, nil
+}
+
+func hpCollection() models.Collection {
+	return models.Collection{
+		ID:   1241,
+		Name: "哈利波特（系列）",
+		Parts: []models.CollectionPart{
+			{TMDbID: 671, Title: "神秘的魔法石", ReleaseDate: "2001-11-16"},
+			{TMDbID: 672, Title: "消失的密室", ReleaseDate: "2002-11-13"},
+			{TMDbID: 673, Title: "阿茲卡班的逃犯", ReleaseDate: "2004-05-31"},
+			{TMDbID: 9999, Title: "Announced"},
+		},
+	}
+}
+
+func newTestCollectionService(repo *fakeCollectionRepo, reader *fakeCollectionTMDb, owned []int64, requester *fakeCollectionRequester) *CollectionService {
+	svc := NewCollectionService(repo, reader, &fakeCollectionOwnership{owned: owned}, requester, nil, nil)
+	svc.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return svc
+}
+
+func TestCollectionService_RecordMovieCollection(t *testing.T) {
+	poster := "/hp.jpg"
+	reader := &fakeCollectionTMDb{collections: map[int]*tmdb.CollectionDetails{
+		1241: {ID: 1241, Name: "哈利波特（系列）", PosterPath: &poster, Parts: []tmdb.Movie{
+			{ID: 672, Title: "消失的密室", ReleaseDate: "2002-11-13"},
+			{ID: 671, OriginalTitle: "Harry Potter and the Philosopher's Stone", ReleaseDate: "2001-11-16"},
+		}},
+	}}
+	repo := &fakeCollectionRepo{}
+	svc := newTestCollectionService(repo, reader, nil, nil)
+
+	t.Run("standalone movie is a no-op", func(t *testing.T) {
+		require.NoError(t, svc.RecordMovieCollection(context.Background(), &tmdb.MovieDetails{Movie: tmdb.Movie{ID: 1}}))
+		assert.Zero(t, reader.fetched)
+	})
+
+	t.Run("stores the collection with parts in release order", func(t *testing.T) {
+		details := &tmdb.MovieDetails{
+			Movie:               tmdb.Movie{ID: 671},
+			BelongsToCollection: &tmdb.CollectionRef{ID: 1241, Name: "Harry Potter Collection"},
+		}
+		require.NoError(t, svc.RecordMovieCollection(context.Background(), details))
+
+		stored := repo.rows[1241]
+		assert.Equal(t, "哈利波特（系列）", stored.Name)
+		assert.Equal(t, "/hp.jpg", stored.PosterPath)
+		require.Len(t, stored.Parts, 2)
+		assert.Equal(t, int64(671), stored.Parts[0].TMDbID)
+		assert.Equal(t, "Harry Potter and the Philosopher's Stone", stored.Parts[0].Title, "falls back to the original title")
+	})
+
+	t.Run("TMDb failure is returned", func(t *testing.T) {
+		details := &tmdb.MovieDetails{BelongsToCollection: &tmdb.CollectionRef{ID: 404}}
+		assert.Error(t, svc.RecordMovieCollection(context.Background(), details))
+	})
+}
+
+func TestCollectionService_ListCollections(t *testing.T) {
+	repo := &fakeCollectionRepo{}
+	require.NoError(t, repo.Upsert(context.Background(), &models.Collection{ID: 1, Name: "Nothing owned",
+		Parts: []models.CollectionPart{{TMDbID: 11, ReleaseDate: "1990-01-01"}}}))
+	hp := hpCollection()
+	require.NoError(t, repo.Upsert(context.Background(), &hp))
+	svc := newTestCollectionService(repo, &fakeCollectionTMDb{}, []int64{671, 673}, nil)
+
+	list, err := svc.ListCollections(context.Background())
+	require.NoError(t, err)
+	require.Len(t, list, 1, "collections with no owned part are left out")
+
+	got := list[0]
+	assert.Equal(t, 2, got.OwnedCount)
+	assert.Equal(t, 3, got.TotalCount, "the announced part is listed but not counted")
+	require.Len(t, got.Parts, 4)
+	assert.True(t, got.Parts[0].Owned)
+	assert.False(t, got.Parts[1].Owned)
+	assert.False(t, got.Parts[3].Released)
+}
+
+func TestCollectionService_RequestMissing(t *testing.T) {
+	repo := &fakeCollectionRepo{}
+	hp := hpCollection()
+	hp.Parts = append(hp.Parts,
+		models.CollectionPart{TMDbID: 674, Title: "火盃的考驗", ReleaseDate: "2005-11-16"},
+		models.CollectionPart{TMDbID: 675, Title: "鳳凰會的密令", ReleaseDate: "2007-06-28"},
+	)
+	require.NoError(t, repo.Upsert(context.Background(), &hp))
+	requester := &fakeCollectionRequester{errs: map[int64]error{
+		674: repository.ErrRequestDuplicate,
+		675: errors.New("tmdb down"),
+	}}
+	svc := newTestCollectionService(repo, &fakeCollectionTMDb{}, []int64{671}, requester)
+
+	result, err := svc.RequestMissing(context.Background(), 1241)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{672, 673}, requester.created)
+	require.Len(t, result.Requested, 2)
+	reasons := map[int64]string{}
+	for _, s := range result.Skipped {
+		reasons[s.TMDbID] = s.Reason
+	}
+	assert.Equal(t, map[int64]string{
+		9999: CollectionSkipUnreleased,
+		674:  CollectionSkipAlreadyRequested,
+		675:  CollectionSkipFailed,
+	}, reasons)
+
+	_, err = svc.RequestMissing(context.Background(), 1)
+	assert.ErrorIs(t, err, repository.ErrCollectionNotFound)
+}
+
+func TestCollectionService_SyncFetchesEachCollectionOnce(t *testing.T) {
+	ref := &tmdb.CollectionRef{ID: 1241}
+	reader := &fakeCollectionTMDb{
+		collections: map[int]*tmdb.CollectionDetails{1241: {ID: 1241, Name: "哈利波特（系列）"}},
+		movies: map[int]*tmdb.MovieDetails{
+			671: {Movie: tmdb.Movie{ID: 671}, BelongsToCollection: ref},
+			672: {Movie: tmdb.Movie{ID: 672}, BelongsToCollection: ref},
+			550: {Movie: tmdb.Movie{ID: 550}},
+		},
+	}
+	repo := &fakeCollectionRepo{}
+	svc := newTestCollectionService(repo, reader, nil, nil)
+	svc.movies = enrichedMovies{
+		{ID: "a", TMDbID: models.NewNullInt64(671)},
+		{ID: "b", TMDbID: models.NewNullInt64(672)},
+		{ID: "c", TMDbID: models.NewNullInt64(550)},
+		{ID: "d"},
+	}
+
+	require.NoError(t, svc.sync(context.Background()))
+	assert.Equal(t, 1, reader.fetched)
+	assert.Contains(t, repo.rows, int64(1241))
+}
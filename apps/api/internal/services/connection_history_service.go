@@ -38,13 +38,18 @@ func NewConnectionHistoryService(repo repository.ConnectionHistoryRepositoryInte
 // ValidServiceNames returns the set of known service names for validation.
 func ValidServiceNames() map[string]bool {
 	return map[string]bool{
-		string(models.ServiceNameTMDb):        true,
-		string(models.ServiceNameDouban):      true,
-		string(models.ServiceNameWikipedia):   true,
-		string(models.ServiceNameAI):          true,
-		string(models.ServiceNameQBittorrent): true,
-		string(models.ServiceNameRadarr):      true,
-		string(models.ServiceNameSonarr):      true,
+		string(models.ServiceNameTMDb):         true,
+		string(models.ServiceNameDouban):       true,
+		string(models.ServiceNameWikipedia):    true,
+		string(models.ServiceNameAI):           true,
+		string(models.ServiceNameQBittorrent):  true,
+		string(models.ServiceNameRadarr):       true,
+		string(models.ServiceNameSonarr):       true,
+		string(models.ServiceNameJellyfin):     true,
+		string(models.ServiceNameEmby):         true,
+		string(models.ServiceNamePlex):         true,
+		string(models.ServiceNameTransmission): true,
+		string(models.ServiceNameDeluge):       true,
 	}
 }
 
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+
+	"github.com/vido/api/internal/deluge"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/qbittorrent"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/secrets"
+	"github.com/vido/api/internal/transmission"
+)
+
+// Download client types selectable in settings.
+const (
+	DownloadClientQBittorrent  = "qbittorrent"
+	DownloadClientTransmission = "transmission"
+	DownloadClientDeluge       = "deluge"
+)
+
+// SettingDownloadClientActive names the client the download subsystem
+// talks to. Unset means qBittorrent, the only client before there was a
+// choice.
+const SettingDownloadClientActive = "download_client.active"
+
+// downloadClientTypes lists the selectable clients in display order.
+var downloadClientTypes = []string{DownloadClientQBittorrent, DownloadClientTransmission, DownloadClientDeluge}
+
+var downloadClientDisplayNames = map[string]string{
+	DownloadClientQBittorrent:  "qBittorrent",
+	DownloadClientTransmission: "Transmission",
+	DownloadClientDeluge:       "Deluge",
+}
+
+// ErrDownloadClientUnsupported is returned for an action the active
+// download client has no equivalent of (categories, tags, file priorities
+// and speed limits are qBittorrent-only).
+var ErrDownloadClientUnsupported = errors.New("not supported by the active download client")
+
+// DownloadClient is what the download subsystem needs from a torrent client.
+// *qbittorrent.Client, *transmission.Client and *deluge.Client satisfy it.
+// All three speak qbittorrent's Torrent model, the downloads API's wire shape
+// [@contract-v1], and report failures as *qbittorrent.ConnectionError so the
+// handlers' error contract holds whichever client is active.
+type DownloadClient interface {
+	TestConnection(ctx context.Context) (*qbittorrent.VersionInfo, error)
+	GetTorrents(ctx context.Context, opts *qbittorrent.ListTorrentsOptions) ([]qbittorrent.Torrent, error)
+	GetTorrentDetails(ctx context.Context, hash string) (*qbittorrent.TorrentDetails, error)
+	PauseTorrents(ctx context.Context, hashes []string) error
+	ResumeTorrents(ctx context.Context, hashes []string) error
+	DeleteTorrents(ctx context.Context, hashes []string, deleteFiles bool) error
+	AddTorrent(ctx context.Context, opts qbittorrent.AddTorrentOptions) error
+}
+
+var (
+	_ DownloadClient = (*qbittorrent.Client)(nil)
+	_ DownloadClient = (*transmission.Client)(nil)
+	_ DownloadClient = (*deluge.Client)(nil)
+)
+
+// DownloadClientSettings is one client's connection. Password is write-only:
+// it is never returned, HasPassword says whether one is stored, and saving
+// with an empty Password keeps the stored one. Deluge ignores Username.
+type DownloadClientSettings struct {
+	Host        string `json:"host"`
+	Username    string `json:"username"`
+	Password    string `json:"password,omitempty"`
+	BasePath    string `json:"base_path"`
+	HasPassword bool   `json:"has_password"`
+}
+
+// DownloadClientInfo summarises one client for the settings page.
+type DownloadClientInfo struct {
+	Type        string `json:"type"`
+	DisplayName string `json:"display_name"`
+	Active      bool   `json:"active"`
+	Configured  bool   `json:"configured"`
+	Host        string `json:"host,omitempty"`
+}
+
+// DownloadClientServiceInterface manages the download client settings.
+type DownloadClientServiceInterface interface {
+	ListClients(ctx context.Context) ([]DownloadClientInfo, error)
+	GetActiveType(ctx context.Context) string
+	SetActiveType(ctx context.Context, clientType string) error
+	GetClientSettings(ctx context.Context, clientType string) (*DownloadClientSettings, error)
+	SaveClientSettings(ctx context.Context, clientType string, settings DownloadClientSettings) error
+	TestClient(ctx context.Context, clientType string, settings *DownloadClientSettings) (*qbittorrent.VersionInfo, error)
+}
+
+// DownloadClientService stores the Transmission and Deluge connections next
+// to qBittorrent's, under the same "<type>.host" style keys with the
+// password in the secrets store, and resolves the active client for
+// DownloadService. qBittorrent's own settings stay with QBittorrentService,
+// so /settings/qbittorrent and the setup wizard keep working unchanged.
+type DownloadClientService struct {
+	settingsRepo repository.SettingsRepositoryInterface
+	secrets      secrets.SecretsServiceInterface
+	qbService    QBittorrentServiceInterface
+	logger       *slog.Logger
+
+	mu        sync.Mutex
+	cached    DownloadClient
+	cachedKey string
+}
+
+// NewDownloadClientService creates a new DownloadClientService.
+func NewDownloadClientService(
+	settingsRepo repository.SettingsRepositoryInterface,
+	secretsService secrets.SecretsServiceInterface,
+	qbService QBittorrentServiceInterface,
+	logger *slog.Logger,
+) *DownloadClientService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DownloadClientService{
+		settingsRepo: settingsRepo,
+		secrets:      secretsService,
+		qbService:    qbService,
+		logger:       logger,
+	}
+}
+
+func validateClientType(clientType string) error {
+	if _, ok := downloadClientDisplayNames[clientType]; !ok {
+		return &models.ValidationError{Field: "type", Message: "type must be one of qbittorrent, transmission, deluge"}
+	}
+	return nil
+}
+
+// GetActiveType returns the selected client type.
+func (s *DownloadClientService) GetActiveType(ctx context.Context) string {
+	active, err := s.settingsRepo.GetString(ctx, SettingDownloadClientActive)
+	if err != nil || validateClientType(active) != nil {
+		return DownloadClientQBittorrent
+	}
+	return active
+}
+
+// SetActiveType selects the client the download subsystem uses. It may be
+// selected before it is configured; downloads then report it as not
+// configured, as they do for a fresh install.
+func (s *DownloadClientService) SetActiveType(ctx context.Context, clientType string) error {
+	if err := validateClientType(clientType); err != nil {
+		return err
+	}
+	if err := s.settingsRepo.SetString(ctx, SettingDownloadClientActive, clientType); err != nil {
+		return fmt.Errorf("save active download client: %w", err)
+	}
+	s.logger.Info("Active download client changed", "type", clientType)
+	return nil
+}
+
+// ListClients summarises every client.
+func (s *DownloadClientService) ListClients(ctx context.Context) ([]DownloadClientInfo, error) {
+	active := s.GetActiveType(ctx)
+	infos := make([]DownloadClientInfo, 0, len(downloadClientTypes))
+	for _, clientType := range downloadClientTypes {
+		settings, err := s.loadSettings(ctx, clientType)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, DownloadClientInfo{
+			Type:        clientType,
+			DisplayName: downloadClientDisplayNames[clientType],
+			Active:      clientType == active,
+			Configured:  settings.Host != "",
+			Host:        settings.Host,
+		})
+	}
+	return infos, nil
+}
+
+// GetClientSettings returns one client's settings without its password.
+func (s *DownloadClientService) GetClientSettings(ctx context.Context, clientType string) (*DownloadClientSettings, error) {
+	if err := validateClientType(clientType); err != nil {
+		return nil, err
+	}
+	settings, err := s.loadSettings(ctx, clientType)
+	if err != nil {
+		return nil, err
+	}
+	settings.Password = ""
+	return settings, nil
+}
+
+// loadSettings reads one client's settings including the decrypted
+// password.
+func (s *DownloadClientService) loadSettings(ctx context.Context, clientType string) (*DownloadClientSettings, error) {
+	if clientType == DownloadClientQBittorrent {
+		config, err := s.qbService.GetConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &DownloadClientSettings{
+			Host:        config.Host,
+			Username:    config.Username,
+			Password:    config.Password,
+			BasePath:    config.BasePath,
+			HasPassword: config.Password != "",
+		}, nil
+	}
+
+	host, _ := s.settingsRepo.GetString(ctx, clientType+".host")
+	username, _ := s.settingsRepo.GetString(ctx, clientType+".username")
+	basePath, _ := s.settingsRepo.GetString(ctx, clientType+".base_path")
+	settings := &DownloadClientSettings{Host: host, Username: username, BasePath: basePath}
+	if exists, _ := s.secrets.Exists(ctx, clientType+".password"); exists {
+		password, err := s.secrets.Retrieve(ctx, clientType+".password")
+		if err != nil {
+			return nil, fmt.Errorf("decrypt %s password: %w", clientType, err)
+		}
+		settings.Password = password
+		settings.HasPassword = password != ""
+	}
+	return settings, nil
+}
+
+// SaveClientSettings stores one client's settings.
+func (s *DownloadClientService) SaveClientSettings(ctx context.Context, clientType string, settings DownloadClientSettings) error {
+	if err := validateClientType(clientType); err != nil {
+		return err
+	}
+	if u, err := url.Parse(settings.Host); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &models.ValidationError{Field: "host", Message: "host must be an http or https URL"}
+	}
+
+	if clientType == DownloadClientQBittorrent {
+		return s.qbService.SaveConfig(ctx, &qbittorrent.Config{
+			Host:     settings.Host,
+			Username: settings.Username,
+			Password: settings.Password,
+			BasePath: settings.BasePath,
+		})
+	}
+
+	for key, value := range map[string]string{
+		clientType + ".host":      settings.Host,
+		clientType + ".username":  settings.Username,
+		clientType + ".base_path": settings.BasePath,
+	} {
+		if err := s.settingsRepo.SetString(ctx, key, value); err != nil {
+			return fmt.Errorf("save %s: %w", key, err)
+		}
+	}
+	if settings.Password != "" {
+		if err := s.secrets.Store(ctx, clientType+".password", settings.Password); err != nil {
+			return fmt.Errorf("encrypt %s password: %w", clientType, err)
+		}
+	}
+	s.logger.Info("Download client settings saved", "type", clientType, "host", settings.Host)
+	return nil
+}
+
+// TestClient connects to a client and returns its version. With nil
+// settings the stored ones are tested; otherwise the given ones are, with
+// the stored password filling in an empty one so an edit can be tested
+// without retyping it.
+func (s *DownloadClientService) TestClient(ctx context.Context, clientType string, settings *DownloadClientSettings) (*qbittorrent.VersionInfo, error) {
+	if err := validateClientType(clientType); err != nil {
+		return nil, err
+	}
+	stored, err := s.loadSettings(ctx, clientType)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = stored
+	} else if settings.Password == "" {
+		settings.Password = stored.Password
+	}
+	if settings.Host == "" {
+		return nil, notConfiguredError(clientType)
+	}
+	return newDownloadClient(clientType, settings).TestConnection(ctx)
+}
+
+// ActiveClient returns a client for the selected type, reusing the previous
+// one while its settings are unchanged (qBittorrent's session cookie and
+// Transmission's session id live on the client).
+func (s *DownloadClientService) ActiveClient(ctx context.Context) (DownloadClient, error) {
+	clientType := s.GetActiveType(ctx)
+	settings, err := s.loadSettings(ctx, clientType)
+	if err != nil {
+		return nil, fmt.Errorf("get %s config: %w", clientType, err)
+	}
+	if settings.Host == "" {
+		return nil, notConfiguredError(clientType)
+	}
+
+	key := clientType + "|" + settings.Host + "|" + settings.Username + "|" + settings.Password + "|" + settings.BasePath
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached == nil || s.cachedKey != key {
+		s.cached = newDownloadClient(clientType, settings)
+		s.cachedKey = key
+	}
+	return s.cached, nil
+}
+
+func notConfiguredError(clientType string) error {
+	return &qbittorrent.ConnectionError{
+		Code:    qbittorrent.ErrCodeNotConfigured,
+		Message: downloadClientDisplayNames[clientType] + " not configured",
+	}
+}
+
+func newDownloadClient(clientType string, settings *DownloadClientSettings) DownloadClient {
+	switch clientType {
+	case DownloadClientTransmission:
+		return transmission.NewClient(&transmission.Config{
+			Host:     settings.Host,
+			Username: settings.Username,
+			Password: settings.Password,
+			BasePath: settings.BasePath,
+		})
+	case DownloadClientDeluge:
+		return deluge.NewClient(&deluge.Config{
+			Host:     settings.Host,
+			Password: settings.Password,
+			BasePath: settings.BasePath,
+		})
+	default:
+		return qbittorrent.NewClient(&qbittorrent.Config{
+			Host:     settings.Host,
+			Username: settings.Username,
+			Password: settings.Password,
+			BasePath: settings.BasePath,
+		})
+	}
+}
+
+// Compile-time interface verification
+var _ DownloadClientServiceInterface = (*DownloadClientService)(nil)
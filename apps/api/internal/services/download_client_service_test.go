@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/qbittorrent"
+	"github.com/vido/api/internal/transmission"
+)
+
+func newTestDownloadClientService() *DownloadClientService {
+	repo := newFakeDVRSettingsRepo()
+	secrets := &fakeSecrets{}
+	return NewDownloadClientService(repo, secrets, NewQBittorrentService(repo, secrets), slog.Default())
+}
+
+func TestDownloadClientService_Settings(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestDownloadClientService()
+
+	assert.Equal(t, DownloadClientQBittorrent, svc.GetActiveType(ctx), "qBittorrent until another client is selected")
+
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, svc.SetActiveType(ctx, "rtorrent"), &validationErr)
+	require.ErrorAs(t, svc.SaveClientSettings(ctx, DownloadClientDeluge, DownloadClientSettings{Host: "nas:8112"}), &validationErr)
+	assert.Equal(t, "host", validationErr.Field)
+
+	require.NoError(t, svc.SaveClientSettings(ctx, DownloadClientTransmission, DownloadClientSettings{
+		Host: "http://nas:9091", Username: "admin", Password: "secret",
+	}))
+	require.NoError(t, svc.SaveClientSettings(ctx, DownloadClientTransmission, DownloadClientSettings{
+		Host: "http://nas:9091", Username: "admin",
+	}))
+	settings, err := svc.GetClientSettings(ctx, DownloadClientTransmission)
+	require.NoError(t, err)
+	assert.Empty(t, settings.Password, "the password is never returned")
+	assert.True(t, settings.HasPassword, "saving without a password keeps the stored one")
+
+	require.NoError(t, svc.SetActiveType(ctx, DownloadClientTransmission))
+	clients, err := svc.ListClients(ctx)
+	require.NoError(t, err)
+	require.Len(t, clients, 3)
+	assert.Equal(t, DownloadClientInfo{Type: "qbittorrent", DisplayName: "qBittorrent"}, clients[0])
+	assert.Equal(t, DownloadClientInfo{Type: "transmission", DisplayName: "Transmission", Active: true, Configured: true, Host: "http://nas:9091"}, clients[1])
+	assert.False(t, clients[2].Configured)
+}
+
+func TestDownloadClientService_ActiveClient(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestDownloadClientService()
+	require.NoError(t, svc.SetActiveType(ctx, DownloadClientDeluge))
+
+	_, err := svc.ActiveClient(ctx)
+	var connErr *qbittorrent.ConnectionError
+	require.ErrorAs(t, err, &connErr)
+	assert.Equal(t, qbittorrent.ErrCodeNotConfigured, connErr.Code)
+	assert.Equal(t, "Deluge not configured", connErr.Message)
+
+	require.NoError(t, svc.SetActiveType(ctx, DownloadClientTransmission))
+	require.NoError(t, svc.SaveClientSettings(ctx, DownloadClientTransmission, DownloadClientSettings{Host: "http://nas:9091"}))
+	first, err := svc.ActiveClient(ctx)
+	require.NoError(t, err)
+	assert.IsType(t, &transmission.Client{}, first)
+	second, err := svc.ActiveClient(ctx)
+	require.NoError(t, err)
+	assert.Same(t, first, second, "the client is reused while its settings are unchanged")
+
+	require.NoError(t, svc.SaveClientSettings(ctx, DownloadClientTransmission, DownloadClientSettings{Host: "http://nas:9092"}))
+	third, err := svc.ActiveClient(ctx)
+	require.NoError(t, err)
+	assert.NotSame(t, first, third)
+}
+
+func TestDownloadClientService_TestClientUsesStoredPassword(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"result":"success","arguments":{"version":"4.0.5","rpc-version":17}}`)
+	}))
+	defer server.Close()
+
+	svc := newTestDownloadClientService()
+	_, err := svc.TestClient(ctx, DownloadClientTransmission, nil)
+	var connErr *qbittorrent.ConnectionError
+	require.ErrorAs(t, err, &connErr)
+	assert.Equal(t, qbittorrent.ErrCodeNotConfigured, connErr.Code)
+
+	require.NoError(t, svc.SaveClientSettings(ctx, DownloadClientTransmission, DownloadClientSettings{
+		Host: "http://stored:9091", Username: "admin", Password: "secret",
+	}))
+	info, err := svc.TestClient(ctx, DownloadClientTransmission, &DownloadClientSettings{Host: server.URL, Username: "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, "4.0.5", info.AppVersion)
+}
+
+// stubClientResolver hands DownloadService a fixed client.
+type stubClientResolver struct{ client DownloadClient }
+
+func (r stubClientResolver) ActiveClient(ctx context.Context) (DownloadClient, error) {
+	return r.client, nil
+}
+
+// listOnlyClient is a non-qBittorrent DownloadClient serving a fixed list.
+type listOnlyClient struct {
+	DownloadClient
+	torrents []qbittorrent.Torrent
+}
+
+func (c listOnlyClient) GetTorrents(ctx context.Context, opts *qbittorrent.ListTorrentsOptions) ([]qbittorrent.Torrent, error) {
+	return c.torrents, nil
+}
+
+func TestDownloadService_FollowsActiveClient(t *testing.T) {
+	ctx := context.Background()
+	svc := NewDownloadService(nil, slog.Default())
+	svc.SetClientResolver(stubClientResolver{client: listOnlyClient{
+		torrents: []qbittorrent.Torrent{{Hash: "aaaa", Status: qbittorrent.StatusDownloading}},
+	}})
+
+	torrents, err := svc.GetAllDownloads(ctx, "all", "", "")
+	require.NoError(t, err)
+	require.Len(t, torrents, 1)
+	assert.Equal(t, "aaaa", torrents[0].Hash)
+
+	_, err = svc.ListCategories(ctx)
+	assert.ErrorIs(t, err, ErrDownloadClientUnsupported, "categories are qBittorrent-only")
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+
+	"github.com/vido/api/internal/qbittorrent"
+)
+
+// DownloadControlServiceInterface is the download front-end's write side:
+// adding torrents and organising them with categories, tags, file
+// priorities and speed limits. It is kept apart from DownloadServiceInterface
+// so the monitoring consumers (the progress broadcaster, the parse worker)
+// do not grow a dozen methods they never call.
+type DownloadControlServiceInterface interface {
+	AddTorrent(ctx context.Context, opts qbittorrent.AddTorrentOptions) error
+
+	ListCategories(ctx context.Context) ([]qbittorrent.Category, error)
+	CreateCategory(ctx context.Context, name, savePath string) error
+	RemoveCategory(ctx context.Context, name string) error
+	SetDownloadCategory(ctx context.Context, hash, category string) error
+
+	ListTags(ctx context.Context) ([]string, error)
+	CreateTags(ctx context.Context, tags []string) error
+	DeleteTag(ctx context.Context, tag string) error
+	AddDownloadTags(ctx context.Context, hash string, tags []string) error
+	RemoveDownloadTags(ctx context.Context, hash string, tags []string) error
+
+	GetDownloadFiles(ctx context.Context, hash string) ([]qbittorrent.TorrentFile, error)
+	SetFilePriority(ctx context.Context, hash string, indexes []int, priority int) error
+
+	GetSpeedLimits(ctx context.Context) (*qbittorrent.SpeedLimits, error)
+	SetSpeedLimits(ctx context.Context, limits qbittorrent.SpeedLimits) error
+	SetDownloadSpeedLimits(ctx context.Context, hash string, downloadLimit, uploadLimit int64) error
+}
+
+// withClient runs one control action against the configured client, logging
+// failures the way the pause/resume/remove actions do.
+func (s *DownloadService) withClient(ctx context.Context, action string, fn func(*qbittorrent.Client) error, attrs ...any) error {
+	client, err := s.clientForAction(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(client); err != nil {
+		s.logger.Error("Failed to "+action, append([]any{"error", err}, attrs...)...)
+		return err
+	}
+	return nil
+}
+
+// ListCategories returns qBittorrent's categories.
+func (s *DownloadService) ListCategories(ctx context.Context) ([]qbittorrent.Category, error) {
+	var categories []qbittorrent.Category
+	err := s.withClient(ctx, "list categories", func(c *qbittorrent.Client) (err error) {
+		categories, err = c.GetCategories(ctx)
+		return err
+	})
+	return categories, err
+}
+
+// CreateCategory adds a category with an optional save path.
+func (s *DownloadService) CreateCategory(ctx context.Context, name, savePath string) error {
+	return s.withClient(ctx, "create category", func(c *qbittorrent.Client) error {
+		return c.CreateCategory(ctx, name, savePath)
+	}, "category", name)
+}
+
+// RemoveCategory deletes a category, leaving its torrents uncategorised.
+func (s *DownloadService) RemoveCategory(ctx context.Context, name string) error {
+	return s.withClient(ctx, "remove category", func(c *qbittorrent.Client) error {
+		return c.RemoveCategories(ctx, []string{name})
+	}, "category", name)
+}
+
+// SetDownloadCategory moves a torrent into category ("" clears it).
+func (s *DownloadService) SetDownloadCategory(ctx context.Context, hash, category string) error {
+	return s.withClient(ctx, "set download category", func(c *qbittorrent.Client) error {
+		return c.SetTorrentCategory(ctx, []string{hash}, category)
+	}, "hash", hash, "category", category)
+}
+
+// ListTags returns every tag qBittorrent knows.
+func (s *DownloadService) ListTags(ctx context.Context) ([]string, error) {
+	var tags []string
+	err := s.withClient(ctx, "list tags", func(c *qbittorrent.Client) (err error) {
+		tags, err = c.GetTags(ctx)
+		return err
+	})
+	return tags, err
+}
+
+// CreateTags registers tags.
+func (s *DownloadService) CreateTags(ctx context.Context, tags []string) error {
+	return s.withClient(ctx, "create tags", func(c *qbittorrent.Client) error {
+		return c.CreateTags(ctx, tags)
+	}, "tags", tags)
+}
+
+// DeleteTag removes a tag everywhere.
+func (s *DownloadService) DeleteTag(ctx context.Context, tag string) error {
+	return s.withClient(ctx, "delete tag", func(c *qbittorrent.Client) error {
+		return c.DeleteTags(ctx, []string{tag})
+	}, "tag", tag)
+}
+
+// AddDownloadTags attaches tags to a torrent.
+func (s *DownloadService) AddDownloadTags(ctx context.Context, hash string, tags []string) error {
+	return s.withClient(ctx, "add download tags", func(c *qbittorrent.Client) error {
+		return c.AddTorrentTags(ctx, []string{hash}, tags)
+	}, "hash", hash, "tags", tags)
+}
+
+// RemoveDownloadTags detaches tags from a torrent.
+func (s *DownloadService) RemoveDownloadTags(ctx context.Context, hash string, tags []string) error {
+	return s.withClient(ctx, "remove download tags", func(c *qbittorrent.Client) error {
+		return c.RemoveTorrentTags(ctx, []string{hash}, tags)
+	}, "hash", hash, "tags", tags)
+}
+
+// GetDownloadFiles lists a torrent's files with their priorities.
+func (s *DownloadService) GetDownloadFiles(ctx context.Context, hash string) ([]qbittorrent.TorrentFile, error) {
+	var files []qbittorrent.TorrentFile
+	err := s.withClient(ctx, "get download files", func(c *qbittorrent.Client) (err error) {
+		files, err = c.GetTorrentFiles(ctx, hash)
+		return err
+	}, "hash", hash)
+	return files, err
+}
+
+// SetFilePriority sets the priority of some of a torrent's files;
+// qbittorrent.FilePriorityDoNotDownload skips them.
+func (s *DownloadService) SetFilePriority(ctx context.Context, hash string, indexes []int, priority int) error {
+	return s.withClient(ctx, "set file priority", func(c *qbittorrent.Client) error {
+		return c.SetFilePriority(ctx, hash, indexes, priority)
+	}, "hash", hash, "priority", priority)
+}
+
+// GetSpeedLimits returns the global speed limits.
+func (s *DownloadService) GetSpeedLimits(ctx context.Context) (*qbittorrent.SpeedLimits, error) {
+	var limits *qbittorrent.SpeedLimits
+	err := s.withClient(ctx, "get speed limits", func(c *qbittorrent.Client) (err error) {
+		limits, err = c.GetSpeedLimits(ctx)
+		return err
+	})
+	return limits, err
+}
+
+// SetSpeedLimits replaces the global speed limits.
+func (s *DownloadService) SetSpeedLimits(ctx context.Context, limits qbittorrent.SpeedLimits) error {
+	return s.withClient(ctx, "set speed limits", func(c *qbittorrent.Client) error {
+		return c.SetSpeedLimits(ctx, limits)
+	})
+}
+
+// SetDownloadSpeedLimits caps one torrent's transfer rates (0 = no cap).
+func (s *DownloadService) SetDownloadSpeedLimits(ctx context.Context, hash string, downloadLimit, uploadLimit int64) error {
+	return s.withClient(ctx, "set download speed limits", func(c *qbittorrent.Client) error {
+		return c.SetTorrentSpeedLimits(ctx, []string{hash}, downloadLimit, uploadLimit)
+	}, "hash", hash)
+}
+
+// Compile-time interface verification
+var _ DownloadControlServiceInterface = (*DownloadService)(nil)
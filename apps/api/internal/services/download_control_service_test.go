@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/qbittorrent"
+)
+
+func TestDownloadService_ControlActions(t *testing.T) {
+	var tagged, prio string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "s"})
+		fmt.Fprint(w, "Ok.")
+	})
+	mux.HandleFunc("/api/v2/torrents/addTags", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		tagged = r.FormValue("hashes") + ":" + r.FormValue("tags")
+	})
+	mux.HandleFunc("/api/v2/torrents/filePrio", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	mux.HandleFunc("/api/v2/torrents/files", func(w http.ResponseWriter, r *http.Request) {
+		prio = r.URL.Query().Get("hash")
+		fmt.Fprint(w, `[{"index":0,"name":"a.mkv","size":1,"priority":1}]`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mockQB := new(MockQBServiceForDownload)
+	mockQB.On("GetConfig", mock.Anything).Return(&qbittorrent.Config{Host: server.URL, Username: "admin", Password: "password"}, nil)
+	service := newTestDownloadService(mockQB)
+	ctx := context.Background()
+
+	require.NoError(t, service.AddDownloadTags(ctx, "h1", []string{"vido", "4k"}))
+	assert.Equal(t, "h1:vido,4k", tagged)
+
+	files, err := service.GetDownloadFiles(ctx, "h1")
+	require.NoError(t, err)
+	assert.Equal(t, "h1", prio)
+	assert.Len(t, files, 1)
+
+	t.Run("qBittorrent refusals keep their code", func(t *testing.T) {
+		err := service.SetFilePriority(ctx, "h1", []int{0}, qbittorrent.FilePriorityDoNotDownload)
+		var connErr *qbittorrent.ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Equal(t, qbittorrent.ErrCodeRequestRejected, connErr.Code)
+	})
+}
+
+func TestDownloadService_ControlActions_NotConfigured(t *testing.T) {
+	mockQB := new(MockQBServiceForDownload)
+	mockQB.On("GetConfig", mock.Anything).Return(&qbittorrent.Config{Host: ""}, nil)
+	service := newTestDownloadService(mockQB)
+
+	_, err := service.ListCategories(context.Background())
+	var connErr *qbittorrent.ConnectionError
+	require.ErrorAs(t, err, &connErr)
+	assert.Equal(t, qbittorrent.ErrCodeNotConfigured, connErr.Code)
+}
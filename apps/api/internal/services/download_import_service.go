@@ -0,0 +1,614 @@
+// Package services — DownloadImportService.
+//
+// The post-download importer. Finished torrents stay where the download
+// client put them, under their release names, and keep seeding; the importer
+// hardlinks the video and its sidecars into the right library, named by the
+// library's organize template, and hands the new paths straight to the
+// scanner so the title shows up without waiting for the next scan.
+//
+// Only Vido's own downloads are imported: torrents tagged with a request by
+// the built-in fulfilment path, and anything in the "vido" category. Radarr
+// and Sonarr import their own grabs, and a user's personal downloads are not
+// ours to file away.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/parser"
+	"github.com/vido/api/internal/qbittorrent"
+	"github.com/vido/api/internal/repository"
+)
+
+// defaultDownloadImportInterval is how often finished torrents are looked
+// for. The request poller's import window spans minutes, so this only has to
+// beat a full scan.
+const defaultDownloadImportInterval = 30 * time.Second
+
+// Narrow ports over what the importer reads.
+type importRequestStore interface {
+	ListActive(ctx context.Context) ([]models.Request, error)
+}
+
+type importLibraryStore interface {
+	GetAll(ctx context.Context) ([]models.MediaLibrary, error)
+	GetPathsByLibraryID(ctx context.Context, libraryID string) ([]models.MediaLibraryPath, error)
+}
+
+type importFilenameParser interface {
+	ParseFilename(filename string) *parser.ParseResult
+}
+
+// importMovieStore is what an upgrade import needs to refresh the row of the
+// copy it replaced.
+type importMovieStore interface {
+	FindByFilePath(ctx context.Context, filePath string) (*models.Movie, error)
+	Update(ctx context.Context, movie *models.Movie) error
+}
+
+// importIngester is the one ScannerService method the importer needs.
+type importIngester interface {
+	ProcessChangedPaths(ctx context.Context, changes []PathChange) (*ScanResult, error)
+}
+
+// DownloadImportServiceInterface is the importer's contract.
+type DownloadImportServiceInterface interface {
+	Start(ctx context.Context)
+	Stop()
+	ImportTorrent(ctx context.Context, torrent qbittorrent.Torrent) (*models.DownloadImport, error)
+}
+
+// DownloadImportService imports finished downloads into the libraries.
+type DownloadImportService struct {
+	torrents  torrentSource
+	requests  importRequestStore
+	libraries importLibraryStore
+	parser    importFilenameParser
+	imports   repository.DownloadImportRepositoryInterface
+	ingest    importIngester
+	changes   MediaChangeNotifier
+	movies    importMovieStore
+	trashDir  string
+	now       func() time.Time
+	interval  time.Duration
+	logger    *slog.Logger
+
+	mu      sync.Mutex
+	stopCh  chan struct{}
+	stopped bool
+	// pendingIngest holds imported paths the scanner could not take because
+	// a full scan held it; they are handed over on the next tick.
+	pendingIngest []PathChange
+}
+
+// NewDownloadImportService creates a DownloadImportService.
+func NewDownloadImportService(
+	torrents torrentSource,
+	requests importRequestStore,
+	libraries importLibraryStore,
+	filenameParser importFilenameParser,
+	imports repository.DownloadImportRepositoryInterface,
+	ingest importIngester,
+	logger *slog.Logger,
+) *DownloadImportService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DownloadImportService{
+		torrents:  torrents,
+		requests:  requests,
+		libraries: libraries,
+		parser:    filenameParser,
+		imports:   imports,
+		ingest:    ingest,
+		now:       time.Now,
+		interval:  defaultDownloadImportInterval,
+		logger:    logger.With("service", "download_import"),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// SetChangeNotifier wires the optional media-server refresh for imported
+// files, as for the organizer.
+func (s *DownloadImportService) SetChangeNotifier(n MediaChangeNotifier) {
+	s.changes = n
+}
+
+// SetUpgradeReplacement lets a quality-upgrade import replace the copy it
+// upgrades. The default templates carry no resolution, so the better release
+// renders to the very path the old one holds; without this the upgrade is
+// skipped as "target already exists" and the request never completes. The
+// old file is moved under trashDir, and its movie row forgets the technical
+// info measured from it so the next evaluation reads the new file.
+func (s *DownloadImportService) SetUpgradeReplacement(movies importMovieStore, trashDir string) {
+	s.movies = movies
+	s.trashDir = trashDir
+}
+
+// Start polls for finished torrents until ctx is cancelled or Stop is called.
+func (s *DownloadImportService) Start(ctx context.Context) {
+	s.logger.Info("Download importer started", "interval", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// Stop stops the importer. Idempotent.
+func (s *DownloadImportService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.stopCh)
+	}
+}
+
+// tick imports every finished torrent that has no import record yet.
+func (s *DownloadImportService) tick(ctx context.Context) {
+	s.flushIngest(ctx, nil)
+
+	torrents, err := s.torrents.GetAllDownloads(ctx, "completed", "", "")
+	if err != nil {
+		s.logger.Debug("Failed to list finished downloads", "error", err)
+		return
+	}
+	var requests []models.Request
+	requestsLoaded := false
+
+	for _, t := range torrents {
+		if t.Progress < 1 {
+			continue
+		}
+		existing, err := s.imports.GetByHash(ctx, t.Hash)
+		if err != nil {
+			s.logger.Warn("Failed to read import record", "hash", t.Hash, "error", err)
+			continue
+		}
+		if existing != nil {
+			continue
+		}
+		if !requestsLoaded {
+			if requests, err = s.requests.ListActive(ctx); err != nil {
+				s.logger.Warn("Failed to list active requests", "error", err)
+				return
+			}
+			requestsLoaded = true
+		}
+		request := requestForTorrent(t, requests)
+		if request == nil && !isVidoTorrent(t) {
+			continue
+		}
+		if _, err := s.importTorrent(ctx, t, request); err != nil {
+			s.logger.Error("Download import failed", "hash", t.Hash, "name", t.Name, "error", err)
+		}
+	}
+}
+
+// ImportTorrent imports one finished torrent whatever its category, and
+// records the outcome. It backs the automatic path and lets an operator
+// import a download by hand.
+func (s *DownloadImportService) ImportTorrent(ctx context.Context, torrent qbittorrent.Torrent) (*models.DownloadImport, error) {
+	requests, err := s.requests.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list active requests: %w", err)
+	}
+	return s.importTorrent(ctx, torrent, requestForTorrent(torrent, requests))
+}
+
+// isVidoTorrent reports whether a torrent sits in Vido's category. The
+// category is a tag on clients without categories (Transmission labels).
+func isVidoTorrent(t qbittorrent.Torrent) bool {
+	return t.Category == builtinTorrentCategory || slices.Contains(t.Tags, builtinTorrentCategory)
+}
+
+// requestForTorrent finds the request a torrent was grabbed for: by its
+// request tag, else by the info-hash a built-in grab records as external id.
+func requestForTorrent(t qbittorrent.Torrent, requests []models.Request) *models.Request {
+	for i := range requests {
+		if slices.Contains(t.Tags, RequestTorrentTag(requests[i].ID)) {
+			return &requests[i]
+		}
+	}
+	for i := range requests {
+		r := &requests[i]
+		if r.FulfilmentSource.String == models.RequestFulfilmentSourceBuiltin && strings.EqualFold(r.ExternalID.String, t.Hash) {
+			return r
+		}
+	}
+	return nil
+}
+
+// importFile is one video the importer places, with its template fields.
+type importFile struct {
+	source string
+	fields organizeFields
+}
+
+// importTorrent does the work and always leaves a record, so a torrent that
+// cannot be imported is not retried on every tick.
+func (s *DownloadImportService) importTorrent(ctx context.Context, t qbittorrent.Torrent, request *models.Request) (*models.DownloadImport, error) {
+	record := &models.DownloadImport{TorrentHash: t.Hash, TorrentName: t.Name, Hardlinked: true}
+	if request != nil {
+		record.RequestID = models.NewNullString(request.ID)
+	}
+	finish := func(status, message string) (*models.DownloadImport, error) {
+		record.Status = status
+		if message != "" {
+			record.Message = models.NewNullString(message)
+		}
+		if status != models.DownloadImportImported {
+			record.Hardlinked = false
+		}
+		if err := s.imports.Save(ctx, record); err != nil {
+			return record, fmt.Errorf("save import record: %w", err)
+		}
+		return record, nil
+	}
+
+	videos, err := releaseVideos(filepath.Join(t.SavePath, t.Name))
+	if err != nil {
+		return finish(models.DownloadImportFailed, "read download: "+err.Error())
+	}
+	if len(videos) == 0 {
+		return finish(models.DownloadImportSkipped, "no video files in the download")
+	}
+
+	// Season packs are often named without an episode ("Show.S02.1080p")
+	// and the parser gives up on them; the files inside name themselves.
+	release := s.parser.ParseFilename(t.Name)
+	if !releaseIdentified(release) {
+		release = s.parser.ParseFilename(filepath.Base(largestFile(videos)))
+	}
+	isTV := releaseIdentified(release) && release.MediaType == parser.MediaTypeTVShow
+	if request != nil {
+		isTV = request.MediaType == models.RequestMediaTypeTV
+	} else if !releaseIdentified(release) {
+		return finish(models.DownloadImportSkipped, "release name could not be identified")
+	}
+
+	contentType := models.ContentTypeMovie
+	if isTV {
+		contentType = models.ContentTypeSeries
+	}
+	lib, root, err := s.targetLibrary(ctx, contentType)
+	if err != nil {
+		return nil, err
+	}
+	if lib == nil {
+		return finish(models.DownloadImportSkipped, fmt.Sprintf("no %s library with a folder to import into", contentType))
+	}
+	record.LibraryID = models.NewNullString(lib.ID)
+
+	source := lib.OrganizeTemplate
+	if strings.TrimSpace(source) == "" {
+		source = DefaultOrganizeTemplate(lib.ContentType)
+	}
+	tpl, err := parseOrganizeTemplate(source, lib.ContentType)
+	if err != nil {
+		return finish(models.DownloadImportFailed, "library organize template: "+err.Error())
+	}
+
+	files, notes := s.planImport(videos, isTV, release, request)
+	upgrade := request != nil && request.UpgradeCutoff.Valid && s.trashDir != ""
+	var ingest []PathChange
+	failed := 0
+	for _, f := range files {
+		rel, err := tpl.render(f.fields)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("%s: %v", filepath.Base(f.source), err))
+			failed++
+			continue
+		}
+		target := filepath.Join(root, rel) + filepath.Ext(f.source)
+		linked, note, err := s.placeFile(ctx, f.source, target, !isTV, upgrade)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("%s: %v", filepath.Base(f.source), err))
+			failed++
+			continue
+		}
+		if note != "" {
+			notes = append(notes, fmt.Sprintf("%s: %s", filepath.Base(f.source), note))
+			continue
+		}
+		record.FilesImported++
+		record.Hardlinked = record.Hardlinked && linked
+		if !record.TargetPath.Valid {
+			record.TargetPath = models.NewNullString(target)
+		}
+		ingest = append(ingest, PathChange{Path: target, Kind: PathChanged})
+	}
+
+	if record.FilesImported == 0 {
+		status := models.DownloadImportSkipped
+		if failed > 0 {
+			status = models.DownloadImportFailed
+		}
+		return finish(status, strings.Join(notes, "; "))
+	}
+
+	s.flushIngest(ctx, ingest)
+	s.logger.Info("Download imported",
+		"hash", t.Hash,
+		"name", t.Name,
+		"library_id", lib.ID,
+		"files", record.FilesImported,
+		"hardlinked", record.Hardlinked,
+		"target", record.TargetPath.String,
+	)
+	return finish(models.DownloadImportImported, strings.Join(notes, "; "))
+}
+
+// releaseIdentified reports whether a parse result names a title.
+func releaseIdentified(r *parser.ParseResult) bool {
+	return r != nil && r.Status != parser.ParseStatusFailed && r.CleanedTitle != ""
+}
+
+// planImport pairs each video with its template fields. A movie release
+// imports its largest video; the rest are extras. A series release imports
+// every video whose episode can be told from its file name or, for a
+// single-episode torrent, from the release name.
+func (s *DownloadImportService) planImport(videos []string, isTV bool, release *parser.ParseResult, request *models.Request) ([]importFile, []string) {
+	base := organizeFields{}
+	if releaseIdentified(release) {
+		base.Title = release.CleanedTitle
+		if release.Year > 0 {
+			base.Year = strconv.Itoa(release.Year)
+		}
+		base.Resolution = release.Quality
+	}
+	if request != nil {
+		base.Title = request.Title
+		base.TMDbID = request.TMDbID
+	}
+
+	if !isTV {
+		return []importFile{{source: largestFile(videos), fields: base}}, nil
+	}
+
+	var files []importFile
+	var notes []string
+	for _, video := range videos {
+		fields := base
+		parsed := s.parser.ParseFilename(filepath.Base(video))
+		switch {
+		case parsed != nil && parsed.Episode > 0:
+			fields.Season, fields.Episode = parsed.Season, parsed.Episode
+		case len(videos) == 1 && release != nil && release.Episode > 0:
+			fields.Season, fields.Episode = release.Season, release.Episode
+		default:
+			notes = append(notes, filepath.Base(video)+": episode number not found")
+			continue
+		}
+		if fields.Season == 0 && release != nil {
+			fields.Season = release.Season
+		}
+		files = append(files, importFile{source: video, fields: fields})
+	}
+	return files, notes
+}
+
+// targetLibrary returns the first library of the content type, in settings
+// order, that has a folder, and that folder.
+func (s *DownloadImportService) targetLibrary(ctx context.Context, contentType models.MediaLibraryContentType) (*models.MediaLibrary, string, error) {
+	libs, err := s.libraries.GetAll(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("list libraries: %w", err)
+	}
+	for i := range libs {
+		if libs[i].ContentType != contentType {
+			continue
+		}
+		paths, err := s.libraries.GetPathsByLibraryID(ctx, libs[i].ID)
+		if err != nil {
+			return nil, "", fmt.Errorf("get library paths: %w", err)
+		}
+		if len(paths) > 0 {
+			return &libs[i], filepath.Clean(paths[0].Path), nil
+		}
+	}
+	return nil, "", nil
+}
+
+// placeFile links a video and its sidecars into place. A target that is
+// already the same file is success (an import interrupted after linking).
+// Any other existing target is left alone and reported as a note — unless
+// replace is set (an upgrade import), when it is trashed and taken over.
+func (s *DownloadImportService) placeFile(ctx context.Context, source, target string, isMovie, replace bool) (hardlinked bool, note string, err error) {
+	trashed := ""
+	if info, statErr := os.Lstat(target); statErr == nil {
+		if src, err := os.Stat(source); err == nil && os.SameFile(src, info) {
+			return true, "", nil
+		}
+		if !replace {
+			return false, "target already exists", nil
+		}
+		if trashed, err = s.trashReplaced(target); err != nil {
+			return false, "", err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return false, "", fmt.Errorf("create target folder: %w", err)
+	}
+	hardlinked, err = linkOrCopy(source, target)
+	if err != nil {
+		if trashed != "" {
+			if restoreErr := transferFile(OrganizeModeMove, trashed, target); restoreErr != nil {
+				s.logger.Error("Failed to restore replaced file", "from", trashed, "to", target, "error", restoreErr)
+			}
+		}
+		return false, "", err
+	}
+	if trashed != "" {
+		s.logger.Info("Upgrade replaced library file", "target", target, "trashed", trashed)
+		if isMovie {
+			s.refreshReplacedMovie(ctx, target)
+		}
+	}
+	if s.changes != nil {
+		s.changes.NotifyChanged(target)
+	}
+
+	for _, sc := range planSidecars(source, target, isMovie) {
+		if _, err := os.Lstat(sc.Target); err == nil {
+			continue
+		}
+		if _, err := linkOrCopy(sc.Source, sc.Target); err != nil {
+			s.logger.Warn("Failed to import sidecar", "source", sc.Source, "target", sc.Target, "error", err)
+		}
+	}
+	return hardlinked, "", nil
+}
+
+// trashReplaced moves the file an upgrade replaces under the trash folder,
+// as the duplicate review does, and returns where it went.
+func (s *DownloadImportService) trashReplaced(target string) (string, error) {
+	dir := filepath.Join(s.trashDir, s.now().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create trash dir: %w", err)
+	}
+	dst := filepath.Join(dir, filepath.Base(target))
+	if err := transferFile(OrganizeModeMove, target, dst); err != nil {
+		return "", fmt.Errorf("move replaced file to trash: %w", err)
+	}
+	return dst, nil
+}
+
+// refreshReplacedMovie points the row at the new file's size and drops the
+// technical columns measured from the old one: the upgrade evaluation trusts
+// them over a probe, and would keep judging the 720p it replaced. Enrichment
+// measures the new file again. Episodes store no technical columns.
+func (s *DownloadImportService) refreshReplacedMovie(ctx context.Context, target string) {
+	if s.movies == nil {
+		return
+	}
+	movie, err := s.movies.FindByFilePath(ctx, target)
+	if err != nil || movie == nil {
+		if err != nil {
+			s.logger.Warn("Failed to find the replaced movie row", "target", target, "error", err)
+		}
+		return
+	}
+	if info, err := os.Stat(target); err == nil {
+		movie.FileSize = models.NewNullInt64(info.Size())
+	}
+	movie.VideoCodec = models.NullString{}
+	movie.VideoResolution = models.NullString{}
+	movie.AudioCodec = models.NullString{}
+	movie.AudioChannels = models.NullInt64{}
+	movie.HDRFormat = models.NullString{}
+	movie.SubtitleTracks = models.NullString{}
+	if err := s.movies.Update(ctx, movie); err != nil {
+		s.logger.Warn("Failed to refresh the replaced movie row", "movie_id", movie.ID, "error", err)
+	}
+}
+
+// linkOrCopy hardlinks src to dst so the torrent keeps seeding from the same
+// blocks, and copies when it cannot: a library on another filesystem, or a
+// share that does not support links.
+func linkOrCopy(src, dst string) (hardlinked bool, err error) {
+	if err := os.Link(src, dst); err == nil {
+		return true, nil
+	}
+	return false, copyFile(src, dst)
+}
+
+// flushIngest hands imported paths to the scanner, with any left over from
+// a tick where a full scan held it.
+func (s *DownloadImportService) flushIngest(ctx context.Context, changes []PathChange) {
+	s.mu.Lock()
+	changes = append(s.pendingIngest, changes...)
+	s.pendingIngest = nil
+	s.mu.Unlock()
+	if len(changes) == 0 {
+		return
+	}
+
+	_, err := s.ingest.ProcessChangedPaths(ctx, changes)
+	if errors.Is(err, ErrScanAlreadyRunning) {
+		s.mu.Lock()
+		s.pendingIngest = append(s.pendingIngest, changes...)
+		s.mu.Unlock()
+		s.logger.Debug("Scan in progress — deferring import ingest", "paths", len(changes))
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to ingest imported files", "paths", len(changes), "error", err)
+	}
+}
+
+// releaseVideos lists the videos of a download, which is a single file or a
+// folder. Samples are left out.
+func releaseVideos(contentPath string) ([]string, error) {
+	info, err := os.Stat(contentPath)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		if isVideoFile(contentPath) && !isSampleFile(contentPath) {
+			return []string{contentPath}, nil
+		}
+		return nil, nil
+	}
+
+	var videos []string
+	err = filepath.WalkDir(contentPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if isSampleFile(path) && path != contentPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isVideoFile(path) && !isSampleFile(path) {
+			videos = append(videos, path)
+		}
+		return nil
+	})
+	return videos, err
+}
+
+// isSampleFile matches a release's sample clip or sample folder.
+func isSampleFile(path string) bool {
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	return name == "sample" || strings.HasSuffix(name, "-sample") || strings.HasSuffix(name, ".sample") ||
+		strings.HasPrefix(name, "sample-")
+}
+
+// largestFile returns the biggest of paths; the main feature of a movie
+// release dwarfs its extras.
+func largestFile(paths []string) string {
+	best, bestSize := paths[0], int64(-1)
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && info.Size() > bestSize {
+			best, bestSize = p, info.Size()
+		}
+	}
+	return best
+}
+
+// Compile-time interface verification
+var _ DownloadImportServiceInterface = (*DownloadImportService)(nil)
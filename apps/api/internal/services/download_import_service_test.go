@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/qbittorrent"
+)
+
+// fakeImportLedger implements repository.DownloadImportRepositoryInterface.
+type fakeImportLedger struct {
+	records map[string]*models.DownloadImport
+}
+
+func (f *fakeImportLedger) Save(_ context.Context, r *models.DownloadImport) error {
+	f.records[r.TorrentHash] = r
+	return nil
+}
+func (f *fakeImportLedger) GetByHash(_ context.Context, hash string) (*models.DownloadImport, error) {
+	return f.records[hash], nil
+}
+func (f *fakeImportLedger) ListRecent(context.Context, int) ([]models.DownloadImport, error) {
+	return nil, nil
+}
+func (f *fakeImportLedger) Delete(_ context.Context, hash string) error {
+	delete(f.records, hash)
+	return nil
+}
+
+type fakeImportRequests struct{ requests []models.Request }
+
+func (f *fakeImportRequests) ListActive(context.Context) ([]models.Request, error) {
+	return f.requests, nil
+}
+
+type fakeImportLibraries struct {
+	libs  []models.MediaLibrary
+	paths map[string][]models.MediaLibraryPath
+}
+
+func (f *fakeImportLibraries) GetAll(context.Context) ([]models.MediaLibrary, error) {
+	return f.libs, nil
+}
+func (f *fakeImportLibraries) GetPathsByLibraryID(_ context.Context, id string) ([]models.MediaLibraryPath, error) {
+	return f.paths[id], nil
+}
+
+type importFixture struct {
+	svc       *DownloadImportService
+	downloads string
+	movies    string
+	shows     string
+	torrents  *fakeTorrents
+	requests  *fakeImportRequests
+	ledger    *fakeImportLedger
+	ingest    *fakeChangeProcessor
+}
+
+func newImportFixture(t *testing.T) *importFixture {
+	t.Helper()
+	root := t.TempDir()
+	f := &importFixture{
+		downloads: filepath.Join(root, "downloads"),
+		movies:    filepath.Join(root, "movies"),
+		shows:     filepath.Join(root, "shows"),
+		torrents:  &fakeTorrents{},
+		requests:  &fakeImportRequests{},
+		ledger:    &fakeImportLedger{records: map[string]*models.DownloadImport{}},
+		ingest:    &fakeChangeProcessor{},
+	}
+	libraries := &fakeImportLibraries{
+		libs: []models.MediaLibrary{
+			{ID: "lib-movies", ContentType: models.ContentTypeMovie},
+			{ID: "lib-shows", ContentType: models.ContentTypeSeries},
+		},
+		paths: map[string][]models.MediaLibraryPath{
+			"lib-movies": {{Path: f.movies}},
+			"lib-shows":  {{Path: f.shows}},
+		},
+	}
+	f.svc = NewDownloadImportService(f.torrents, f.requests, libraries, NewParserService(), f.ledger, f.ingest, nil)
+	return f
+}
+
+func writeImportFile(t *testing.T, path string, size int) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+}
+
+func TestDownloadImportService_HardlinksRequestedMovie(t *testing.T) {
+	ctx := context.Background()
+	f := newImportFixture(t)
+	release := "Inception.2010.1080p.BluRay.x264-GRP"
+	source := filepath.Join(f.downloads, release, release+".mkv")
+	writeImportFile(t, source, 2048)
+	writeImportFile(t, filepath.Join(f.downloads, release, release+".zh-Hant.srt"), 10)
+	writeImportFile(t, filepath.Join(f.downloads, release, "Sample", "sample.mkv"), 4096)
+
+	f.requests.requests = []models.Request{{ID: "req-1", TMDbID: 27205, MediaType: models.RequestMediaTypeMovie, Title: "全面啟動"}}
+	f.torrents.torrents = []qbittorrent.Torrent{
+		{Hash: "abc", Name: release, SavePath: f.downloads, Progress: 1, Status: qbittorrent.StatusSeeding, Tags: []string{RequestTorrentTag("req-1")}},
+		{Hash: "arr", Name: "Other.Movie.2021.1080p", SavePath: f.downloads, Progress: 1, Category: "radarr"},
+	}
+
+	f.svc.tick(ctx)
+
+	record := f.ledger.records["abc"]
+	require.NotNil(t, record)
+	assert.Equal(t, models.DownloadImportImported, record.Status, record.Message.String)
+	assert.Equal(t, "req-1", record.RequestID.String)
+	assert.True(t, record.Hardlinked)
+	assert.Equal(t, 1, record.FilesImported, "the sample is not imported")
+
+	target := filepath.Join(f.movies, "全面啟動 (2010)", "全面啟動 (2010).mkv")
+	assert.Equal(t, target, record.TargetPath.String)
+	src, err := os.Stat(source)
+	require.NoError(t, err, "the torrent's own file stays in place to keep seeding")
+	dst, err := os.Stat(target)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(src, dst), "imported as a hardlink")
+	assert.FileExists(t, filepath.Join(f.movies, "全面啟動 (2010)", "全面啟動 (2010).zh-Hant.srt"))
+
+	batches, _ := f.ingest.snapshot()
+	require.Len(t, batches, 1)
+	assert.Equal(t, []PathChange{{Path: target, Kind: PathChanged}}, batches[0])
+	assert.Nil(t, f.ledger.records["arr"], "other tools' downloads are left alone")
+
+	f.svc.tick(ctx)
+	batches, _ = f.ingest.snapshot()
+	assert.Len(t, batches, 1, "an imported torrent is not imported again")
+}
+
+func TestDownloadImportService_SeriesByCategoryAndDeferredIngest(t *testing.T) {
+	ctx := context.Background()
+	f := newImportFixture(t)
+	f.ingest.busyFirst = 1
+	release := "Show.Name.S02.1080p.WEB-DL"
+	writeImportFile(t, filepath.Join(f.downloads, release, "Show.Name.S02E01.1080p.WEB-DL.mkv"), 100)
+	writeImportFile(t, filepath.Join(f.downloads, release, "Show.Name.S02E02.1080p.WEB-DL.mkv"), 100)
+	f.torrents.torrents = []qbittorrent.Torrent{
+		{Hash: "tv", Name: release, SavePath: f.downloads, Progress: 1, Category: builtinTorrentCategory},
+	}
+
+	f.svc.tick(ctx)
+
+	record := f.ledger.records["tv"]
+	require.NotNil(t, record)
+	assert.Equal(t, models.DownloadImportImported, record.Status, record.Message.String)
+	assert.Equal(t, 2, record.FilesImported)
+	assert.FileExists(t, filepath.Join(f.shows, "Show Name", "Season 02", "Show Name - S02E02.mkv"))
+
+	batches, _ := f.ingest.snapshot()
+	assert.Empty(t, batches, "a running scan defers the ingest")
+
+	f.svc.tick(ctx)
+	batches, _ = f.ingest.snapshot()
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0], 2)
+}
+
+func TestDownloadImportService_ExistingTargetIsSkipped(t *testing.T) {
+	ctx := context.Background()
+	f := newImportFixture(t)
+	release := "Arrival.2016.2160p.UHD.BluRay"
+	writeImportFile(t, filepath.Join(f.downloads, release+".mkv"), 100)
+	writeImportFile(t, filepath.Join(f.movies, "Arrival (2016)", "Arrival (2016).mkv"), 50)
+
+	record, err := f.svc.ImportTorrent(ctx, qbittorrent.Torrent{Hash: "arr1", Name: release + ".mkv", SavePath: f.downloads, Progress: 1})
+	require.NoError(t, err)
+	assert.Equal(t, models.DownloadImportSkipped, record.Status)
+	assert.Contains(t, record.Message.String, "target already exists")
+	assert.False(t, record.Hardlinked)
+
+	info, err := os.Stat(filepath.Join(f.movies, "Arrival (2016)", "Arrival (2016).mkv"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 50, info.Size(), "the library copy is not overwritten")
+}
+
+type fakeImportMovies struct {
+	byPath  map[string]*models.Movie
+	updated []models.Movie
+}
+
+func (f *fakeImportMovies) FindByFilePath(_ context.Context, path string) (*models.Movie, error) {
+	return f.byPath[path], nil
+}
+
+func (f *fakeImportMovies) Update(_ context.Context, m *models.Movie) error {
+	f.updated = append(f.updated, *m)
+	return nil
+}
+
+// TestDownloadImportService_UpgradeReplacesBelowCutoffCopy — the default
+// template has no resolution, so a 1080p upgrade renders onto the 720p it
+// replaces. The 720p goes to the trash and the row forgets its measurements.
+func TestDownloadImportService_UpgradeReplacesBelowCutoffCopy(t *testing.T) {
+	ctx := context.Background()
+	f := newImportFixture(t)
+	trash := filepath.Join(t.TempDir(), "trash")
+	target := filepath.Join(f.movies, "全面啟動 (2010)", "全面啟動 (2010).mkv")
+	writeImportFile(t, target, 720)
+	movies := &fakeImportMovies{byPath: map[string]*models.Movie{target: {
+		ID: "movie-1", FilePath: models.NewNullString(target), FileSize: models.NewNullInt64(720),
+		VideoResolution: models.NewNullString("1280x720"), VideoCodec: models.NewNullString("h264"),
+	}}}
+	f.svc.SetUpgradeReplacement(movies, trash)
+	f.svc.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local) }
+
+	release := "Inception.2010.1080p.BluRay.x264-GRP"
+	source := filepath.Join(f.downloads, release, release+".mkv")
+	writeImportFile(t, source, 1080)
+	f.requests.requests = []models.Request{{
+		ID: "req-up", TMDbID: 27205, MediaType: models.RequestMediaTypeMovie, Title: "全面啟動",
+		UpgradeCutoff: models.NewNullString("1080p"),
+	}}
+	f.torrents.torrents = []qbittorrent.Torrent{
+		{Hash: "up", Name: release, SavePath: f.downloads, Progress: 1, Status: qbittorrent.StatusSeeding, Tags: []string{RequestTorrentTag("req-up")}},
+	}
+
+	f.svc.tick(ctx)
+
+	record := f.ledger.records["up"]
+	require.NotNil(t, record)
+	assert.Equal(t, models.DownloadImportImported, record.Status, record.Message.String)
+	assert.Equal(t, target, record.TargetPath.String)
+	src, err := os.Stat(source)
+	require.NoError(t, err)
+	dst, err := os.Stat(target)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(src, dst), "the library path now holds the 1080p")
+
+	old, err := os.Stat(filepath.Join(trash, "20261016-120000", "全面啟動 (2010).mkv"))
+	require.NoError(t, err, "the 720p is trashed, not deleted")
+	assert.EqualValues(t, 720, old.Size())
+
+	require.Len(t, movies.updated, 1)
+	assert.EqualValues(t, 1080, movies.updated[0].FileSize.Int64)
+	assert.False(t, movies.updated[0].VideoResolution.Valid, "the 720p measurement must not outlive the file")
+	assert.False(t, movies.updated[0].VideoCodec.Valid)
+}
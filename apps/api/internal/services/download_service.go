@@ -28,6 +28,16 @@ type DownloadService struct {
 	mu              sync.Mutex
 	cachedClient    *qbittorrent.Client
 	cachedConfigKey string // "host|username|password" fingerprint
+
+	// clientResolver picks the download client selected in settings. Without
+	// one the service talks to qBittorrent, as it did before there was a
+	// choice.
+	clientResolver downloadClientResolver
+}
+
+// downloadClientResolver is the narrow port onto DownloadClientService.
+type downloadClientResolver interface {
+	ActiveClient(ctx context.Context) (DownloadClient, error)
 }
 
 // NewDownloadService creates a new DownloadService.
@@ -38,6 +48,11 @@ func NewDownloadService(qbService QBittorrentServiceInterface, logger *slog.Logg
 	}
 }
 
+// SetClientResolver routes downloads to the client selected in settings.
+func (s *DownloadService) SetClientResolver(resolver downloadClientResolver) {
+	s.clientResolver = resolver
+}
+
 // configFingerprint returns a string key representing the config identity.
 func configFingerprint(cfg *qbittorrent.Config) string {
 	return cfg.Host + "|" + cfg.Username + "|" + cfg.Password + "|" + cfg.BasePath
@@ -83,24 +98,15 @@ var validFilters = map[string]bool{
 	"completed": true, "seeding": true, "error": true,
 }
 
-// GetAllDownloads retrieves all torrents from qBittorrent with optional filtering and sorting.
+// GetAllDownloads retrieves all torrents from the active client with optional filtering and sorting.
 // When sortField is "status", sorting is performed server-side since qBittorrent
 // does not support native status sorting.
 func (s *DownloadService) GetAllDownloads(ctx context.Context, filter string, sortField string, order string) ([]qbittorrent.Torrent, error) {
-	config, err := s.qbService.GetConfig(ctx)
+	client, err := s.activeClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("get qBittorrent config: %w", err)
-	}
-
-	if config.Host == "" {
-		return nil, &qbittorrent.ConnectionError{
-			Code:    qbittorrent.ErrCodeNotConfigured,
-			Message: "qBittorrent not configured",
-		}
+		return nil, err
 	}
 
-	client := s.getClient(config)
-
 	// Validate and map filter
 	if !validFilters[filter] {
 		filter = "all"
@@ -142,20 +148,11 @@ func (s *DownloadService) GetAllDownloads(ctx context.Context, filter string, so
 
 // GetDownloadDetails retrieves detailed information for a specific torrent.
 func (s *DownloadService) GetDownloadDetails(ctx context.Context, hash string) (*qbittorrent.TorrentDetails, error) {
-	config, err := s.qbService.GetConfig(ctx)
+	client, err := s.activeClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("get qBittorrent config: %w", err)
-	}
-
-	if config.Host == "" {
-		return nil, &qbittorrent.ConnectionError{
-			Code:    qbittorrent.ErrCodeNotConfigured,
-			Message: "qBittorrent not configured",
-		}
+		return nil, err
 	}
 
-	client := s.getClient(config)
-
 	details, err := client.GetTorrentDetails(ctx, hash)
 	if err != nil {
 		s.logger.Error("Failed to get torrent details", "error", err, "hash", hash)
@@ -200,10 +197,37 @@ func (s *DownloadService) GetDownloadCounts(ctx context.Context) (*qbittorrent.D
 	return counts, nil
 }
 
-// clientForAction resolves the qBittorrent config and returns a client, mirroring
-// the not-configured guard the GET methods use. Shared by the pause/resume/remove
-// action methods.
+// activeClient returns the client downloads are listed from and acted on:
+// the one selected in settings, or qBittorrent without a resolver.
+func (s *DownloadService) activeClient(ctx context.Context) (DownloadClient, error) {
+	if s.clientResolver != nil {
+		return s.clientResolver.ActiveClient(ctx)
+	}
+	client, err := s.qbClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// clientForAction returns the active client for the qBittorrent-only
+// actions (categories, tags, file priorities, speed limits), or
+// ErrDownloadClientUnsupported when another client is selected.
 func (s *DownloadService) clientForAction(ctx context.Context) (*qbittorrent.Client, error) {
+	client, err := s.activeClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	qbClient, ok := client.(*qbittorrent.Client)
+	if !ok {
+		return nil, ErrDownloadClientUnsupported
+	}
+	return qbClient, nil
+}
+
+// qbClient resolves the qBittorrent config and returns a client, with the
+// not-configured guard every download method shares.
+func (s *DownloadService) qbClient(ctx context.Context) (*qbittorrent.Client, error) {
 	config, err := s.qbService.GetConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get qBittorrent config: %w", err)
@@ -219,7 +243,7 @@ func (s *DownloadService) clientForAction(ctx context.Context) (*qbittorrent.Cli
 
 // PauseDownload pauses the torrent with the given hash.
 func (s *DownloadService) PauseDownload(ctx context.Context, hash string) error {
-	client, err := s.clientForAction(ctx)
+	client, err := s.activeClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -232,7 +256,7 @@ func (s *DownloadService) PauseDownload(ctx context.Context, hash string) error
 
 // ResumeDownload resumes the torrent with the given hash.
 func (s *DownloadService) ResumeDownload(ctx context.Context, hash string) error {
-	client, err := s.clientForAction(ctx)
+	client, err := s.activeClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -243,10 +267,10 @@ func (s *DownloadService) ResumeDownload(ctx context.Context, hash string) error
 	return nil
 }
 
-// RemoveDownload removes the torrent with the given hash from qBittorrent.
+// RemoveDownload removes the torrent with the given hash from the client.
 // When deleteFiles is true the downloaded data is also deleted from disk.
 func (s *DownloadService) RemoveDownload(ctx context.Context, hash string, deleteFiles bool) error {
-	client, err := s.clientForAction(ctx)
+	client, err := s.activeClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -257,5 +281,20 @@ func (s *DownloadService) RemoveDownload(ctx context.Context, hash string, delet
 	return nil
 }
 
+// AddTorrent hands a magnet or .torrent file to the active client. It backs both
+// POST /downloads and the built-in fulfilment path's grab step, and belongs
+// to DownloadControlServiceInterface rather than DownloadServiceInterface.
+func (s *DownloadService) AddTorrent(ctx context.Context, opts qbittorrent.AddTorrentOptions) error {
+	client, err := s.activeClient(ctx)
+	if err != nil {
+		return err
+	}
+	if err := client.AddTorrent(ctx, opts); err != nil {
+		s.logger.Error("Failed to add torrent", "error", err, "category", opts.Category)
+		return err
+	}
+	return nil
+}
+
 // Compile-time interface verification
 var _ DownloadServiceInterface = (*DownloadService)(nil)
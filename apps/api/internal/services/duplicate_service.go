@@ -0,0 +1,626 @@
+// Package services DuplicateService — duplicate and upgrade detection.
+//
+// A scan creates one row per file, so owning a 1080p WEB-DL and a 2160p remux
+// of the same film leaves two movie rows with one TMDb ID. The detector groups
+// movies by TMDb ID (IMDb ID when TMDb is missing) and episodes by series
+// TMDb ID plus season/episode, then ranks each group's copies on the ffprobe
+// technical data: resolution, HDR, video codec, audio, and finally size.
+//
+// Resolving a group keeps one copy and removes the others' rows along with
+// their files — deleted outright or moved into the trash directory. Like
+// DeleteTorrents with deleteFiles, touching disk needs an explicit
+// confirmation, and only copies of the same current group can be removed.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vido/api/internal/models"
+)
+
+// Duplicate resolution actions.
+const (
+	DuplicateActionDelete = "delete"
+	DuplicateActionTrash  = "trash"
+)
+
+var (
+	// ErrDuplicateConfirmationRequired is returned when a resolve request
+	// would touch files but was not confirmed.
+	ErrDuplicateConfirmationRequired = errors.New("duplicate resolution requires confirmation")
+	// ErrDuplicateInvalidRequest is returned for an unknown action or a
+	// request without copies.
+	ErrDuplicateInvalidRequest = errors.New("invalid duplicate resolution request")
+	// ErrDuplicateGroupMismatch is returned when the kept and removed copies
+	// are not all in one duplicate group.
+	ErrDuplicateGroupMismatch = errors.New("copies are not duplicates of each other")
+	// ErrDuplicateSameFile is returned when a copy to remove is the kept
+	// file itself: the same path, or a hardlink to the same inode.
+	ErrDuplicateSameFile = errors.New("copy to remove is the kept file")
+)
+
+// DuplicateServiceInterface defines the contract for duplicate review.
+type DuplicateServiceInterface interface {
+	FindDuplicates(ctx context.Context) (*DuplicateReport, error)
+	Resolve(ctx context.Context, req ResolveDuplicatesRequest) (*DuplicateResolution, error)
+}
+
+// DuplicateMovieStore is the movie-repo subset the detector needs.
+// *repository.MovieRepository satisfies it.
+type DuplicateMovieStore interface {
+	FindAllWithFilePath(ctx context.Context) ([]models.Movie, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// DuplicateSeriesLister is the series-repo method the detector walks.
+// *repository.SeriesRepository satisfies it.
+type DuplicateSeriesLister interface {
+	FindByParseStatus(ctx context.Context, status models.ParseStatus) ([]models.Series, error)
+}
+
+// DuplicateEpisodeStore is the episode-repo subset the detector needs.
+// *repository.EpisodeRepository satisfies it.
+type DuplicateEpisodeStore interface {
+	FindBySeriesID(ctx context.Context, seriesID string) ([]models.Episode, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// TechProber reads technical info from a file. *FFprobeService satisfies it.
+type TechProber interface {
+	Probe(ctx context.Context, filePath string) (*MediaTechInfo, error)
+}
+
+// DuplicateCopy is one file of a duplicated title.
+type DuplicateCopy struct {
+	ID            string `json:"id"`
+	FilePath      string `json:"file_path"`
+	FileSize      int64  `json:"file_size"`
+	Resolution    string `json:"resolution,omitempty"`
+	HDRFormat     string `json:"hdr_format,omitempty"`
+	VideoCodec    string `json:"video_codec,omitempty"`
+	AudioCodec    string `json:"audio_codec,omitempty"`
+	AudioChannels int    `json:"audio_channels,omitempty"`
+	// Best marks the copy the ranking would keep.
+	Best bool `json:"best"`
+}
+
+// DuplicateGroup is a title owned more than once, copies ranked best first.
+type DuplicateGroup struct {
+	Key           string          `json:"key"`
+	MediaType     string          `json:"media_type"`
+	Title         string          `json:"title"`
+	TMDbID        int64           `json:"tmdb_id,omitempty"`
+	SeasonNumber  int             `json:"season_number,omitempty"`
+	EpisodeNumber int             `json:"episode_number,omitempty"`
+	Copies        []DuplicateCopy `json:"copies"`
+	// ReclaimableBytes is the size of every copy but the best.
+	ReclaimableBytes int64 `json:"reclaimable_bytes"`
+}
+
+// DuplicateReport lists every duplicate group in the library.
+type DuplicateReport struct {
+	Groups           []DuplicateGroup `json:"groups"`
+	ReclaimableBytes int64            `json:"reclaimable_bytes"`
+}
+
+// ResolveDuplicatesRequest is the POST /api/v1/library/duplicates/resolve body.
+type ResolveDuplicatesRequest struct {
+	MediaType string   `json:"media_type"`
+	KeepID    string   `json:"keep_id"`
+	RemoveIDs []string `json:"remove_ids"`
+	// Action is "delete" or "trash".
+	Action string `json:"action"`
+	// Confirm must be true: resolving removes files from disk.
+	Confirm bool `json:"confirm"`
+}
+
+// RemovedCopy is a copy Resolve removed.
+type RemovedCopy struct {
+	ID        string `json:"id"`
+	FilePath  string `json:"file_path"`
+	TrashPath string `json:"trash_path,omitempty"`
+}
+
+// FailedCopy is a copy Resolve could not remove.
+type FailedCopy struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// DuplicateResolution is the outcome of Resolve.
+type DuplicateResolution struct {
+	KeptID  string        `json:"kept_id"`
+	Removed []RemovedCopy `json:"removed"`
+	Failed  []FailedCopy  `json:"failed"`
+}
+
+// DuplicateService implements DuplicateServiceInterface.
+type DuplicateService struct {
+	movies   DuplicateMovieStore
+	series   DuplicateSeriesLister
+	episodes DuplicateEpisodeStore
+	prober   TechProber
+	trashDir string
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// Compile-time verification.
+var _ DuplicateServiceInterface = (*DuplicateService)(nil)
+
+// NewDuplicateService builds a DuplicateService. prober may be nil; copies
+// without stored technical info then rank on size alone. Trashed files are
+// moved under trashDir.
+func NewDuplicateService(
+	movies DuplicateMovieStore,
+	series DuplicateSeriesLister,
+	episodes DuplicateEpisodeStore,
+	prober TechProber,
+	trashDir string,
+	logger *slog.Logger,
+) *DuplicateService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DuplicateService{
+		movies: movies, series: series, episodes: episodes, prober: prober, trashDir: trashDir,
+		logger: logger.With("service", "duplicates"),
+		now:    time.Now,
+	}
+}
+
+func (s *DuplicateService) FindDuplicates(ctx context.Context) (*DuplicateReport, error) {
+	movieGroups, err := s.movieGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	episodeGroups, err := s.episodeGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DuplicateReport{Groups: append(append([]DuplicateGroup{}, movieGroups...), episodeGroups...)}
+	for _, g := range report.Groups {
+		report.ReclaimableBytes += g.ReclaimableBytes
+	}
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		return report.Groups[i].ReclaimableBytes > report.Groups[j].ReclaimableBytes
+	})
+	return report, nil
+}
+
+// movieGroups groups movies by TMDb ID; a movie without one joins the group
+// of a movie sharing its IMDb ID, or an IMDb-keyed group of its own.
+func (s *DuplicateService) movieGroups(ctx context.Context) ([]DuplicateGroup, error) {
+	movies, err := s.movies.FindAllWithFilePath(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list movies: %w", err)
+	}
+
+	byKey := map[string][]*models.Movie{}
+	var order []string
+	imdbKey := map[string]string{}
+	add := func(key string, m *models.Movie) {
+		if _, ok := byKey[key]; !ok {
+			order = append(order, key)
+		}
+		byKey[key] = append(byKey[key], m)
+	}
+	for i := range movies {
+		m := &movies[i]
+		if m.TMDbID.Valid && m.TMDbID.Int64 > 0 {
+			key := fmt.Sprintf("movie:tmdb:%d", m.TMDbID.Int64)
+			add(key, m)
+			if m.IMDbID.Valid && m.IMDbID.String != "" {
+				imdbKey[m.IMDbID.String] = key
+			}
+		}
+	}
+	for i := range movies {
+		m := &movies[i]
+		if (m.TMDbID.Valid && m.TMDbID.Int64 > 0) || !m.IMDbID.Valid || m.IMDbID.String == "" {
+			continue
+		}
+		key, ok := imdbKey[m.IMDbID.String]
+		if !ok {
+			key = "movie:imdb:" + m.IMDbID.String
+		}
+		add(key, m)
+	}
+
+	var groups []DuplicateGroup
+	for _, key := range order {
+		members := byKey[key]
+		if len(members) < 2 {
+			continue
+		}
+		group := DuplicateGroup{Key: key, MediaType: models.RequestMediaTypeMovie, Title: members[0].Title}
+		if members[0].TMDbID.Valid {
+			group.TMDbID = members[0].TMDbID.Int64
+		}
+		for _, m := range members {
+			group.Copies = append(group.Copies, s.movieCopy(ctx, m))
+		}
+		groups = append(groups, rankGroup(group))
+	}
+	return groups, nil
+}
+
+// episodeGroups groups episodes of series rows sharing a TMDb ID (one show
+// scanned from two folders) by season and episode number.
+func (s *DuplicateService) episodeGroups(ctx context.Context) ([]DuplicateGroup, error) {
+	seriesList, err := s.series.FindByParseStatus(ctx, models.ParseStatusSuccess)
+	if err != nil {
+		return nil, fmt.Errorf("list series: %w", err)
+	}
+	byTMDb := map[int64][]*models.Series{}
+	var order []int64
+	for i := range seriesList {
+		series := &seriesList[i]
+		if !series.TMDbID.Valid || series.TMDbID.Int64 <= 0 {
+			continue
+		}
+		if _, ok := byTMDb[series.TMDbID.Int64]; !ok {
+			order = append(order, series.TMDbID.Int64)
+		}
+		byTMDb[series.TMDbID.Int64] = append(byTMDb[series.TMDbID.Int64], series)
+	}
+
+	var groups []DuplicateGroup
+	for _, tmdbID := range order {
+		rows := byTMDb[tmdbID]
+		if len(rows) < 2 {
+			continue
+		}
+		byEpisode := map[episodeKey][]models.Episode{}
+		for _, series := range rows {
+			episodes, err := s.episodes.FindBySeriesID(ctx, series.ID)
+			if err != nil {
+				return nil, fmt.Errorf("list episodes of series %s: %w", series.ID, err)
+			}
+			for _, ep := range episodes {
+				if ep.FilePath.Valid && ep.FilePath.String != "" {
+					key := episodeKey{ep.SeasonNumber, ep.EpisodeNumber}
+					byEpisode[key] = append(byEpisode[key], ep)
+				}
+			}
+		}
+
+		keys := make([]episodeKey, 0, len(byEpisode))
+		for key, eps := range byEpisode {
+			if len(eps) > 1 {
+				keys = append(keys, key)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].season != keys[j].season {
+				return keys[i].season < keys[j].season
+			}
+			return keys[i].episode < keys[j].episode
+		})
+		for _, key := range keys {
+			group := DuplicateGroup{
+				Key:           fmt.Sprintf("episode:tmdb:%d:s%02de%02d", tmdbID, key.season, key.episode),
+				MediaType:     models.RequestMediaTypeTV,
+				Title:         rows[0].Title,
+				TMDbID:        tmdbID,
+				SeasonNumber:  key.season,
+				EpisodeNumber: key.episode,
+			}
+			for _, ep := range byEpisode[key] {
+				group.Copies = append(group.Copies, s.probedCopy(ctx, ep.ID, ep.FilePath.String, 0))
+			}
+			groups = append(groups, rankGroup(group))
+		}
+	}
+	return groups, nil
+}
+
+// movieCopy builds a copy from the stored technical columns, probing the file
+// only when a scan never recorded them.
+func (s *DuplicateService) movieCopy(ctx context.Context, m *models.Movie) DuplicateCopy {
+	if !m.VideoResolution.Valid || m.VideoResolution.String == "" {
+		var size int64
+		if m.FileSize.Valid {
+			size = m.FileSize.Int64
+		}
+		return s.probedCopy(ctx, m.ID, m.FilePath.String, size)
+	}
+	c := DuplicateCopy{
+		ID:         m.ID,
+		FilePath:   m.FilePath.String,
+		Resolution: resolutionLabel(m.VideoResolution.String),
+		HDRFormat:  m.HDRFormat.String,
+		VideoCodec: m.VideoCodec.String,
+		AudioCodec: m.AudioCodec.String,
+	}
+	if m.AudioChannels.Valid {
+		c.AudioChannels = int(m.AudioChannels.Int64)
+	}
+	if m.FileSize.Valid {
+		c.FileSize = m.FileSize.Int64
+	} else {
+		c.FileSize = fileSize(c.FilePath)
+	}
+	return c
+}
+
+// probedCopy builds a copy from ffprobe. A probe failure leaves the technical
+// fields empty; the copy still ranks on size.
+func (s *DuplicateService) probedCopy(ctx context.Context, id, path string, size int64) DuplicateCopy {
+	c := DuplicateCopy{ID: id, FilePath: path, FileSize: size}
+	if c.FileSize == 0 {
+		c.FileSize = fileSize(path)
+	}
+	if s.prober == nil {
+		return c
+	}
+	info, err := s.prober.Probe(ctx, path)
+	if err != nil {
+		if !errors.Is(err, ErrFFprobeNotAvailable) {
+			s.logger.Warn("probe failed for duplicate copy", "id", id, "path", path, "error", err)
+		}
+		return c
+	}
+	c.Resolution = resolutionLabel(info.VideoResolution)
+	c.HDRFormat = info.HDRFormat
+	c.VideoCodec = info.VideoCodec
+	c.AudioCodec = info.AudioCodec
+	c.AudioChannels = info.AudioChannels
+	return c
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// rankGroup sorts copies best first and totals what removing the rest frees.
+func rankGroup(g DuplicateGroup) DuplicateGroup {
+	sort.SliceStable(g.Copies, func(i, j int) bool { return compareCopies(g.Copies[i], g.Copies[j]) > 0 })
+	g.Copies[0].Best = true
+	for _, c := range g.Copies[1:] {
+		g.ReclaimableBytes += c.FileSize
+	}
+	return g
+}
+
+// compareCopies orders two copies: resolution, then HDR, video codec, audio,
+// and size as the tie-breaker. Positive means a is better.
+func compareCopies(a, b DuplicateCopy) int {
+	ra, rb := copyRank(a), copyRank(b)
+	for i := range ra {
+		if ra[i] != rb[i] {
+			if ra[i] > rb[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+func copyRank(c DuplicateCopy) [6]int64 {
+	return [6]int64{
+		resolutionRank(c.Resolution),
+		hdrRank(c.HDRFormat),
+		videoCodecRank(c.VideoCodec),
+		audioCodecRank(c.AudioCodec),
+		int64(c.AudioChannels),
+		c.FileSize,
+	}
+}
+
+func resolutionRank(label string) int64 {
+	switch label {
+	case "2160p":
+		return 5
+	case "1080p":
+		return 4
+	case "720p":
+		return 3
+	case "576p":
+		return 2
+	case "480p":
+		return 1
+	}
+	return 0
+}
+
+func hdrRank(format string) int64 {
+	f := strings.ToLower(format)
+	switch {
+	case f == "":
+		return 0
+	case strings.Contains(f, "dolby") || strings.Contains(f, "dovi"):
+		return 3
+	case strings.Contains(f, "hdr10+") || strings.Contains(f, "hdr10plus"):
+		return 2
+	default:
+		return 1
+	}
+}
+
+func videoCodecRank(codec string) int64 {
+	switch strings.ToLower(codec) {
+	case "av1":
+		return 3
+	case "hevc", "h265", "h.265", "x265":
+		return 2
+	case "h264", "avc", "h.264", "x264":
+		return 1
+	}
+	return 0
+}
+
+func audioCodecRank(codec string) int64 {
+	switch strings.ToLower(codec) {
+	case "truehd", "dts-hd", "dtshd", "flac", "pcm_s16le", "pcm_s24le":
+		return 3
+	case "dts", "eac3":
+		return 2
+	case "ac3", "aac", "opus":
+		return 1
+	}
+	return 0
+}
+
+// Resolve keeps one copy of a group and removes the others with their files.
+// Every copy must belong to one current duplicate group, so a stale or forged
+// request cannot delete an unrelated title, and none may be the kept file
+// under another row — a hardlink left by a copy/hardlink organize or an
+// upgrade import shares the kept copy's data. Per-copy failures are reported
+// without aborting the rest.
+func (s *DuplicateService) Resolve(ctx context.Context, req ResolveDuplicatesRequest) (*DuplicateResolution, error) {
+	if !req.Confirm {
+		return nil, ErrDuplicateConfirmationRequired
+	}
+	if req.Action != DuplicateActionDelete && req.Action != DuplicateActionTrash {
+		return nil, fmt.Errorf("unknown action %q: %w", req.Action, ErrDuplicateInvalidRequest)
+	}
+	if req.KeepID == "" || len(req.RemoveIDs) == 0 {
+		return nil, fmt.Errorf("keep_id and remove_ids are required: %w", ErrDuplicateInvalidRequest)
+	}
+
+	report, err := s.FindDuplicates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	group := findGroupWithCopy(report.Groups, req.MediaType, req.KeepID)
+	if group == nil {
+		return nil, fmt.Errorf("copy %s is not in a duplicate group: %w", req.KeepID, ErrDuplicateGroupMismatch)
+	}
+	copies := make(map[string]DuplicateCopy, len(group.Copies))
+	for _, c := range group.Copies {
+		copies[c.ID] = c
+	}
+	kept := copies[req.KeepID]
+	for _, id := range req.RemoveIDs {
+		c, ok := copies[id]
+		if !ok || id == req.KeepID {
+			return nil, fmt.Errorf("copy %s is not a duplicate of %s: %w", id, req.KeepID, ErrDuplicateGroupMismatch)
+		}
+		if sameFile(c.FilePath, kept.FilePath) {
+			return nil, fmt.Errorf("copy %s is the same file as %s: %w", id, req.KeepID, ErrDuplicateSameFile)
+		}
+	}
+
+	result := &DuplicateResolution{KeptID: req.KeepID, Removed: []RemovedCopy{}, Failed: []FailedCopy{}}
+	for _, id := range req.RemoveIDs {
+		c := copies[id]
+		removed, err := s.removeCopy(ctx, group.MediaType, c, kept, req.Action)
+		if err != nil {
+			s.logger.Warn("failed to remove duplicate copy", "id", id, "path", c.FilePath, "error", err)
+			result.Failed = append(result.Failed, FailedCopy{ID: id, Error: err.Error()})
+			continue
+		}
+		result.Removed = append(result.Removed, *removed)
+	}
+
+	s.logger.Info("duplicates resolved", "group", group.Key, "kept", req.KeepID,
+		"action", req.Action, "removed", len(result.Removed), "failed", len(result.Failed))
+	return result, nil
+}
+
+func findGroupWithCopy(groups []DuplicateGroup, mediaType, id string) *DuplicateGroup {
+	for i := range groups {
+		if mediaType != "" && groups[i].MediaType != mediaType {
+			continue
+		}
+		for _, c := range groups[i].Copies {
+			if c.ID == id {
+				return &groups[i]
+			}
+		}
+	}
+	return nil
+}
+
+// sameFile reports whether two copies are one file on disk: the same path,
+// or two names for the same inode. A copy that cannot be stat'ed is not the
+// same as anything.
+func sameFile(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(ai, bi)
+}
+
+// removeCopy deletes or trashes the file first and drops the row after, so a
+// failed file operation leaves the library as it was. The copy's sidecars
+// (subtitles, .nfo, artwork) go with it, the way the organizer carries them.
+func (s *DuplicateService) removeCopy(ctx context.Context, mediaType string, c, kept DuplicateCopy, action string) (*RemovedCopy, error) {
+	removed := &RemovedCopy{ID: c.ID, FilePath: c.FilePath}
+	isMovie := mediaType == models.RequestMediaTypeMovie
+	switch action {
+	case DuplicateActionTrash:
+		dir := filepath.Join(s.trashDir, s.now().Format("20060102-150405"))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create trash dir: %w", err)
+		}
+		dst := filepath.Join(dir, c.ID+"-"+filepath.Base(c.FilePath))
+		sidecars := copySidecars(c, kept, dst, isMovie)
+		if err := transferFile(OrganizeModeMove, c.FilePath, dst); err != nil {
+			return nil, fmt.Errorf("move to trash: %w", err)
+		}
+		removed.TrashPath = dst
+		for _, sc := range sidecars {
+			if err := transferFile(OrganizeModeMove, sc.Source, sc.Target); err != nil {
+				s.logger.Warn("failed to trash sidecar", "source", sc.Source, "target", sc.Target, "error", err)
+			}
+		}
+	default:
+		sidecars := copySidecars(c, kept, c.FilePath, isMovie)
+		if err := os.Remove(c.FilePath); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("delete file: %w", err)
+		}
+		for _, sc := range sidecars {
+			if err := os.Remove(sc.Source); err != nil && !os.IsNotExist(err) {
+				s.logger.Warn("failed to delete sidecar", "path", sc.Source, "error", err)
+			}
+		}
+	}
+
+	var err error
+	if isMovie {
+		err = s.movies.Delete(ctx, c.ID)
+	} else {
+		err = s.episodes.Delete(ctx, c.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("delete row: %w", err)
+	}
+	return removed, nil
+}
+
+// copySidecars plans the sidecars of a copy being removed, named after target.
+// A copy sharing its folder and base name with the kept one ("Dune.mkv" next
+// to "Dune.mp4") shares its sidecars too, so those stay with the kept copy.
+func copySidecars(c, kept DuplicateCopy, target string, isMovie bool) []OrganizeSidecar {
+	baseName := func(p string) string {
+		return strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+	}
+	if filepath.Dir(c.FilePath) == filepath.Dir(kept.FilePath) && baseName(c.FilePath) == baseName(kept.FilePath) {
+		return nil
+	}
+	return planSidecars(c.FilePath, target, isMovie)
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+)
+
+type fakeDuplicateMovies struct {
+	movies  []models.Movie
+	deleted []string
+}
+
+func (f *fakeDuplicateMovies) FindAllWithFilePath(ctx context.Context) ([]models.Movie, error) {
+	return f.movies, nil
+}
+
+func (f *fakeDuplicateMovies) Delete(ctx context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+type fakeDuplicateEpisodes struct {
+	calendarEpisodes
+	deleted []string
+}
+
+func (f *fakeDuplicateEpisodes) Delete(ctx context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+type fakeProber map[string]*MediaTechInfo
+
+func (f fakeProber) Probe(ctx context.Context, path string) (*MediaTechInfo, error) {
+	if info, ok := f[path]; ok {
+		return info, nil
+	}
+	return nil, ErrFFprobeNotAvailable
+}
+
+func writeDuplicateFile(t *testing.T, path string, size int) string {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+	return path
+}
+
+func duplicateMovie(id string, tmdbID int64, imdbID, path, resolution, hdr, codec string, size int64) models.Movie {
+	m := models.Movie{ID: id, Title: "沙丘", FilePath: models.NewNullString(path), FileSize: models.NewNullInt64(size)}
+	if tmdbID > 0 {
+		m.TMDbID = models.NewNullInt64(tmdbID)
+	}
+	if imdbID != "" {
+		m.IMDbID = models.NewNullString(imdbID)
+	}
+	if resolution != "" {
+		m.VideoResolution = models.NewNullString(resolution)
+		m.HDRFormat = models.NewNullString(hdr)
+		m.VideoCodec = models.NewNullString(codec)
+	}
+	return m
+}
+
+func newDuplicateFixture(t *testing.T) (*DuplicateService, *fakeDuplicateMovies, *fakeDuplicateEpisodes, string) {
+	dir := t.TempDir()
+	web := writeDuplicateFile(t, filepath.Join(dir, "movies", "Dune.2021.1080p.WEB-DL.mkv"), 10)
+	remux := writeDuplicateFile(t, filepath.Join(dir, "movies", "Dune.2021.2160p.Remux.mkv"), 40)
+	dvd := writeDuplicateFile(t, filepath.Join(dir, "movies", "Dune.2021.DVDRip.avi"), 5)
+
+	movies := &fakeDuplicateMovies{movies: []models.Movie{
+		duplicateMovie("m-web", 438631, "tt1160419", web, "1920x1080", "", "h264", 10),
+		duplicateMovie("m-remux", 438631, "", remux, "3840x2160", "HDR10", "hevc", 40),
+		duplicateMovie("m-dvd", 0, "tt1160419", dvd, "", "", "", 5),
+		duplicateMovie("m-solo", 27205, "", filepath.Join(dir, "Inception.mkv"), "1920x1080", "", "h264", 8),
+	}}
+
+	ep1 := writeDuplicateFile(t, filepath.Join(dir, "tv", "a", "S01E01.mkv"), 3)
+	ep2 := writeDuplicateFile(t, filepath.Join(dir, "tv", "b", "S01E01.mkv"), 2)
+	series := calendarSeries{
+		{ID: "s-a", Title: "怪奇物語", TMDbID: models.NewNullInt64(66732)},
+		{ID: "s-b", Title: "怪奇物語", TMDbID: models.NewNullInt64(66732)},
+	}
+	episodes := &fakeDuplicateEpisodes{calendarEpisodes: calendarEpisodes{
+		"s-a": {{ID: "e-a1", SeasonNumber: 1, EpisodeNumber: 1, FilePath: models.NewNullString(ep1)},
+			{ID: "e-a2", SeasonNumber: 1, EpisodeNumber: 2, FilePath: models.NewNullString(filepath.Join(dir, "tv", "a", "S01E02.mkv"))}},
+		"s-b": {{ID: "e-b1", SeasonNumber: 1, EpisodeNumber: 1, FilePath: models.NewNullString(ep2)}},
+	}}
+	prober := fakeProber{
+		ep1: {VideoResolution: "1280x720", VideoCodec: "h264"},
+		ep2: {VideoResolution: "1920x1080", VideoCodec: "hevc"},
+	}
+
+	svc := NewDuplicateService(movies, series, episodes, prober, filepath.Join(dir, "trash"), nil)
+	svc.now = func() time.Time { return time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) }
+	return svc, movies, episodes, dir
+}
+
+func TestDuplicateService_FindDuplicates(t *testing.T) {
+	svc, _, _, _ := newDuplicateFixture(t)
+
+	report, err := svc.FindDuplicates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Groups, 2)
+
+	movie := report.Groups[0]
+	assert.Equal(t, "movie:tmdb:438631", movie.Key)
+	require.Len(t, movie.Copies, 3, "the IMDb-only copy joins the TMDb group")
+	assert.Equal(t, []string{"m-remux", "m-web", "m-dvd"},
+		[]string{movie.Copies[0].ID, movie.Copies[1].ID, movie.Copies[2].ID})
+	assert.True(t, movie.Copies[0].Best)
+	assert.Equal(t, "2160p", movie.Copies[0].Resolution)
+	assert.Equal(t, int64(15), movie.ReclaimableBytes)
+
+	episode := report.Groups[1]
+	assert.Equal(t, "episode:tmdb:66732:s01e01", episode.Key)
+	assert.Equal(t, "e-b1", episode.Copies[0].ID, "probed 1080p beats the larger 720p copy")
+	assert.Equal(t, int64(18), report.ReclaimableBytes)
+}
+
+func TestCompareCopies(t *testing.T) {
+	uhd := DuplicateCopy{Resolution: "2160p", VideoCodec: "hevc", FileSize: 1}
+	hdr := DuplicateCopy{Resolution: "2160p", HDRFormat: "HDR10", VideoCodec: "hevc", FileSize: 1}
+	dv := DuplicateCopy{Resolution: "2160p", HDRFormat: "Dolby Vision", VideoCodec: "hevc", FileSize: 1}
+	lossless := DuplicateCopy{Resolution: "2160p", HDRFormat: "Dolby Vision", VideoCodec: "hevc", AudioCodec: "truehd", FileSize: 1}
+	fullHD := DuplicateCopy{Resolution: "1080p", HDRFormat: "Dolby Vision", VideoCodec: "av1", AudioCodec: "truehd", FileSize: 100}
+
+	assert.Positive(t, compareCopies(hdr, uhd))
+	assert.Positive(t, compareCopies(dv, hdr))
+	assert.Positive(t, compareCopies(lossless, dv))
+	assert.Positive(t, compareCopies(uhd, fullHD), "resolution outranks everything else")
+	assert.Zero(t, compareCopies(uhd, uhd))
+}
+
+func TestDuplicateService_Resolve(t *testing.T) {
+	t.Run("requires confirmation", func(t *testing.T) {
+		svc, movies, _, _ := newDuplicateFixture(t)
+		_, err := svc.Resolve(context.Background(), ResolveDuplicatesRequest{
+			KeepID: "m-remux", RemoveIDs: []string{"m-web"}, Action: DuplicateActionDelete,
+		})
+		assert.ErrorIs(t, err, ErrDuplicateConfirmationRequired)
+		assert.Empty(t, movies.deleted)
+	})
+
+	t.Run("rejects copies outside the group", func(t *testing.T) {
+		svc, movies, _, _ := newDuplicateFixture(t)
+		_, err := svc.Resolve(context.Background(), ResolveDuplicatesRequest{
+			KeepID: "m-remux", RemoveIDs: []string{"m-web", "m-solo"}, Action: DuplicateActionDelete, Confirm: true,
+		})
+		assert.ErrorIs(t, err, ErrDuplicateGroupMismatch)
+		assert.Empty(t, movies.deleted, "nothing is removed when any copy is out of the group")
+	})
+
+	t.Run("trash moves files and drops rows", func(t *testing.T) {
+		svc, movies, _, dir := newDuplicateFixture(t)
+		result, err := svc.Resolve(context.Background(), ResolveDuplicatesRequest{
+			KeepID: "m-remux", RemoveIDs: []string{"m-web", "m-dvd"}, Action: DuplicateActionTrash, Confirm: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Removed, 2)
+		assert.Empty(t, result.Failed)
+		assert.Equal(t, []string{"m-web", "m-dvd"}, movies.deleted)
+
+		assert.NoFileExists(t, filepath.Join(dir, "movies", "Dune.2021.1080p.WEB-DL.mkv"))
+		assert.FileExists(t, filepath.Join(dir, "trash", "20261016-090000", "m-web-Dune.2021.1080p.WEB-DL.mkv"))
+		assert.FileExists(t, filepath.Join(dir, "movies", "Dune.2021.2160p.Remux.mkv"))
+	})
+
+	t.Run("rejects a copy that is the kept file", func(t *testing.T) {
+		svc, movies, _, dir := newDuplicateFixture(t)
+		remux := filepath.Join(dir, "movies", "Dune.2021.2160p.Remux.mkv")
+		seeding := filepath.Join(dir, "downloads", "Dune.2021.2160p.Remux.mkv")
+		require.NoError(t, os.MkdirAll(filepath.Dir(seeding), 0o755))
+		require.NoError(t, os.Link(remux, seeding))
+		movies.movies = append(movies.movies,
+			duplicateMovie("m-link", 438631, "", seeding, "3840x2160", "HDR10", "hevc", 40),
+			duplicateMovie("m-again", 438631, "", remux, "3840x2160", "HDR10", "hevc", 40))
+
+		for _, id := range []string{"m-link", "m-again"} {
+			_, err := svc.Resolve(context.Background(), ResolveDuplicatesRequest{
+				KeepID: "m-remux", RemoveIDs: []string{"m-web", id}, Action: DuplicateActionDelete, Confirm: true,
+			})
+			assert.ErrorIs(t, err, ErrDuplicateSameFile, id)
+		}
+		assert.Empty(t, movies.deleted)
+		assert.FileExists(t, seeding)
+		assert.FileExists(t, remux)
+	})
+
+	t.Run("sidecars go with the removed copy", func(t *testing.T) {
+		svc, _, _, dir := newDuplicateFixture(t)
+		srt := writeDuplicateFile(t, filepath.Join(dir, "movies", "Dune.2021.1080p.WEB-DL.zh-Hant.srt"), 1)
+		nfo := writeDuplicateFile(t, filepath.Join(dir, "movies", "Dune.2021.1080p.WEB-DL.nfo"), 1)
+		dvdSrt := writeDuplicateFile(t, filepath.Join(dir, "movies", "Dune.2021.DVDRip.srt"), 1)
+		keptSrt := writeDuplicateFile(t, filepath.Join(dir, "movies", "Dune.2021.2160p.Remux.zh-Hant.srt"), 1)
+
+		_, err := svc.Resolve(context.Background(), ResolveDuplicatesRequest{
+			KeepID: "m-remux", RemoveIDs: []string{"m-web"}, Action: DuplicateActionTrash, Confirm: true,
+		})
+		require.NoError(t, err)
+		trash := filepath.Join(dir, "trash", "20261016-090000")
+		assert.NoFileExists(t, srt)
+		assert.NoFileExists(t, nfo)
+		assert.FileExists(t, filepath.Join(trash, "m-web-Dune.2021.1080p.WEB-DL.zh-Hant.srt"))
+		assert.FileExists(t, filepath.Join(trash, "m-web-Dune.2021.1080p.WEB-DL.nfo"))
+
+		_, err = svc.Resolve(context.Background(), ResolveDuplicatesRequest{
+			KeepID: "m-remux", RemoveIDs: []string{"m-dvd"}, Action: DuplicateActionDelete, Confirm: true,
+		})
+		require.NoError(t, err)
+		assert.NoFileExists(t, dvdSrt)
+		assert.FileExists(t, keptSrt, "the kept copy's sidecars stay")
+	})
+
+	t.Run("delete removes episode files", func(t *testing.T) {
+		svc, _, episodes, dir := newDuplicateFixture(t)
+		result, err := svc.Resolve(context.Background(), ResolveDuplicatesRequest{
+			MediaType: models.RequestMediaTypeTV,
+			KeepID:    "e-b1", RemoveIDs: []string{"e-a1"}, Action: DuplicateActionDelete, Confirm: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Removed, 1)
+		assert.Equal(t, []string{"e-a1"}, episodes.deleted)
+		assert.NoFileExists(t, filepath.Join(dir, "tv", "a", "S01E01.mkv"))
+	})
+}
@@ -201,14 +201,19 @@ func (s *DVRSettingsService) profileLister(ctx context.Context, plugin string) (
 // effectiveAPIKey resolves the key to use: the provided one, or the stored
 // one when the input omits it (the GET response never echoes the key back).
 func (s *DVRSettingsService) effectiveAPIKey(ctx context.Context, plugin, inputKey string) (string, error) {
+	return effectivePluginAPIKey(ctx, s.secrets, plugin, inputKey)
+}
+
+// effectivePluginAPIKey is effectiveAPIKey for any plugin's settings service.
+func effectivePluginAPIKey(ctx context.Context, secretsService secrets.SecretsServiceInterface, plugin, inputKey string) (string, error) {
 	if inputKey != "" {
 		return inputKey, nil
 	}
-	exists, _ := s.secrets.Exists(ctx, plugins.SettingKeyAPIKey(plugin))
+	exists, _ := secretsService.Exists(ctx, plugins.SettingKeyAPIKey(plugin))
 	if !exists {
 		return "", nil
 	}
-	stored, err := s.secrets.Retrieve(ctx, plugins.SettingKeyAPIKey(plugin))
+	stored, err := secretsService.Retrieve(ctx, plugins.SettingKeyAPIKey(plugin))
 	if err != nil {
 		return "", fmt.Errorf("decrypt stored api key: %w", err)
 	}
@@ -36,7 +36,14 @@ func (f *fakeDVRSettingsRepo) Get(ctx context.Context, key string) (*models.Sett
 func (f *fakeDVRSettingsRepo) GetAll(ctx context.Context) ([]models.Setting, error) {
 	return nil, nil
 }
-func (f *fakeDVRSettingsRepo) Delete(ctx context.Context, key string) error { return nil }
+func (f *fakeDVRSettingsRepo) Delete(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.strings, key)
+	delete(f.bools, key)
+	delete(f.ints, key)
+	return nil
+}
 func (f *fakeDVRSettingsRepo) GetString(ctx context.Context, key string) (string, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
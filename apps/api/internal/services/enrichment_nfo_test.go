@@ -52,6 +52,10 @@ func (m *mockTMDbServiceForNFO) GetTVExternalIDs(ctx context.Context, tvID int)
 	return &tmdb.TVExternalIDs{ID: int64(tvID)}, nil
 }
 
+func (m *mockTMDbServiceForNFO) GetCollection(ctx context.Context, collectionID int) (*tmdb.CollectionDetails, error) {
+	return &tmdb.CollectionDetails{ID: collectionID}, nil
+}
+
 func (m *mockTMDbServiceForNFO) FindByExternalID(ctx context.Context, externalID string, externalSource string) (*tmdb.FindByExternalIDResponse, error) {
 	if m.findByExtErr != nil {
 		return nil, m.findByExtErr
@@ -188,6 +192,9 @@ func (m *mockMovieRepoForNFO) CountMissingZhHantSubtitle(ctx context.Context) (i
 func (m *mockMovieRepoForNFO) FindAllWithFilePath(ctx context.Context) ([]models.Movie, error) {
 	return nil, nil
 }
+func (m *mockMovieRepoForNFO) FindAllWithFilePathByTMDbID(ctx context.Context, tmdbID int64) ([]models.Movie, error) {
+	return nil, nil
+}
 func (m *mockMovieRepoForNFO) GetStats(ctx context.Context) (*repository.MediaStats, error) {
 	return &repository.MediaStats{}, nil
 }
@@ -268,6 +275,37 @@ func TestEnrichMovie_NFO_TMDbDirectLookup(t *testing.T) {
 	assert.Equal(t, int64(6), mockRepo.updatedMovie.AudioChannels.Int64)
 }
 
+// recordingCollections captures what enrichment hands the collections view.
+type recordingCollections struct{ recorded []*tmdb.MovieDetails }
+
+func (r *recordingCollections) RecordMovieCollection(ctx context.Context, details *tmdb.MovieDetails) error {
+	r.recorded = append(r.recorded, details)
+	return nil
+}
+
+func TestEnrichMovie_NFO_RecordsCollection(t *testing.T) {
+	dir := t.TempDir()
+	videoPath := filepath.Join(dir, "Harry.Potter.2001.mkv")
+	nfoContent := `<movie><uniqueid type="tmdb">671</uniqueid></movie>`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Harry.Potter.2001.nfo"), []byte(nfoContent), 0o644))
+
+	mockTMDb := &mockTMDbServiceForNFO{
+		getMovieDetailsResp: &tmdb.MovieDetails{
+			Movie:               tmdb.Movie{ID: 671, Title: "哈利波特：神秘的魔法石"},
+			BelongsToCollection: &tmdb.CollectionRef{ID: 1241, Name: "哈利波特（系列）"},
+		},
+	}
+	recorder := &recordingCollections{}
+	svc := NewEnrichmentService(&mockMovieRepoForNFO{}, nil, nil, NewNFOReaderService(nil), mockTMDb, nil, nil, nil)
+	svc.SetCollectionRecorder(recorder)
+
+	movie := &models.Movie{ID: "hp1", Title: "Harry.Potter.2001.mkv", FilePath: models.NewNullString(videoPath)}
+	require.NoError(t, svc.enrichMovie(context.Background(), movie))
+
+	require.Len(t, recorder.recorded, 1)
+	assert.Equal(t, 1241, recorder.recorded[0].BelongsToCollection.ID)
+}
+
 // ─── Test: NFO enrichment — IMDB ID find (AC #3) ──────────────────────────
 
 func TestEnrichMovie_NFO_IMDbLookup(t *testing.T) {
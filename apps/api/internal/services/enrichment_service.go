@@ -50,8 +50,10 @@ type EnrichmentService struct {
 	nfoReader       *NFOReaderService
 	tmdbService     TMDbServiceInterface
 	ffprobeService  *FFprobeService
-	sseHub          *sse.Hub
-	logger          *slog.Logger
+	// collections is optional: without it TMDb collection info is dropped.
+	collections CollectionRecorder
+	sseHub      *sse.Hub
+	logger      *slog.Logger
 
 	mu          sync.Mutex
 	isEnriching bool
@@ -355,6 +357,12 @@ func (s *EnrichmentService) SetSeriesRepo(repo repository.SeriesRepositoryInterf
 	s.seriesRepo = repo
 }
 
+// SetCollectionRecorder records the TMDb collection of every movie enriched
+// from TMDb, feeding the collections view.
+func (s *EnrichmentService) SetCollectionRecorder(recorder CollectionRecorder) {
+	s.collections = recorder
+}
+
 // findUnenrichedMovies queries for movies with empty or pending parse_status.
 func (s *EnrichmentService) findUnenrichedMovies(ctx context.Context) ([]models.Movie, error) {
 	pending, err := s.movieRepo.FindByParseStatus(ctx, models.ParseStatusPending)
@@ -452,6 +460,12 @@ func (s *EnrichmentService) enrichMovie(ctx context.Context, movie *models.Movie
 		"tmdb_id", movie.TMDbID.Int64,
 	)
 
+	// The search result carries no collection, so a TMDb match is followed
+	// up with the (cached) details lookup.
+	if searchResult.Source == models.MetadataSourceTMDb {
+		s.recordCollection(ctx, movie, nil)
+	}
+
 	return nil
 }
 
@@ -591,6 +605,7 @@ func (s *EnrichmentService) enrichFromNFOWithTMDb(ctx context.Context, movie *mo
 			return fmt.Errorf("tmdb get movie %d: %w", tmdbID, err)
 		}
 		s.applyTMDbMovieDetails(movie, details)
+		s.recordCollection(ctx, movie, details)
 		return nil
 	}
 
@@ -675,6 +690,29 @@ func (s *EnrichmentService) applyTMDbMovieDetails(movie *models.Movie, details *
 	}
 }
 
+// recordCollection hands the movie's TMDb collection to the recorder, fetching
+// the details when the caller has none. Best-effort: a failure is logged and
+// never fails the enrichment.
+func (s *EnrichmentService) recordCollection(ctx context.Context, movie *models.Movie, details *tmdb.MovieDetails) {
+	if s.collections == nil {
+		return
+	}
+	if details == nil {
+		if !movie.TMDbID.Valid || movie.TMDbID.Int64 <= 0 {
+			return
+		}
+		var err error
+		details, err = s.tmdbService.GetMovieDetails(ctx, int(movie.TMDbID.Int64))
+		if err != nil {
+			s.logger.Warn("collection lookup failed", "movie_id", movie.ID, "error", err)
+			return
+		}
+	}
+	if err := s.collections.RecordMovieCollection(ctx, details); err != nil {
+		s.logger.Warn("failed to record collection", "movie_id", movie.ID, "error", err)
+	}
+}
+
 // enrichFromIMDbID uses IMDB ID to find the movie on TMDB via /find endpoint
 func (s *EnrichmentService) enrichFromIMDbID(ctx context.Context, movie *models.Movie, imdbID string) error {
 	findResult, err := s.tmdbService.FindByExternalID(ctx, imdbID, "imdb_id")
@@ -690,6 +728,7 @@ func (s *EnrichmentService) enrichFromIMDbID(ctx context.Context, movie *models.
 			return fmt.Errorf("tmdb get movie %d (from imdb %s): %w", tmdbID, imdbID, err)
 		}
 		s.applyTMDbMovieDetails(movie, details)
+		s.recordCollection(ctx, movie, details)
 		return nil
 	}
 
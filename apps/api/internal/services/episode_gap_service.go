@@ -0,0 +1,341 @@
+// Package services EpisodeGapService — missing episodes and season gaps.
+//
+// MediaIngestService records the episodes we have files for; this service
+// diffs them against TMDb's season details (through the TMDb cache) to find
+// what aired but never arrived. Specials (season 0) are left out unless asked
+// for, and episodes that have not aired yet never count as missing. A series'
+// gaps come back as a partial request selection — fully-missing seasons on
+// the seasons side, scattered episodes on the episodes side — so they can be
+// requested through the regular RequestService create path.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/tmdb"
+)
+
+// ErrSeriesUnmatched is returned for a series without a TMDb match: there is
+// no episode list to compare against.
+var ErrSeriesUnmatched = errors.New("series has no TMDb match")
+
+// Skip reasons reported by RequestGaps.
+const (
+	GapSkipNoGaps           = "no_gaps"
+	GapSkipUnmatched        = "unmatched"
+	GapSkipAlreadyRequested = "already_requested"
+	GapSkipFailed           = "failed"
+)
+
+// EpisodeGapServiceInterface defines the contract for gap detection.
+type EpisodeGapServiceInterface interface {
+	SeriesGaps(ctx context.Context, seriesID string, opts GapOptions) (*SeriesGaps, error)
+	// LibraryGaps returns every matched series with at least one missing
+	// episode. A series TMDb cannot answer for is logged and left out.
+	LibraryGaps(ctx context.Context, opts GapOptions) ([]SeriesGaps, error)
+	// RequestGaps creates one partial request per series covering its gaps.
+	// No series IDs means every series with gaps.
+	RequestGaps(ctx context.Context, seriesIDs []string, opts GapOptions) (*GapRequestResult, error)
+}
+
+// GapTMDbReader is the narrow TMDb port gap detection reads through.
+// TMDbServiceInterface satisfies it; both calls are cached.
+type GapTMDbReader interface {
+	GetTVShowDetails(ctx context.Context, tvID int) (*tmdb.TVShowDetails, error)
+	GetSeasonDetails(ctx context.Context, tvID int, seasonNumber int) (*tmdb.SeasonDetails, error)
+}
+
+// GapSeriesReader is the series-repo subset gap detection needs.
+// *repository.SeriesRepository satisfies it.
+type GapSeriesReader interface {
+	FindByID(ctx context.Context, id string) (*models.Series, error)
+	FindByParseStatus(ctx context.Context, status models.ParseStatus) ([]models.Series, error)
+}
+
+// GapOptions tunes the analysis.
+type GapOptions struct {
+	// IncludeSpecials also diffs season 0.
+	IncludeSpecials bool
+}
+
+// SeasonGap is one season with at least one missing episode.
+type SeasonGap struct {
+	SeasonNumber int `json:"season_number"`
+	// AiredCount is how many of the season's episodes have aired.
+	AiredCount int `json:"aired_count"`
+	OwnedCount int `json:"owned_count"`
+	// UnairedCount is how many episodes are announced but not yet aired.
+	UnairedCount int   `json:"unaired_count"`
+	Missing      []int `json:"missing"`
+}
+
+// wholeSeason reports a finished season of which nothing is owned; it is
+// requested as a season rather than episode by episode.
+func (g SeasonGap) wholeSeason() bool {
+	return g.OwnedCount == 0 && g.UnairedCount == 0
+}
+
+// SeriesGaps is a series' missing episodes. RequestSeasons/RequestEpisodes
+// carry the gaps in the POST /api/v1/requests body vocabulary.
+type SeriesGaps struct {
+	SeriesID        string           `json:"series_id"`
+	Title           string           `json:"title"`
+	TMDbID          int64            `json:"tmdb_id"`
+	PosterPath      string           `json:"poster_path,omitempty"`
+	MissingCount    int              `json:"missing_count"`
+	Seasons         []SeasonGap      `json:"seasons"`
+	RequestSeasons  []int            `json:"request_seasons"`
+	RequestEpisodes map[string][]int `json:"request_episodes"`
+}
+
+// Selection returns the gaps as a canonical partial selection, nil when
+// nothing is missing.
+func (g *SeriesGaps) Selection() *RequestSelection {
+	if g.MissingCount == 0 {
+		return nil
+	}
+	sel := &RequestSelection{}
+	for _, season := range g.Seasons {
+		if season.wholeSeason() {
+			sel.Seasons = append(sel.Seasons, season.SeasonNumber)
+			continue
+		}
+		if sel.Episodes == nil {
+			sel.Episodes = make(map[int][]int)
+		}
+		sel.Episodes[season.SeasonNumber] = season.Missing
+	}
+	return sel
+}
+
+// GapRequestSkip is a series RequestGaps did not request, and why.
+type GapRequestSkip struct {
+	SeriesID string `json:"series_id"`
+	Title    string `json:"title,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// GapRequestResult is the outcome of RequestGaps.
+type GapRequestResult struct {
+	Requested []models.Request `json:"requested"`
+	Skipped   []GapRequestSkip `json:"skipped"`
+}
+
+// EpisodeGapService implements EpisodeGapServiceInterface.
+type EpisodeGapService struct {
+	series   GapSeriesReader
+	episodes EpisodeOwnershipReader
+	tmdb     GapTMDbReader
+	requests RequestServiceInterface
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// Compile-time verification.
+var _ EpisodeGapServiceInterface = (*EpisodeGapService)(nil)
+
+// NewEpisodeGapService builds an EpisodeGapService. Requests go through the
+// RequestService so selection validation and duplicate guards stay in one
+// place.
+func NewEpisodeGapService(
+	series GapSeriesReader,
+	episodes EpisodeOwnershipReader,
+	tmdbReader GapTMDbReader,
+	requests RequestServiceInterface,
+	logger *slog.Logger,
+) *EpisodeGapService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &EpisodeGapService{
+		series: series, episodes: episodes, tmdb: tmdbReader, requests: requests,
+		logger: logger.With("service", "episode_gaps"),
+		now:    time.Now,
+	}
+}
+
+func (s *EpisodeGapService) SeriesGaps(ctx context.Context, seriesID string, opts GapOptions) (*SeriesGaps, error) {
+	series, err := s.series.FindByID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return s.analyze(ctx, series, opts)
+}
+
+func (s *EpisodeGapService) LibraryGaps(ctx context.Context, opts GapOptions) ([]SeriesGaps, error) {
+	seriesList, err := s.series.FindByParseStatus(ctx, models.ParseStatusSuccess)
+	if err != nil {
+		return nil, fmt.Errorf("list series: %w", err)
+	}
+
+	out := []SeriesGaps{}
+	for i := range seriesList {
+		series := &seriesList[i]
+		if !series.TMDbID.Valid || series.TMDbID.Int64 <= 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		gaps, err := s.analyze(ctx, series, opts)
+		if err != nil {
+			s.logger.Warn("gap analysis skipped series", "series_id", series.ID, "tmdb_id", series.TMDbID.Int64, "error", err)
+			continue
+		}
+		if gaps.MissingCount > 0 {
+			out = append(out, *gaps)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Title < out[j].Title })
+	return out, nil
+}
+
+func (s *EpisodeGapService) RequestGaps(ctx context.Context, seriesIDs []string, opts GapOptions) (*GapRequestResult, error) {
+	var targets []SeriesGaps
+	result := &GapRequestResult{Requested: []models.Request{}, Skipped: []GapRequestSkip{}}
+	if len(seriesIDs) == 0 {
+		all, err := s.LibraryGaps(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		targets = all
+	} else {
+		for _, id := range seriesIDs {
+			gaps, err := s.SeriesGaps(ctx, id, opts)
+			switch {
+			case err == nil:
+				targets = append(targets, *gaps)
+			case errors.Is(err, ErrSeriesUnmatched):
+				result.Skipped = append(result.Skipped, GapRequestSkip{SeriesID: id, Reason: GapSkipUnmatched})
+			default:
+				s.logger.Warn("gap analysis failed", "series_id", id, "error", err)
+				result.Skipped = append(result.Skipped, GapRequestSkip{SeriesID: id, Reason: GapSkipFailed})
+			}
+		}
+	}
+
+	for i := range targets {
+		gaps := &targets[i]
+		skip := GapRequestSkip{SeriesID: gaps.SeriesID, Title: gaps.Title}
+		if gaps.MissingCount == 0 {
+			skip.Reason = GapSkipNoGaps
+			result.Skipped = append(result.Skipped, skip)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		request, err := s.requests.CreateRequest(ctx, CreateMediaRequestRequest{
+			TMDbID:    gaps.TMDbID,
+			MediaType: models.RequestMediaTypeTV,
+			Seasons:   gaps.RequestSeasons,
+			Episodes:  gaps.RequestEpisodes,
+		})
+		switch {
+		case err == nil:
+			result.Requested = append(result.Requested, *request)
+			continue
+		case errors.Is(err, repository.ErrRequestDuplicate):
+			skip.Reason = GapSkipAlreadyRequested
+		default:
+			s.logger.Warn("failed to request series gaps",
+				"series_id", gaps.SeriesID, "tmdb_id", gaps.TMDbID, "error", err)
+			skip.Reason = GapSkipFailed
+		}
+		result.Skipped = append(result.Skipped, skip)
+	}
+
+	s.logger.Info("series gaps requested", "requested", len(result.Requested), "skipped", len(result.Skipped))
+	return result, nil
+}
+
+// analyze diffs one series' local episodes against TMDb. Only seasons that
+// have started airing are fetched.
+func (s *EpisodeGapService) analyze(ctx context.Context, series *models.Series, opts GapOptions) (*SeriesGaps, error) {
+	if !series.TMDbID.Valid || series.TMDbID.Int64 <= 0 {
+		return nil, fmt.Errorf("series %s: %w", series.ID, ErrSeriesUnmatched)
+	}
+	tvID := int(series.TMDbID.Int64)
+	today := s.now().Format(time.DateOnly)
+
+	show, err := s.tmdb.GetTVShowDetails(ctx, tvID)
+	if err != nil {
+		return nil, fmt.Errorf("show details: %w", err)
+	}
+	local, err := s.episodes.FindBySeriesID(ctx, series.ID)
+	if err != nil {
+		return nil, fmt.Errorf("local episodes: %w", err)
+	}
+	owned := ownedEpisodeKeys(local)
+
+	gaps := &SeriesGaps{
+		SeriesID:        series.ID,
+		Title:           series.Title,
+		TMDbID:          series.TMDbID.Int64,
+		Seasons:         []SeasonGap{},
+		RequestSeasons:  []int{},
+		RequestEpisodes: map[string][]int{},
+	}
+	if series.PosterPath.Valid {
+		gaps.PosterPath = series.PosterPath.String
+	}
+
+	seasons := append([]tmdb.Season(nil), show.Seasons...)
+	sort.Slice(seasons, func(i, j int) bool { return seasons[i].SeasonNumber < seasons[j].SeasonNumber })
+	for _, summary := range seasons {
+		if summary.SeasonNumber == 0 && !opts.IncludeSpecials {
+			continue
+		}
+		if summary.EpisodeCount == 0 || summary.AirDate == nil || *summary.AirDate > today {
+			continue
+		}
+		details, err := s.tmdb.GetSeasonDetails(ctx, tvID, summary.SeasonNumber)
+		if err != nil {
+			return nil, fmt.Errorf("season %d: %w", summary.SeasonNumber, err)
+		}
+		gap := seasonGap(summary.SeasonNumber, details.Episodes, owned, today)
+		if len(gap.Missing) > 0 {
+			gaps.Seasons = append(gaps.Seasons, gap)
+			gaps.MissingCount += len(gap.Missing)
+		}
+	}
+
+	if sel := gaps.Selection(); sel != nil {
+		if sel.Seasons != nil {
+			gaps.RequestSeasons = sel.Seasons
+		}
+		for season, eps := range sel.Episodes {
+			gaps.RequestEpisodes[strconv.Itoa(season)] = eps
+		}
+	}
+	return gaps, nil
+}
+
+// seasonGap counts one season. An episode without an air date, or airing
+// after today, is unaired and never missing.
+func seasonGap(seasonNumber int, episodes []tmdb.EpisodeInfo, owned map[episodeKey]bool, today string) SeasonGap {
+	gap := SeasonGap{SeasonNumber: seasonNumber}
+	for _, ep := range episodes {
+		if ep.AirDate == nil || *ep.AirDate == "" || *ep.AirDate > today {
+			gap.UnairedCount++
+			continue
+		}
+		gap.AiredCount++
+		if owned[episodeKey{seasonNumber, ep.EpisodeNumber}] {
+			gap.OwnedCount++
+			continue
+		}
+		gap.Missing = append(gap.Missing, ep.EpisodeNumber)
+	}
+	gap.Missing = sortedUniqueInts(gap.Missing)
+	return gap
+}
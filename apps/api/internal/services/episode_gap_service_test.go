@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/tmdb"
+)
+
+type gapSeries []models.Series
+
+func (g gapSeries) FindByID(ctx context.Context, id string) (*models.Series, error) {
+	for i := range g {
+		if g[i].ID == id {
+			return &g[i], nil
+		}
+	}
+	return nil, fmt.Errorf("series with id %s not found: %w", id, sql.ErrNoRows)
+}
+
+func (g gapSeries) FindByParseStatus(ctx context.Context, status models.ParseStatus) ([]models.Series, error) {
+	return g, nil
+}
+
+// gapRequester records the create bodies it was handed.
+type gapRequester struct {
+	fakeCollectionRequester
+	bodies []CreateMediaRequestRequest
+}
+
+func (f *gapRequester) CreateRequest(ctx context.Context, req CreateMediaRequestRequest) (*models.Request, error) {
+	f.bodies = append(f.bodies, req)
+	return f.fakeCollectionRequester.CreateRequest(ctx, req)
+}
+
+func newGapFixture() (*EpisodeGapService, *gapRequester) {
+	date := func(d string) *string { return &d }
+	reader := &fakeCalendarTMDb{
+		shows: map[int]*tmdb.TVShowDetails{
+			100: {Seasons: []tmdb.Season{
+				{SeasonNumber: 0, EpisodeCount: 1, AirDate: date("2020-01-01")},
+				{SeasonNumber: 1, EpisodeCount: 3, AirDate: date("2020-01-01")},
+				{SeasonNumber: 2, EpisodeCount: 2, AirDate: date("2021-01-01")},
+				{SeasonNumber: 3, EpisodeCount: 2, AirDate: date("2026-10-01")},
+				{SeasonNumber: 4, EpisodeCount: 0, AirDate: date("2027-04-01")},
+			}},
+			200: {Seasons: []tmdb.Season{{SeasonNumber: 1, EpisodeCount: 1, AirDate: date("2019-01-01")}}},
+		},
+		seasons: map[[2]int]*tmdb.SeasonDetails{
+			{100, 0}: {Episodes: []tmdb.EpisodeInfo{airing(0, 1, "2020-06-01", "OVA")}},
+			{100, 1}: {Episodes: []tmdb.EpisodeInfo{
+				airing(1, 1, "2020-01-01", ""), airing(1, 2, "2020-01-08", ""), airing(1, 3, "2020-01-15", ""),
+			}},
+			{100, 2}: {Episodes: []tmdb.EpisodeInfo{airing(2, 1, "2021-01-01", ""), airing(2, 2, "2021-01-08", "")}},
+			{100, 3}: {Episodes: []tmdb.EpisodeInfo{
+				airing(3, 1, "2026-10-01", ""), airing(3, 2, "2026-10-22", ""), {SeasonNumber: 3, EpisodeNumber: 3},
+			}},
+			{200, 1}: {Episodes: []tmdb.EpisodeInfo{airing(1, 1, "2019-01-01", "")}},
+		},
+	}
+	owned := func(season, episode int) models.Episode {
+		return models.Episode{SeasonNumber: season, EpisodeNumber: episode,
+			FilePath: models.NewNullString(fmt.Sprintf("/tv/S%02dE%02d.mkv", season, episode))}
+	}
+	series := gapSeries{
+		{ID: "s1", Title: "進擊的巨人", TMDbID: models.NewNullInt64(100)},
+		{ID: "s2", Title: "Complete", TMDbID: models.NewNullInt64(200)},
+		{ID: "s3", Title: "Unmatched"},
+	}
+	episodes := calendarEpisodes{
+		"s1": {owned(1, 1), owned(1, 3)},
+		"s2": {owned(1, 1)},
+	}
+	requests := &gapRequester{}
+	svc := NewEpisodeGapService(series, episodes, reader, requests, nil)
+	svc.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	return svc, requests
+}
+
+func TestEpisodeGapService_SeriesGaps(t *testing.T) {
+	svc, _ := newGapFixture()
+
+	gaps, err := svc.SeriesGaps(context.Background(), "s1", GapOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, gaps.MissingCount)
+	require.Len(t, gaps.Seasons, 3)
+	assert.Equal(t, SeasonGap{SeasonNumber: 1, AiredCount: 3, OwnedCount: 2, Missing: []int{2}}, gaps.Seasons[0])
+	assert.Equal(t, SeasonGap{SeasonNumber: 3, AiredCount: 1, UnairedCount: 2, Missing: []int{1}}, gaps.Seasons[2],
+		"unaired episodes are counted but never missing")
+
+	assert.Equal(t, []int{2}, gaps.RequestSeasons, "a finished season with nothing owned is requested whole")
+	assert.Equal(t, map[string][]int{"1": {2}, "3": {1}}, gaps.RequestEpisodes)
+
+	withSpecials, err := svc.SeriesGaps(context.Background(), "s1", GapOptions{IncludeSpecials: true})
+	require.NoError(t, err)
+	assert.Equal(t, 5, withSpecials.MissingCount)
+	assert.Equal(t, []int{0, 2}, withSpecials.RequestSeasons)
+
+	_, err = svc.SeriesGaps(context.Background(), "s3", GapOptions{})
+	assert.ErrorIs(t, err, ErrSeriesUnmatched)
+}
+
+func TestEpisodeGapService_RequestGaps(t *testing.T) {
+	svc, requests := newGapFixture()
+	requests.errs = map[int64]error{}
+
+	result, err := svc.RequestGaps(context.Background(), nil, GapOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Requested, 1, "only series with gaps are requested")
+	require.Len(t, requests.bodies, 1)
+	assert.Equal(t, CreateMediaRequestRequest{
+		TMDbID: 100, MediaType: models.RequestMediaTypeTV,
+		Seasons: []int{2}, Episodes: map[string][]int{"1": {2}, "3": {1}},
+	}, requests.bodies[0])
+
+	requests.errs[100] = repository.ErrRequestDuplicate
+	result, err = svc.RequestGaps(context.Background(), []string{"s1", "s2", "s3"}, GapOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Requested)
+	reasons := map[string]string{}
+	for _, skip := range result.Skipped {
+		reasons[skip.SeriesID] = skip.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"s1": GapSkipAlreadyRequested, "s2": GapSkipNoGaps, "s3": GapSkipUnmatched,
+	}, reasons)
+}
@@ -45,6 +45,9 @@ func (m *mockTMDbServiceForExplore) GetSeasonDetails(ctx context.Context, id int
 func (m *mockTMDbServiceForExplore) GetTVExternalIDs(ctx context.Context, tvID int) (*tmdb.TVExternalIDs, error) {
 	return &tmdb.TVExternalIDs{ID: int64(tvID)}, nil
 }
+func (m *mockTMDbServiceForExplore) GetCollection(ctx context.Context, collectionID int) (*tmdb.CollectionDetails, error) {
+	return &tmdb.CollectionDetails{ID: collectionID}, nil
+}
 func (m *mockTMDbServiceForExplore) FindByExternalID(ctx context.Context, id, src string) (*tmdb.FindByExternalIDResponse, error) {
 	return &tmdb.FindByExternalIDResponse{}, nil
 }
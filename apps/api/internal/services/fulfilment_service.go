@@ -66,6 +66,11 @@ func (s *FulfilmentService) FulfilRequest(ctx context.Context, request *models.R
 	if request == nil {
 		return
 	}
+	if request.FulfilmentSource.String == models.RequestFulfilmentSourceBuiltin {
+		// Claimed by the built-in path (quality upgrades of owned titles):
+		// adding an owned title to an *arr is not how it gets upgraded.
+		return
+	}
 
 	if request.MediaType == models.RequestMediaTypeTV {
 		// 13-4b AC #4 — Sonarr adds; 13-2a — the stored selection (if any)
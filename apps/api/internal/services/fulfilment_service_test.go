@@ -343,3 +343,18 @@ func TestFulfilmentService_TVMalformedSelectionStaysPendingHonestly(t *testing.T
 	assert.Equal(t, "請求的選取資料無法解析", req.ErrorMessage.String)
 	assert.Equal(t, int64(0), env.sonarrPlugin.lastAddTMDb, "sonarr must never be called on a malformed selection")
 }
+
+func TestFulfilmentService_BuiltinUpgradeRequestIsLeftAlone(t *testing.T) {
+	// A quality-upgrade request is born on the built-in path; handing it to
+	// Radarr would add a movie Radarr may already manage.
+	env := newFulfilmentTestEnv(t)
+	req := pendingMovieRequest()
+	req.FulfilmentSource = models.NewNullString(models.RequestFulfilmentSourceBuiltin)
+	req.UpgradeCutoff = models.NewNullString("1080p")
+
+	env.service.FulfilRequest(context.Background(), req)
+
+	assert.Equal(t, models.RequestStatusPending, req.Status)
+	assert.Equal(t, 0, env.plugin.addMovieHits)
+	assert.Empty(t, env.repo.updates)
+}
@@ -131,7 +131,9 @@ type GenerationBatchProcessor struct {
 	// budgetUSD is the DEFAULT ceiling (AI_RUN_BUDGET_USD; <=0 = unlimited),
 	// used only when Start receives no user-approved ceiling (sub-4-2 AC #1).
 	budgetUSD float64
-	logger    *slog.Logger
+	// spendGuard holds the global daily/monthly caps (nil = none).
+	spendGuard ai.SpendGuard
+	logger     *slog.Logger
 
 	mu           sync.Mutex
 	activeBatch  *GenerationBatchProgress
@@ -163,6 +165,21 @@ func NewGenerationBatchProcessor(
 	}
 }
 
+// SetSpendGuard wires the global AI spend caps: Start refuses a batch once a
+// cap is used up, and a cap reached mid-batch pauses the rest of the queue
+// like the batch ceiling does. Nil-safe.
+func (p *GenerationBatchProcessor) SetSpendGuard(g ai.SpendGuard) {
+	p.spendGuard = g
+}
+
+// checkSpend reports a used-up global cap, or nil.
+func (p *GenerationBatchProcessor) checkSpend(ctx context.Context) error {
+	if p.spendGuard == nil {
+		return nil
+	}
+	return p.spendGuard.CheckSpend(ctx)
+}
+
 // IsAvailable reports whether the underlying generation pipeline can run
 // (FFmpeg + ASR configured) — the handler's 503 TRANSCRIPTION_DISABLED gate.
 func (p *GenerationBatchProcessor) IsAvailable() bool {
@@ -253,7 +270,8 @@ func (p *GenerationBatchProcessor) PreviewMissing(ctx context.Context) (movies,
 // 0 means "not provided → use the configured default". The handler validates
 // user input to be strictly > 0, so 0 can only mean absent — user input is
 // NEVER mapped onto ai.NewBudget's <=0 = unlimited semantic.
-// Errors: ErrGenerationBatchRunning (409), ErrGenerationSelectionInvalid (400).
+// Errors: ErrGenerationBatchRunning (409), ErrGenerationSelectionInvalid (400),
+// ai.ErrSpendCapReached when a global spend cap is used up.
 func (p *GenerationBatchProcessor) Start(ctx context.Context, scope string, mediaIDs []string, budgetUSD float64) (string, []GenerationBatchItem, error) {
 	// Quick check — release the lock before DB queries (fetch-batch H1 fix).
 	p.mu.Lock()
@@ -263,6 +281,10 @@ func (p *GenerationBatchProcessor) Start(ctx context.Context, scope string, medi
 	}
 	p.mu.Unlock()
 
+	if err := p.checkSpend(ctx); err != nil {
+		return "", nil, err
+	}
+
 	items, err := p.collectItems(ctx, scope, mediaIDs)
 	if err != nil {
 		return "", nil, err
@@ -288,7 +310,7 @@ func (p *GenerationBatchProcessor) Start(ctx context.Context, scope string, medi
 	}
 	budget := ai.NewBudget(ceiling)
 	processCtx, processCancel := context.WithCancel(context.Background())
-	processCtx = ai.WithBudget(processCtx, budget)
+	processCtx = ai.WithUsageRun(ai.WithBudget(processCtx, budget), batchID)
 
 	p.activeBatch = &GenerationBatchProgress{
 		BatchID:    batchID,
@@ -439,7 +461,8 @@ func (p *GenerationBatchProcessor) process(ctx context.Context, batchID string,
 
 		// AC 7: budget pre-check — an exhausted envelope pauses this item and
 		// everything queued behind it (paused, NOT failed).
-		if budget.Exceeded() {
+		// The global spend caps pause it the same way.
+		if budget.Exceeded() || p.checkSpend(ctx) != nil {
 			paused := len(items) - i
 			p.finish(batchID, GenerationBatchStatusBudgetCeiling, len(items), i, item, successCount, failCount, paused, budget)
 			return
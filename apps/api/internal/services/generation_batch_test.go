@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -809,3 +810,41 @@ func TestGenerationBatch_SkippedItemCountsAsFail(t *testing.T) {
 	assert.Equal(t, 2, last["success_count"], "success means a subtitle exists — skips are not successes")
 	assert.Equal(t, 1, last["fail_count"])
 }
+
+// fakeSpendGuard refuses once closed is set.
+type fakeSpendGuard struct{ closed atomic.Bool }
+
+func (g *fakeSpendGuard) CheckSpend(context.Context) error {
+	if g.closed.Load() {
+		return &SpendCapError{Period: "daily", CapUSD: 1, SpentUSD: 1}
+	}
+	return nil
+}
+
+// A used-up global cap refuses the batch up front; one reached mid-batch
+// pauses the rest of the queue like the batch ceiling.
+func TestGenerationBatch_SpendCap(t *testing.T) {
+	guard := &fakeSpendGuard{}
+	runner := &fakeGenerationRunner{
+		available: true,
+		onCall: func(ctx context.Context, mediaID string) error {
+			guard.closed.Store(true)
+			return nil
+		},
+	}
+	finder := &fakeCandidateFinder{movies: []models.Movie{
+		genMovie(uuidA, "A", "/m/a.mkv"),
+		genMovie(uuidB, "B", "/m/b.mkv"),
+	}}
+	p, _ := newTestGenerationProcessor(t, runner, finder, 5)
+	p.SetSpendGuard(guard)
+
+	_, _, err := p.Start(context.Background(), "missing", nil, 0)
+	require.NoError(t, err)
+	waitUntilIdle(t, p)
+	assert.Equal(t, []string{uuidA}, runner.callIDs(), "the cap closed after the first item")
+
+	_, _, err = p.Start(context.Background(), "missing", nil, 0)
+	assert.ErrorIs(t, err, ai.ErrSpendCapReached)
+	assert.False(t, p.IsRunning())
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/indexer"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+)
+
+type fakeIndexerRepo struct {
+	mu       sync.Mutex
+	indexers map[string]models.Indexer
+}
+
+func newFakeIndexerRepo() *fakeIndexerRepo {
+	return &fakeIndexerRepo{indexers: map[string]models.Indexer{}}
+}
+
+func (f *fakeIndexerRepo) Create(ctx context.Context, ix *models.Indexer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.indexers[ix.ID] = *ix
+	return nil
+}
+func (f *fakeIndexerRepo) GetByID(ctx context.Context, id string) (*models.Indexer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ix, ok := f.indexers[id]
+	if !ok {
+		return nil, repository.ErrIndexerNotFound
+	}
+	return &ix, nil
+}
+func (f *fakeIndexerRepo) List(ctx context.Context) ([]models.Indexer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []models.Indexer
+	for _, ix := range f.indexers {
+		out = append(out, ix)
+	}
+	return out, nil
+}
+func (f *fakeIndexerRepo) ListEnabled(ctx context.Context) ([]models.Indexer, error) {
+	return f.List(ctx)
+}
+func (f *fakeIndexerRepo) Update(ctx context.Context, ix *models.Indexer) error {
+	return f.Create(ctx, ix)
+}
+func (f *fakeIndexerRepo) Delete(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.indexers[id]; !ok {
+		return repository.ErrIndexerNotFound
+	}
+	delete(f.indexers, id)
+	return nil
+}
+
+// fakeIndexerProber records the config it was asked to test.
+type fakeIndexerProber struct {
+	tested indexer.Config
+	err    error
+}
+
+func (f *fakeIndexerProber) Test(ctx context.Context, cfg indexer.Config) error {
+	f.tested = cfg
+	return f.err
+}
+
+func jackettInput() IndexerInput {
+	return IndexerInput{
+		Name:       "Jackett",
+		Type:       models.IndexerTypeTorznab,
+		URL:        "http://jackett:9117/api/v2.0/indexers/all/results/torznab",
+		Categories: []int{2000, 5000},
+		Enabled:    true,
+		APIKey:     "k1",
+	}
+}
+
+func TestIndexerService_CRUD(t *testing.T) {
+	repo := newFakeIndexerRepo()
+	secretsSvc := newFakeDVRSecrets()
+	prober := &fakeIndexerProber{}
+	service := NewIndexerService(repo, secretsSvc, prober, nil)
+	ctx := context.Background()
+
+	ix, err := service.CreateIndexer(ctx, jackettInput())
+	require.NoError(t, err)
+	assert.True(t, ix.HasAPIKey)
+
+	t.Run("api key is stored outside the indexer row", func(t *testing.T) {
+		stored, err := secretsSvc.Retrieve(ctx, indexerSecretKey(ix.ID))
+		require.NoError(t, err)
+		assert.Equal(t, "k1", stored)
+	})
+
+	t.Run("update with an empty key keeps the stored one; type is fixed", func(t *testing.T) {
+		input := jackettInput()
+		input.APIKey = ""
+		input.Type = models.IndexerTypeRSS
+		input.Enabled = false
+		updated, err := service.UpdateIndexer(ctx, ix.ID, input)
+		require.NoError(t, err)
+		assert.True(t, updated.HasAPIKey)
+		assert.Equal(t, models.IndexerTypeTorznab, updated.Type)
+		assert.False(t, updated.Enabled)
+	})
+
+	t.Run("test sends the stored key", func(t *testing.T) {
+		require.NoError(t, service.TestIndexer(ctx, ix.ID))
+		assert.Equal(t, "k1", prober.tested.APIKey)
+		assert.Equal(t, []int{2000, 5000}, prober.tested.Categories)
+	})
+
+	t.Run("invalid url is rejected", func(t *testing.T) {
+		input := jackettInput()
+		input.URL = "ftp://jackett"
+		_, err := service.CreateIndexer(ctx, input)
+		var valErr *models.ValidationError
+		require.ErrorAs(t, err, &valErr)
+		assert.Equal(t, "url", valErr.Field)
+	})
+
+	t.Run("delete removes the api key", func(t *testing.T) {
+		require.NoError(t, service.DeleteIndexer(ctx, ix.ID))
+		exists, _ := secretsSvc.Exists(ctx, indexerSecretKey(ix.ID))
+		assert.False(t, exists)
+		_, err := service.GetIndexer(ctx, ix.ID)
+		assert.ErrorIs(t, err, repository.ErrIndexerNotFound)
+	})
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultWatchDebounce is how long a path must stay quiet before the watcher
+	// processes it. Files are only reported once their writer closes them, so
+	// this mostly coalesces the burst a release unpack or a season move makes.
+	defaultWatchDebounce = 10 * time.Second
+
+	// watchRootRefreshInterval is how often the watcher re-reads the library
+	// paths, so a folder added in Settings is watched without a restart.
+	watchRootRefreshInterval = 5 * time.Minute
+)
+
+// ErrWatchUnsupported is returned on platforms without a filesystem event API.
+var ErrWatchUnsupported = errors.New("LIBRARY_WATCH_UNSUPPORTED: filesystem events are not available on this platform")
+
+// FileEventKind classifies a raw filesystem event.
+type FileEventKind int
+
+const (
+	// FileEventChanged covers a file closed after writing, or anything moved in.
+	FileEventChanged FileEventKind = iota
+	// FileEventRemoved covers a delete or a move out.
+	FileEventRemoved
+	// FileEventOverflow means the kernel dropped events; only a full scan can
+	// recover what was missed.
+	FileEventOverflow
+)
+
+// FileEvent is one raw event from a fileEventSource.
+type FileEvent struct {
+	Path string
+	Kind FileEventKind
+}
+
+// fileEventSource is the platform port under LibraryWatcher: inotify on Linux,
+// unsupported elsewhere, a channel-fed fake in tests. Watch is recursive —
+// the source follows sub-folders created after the call on its own.
+type fileEventSource interface {
+	Watch(root string) error
+	Unwatch(root string) error
+	Events() <-chan FileEvent
+	Errors() <-chan error
+	Close() error
+}
+
+// changeProcessor is the narrow port over *ScannerService.
+type changeProcessor interface {
+	ScanRoots(ctx context.Context) []string
+	ProcessChangedPaths(ctx context.Context, changes []PathChange) (*ScanResult, error)
+	StartScan(ctx context.Context) (*ScanResult, error)
+}
+
+// LibraryWatcher turns filesystem events under every library path into
+// incremental scans, so a new episode shows up seconds after it lands instead
+// of at the next scheduled full scan. The scan scheduler stays in place as the
+// safety net for anything the watcher cannot see (NFS/SMB mounts that emit no
+// events, or a kernel queue overflow).
+type LibraryWatcher struct {
+	scanner   changeProcessor
+	newSource func() (fileEventSource, error)
+	debounce  time.Duration
+	logger    *slog.Logger
+
+	mu      sync.Mutex
+	pending map[string]PathChangeKind
+	roots   map[string]bool
+	done    chan struct{}
+	running bool
+}
+
+// NewLibraryWatcher creates a watcher over the scanner's roots. debounce <= 0
+// selects the default quiet window.
+func NewLibraryWatcher(scanner *ScannerService, debounce time.Duration, logger *slog.Logger) *LibraryWatcher {
+	return newLibraryWatcher(scanner, newFileEventSource, debounce, logger)
+}
+
+func newLibraryWatcher(scanner changeProcessor, newSource func() (fileEventSource, error), debounce time.Duration, logger *slog.Logger) *LibraryWatcher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if debounce <= 0 {
+		debounce = defaultWatchDebounce
+	}
+	return &LibraryWatcher{
+		scanner:   scanner,
+		newSource: newSource,
+		debounce:  debounce,
+		logger:    logger.With("service", "library_watcher"),
+		pending:   make(map[string]PathChangeKind),
+		roots:     make(map[string]bool),
+	}
+}
+
+// Start watches every library path and blocks until ctx is cancelled or Stop
+// is called. It returns an error only when the event source cannot be opened
+// at all; a root that cannot be watched is logged and retried on the next
+// root refresh.
+func (w *LibraryWatcher) Start(ctx context.Context) error {
+	source, err := w.newSource()
+	if err != nil {
+		return fmt.Errorf("library watcher: %w", err)
+	}
+	defer source.Close()
+
+	w.mu.Lock()
+	w.done = make(chan struct{})
+	w.running = true
+	done := w.done
+	w.mu.Unlock()
+
+	w.refreshRoots(ctx, source)
+
+	refresh := time.NewTicker(watchRootRefreshInterval)
+	defer refresh.Stop()
+
+	flush := time.NewTimer(w.debounce)
+	flush.Stop()
+
+	errs := source.Errors()
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Library watcher stopped (context cancelled)")
+			return nil
+		case <-done:
+			w.logger.Info("Library watcher stopped (stop signal)")
+			return nil
+		case <-refresh.C:
+			w.refreshRoots(ctx, source)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			w.logger.Warn("library watcher event error", "error", err)
+		case ev, ok := <-source.Events():
+			if !ok {
+				// The source gave up (an inotify read error closes it). Hand over
+				// what was already seen and leave the rest to the scan scheduler
+				// rather than spinning on a closed channel.
+				w.logger.Warn("filesystem event source closed — library watching stopped, relying on scheduled scans")
+				w.flush(ctx)
+				w.mu.Lock()
+				w.running = false
+				w.mu.Unlock()
+				return errors.New("library watcher: event source closed")
+			}
+			if ev.Kind == FileEventOverflow {
+				w.logger.Warn("filesystem event queue overflowed — falling back to a full scan")
+				go w.fullScan(ctx)
+				continue
+			}
+			w.note(ev)
+			// Every event restarts the quiet window, so one burst becomes one
+			// incremental scan.
+			flush.Reset(w.debounce)
+		case <-flush.C:
+			if w.flush(ctx) {
+				flush.Reset(w.debounce)
+			}
+		}
+	}
+}
+
+// Stop ends a running Start loop.
+func (w *LibraryWatcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.running {
+		return
+	}
+	w.running = false
+	close(w.done)
+}
+
+// note records an event, the latest kind per path winning: a file deleted and
+// re-created inside one window is a change, one created and deleted is a removal.
+func (w *LibraryWatcher) note(ev FileEvent) {
+	kind := PathChanged
+	if ev.Kind == FileEventRemoved {
+		kind = PathRemoved
+	}
+	w.mu.Lock()
+	w.pending[ev.Path] = kind
+	w.mu.Unlock()
+}
+
+// flush hands the pending changes to the scanner. It reports true when the
+// batch must be retried — a full scan held the scanner — in which case the
+// changes are put back for the next window.
+func (w *LibraryWatcher) flush(ctx context.Context) bool {
+	w.mu.Lock()
+	if len(w.pending) == 0 {
+		w.mu.Unlock()
+		return false
+	}
+	changes := make([]PathChange, 0, len(w.pending))
+	for path, kind := range w.pending {
+		changes = append(changes, PathChange{Path: path, Kind: kind})
+	}
+	w.pending = make(map[string]PathChangeKind)
+	w.mu.Unlock()
+
+	// Deterministic order: a parent folder is walked before its children are
+	// looked at individually, and logs read top-down.
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+
+	_, err := w.scanner.ProcessChangedPaths(ctx, changes)
+	if errors.Is(err, ErrScanAlreadyRunning) {
+		w.mu.Lock()
+		for _, c := range changes {
+			if _, newer := w.pending[c.Path]; !newer {
+				w.pending[c.Path] = c.Kind
+			}
+		}
+		w.mu.Unlock()
+		w.logger.Debug("scan in progress — deferring watcher batch", "changes", len(changes))
+		return true
+	}
+	if err != nil {
+		w.logger.Error("incremental scan failed", "changes", len(changes), "error", err)
+	}
+	return false
+}
+
+// refreshRoots brings the watched set in line with the current library paths.
+func (w *LibraryWatcher) refreshRoots(ctx context.Context, source fileEventSource) {
+	want := make(map[string]bool)
+	for _, root := range w.scanner.ScanRoots(ctx) {
+		want[root] = true
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for root := range w.roots {
+		if !want[root] {
+			if err := source.Unwatch(root); err != nil {
+				w.logger.Warn("failed to stop watching library path", "path", root, "error", err)
+			}
+			delete(w.roots, root)
+		}
+	}
+	for root := range want {
+		if w.roots[root] {
+			continue
+		}
+		if err := source.Watch(root); err != nil {
+			w.logger.Warn("cannot watch library path — it stays on scheduled scans", "path", root, "error", err)
+			continue
+		}
+		w.roots[root] = true
+		w.logger.Info("watching library path", "path", root)
+	}
+}
+
+// fullScan recovers from an event overflow.
+func (w *LibraryWatcher) fullScan(ctx context.Context) {
+	if _, err := w.scanner.StartScan(ctx); err != nil && !errors.Is(err, ErrScanAlreadyRunning) {
+		w.logger.Error("overflow recovery scan failed", "error", err)
+	}
+}
@@ -0,0 +1,205 @@
+//go:build linux
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyMask selects the events that matter for a media library. Files are
+// reported on IN_CLOSE_WRITE rather than IN_CREATE: a 20 GB copy is "created"
+// minutes before it is complete, and ffprobe on half a file poisons the row.
+const inotifyMask = unix.IN_CLOSE_WRITE | unix.IN_MOVED_TO | unix.IN_MOVED_FROM |
+	unix.IN_CREATE | unix.IN_DELETE | unix.IN_DELETE_SELF
+
+// inotifySource is the Linux fileEventSource. inotify watches are per
+// directory, so Watch adds one per sub-folder and new sub-folders are added as
+// they appear.
+type inotifySource struct {
+	file   *os.File
+	events chan FileEvent
+	errors chan error
+	closed chan struct{}
+
+	mu    sync.Mutex
+	paths map[int]string // watch descriptor → directory
+	wds   map[string]int // directory → watch descriptor
+}
+
+func newFileEventSource() (fileEventSource, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify init: %w", err)
+	}
+	s := &inotifySource{
+		// A non-blocking fd wrapped by os.NewFile is registered with the runtime
+		// poller, so Close unblocks the reader goroutine.
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan FileEvent, 256),
+		errors: make(chan error, 8),
+		closed: make(chan struct{}),
+		paths:  make(map[int]string),
+		wds:    make(map[string]int),
+	}
+	go s.readLoop()
+	return s, nil
+}
+
+func (s *inotifySource) Events() <-chan FileEvent { return s.events }
+func (s *inotifySource) Errors() <-chan error     { return s.errors }
+
+// Watch adds a watch on root and every directory beneath it.
+func (s *inotifySource) Watch(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil // an unreadable sub-folder must not cost the whole root
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		return s.addWatch(path)
+	})
+}
+
+// Unwatch removes every watch at or under root.
+func (s *inotifySource) Unwatch(root string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, err := s.file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	root = filepath.Clean(root)
+	prefix := root + string(filepath.Separator)
+	var firstErr error
+	for dir, wd := range s.wds {
+		if dir != root && !strings.HasPrefix(dir, prefix) {
+			continue
+		}
+		// File.Fd would flip the descriptor back to blocking mode and detach it
+		// from the poller, so the raw descriptor is borrowed instead.
+		_ = raw.Control(func(fd uintptr) {
+			if _, err := unix.InotifyRmWatch(int(fd), uint32(wd)); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		})
+		delete(s.wds, dir)
+		delete(s.paths, wd)
+	}
+	return firstErr
+}
+
+func (s *inotifySource) Close() error {
+	close(s.closed)
+	return s.file.Close()
+}
+
+func (s *inotifySource) addWatch(dir string) error {
+	raw, err := s.file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var wd int
+	var addErr error
+	if err := raw.Control(func(fd uintptr) {
+		wd, addErr = unix.InotifyAddWatch(int(fd), dir, inotifyMask)
+	}); err != nil {
+		return err
+	}
+	if addErr != nil {
+		return fmt.Errorf("inotify watch %s: %w", dir, addErr)
+	}
+
+	s.mu.Lock()
+	s.paths[wd] = filepath.Clean(dir)
+	s.wds[filepath.Clean(dir)] = wd
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *inotifySource) readLoop() {
+	defer close(s.events)
+
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := s.file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				s.sendError(fmt.Errorf("inotify read: %w", err))
+			}
+			return
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			raw := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+unix.SizeofInotifyEvent : offset+unix.SizeofInotifyEvent+int(raw.Len)]
+			offset += unix.SizeofInotifyEvent + int(raw.Len)
+
+			s.dispatch(int(raw.Wd), raw.Mask, strings.TrimRight(string(nameBytes), "\x00"))
+		}
+	}
+}
+
+func (s *inotifySource) dispatch(wd int, mask uint32, name string) {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		s.send(FileEvent{Kind: FileEventOverflow})
+		return
+	}
+
+	s.mu.Lock()
+	dir, ok := s.paths[wd]
+	if mask&unix.IN_IGNORED != 0 && ok {
+		delete(s.paths, wd)
+		delete(s.wds, dir)
+	}
+	s.mu.Unlock()
+	if !ok || name == "" {
+		return
+	}
+	path := filepath.Join(dir, name)
+	isDir := mask&unix.IN_ISDIR != 0
+
+	switch {
+	case mask&(unix.IN_DELETE|unix.IN_MOVED_FROM) != 0:
+		s.send(FileEvent{Path: path, Kind: FileEventRemoved})
+	case isDir && mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+		// A new folder needs its own watches before anything lands in it; a
+		// folder moved in arrives already populated, so it is reported too and
+		// the scanner walks it.
+		if err := s.Watch(path); err != nil {
+			s.sendError(err)
+		}
+		s.send(FileEvent{Path: path, Kind: FileEventChanged})
+	case !isDir && mask&(unix.IN_CLOSE_WRITE|unix.IN_MOVED_TO) != 0:
+		s.send(FileEvent{Path: path, Kind: FileEventChanged})
+	}
+}
+
+// send delivers ev unless the source is closing, so a reader goroutine never
+// outlives the watcher blocked on a channel nobody drains.
+func (s *inotifySource) send(ev FileEvent) {
+	select {
+	case s.events <- ev:
+	case <-s.closed:
+	}
+}
+
+func (s *inotifySource) sendError(err error) {
+	select {
+	case s.errors <- err:
+	default:
+	}
+}
@@ -0,0 +1,44 @@
+//go:build linux
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInotifySource_ReportsClosedFilesAndNewFolders(t *testing.T) {
+	root := t.TempDir()
+	source, err := newFileEventSource()
+	require.NoError(t, err)
+	t.Cleanup(func() { source.Close() })
+	require.NoError(t, source.Watch(root))
+
+	next := func() FileEvent {
+		t.Helper()
+		select {
+		case ev := <-source.Events():
+			return ev
+		case <-time.After(2 * time.Second):
+			t.Fatal("no inotify event")
+			return FileEvent{}
+		}
+	}
+
+	season := filepath.Join(root, "Season 01")
+	require.NoError(t, os.Mkdir(season, 0755))
+	assert.Equal(t, FileEvent{Path: season, Kind: FileEventChanged}, next())
+
+	// The new folder was picked up recursively, so a file written into it is seen.
+	episode := filepath.Join(season, "S01E01.mkv")
+	require.NoError(t, os.WriteFile(episode, []byte("video"), 0644))
+	assert.Equal(t, FileEvent{Path: episode, Kind: FileEventChanged}, next())
+
+	require.NoError(t, os.Remove(episode))
+	assert.Equal(t, FileEvent{Path: episode, Kind: FileEventRemoved}, next())
+}
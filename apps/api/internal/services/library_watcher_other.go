@@ -0,0 +1,9 @@
+//go:build !linux
+
+package services
+
+// newFileEventSource reports that this platform has no event source; the
+// scan scheduler keeps the library current on its own.
+func newFileEventSource() (fileEventSource, error) {
+	return nil, ErrWatchUnsupported
+}
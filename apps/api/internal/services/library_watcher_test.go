@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEventSource struct {
+	events chan FileEvent
+	errors chan error
+
+	mu      sync.Mutex
+	watched map[string]bool
+}
+
+func newFakeEventSource() *fakeEventSource {
+	return &fakeEventSource{
+		events:  make(chan FileEvent, 16),
+		errors:  make(chan error, 1),
+		watched: make(map[string]bool),
+	}
+}
+
+func (f *fakeEventSource) Watch(root string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.watched[root] = true
+	return nil
+}
+
+func (f *fakeEventSource) Unwatch(root string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.watched, root)
+	return nil
+}
+
+func (f *fakeEventSource) Events() <-chan FileEvent { return f.events }
+func (f *fakeEventSource) Errors() <-chan error     { return f.errors }
+func (f *fakeEventSource) Close() error             { return nil }
+
+type fakeChangeProcessor struct {
+	mu        sync.Mutex
+	roots     []string
+	batches   [][]PathChange
+	busyFirst int
+	fullScans int
+}
+
+func (f *fakeChangeProcessor) ScanRoots(context.Context) []string { return f.roots }
+
+func (f *fakeChangeProcessor) ProcessChangedPaths(_ context.Context, changes []PathChange) (*ScanResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.busyFirst > 0 {
+		f.busyFirst--
+		return nil, ErrScanAlreadyRunning
+	}
+	f.batches = append(f.batches, changes)
+	return &ScanResult{}, nil
+}
+
+func (f *fakeChangeProcessor) StartScan(context.Context) (*ScanResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fullScans++
+	return &ScanResult{}, nil
+}
+
+func (f *fakeChangeProcessor) snapshot() ([][]PathChange, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]PathChange(nil), f.batches...), f.fullScans
+}
+
+func startTestWatcher(t *testing.T, proc *fakeChangeProcessor) (*LibraryWatcher, *fakeEventSource) {
+	t.Helper()
+	source := newFakeEventSource()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	w := newLibraryWatcher(proc, func() (fileEventSource, error) { return source, nil }, 20*time.Millisecond, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = w.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return w, source
+}
+
+func TestLibraryWatcher_DebouncesBurstIntoOneBatch(t *testing.T) {
+	proc := &fakeChangeProcessor{roots: []string{"/media/tv"}}
+	_, source := startTestWatcher(t, proc)
+
+	source.events <- FileEvent{Path: "/media/tv/Show/S01E01.mkv", Kind: FileEventChanged}
+	source.events <- FileEvent{Path: "/media/tv/Show/S01E02.mkv", Kind: FileEventChanged}
+	source.events <- FileEvent{Path: "/media/tv/Show/S01E01.mkv", Kind: FileEventRemoved}
+
+	require.Eventually(t, func() bool {
+		batches, _ := proc.snapshot()
+		return len(batches) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	batches, _ := proc.snapshot()
+	assert.Equal(t, []PathChange{
+		{Path: "/media/tv/Show/S01E01.mkv", Kind: PathRemoved},
+		{Path: "/media/tv/Show/S01E02.mkv", Kind: PathChanged},
+	}, batches[0], "latest kind per path wins, sorted by path")
+
+	source.mu.Lock()
+	assert.True(t, source.watched["/media/tv"])
+	source.mu.Unlock()
+}
+
+func TestLibraryWatcher_RetriesWhileFullScanRuns(t *testing.T) {
+	proc := &fakeChangeProcessor{roots: []string{"/media"}, busyFirst: 2}
+	_, source := startTestWatcher(t, proc)
+
+	source.events <- FileEvent{Path: "/media/a.mkv", Kind: FileEventChanged}
+
+	require.Eventually(t, func() bool {
+		batches, _ := proc.snapshot()
+		return len(batches) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	batches, _ := proc.snapshot()
+	assert.Equal(t, []PathChange{{Path: "/media/a.mkv", Kind: PathChanged}}, batches[0])
+}
+
+func TestLibraryWatcher_OverflowTriggersFullScan(t *testing.T) {
+	proc := &fakeChangeProcessor{roots: []string{"/media"}}
+	_, source := startTestWatcher(t, proc)
+
+	source.events <- FileEvent{Kind: FileEventOverflow}
+
+	require.Eventually(t, func() bool {
+		_, full := proc.snapshot()
+		return full == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestLibraryWatcher_StopEndsStart(t *testing.T) {
+	proc := &fakeChangeProcessor{}
+	w := newLibraryWatcher(proc, func() (fileEventSource, error) { return newFakeEventSource(), nil }, time.Second, nil)
+
+	done := make(chan error, 1)
+	go func() { done <- w.Start(context.Background()) }()
+
+	require.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.running
+	}, time.Second, 5*time.Millisecond)
+	w.Stop()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after Stop")
+	}
+}
+
+// TestLibraryWatcher_ClosedSourceEndsStart — an inotify read error closes the
+// event channel; Start must hand over what it saw and return, not spin.
+func TestLibraryWatcher_ClosedSourceEndsStart(t *testing.T) {
+	proc := &fakeChangeProcessor{roots: []string{"/media"}}
+	source := newFakeEventSource()
+	w := newLibraryWatcher(proc, func() (fileEventSource, error) { return source, nil }, time.Hour, nil)
+
+	done := make(chan error, 1)
+	go func() { done <- w.Start(context.Background()) }()
+
+	source.events <- FileEvent{Path: "/media/a.mkv", Kind: FileEventChanged}
+	close(source.events)
+
+	select {
+	case err := <-done:
+		assert.Error(t, err, "main logs the fallback to scheduled scans")
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after its event source closed")
+	}
+
+	batches, _ := proc.snapshot()
+	assert.Equal(t, [][]PathChange{{{Path: "/media/a.mkv", Kind: PathChanged}}}, batches, "pending changes are not dropped")
+	w.mu.Lock()
+	assert.False(t, w.running)
+	w.mu.Unlock()
+	w.Stop() // a no-op once the loop has ended
+}
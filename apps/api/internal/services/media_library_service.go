@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/vido/api/internal/models"
 	"github.com/vido/api/internal/repository"
@@ -34,6 +36,13 @@ type CreateLibraryRequest struct {
 	// the modal rendered a checkbox whose value was silently discarded on
 	// create — the user ticked it, pressed 建立, and nothing said otherwise.
 	AutoSubtitle bool `json:"auto_subtitle"`
+	// SubtitleOutputMode picks translated-only or bilingual subtitles for
+	// this library; empty means translated-only.
+	SubtitleOutputMode string `json:"subtitle_output_mode"`
+	// OrganizeTemplate is the organizer layout; empty selects the built-in.
+	OrganizeTemplate string `json:"organize_template"`
+	// QualityProfileID assigns a quality profile; empty means none.
+	QualityProfileID string `json:"quality_profile_id"`
 }
 
 // UpdateLibraryRequest is the input for updating a library.
@@ -46,11 +55,23 @@ type UpdateLibraryRequest struct {
 	// as-is", so a form that does not know about the setting cannot silently
 	// switch it off — or, worse, on.
 	AutoSubtitle *bool `json:"auto_subtitle,omitempty"`
+	// SubtitleOutputMode replaces the library's subtitle output mode; ""
+	// resets it to translated-only.
+	SubtitleOutputMode *string `json:"subtitle_output_mode,omitempty"`
+	// OrganizeTemplate replaces the organizer layout; "" resets it to the
+	// built-in one.
+	OrganizeTemplate *string `json:"organize_template,omitempty"`
+	// QualityProfileID replaces the quality profile assignment; "" removes
+	// it, which stops the upgrade evaluator judging this library.
+	QualityProfileID *string `json:"quality_profile_id,omitempty"`
 }
 
 // MediaLibraryService implements MediaLibraryServiceInterface.
 type MediaLibraryService struct {
 	repo repository.MediaLibraryRepositoryInterface
+	// profiles, when wired, rejects an assignment to a profile that does not
+	// exist. Nil-safe: unwired = the id is stored as given.
+	profiles QualityProfileReader
 }
 
 // NewMediaLibraryService creates a new MediaLibraryService.
@@ -58,6 +79,11 @@ func NewMediaLibraryService(repo repository.MediaLibraryRepositoryInterface) *Me
 	return &MediaLibraryService{repo: repo}
 }
 
+// SetQualityProfiles wires the quality profile existence check (main.go).
+func (s *MediaLibraryService) SetQualityProfiles(profiles QualityProfileReader) {
+	s.profiles = profiles
+}
+
 func (s *MediaLibraryService) GetAllLibraries(ctx context.Context) ([]models.MediaLibraryWithPaths, error) {
 	libraries, err := s.repo.GetAllWithPathsAndCounts(ctx)
 	if err != nil {
@@ -85,13 +111,19 @@ func (s *MediaLibraryService) GetLibrary(ctx context.Context, id string) (*model
 
 func (s *MediaLibraryService) CreateLibrary(ctx context.Context, req CreateLibraryRequest) (*models.MediaLibrary, error) {
 	lib := &models.MediaLibrary{
-		Name:         req.Name,
-		ContentType:  models.MediaLibraryContentType(req.ContentType),
-		AutoSubtitle: req.AutoSubtitle,
+		Name:               req.Name,
+		ContentType:        models.MediaLibraryContentType(req.ContentType),
+		AutoSubtitle:       req.AutoSubtitle,
+		SubtitleOutputMode: models.SubtitleOutputMode(strings.TrimSpace(req.SubtitleOutputMode)),
+		OrganizeTemplate:   strings.TrimSpace(req.OrganizeTemplate),
+		QualityProfileID:   profileIDColumn(req.QualityProfileID),
 	}
 
-	if err := lib.Validate(); err != nil {
-		return nil, fmt.Errorf("validation: %w", err)
+	if err := validateLibrary(lib); err != nil {
+		return nil, err
+	}
+	if err := s.checkQualityProfile(ctx, lib); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.Create(ctx, lib); err != nil {
@@ -128,9 +160,21 @@ func (s *MediaLibraryService) UpdateLibrary(ctx context.Context, id string, req
 	if req.AutoSubtitle != nil {
 		lib.AutoSubtitle = *req.AutoSubtitle
 	}
+	if req.SubtitleOutputMode != nil {
+		lib.SubtitleOutputMode = models.SubtitleOutputMode(strings.TrimSpace(*req.SubtitleOutputMode))
+	}
+	if req.OrganizeTemplate != nil {
+		lib.OrganizeTemplate = strings.TrimSpace(*req.OrganizeTemplate)
+	}
+	if req.QualityProfileID != nil {
+		lib.QualityProfileID = profileIDColumn(*req.QualityProfileID)
+	}
 
-	if err := lib.Validate(); err != nil {
-		return nil, fmt.Errorf("validation: %w", err)
+	if err := validateLibrary(lib); err != nil {
+		return nil, err
+	}
+	if err := s.checkQualityProfile(ctx, lib); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.Update(ctx, lib); err != nil {
@@ -141,6 +185,43 @@ func (s *MediaLibraryService) UpdateLibrary(ctx context.Context, id string, req
 	return lib, nil
 }
 
+// validateLibrary runs the model checks plus the organizer template, which is
+// re-checked on every save because a content-type change can invalidate it
+// ({season} in what became a movie library).
+func validateLibrary(lib *models.MediaLibrary) error {
+	if err := lib.Validate(); err != nil {
+		return fmt.Errorf("validation: %w", err)
+	}
+	if err := ValidateOrganizeTemplate(lib.OrganizeTemplate, lib.ContentType); err != nil {
+		return fmt.Errorf("validation: %w", &models.ValidationError{Field: "organize_template", Message: err.Error()})
+	}
+	return nil
+}
+
+// profileIDColumn maps the request's profile id to its column; blank is NULL.
+func profileIDColumn(id string) models.NullString {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return models.NullString{}
+	}
+	return models.NewNullString(id)
+}
+
+// checkQualityProfile rejects an assignment to a profile that does not exist.
+func (s *MediaLibraryService) checkQualityProfile(ctx context.Context, lib *models.MediaLibrary) error {
+	if s.profiles == nil || !lib.QualityProfileID.Valid {
+		return nil
+	}
+	if _, err := s.profiles.GetByID(ctx, lib.QualityProfileID.String); err != nil {
+		if errors.Is(err, repository.ErrQualityProfileNotFound) {
+			return fmt.Errorf("validation: %w", &models.ValidationError{
+				Field: "quality_profile_id", Message: "quality profile not found"})
+		}
+		return fmt.Errorf("check quality profile: %w", err)
+	}
+	return nil
+}
+
 func (s *MediaLibraryService) DeleteLibrary(ctx context.Context, id string, removeMedia bool) error {
 	if removeMedia {
 		slog.Info("Deleting library with media removal", "id", id)
@@ -155,3 +155,67 @@ func TestCreateLibrary_DefaultsOffWhenUnspecified(t *testing.T) {
 
 	assert.False(t, got.AutoSubtitle, "a request that never mentions the opt-in must produce an OFF library")
 }
+
+// ─── Organizer template is validated on every save ────────────────────────
+
+func TestUpdateLibrary_RejectsInvalidOrganizeTemplate(t *testing.T) {
+	svc, repo := newLibraryServiceWith(false)
+	tpl := "{title} ({year})/{titel}"
+
+	_, err := svc.UpdateLibrary(context.Background(), "lib-1", UpdateLibraryRequest{OrganizeTemplate: &tpl})
+
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "organize_template", validationErr.Field)
+	assert.Nil(t, repo.updated, "an invalid template must not be persisted")
+}
+
+func TestUpdateLibrary_ContentTypeChangeRevalidatesTemplate(t *testing.T) {
+	svc, repo := newLibraryServiceWith(false)
+	repo.lib.ContentType = models.ContentTypeSeries
+	repo.lib.OrganizeTemplate = DefaultSeriesOrganizeTemplate
+	movie := "movie"
+
+	_, err := svc.UpdateLibrary(context.Background(), "lib-1", UpdateLibraryRequest{ContentType: &movie})
+
+	assert.Error(t, err, "{season} in a movie library can never render — refuse the save rather than the next organize run")
+}
+
+func TestCreateLibrary_CarriesOrganizeTemplate(t *testing.T) {
+	repo := &stubLibraryRepo{}
+	svc := NewMediaLibraryService(repo)
+
+	got, err := svc.CreateLibrary(context.Background(), CreateLibraryRequest{
+		Name:             "我的電影",
+		ContentType:      "movie",
+		OrganizeTemplate: "  {title} ({year})/{title}  ",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "{title} ({year})/{title}", got.OrganizeTemplate)
+}
+
+// ─── Quality profile assignment ───────────────────────────────────────────
+
+func TestUpdateLibrary_QualityProfileAssignment(t *testing.T) {
+	svc, repo := newLibraryServiceWith(false)
+	svc.SetQualityProfiles(qualityProfileMap{"hd": {ID: "hd", Name: "HD", Cutoff: "1080p"}})
+	ctx := context.Background()
+
+	hd := "hd"
+	got, err := svc.UpdateLibrary(ctx, "lib-1", UpdateLibraryRequest{QualityProfileID: &hd})
+	require.NoError(t, err)
+	assert.Equal(t, "hd", got.QualityProfileID.String)
+
+	missing := "nope"
+	_, err = svc.UpdateLibrary(ctx, "lib-1", UpdateLibraryRequest{QualityProfileID: &missing})
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "quality_profile_id", validationErr.Field)
+
+	none := ""
+	got, err = svc.UpdateLibrary(ctx, "lib-1", UpdateLibraryRequest{QualityProfileID: &none})
+	require.NoError(t, err)
+	assert.False(t, got.QualityProfileID.Valid, "an empty id removes the assignment")
+	assert.False(t, repo.updated.QualityProfileID.Valid)
+}
@@ -0,0 +1,543 @@
+// Package services — MediaServerService.
+//
+// Jellyfin/Emby/Plex integration over the plugin manager's media-server
+// registry. Vido writes the sidecars those servers read (zh-TW subtitles,
+// localized NFOs) but the servers only notice on their own scheduled scan;
+// this service tells them as soon as a file lands, and can pull Jellyfin/Emby
+// played state into Vido's watch history. Config lives in the settings table + secrets service,
+// the DVR plugin precedent.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/plugins"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/secrets"
+)
+
+const (
+	// defaultRefreshDebounce coalesces the burst a batch subtitle run or a
+	// whole-show NFO localization makes into one refresh call per server.
+	defaultRefreshDebounce = 15 * time.Second
+	// maxRefreshDelay caps how long a steady trickle of changes can keep
+	// postponing the refresh.
+	maxRefreshDelay = 2 * time.Minute
+	// refreshCallTimeout bounds one server's refresh call.
+	refreshCallTimeout = 30 * time.Second
+)
+
+// MediaChangeNotifier is told about sidecar files Vido writes next to a media
+// file. *MediaServerService implements it; producers hold it as an optional,
+// nil-safe dependency.
+type MediaChangeNotifier interface {
+	NotifyChanged(path string)
+}
+
+// MediaServerConfigInput is the PUT /settings/{jellyfin,emby,plex} body. An empty
+// APIKey keeps the stored key, like DVRConfigInput.
+type MediaServerConfigInput struct {
+	URL              string `json:"url"`
+	APIKey           string `json:"api_key"`
+	Enabled          bool   `json:"enabled"`
+	LocalPathPrefix  string `json:"local_path_prefix"`
+	ServerPathPrefix string `json:"server_path_prefix"`
+	WatchImportUser  string `json:"watch_import_user"`
+}
+
+// MediaServerConfigStatus is the GET /settings/{jellyfin,emby,plex} response.
+type MediaServerConfigStatus struct {
+	URL              string               `json:"url"`
+	Enabled          bool                 `json:"enabled"`
+	LocalPathPrefix  string               `json:"local_path_prefix"`
+	ServerPathPrefix string               `json:"server_path_prefix"`
+	WatchImportUser  string               `json:"watch_import_user"`
+	HasAPIKey        bool                 `json:"has_api_key"`
+	Health           plugins.PluginHealth `json:"health"`
+}
+
+// WatchImportResult reports one played-state import.
+type WatchImportResult struct {
+	Users     int `json:"users"`     // Vido histories imported into
+	Imported  int `json:"imported"`  // items whose state changed
+	Unchanged int `json:"unchanged"` // items Vido already had
+	Unmatched int `json:"unmatched"` // server items with no file Vido knows
+}
+
+// MediaServerServiceInterface defines the contract for media-server settings
+// and actions.
+type MediaServerServiceInterface interface {
+	GetConfig(ctx context.Context, plugin string) (*MediaServerConfigStatus, error)
+	SaveConfig(ctx context.Context, plugin string, input MediaServerConfigInput) error
+	TestConnection(ctx context.Context, plugin string, input *MediaServerConfigInput) error
+	ListServerUsers(ctx context.Context, plugin string) ([]plugins.MediaServerUser, error)
+	RefreshLibrary(ctx context.Context, plugin string) error
+	ImportWatchState(ctx context.Context, plugin string) (*WatchImportResult, error)
+}
+
+// mediaServerRegistry is the narrow *plugins.Manager surface this service needs.
+type mediaServerRegistry interface {
+	RegisteredMediaServers() []string
+	GetMediaServer(ctx context.Context, name string) (plugins.MediaServerPlugin, error)
+	LoadConfig(ctx context.Context, name string) (plugins.PluginConfig, bool, error)
+	TestConfig(ctx context.Context, name string, config plugins.PluginConfig) error
+	CheckHealth(ctx context.Context, name string) plugins.PluginHealth
+	Health(name string) plugins.PluginHealth
+}
+
+type mediaServerUserLister interface {
+	List(ctx context.Context) ([]models.User, error)
+}
+
+type mediaServerMovieFinder interface {
+	FindByFilePath(ctx context.Context, filePath string) (*models.Movie, error)
+	FindByTMDbID(ctx context.Context, tmdbID int64) (*models.Movie, error)
+}
+
+type mediaServerEpisodeFinder interface {
+	FindByFilePath(ctx context.Context, filePath string) (*models.Episode, error)
+}
+
+type watchStateMerger interface {
+	MergeExternalState(ctx context.Context, userID, mediaType, mediaID string, ext ExternalWatchState) (bool, error)
+}
+
+// MediaServerService implements MediaServerServiceInterface and
+// MediaChangeNotifier.
+type MediaServerService struct {
+	registry     mediaServerRegistry
+	settingsRepo repository.SettingsRepositoryInterface
+	secrets      secrets.SecretsServiceInterface
+	users        mediaServerUserLister
+	movies       mediaServerMovieFinder
+	episodes     mediaServerEpisodeFinder
+	watch        watchStateMerger
+	logger       *slog.Logger
+	debounce     time.Duration
+
+	mu           sync.Mutex
+	pending      map[string]struct{}
+	pendingSince time.Time
+	timer        *time.Timer
+	stopped      bool
+}
+
+// Compile-time interface verification.
+var (
+	_ MediaServerServiceInterface = (*MediaServerService)(nil)
+	_ MediaChangeNotifier         = (*MediaServerService)(nil)
+)
+
+// NewMediaServerService creates a new MediaServerService.
+func NewMediaServerService(
+	registry mediaServerRegistry,
+	settingsRepo repository.SettingsRepositoryInterface,
+	secretsService secrets.SecretsServiceInterface,
+	users mediaServerUserLister,
+	movies mediaServerMovieFinder,
+	episodes mediaServerEpisodeFinder,
+	watch watchStateMerger,
+	logger *slog.Logger,
+) *MediaServerService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &MediaServerService{
+		registry:     registry,
+		settingsRepo: settingsRepo,
+		secrets:      secretsService,
+		users:        users,
+		movies:       movies,
+		episodes:     episodes,
+		watch:        watch,
+		logger:       logger.With("service", "media_server"),
+		debounce:     defaultRefreshDebounce,
+		pending:      make(map[string]struct{}),
+	}
+}
+
+// GetConfig returns the stored config sans key + the live health block.
+func (s *MediaServerService) GetConfig(ctx context.Context, plugin string) (*MediaServerConfigStatus, error) {
+	url, _ := s.settingsRepo.GetString(ctx, plugins.SettingKeyURL(plugin))
+	enabled, _ := s.settingsRepo.GetBool(ctx, plugins.SettingKeyEnabled(plugin))
+	localPrefix, _ := s.settingsRepo.GetString(ctx, plugins.SettingKeyLocalPathPrefix(plugin))
+	serverPrefix, _ := s.settingsRepo.GetString(ctx, plugins.SettingKeyServerPathPrefix(plugin))
+	importUser, _ := s.settingsRepo.GetString(ctx, plugins.SettingKeyWatchImportUser(plugin))
+	hasKey, _ := s.secrets.Exists(ctx, plugins.SettingKeyAPIKey(plugin))
+
+	return &MediaServerConfigStatus{
+		URL:              url,
+		Enabled:          enabled,
+		LocalPathPrefix:  localPrefix,
+		ServerPathPrefix: serverPrefix,
+		WatchImportUser:  importUser,
+		HasAPIKey:        hasKey,
+		Health:           s.registry.Health(plugin),
+	}, nil
+}
+
+// SaveConfig persists a media-server config behind the same test-before-save
+// guard as DVRSettingsService.SaveConfig.
+func (s *MediaServerService) SaveConfig(ctx context.Context, plugin string, input MediaServerConfigInput) error {
+	if input.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	if (input.LocalPathPrefix == "") != (input.ServerPathPrefix == "") {
+		return &models.ValidationError{Field: "path_prefix", Message: "local and server path prefixes must be set together"}
+	}
+
+	effectiveKey, err := effectivePluginAPIKey(ctx, s.secrets, plugin, input.APIKey)
+	if err != nil {
+		return err
+	}
+
+	if input.Enabled {
+		candidate := plugins.PluginConfig{URL: input.URL, APIKey: effectiveKey}
+		if err := s.registry.TestConfig(ctx, plugin, candidate); err != nil {
+			return &plugins.PluginError{
+				Code:    plugins.ErrCodeTestFailed,
+				Message: fmt.Sprintf("%s connection test failed — config not saved", plugin),
+				Cause:   err,
+			}
+		}
+	}
+
+	values := map[string]string{
+		plugins.SettingKeyURL(plugin):              input.URL,
+		plugins.SettingKeyLocalPathPrefix(plugin):  cleanPathPrefix(input.LocalPathPrefix),
+		plugins.SettingKeyServerPathPrefix(plugin): cleanPathPrefix(input.ServerPathPrefix),
+		plugins.SettingKeyWatchImportUser(plugin):  input.WatchImportUser,
+	}
+	for key, value := range values {
+		if err := s.settingsRepo.SetString(ctx, key, value); err != nil {
+			return fmt.Errorf("save %s: %w", key, err)
+		}
+	}
+	if err := s.settingsRepo.SetBool(ctx, plugins.SettingKeyEnabled(plugin), input.Enabled); err != nil {
+		return fmt.Errorf("save enabled: %w", err)
+	}
+	if input.APIKey != "" {
+		if err := s.secrets.Store(ctx, plugins.SettingKeyAPIKey(plugin), input.APIKey); err != nil {
+			return fmt.Errorf("encrypt api key: %w", err)
+		}
+	}
+
+	s.registry.CheckHealth(ctx, plugin)
+
+	s.logger.Info("Media server configuration saved", "plugin", plugin, "url", input.URL, "enabled", input.Enabled)
+	return nil
+}
+
+// TestConnection probes the body config when provided, else the saved config.
+func (s *MediaServerService) TestConnection(ctx context.Context, plugin string, input *MediaServerConfigInput) error {
+	if input != nil && input.URL != "" {
+		effectiveKey, err := effectivePluginAPIKey(ctx, s.secrets, plugin, input.APIKey)
+		if err != nil {
+			return err
+		}
+		return s.registry.TestConfig(ctx, plugin, plugins.PluginConfig{URL: input.URL, APIKey: effectiveKey})
+	}
+
+	config, _, err := s.registry.LoadConfig(ctx, plugin)
+	if err != nil {
+		return err
+	}
+	if config.URL == "" || config.APIKey == "" {
+		return &plugins.PluginError{
+			Code:    plugins.ErrCodeNotConfigured,
+			Message: fmt.Sprintf("%s is not configured", plugin),
+		}
+	}
+	return s.registry.TestConfig(ctx, plugin, config)
+}
+
+// ListServerUsers lists the server's accounts, for picking the import user.
+func (s *MediaServerService) ListServerUsers(ctx context.Context, plugin string) ([]plugins.MediaServerUser, error) {
+	source, err := s.watchSource(ctx, plugin)
+	if err != nil {
+		return nil, err
+	}
+	return source.ListUsers(ctx)
+}
+
+// RefreshLibrary asks the server for a full library scan.
+func (s *MediaServerService) RefreshLibrary(ctx context.Context, plugin string) error {
+	client, err := s.registry.GetMediaServer(ctx, plugin)
+	if err != nil {
+		return err
+	}
+	return client.RefreshLibrary(ctx)
+}
+
+// NotifyChanged queues a written file for the next refresh. It never blocks
+// the writer: the refresh runs once changes have been quiet for the debounce
+// window, or after maxRefreshDelay at the latest.
+func (s *MediaServerService) NotifyChanged(path string) {
+	if path == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+
+	if len(s.pending) == 0 {
+		s.pendingSince = time.Now()
+	}
+	s.pending[path] = struct{}{}
+
+	switch {
+	case s.timer == nil:
+		s.timer = time.AfterFunc(s.debounce, s.flushPending)
+	case time.Since(s.pendingSince) < maxRefreshDelay:
+		s.timer.Reset(s.debounce)
+	}
+}
+
+// Stop drops any queued refresh; later notifications are ignored.
+func (s *MediaServerService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
+
+func (s *MediaServerService) flushPending() {
+	s.mu.Lock()
+	paths := make([]string, 0, len(s.pending))
+	for p := range s.pending {
+		paths = append(paths, p)
+	}
+	s.pending = make(map[string]struct{})
+	s.timer = nil
+	s.mu.Unlock()
+
+	if len(paths) == 0 {
+		return
+	}
+	sort.Strings(paths)
+	s.refreshPaths(context.Background(), paths)
+}
+
+// refreshPaths reports paths to every enabled server. A failure is logged and
+// dropped: the server's own scheduled scan is the safety net.
+func (s *MediaServerService) refreshPaths(ctx context.Context, paths []string) {
+	for _, name := range s.registry.RegisteredMediaServers() {
+		client, err := s.registry.GetMediaServer(ctx, name)
+		if err != nil {
+			var pluginErr *plugins.PluginError
+			if !errors.As(err, &pluginErr) || pluginErr.Code != plugins.ErrCodeNotConfigured {
+				s.logger.Warn("Media server refresh skipped", "plugin", name, "error", err)
+			}
+			continue
+		}
+
+		localPrefix, serverPrefix := s.pathPrefixes(ctx, name)
+		mapped := make([]string, 0, len(paths))
+		for _, p := range paths {
+			mapped = append(mapped, replacePathPrefix(p, localPrefix, serverPrefix))
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx, refreshCallTimeout)
+		err = client.RefreshPaths(callCtx, mapped)
+		cancel()
+		if err != nil {
+			s.logger.Warn("Media server refresh failed", "plugin", name, "paths", len(mapped), "error", err)
+			continue
+		}
+		s.logger.Info("Media server refresh requested", "plugin", name, "paths", len(mapped))
+	}
+}
+
+// watchImportTarget pairs a Vido history with a server account.
+type watchImportTarget struct {
+	userID       string
+	serverUserID string
+}
+
+// ImportWatchState pulls played and part-played items from the server into
+// Vido's watch history. Each Vido account imports the server account with the
+// same name; an install without accounts imports the configured
+// watch_import_user into its shared history. Nothing recorded in Vido is
+// ever undone (see WatchService.MergeExternalState).
+func (s *MediaServerService) ImportWatchState(ctx context.Context, plugin string) (*WatchImportResult, error) {
+	client, err := s.watchSource(ctx, plugin)
+	if err != nil {
+		return nil, err
+	}
+	serverUsers, err := client.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := s.importTargets(ctx, plugin, serverUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	localPrefix, serverPrefix := s.pathPrefixes(ctx, plugin)
+	result := &WatchImportResult{Users: len(targets)}
+	for _, target := range targets {
+		items, err := client.GetPlayedItems(ctx, target.serverUserID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			mediaType, mediaID, err := s.matchItem(ctx, item, replacePathPrefix(item.Path, serverPrefix, localPrefix))
+			if err != nil {
+				return nil, err
+			}
+			if mediaID == "" {
+				result.Unmatched++
+				continue
+			}
+			changed, err := s.watch.MergeExternalState(ctx, target.userID, mediaType, mediaID, ExternalWatchState{
+				Watched:         item.Played,
+				PlayCount:       item.PlayCount,
+				PositionSeconds: item.PositionSeconds,
+				DurationSeconds: item.RuntimeSeconds,
+				LastPlayedAt:    item.LastPlayedAt,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("merge %s %s: %w", mediaType, mediaID, err)
+			}
+			if changed {
+				result.Imported++
+			} else {
+				result.Unchanged++
+			}
+		}
+	}
+
+	s.logger.Info("Media server watch state imported", "plugin", plugin,
+		"users", result.Users, "imported", result.Imported, "unmatched", result.Unmatched)
+	return result, nil
+}
+
+// watchSource returns the plugin's played-state export; servers without one
+// (Plex) fail validation rather than pretending to import nothing.
+func (s *MediaServerService) watchSource(ctx context.Context, plugin string) (plugins.WatchStateSource, error) {
+	client, err := s.registry.GetMediaServer(ctx, plugin)
+	if err != nil {
+		return nil, err
+	}
+	source, ok := client.(plugins.WatchStateSource)
+	if !ok {
+		return nil, &models.ValidationError{Field: "plugin", Message: fmt.Sprintf("%s does not support watch-state import", plugin)}
+	}
+	return source, nil
+}
+
+func (s *MediaServerService) importTargets(ctx context.Context, plugin string, serverUsers []plugins.MediaServerUser) ([]watchImportTarget, error) {
+	findServerUser := func(name string) string {
+		for _, u := range serverUsers {
+			if strings.EqualFold(u.Name, name) {
+				return u.ID
+			}
+		}
+		return ""
+	}
+
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		importUser, _ := s.settingsRepo.GetString(ctx, plugins.SettingKeyWatchImportUser(plugin))
+		if importUser == "" {
+			return nil, &models.ValidationError{Field: "watch_import_user", Message: "choose which server account to import"}
+		}
+		serverUserID := findServerUser(importUser)
+		if serverUserID == "" {
+			return nil, &models.ValidationError{Field: "watch_import_user", Message: fmt.Sprintf("%s has no account named %q", plugin, importUser)}
+		}
+		return []watchImportTarget{{userID: "", serverUserID: serverUserID}}, nil
+	}
+
+	var targets []watchImportTarget
+	for _, user := range users {
+		if serverUserID := findServerUser(user.Username); serverUserID != "" {
+			targets = append(targets, watchImportTarget{userID: user.ID, serverUserID: serverUserID})
+		}
+	}
+	if len(targets) == 0 {
+		return nil, &models.ValidationError{Field: "users", Message: fmt.Sprintf("no %s account has the same name as a Vido account", plugin)}
+	}
+	return targets, nil
+}
+
+// matchItem finds the Vido movie or episode for a server item, by file path
+// first; movies fall back to their TMDb id for libraries Vido and the server
+// index from different copies. An empty id means no match.
+func (s *MediaServerService) matchItem(ctx context.Context, item plugins.PlayedItem, localPath string) (string, string, error) {
+	switch item.Type {
+	case plugins.MediaServerItemMovie:
+		movie, err := s.movies.FindByFilePath(ctx, localPath)
+		if err != nil {
+			return "", "", err
+		}
+		if movie == nil && item.TMDbID > 0 {
+			// FindByTMDbID reports a miss as an error; a miss is all it can be here.
+			movie, _ = s.movies.FindByTMDbID(ctx, item.TMDbID)
+		}
+		if movie == nil {
+			return models.WatchMediaMovie, "", nil
+		}
+		return models.WatchMediaMovie, movie.ID, nil
+	case plugins.MediaServerItemEpisode:
+		episode, err := s.episodes.FindByFilePath(ctx, localPath)
+		if err != nil {
+			return "", "", err
+		}
+		if episode == nil {
+			return models.WatchMediaEpisode, "", nil
+		}
+		return models.WatchMediaEpisode, episode.ID, nil
+	}
+	return "", "", nil
+}
+
+func (s *MediaServerService) pathPrefixes(ctx context.Context, plugin string) (local, server string) {
+	local, _ = s.settingsRepo.GetString(ctx, plugins.SettingKeyLocalPathPrefix(plugin))
+	server, _ = s.settingsRepo.GetString(ctx, plugins.SettingKeyServerPathPrefix(plugin))
+	return local, server
+}
+
+// replacePathPrefix rewrites p from one mount's view to another's. Only a
+// whole leading path segment matches: /media does not rewrite /media2/x.
+// Paths outside the prefix pass through, for setups that share some mounts.
+func replacePathPrefix(p, from, to string) string {
+	if from == "" {
+		return p
+	}
+	if from == "/" {
+		return path.Join(to, p)
+	}
+	if p == from {
+		return to
+	}
+	if rest, ok := strings.CutPrefix(p, from+"/"); ok {
+		return path.Join(to, rest)
+	}
+	return p
+}
+
+// cleanPathPrefix normalizes a configured prefix; "" stays "" (no mapping).
+func cleanPathPrefix(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	return path.Clean(p)
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/plugins"
+)
+
+type fakeMediaServer struct {
+	mu        sync.Mutex
+	refreshed [][]string
+	users     []plugins.MediaServerUser
+	played    map[string][]plugins.PlayedItem
+}
+
+func (f *fakeMediaServer) Name() string { return "jellyfin" }
+func (f *fakeMediaServer) TestConnection(context.Context, plugins.PluginConfig) error {
+	return nil
+}
+func (f *fakeMediaServer) RefreshLibrary(context.Context) error { return nil }
+func (f *fakeMediaServer) RefreshPaths(_ context.Context, paths []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.refreshed = append(f.refreshed, paths)
+	return nil
+}
+func (f *fakeMediaServer) ListUsers(context.Context) ([]plugins.MediaServerUser, error) {
+	return f.users, nil
+}
+func (f *fakeMediaServer) GetPlayedItems(_ context.Context, userID string) ([]plugins.PlayedItem, error) {
+	return f.played[userID], nil
+}
+
+func (f *fakeMediaServer) refreshCalls() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.refreshed...)
+}
+
+// fakeMediaServerRegistry serves one configured server; testErr fails
+// TestConfig, refreshOnly hides its WatchStateSource side.
+type fakeMediaServerRegistry struct {
+	server      *fakeMediaServer
+	testErr     error
+	refreshOnly bool
+}
+
+func (f *fakeMediaServerRegistry) RegisteredMediaServers() []string { return []string{"jellyfin"} }
+func (f *fakeMediaServerRegistry) GetMediaServer(context.Context, string) (plugins.MediaServerPlugin, error) {
+	if f.refreshOnly {
+		return struct{ plugins.MediaServerPlugin }{f.server}, nil
+	}
+	return f.server, nil
+}
+func (f *fakeMediaServerRegistry) LoadConfig(context.Context, string) (plugins.PluginConfig, bool, error) {
+	return plugins.PluginConfig{URL: "http://nas:8096", APIKey: "key"}, true, nil
+}
+func (f *fakeMediaServerRegistry) TestConfig(context.Context, string, plugins.PluginConfig) error {
+	return f.testErr
+}
+func (f *fakeMediaServerRegistry) CheckHealth(context.Context, string) plugins.PluginHealth {
+	return plugins.PluginHealth{}
+}
+func (f *fakeMediaServerRegistry) Health(string) plugins.PluginHealth { return plugins.PluginHealth{} }
+
+type fakeUserLister []models.User
+
+func (f fakeUserLister) List(context.Context) ([]models.User, error) { return f, nil }
+
+type mediaServerFixture struct {
+	*watchFixture
+	svc      *MediaServerService
+	server   *fakeMediaServer
+	registry *fakeMediaServerRegistry
+	settings *fakeDVRSettingsRepo
+}
+
+func setupMediaServerService(t *testing.T, users ...models.User) *mediaServerFixture {
+	t.Helper()
+	wf := setupWatchService(t)
+	server := &fakeMediaServer{played: map[string][]plugins.PlayedItem{}}
+	registry := &fakeMediaServerRegistry{server: server}
+	settings := newFakeDVRSettingsRepo()
+	svc := NewMediaServerService(registry, settings, newFakeDVRSecrets(), fakeUserLister(users),
+		wf.movies, wf.episodes, wf.svc, nil)
+	t.Cleanup(svc.Stop)
+	return &mediaServerFixture{watchFixture: wf, svc: svc, server: server, registry: registry, settings: settings}
+}
+
+func TestReplacePathPrefix(t *testing.T) {
+	tests := []struct {
+		name, path, from, to, want string
+	}{
+		{"no mapping", "/data/a.mkv", "", "/media", "/data/a.mkv"},
+		{"leading segment", "/data/movies/a.mkv", "/data", "/media", "/media/movies/a.mkv"},
+		{"prefix itself", "/data", "/data", "/media", "/media"},
+		{"partial segment is not a match", "/data2/a.mkv", "/data", "/media", "/data2/a.mkv"},
+		{"outside prefix", "/other/a.mkv", "/data", "/media", "/other/a.mkv"},
+		{"root prefix", "/movies/a.mkv", "/", "/mnt/nas", "/mnt/nas/movies/a.mkv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, replacePathPrefix(tt.path, tt.from, tt.to))
+		})
+	}
+}
+
+func TestMediaServerService_SaveConfig(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("prefixes must be set together", func(t *testing.T) {
+		f := setupMediaServerService(t)
+		err := f.svc.SaveConfig(ctx, "jellyfin", MediaServerConfigInput{URL: "http://nas:8096", LocalPathPrefix: "/data"})
+		var validationErr *models.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("failed test is not saved", func(t *testing.T) {
+		f := setupMediaServerService(t)
+		f.registry.testErr = &plugins.PluginError{Code: plugins.ErrCodeMediaServerAuthFailed, Message: "rejected"}
+		err := f.svc.SaveConfig(ctx, "jellyfin", MediaServerConfigInput{URL: "http://nas:8096", APIKey: "k", Enabled: true})
+
+		var pluginErr *plugins.PluginError
+		require.ErrorAs(t, err, &pluginErr)
+		assert.Equal(t, plugins.ErrCodeTestFailed, pluginErr.Code)
+		_, getErr := f.settings.GetString(ctx, plugins.SettingKeyURL("jellyfin"))
+		assert.Error(t, getErr)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		f := setupMediaServerService(t)
+		require.NoError(t, f.svc.SaveConfig(ctx, "jellyfin", MediaServerConfigInput{
+			URL: "http://nas:8096", APIKey: "k", Enabled: true,
+			LocalPathPrefix: "/data/", ServerPathPrefix: " /media ", WatchImportUser: "alice",
+		}))
+
+		status, err := f.svc.GetConfig(ctx, "jellyfin")
+		require.NoError(t, err)
+		assert.True(t, status.Enabled)
+		assert.True(t, status.HasAPIKey)
+		assert.Equal(t, "/data", status.LocalPathPrefix)
+		assert.Equal(t, "/media", status.ServerPathPrefix)
+		assert.Equal(t, "alice", status.WatchImportUser)
+	})
+}
+
+func TestMediaServerService_NotifyChangedDebounces(t *testing.T) {
+	ctx := context.Background()
+	f := setupMediaServerService(t)
+	f.svc.debounce = 20 * time.Millisecond
+	require.NoError(t, f.settings.SetString(ctx, plugins.SettingKeyLocalPathPrefix("jellyfin"), "/data"))
+	require.NoError(t, f.settings.SetString(ctx, plugins.SettingKeyServerPathPrefix("jellyfin"), "/media"))
+
+	f.svc.NotifyChanged("/data/B/B.zh-Hant.srt")
+	f.svc.NotifyChanged("/data/A/A.nfo")
+	f.svc.NotifyChanged("/data/A/A.nfo")
+
+	require.Eventually(t, func() bool { return len(f.server.refreshCalls()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"/media/A/A.nfo", "/media/B/B.zh-Hant.srt"}, f.server.refreshCalls()[0],
+		"one call with the burst deduplicated and mapped to the server's mount")
+
+	t.Run("stopped service ignores notifications", func(t *testing.T) {
+		f.svc.Stop()
+		f.svc.NotifyChanged("/data/C/C.srt")
+		time.Sleep(60 * time.Millisecond)
+		assert.Len(t, f.server.refreshCalls(), 1)
+	})
+}
+
+func TestMediaServerService_ImportWatchState(t *testing.T) {
+	ctx := context.Background()
+	lastPlayed := time.Date(2026, 9, 1, 20, 0, 0, 0, time.UTC)
+
+	t.Run("accounts import the server account with the same name", func(t *testing.T) {
+		f := setupMediaServerService(t,
+			models.User{ID: "vido-alice", Username: "Alice"},
+			models.User{ID: "vido-bob", Username: "bob"})
+		require.NoError(t, f.settings.SetString(ctx, plugins.SettingKeyLocalPathPrefix("jellyfin"), "/tv"))
+		require.NoError(t, f.settings.SetString(ctx, plugins.SettingKeyServerPathPrefix("jellyfin"), "/media/tv"))
+		f.addShow(t, "show", [2]int{1, 1}, [2]int{1, 2})
+		require.NoError(t, f.movies.Create(ctx, &models.Movie{ID: "m1", Title: "Movie", TMDbID: models.NewNullInt64(603)}))
+
+		f.server.users = []plugins.MediaServerUser{{ID: "jf-alice", Name: "alice"}, {ID: "jf-carol", Name: "carol"}}
+		f.server.played["jf-alice"] = []plugins.PlayedItem{
+			{Type: plugins.MediaServerItemEpisode, Path: "/media/tv/show/S01E01.mkv", Played: true, PlayCount: 2, LastPlayedAt: &lastPlayed},
+			{Type: plugins.MediaServerItemEpisode, Path: "/media/tv/show/S01E02.mkv", PositionSeconds: 600, RuntimeSeconds: 2400},
+			{Type: plugins.MediaServerItemMovie, Path: "/elsewhere/Movie.mkv", TMDbID: 603, Played: true},
+			{Type: plugins.MediaServerItemMovie, Path: "/elsewhere/Unknown.mkv", Played: true},
+		}
+
+		result, err := f.svc.ImportWatchState(ctx, "jellyfin")
+		require.NoError(t, err)
+		assert.Equal(t, &WatchImportResult{Users: 1, Imported: 3, Unmatched: 1}, result)
+
+		state, err := f.watchFixture.svc.GetState(ctx, "vido-alice", models.WatchMediaEpisode, "show-s1e1")
+		require.NoError(t, err)
+		assert.True(t, state.Watched)
+		assert.Equal(t, 2, state.WatchCount)
+
+		state, err = f.watchFixture.svc.GetState(ctx, "vido-alice", models.WatchMediaEpisode, "show-s1e2")
+		require.NoError(t, err)
+		assert.InDelta(t, 600, state.PositionSeconds, 0.01)
+
+		state, err = f.watchFixture.svc.GetState(ctx, "vido-alice", models.WatchMediaMovie, "m1")
+		require.NoError(t, err)
+		assert.True(t, state.Watched, "matched by TMDb id when the paths differ")
+
+		state, err = f.watchFixture.svc.GetState(ctx, "vido-bob", models.WatchMediaMovie, "m1")
+		require.NoError(t, err)
+		assert.False(t, state.Watched, "bob has no server account")
+
+		again, err := f.svc.ImportWatchState(ctx, "jellyfin")
+		require.NoError(t, err)
+		assert.Equal(t, 0, again.Imported)
+		assert.Equal(t, 3, again.Unchanged, "a repeated import changes nothing")
+	})
+
+	t.Run("no matching accounts is a validation error", func(t *testing.T) {
+		f := setupMediaServerService(t, models.User{ID: "vido-dave", Username: "dave"})
+		f.server.users = []plugins.MediaServerUser{{ID: "jf-alice", Name: "alice"}}
+
+		_, err := f.svc.ImportWatchState(ctx, "jellyfin")
+		var validationErr *models.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("install without accounts uses the configured import user", func(t *testing.T) {
+		f := setupMediaServerService(t)
+		require.NoError(t, f.movies.Create(ctx, &models.Movie{ID: "m1", Title: "Movie", FilePath: models.NewNullString("/data/Movie.mkv")}))
+		f.server.users = []plugins.MediaServerUser{{ID: "jf-alice", Name: "alice"}}
+		f.server.played["jf-alice"] = []plugins.PlayedItem{
+			{Type: plugins.MediaServerItemMovie, Path: "/data/Movie.mkv", Played: true},
+		}
+
+		_, err := f.svc.ImportWatchState(ctx, "jellyfin")
+		var validationErr *models.ValidationError
+		require.ErrorAs(t, err, &validationErr, "no import user configured yet")
+
+		require.NoError(t, f.settings.SetString(ctx, plugins.SettingKeyWatchImportUser("jellyfin"), "alice"))
+		result, err := f.svc.ImportWatchState(ctx, "jellyfin")
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Imported)
+
+		state, err := f.watchFixture.svc.GetState(ctx, "", models.WatchMediaMovie, "m1")
+		require.NoError(t, err)
+		assert.True(t, state.Watched)
+	})
+}
+
+func TestMediaServerService_RefreshOnlyServer(t *testing.T) {
+	ctx := context.Background()
+	f := setupMediaServerService(t)
+	f.registry.refreshOnly = true // a server without a played-state export, like Plex
+
+	_, err := f.svc.ImportWatchState(ctx, "plex")
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, validationErr.Message, "does not support watch-state import")
+
+	_, err = f.svc.ListServerUsers(ctx, "plex")
+	assert.ErrorAs(t, err, &validationErr)
+
+	assert.NoError(t, f.svc.RefreshLibrary(ctx, "plex"), "refreshing still works")
+}
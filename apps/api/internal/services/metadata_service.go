@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/vido/api/internal/metadata"
+	"github.com/vido/api/internal/metrics"
 	"github.com/vido/api/internal/models"
 	"github.com/vido/api/internal/retry"
 )
@@ -317,15 +318,16 @@ type PosterUploader interface {
 
 // MetadataService implements MetadataServiceInterface
 type MetadataService struct {
-	orchestrator     *metadata.Orchestrator
-	tmdbProvider     *metadata.TMDbProvider
-	doubanProvider   *metadata.DoubanProvider // Story 12-1: shared with DoubanRatingService (single rate limiter)
-	movieUpdater     MediaUpdater
-	seriesUpdater    MediaUpdater
-	movieEditor      MetadataEditor
-	seriesEditor     MetadataEditor
-	posterUploader   PosterUploader
-	retryService     RetryServiceInterface // Story 3.11: Auto-retry integration
+	orchestrator      *metadata.Orchestrator
+	tmdbProvider      *metadata.TMDbProvider
+	doubanProvider    *metadata.DoubanProvider // Story 12-1: shared with DoubanRatingService (single rate limiter)
+	wikipediaProvider *metadata.WikipediaProvider
+	movieUpdater      MediaUpdater
+	seriesUpdater     MediaUpdater
+	movieEditor       MetadataEditor
+	seriesEditor      MetadataEditor
+	posterUploader    PosterUploader
+	retryService      RetryServiceInterface // Story 3.11: Auto-retry integration
 }
 
 // Compile-time interface verification
@@ -386,8 +388,9 @@ func NewMetadataService(cfg MetadataServiceConfig, tmdbSearcher metadata.TMDbSea
 	}
 
 	// Register Wikipedia provider if enabled
+	var wikipediaProvider *metadata.WikipediaProvider
 	if cfg.EnableWikipedia {
-		wikipediaProvider := metadata.NewWikipediaProvider(metadata.WikipediaProviderConfig{
+		wikipediaProvider = metadata.NewWikipediaProvider(metadata.WikipediaProviderConfig{
 			Enabled: true,
 		})
 		orch.RegisterProvider(wikipediaProvider)
@@ -402,9 +405,10 @@ func NewMetadataService(cfg MetadataServiceConfig, tmdbSearcher metadata.TMDbSea
 	)
 
 	return &MetadataService{
-		orchestrator:   orch,
-		tmdbProvider:   tmdbProvider,
-		doubanProvider: doubanProvider,
+		orchestrator:      orch,
+		tmdbProvider:      tmdbProvider,
+		doubanProvider:    doubanProvider,
+		wikipediaProvider: wikipediaProvider,
 	}
 }
 
@@ -424,6 +428,7 @@ func (s *MetadataService) SearchMetadata(ctx context.Context, req *SearchMetadat
 
 	metaReq := req.ToMetadataRequest()
 	result, status := s.orchestrator.Search(ctx, metaReq)
+	observeParseOutcome(result, status)
 
 	// Story 3.11: Queue retry if search failed due to transient errors
 	if result == nil || len(result.Items) == 0 {
@@ -433,6 +438,51 @@ func (s *MetadataService) SearchMetadata(ctx context.Context, req *SearchMetadat
 	return result, status, nil
 }
 
+// observeParseOutcome counts one lookup on /metrics by the source that
+// matched. Cancelled lookups are counted apart so a shutdown does not read as
+// a wave of misses.
+func observeParseOutcome(result *metadata.SearchResult, status *metadata.FallbackStatus) {
+	switch {
+	case result != nil && result.HasResults():
+		metrics.ParseOutcomes.WithLabelValues(string(result.Source), "matched").Inc()
+	case status != nil && status.Cancelled:
+		metrics.ParseOutcomes.WithLabelValues("none", "cancelled").Inc()
+	default:
+		metrics.ParseOutcomes.WithLabelValues("none", "not_found").Inc()
+	}
+}
+
+// RegisterMetrics exposes provider state on reg: circuit breaker states (0
+// closed, 1 open, 2 half-open) and the Douban/Wikipedia client counters.
+func (s *MetadataService) RegisterMetrics(reg *metrics.Registry) {
+	reg.NewGaugeFunc("vido_metadata_provider_circuit_state",
+		"Fallback-chain circuit breaker state per metadata provider (0 closed, 1 open, 2 half-open).",
+		[]string{"provider"}, func(emit metrics.Emit) {
+			for name, state := range s.orchestrator.CircuitBreakerStates() {
+				emit(float64(state), name)
+			}
+		})
+	reg.NewCounterFunc("vido_metadata_client_requests_total",
+		"Douban and Wikipedia HTTP client requests, by result.",
+		[]string{"client", "result"}, func(emit metrics.Emit) {
+			if s.doubanProvider != nil {
+				m := s.doubanProvider.GetClientMetrics()
+				emit(float64(m.TotalRequests), "douban", "total")
+				emit(float64(m.SuccessfulRequests), "douban", "success")
+				emit(float64(m.BlockedRequests), "douban", "blocked")
+				emit(float64(m.TimeoutRequests), "douban", "timeout")
+				emit(float64(m.RetryCount), "douban", "retry")
+			}
+			if s.wikipediaProvider != nil {
+				m := s.wikipediaProvider.GetClientMetrics()
+				emit(float64(m.TotalRequests), "wikipedia", "total")
+				emit(float64(m.SuccessfulRequests), "wikipedia", "success")
+				emit(float64(m.FailedRequests), "wikipedia", "failed")
+				emit(float64(m.RateLimitedCount), "wikipedia", "rate_limited")
+			}
+		})
+}
+
 // GetProviders returns information about registered providers
 func (s *MetadataService) GetProviders() []ProviderInfo {
 	providers := s.orchestrator.Providers()
@@ -30,6 +30,10 @@ type NFOLocalizerService struct {
 	// Optional / nil-safe — unwired means `include_episodes` localizes the show
 	// file only.
 	episodes NFOEpisodeLister
+
+	// changes hears about every .nfo written so a media server refreshes the
+	// item. Optional / nil-safe.
+	changes MediaChangeNotifier
 }
 
 // NewNFOLocalizerService creates the localizer. Returns nil only when no
@@ -86,7 +90,20 @@ func (s *NFOLocalizerService) LocalizeMovieNFO(ctx context.Context, movie models
 	}
 
 	data := marshalNFO(localized)
-	return writeAdditiveNFO(movie.FilePath.String, data)
+	return s.notifyWritten(writeAdditiveNFO(movie.FilePath.String, data))
+}
+
+// SetChangeNotifier wires the optional media-server refresh notification.
+func (s *NFOLocalizerService) SetChangeNotifier(n MediaChangeNotifier) {
+	s.changes = n
+}
+
+// notifyWritten passes a successful write on to the change notifier.
+func (s *NFOLocalizerService) notifyWritten(result *NFOLocalizeResult, err error) (*NFOLocalizeResult, error) {
+	if err == nil && result != nil && s.changes != nil {
+		s.changes.NotifyChanged(result.Path)
+	}
+	return result, err
 }
 
 // loadGlossary loads the per-show glossary as translation pairs (fail-soft).
@@ -338,7 +355,7 @@ func (s *NFOLocalizerService) LocalizeTVShowNFO(ctx context.Context, series mode
 	// Taking filepath.Dir of it — the movie path's shape, where FilePath is a
 	// FILE — would write tvshow.nfo one level UP, into the library root: no
 	// player would find it and the user's library root would be polluted.
-	return writeReplaceNFO(filepath.Join(series.FilePath.String, "tvshow.nfo"), marshalNFO(localized))
+	return s.notifyWritten(writeReplaceNFO(filepath.Join(series.FilePath.String, "tvshow.nfo"), marshalNFO(localized)))
 }
 
 // LocalizeEpisodeNFO localizes one episode and writes `<basename>.nfo` beside
@@ -378,7 +395,7 @@ func (s *NFOLocalizerService) localizeEpisodeWithGlossary(ctx context.Context, e
 	// the opposite of the series case above.
 	dir := filepath.Dir(episode.FilePath.String)
 	base := strings.TrimSuffix(filepath.Base(episode.FilePath.String), filepath.Ext(episode.FilePath.String))
-	return writeReplaceNFO(filepath.Join(dir, base+".nfo"), marshalNFO(localized))
+	return s.notifyWritten(writeReplaceNFO(filepath.Join(dir, base+".nfo"), marshalNFO(localized)))
 }
 
 // LocalizeSeriesNFOWithEpisodes localizes tvshow.nfo and then every episode.
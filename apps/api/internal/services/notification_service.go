@@ -0,0 +1,427 @@
+// Package services — NotificationService.
+//
+// Outbound notifications for what otherwise only reaches an open browser over
+// the SSE hub. Producers (scanner, subtitle pipeline, request poller, backup
+// scheduler, health monitor) hand a models.NotificationEvent to Notify; every
+// enabled target subscribed to its type gets it rendered through the
+// target's own templates and sent by notify.Dispatcher. A transient failure
+// is queued on the retry queue as a "notification" task, which re-renders
+// from the stored event against the target as it is at retry time.
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/notify"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/retry"
+	"github.com/vido/api/internal/secrets"
+)
+
+// notificationDeliveryTimeout bounds one send, SMTP included.
+const notificationDeliveryTimeout = 30 * time.Second
+
+// notificationSecretKey is the secrets-store key of a target's credential.
+func notificationSecretKey(targetID string) string {
+	return "notification." + targetID + ".secret"
+}
+
+// EventNotifier is the narrow port producers depend on. Notify never blocks
+// on delivery and never fails the caller.
+type EventNotifier interface {
+	Notify(ctx context.Context, event models.NotificationEvent)
+}
+
+// NotificationTargetInput is the POST/PUT body for a target. An empty Secret
+// means "keep the stored one" — responses never return it, so the UI cannot
+// echo it back. Type is fixed once created and ignored on update.
+type NotificationTargetInput struct {
+	Name          string                         `json:"name"`
+	Type          string                         `json:"type"`
+	Enabled       bool                           `json:"enabled"`
+	Events        []models.NotificationEventType `json:"events"`
+	Config        map[string]string              `json:"config"`
+	Secret        string                         `json:"secret"`
+	TitleTemplate string                         `json:"title_template"`
+	BodyTemplate  string                         `json:"body_template"`
+}
+
+// NotificationServiceInterface defines the contract for notification target
+// management (Rule 11 — handlers consume this from the services package).
+type NotificationServiceInterface interface {
+	ListTargets(ctx context.Context) ([]models.NotificationTarget, error)
+	GetTarget(ctx context.Context, id string) (*models.NotificationTarget, error)
+	CreateTarget(ctx context.Context, input NotificationTargetInput) (*models.NotificationTarget, error)
+	UpdateTarget(ctx context.Context, id string, input NotificationTargetInput) (*models.NotificationTarget, error)
+	DeleteTarget(ctx context.Context, id string) error
+	// TestTarget sends a test notification to a saved target, synchronously,
+	// and returns the delivery error as is (no retry).
+	TestTarget(ctx context.Context, id string) error
+}
+
+// notificationSender is the notify.Dispatcher seam.
+type notificationSender interface {
+	Send(ctx context.Context, dest notify.Destination, msg notify.Message) error
+}
+
+// notificationRetryQueue is the slice of RetryServiceInterface used here.
+type notificationRetryQueue interface {
+	QueueRetry(ctx context.Context, taskID, taskType string, payload interface{}, err error) error
+}
+
+// notificationRetryPayload is the retry item payload of a failed delivery.
+type notificationRetryPayload struct {
+	TargetID string                   `json:"target_id"`
+	Event    models.NotificationEvent `json:"event"`
+}
+
+// NotificationService implements NotificationServiceInterface and
+// EventNotifier.
+type NotificationService struct {
+	repo    repository.NotificationTargetRepositoryInterface
+	secrets secrets.SecretsServiceInterface
+	sender  notificationSender
+	retries notificationRetryQueue
+	logger  *slog.Logger
+
+	// inflight tracks asynchronous deliveries so tests can wait for them.
+	inflight sync.WaitGroup
+}
+
+// Compile-time interface verification.
+var (
+	_ NotificationServiceInterface  = (*NotificationService)(nil)
+	_ EventNotifier                 = (*NotificationService)(nil)
+	_ retry.NotificationRedeliverer = (*NotificationService)(nil)
+)
+
+// NewNotificationService creates a new NotificationService.
+func NewNotificationService(
+	repo repository.NotificationTargetRepositoryInterface,
+	secretsService secrets.SecretsServiceInterface,
+	sender notificationSender,
+	logger *slog.Logger,
+) *NotificationService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &NotificationService{repo: repo, secrets: secretsService, sender: sender, logger: logger}
+}
+
+// SetRetryQueue enables queueing of transiently failed deliveries. Without
+// it a failed delivery is logged and dropped.
+func (s *NotificationService) SetRetryQueue(queue notificationRetryQueue) {
+	s.retries = queue
+}
+
+// ListTargets returns every target with HasSecret filled in.
+func (s *NotificationService) ListTargets(ctx context.Context) ([]models.NotificationTarget, error) {
+	targets, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range targets {
+		targets[i].HasSecret = s.hasSecret(ctx, targets[i].ID)
+	}
+	return targets, nil
+}
+
+// GetTarget returns one target with HasSecret filled in.
+func (s *NotificationService) GetTarget(ctx context.Context, id string) (*models.NotificationTarget, error) {
+	target, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	target.HasSecret = s.hasSecret(ctx, id)
+	return target, nil
+}
+
+// CreateTarget validates and stores a new target and its credential.
+func (s *NotificationService) CreateTarget(ctx context.Context, input NotificationTargetInput) (*models.NotificationTarget, error) {
+	target := &models.NotificationTarget{ID: uuid.New().String(), Type: input.Type}
+	applyNotificationInput(target, input)
+	if err := s.validate(target, input.Secret); err != nil {
+		return nil, err
+	}
+
+	if input.Secret != "" {
+		if err := s.secrets.Store(ctx, notificationSecretKey(target.ID), input.Secret); err != nil {
+			return nil, fmt.Errorf("store notification secret: %w", err)
+		}
+	}
+	if err := s.repo.Create(ctx, target); err != nil {
+		return nil, err
+	}
+	target.HasSecret = input.Secret != ""
+	return target, nil
+}
+
+// UpdateTarget replaces a target's settings; an empty Secret keeps the
+// stored credential.
+func (s *NotificationService) UpdateTarget(ctx context.Context, id string, input NotificationTargetInput) (*models.NotificationTarget, error) {
+	target, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	applyNotificationInput(target, input)
+
+	secret := input.Secret
+	if secret == "" {
+		secret, err = s.storedSecret(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.validate(target, secret); err != nil {
+		return nil, err
+	}
+
+	if input.Secret != "" {
+		if err := s.secrets.Store(ctx, notificationSecretKey(id), input.Secret); err != nil {
+			return nil, fmt.Errorf("store notification secret: %w", err)
+		}
+	}
+	if err := s.repo.Update(ctx, target); err != nil {
+		return nil, err
+	}
+	target.HasSecret = secret != ""
+	return target, nil
+}
+
+// DeleteTarget removes a target and its credential. Queued retries for it
+// find no target and are dropped.
+func (s *NotificationService) DeleteTarget(ctx context.Context, id string) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := s.secrets.Delete(ctx, notificationSecretKey(id)); err != nil {
+		s.logger.Warn("Failed to delete notification secret", "target_id", id, "error", err)
+	}
+	return nil
+}
+
+// TestTarget sends a test notification to a saved target, enabled or not.
+func (s *NotificationService) TestTarget(ctx context.Context, id string) error {
+	target, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.deliver(ctx, target, models.NotificationEvent{
+		ID:         uuid.New().String(),
+		Type:       models.NotificationEventTest,
+		Title:      "Vido 測試通知",
+		Message:    fmt.Sprintf("「%s」設定正確，之後的通知會送到這裡。", target.Name),
+		OccurredAt: time.Now(),
+	})
+}
+
+// Notify fans event out to every enabled target subscribed to its type. The
+// target lookup is synchronous; delivery runs in the background on a context
+// detached from the caller's, so a finished HTTP request or tick does not
+// cancel it.
+func (s *NotificationService) Notify(ctx context.Context, event models.NotificationEvent) {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	targets, err := s.repo.List(ctx)
+	if err != nil {
+		s.logger.Error("Failed to load notification targets", "event", event.Type, "error", err)
+		return
+	}
+
+	detached := context.WithoutCancel(ctx)
+	for i := range targets {
+		target := targets[i]
+		if !target.Enabled || !target.Subscribes(event.Type) {
+			continue
+		}
+		s.inflight.Add(1)
+		go func() {
+			defer s.inflight.Done()
+			s.dispatch(detached, &target, event)
+		}()
+	}
+}
+
+// DegradationChanged adapts health.HealthMonitor's degradation handler into a
+// degradation_changed event. The monitor's message is empty on recovery.
+func (s *NotificationService) DegradationChanged(prev, next models.DegradationLevel, message string) {
+	if message == "" {
+		message = "所有外部服務已恢復正常"
+	}
+	s.Notify(context.Background(), models.NotificationEvent{
+		Type:    models.NotificationEventDegradationChanged,
+		Title:   "服務狀態變更",
+		Message: message,
+		Data: map[string]interface{}{
+			"previous_level": string(prev),
+			"level":          string(next),
+		},
+	})
+}
+
+// RedeliverNotification is the retry-queue executor hook. A target deleted,
+// disabled or unsubscribed since the first attempt ends the retry quietly.
+func (s *NotificationService) RedeliverNotification(ctx context.Context, payload json.RawMessage) error {
+	var p notificationRetryPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return retry.WrapNonRetryable(fmt.Errorf("decode notification retry payload: %w", err), notify.ErrCodeDeliveryFailed)
+	}
+	target, err := s.repo.GetByID(ctx, p.TargetID)
+	if errors.Is(err, repository.ErrNotificationTargetNotFound) {
+		s.logger.Info("Dropping notification retry for a deleted target", "target_id", p.TargetID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !target.Enabled || !target.Subscribes(p.Event.Type) {
+		return nil
+	}
+	return s.deliver(ctx, target, p.Event)
+}
+
+// dispatch delivers once and queues a retry on a transient failure.
+func (s *NotificationService) dispatch(ctx context.Context, target *models.NotificationTarget, event models.NotificationEvent) {
+	err := s.deliver(ctx, target, event)
+	if err == nil {
+		return
+	}
+	s.logger.Warn("Notification delivery failed",
+		"target_id", target.ID, "target", target.Name, "event", event.Type, "error", err)
+
+	if s.retries == nil {
+		return
+	}
+	taskID := "notification:" + target.ID + ":" + event.ID
+	payload := notificationRetryPayload{TargetID: target.ID, Event: event}
+	if qErr := s.retries.QueueRetry(ctx, taskID, retry.TaskTypeNotification, payload, err); qErr != nil {
+		s.logger.Debug("Notification delivery not queued for retry", "task_id", taskID, "reason", qErr)
+	}
+}
+
+// deliver renders event for target and sends it.
+func (s *NotificationService) deliver(ctx context.Context, target *models.NotificationTarget, event models.NotificationEvent) error {
+	secret, err := s.storedSecret(ctx, target.ID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notificationDeliveryTimeout)
+	defer cancel()
+	return s.sender.Send(ctx, notify.Destination{
+		Type:   target.Type,
+		Config: target.Config,
+		Secret: secret,
+	}, s.render(target, event))
+}
+
+// storedSecret returns the target's credential, "" when none is stored.
+func (s *NotificationService) storedSecret(ctx context.Context, targetID string) (string, error) {
+	key := notificationSecretKey(targetID)
+	exists, err := s.secrets.Exists(ctx, key)
+	if err != nil || !exists {
+		return "", err
+	}
+	secret, err := s.secrets.Retrieve(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("retrieve notification secret: %w", err)
+	}
+	return secret, nil
+}
+
+func (s *NotificationService) hasSecret(ctx context.Context, targetID string) bool {
+	exists, _ := s.secrets.Exists(ctx, notificationSecretKey(targetID))
+	return exists
+}
+
+// validate runs the common checks, the template parse and the type-specific
+// checks against the effective secret.
+func (s *NotificationService) validate(target *models.NotificationTarget, secret string) error {
+	if err := target.Validate(); err != nil {
+		return err
+	}
+	if _, err := parseNotificationTemplate(target.TitleTemplate); err != nil {
+		return &models.ValidationError{Field: "title_template", Message: fmt.Sprintf("title_template does not parse: %v", err)}
+	}
+	if _, err := parseNotificationTemplate(target.BodyTemplate); err != nil {
+		return &models.ValidationError{Field: "body_template", Message: fmt.Sprintf("body_template does not parse: %v", err)}
+	}
+	return notify.Validate(notify.Destination{Type: target.Type, Config: target.Config, Secret: secret})
+}
+
+// applyNotificationInput copies the mutable fields of input onto target.
+func applyNotificationInput(target *models.NotificationTarget, input NotificationTargetInput) {
+	target.Name = strings.TrimSpace(input.Name)
+	target.Enabled = input.Enabled
+	target.Events = input.Events
+	target.Config = input.Config
+	target.TitleTemplate = input.TitleTemplate
+	target.BodyTemplate = input.BodyTemplate
+}
+
+// notificationTemplateData is what a target template sees:
+// {{.Event}}, {{.Title}}, {{.Message}}, {{.Time}} and {{.Data.<key>}}.
+type notificationTemplateData struct {
+	Event   string
+	Title   string
+	Message string
+	Time    string
+	Data    map[string]interface{}
+}
+
+func parseNotificationTemplate(text string) (*template.Template, error) {
+	return template.New("notification").Option("missingkey=zero").Parse(text)
+}
+
+// render applies the target's templates. An empty template, or one that
+// fails at execution time, falls back to the event's own title/message so a
+// template mistake never swallows a notification.
+func (s *NotificationService) render(target *models.NotificationTarget, event models.NotificationEvent) notify.Message {
+	data := notificationTemplateData{
+		Event:   string(event.Type),
+		Title:   event.Title,
+		Message: event.Message,
+		Time:    event.OccurredAt.Local().Format("2006-01-02 15:04"),
+		Data:    event.Data,
+	}
+	return notify.Message{
+		Event:      string(event.Type),
+		Title:      s.renderTemplate(target, target.TitleTemplate, data, event.Title),
+		Body:       s.renderTemplate(target, target.BodyTemplate, data, event.Message),
+		Data:       event.Data,
+		OccurredAt: event.OccurredAt,
+	}
+}
+
+func (s *NotificationService) renderTemplate(target *models.NotificationTarget, text string, data notificationTemplateData, fallback string) string {
+	if strings.TrimSpace(text) == "" {
+		return fallback
+	}
+	tmpl, err := parseNotificationTemplate(text)
+	if err == nil {
+		var b strings.Builder
+		if err = tmpl.Execute(&b, data); err == nil {
+			// missingkey=zero still prints a missing map entry of interface
+			// type as "<no value>"; an absent field should render as nothing.
+			return strings.ReplaceAll(b.String(), "<no value>", "")
+		}
+	}
+	s.logger.Warn("Notification template failed, using the default text", "target_id", target.ID, "error", err)
+	return fallback
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/notify"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/retry"
+)
+
+type fakeNotificationTargetRepo struct {
+	mu      sync.Mutex
+	targets map[string]models.NotificationTarget
+}
+
+func newFakeNotificationTargetRepo() *fakeNotificationTargetRepo {
+	return &fakeNotificationTargetRepo{targets: map[string]models.NotificationTarget{}}
+}
+
+func (f *fakeNotificationTargetRepo) Create(ctx context.Context, t *models.NotificationTarget) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.targets[t.ID] = *t
+	return nil
+}
+func (f *fakeNotificationTargetRepo) GetByID(ctx context.Context, id string) (*models.NotificationTarget, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.targets[id]
+	if !ok {
+		return nil, repository.ErrNotificationTargetNotFound
+	}
+	return &t, nil
+}
+func (f *fakeNotificationTargetRepo) List(ctx context.Context) ([]models.NotificationTarget, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []models.NotificationTarget
+	for _, t := range f.targets {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+func (f *fakeNotificationTargetRepo) Update(ctx context.Context, t *models.NotificationTarget) error {
+	return f.Create(ctx, t)
+}
+func (f *fakeNotificationTargetRepo) Delete(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.targets[id]; !ok {
+		return repository.ErrNotificationTargetNotFound
+	}
+	delete(f.targets, id)
+	return nil
+}
+
+// fakeNotificationSender records sends and fails with err when set.
+type fakeNotificationSender struct {
+	mu    sync.Mutex
+	sent  []notify.Message
+	dests []notify.Destination
+	err   error
+}
+
+func (f *fakeNotificationSender) Send(ctx context.Context, dest notify.Destination, msg notify.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, msg)
+	f.dests = append(f.dests, dest)
+	return f.err
+}
+
+// recordingEventNotifier is the EventNotifier the producer tests (scanner,
+// poller, backup scheduler) hand in.
+type recordingEventNotifier struct {
+	mu     sync.Mutex
+	events []models.NotificationEvent
+}
+
+func (r *recordingEventNotifier) Notify(ctx context.Context, event models.NotificationEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingEventNotifier) all() []models.NotificationEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.NotificationEvent(nil), r.events...)
+}
+
+type queuedRetry struct {
+	taskID, taskType string
+	payload          interface{}
+}
+
+type fakeNotificationRetryQueue struct {
+	mu     sync.Mutex
+	queued []queuedRetry
+}
+
+func (f *fakeNotificationRetryQueue) QueueRetry(ctx context.Context, taskID, taskType string, payload interface{}, err error) error {
+	var retryErr *retry.RetryableError
+	if !errors.As(err, &retryErr) || !retryErr.Retryable {
+		return errors.New("error is not retryable")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queued = append(f.queued, queuedRetry{taskID, taskType, payload})
+	return nil
+}
+
+type notificationFixture struct {
+	service *NotificationService
+	repo    *fakeNotificationTargetRepo
+	secrets *fakeDVRSecrets
+	sender  *fakeNotificationSender
+	retries *fakeNotificationRetryQueue
+}
+
+func setupNotificationService(t *testing.T) *notificationFixture {
+	t.Helper()
+	f := &notificationFixture{
+		repo:    newFakeNotificationTargetRepo(),
+		secrets: newFakeDVRSecrets(),
+		sender:  &fakeNotificationSender{},
+		retries: &fakeNotificationRetryQueue{},
+	}
+	f.service = NewNotificationService(f.repo, f.secrets, f.sender, nil)
+	f.service.SetRetryQueue(f.retries)
+	return f
+}
+
+func discordInput(events ...models.NotificationEventType) NotificationTargetInput {
+	return NotificationTargetInput{
+		Name:    "Discord",
+		Type:    models.NotificationTargetDiscord,
+		Enabled: true,
+		Events:  events,
+		Secret:  "https://discord.test/api/webhooks/1/t",
+	}
+}
+
+func TestNotificationService_TargetCRUD(t *testing.T) {
+	f := setupNotificationService(t)
+	ctx := context.Background()
+
+	target, err := f.service.CreateTarget(ctx, discordInput(models.NotificationEventBackupFailed))
+	require.NoError(t, err)
+	assert.True(t, target.HasSecret)
+
+	t.Run("secret is stored outside the target row", func(t *testing.T) {
+		stored, err := f.secrets.Retrieve(ctx, notificationSecretKey(target.ID))
+		require.NoError(t, err)
+		assert.Equal(t, "https://discord.test/api/webhooks/1/t", stored)
+	})
+
+	t.Run("update with an empty secret keeps the stored one", func(t *testing.T) {
+		input := discordInput(models.NotificationEventBackupFailed, models.NotificationEventScanComplete)
+		input.Secret = ""
+		updated, err := f.service.UpdateTarget(ctx, target.ID, input)
+		require.NoError(t, err)
+		assert.True(t, updated.HasSecret)
+		assert.Len(t, updated.Events, 2)
+	})
+
+	t.Run("discord without a webhook url is rejected", func(t *testing.T) {
+		input := discordInput(models.NotificationEventBackupFailed)
+		input.Secret = ""
+		_, err := f.service.CreateTarget(ctx, input)
+		var valErr *models.ValidationError
+		require.ErrorAs(t, err, &valErr)
+		assert.Equal(t, "secret", valErr.Field)
+	})
+
+	t.Run("broken template is rejected", func(t *testing.T) {
+		input := discordInput(models.NotificationEventBackupFailed)
+		input.BodyTemplate = "{{.Message"
+		_, err := f.service.CreateTarget(ctx, input)
+		var valErr *models.ValidationError
+		require.ErrorAs(t, err, &valErr)
+		assert.Equal(t, "body_template", valErr.Field)
+	})
+
+	t.Run("delete removes the secret", func(t *testing.T) {
+		require.NoError(t, f.service.DeleteTarget(ctx, target.ID))
+		exists, _ := f.secrets.Exists(ctx, notificationSecretKey(target.ID))
+		assert.False(t, exists)
+	})
+}
+
+func TestNotificationService_Notify(t *testing.T) {
+	f := setupNotificationService(t)
+	ctx := context.Background()
+
+	_, err := f.service.CreateTarget(ctx, discordInput(models.NotificationEventSubtitleComplete))
+	require.NoError(t, err)
+	other := discordInput(models.NotificationEventBackupFailed)
+	other.Name = "Backups only"
+	_, err = f.service.CreateTarget(ctx, other)
+	require.NoError(t, err)
+	disabled := discordInput(models.NotificationEventSubtitleComplete)
+	disabled.Name = "Muted"
+	disabled.Enabled = false
+	_, err = f.service.CreateTarget(ctx, disabled)
+	require.NoError(t, err)
+
+	f.service.Notify(ctx, models.NotificationEvent{
+		Type:    models.NotificationEventSubtitleComplete,
+		Title:   "字幕已生成",
+		Message: "寄生上流",
+	})
+	f.service.inflight.Wait()
+
+	require.Len(t, f.sender.sent, 1, "only the enabled, subscribed target")
+	assert.Equal(t, "字幕已生成", f.sender.sent[0].Title)
+	assert.Equal(t, "https://discord.test/api/webhooks/1/t", f.sender.dests[0].Secret)
+	assert.NotZero(t, f.sender.sent[0].OccurredAt)
+	assert.Empty(t, f.retries.queued)
+}
+
+func TestNotificationService_Templates(t *testing.T) {
+	f := setupNotificationService(t)
+	ctx := context.Background()
+
+	input := discordInput(models.NotificationEventRequestStatus)
+	input.TitleTemplate = "[Vido] {{.Data.title}}"
+	input.BodyTemplate = "{{.Data.status}} at {{.Time}}{{.Data.missing}}"
+	_, err := f.service.CreateTarget(ctx, input)
+	require.NoError(t, err)
+
+	f.service.Notify(ctx, models.NotificationEvent{
+		Type:       models.NotificationEventRequestStatus,
+		Title:      "default",
+		Data:       map[string]interface{}{"title": "沙丘", "status": "completed"},
+		OccurredAt: time.Date(2026, 10, 16, 8, 30, 0, 0, time.Local),
+	})
+	f.service.inflight.Wait()
+
+	require.Len(t, f.sender.sent, 1)
+	assert.Equal(t, "[Vido] 沙丘", f.sender.sent[0].Title)
+	assert.Equal(t, "completed at 2026-10-16 08:30", f.sender.sent[0].Body, "a missing key renders empty")
+}
+
+func TestNotificationService_Retry(t *testing.T) {
+	f := setupNotificationService(t)
+	ctx := context.Background()
+
+	target, err := f.service.CreateTarget(ctx, discordInput(models.NotificationEventBackupFailed))
+	require.NoError(t, err)
+
+	t.Run("transient failure is queued", func(t *testing.T) {
+		f.sender.err = retry.NewRetryableError(notify.ErrCodeDeliveryFailed, "discord returned status 502", true, 502)
+		f.service.Notify(ctx, models.NotificationEvent{ID: "e1", Type: models.NotificationEventBackupFailed, Title: "備份失敗"})
+		f.service.inflight.Wait()
+
+		require.Len(t, f.retries.queued, 1)
+		assert.Equal(t, "notification:"+target.ID+":e1", f.retries.queued[0].taskID)
+		assert.Equal(t, retry.TaskTypeNotification, f.retries.queued[0].taskType)
+	})
+
+	t.Run("permanent failure is not", func(t *testing.T) {
+		f.sender.err = retry.NewRetryableError(notify.ErrCodeDeliveryFailed, "discord returned status 401", false, 401)
+		f.service.Notify(ctx, models.NotificationEvent{ID: "e2", Type: models.NotificationEventBackupFailed})
+		f.service.inflight.Wait()
+
+		assert.Len(t, f.retries.queued, 1)
+	})
+
+	t.Run("redelivery re-sends the stored event", func(t *testing.T) {
+		f.sender.err = nil
+		payload, err := json.Marshal(f.retries.queued[0].payload)
+		require.NoError(t, err)
+
+		require.NoError(t, f.service.RedeliverNotification(ctx, payload))
+		assert.Equal(t, "備份失敗", f.sender.sent[len(f.sender.sent)-1].Title)
+	})
+
+	t.Run("redelivery to a deleted target is dropped", func(t *testing.T) {
+		payload, err := json.Marshal(f.retries.queued[0].payload)
+		require.NoError(t, err)
+		require.NoError(t, f.service.DeleteTarget(ctx, target.ID))
+
+		sent := len(f.sender.sent)
+		assert.NoError(t, f.service.RedeliverNotification(ctx, payload))
+		assert.Len(t, f.sender.sent, sent)
+	})
+}
+
+func TestNotificationService_TestTarget(t *testing.T) {
+	f := setupNotificationService(t)
+	ctx := context.Background()
+
+	input := discordInput(models.NotificationEventBackupFailed)
+	input.Enabled = false
+	target, err := f.service.CreateTarget(ctx, input)
+	require.NoError(t, err)
+
+	require.NoError(t, f.service.TestTarget(ctx, target.ID), "a disabled target can still be tested")
+	require.Len(t, f.sender.sent, 1)
+	assert.Equal(t, string(models.NotificationEventTest), f.sender.sent[0].Event)
+
+	f.sender.err = errors.New("discord unreachable")
+	assert.ErrorContains(t, f.service.TestTarget(ctx, target.ID), "unreachable")
+	assert.Empty(t, f.retries.queued, "a test send is never retried")
+}
@@ -0,0 +1,727 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+)
+
+// ErrOrganizeAlreadyRunning is returned when an organize run is requested
+// while another is still moving files.
+var ErrOrganizeAlreadyRunning = errors.New("ORGANIZE_ALREADY_RUNNING: an organize run is already in progress")
+
+// ErrInvalidOrganizeMode is returned for a mode outside OrganizeMode.
+var ErrInvalidOrganizeMode = errors.New("ORGANIZE_INVALID_MODE: mode must be move, copy, hardlink or dry_run")
+
+// OrganizeMode selects how the organizer puts a file at its new name.
+type OrganizeMode string
+
+const (
+	// OrganizeModeMove renames in place (copy + delete across filesystems).
+	OrganizeModeMove OrganizeMode = "move"
+	// OrganizeModeCopy leaves the release where it is — e.g. still seeding —
+	// and points the library at a full copy.
+	OrganizeModeCopy OrganizeMode = "copy"
+	// OrganizeModeHardlink is the seeding-friendly copy that costs no space.
+	// Source and target must be on the same filesystem.
+	OrganizeModeHardlink OrganizeMode = "hardlink"
+	// OrganizeModeDryRun computes the plan and touches nothing.
+	OrganizeModeDryRun OrganizeMode = "dry_run"
+)
+
+// ParseOrganizeMode validates a mode from the API. Empty is a dry run: the
+// safe answer to a request that did not say what it wanted.
+func ParseOrganizeMode(s string) (OrganizeMode, error) {
+	switch mode := OrganizeMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return OrganizeModeDryRun, nil
+	case OrganizeModeMove, OrganizeModeCopy, OrganizeModeHardlink, OrganizeModeDryRun:
+		return mode, nil
+	default:
+		return "", ErrInvalidOrganizeMode
+	}
+}
+
+// OrganizeItemStatus is the outcome for one media file.
+type OrganizeItemStatus string
+
+const (
+	OrganizeStatusPlanned   OrganizeItemStatus = "planned"
+	OrganizeStatusOrganized OrganizeItemStatus = "organized"
+	OrganizeStatusUnchanged OrganizeItemStatus = "unchanged"
+	OrganizeStatusSkipped   OrganizeItemStatus = "skipped"
+	OrganizeStatusFailed    OrganizeItemStatus = "failed"
+)
+
+// OrganizeSidecar is one file that travels with a video.
+type OrganizeSidecar struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// OrganizeItem is the plan, and after a real run the outcome, for one file.
+type OrganizeItem struct {
+	MediaType string             `json:"media_type"` // "movie" or "episode"
+	MediaID   string             `json:"media_id"`
+	Title     string             `json:"title"`
+	Source    string             `json:"source"`
+	Target    string             `json:"target,omitempty"`
+	Sidecars  []OrganizeSidecar  `json:"sidecars,omitempty"`
+	Status    OrganizeItemStatus `json:"status"`
+	Reason    string             `json:"reason,omitempty"`
+}
+
+// OrganizeResult summarizes an organize run.
+type OrganizeResult struct {
+	LibraryID string         `json:"library_id"`
+	Mode      OrganizeMode   `json:"mode"`
+	Template  string         `json:"template"`
+	Items     []OrganizeItem `json:"items"`
+	Organized int            `json:"organized"`
+	Planned   int            `json:"planned"`
+	Unchanged int            `json:"unchanged"`
+	Skipped   int            `json:"skipped"`
+	Failed    int            `json:"failed"`
+}
+
+// sidecarExtensions are the files that belong to a video by sharing its base
+// name: subtitles, NFOs (including the localizer's .nfo.orig backup) and
+// Kodi/Jellyfin per-file artwork such as "<base>-poster.jpg".
+var sidecarExtensions = map[string]bool{
+	".srt": true, ".ass": true, ".ssa": true, ".vtt": true, ".sub": true, ".idx": true, ".sup": true,
+	".nfo": true, ".orig": true,
+	".jpg": true, ".jpeg": true, ".png": true, ".webp": true, ".tbn": true,
+}
+
+// movieFolderFiles are folder-level files that describe a movie rather than a
+// file: they follow the movie only when its folder holds nothing else.
+var movieFolderFiles = []string{
+	"movie.nfo", "movie.nfo.orig",
+	"poster.jpg", "poster.png", "folder.jpg", "folder.png",
+	"fanart.jpg", "fanart.png", "backdrop.jpg", "banner.jpg", "logo.png", "clearart.png",
+}
+
+// Narrow ports over the repositories the organizer reads and rewrites.
+type organizerLibraryStore interface {
+	GetByID(ctx context.Context, id string) (*models.MediaLibrary, error)
+	GetPathsByLibraryID(ctx context.Context, libraryID string) ([]models.MediaLibraryPath, error)
+}
+
+type organizerMovieStore interface {
+	FindAllWithFilePath(ctx context.Context) ([]models.Movie, error)
+	Update(ctx context.Context, movie *models.Movie) error
+}
+
+type organizerSeriesStore interface {
+	List(ctx context.Context, params repository.ListParams) ([]models.Series, *repository.PaginationResult, error)
+	Update(ctx context.Context, series *models.Series) error
+}
+
+type organizerEpisodeStore interface {
+	FindBySeriesID(ctx context.Context, seriesID string) ([]models.Episode, error)
+	Update(ctx context.Context, episode *models.Episode) error
+	UpdateEpisodeSubtitleStatus(ctx context.Context, episodeID string, status models.SubtitleStatus, path, language string) error
+}
+
+// organizerSourceLedger remembers the originals copy and hardlink modes leave
+// under the library root, so the scanner does not take them for new files.
+type organizerSourceLedger interface {
+	Record(ctx context.Context, source, target string) error
+	Forget(ctx context.Context, source string) error
+}
+
+// OrganizerService renames a library's files into its naming template.
+//
+// Only rows already matched to TMDb are touched: the template renders the
+// matched title, and an unmatched row's "title" is the messy release name the
+// organizer exists to replace. After a file lands, its row's file_path (and
+// subtitle_path, when the subtitle travelled with it) is rewritten so the
+// subtitle pipeline and the NFO localizer keep finding it. For series the
+// row's file_path — the identity the scanner resolves episodes through
+// (SeriesDirFor) — follows the episodes to their new folder.
+//
+// Copy and hardlink leave the original where it was, inside the library root
+// the scanner walks. Each original is written to the source ledger before it
+// is copied, and the scanner skips ledger paths — otherwise the next scan
+// would find a file no row claims and insert the movie a second time.
+type OrganizerService struct {
+	libraries organizerLibraryStore
+	movies    organizerMovieStore
+	series    organizerSeriesStore
+	episodes  organizerEpisodeStore
+	sources   organizerSourceLedger
+	changes   MediaChangeNotifier
+	logger    *slog.Logger
+
+	running sync.Mutex
+}
+
+// NewOrganizerService creates an OrganizerService.
+func NewOrganizerService(
+	libraries organizerLibraryStore,
+	movies organizerMovieStore,
+	series organizerSeriesStore,
+	episodes organizerEpisodeStore,
+	sources organizerSourceLedger,
+	logger *slog.Logger,
+) *OrganizerService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &OrganizerService{
+		libraries: libraries,
+		movies:    movies,
+		series:    series,
+		episodes:  episodes,
+		sources:   sources,
+		logger:    logger.With("service", "organizer"),
+	}
+}
+
+// SetChangeNotifier wires the optional media-server refresh: every file the
+// organizer puts in place, and in move mode every file it takes away, is
+// reported so Plex/Jellyfin/Emby rescan the folders involved.
+func (s *OrganizerService) SetChangeNotifier(n MediaChangeNotifier) {
+	s.changes = n
+}
+
+// organizeCandidate is one file the organizer may act on, with what it needs
+// to write the result back.
+type organizeCandidate struct {
+	item    OrganizeItem
+	root    string
+	movie   *models.Movie
+	episode *models.Episode
+	series  *models.Series
+	fields  organizeFields
+	matched bool
+}
+
+// Organize renders every file of the library into its template and, unless
+// mode is a dry run, puts it there.
+func (s *OrganizerService) Organize(ctx context.Context, libraryID string, mode OrganizeMode) (*OrganizeResult, error) {
+	if !s.running.TryLock() {
+		return nil, ErrOrganizeAlreadyRunning
+	}
+	defer s.running.Unlock()
+
+	lib, err := s.libraries.GetByID(ctx, libraryID)
+	if err != nil {
+		return nil, fmt.Errorf("get library: %w", err)
+	}
+	libPaths, err := s.libraries.GetPathsByLibraryID(ctx, libraryID)
+	if err != nil {
+		return nil, fmt.Errorf("get library paths: %w", err)
+	}
+	roots := make([]string, 0, len(libPaths))
+	for _, p := range libPaths {
+		roots = append(roots, filepath.Clean(p.Path))
+	}
+
+	source := lib.OrganizeTemplate
+	if strings.TrimSpace(source) == "" {
+		source = DefaultOrganizeTemplate(lib.ContentType)
+	}
+	tpl, err := parseOrganizeTemplate(source, lib.ContentType)
+	if err != nil {
+		return nil, &models.ValidationError{Field: "organize_template", Message: err.Error()}
+	}
+
+	var candidates []*organizeCandidate
+	if lib.ContentType == models.ContentTypeSeries {
+		candidates, err = s.seriesCandidates(ctx, lib.ID, roots)
+	} else {
+		candidates, err = s.movieCandidates(ctx, lib.ID, roots)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OrganizeResult{LibraryID: lib.ID, Mode: mode, Template: source}
+	s.plan(candidates, tpl)
+
+	for _, c := range candidates {
+		if c.item.Status == "" {
+			if mode == OrganizeModeDryRun {
+				c.item.Status = OrganizeStatusPlanned
+			} else {
+				s.apply(ctx, c, mode)
+			}
+		}
+	}
+	if mode != OrganizeModeDryRun && lib.ContentType == models.ContentTypeSeries {
+		s.relocateSeries(ctx, candidates)
+	}
+
+	for _, c := range candidates {
+		switch c.item.Status {
+		case OrganizeStatusOrganized:
+			result.Organized++
+		case OrganizeStatusPlanned:
+			result.Planned++
+		case OrganizeStatusUnchanged:
+			result.Unchanged++
+		case OrganizeStatusSkipped:
+			result.Skipped++
+		case OrganizeStatusFailed:
+			result.Failed++
+		}
+		result.Items = append(result.Items, c.item)
+	}
+
+	s.logger.Info("organize run completed",
+		"library_id", lib.ID,
+		"mode", mode,
+		"organized", result.Organized,
+		"planned", result.Planned,
+		"unchanged", result.Unchanged,
+		"skipped", result.Skipped,
+		"failed", result.Failed,
+	)
+	return result, nil
+}
+
+func (s *OrganizerService) movieCandidates(ctx context.Context, libraryID string, roots []string) ([]*organizeCandidate, error) {
+	movies, err := s.movies.FindAllWithFilePath(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list movies: %w", err)
+	}
+
+	var out []*organizeCandidate
+	for i := range movies {
+		m := &movies[i]
+		if m.IsRemoved || !m.FilePath.Valid || m.FilePath.String == "" {
+			continue
+		}
+		if m.LibraryID.Valid && m.LibraryID.String != libraryID {
+			continue
+		}
+		root, ok := owningRoot(roots, m.FilePath.String)
+		if !ok {
+			continue
+		}
+		out = append(out, &organizeCandidate{
+			item:    OrganizeItem{MediaType: "movie", MediaID: m.ID, Title: m.Title, Source: m.FilePath.String},
+			root:    root,
+			movie:   m,
+			matched: m.TMDbID.Valid && m.TMDbID.Int64 > 0,
+			fields: organizeFields{
+				Title:         m.Title,
+				OriginalTitle: m.OriginalTitle.String,
+				Year:          organizeYear(m.ReleaseDate),
+				Resolution:    resolutionLabel(m.VideoResolution.String),
+				TMDbID:        m.TMDbID.Int64,
+			},
+		})
+	}
+	return out, nil
+}
+
+func (s *OrganizerService) seriesCandidates(ctx context.Context, libraryID string, roots []string) ([]*organizeCandidate, error) {
+	// Same enumeration the scanner's file-size pass uses (aggregateSeriesFileSizes).
+	allSeries, _, err := s.series.List(ctx, repository.ListParams{Page: 1, PageSize: 10000})
+	if err != nil {
+		return nil, fmt.Errorf("list series: %w", err)
+	}
+
+	var out []*organizeCandidate
+	for i := range allSeries {
+		series := &allSeries[i]
+		if series.IsRemoved || (series.LibraryID.Valid && series.LibraryID.String != libraryID) {
+			continue
+		}
+		episodes, err := s.episodes.FindBySeriesID(ctx, series.ID)
+		if err != nil {
+			return nil, fmt.Errorf("list episodes for series %s: %w", series.ID, err)
+		}
+		for j := range episodes {
+			ep := &episodes[j]
+			if !ep.FilePath.Valid || ep.FilePath.String == "" {
+				continue
+			}
+			root, ok := owningRoot(roots, ep.FilePath.String)
+			if !ok {
+				continue
+			}
+			out = append(out, &organizeCandidate{
+				item: OrganizeItem{
+					MediaType: "episode",
+					MediaID:   ep.ID,
+					Title:     fmt.Sprintf("%s %s", series.Title, ep.GetSeasonEpisodeCode()),
+					Source:    ep.FilePath.String,
+				},
+				root:    root,
+				episode: ep,
+				series:  series,
+				matched: series.TMDbID.Valid && series.TMDbID.Int64 > 0,
+				fields: organizeFields{
+					Title:         series.Title,
+					OriginalTitle: series.OriginalTitle.String,
+					Year:          organizeYear(series.FirstAirDate),
+					Resolution:    resolutionLabel(series.VideoResolution.String),
+					TMDbID:        series.TMDbID.Int64,
+					Season:        ep.SeasonNumber,
+					Episode:       ep.EpisodeNumber,
+					EpisodeTitle:  ep.Title.String,
+				},
+			})
+		}
+	}
+	return out, nil
+}
+
+// plan fills in each candidate's target and sidecars, and settles the items
+// that need no filesystem work: unmatched, already in place, or colliding
+// with another file's target.
+func (s *OrganizerService) plan(candidates []*organizeCandidate, tpl *organizeTemplate) {
+	claimed := make(map[string]string)
+	for _, c := range candidates {
+		if !c.matched {
+			c.item.Status = OrganizeStatusSkipped
+			c.item.Reason = "not matched to TMDb"
+			continue
+		}
+		rel, err := tpl.render(c.fields)
+		if err != nil {
+			c.item.Status = OrganizeStatusFailed
+			c.item.Reason = err.Error()
+			continue
+		}
+		ext := filepath.Ext(c.item.Source)
+		target := filepath.Join(c.root, rel) + ext
+		c.item.Target = target
+
+		if filepath.Clean(target) == filepath.Clean(c.item.Source) {
+			c.item.Status = OrganizeStatusUnchanged
+			continue
+		}
+		key := strings.ToLower(target) // SMB shares are case-insensitive
+		if other, taken := claimed[key]; taken {
+			c.item.Status = OrganizeStatusSkipped
+			c.item.Reason = fmt.Sprintf("target also claimed by %s", other)
+			continue
+		}
+		claimed[key] = c.item.Source
+		c.item.Sidecars = planSidecars(c.item.Source, target, c.movie != nil)
+	}
+}
+
+// planSidecars finds the files travelling with source and names them after
+// target, keeping whatever followed the base name (".zh-Hant.srt",
+// "-poster.jpg").
+func planSidecars(source, target string, isMovie bool) []OrganizeSidecar {
+	srcDir := filepath.Dir(source)
+	srcBase := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	dstDir := filepath.Dir(target)
+	dstBase := strings.TrimSuffix(filepath.Base(target), filepath.Ext(target))
+
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return nil
+	}
+
+	var out []OrganizeSidecar
+	videos := 0
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			continue
+		}
+		if isVideoFile(name) {
+			videos++
+			continue
+		}
+		suffix, ok := strings.CutPrefix(name, srcBase)
+		if !ok || suffix == "" || (suffix[0] != '.' && suffix[0] != '-') {
+			continue
+		}
+		if !sidecarExtensions[strings.ToLower(filepath.Ext(name))] {
+			continue
+		}
+		out = append(out, OrganizeSidecar{
+			Source: filepath.Join(srcDir, name),
+			Target: filepath.Join(dstDir, dstBase+suffix),
+		})
+	}
+
+	if isMovie && videos == 1 && srcDir != dstDir {
+		for _, name := range movieFolderFiles {
+			p := filepath.Join(srcDir, name)
+			if _, err := os.Stat(p); err == nil {
+				out = append(out, OrganizeSidecar{Source: p, Target: filepath.Join(dstDir, name)})
+			}
+		}
+	}
+	return out
+}
+
+// apply performs one planned item and writes the new paths back.
+func (s *OrganizerService) apply(ctx context.Context, c *organizeCandidate, mode OrganizeMode) {
+	fail := func(reason string, err error) {
+		c.item.Status = OrganizeStatusFailed
+		c.item.Reason = fmt.Sprintf("%s: %v", reason, err)
+		s.logger.Warn("organize item failed", "source", c.item.Source, "target", c.item.Target, "reason", reason, "error", err)
+	}
+
+	if _, err := os.Lstat(c.item.Target); err == nil {
+		c.item.Status = OrganizeStatusSkipped
+		c.item.Reason = "target already exists"
+		return
+	}
+	// Recorded first: a left-behind original the ledger missed is a
+	// duplicate row on the next scan, so no ledger entry means no copy.
+	keepsSource := mode == OrganizeModeCopy || mode == OrganizeModeHardlink
+	if keepsSource {
+		if err := s.sources.Record(ctx, c.item.Source, c.item.Target); err != nil {
+			fail("record source", err)
+			return
+		}
+	}
+	forgetSource := func() {
+		if !keepsSource {
+			return
+		}
+		if err := s.sources.Forget(ctx, c.item.Source); err != nil {
+			s.logger.Warn("failed to forget organized source", "source", c.item.Source, "error", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.item.Target), 0o755); err != nil {
+		forgetSource()
+		fail("create target folder", err)
+		return
+	}
+	if err := transferFile(mode, c.item.Source, c.item.Target); err != nil {
+		forgetSource()
+		fail("transfer file", err)
+		return
+	}
+
+	var moved []OrganizeSidecar
+	for _, sc := range c.item.Sidecars {
+		if _, err := os.Lstat(sc.Target); err == nil {
+			s.logger.Warn("sidecar target exists — leaving sidecar in place", "source", sc.Source, "target", sc.Target)
+			continue
+		}
+		if err := transferFile(mode, sc.Source, sc.Target); err != nil {
+			s.logger.Warn("failed to transfer sidecar", "source", sc.Source, "target", sc.Target, "error", err)
+			continue
+		}
+		moved = append(moved, sc)
+	}
+	c.item.Sidecars = moved
+
+	if err := s.writeBack(ctx, c); err != nil {
+		// A moved file the database does not know about would read as
+		// "removed" on the next scan and lose its metadata; put it back.
+		if mode == OrganizeModeMove {
+			s.rollback(c)
+		}
+		forgetSource()
+		fail("update database", err)
+		return
+	}
+
+	if mode == OrganizeModeMove {
+		pruneEmptyDirs(filepath.Dir(c.item.Source), c.root)
+	}
+	s.notifyOrganized(c, mode)
+	c.item.Status = OrganizeStatusOrganized
+	s.logger.Info("organized file", "media_type", c.item.MediaType, "id", c.item.MediaID, "from", c.item.Source, "to", c.item.Target, "mode", mode)
+}
+
+func (s *OrganizerService) notifyOrganized(c *organizeCandidate, mode OrganizeMode) {
+	if s.changes == nil {
+		return
+	}
+	s.changes.NotifyChanged(c.item.Target)
+	for _, sc := range c.item.Sidecars {
+		s.changes.NotifyChanged(sc.Target)
+	}
+	if mode == OrganizeModeMove {
+		s.changes.NotifyChanged(c.item.Source)
+	}
+}
+
+// writeBack points the row at the new file and, when its subtitle moved
+// along, at the new subtitle.
+func (s *OrganizerService) writeBack(ctx context.Context, c *organizeCandidate) error {
+	newSubtitle := func(old models.NullString) (string, bool) {
+		if !old.Valid {
+			return "", false
+		}
+		for _, sc := range c.item.Sidecars {
+			if filepath.Clean(sc.Source) == filepath.Clean(old.String) {
+				return sc.Target, true
+			}
+		}
+		return "", false
+	}
+
+	if c.movie != nil {
+		c.movie.FilePath = models.NewNullString(c.item.Target)
+		if sub, ok := newSubtitle(c.movie.SubtitlePath); ok {
+			c.movie.SubtitlePath = models.NewNullString(sub)
+		}
+		return s.movies.Update(ctx, c.movie)
+	}
+
+	c.episode.FilePath = models.NewNullString(c.item.Target)
+	if err := s.episodes.Update(ctx, c.episode); err != nil {
+		return err
+	}
+	if sub, ok := newSubtitle(c.episode.SubtitlePath); ok {
+		if err := s.episodes.UpdateEpisodeSubtitleStatus(ctx, c.episode.ID, c.episode.SubtitleStatus, sub, c.episode.SubtitleLanguage.String); err != nil {
+			return err
+		}
+		c.episode.SubtitlePath = models.NewNullString(sub)
+	}
+	return nil
+}
+
+func (s *OrganizerService) rollback(c *organizeCandidate) {
+	if err := transferFile(OrganizeModeMove, c.item.Target, c.item.Source); err != nil {
+		s.logger.Error("failed to roll back organized file", "from", c.item.Target, "to", c.item.Source, "error", err)
+	}
+	for _, sc := range c.item.Sidecars {
+		if err := transferFile(OrganizeModeMove, sc.Target, sc.Source); err != nil {
+			s.logger.Error("failed to roll back sidecar", "from", sc.Target, "to", sc.Source, "error", err)
+		}
+	}
+	c.item.Sidecars = nil
+}
+
+// relocateSeries moves each series row's file_path to the folder its
+// episodes now share. A series whose episodes ended up in different folders
+// (a partial run) keeps its old path; the next run settles it.
+func (s *OrganizerService) relocateSeries(ctx context.Context, candidates []*organizeCandidate) {
+	type seriesDirs struct {
+		series *models.Series
+		dirs   map[string]bool
+		moved  bool
+	}
+	bySeries := make(map[string]*seriesDirs)
+	for _, c := range candidates {
+		if c.series == nil {
+			continue
+		}
+		sd := bySeries[c.series.ID]
+		if sd == nil {
+			sd = &seriesDirs{series: c.series, dirs: make(map[string]bool)}
+			bySeries[c.series.ID] = sd
+		}
+		current := c.item.Source
+		if c.item.Status == OrganizeStatusOrganized {
+			current = c.item.Target
+			sd.moved = true
+		}
+		sd.dirs[SeriesDirFor(current, c.root)] = true
+	}
+
+	for _, sd := range bySeries {
+		if !sd.moved || len(sd.dirs) != 1 {
+			continue
+		}
+		var dir string
+		for d := range sd.dirs {
+			dir = d
+		}
+		if sd.series.FilePath.Valid && filepath.Clean(sd.series.FilePath.String) == dir {
+			continue
+		}
+		sd.series.FilePath = models.NewNullString(dir)
+		if err := s.series.Update(ctx, sd.series); err != nil {
+			s.logger.Error("failed to update series folder", "series_id", sd.series.ID, "dir", dir, "error", err)
+		}
+	}
+}
+
+// owningRoot returns the deepest library path containing path.
+func owningRoot(roots []string, path string) (string, bool) {
+	best := ""
+	for _, r := range roots {
+		if pathUnderAny(path, []string{r}) && len(r) > len(best) {
+			best = r
+		}
+	}
+	return best, best != ""
+}
+
+// transferFile puts src at dst according to mode. dst must not exist.
+func transferFile(mode OrganizeMode, src, dst string) error {
+	switch mode {
+	case OrganizeModeMove:
+		err := os.Rename(src, dst)
+		if err == nil || !errors.Is(err, syscall.EXDEV) {
+			return err
+		}
+		// Library paths on different mounts: copy, then drop the original.
+		if err := copyFile(src, dst); err != nil {
+			return err
+		}
+		return os.Remove(src)
+	case OrganizeModeCopy:
+		return copyFile(src, dst)
+	case OrganizeModeHardlink:
+		return os.Link(src, dst)
+	default:
+		return ErrInvalidOrganizeMode
+	}
+}
+
+// copyFile copies src to a new dst, keeping its mode and mtime so the scanner
+// does not take the copy for a changed file.
+func copyFile(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(dst)
+		}
+	}()
+
+	if _, err = io.Copy(out, in); err != nil {
+		return err
+	}
+	if err = out.Sync(); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// pruneEmptyDirs removes dir and its parents while they are empty, stopping
+// at root: a release folder emptied by a move should not linger.
+func pruneEmptyDirs(dir, root string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && pathUnderAny(dir, []string{root}); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			return // not empty, or not ours to remove
+		}
+	}
+}
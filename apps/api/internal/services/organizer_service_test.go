@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/database/migrations"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+)
+
+type fakeOrganizerLibraries struct {
+	lib   *models.MediaLibrary
+	paths []models.MediaLibraryPath
+}
+
+func (f *fakeOrganizerLibraries) GetByID(_ context.Context, id string) (*models.MediaLibrary, error) {
+	if f.lib == nil || f.lib.ID != id {
+		return nil, repository.ErrLibraryNotFound
+	}
+	return f.lib, nil
+}
+
+func (f *fakeOrganizerLibraries) GetPathsByLibraryID(context.Context, string) ([]models.MediaLibraryPath, error) {
+	return f.paths, nil
+}
+
+type fakeOrganizerMovies struct {
+	movies    []models.Movie
+	updated   []models.Movie
+	updateErr error
+}
+
+func (f *fakeOrganizerMovies) FindAllWithFilePath(context.Context) ([]models.Movie, error) {
+	return append([]models.Movie(nil), f.movies...), nil
+}
+
+func (f *fakeOrganizerMovies) Update(_ context.Context, m *models.Movie) error {
+	if f.updateErr != nil {
+		return f.updateErr
+	}
+	f.updated = append(f.updated, *m)
+	return nil
+}
+
+type fakeOrganizerSeries struct {
+	series  []models.Series
+	updated []models.Series
+}
+
+func (f *fakeOrganizerSeries) List(context.Context, repository.ListParams) ([]models.Series, *repository.PaginationResult, error) {
+	return append([]models.Series(nil), f.series...), &repository.PaginationResult{}, nil
+}
+
+func (f *fakeOrganizerSeries) Update(_ context.Context, s *models.Series) error {
+	f.updated = append(f.updated, *s)
+	return nil
+}
+
+type fakeOrganizerEpisodes struct {
+	episodes  map[string][]models.Episode
+	updated   []models.Episode
+	subtitles map[string]string
+}
+
+func (f *fakeOrganizerEpisodes) FindBySeriesID(_ context.Context, seriesID string) ([]models.Episode, error) {
+	return append([]models.Episode(nil), f.episodes[seriesID]...), nil
+}
+
+func (f *fakeOrganizerEpisodes) Update(_ context.Context, e *models.Episode) error {
+	f.updated = append(f.updated, *e)
+	return nil
+}
+
+func (f *fakeOrganizerEpisodes) UpdateEpisodeSubtitleStatus(_ context.Context, id string, _ models.SubtitleStatus, path, _ string) error {
+	if f.subtitles == nil {
+		f.subtitles = make(map[string]string)
+	}
+	f.subtitles[id] = path
+	return nil
+}
+
+type fakeOrganizerSources struct {
+	recorded map[string]string
+}
+
+func (f *fakeOrganizerSources) Record(_ context.Context, source, target string) error {
+	if f.recorded == nil {
+		f.recorded = make(map[string]string)
+	}
+	f.recorded[source] = target
+	return nil
+}
+
+func (f *fakeOrganizerSources) Forget(_ context.Context, source string) error {
+	delete(f.recorded, source)
+	return nil
+}
+
+func writeOrganizerFile(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(filepath.Base(path)), 0o644))
+}
+
+// movieOrganizerFixture is a movie library with one matched release folder:
+// the video, a zh-Hant subtitle, a per-file NFO and folder-level artwork.
+func movieOrganizerFixture(t *testing.T) (*OrganizerService, *fakeOrganizerMovies, string, string) {
+	t.Helper()
+	root := t.TempDir()
+	release := filepath.Join(root, "Arrival.2016.1080p.BluRay.x264-SPARKS")
+	video := filepath.Join(release, "Arrival.2016.1080p.BluRay.x264-SPARKS.mkv")
+	writeOrganizerFile(t, video)
+	writeOrganizerFile(t, filepath.Join(release, "Arrival.2016.1080p.BluRay.x264-SPARKS.zh-Hant.srt"))
+	writeOrganizerFile(t, filepath.Join(release, "Arrival.2016.1080p.BluRay.x264-SPARKS.nfo"))
+	writeOrganizerFile(t, filepath.Join(release, "poster.jpg"))
+
+	movies := &fakeOrganizerMovies{movies: []models.Movie{{
+		ID:           "movie-1",
+		Title:        "異星入境",
+		ReleaseDate:  "2016-11-11",
+		TMDbID:       models.NewNullInt64(329865),
+		FilePath:     models.NewNullString(video),
+		SubtitlePath: models.NewNullString(filepath.Join(release, "Arrival.2016.1080p.BluRay.x264-SPARKS.zh-Hant.srt")),
+		LibraryID:    models.NewNullString("lib-movies"),
+	}}}
+	libs := &fakeOrganizerLibraries{
+		lib:   &models.MediaLibrary{ID: "lib-movies", Name: "電影", ContentType: models.ContentTypeMovie},
+		paths: []models.MediaLibraryPath{{LibraryID: "lib-movies", Path: root}},
+	}
+	svc := NewOrganizerService(libs, movies, &fakeOrganizerSeries{}, &fakeOrganizerEpisodes{}, &fakeOrganizerSources{}, nil)
+	return svc, movies, root, video
+}
+
+func TestOrganizer_MoveRenamesVideoAndSidecars(t *testing.T) {
+	svc, movies, root, video := movieOrganizerFixture(t)
+
+	result, err := svc.Organize(context.Background(), "lib-movies", OrganizeModeMove)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, OrganizeStatusOrganized, result.Items[0].Status, result.Items[0].Reason)
+	assert.Equal(t, 1, result.Organized)
+
+	dir := filepath.Join(root, "異星入境 (2016)")
+	target := filepath.Join(dir, "異星入境 (2016).mkv")
+	assert.FileExists(t, target)
+	assert.FileExists(t, filepath.Join(dir, "異星入境 (2016).zh-Hant.srt"))
+	assert.FileExists(t, filepath.Join(dir, "異星入境 (2016).nfo"), "the per-file NFO keeps resolving for the localizer")
+	assert.FileExists(t, filepath.Join(dir, "poster.jpg"), "folder artwork follows a movie that had the folder to itself")
+	assert.NoFileExists(t, video)
+	assert.NoDirExists(t, filepath.Dir(video), "the emptied release folder is pruned")
+
+	require.Len(t, movies.updated, 1)
+	assert.Equal(t, target, movies.updated[0].FilePath.String)
+	assert.Equal(t, filepath.Join(dir, "異星入境 (2016).zh-Hant.srt"), movies.updated[0].SubtitlePath.String,
+		"subtitle_path must follow the subtitle or the pipeline re-generates it")
+}
+
+// recordingNotifier collects NotifyChanged paths.
+type recordingNotifier struct{ paths []string }
+
+func (r *recordingNotifier) NotifyChanged(path string) { r.paths = append(r.paths, path) }
+
+func TestOrganizer_MoveNotifiesMediaServers(t *testing.T) {
+	svc, _, root, video := movieOrganizerFixture(t)
+	notifier := &recordingNotifier{}
+	svc.SetChangeNotifier(notifier)
+
+	_, err := svc.Organize(context.Background(), "lib-movies", OrganizeModeMove)
+	require.NoError(t, err)
+
+	dir := filepath.Join(root, "異星入境 (2016)")
+	assert.Contains(t, notifier.paths, filepath.Join(dir, "異星入境 (2016).mkv"))
+	assert.Contains(t, notifier.paths, filepath.Join(dir, "異星入境 (2016).zh-Hant.srt"))
+	assert.Contains(t, notifier.paths, video, "the folder the file left is rescanned too")
+
+	t.Run("dry run notifies nothing", func(t *testing.T) {
+		svc, _, _, _ := movieOrganizerFixture(t)
+		notifier := &recordingNotifier{}
+		svc.SetChangeNotifier(notifier)
+
+		_, err := svc.Organize(context.Background(), "lib-movies", OrganizeModeDryRun)
+		require.NoError(t, err)
+		assert.Empty(t, notifier.paths)
+	})
+}
+
+func TestOrganizer_DryRunTouchesNothing(t *testing.T) {
+	svc, movies, root, video := movieOrganizerFixture(t)
+
+	result, err := svc.Organize(context.Background(), "lib-movies", OrganizeModeDryRun)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+
+	item := result.Items[0]
+	assert.Equal(t, OrganizeStatusPlanned, item.Status)
+	assert.Equal(t, filepath.Join(root, "異星入境 (2016)", "異星入境 (2016).mkv"), item.Target)
+	assert.Len(t, item.Sidecars, 3)
+	assert.FileExists(t, video)
+	assert.Empty(t, movies.updated)
+}
+
+func TestOrganizer_HardlinkKeepsSource(t *testing.T) {
+	svc, movies, root, video := movieOrganizerFixture(t)
+
+	result, err := svc.Organize(context.Background(), "lib-movies", OrganizeModeHardlink)
+	require.NoError(t, err)
+	require.Equal(t, OrganizeStatusOrganized, result.Items[0].Status, result.Items[0].Reason)
+
+	target := filepath.Join(root, "異星入境 (2016)", "異星入境 (2016).mkv")
+	srcInfo, err := os.Stat(video)
+	require.NoError(t, err, "the release stays in place for seeding")
+	dstInfo, err := os.Stat(target)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(srcInfo, dstInfo), "a hardlink, not a copy")
+	assert.Equal(t, target, movies.updated[0].FilePath.String)
+	assert.Equal(t, target, svc.sources.(*fakeOrganizerSources).recorded[video], "the original is in the ledger")
+}
+
+// TestOrganizer_ScanAfterCopyKeepsOneRow — the copied-from original is still
+// under the library root; the next scan must not take it for a new movie.
+func TestOrganizer_ScanAfterCopyKeepsOneRow(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	runner, err := migrations.NewRunner(db)
+	require.NoError(t, err)
+	require.NoError(t, runner.RegisterAll(migrations.GetAll()))
+	require.NoError(t, runner.Up(context.Background()))
+
+	ctx := context.Background()
+	root := t.TempDir()
+	video := filepath.Join(root, "Arrival.2016.1080p.BluRay.x264-SPARKS", "Arrival.2016.1080p.BluRay.x264-SPARKS.mkv")
+	writeOrganizerFile(t, video)
+
+	movieRepo := repository.NewMovieRepository(db)
+	sources := repository.NewOrganizedSourceRepository(db)
+	require.NoError(t, movieRepo.Create(ctx, &models.Movie{
+		ID:          "movie-1",
+		Title:       "異星入境",
+		ReleaseDate: "2016-11-11",
+		TMDbID:      models.NewNullInt64(329865),
+		FilePath:    models.NewNullString(video),
+		LibraryID:   models.NewNullString("lib-movies"),
+		ParseStatus: models.ParseStatusSuccess,
+	}))
+	libs := &fakeOrganizerLibraries{
+		lib:   &models.MediaLibrary{ID: "lib-movies", Name: "電影", ContentType: models.ContentTypeMovie},
+		paths: []models.MediaLibraryPath{{LibraryID: "lib-movies", Path: root}},
+	}
+	organizer := NewOrganizerService(libs, movieRepo, &fakeOrganizerSeries{}, &fakeOrganizerEpisodes{}, sources, nil)
+	result, err := organizer.Organize(ctx, "lib-movies", OrganizeModeCopy)
+	require.NoError(t, err)
+	require.Equal(t, OrganizeStatusOrganized, result.Items[0].Status, result.Items[0].Reason)
+	require.FileExists(t, video, "copy mode leaves the original")
+
+	scanner := NewScannerService(movieRepo, repository.NewSeriesRepository(db), []string{root}, nil, nil)
+	scanner.SetOrganizedSources(sources)
+	_, err = scanner.StartScan(ctx)
+	require.NoError(t, err)
+
+	rows, err := movieRepo.FindAllWithFilePath(ctx)
+	require.NoError(t, err)
+	require.Len(t, rows, 1, "the original must not come back as a second movie")
+	assert.Equal(t, filepath.Join(root, "異星入境 (2016)", "異星入境 (2016).mkv"), rows[0].FilePath.String)
+}
+
+func TestOrganizer_SkipsUnmatchedAndExistingTargets(t *testing.T) {
+	svc, movies, root, _ := movieOrganizerFixture(t)
+	unmatched := filepath.Join(root, "Some.Home.Video.mkv")
+	writeOrganizerFile(t, unmatched)
+	movies.movies = append(movies.movies, models.Movie{ID: "movie-2", Title: "Some.Home.Video", FilePath: models.NewNullString(unmatched)})
+	writeOrganizerFile(t, filepath.Join(root, "異星入境 (2016)", "異星入境 (2016).mkv"))
+
+	result, err := svc.Organize(context.Background(), "lib-movies", OrganizeModeMove)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Skipped)
+	for _, item := range result.Items {
+		assert.Equal(t, OrganizeStatusSkipped, item.Status)
+	}
+	assert.FileExists(t, unmatched)
+	assert.Empty(t, movies.updated)
+}
+
+func TestOrganizer_RollsBackMoveWhenDatabaseWriteFails(t *testing.T) {
+	svc, movies, _, video := movieOrganizerFixture(t)
+	movies.updateErr = errors.New("database is locked")
+
+	result, err := svc.Organize(context.Background(), "lib-movies", OrganizeModeMove)
+	require.NoError(t, err)
+
+	assert.Equal(t, OrganizeStatusFailed, result.Items[0].Status)
+	assert.FileExists(t, video, "a file the database cannot point at must go back where it was")
+	assert.FileExists(t, filepath.Join(filepath.Dir(video), "poster.jpg"))
+}
+
+func TestOrganizer_SeriesMovesEpisodesAndSeriesFolder(t *testing.T) {
+	root := t.TempDir()
+	oldDir := filepath.Join(root, "Rick.and.Morty.S07.1080p.WEB")
+	ep1 := filepath.Join(oldDir, "Rick.and.Morty.S07E01.1080p.WEB.mkv")
+	ep2 := filepath.Join(oldDir, "Rick.and.Morty.S07E02.1080p.WEB.mkv")
+	sub1 := filepath.Join(oldDir, "Rick.and.Morty.S07E01.1080p.WEB.zh-Hant.srt")
+	writeOrganizerFile(t, ep1)
+	writeOrganizerFile(t, ep2)
+	writeOrganizerFile(t, sub1)
+
+	series := &fakeOrganizerSeries{series: []models.Series{{
+		ID:           "series-1",
+		Title:        "瑞克和莫蒂",
+		FirstAirDate: "2013-12-02",
+		TMDbID:       models.NewNullInt64(60625),
+		FilePath:     models.NewNullString(oldDir),
+		LibraryID:    models.NewNullString("lib-tv"),
+	}}}
+	episodes := &fakeOrganizerEpisodes{episodes: map[string][]models.Episode{"series-1": {
+		{ID: "ep-1", SeriesID: "series-1", SeasonNumber: 7, EpisodeNumber: 1, FilePath: models.NewNullString(ep1),
+			SubtitleStatus: models.SubtitleStatusFound, SubtitlePath: models.NewNullString(sub1)},
+		{ID: "ep-2", SeriesID: "series-1", SeasonNumber: 7, EpisodeNumber: 2, FilePath: models.NewNullString(ep2)},
+	}}}
+	libs := &fakeOrganizerLibraries{
+		lib:   &models.MediaLibrary{ID: "lib-tv", Name: "影集", ContentType: models.ContentTypeSeries},
+		paths: []models.MediaLibraryPath{{LibraryID: "lib-tv", Path: root}},
+	}
+	svc := NewOrganizerService(libs, &fakeOrganizerMovies{}, series, episodes, &fakeOrganizerSources{}, nil)
+
+	result, err := svc.Organize(context.Background(), "lib-tv", OrganizeModeMove)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Organized)
+
+	showDir := filepath.Join(root, "瑞克和莫蒂 (2013)")
+	target1 := filepath.Join(showDir, "Season 07", "瑞克和莫蒂 - S07E01.mkv")
+	assert.FileExists(t, target1)
+	assert.FileExists(t, filepath.Join(showDir, "Season 07", "瑞克和莫蒂 - S07E02.mkv"))
+	assert.Equal(t, filepath.Join(showDir, "Season 07", "瑞克和莫蒂 - S07E01.zh-Hant.srt"), episodes.subtitles["ep-1"])
+	require.Len(t, episodes.updated, 2)
+	assert.Equal(t, target1, episodes.updated[0].FilePath.String)
+
+	require.Len(t, series.updated, 1, "the series row must follow its episodes or the scanner mints a duplicate series")
+	assert.Equal(t, showDir, series.updated[0].FilePath.String)
+}
+
+func TestOrganizer_RejectsConcurrentRuns(t *testing.T) {
+	svc, _, _, _ := movieOrganizerFixture(t)
+	svc.running.Lock()
+	defer svc.running.Unlock()
+
+	_, err := svc.Organize(context.Background(), "lib-movies", OrganizeModeDryRun)
+	assert.ErrorIs(t, err, ErrOrganizeAlreadyRunning)
+}
+
+func TestParseOrganizeMode(t *testing.T) {
+	mode, err := ParseOrganizeMode("")
+	require.NoError(t, err)
+	assert.Equal(t, OrganizeModeDryRun, mode, "an unspecified mode must never move files")
+
+	mode, err = ParseOrganizeMode("Hardlink")
+	require.NoError(t, err)
+	assert.Equal(t, OrganizeModeHardlink, mode)
+
+	_, err = ParseOrganizeMode("symlink")
+	assert.ErrorIs(t, err, ErrInvalidOrganizeMode)
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vido/api/internal/models"
+)
+
+// Built-in organizer layouts, used when a library's organize_template is empty.
+// They follow the Plex/Jellyfin naming guides so an organized library is also
+// what those servers expect to read.
+const (
+	DefaultMovieOrganizeTemplate  = "{title} ({year})/{title} ({year})"
+	DefaultSeriesOrganizeTemplate = "{title} ({year})/Season {season:02}/{title} - S{season:02}E{episode:02}"
+)
+
+// DefaultOrganizeTemplate returns the built-in layout for a content type.
+func DefaultOrganizeTemplate(contentType models.MediaLibraryContentType) string {
+	if contentType == models.ContentTypeSeries {
+		return DefaultSeriesOrganizeTemplate
+	}
+	return DefaultMovieOrganizeTemplate
+}
+
+// organizeFields is everything a template placeholder can read. Titles come
+// from the TMDb-matched row, not the release name — fixing the release name
+// is the point.
+type organizeFields struct {
+	Title         string
+	OriginalTitle string
+	Year          string
+	Resolution    string
+	TMDbID        int64
+	Season        int
+	Episode       int
+	EpisodeTitle  string
+}
+
+// organizePlaceholders lists the placeholder names and whether each is only
+// meaningful in a series library.
+var organizePlaceholders = map[string]bool{
+	"title":          false,
+	"original_title": false,
+	"year":           false,
+	"resolution":     false,
+	"tmdb_id":        false,
+	"season":         true,
+	"episode":        true,
+	"episode_title":  true,
+}
+
+// organizeTemplate is a parsed template: literal text interleaved with
+// placeholders. `{season:02}` zero-pads a number to two digits.
+type organizeTemplate struct {
+	tokens []templateToken
+}
+
+type templateToken struct {
+	literal string
+	field   string
+	width   int
+}
+
+var placeholderPattern = regexp.MustCompile(`^([a-z_]+)(?::(0\d+))?$`)
+
+// ValidateOrganizeTemplate reports whether tpl parses and only uses
+// placeholders that exist for the library's content type. An empty template
+// is valid: it selects the built-in layout.
+func ValidateOrganizeTemplate(tpl string, contentType models.MediaLibraryContentType) error {
+	if strings.TrimSpace(tpl) == "" {
+		return nil
+	}
+	_, err := parseOrganizeTemplate(tpl, contentType)
+	return err
+}
+
+func parseOrganizeTemplate(tpl string, contentType models.MediaLibraryContentType) (*organizeTemplate, error) {
+	if strings.HasPrefix(tpl, "/") || filepath.IsAbs(tpl) {
+		return nil, fmt.Errorf("organize template must be relative to the library path")
+	}
+	for _, seg := range strings.Split(tpl, "/") {
+		if seg == ".." {
+			return nil, fmt.Errorf("organize template may not climb out of the library path")
+		}
+	}
+
+	out := &organizeTemplate{}
+	rest := tpl
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if closeIdx := strings.IndexByte(rest, '}'); closeIdx >= 0 && (open < 0 || closeIdx < open) {
+			return nil, fmt.Errorf("organize template has an unmatched '}'")
+		}
+		if open < 0 {
+			out.tokens = append(out.tokens, templateToken{literal: rest})
+			break
+		}
+		if open > 0 {
+			out.tokens = append(out.tokens, templateToken{literal: rest[:open]})
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("organize template has an unclosed '{'")
+		}
+		body := rest[open+1 : open+end]
+		m := placeholderPattern.FindStringSubmatch(body)
+		if m == nil {
+			return nil, fmt.Errorf("organize template placeholder {%s} is malformed", body)
+		}
+		seriesOnly, known := organizePlaceholders[m[1]]
+		if !known {
+			return nil, fmt.Errorf("organize template placeholder {%s} is unknown", m[1])
+		}
+		if seriesOnly && contentType != models.ContentTypeSeries {
+			return nil, fmt.Errorf("organize template placeholder {%s} only applies to series libraries", m[1])
+		}
+		tok := templateToken{field: m[1]}
+		if m[2] != "" {
+			tok.width, _ = strconv.Atoi(m[2])
+		}
+		out.tokens = append(out.tokens, tok)
+		rest = rest[open+end+1:]
+	}
+
+	if contentType == models.ContentTypeSeries && !out.uses("episode") {
+		// Without the episode number every file of a season renders to the
+		// same name and all but the first would collide.
+		return nil, fmt.Errorf("series organize template must include {episode}")
+	}
+	return out, nil
+}
+
+func (t *organizeTemplate) uses(field string) bool {
+	for _, tok := range t.tokens {
+		if tok.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// render produces the relative path (without extension) for f. Each path
+// segment is cleaned after substitution, so a missing year leaves
+// "Title" rather than "Title ()".
+func (t *organizeTemplate) render(f organizeFields) (string, error) {
+	var sb strings.Builder
+	for _, tok := range t.tokens {
+		if tok.field == "" {
+			sb.WriteString(tok.literal)
+			continue
+		}
+		sb.WriteString(f.value(tok.field, tok.width))
+	}
+
+	var segments []string
+	for _, seg := range strings.Split(sb.String(), "/") {
+		if seg = cleanPathSegment(seg); seg != "" {
+			segments = append(segments, seg)
+		}
+	}
+	if len(segments) == 0 {
+		return "", fmt.Errorf("organize template rendered an empty path")
+	}
+	return filepath.Join(segments...), nil
+}
+
+func (f organizeFields) value(field string, width int) string {
+	number := func(n int64) string {
+		if width > 0 {
+			return fmt.Sprintf("%0*d", width, n)
+		}
+		return strconv.FormatInt(n, 10)
+	}
+	switch field {
+	case "title":
+		return sanitizeFilenameValue(f.Title)
+	case "original_title":
+		return sanitizeFilenameValue(f.OriginalTitle)
+	case "year":
+		return f.Year
+	case "resolution":
+		return f.Resolution
+	case "tmdb_id":
+		if f.TMDbID <= 0 {
+			return ""
+		}
+		return number(f.TMDbID)
+	case "season":
+		return number(int64(f.Season))
+	case "episode":
+		return number(int64(f.Episode))
+	case "episode_title":
+		return sanitizeFilenameValue(f.EpisodeTitle)
+	default:
+		return ""
+	}
+}
+
+// filenameReplacer makes a metadata value safe as part of one path segment on
+// every filesystem a NAS share is likely to be read from (SMB clients reject
+// the Windows-reserved set even when the server is Linux).
+var filenameReplacer = strings.NewReplacer(
+	"/", "-", "\\", "-",
+	": ", " - ", ":", "-",
+	"<", "", ">", "", "\"", "", "|", "", "?", "", "*", "",
+)
+
+func sanitizeFilenameValue(s string) string {
+	s = filenameReplacer.Replace(s)
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+var (
+	emptyBracketsPattern = regexp.MustCompile(`\(\s*\)|\[\s*\]`)
+	multiSpacePattern    = regexp.MustCompile(`\s{2,}`)
+)
+
+// cleanPathSegment tidies what an empty placeholder leaves behind and strips
+// the characters a segment may not start or end with: leading dots would
+// hide it, trailing dots and spaces are dropped by SMB.
+func cleanPathSegment(seg string) string {
+	seg = emptyBracketsPattern.ReplaceAllString(seg, "")
+	seg = multiSpacePattern.ReplaceAllString(seg, " ")
+	seg = strings.Trim(seg, " -_.")
+	return seg
+}
+
+// organizeYear renders a TMDb date's year, or nothing when it has none.
+func organizeYear(date string) string {
+	if year := yearFromDate(date); year > 0 {
+		return strconv.Itoa(year)
+	}
+	return ""
+}
+
+// resolutionLabel turns ffprobe's "1920x1080" into "1080p". The width counts
+// too, so a scope crop (1920x800) is still labelled the 1080p it was released
+// as. Anything unrecognized renders empty rather than leaking a raw value into
+// a name.
+func resolutionLabel(res string) string {
+	w, h, ok := strings.Cut(strings.ToLower(res), "x")
+	if !ok {
+		return ""
+	}
+	width, errW := strconv.Atoi(w)
+	height, errH := strconv.Atoi(h)
+	if errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return ""
+	}
+	height = max(height, width*9/16)
+	switch {
+	case height >= 2000:
+		return "2160p"
+	case height >= 1000:
+		return "1080p"
+	case height >= 700:
+		return "720p"
+	case height >= 560:
+		return "576p"
+	default:
+		return "480p"
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/models"
+)
+
+func TestOrganizeTemplate_RendersSeriesLayout(t *testing.T) {
+	tpl, err := parseOrganizeTemplate(DefaultSeriesOrganizeTemplate, models.ContentTypeSeries)
+	require.NoError(t, err)
+
+	got, err := tpl.render(organizeFields{Title: "鵲刀門傳奇", Year: "2023", Season: 2, Episode: 5})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("鵲刀門傳奇 (2023)", "Season 02", "鵲刀門傳奇 - S02E05"), got)
+}
+
+func TestOrganizeTemplate_CleansWhatEmptyFieldsLeave(t *testing.T) {
+	tpl, err := parseOrganizeTemplate("{title} ({year}) [{resolution}]/{title} - {original_title}", models.ContentTypeMovie)
+	require.NoError(t, err)
+
+	got, err := tpl.render(organizeFields{Title: "Arrival"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("Arrival", "Arrival"), got)
+}
+
+func TestOrganizeTemplate_SanitizesTitles(t *testing.T) {
+	tpl, err := parseOrganizeTemplate(DefaultMovieOrganizeTemplate, models.ContentTypeMovie)
+	require.NoError(t, err)
+
+	got, err := tpl.render(organizeFields{Title: "Mission: Impossible / Face?Off", Year: "1996"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("Mission - Impossible - FaceOff (1996)", "Mission - Impossible - FaceOff (1996)"), got)
+}
+
+func TestOrganizeTemplate_TitleCannotEscapeRoot(t *testing.T) {
+	tpl, err := parseOrganizeTemplate("{title}/{title}", models.ContentTypeMovie)
+	require.NoError(t, err)
+
+	got, err := tpl.render(organizeFields{Title: "../../etc"})
+	require.NoError(t, err)
+	assert.NotContains(t, got, "..")
+}
+
+func TestValidateOrganizeTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		tpl         string
+		contentType models.MediaLibraryContentType
+		wantErr     bool
+	}{
+		{"empty selects built-in", "", models.ContentTypeMovie, false},
+		{"default movie", DefaultMovieOrganizeTemplate, models.ContentTypeMovie, false},
+		{"default series", DefaultSeriesOrganizeTemplate, models.ContentTypeSeries, false},
+		{"unknown placeholder", "{titel}", models.ContentTypeMovie, true},
+		{"series field in movie library", "{title} S{season:02}", models.ContentTypeMovie, true},
+		{"series without episode", "{title}/Season {season}", models.ContentTypeSeries, true},
+		{"unclosed brace", "{title", models.ContentTypeMovie, true},
+		{"stray closing brace", "title}", models.ContentTypeMovie, true},
+		{"bad width", "{season:2}", models.ContentTypeSeries, true},
+		{"absolute", "/media/{title}", models.ContentTypeMovie, true},
+		{"climbs out", "../{title}", models.ContentTypeMovie, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOrganizeTemplate(tt.tpl, tt.contentType)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestResolutionLabel(t *testing.T) {
+	assert.Equal(t, "2160p", resolutionLabel("3840x2160"))
+	assert.Equal(t, "1080p", resolutionLabel("1920x1080"))
+	assert.Equal(t, "1080p", resolutionLabel("1920x800")) // scope crop is still a 1080p release
+	assert.Equal(t, "720p", resolutionLabel("1280x720"))
+	assert.Equal(t, "", resolutionLabel("unknown"))
+}
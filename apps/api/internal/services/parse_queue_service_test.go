@@ -238,6 +238,9 @@ func (m *mockPQMovieRepo) CountMissingZhHantSubtitle(_ context.Context) (int, er
 func (m *mockPQMovieRepo) FindAllWithFilePath(_ context.Context) ([]models.Movie, error) {
 	return nil, nil
 }
+func (m *mockPQMovieRepo) FindAllWithFilePathByTMDbID(_ context.Context, _ int64) ([]models.Movie, error) {
+	return nil, nil
+}
 func (m *mockPQMovieRepo) GetStats(_ context.Context) (*repository.MediaStats, error) {
 	return &repository.MediaStats{}, nil
 }
@@ -453,6 +456,10 @@ func (m *mockPQEpisodeRepo) FindBySeriesSeasonEpisode(_ context.Context, seriesI
 	return nil, fmt.Errorf("episode S%02dE%02d for series %s: %w", season, episode, seriesID, repository.ErrEpisodeNotFound)
 }
 
+func (m *mockPQEpisodeRepo) FindByFilePath(context.Context, string) (*models.Episode, error) {
+	return nil, nil
+}
+
 func (m *mockPQEpisodeRepo) Update(_ context.Context, _ *models.Episode) error { return nil }
 func (m *mockPQEpisodeRepo) UpdateEpisodeSubtitleStatus(_ context.Context, _ string, _ models.SubtitleStatus, _, _ string) error {
 	return nil
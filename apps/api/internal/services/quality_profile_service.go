@@ -0,0 +1,115 @@
+// Package services — QualityProfileService.
+//
+// CRUD for Vido-native quality profiles. A profile only does something once a
+// media library points at it (UpdateLibraryRequest.QualityProfileID); the
+// QualityUpgradeService then judges that library's files against it.
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+)
+
+// QualityProfileInput is the POST/PUT body for a profile. PUT replaces every
+// field.
+type QualityProfileInput struct {
+	Name        string   `json:"name"`
+	Resolutions []string `json:"resolutions"`
+	Sources     []string `json:"sources"`
+	Codecs      []string `json:"codecs"`
+	Cutoff      string   `json:"cutoff"`
+}
+
+// QualityProfileServiceInterface defines the contract for quality profile
+// management (Rule 11 — handlers consume this from the services package).
+type QualityProfileServiceInterface interface {
+	ListProfiles(ctx context.Context) ([]models.QualityProfile, error)
+	GetProfile(ctx context.Context, id string) (*models.QualityProfile, error)
+	CreateProfile(ctx context.Context, input QualityProfileInput) (*models.QualityProfile, error)
+	UpdateProfile(ctx context.Context, id string, input QualityProfileInput) (*models.QualityProfile, error)
+	// DeleteProfile removes a profile; libraries using it fall back to none.
+	DeleteProfile(ctx context.Context, id string) error
+}
+
+// QualityProfileService implements QualityProfileServiceInterface.
+type QualityProfileService struct {
+	repo repository.QualityProfileRepositoryInterface
+}
+
+// Compile-time interface verification.
+var _ QualityProfileServiceInterface = (*QualityProfileService)(nil)
+
+// NewQualityProfileService creates a new QualityProfileService.
+func NewQualityProfileService(repo repository.QualityProfileRepositoryInterface) *QualityProfileService {
+	return &QualityProfileService{repo: repo}
+}
+
+func (s *QualityProfileService) ListProfiles(ctx context.Context) ([]models.QualityProfile, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *QualityProfileService) GetProfile(ctx context.Context, id string) (*models.QualityProfile, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *QualityProfileService) CreateProfile(ctx context.Context, input QualityProfileInput) (*models.QualityProfile, error) {
+	profile := &models.QualityProfile{}
+	applyQualityProfileInput(profile, input)
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *QualityProfileService) UpdateProfile(ctx context.Context, id string, input QualityProfileInput) (*models.QualityProfile, error) {
+	profile, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	applyQualityProfileInput(profile, input)
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *QualityProfileService) DeleteProfile(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// applyQualityProfileInput copies input onto profile, trimming values and
+// dropping blanks and case-insensitive repeats from the allow-lists.
+func applyQualityProfileInput(profile *models.QualityProfile, input QualityProfileInput) {
+	profile.Name = strings.TrimSpace(input.Name)
+	profile.Resolutions = cleanAllowList(input.Resolutions, strings.ToLower)
+	profile.Sources = cleanAllowList(input.Sources, nil)
+	profile.Codecs = cleanAllowList(input.Codecs, nil)
+	profile.Cutoff = strings.ToLower(strings.TrimSpace(input.Cutoff))
+}
+
+func cleanAllowList(values []string, normalize func(string) string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if normalize != nil {
+			v = normalize(v)
+		}
+		key := strings.ToLower(v)
+		if v == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, v)
+	}
+	return out
+}
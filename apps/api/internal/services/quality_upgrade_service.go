@@ -0,0 +1,680 @@
+// Package services — QualityUpgradeService.
+//
+// Once a file exists its request is done forever, whatever it looks like.
+// This evaluator closes that gap for libraries with a quality profile: on a
+// daily tick (and on demand) every owned movie and episode is judged against
+// its library's profile, using what the file name says (internal/parser) and
+// what ffprobe measured (MediaTechInfo) — measurement wins where both speak.
+//
+// A title that falls short is handed to the *arr that manages it as a search
+// (plugins.UpgradeSearcher), when that plugin is configured and healthy.
+// Otherwise it becomes an upgrade request on the built-in fulfilment path: a
+// pending request row marked with upgrade_cutoff, which the request poller
+// completes only once the file on disk meets the profile (UpgradeSatisfied).
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/parser"
+	"github.com/vido/api/internal/plugins"
+)
+
+// defaultQualityEvaluationInterval is the evaluator cadence. Upgrades are not
+// urgent, and a pass can probe files.
+const defaultQualityEvaluationInterval = 24 * time.Hour
+
+// upgradeSearchCooldown keeps the evaluator from asking an *arr to re-search
+// the same title every day; the *arr's own RSS sync keeps watching anyway.
+const upgradeSearchCooldown = 7 * 24 * time.Hour
+
+// Upgrade actions reported per title.
+const (
+	UpgradeActionDVRSearch        = "dvr_search"
+	UpgradeActionRequested        = "upgrade_requested"
+	UpgradeActionAlreadyRequested = "already_requested"
+	UpgradeActionCoolingDown      = "cooling_down"
+	UpgradeActionFailed           = "failed"
+)
+
+// QualityUpgradeServiceInterface is the on-demand face of the evaluator.
+type QualityUpgradeServiceInterface interface {
+	// Evaluate runs one pass over every library with a quality profile and
+	// acts on what falls short.
+	Evaluate(ctx context.Context) (*QualityEvaluation, error)
+}
+
+// QualityLibraryReader is the one media-library method the evaluator needs.
+type QualityLibraryReader interface {
+	GetAll(ctx context.Context) ([]models.MediaLibrary, error)
+}
+
+// QualityProfileReader resolves a library's profile.
+type QualityProfileReader interface {
+	GetByID(ctx context.Context, id string) (*models.QualityProfile, error)
+}
+
+// QualityMovieReader lists owned movies, all of them or every copy of one
+// TMDb id.
+type QualityMovieReader interface {
+	FindAllWithFilePath(ctx context.Context) ([]models.Movie, error)
+	FindAllWithFilePathByTMDbID(ctx context.Context, tmdbID int64) ([]models.Movie, error)
+}
+
+// QualitySeriesReader lists matched series and finds one by TMDb id.
+type QualitySeriesReader interface {
+	FindByParseStatus(ctx context.Context, status models.ParseStatus) ([]models.Series, error)
+	FindByTMDbID(ctx context.Context, tmdbID int64) (*models.Series, error)
+}
+
+// UpgradeRequestStore is the slice of the request repository used to raise
+// upgrade requests.
+type UpgradeRequestStore interface {
+	FindActiveByTMDbID(ctx context.Context, tmdbID int64, mediaType string) (*models.Request, error)
+	Create(ctx context.Context, request *models.Request) error
+}
+
+// upgradeDVRSource is the plugins.Manager surface the evaluator needs.
+// *plugins.Manager satisfies it.
+type upgradeDVRSource interface {
+	IsConfigured(ctx context.Context, name string) bool
+	Health(name string) plugins.PluginHealth
+	GetClient(ctx context.Context, name string) (plugins.DVRPlugin, error)
+}
+
+// QualityShortfall is one owned file that falls short of its profile.
+type QualityShortfall struct {
+	Path          string              `json:"path"`
+	SeasonNumber  int                 `json:"season_number,omitempty"`
+	EpisodeNumber int                 `json:"episode_number,omitempty"`
+	Quality       models.MediaQuality `json:"quality"`
+	Reasons       []string            `json:"reasons"`
+}
+
+// QualityUpgrade is one title with at least one file below its profile, and
+// what the evaluator did about it.
+type QualityUpgrade struct {
+	MediaType string             `json:"media_type"`
+	TMDbID    int64              `json:"tmdb_id"`
+	Title     string             `json:"title"`
+	LibraryID string             `json:"library_id"`
+	ProfileID string             `json:"profile_id"`
+	Cutoff    string             `json:"cutoff"`
+	Files     []QualityShortfall `json:"files"`
+	Action    string             `json:"action"`
+	RequestID string             `json:"request_id,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// QualityEvaluation is the result of one pass.
+type QualityEvaluation struct {
+	EvaluatedFiles int              `json:"evaluated_files"`
+	Upgrades       []QualityUpgrade `json:"upgrades"`
+	StartedAt      time.Time        `json:"started_at"`
+	FinishedAt     time.Time        `json:"finished_at"`
+}
+
+// probedQuality caches one ffprobe measurement against the file's identity.
+type probedQuality struct {
+	modTime time.Time
+	size    int64
+	tech    *MediaTechInfo
+}
+
+// QualityUpgradeService implements QualityUpgradeServiceInterface and the
+// poller's UpgradeChecker.
+type QualityUpgradeService struct {
+	libraries QualityLibraryReader
+	profiles  QualityProfileReader
+	movies    QualityMovieReader
+	series    QualitySeriesReader
+	episodes  EpisodeOwnershipReader
+	requests  UpgradeRequestStore
+	dvr       upgradeDVRSource // nil = every shortfall becomes a request
+	prober    TechProber       // nil = file names only for episodes
+	logger    *slog.Logger
+	interval  time.Duration
+	now       func() time.Time
+
+	mu         sync.Mutex // guards probed, searchedAt and running
+	probed     map[string]probedQuality
+	searchedAt map[string]time.Time
+	running    bool
+
+	stopMu  sync.Mutex
+	stopCh  chan struct{}
+	stopped bool
+}
+
+// Compile-time interface verification.
+var (
+	_ QualityUpgradeServiceInterface = (*QualityUpgradeService)(nil)
+	_ UpgradeChecker                 = (*QualityUpgradeService)(nil)
+)
+
+// ErrQualityEvaluationRunning is returned when a pass is requested while one
+// is already in progress.
+var ErrQualityEvaluationRunning = errors.New("quality evaluation already running")
+
+// NewQualityUpgradeService creates the evaluator.
+func NewQualityUpgradeService(
+	libraries QualityLibraryReader,
+	profiles QualityProfileReader,
+	movies QualityMovieReader,
+	series QualitySeriesReader,
+	episodes EpisodeOwnershipReader,
+	requests UpgradeRequestStore,
+	dvr upgradeDVRSource,
+	prober TechProber,
+	logger *slog.Logger,
+) *QualityUpgradeService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &QualityUpgradeService{
+		libraries:  libraries,
+		profiles:   profiles,
+		movies:     movies,
+		series:     series,
+		episodes:   episodes,
+		requests:   requests,
+		dvr:        dvr,
+		prober:     prober,
+		logger:     logger,
+		interval:   defaultQualityEvaluationInterval,
+		now:        time.Now,
+		probed:     map[string]probedQuality{},
+		searchedAt: map[string]time.Time{},
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start runs a pass now and then on every tick until ctx is cancelled or
+// Stop is called. It blocks; main.go runs it in a goroutine.
+func (s *QualityUpgradeService) Start(ctx context.Context) {
+	s.logger.Info("Quality upgrade evaluator started", "interval", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Evaluate(ctx); err != nil && !errors.Is(err, ErrQualityEvaluationRunning) {
+			s.logger.Error("Quality evaluation failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Quality upgrade evaluator stopped (context cancelled)")
+			return
+		case <-s.stopCh:
+			s.logger.Info("Quality upgrade evaluator stopped (stop signal)")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops the evaluator loop. Idempotent.
+func (s *QualityUpgradeService) Stop() {
+	s.stopMu.Lock()
+	defer s.stopMu.Unlock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.stopCh)
+	}
+}
+
+// Evaluate runs one pass. Libraries without a profile, and titles without a
+// TMDb id, are skipped; one title's failure never stops the pass.
+func (s *QualityUpgradeService) Evaluate(ctx context.Context) (*QualityEvaluation, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, ErrQualityEvaluationRunning
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	result := &QualityEvaluation{Upgrades: []QualityUpgrade{}, StartedAt: s.now()}
+	profiles, err := s.libraryProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		result.FinishedAt = s.now()
+		return result, nil
+	}
+
+	movieUpgrades, movieFiles, err := s.evaluateMovies(ctx, profiles)
+	if err != nil {
+		return nil, err
+	}
+	seriesUpgrades, episodeFiles, err := s.evaluateSeries(ctx, profiles)
+	if err != nil {
+		return nil, err
+	}
+	result.EvaluatedFiles = movieFiles + episodeFiles
+
+	for _, up := range append(movieUpgrades, seriesUpgrades...) {
+		s.act(ctx, &up)
+		result.Upgrades = append(result.Upgrades, up)
+	}
+	result.FinishedAt = s.now()
+	s.logger.Info("Quality evaluation finished",
+		"evaluated_files", result.EvaluatedFiles, "below_profile", len(result.Upgrades))
+	return result, nil
+}
+
+// libraryProfiles maps each library with a usable profile to it. A dangling
+// assignment is logged and skipped.
+func (s *QualityUpgradeService) libraryProfiles(ctx context.Context) (map[string]*models.QualityProfile, error) {
+	libs, err := s.libraries.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list libraries: %w", err)
+	}
+	out := map[string]*models.QualityProfile{}
+	for _, lib := range libs {
+		if !lib.QualityProfileID.Valid || lib.QualityProfileID.String == "" {
+			continue
+		}
+		profile, err := s.profiles.GetByID(ctx, lib.QualityProfileID.String)
+		if err != nil {
+			s.logger.Warn("Library quality profile unavailable", "library_id", lib.ID,
+				"profile_id", lib.QualityProfileID.String, "error", err)
+			continue
+		}
+		out[lib.ID] = profile
+	}
+	return out, nil
+}
+
+func (s *QualityUpgradeService) evaluateMovies(ctx context.Context, profiles map[string]*models.QualityProfile) ([]QualityUpgrade, int, error) {
+	movies, err := s.movies.FindAllWithFilePath(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list movies: %w", err)
+	}
+	// Copies of one title are judged together: a 2160p alongside the 720p
+	// means nothing is missing.
+	var order []int64
+	copies := map[int64][]models.Movie{}
+	for _, m := range movies {
+		if profiles[m.LibraryID.String] == nil || !m.TMDbID.Valid || m.TMDbID.Int64 <= 0 {
+			continue
+		}
+		if _, seen := copies[m.TMDbID.Int64]; !seen {
+			order = append(order, m.TMDbID.Int64)
+		}
+		copies[m.TMDbID.Int64] = append(copies[m.TMDbID.Int64], m)
+	}
+
+	var upgrades []QualityUpgrade
+	evaluated := 0
+	for _, tmdbID := range order {
+		evaluated += len(copies[tmdbID])
+		best := s.bestMovieCopy(ctx, copies[tmdbID], profiles)
+		if best == nil || len(best.reasons) == 0 {
+			continue
+		}
+		upgrades = append(upgrades, QualityUpgrade{
+			MediaType: models.RequestMediaTypeMovie,
+			TMDbID:    tmdbID,
+			Title:     best.movie.Title,
+			LibraryID: best.movie.LibraryID.String,
+			ProfileID: best.profile.ID,
+			Cutoff:    best.profile.Cutoff,
+			Files:     []QualityShortfall{{Path: best.movie.FilePath.String, Quality: best.quality, Reasons: best.reasons}},
+		})
+	}
+	return upgrades, evaluated, nil
+}
+
+// movieVerdict is one movie copy held to its library's profile.
+type movieVerdict struct {
+	movie   *models.Movie
+	profile *models.QualityProfile
+	quality models.MediaQuality
+	reasons []string
+}
+
+// bestMovieCopy judges every copy of a title against its own library's
+// profile and returns the best: one that meets its profile if any does, else
+// the one that falls short on the fewest counts, then the sharpest. nil
+// means no copy sits in a library with a profile.
+func (s *QualityUpgradeService) bestMovieCopy(ctx context.Context, copies []models.Movie, profiles map[string]*models.QualityProfile) *movieVerdict {
+	var best *movieVerdict
+	for i := range copies {
+		m := &copies[i]
+		profile := profiles[m.LibraryID.String]
+		if profile == nil {
+			continue
+		}
+		q := s.movieQuality(ctx, m)
+		v := &movieVerdict{movie: m, profile: profile, quality: q, reasons: profile.Evaluate(q)}
+		if len(v.reasons) == 0 {
+			return v
+		}
+		if best == nil || len(v.reasons) < len(best.reasons) ||
+			(len(v.reasons) == len(best.reasons) &&
+				models.QualityResolutionRank(q.Resolution) > models.QualityResolutionRank(best.quality.Resolution)) {
+			best = v
+		}
+	}
+	return best
+}
+
+func (s *QualityUpgradeService) evaluateSeries(ctx context.Context, profiles map[string]*models.QualityProfile) ([]QualityUpgrade, int, error) {
+	seriesList, err := s.series.FindByParseStatus(ctx, models.ParseStatusSuccess)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list series: %w", err)
+	}
+	var upgrades []QualityUpgrade
+	evaluated := 0
+	for i := range seriesList {
+		sr := &seriesList[i]
+		profile := profiles[sr.LibraryID.String]
+		if profile == nil || !sr.TMDbID.Valid || sr.TMDbID.Int64 <= 0 {
+			continue
+		}
+		shortfalls, n, err := s.episodeShortfalls(ctx, sr.ID, profile, nil)
+		if err != nil {
+			s.logger.Warn("Quality evaluation skipped series", "series_id", sr.ID, "error", err)
+			continue
+		}
+		evaluated += n
+		if len(shortfalls) == 0 {
+			continue
+		}
+		upgrades = append(upgrades, QualityUpgrade{
+			MediaType: models.RequestMediaTypeTV,
+			TMDbID:    sr.TMDbID.Int64,
+			Title:     sr.Title,
+			LibraryID: sr.LibraryID.String,
+			ProfileID: profile.ID,
+			Cutoff:    profile.Cutoff,
+			Files:     shortfalls,
+		})
+	}
+	return upgrades, evaluated, nil
+}
+
+// episodeShortfalls judges a series' episode files; only, when non-nil,
+// restricts it to the episodes it accepts. It returns the shortfalls and how many
+// files were judged.
+func (s *QualityUpgradeService) episodeShortfalls(ctx context.Context, seriesID string, profile *models.QualityProfile, only func(season, episode int) bool) ([]QualityShortfall, int, error) {
+	episodes, err := s.episodes.FindBySeriesID(ctx, seriesID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list episodes: %w", err)
+	}
+	var shortfalls []QualityShortfall
+	evaluated := 0
+	for _, ep := range episodes {
+		if !ep.FilePath.Valid || ep.FilePath.String == "" {
+			continue
+		}
+		if only != nil && !only(ep.SeasonNumber, ep.EpisodeNumber) {
+			continue
+		}
+		evaluated++
+		q := s.episodeQuality(ctx, ep.FilePath.String)
+		if reasons := profile.Evaluate(q); len(reasons) > 0 {
+			shortfalls = append(shortfalls, QualityShortfall{
+				Path:          ep.FilePath.String,
+				SeasonNumber:  ep.SeasonNumber,
+				EpisodeNumber: ep.EpisodeNumber,
+				Quality:       q,
+				Reasons:       reasons,
+			})
+		}
+	}
+	sort.Slice(shortfalls, func(i, j int) bool {
+		if shortfalls[i].SeasonNumber != shortfalls[j].SeasonNumber {
+			return shortfalls[i].SeasonNumber < shortfalls[j].SeasonNumber
+		}
+		return shortfalls[i].EpisodeNumber < shortfalls[j].EpisodeNumber
+	})
+	return shortfalls, evaluated, nil
+}
+
+// movieQuality uses the technical columns the scan stored, probing only when
+// it never recorded them.
+func (s *QualityUpgradeService) movieQuality(ctx context.Context, m *models.Movie) models.MediaQuality {
+	if m.VideoResolution.Valid && m.VideoResolution.String != "" {
+		return fileQuality(m.FilePath.String, &MediaTechInfo{
+			VideoResolution: m.VideoResolution.String,
+			VideoCodec:      m.VideoCodec.String,
+		})
+	}
+	return fileQuality(m.FilePath.String, s.probe(ctx, m.FilePath.String))
+}
+
+// episodeQuality reads the file name first. Episodes carry no technical
+// columns, so ffprobe is only asked when the name says nothing about the
+// resolution — the one field a cutoff needs.
+func (s *QualityUpgradeService) episodeQuality(ctx context.Context, path string) models.MediaQuality {
+	q := fileQuality(path, nil)
+	if q.Resolution != "" {
+		return q
+	}
+	return fileQuality(path, s.probe(ctx, path))
+}
+
+// probe measures a file, reusing the last measurement while the file's size
+// and modification time are unchanged. A failure yields nil.
+func (s *QualityUpgradeService) probe(ctx context.Context, path string) *MediaTechInfo {
+	if s.prober == nil {
+		return nil
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	s.mu.Lock()
+	cached, ok := s.probed[path]
+	s.mu.Unlock()
+	if ok && cached.size == stat.Size() && cached.modTime.Equal(stat.ModTime()) {
+		return cached.tech
+	}
+
+	info, err := s.prober.Probe(ctx, path)
+	if err != nil {
+		if !errors.Is(err, ErrFFprobeNotAvailable) {
+			s.logger.Warn("probe failed for quality evaluation", "path", path, "error", err)
+		}
+		return nil
+	}
+	s.mu.Lock()
+	s.probed[path] = probedQuality{modTime: stat.ModTime(), size: stat.Size(), tech: info}
+	s.mu.Unlock()
+	return info
+}
+
+// fileQuality combines the parser's reading of the file name with what
+// ffprobe measured; a measured value replaces the named one.
+func fileQuality(path string, tech *MediaTechInfo) models.MediaQuality {
+	base := filepath.Base(path)
+	q := models.MediaQuality{
+		Resolution: parser.DetectQuality(base),
+		Source:     parser.DetectSource(base),
+		Codec:      parser.DetectVideoCodec(base),
+	}
+	if tech != nil {
+		if r := resolutionLabel(tech.VideoResolution); r != "" {
+			q.Resolution = r
+		}
+		if c := parserCodecName(tech.VideoCodec); c != "" {
+			q.Codec = c
+		}
+	}
+	return q
+}
+
+// parserCodecName maps an ffprobe codec_name onto the parser's vocabulary,
+// which is the one profiles are written in. Unknown names map to "".
+func parserCodecName(codec string) string {
+	switch strings.ToLower(codec) {
+	case "h264":
+		return "x264"
+	case "hevc", "h265":
+		return "x265"
+	case "av1":
+		return "AV1"
+	}
+	return ""
+}
+
+// act hands one shortfall to its *arr, or raises an upgrade request when no
+// *arr can search for it.
+func (s *QualityUpgradeService) act(ctx context.Context, up *QualityUpgrade) {
+	key := up.MediaType + ":" + fmt.Sprint(up.TMDbID)
+
+	if searcher, ok := s.upgradeSearcher(ctx, up.MediaType); ok {
+		s.mu.Lock()
+		last, searched := s.searchedAt[key]
+		s.mu.Unlock()
+		if searched && s.now().Sub(last) < upgradeSearchCooldown {
+			up.Action = UpgradeActionCoolingDown
+			return
+		}
+		err := searcher.SearchUpgrade(ctx, up.TMDbID)
+		if err == nil {
+			s.mu.Lock()
+			s.searchedAt[key] = s.now()
+			s.mu.Unlock()
+			up.Action = UpgradeActionDVRSearch
+			s.logger.Info("Quality upgrade search sent to DVR",
+				"media_type", up.MediaType, "tmdb_id", up.TMDbID, "title", up.Title)
+			return
+		}
+		var pluginErr *plugins.PluginError
+		if !errors.As(err, &pluginErr) ||
+			(pluginErr.Code != plugins.ErrCodeNotFound && pluginErr.Code != plugins.ErrCodeTVDBNotFound) {
+			up.Action = UpgradeActionFailed
+			up.Error = err.Error()
+			s.logger.Warn("Quality upgrade search failed",
+				"media_type", up.MediaType, "tmdb_id", up.TMDbID, "error", err)
+			return
+		}
+		// The *arr does not manage this title — fall through to a request.
+	}
+
+	s.raiseRequest(ctx, up)
+}
+
+// upgradeSearcher returns the *arr for mediaType when it is configured,
+// healthy and able to re-search.
+func (s *QualityUpgradeService) upgradeSearcher(ctx context.Context, mediaType string) (plugins.UpgradeSearcher, bool) {
+	if s.dvr == nil {
+		return nil, false
+	}
+	name := dvrMoviePlugin
+	if mediaType == models.RequestMediaTypeTV {
+		name = dvrSeriesPlugin
+	}
+	if !s.dvr.IsConfigured(ctx, name) || s.dvr.Health(name).Status != plugins.HealthStatusHealthy {
+		return nil, false
+	}
+	client, err := s.dvr.GetClient(ctx, name)
+	if err != nil {
+		return nil, false
+	}
+	searcher, ok := client.(plugins.UpgradeSearcher)
+	return searcher, ok
+}
+
+// raiseRequest records the upgrade on the built-in path. A series request
+// selects exactly the episodes that fall short.
+func (s *QualityUpgradeService) raiseRequest(ctx context.Context, up *QualityUpgrade) {
+	if existing, err := s.requests.FindActiveByTMDbID(ctx, up.TMDbID, up.MediaType); err == nil {
+		up.Action = UpgradeActionAlreadyRequested
+		up.RequestID = existing.ID
+		return
+	}
+
+	request := &models.Request{
+		TMDbID:           up.TMDbID,
+		MediaType:        up.MediaType,
+		Title:            up.Title,
+		Status:           models.RequestStatusPending,
+		FulfilmentSource: models.NewNullString(models.RequestFulfilmentSourceBuiltin),
+		UpgradeCutoff:    models.NewNullString(up.Cutoff),
+	}
+	if up.MediaType == models.RequestMediaTypeTV {
+		sel := &RequestSelection{Episodes: map[int][]int{}}
+		for _, f := range up.Files {
+			sel.Episodes[f.SeasonNumber] = append(sel.Episodes[f.SeasonNumber], f.EpisodeNumber)
+		}
+		var err error
+		if request.Seasons, request.Episodes, err = selectionColumns(sel); err != nil {
+			up.Action = UpgradeActionFailed
+			up.Error = err.Error()
+			return
+		}
+	}
+	if err := s.requests.Create(ctx, request); err != nil {
+		up.Action = UpgradeActionFailed
+		up.Error = err.Error()
+		s.logger.Warn("Failed to raise upgrade request", "tmdb_id", up.TMDbID, "error", err)
+		return
+	}
+	up.Action = UpgradeActionRequested
+	up.RequestID = request.ID
+	s.logger.Info("Quality upgrade request raised",
+		"request_id", request.ID, "media_type", up.MediaType, "tmdb_id", up.TMDbID, "cutoff", up.Cutoff)
+}
+
+// UpgradeSatisfied reports whether an owned copy of an upgrade request's
+// title now meets its library's profile (the poller's UpgradeChecker). Every
+// copy is judged, so the request completes once the upgrade lands beside
+// the old file as well as over it. A title with no copy left in a library
+// with a profile is satisfied: nothing is left to hold it to. For a series
+// only the selected episodes are judged.
+func (s *QualityUpgradeService) UpgradeSatisfied(ctx context.Context, row models.Request) (bool, error) {
+	profiles, err := s.libraryProfiles(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if row.MediaType != models.RequestMediaTypeTV {
+		copies, err := s.movies.FindAllWithFilePathByTMDbID(ctx, row.TMDbID)
+		if err != nil {
+			return false, err
+		}
+		best := s.bestMovieCopy(ctx, copies, profiles)
+		return best == nil || len(best.reasons) == 0, nil
+	}
+
+	sr, err := s.series.FindByTMDbID(ctx, row.TMDbID)
+	if err != nil {
+		return false, err
+	}
+	profile := profiles[sr.LibraryID.String]
+	if profile == nil {
+		return true, nil
+	}
+	sel, err := parseSelectionColumns(row.Seasons, row.Episodes)
+	if err != nil {
+		return false, err
+	}
+	var only func(season, episode int) bool
+	if sel != nil {
+		only = sel.includes
+	}
+	shortfalls, _, err := s.episodeShortfalls(ctx, sr.ID, profile, only)
+	if err != nil {
+		return false, err
+	}
+	return len(shortfalls) == 0, nil
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/plugins"
+	"github.com/vido/api/internal/repository"
+)
+
+type qualityProfileMap map[string]*models.QualityProfile
+
+func (q qualityProfileMap) GetByID(ctx context.Context, id string) (*models.QualityProfile, error) {
+	if p, ok := q[id]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("quality profile with id %s: %w", id, repository.ErrQualityProfileNotFound)
+}
+
+type qualityLibraries []models.MediaLibrary
+
+func (q qualityLibraries) GetAll(ctx context.Context) ([]models.MediaLibrary, error) {
+	return q, nil
+}
+
+type qualityMovies []models.Movie
+
+func (q qualityMovies) FindAllWithFilePath(ctx context.Context) ([]models.Movie, error) {
+	return q, nil
+}
+
+func (q qualityMovies) FindAllWithFilePathByTMDbID(ctx context.Context, tmdbID int64) ([]models.Movie, error) {
+	var out []models.Movie
+	for i := range q {
+		if q[i].TMDbID.Int64 == tmdbID {
+			out = append(out, q[i])
+		}
+	}
+	return out, nil
+}
+
+type qualitySeries []models.Series
+
+func (q qualitySeries) FindByParseStatus(ctx context.Context, status models.ParseStatus) ([]models.Series, error) {
+	return q, nil
+}
+
+func (q qualitySeries) FindByTMDbID(ctx context.Context, tmdbID int64) (*models.Series, error) {
+	for i := range q {
+		if q[i].TMDbID.Int64 == tmdbID {
+			return &q[i], nil
+		}
+	}
+	return nil, fmt.Errorf("series with tmdb_id %d not found", tmdbID)
+}
+
+type fakeUpgradeRequests struct {
+	active  map[int64]*models.Request
+	created []*models.Request
+}
+
+func (f *fakeUpgradeRequests) FindActiveByTMDbID(ctx context.Context, tmdbID int64, mediaType string) (*models.Request, error) {
+	if r, ok := f.active[tmdbID]; ok {
+		return r, nil
+	}
+	return nil, repository.ErrRequestNotFound
+}
+
+func (f *fakeUpgradeRequests) Create(ctx context.Context, request *models.Request) error {
+	request.ID = fmt.Sprintf("req-%d", len(f.created)+1)
+	f.created = append(f.created, request)
+	return nil
+}
+
+// upgradeSearchClient is a DVR client that can re-search; the embedded
+// interface is never called.
+type upgradeSearchClient struct {
+	plugins.DVRPlugin
+	searched []int64
+	err      error
+}
+
+func (c *upgradeSearchClient) SearchUpgrade(ctx context.Context, tmdbID int64) error {
+	c.searched = append(c.searched, tmdbID)
+	return c.err
+}
+
+type fakeUpgradeDVR struct {
+	status string
+	client plugins.DVRPlugin
+}
+
+func (f *fakeUpgradeDVR) IsConfigured(ctx context.Context, name string) bool { return f.client != nil }
+
+func (f *fakeUpgradeDVR) Health(name string) plugins.PluginHealth {
+	return plugins.PluginHealth{Status: f.status}
+}
+
+func (f *fakeUpgradeDVR) GetClient(ctx context.Context, name string) (plugins.DVRPlugin, error) {
+	return f.client, nil
+}
+
+type qualityFixture struct {
+	svc      *QualityUpgradeService
+	requests *fakeUpgradeRequests
+}
+
+func newQualityFixture(movies qualityMovies, series qualitySeries, episodes calendarEpisodes, dvr upgradeDVRSource) *qualityFixture {
+	libs := qualityLibraries{
+		{ID: "movies", QualityProfileID: models.NewNullString("hd")},
+		{ID: "tv", QualityProfileID: models.NewNullString("hd")},
+		{ID: "anything"},
+	}
+	profiles := qualityProfileMap{"hd": {ID: "hd", Name: "HD", Cutoff: "1080p", Codecs: []string{"x264", "x265"}}}
+	requests := &fakeUpgradeRequests{active: map[int64]*models.Request{}}
+	svc := NewQualityUpgradeService(libs, profiles, movies, series, episodes, requests, dvr, nil, nil)
+	return &qualityFixture{svc: svc, requests: requests}
+}
+
+func qualityMovie(tmdbID int64, library, path, resolution string) models.Movie {
+	m := models.Movie{
+		ID:        fmt.Sprint("m", tmdbID),
+		Title:     fmt.Sprint("Movie ", tmdbID),
+		TMDbID:    models.NewNullInt64(tmdbID),
+		LibraryID: models.NewNullString(library),
+		FilePath:  models.NewNullString(path),
+	}
+	if resolution != "" {
+		m.VideoResolution = models.NewNullString(resolution)
+	}
+	return m
+}
+
+func TestQualityUpgrade_MovieBelowCutoffRaisesBuiltinRequest(t *testing.T) {
+	f := newQualityFixture(qualityMovies{
+		qualityMovie(550, "movies", "/m/Fight.Club.1999.720p.BluRay.x264.mkv", ""),
+		qualityMovie(551, "movies", "/m/Heat.1995.1080p.BluRay.x264.mkv", ""),
+		qualityMovie(552, "anything", "/m/Old.1960.480p.DVDRip.mkv", ""),
+	}, nil, calendarEpisodes{}, nil)
+
+	result, err := f.svc.Evaluate(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.EvaluatedFiles, "a library without a profile is never judged")
+	require.Len(t, result.Upgrades, 1)
+	up := result.Upgrades[0]
+	assert.Equal(t, int64(550), up.TMDbID)
+	assert.Equal(t, UpgradeActionRequested, up.Action)
+	assert.Equal(t, []string{models.QualityReasonBelowCutoff}, up.Files[0].Reasons)
+
+	require.Len(t, f.requests.created, 1)
+	req := f.requests.created[0]
+	assert.Equal(t, models.RequestStatusPending, req.Status)
+	assert.Equal(t, models.RequestFulfilmentSourceBuiltin, req.FulfilmentSource.String)
+	assert.Equal(t, "1080p", req.UpgradeCutoff.String)
+	assert.Equal(t, req.ID, up.RequestID)
+}
+
+func TestQualityUpgrade_MeasuredResolutionBeatsFileName(t *testing.T) {
+	// The name claims 1080p but the scan measured 1280x720.
+	f := newQualityFixture(qualityMovies{
+		qualityMovie(550, "movies", "/m/Fight.Club.1999.1080p.BluRay.x264.mkv", "1280x720"),
+	}, nil, calendarEpisodes{}, nil)
+
+	result, err := f.svc.Evaluate(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, result.Upgrades, 1)
+	assert.Equal(t, "720p", result.Upgrades[0].Files[0].Quality.Resolution)
+}
+
+func TestQualityUpgrade_DisallowedCodecFallsShort(t *testing.T) {
+	f := newQualityFixture(qualityMovies{
+		qualityMovie(550, "movies", "/m/Fight.Club.1999.1080p.WEB-DL.AV1.mkv", ""),
+	}, nil, calendarEpisodes{}, nil)
+
+	result, err := f.svc.Evaluate(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, result.Upgrades, 1)
+	assert.Equal(t, []string{models.QualityReasonCodecNotAllowed}, result.Upgrades[0].Files[0].Reasons)
+}
+
+func TestQualityUpgrade_ActiveRequestIsNotDuplicated(t *testing.T) {
+	f := newQualityFixture(qualityMovies{
+		qualityMovie(550, "movies", "/m/Fight.Club.1999.720p.mkv", ""),
+	}, nil, calendarEpisodes{}, nil)
+	f.requests.active[550] = &models.Request{ID: "existing"}
+
+	result, err := f.svc.Evaluate(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, result.Upgrades, 1)
+	assert.Equal(t, UpgradeActionAlreadyRequested, result.Upgrades[0].Action)
+	assert.Equal(t, "existing", result.Upgrades[0].RequestID)
+	assert.Empty(t, f.requests.created)
+}
+
+func TestQualityUpgrade_HealthyDVRSearchesWithCooldown(t *testing.T) {
+	client := &upgradeSearchClient{}
+	f := newQualityFixture(qualityMovies{
+		qualityMovie(550, "movies", "/m/Fight.Club.1999.720p.mkv", ""),
+	}, nil, calendarEpisodes{}, &fakeUpgradeDVR{status: plugins.HealthStatusHealthy, client: client})
+	now := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+	f.svc.now = func() time.Time { return now }
+
+	result, err := f.svc.Evaluate(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.Upgrades, 1)
+	assert.Equal(t, UpgradeActionDVRSearch, result.Upgrades[0].Action)
+	assert.Equal(t, []int64{550}, client.searched)
+	assert.Empty(t, f.requests.created, "a DVR search never raises a request")
+
+	now = now.Add(24 * time.Hour)
+	result, err = f.svc.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, UpgradeActionCoolingDown, result.Upgrades[0].Action)
+	assert.Len(t, client.searched, 1)
+
+	now = now.Add(upgradeSearchCooldown)
+	_, err = f.svc.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, client.searched, 2)
+}
+
+func TestQualityUpgrade_DVRFallbacks(t *testing.T) {
+	movies := qualityMovies{qualityMovie(550, "movies", "/m/Fight.Club.1999.720p.mkv", "")}
+
+	t.Run("unhealthy plugin raises a request", func(t *testing.T) {
+		client := &upgradeSearchClient{}
+		f := newQualityFixture(movies, nil, calendarEpisodes{}, &fakeUpgradeDVR{status: plugins.HealthStatusUnhealthy, client: client})
+		result, err := f.svc.Evaluate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, UpgradeActionRequested, result.Upgrades[0].Action)
+		assert.Empty(t, client.searched)
+	})
+
+	t.Run("title unknown to the DVR raises a request", func(t *testing.T) {
+		client := &upgradeSearchClient{err: &plugins.PluginError{Code: plugins.ErrCodeNotFound, Message: "not managed"}}
+		f := newQualityFixture(movies, nil, calendarEpisodes{}, &fakeUpgradeDVR{status: plugins.HealthStatusHealthy, client: client})
+		result, err := f.svc.Evaluate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, UpgradeActionRequested, result.Upgrades[0].Action)
+		assert.Len(t, f.requests.created, 1)
+	})
+
+	t.Run("other DVR errors are reported, not requested", func(t *testing.T) {
+		client := &upgradeSearchClient{err: errors.New("connection refused")}
+		f := newQualityFixture(movies, nil, calendarEpisodes{}, &fakeUpgradeDVR{status: plugins.HealthStatusHealthy, client: client})
+		result, err := f.svc.Evaluate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, UpgradeActionFailed, result.Upgrades[0].Action)
+		assert.Equal(t, "connection refused", result.Upgrades[0].Error)
+		assert.Empty(t, f.requests.created)
+	})
+}
+
+func TestQualityUpgrade_SeriesRequestSelectsShortEpisodes(t *testing.T) {
+	series := qualitySeries{{
+		ID: "s1", Title: "Show", TMDbID: models.NewNullInt64(1399), LibraryID: models.NewNullString("tv"),
+	}}
+	episodes := calendarEpisodes{"s1": {
+		{SeasonNumber: 1, EpisodeNumber: 1, FilePath: models.NewNullString("/tv/Show.S01E01.1080p.WEB-DL.x264.mkv")},
+		{SeasonNumber: 1, EpisodeNumber: 2, FilePath: models.NewNullString("/tv/Show.S01E02.720p.HDTV.x264.mkv")},
+		{SeasonNumber: 2, EpisodeNumber: 1, FilePath: models.NewNullString("/tv/Show.S02E01.480p.x264.mkv")},
+		{SeasonNumber: 2, EpisodeNumber: 2},
+	}}
+	f := newQualityFixture(nil, series, episodes, nil)
+
+	result, err := f.svc.Evaluate(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.EvaluatedFiles)
+	require.Len(t, result.Upgrades, 1)
+	require.Len(t, result.Upgrades[0].Files, 2)
+
+	require.Len(t, f.requests.created, 1)
+	req := f.requests.created[0]
+	assert.Equal(t, models.RequestMediaTypeTV, req.MediaType)
+	sel, err := parseSelectionColumns(req.Seasons, req.Episodes)
+	require.NoError(t, err)
+	require.NotNil(t, sel)
+	assert.Equal(t, map[int][]int{1: {2}, 2: {1}}, sel.Episodes)
+}
+
+func TestQualityUpgrade_UpgradeSatisfied(t *testing.T) {
+	movies := qualityMovies{qualityMovie(550, "movies", "/m/Fight.Club.1999.720p.mkv", "")}
+	series := qualitySeries{{
+		ID: "s1", Title: "Show", TMDbID: models.NewNullInt64(1399), LibraryID: models.NewNullString("tv"),
+	}}
+	episodes := calendarEpisodes{"s1": {
+		{SeasonNumber: 1, EpisodeNumber: 1, FilePath: models.NewNullString("/tv/Show.S01E01.720p.mkv")},
+		{SeasonNumber: 1, EpisodeNumber: 2, FilePath: models.NewNullString("/tv/Show.S01E02.1080p.mkv")},
+	}}
+	f := newQualityFixture(movies, series, episodes, nil)
+	ctx := context.Background()
+
+	ok, err := f.svc.UpgradeSatisfied(ctx, models.Request{MediaType: models.RequestMediaTypeMovie, TMDbID: 550})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	movies[0].FilePath = models.NewNullString("/m/Fight.Club.1999.2160p.mkv")
+	ok, err = f.svc.UpgradeSatisfied(ctx, models.Request{MediaType: models.RequestMediaTypeMovie, TMDbID: 550})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Only the selected episode is judged: S01E02 already meets the cutoff.
+	ok, err = f.svc.UpgradeSatisfied(ctx, models.Request{
+		MediaType: models.RequestMediaTypeTV, TMDbID: 1399, Episodes: models.NewNullString(`{"1":[2]}`),
+	})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = f.svc.UpgradeSatisfied(ctx, models.Request{
+		MediaType: models.RequestMediaTypeTV, TMDbID: 1399, Episodes: models.NewNullString(`{"1":[1]}`),
+	})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// TestQualityUpgrade_CopiesOfOneTitleAreJudgedTogether — the upgrade landed
+// beside the old file (a second library, or a template with a resolution):
+// the title is judged by its best copy, never by whichever row comes first.
+func TestQualityUpgrade_CopiesOfOneTitleAreJudgedTogether(t *testing.T) {
+	movies := qualityMovies{
+		qualityMovie(550, "movies", "/m/Fight.Club.1999.720p.BluRay.x264.mkv", ""),
+		qualityMovie(550, "movies", "/m/Fight.Club.1999.2160p.BluRay.x265.mkv", ""),
+		qualityMovie(551, "movies", "/m/Heat.1995.480p.DVDRip.x264.mkv", ""),
+		qualityMovie(551, "movies", "/m/Heat.1995.720p.BluRay.x264.mkv", ""),
+	}
+	f := newQualityFixture(movies, nil, calendarEpisodes{}, nil)
+	ctx := context.Background()
+
+	result, err := f.svc.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.EvaluatedFiles)
+	require.Len(t, result.Upgrades, 1, "the 2160p copy covers Fight Club")
+	up := result.Upgrades[0]
+	assert.Equal(t, int64(551), up.TMDbID)
+	require.Len(t, up.Files, 1)
+	assert.Equal(t, "/m/Heat.1995.720p.BluRay.x264.mkv", up.Files[0].Path, "reported against the best copy")
+
+	ok, err := f.svc.UpgradeSatisfied(ctx, models.Request{MediaType: models.RequestMediaTypeMovie, TMDbID: 550})
+	require.NoError(t, err)
+	assert.True(t, ok, "any copy meeting the profile satisfies the request")
+
+	ok, err = f.svc.UpgradeSatisfied(ctx, models.Request{MediaType: models.RequestMediaTypeMovie, TMDbID: 551})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestQualityUpgrade_EvaluateRejectsConcurrentPass(t *testing.T) {
+	f := newQualityFixture(nil, nil, calendarEpisodes{}, nil)
+	f.svc.running = true
+
+	_, err := f.svc.Evaluate(context.Background())
+	assert.ErrorIs(t, err, ErrQualityEvaluationRunning)
+}
@@ -53,6 +53,21 @@ type RequestSelection struct {
 	Episodes map[int][]int
 }
 
+// includes reports whether one episode falls inside the selection.
+func (s *RequestSelection) includes(season, episode int) bool {
+	for _, n := range s.Seasons {
+		if n == season {
+			return true
+		}
+	}
+	for _, n := range s.Episodes[season] {
+		if n == episode {
+			return true
+		}
+	}
+	return false
+}
+
 // Selection size ceilings (CR 13-2a M1). Every selected season costs one TMDB
 // season-details fetch on create AND one Sonarr episode-list fetch on
 // fulfilment, so an unbounded selection turns one HTTP request into dozens of
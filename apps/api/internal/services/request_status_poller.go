@@ -14,6 +14,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -125,6 +126,15 @@ type RequestStatusPoller struct {
 	// behavior for every row.
 	selectionOwnership SelectionOwnershipChecker
 
+	// upgrades refines rule 1 for quality-upgrade rows (upgrade_cutoff set):
+	// the title is owned by construction, so completion waits until the file
+	// meets its library's profile. Nil-safe: unwired = title-level behavior.
+	upgrades UpgradeChecker
+
+	// notifier receives every persisted status transition (user-008).
+	// Nil-safe: unwired = no outbound notifications.
+	notifier EventNotifier
+
 	// OnRequestCompleted is the 13-5 seam: invoked exactly once per request
 	// transition INTO completed (idempotence lives on the transition edge —
 	// a completed row leaves ListActive, so re-ticks cannot re-fire). Nil-safe;
@@ -282,6 +292,23 @@ func (p *RequestStatusPoller) SetSelectionOwnershipChecker(checker SelectionOwne
 	p.selectionOwnership = checker
 }
 
+// UpgradeChecker answers whether an upgrade request's owned copy now meets
+// its quality profile. *QualityUpgradeService implements it.
+type UpgradeChecker interface {
+	UpgradeSatisfied(ctx context.Context, row models.Request) (bool, error)
+}
+
+// SetUpgradeChecker wires the quality-upgrade refinement of rule 1 (main.go).
+func (p *RequestStatusPoller) SetUpgradeChecker(checker UpgradeChecker) {
+	p.upgrades = checker
+}
+
+// SetNotifier wires the outbound notification targets (main.go). Every
+// persisted transition becomes a request_status event; the targets filter.
+func (p *RequestStatusPoller) SetNotifier(n EventNotifier) {
+	p.notifier = n
+}
+
 // selectionSatisfied answers rule 1's follow-up question for a partial row:
 // are the SELECTED seasons/episodes all present locally? Whole-title rows and
 // an unwired checker answer true (the pre-13-2a behavior).
@@ -303,6 +330,21 @@ func (p *RequestStatusPoller) selectionSatisfied(ctx context.Context, row *model
 	return full
 }
 
+// upgradeSatisfied is rule 1's follow-up for an upgrade row, with the same
+// fail-soft direction as selectionSatisfied: a lookup failure holds the row.
+func (p *RequestStatusPoller) upgradeSatisfied(ctx context.Context, row *models.Request) bool {
+	if p.upgrades == nil || !row.UpgradeCutoff.Valid {
+		return true
+	}
+	ok, err := p.upgrades.UpgradeSatisfied(ctx, *row)
+	if err != nil {
+		p.logSourceErr("upgrade_quality", "Request status poll failed upgrade quality check", err)
+		return false
+	}
+	p.clearSourceErr("upgrade_quality")
+	return ok
+}
+
 // reconcile applies the AC #2 derivation table to one row IN ORDER and
 // returns its snapshot item (with the row mutated to its post-tick state).
 func (p *RequestStatusPoller) reconcile(
@@ -316,8 +358,10 @@ func (p *RequestStatusPoller) reconcile(
 	// Rule 1 — Vido's own library is the truth for 已入庫 (terminal). For a
 	// PARTIAL request the title-level answer is not enough (CR 13-2a H1): the
 	// show is already local by construction, so completion must be judged
-	// against the SELECTED seasons/episodes.
-	if ownedOK && ownedSet.has(row.MediaType, row.TMDbID) && p.selectionSatisfied(ctx, row) {
+	// against the SELECTED seasons/episodes — and for an upgrade row, against
+	// the quality the file has to reach.
+	if ownedOK && ownedSet.has(row.MediaType, row.TMDbID) && p.selectionSatisfied(ctx, row) &&
+		p.upgradeSatisfied(ctx, row) {
 		p.completeRequest(ctx, row)
 		return requestProgressItem{Request: *row}
 	}
@@ -342,6 +386,10 @@ func (p *RequestStatusPoller) reconcileExternal(
 	queues map[string]queueEvidence,
 	torrents map[string]qbittorrent.Torrent,
 ) requestProgressItem {
+	if row.FulfilmentSource.String == models.RequestFulfilmentSourceBuiltin {
+		return p.reconcileBuiltin(ctx, row, torrents)
+	}
+
 	pluginName := dvrMoviePlugin
 	if row.MediaType == models.RequestMediaTypeTV {
 		pluginName = dvrSeriesPlugin
@@ -384,6 +432,58 @@ func (p *RequestStatusPoller) reconcileExternal(
 	return requestProgressItem{Request: *row}
 }
 
+// reconcileBuiltin runs rules 2–3 for a built-in grab: qBittorrent is the
+// only queue there is. The torrent is found by its request tag, falling back
+// to the external id (the info-hash) for grabs made before tagging.
+func (p *RequestStatusPoller) reconcileBuiltin(
+	ctx context.Context,
+	row *models.Request,
+	torrents map[string]qbittorrent.Torrent,
+) requestProgressItem {
+	if torrents == nil {
+		// Fail-soft, as for a missing *arr queue.
+		return requestProgressItem{Request: *row}
+	}
+
+	torrent, found := builtinTorrent(row, torrents)
+	if !found {
+		// Gone from qBittorrent: removed after finishing, or by hand. Either
+		// way the library scan decides; hold in the import window.
+		p.enterImportWindow(ctx, row.ID)
+		return requestProgressItem{Request: *row}
+	}
+
+	switch mapTorrentToQueueState(torrent.Status) {
+	case queueStateFailed:
+		p.persistStatus(ctx, row, models.RequestStatusFailed, "下載發生錯誤，請重試或檢查下載器")
+		delete(p.inImportWindow, row.ID)
+		return requestProgressItem{Request: *row}
+	case queueStateImportWindow:
+		p.enterImportWindow(ctx, row.ID)
+		return requestProgressItem{Request: *row}
+	default:
+		if row.Status != models.RequestStatusDownloading {
+			p.persistStatus(ctx, row, models.RequestStatusDownloading, "")
+		}
+		delete(p.inImportWindow, row.ID)
+		progress := torrent.Progress
+		return requestProgressItem{Request: *row, Progress: &progress}
+	}
+}
+
+// builtinTorrent finds a built-in grab's torrent: the one tagged with the
+// request, else the one whose hash is the external id.
+func builtinTorrent(row *models.Request, torrents map[string]qbittorrent.Torrent) (qbittorrent.Torrent, bool) {
+	tag := RequestTorrentTag(row.ID)
+	for _, t := range torrents {
+		if slices.Contains(t.Tags, tag) {
+			return t, true
+		}
+	}
+	t, found := torrents[strings.ToLower(row.ExternalID.String)]
+	return t, found
+}
+
 // reconcileQueued runs rule 2 for a row with a live queue record: derive
 // downloading/failed/import-window + the ephemeral progress, refined by the
 // joined qBT torrent when the hash matches.
@@ -454,6 +554,7 @@ func (p *RequestStatusPoller) persistStatus(ctx context.Context, row *models.Req
 			"request_id", row.ID, "from", row.Status, "to", status, "error", err)
 		return false
 	}
+	previous := row.Status
 	row.Status = status
 	row.UpdatedAt = updatedAt
 	if errMsg == "" {
@@ -461,9 +562,50 @@ func (p *RequestStatusPoller) persistStatus(ctx context.Context, row *models.Req
 	} else {
 		row.ErrorMessage = models.NewNullString(errMsg)
 	}
+	if previous != status {
+		p.notifyStatus(ctx, *row, previous)
+	}
 	return true
 }
 
+// requestStatusLabels are the zh-TW words the request page already uses.
+var requestStatusLabels = map[string]string{
+	models.RequestStatusPending:     "等待中",
+	models.RequestStatusSearching:   "搜尋中",
+	models.RequestStatusDownloading: "下載中",
+	models.RequestStatusCompleted:   "已入庫",
+	models.RequestStatusFailed:      "失敗",
+}
+
+// notifyStatus hands one persisted transition to the notifier, if wired.
+func (p *RequestStatusPoller) notifyStatus(ctx context.Context, row models.Request, previous string) {
+	if p.notifier == nil {
+		return
+	}
+	label := requestStatusLabels[row.Status]
+	if label == "" {
+		label = row.Status
+	}
+	message := row.Title + " → " + label
+	if row.ErrorMessage.Valid {
+		message += "：" + row.ErrorMessage.String
+	}
+	p.notifier.Notify(ctx, models.NotificationEvent{
+		Type:    models.NotificationEventRequestStatus,
+		Title:   "媒體請求狀態更新",
+		Message: message,
+		Data: map[string]interface{}{
+			"request_id":      row.ID,
+			"title":           row.Title,
+			"tmdb_id":         row.TMDbID,
+			"media_type":      row.MediaType,
+			"status":          row.Status,
+			"previous_status": previous,
+			"error":           row.ErrorMessage.String,
+		},
+	})
+}
+
 // enterImportWindow marks a row as waiting for the *arr import and triggers
 // the debounced library scan on the ENTRY edge only.
 func (p *RequestStatusPoller) enterImportWindow(ctx context.Context, id string) {
@@ -794,3 +794,160 @@ func TestPoller_Rule1_WholeTitleRowSkipsTheSelectionCheck(t *testing.T) {
 	require.Len(t, updates, 1)
 	assert.Equal(t, models.RequestStatusCompleted, updates[0].status)
 }
+
+func TestPoller_NotifiesPersistedTransitionsOnly(t *testing.T) {
+	env := newPollerTestEnv(t)
+	notifier := &recordingEventNotifier{}
+	env.poller.SetNotifier(notifier)
+	env.repo.rows = []models.Request{activeRow("r1", 550, models.RequestMediaTypeMovie, models.RequestStatusSearching, "42")}
+	env.queues.plugins["radarr"] = queueItems(plugins.QueueItem{
+		ExternalID: 42, Title: "Fight Club", Status: "downloading", Size: 100, SizeLeft: 50,
+	})
+
+	env.poller.tick(context.Background())
+	env.poller.tick(context.Background())
+
+	events := notifier.all()
+	require.Len(t, events, 1, "holding downloading on the second tick is not a transition")
+	assert.Equal(t, models.NotificationEventRequestStatus, events[0].Type)
+	assert.Equal(t, models.RequestStatusDownloading, events[0].Data["status"])
+	assert.Equal(t, models.RequestStatusSearching, events[0].Data["previous_status"])
+	assert.Equal(t, "t-r1 → 下載中", events[0].Message)
+}
+
+type stubUpgradeChecker struct {
+	satisfied bool
+	err       error
+	calls     int
+}
+
+func (s *stubUpgradeChecker) UpgradeSatisfied(_ context.Context, _ models.Request) (bool, error) {
+	s.calls++
+	return s.satisfied, s.err
+}
+
+func upgradeRow(id string, tmdbID int64) models.Request {
+	row := activeRow(id, tmdbID, models.RequestMediaTypeMovie, models.RequestStatusPending, "")
+	row.FulfilmentSource = models.NewNullString(models.RequestFulfilmentSourceBuiltin)
+	row.UpgradeCutoff = models.NewNullString("1080p")
+	return row
+}
+
+func TestPoller_Rule1_UpgradeRowHeldUntilQualityMeetsProfile(t *testing.T) {
+	// The title is owned from the start — that is why it needs an upgrade —
+	// so ownership alone must not complete the row.
+	env := newPollerTestEnv(t)
+	env.repo.rows = []models.Request{upgradeRow("r1", 550)}
+	env.owned.set(models.RequestMediaTypeMovie, 550)
+	checker := &stubUpgradeChecker{satisfied: false}
+	env.poller.SetUpgradeChecker(checker)
+
+	env.poller.tick(context.Background())
+
+	assert.Equal(t, 1, checker.calls)
+	for _, u := range env.repo.updates() {
+		assert.NotEqual(t, models.RequestStatusCompleted, u.status)
+	}
+
+	checker.satisfied = true
+	env.poller.tick(context.Background())
+
+	updates := env.repo.updates()
+	require.NotEmpty(t, updates)
+	assert.Equal(t, models.RequestStatusCompleted, updates[len(updates)-1].status)
+}
+
+func TestPoller_Rule1_UpgradeCheckFailureHolds(t *testing.T) {
+	env := newPollerTestEnv(t)
+	env.repo.rows = []models.Request{upgradeRow("r1", 550)}
+	env.owned.set(models.RequestMediaTypeMovie, 550)
+	env.poller.SetUpgradeChecker(&stubUpgradeChecker{err: errors.New("probe down")})
+
+	env.poller.tick(context.Background())
+
+	for _, u := range env.repo.updates() {
+		assert.NotEqual(t, models.RequestStatusCompleted, u.status)
+	}
+}
+
+func builtinRow(id string, tmdbID int64, status, hash string) models.Request {
+	row := activeRow(id, tmdbID, models.RequestMediaTypeMovie, status, hash)
+	row.FulfilmentSource = models.NewNullString(models.RequestFulfilmentSourceBuiltin)
+	return row
+}
+
+func TestPoller_BuiltinRowFollowsTorrentByHash(t *testing.T) {
+	env := newPollerTestEnv(t)
+	env.repo.rows = []models.Request{builtinRow("r1", 550, models.RequestStatusPending, "abcdef")}
+	env.torrents.torrents = []qbittorrent.Torrent{{Hash: "ABCDEF", Progress: 0.25, Status: qbittorrent.StatusDownloading}}
+	env.queues.plugins["radarr"] = queueItems() // *arr queue evidence must not be consulted
+
+	env.poller.tick(context.Background())
+
+	updates := env.repo.updates()
+	require.Len(t, updates, 1)
+	assert.Equal(t, models.RequestStatusDownloading, updates[0].status)
+	events := env.sink.all()
+	require.NotEmpty(t, events)
+	items := events[len(events)-1].Data.([]requestProgressItem)
+	require.NotNil(t, items[0].Progress)
+	assert.InDelta(t, 0.25, *items[0].Progress, 0.001)
+}
+
+func TestPoller_BuiltinRowFollowsTorrentByRequestTag(t *testing.T) {
+	env := newPollerTestEnv(t)
+	env.repo.rows = []models.Request{builtinRow("r1", 550, models.RequestStatusDownloading, "abcdef")}
+	// qBittorrent reports a different hash than the one recorded (a hybrid
+	// v1/v2 torrent); the tag still ties it to the request.
+	env.torrents.torrents = []qbittorrent.Torrent{
+		{Hash: "abcdef", Progress: 1, Status: qbittorrent.StatusError},
+		{Hash: "123456", Progress: 0.5, Status: qbittorrent.StatusDownloading, Tags: []string{"4k", RequestTorrentTag("r1")}},
+	}
+
+	env.poller.tick(context.Background())
+
+	assert.Empty(t, env.repo.updates(), "the tagged torrent is still downloading")
+	events := env.sink.all()
+	require.NotEmpty(t, events)
+	items := events[len(events)-1].Data.([]requestProgressItem)
+	require.NotNil(t, items[0].Progress)
+	assert.InDelta(t, 0.5, *items[0].Progress, 0.001)
+}
+
+func TestPoller_BuiltinRowErroredTorrentBecomesFailed(t *testing.T) {
+	env := newPollerTestEnv(t)
+	env.repo.rows = []models.Request{builtinRow("r1", 550, models.RequestStatusDownloading, "abcdef")}
+	env.torrents.torrents = []qbittorrent.Torrent{{Hash: "abcdef", Status: qbittorrent.StatusError}}
+
+	env.poller.tick(context.Background())
+
+	updates := env.repo.updates()
+	require.Len(t, updates, 1)
+	assert.Equal(t, models.RequestStatusFailed, updates[0].status)
+}
+
+func TestPoller_BuiltinRowFinishedOrGoneEntersImportWindow(t *testing.T) {
+	env := newPollerTestEnv(t)
+	env.repo.rows = []models.Request{
+		builtinRow("r1", 550, models.RequestStatusDownloading, "aaaa"),
+		builtinRow("r2", 551, models.RequestStatusDownloading, "bbbb"),
+	}
+	env.torrents.torrents = []qbittorrent.Torrent{{Hash: "aaaa", Progress: 1, Status: qbittorrent.StatusSeeding}}
+
+	env.poller.tick(context.Background())
+
+	assert.Empty(t, env.repo.updates(), "import window holds downloading")
+	assert.Eventually(t, func() bool { return env.scanner.count() == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestPoller_BuiltinRowHeldWhenQBTUnavailable(t *testing.T) {
+	env := newPollerTestEnv(t)
+	env.repo.rows = []models.Request{builtinRow("r1", 550, models.RequestStatusDownloading, "aaaa")}
+	env.torrents.err = &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeNotConfigured, Message: "down"}
+
+	env.poller.tick(context.Background())
+
+	assert.Empty(t, env.repo.updates())
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 0, env.scanner.count())
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vido/api/internal/metrics"
 	"github.com/vido/api/internal/repository"
 	"github.com/vido/api/internal/retry"
 )
@@ -312,5 +313,28 @@ func (s *RetryService) ClearAll(ctx context.Context) error {
 	return s.repo.ClearAll(ctx)
 }
 
+// RegisterMetrics exposes the retry queue depth per task type on reg, read
+// from the repository at scrape time.
+func (s *RetryService) RegisterMetrics(reg *metrics.Registry) {
+	reg.NewGaugeFunc("vido_retry_queue_depth",
+		"Items waiting in the retry queue, by task type.",
+		[]string{"task_type"}, func(emit metrics.Emit) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			items, err := s.repo.GetAll(ctx)
+			if err != nil {
+				slog.Warn("Failed to read retry queue for metrics", "error", err)
+				return
+			}
+			depth := map[string]int{}
+			for _, item := range items {
+				depth[item.TaskType]++
+			}
+			for taskType, n := range depth {
+				emit(float64(n), taskType)
+			}
+		})
+}
+
 // Compile-time interface verification
 var _ RetryServiceInterface = (*RetryService)(nil)
@@ -69,7 +69,9 @@ const (
 	// This is the sub-1-5b segment-cache split applied to a different axis:
 	// the key PREFIX versions the key FORMAT, RunVersion versions the key's
 	// INPUTS; here the version tags the LOGIC that produced the value.
-	routeVersion = 1
+	//
+	// v2: a PGS/VobSub track the OCR route can read predicts extract, not asr.
+	routeVersion = 2
 )
 
 // routeCacheKey is the [@contract-v1] key format:
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vido/api/internal/metrics"
 	"github.com/vido/api/internal/models"
 	"github.com/vido/api/internal/parser"
 	"github.com/vido/api/internal/repository"
@@ -21,6 +22,10 @@ import (
 // ErrScanNotActive is returned when attempting to cancel a scan that is not running
 var ErrScanNotActive = fmt.Errorf("SCANNER_NOT_ACTIVE: no scan is currently active")
 
+// ErrScanAlreadyRunning is returned when a full or incremental scan is requested
+// while another one holds the scanner.
+var ErrScanAlreadyRunning = fmt.Errorf("SCANNER_ALREADY_RUNNING: a scan is already in progress")
+
 // videoExtensions defines the supported video file extensions (lowercase).
 // Using a function to prevent mutation of the lookup map.
 var videoExtensions = func() map[string]bool {
@@ -65,6 +70,7 @@ type ScannerService struct {
 	seriesRepo    repository.SeriesRepositoryInterface
 	episodeRepo   repository.EpisodeRepositoryInterface
 	libraryRepo   repository.MediaLibraryRepositoryInterface
+	organized     repository.OrganizedSourceRepositoryInterface
 	ingestService *MediaIngestService
 	parserService ParserServiceInterface
 	mediaDirs     []string // Fallback dirs from VIDO_MEDIA_DIRS env var
@@ -76,6 +82,7 @@ type ScannerService struct {
 	cancelChan      chan struct{}
 	progress        ScanProgress
 	onScanComplete  func()
+	notifier        EventNotifier
 }
 
 // SetOnScanComplete sets a callback to be invoked after a successful scan.
@@ -83,6 +90,13 @@ func (s *ScannerService) SetOnScanComplete(fn func()) {
 	s.onScanComplete = fn
 }
 
+// SetNotifier routes completed full scans to the outbound notification
+// targets. Watcher batches are left out: they fire per file change and would
+// flood every subscribed channel.
+func (s *ScannerService) SetNotifier(n EventNotifier) {
+	s.notifier = n
+}
+
 // NewScannerService creates a new ScannerService
 func NewScannerService(
 	movieRepo repository.MovieRepositoryInterface,
@@ -114,6 +128,12 @@ func (s *ScannerService) SetEpisodeRepo(repo repository.EpisodeRepositoryInterfa
 	s.episodeRepo = repo
 }
 
+// SetOrganizedSources makes the scanner skip the originals a copy or
+// hardlink organize left in place; their rows already point at the copy.
+func (s *ScannerService) SetOrganizedSources(repo repository.OrganizedSourceRepositoryInterface) {
+	s.organized = repo
+}
+
 // SetTVIngest enables TV routing. Without it the scanner keeps its historical behaviour
 // of writing every file to `movies`, which is what left series/seasons/episodes empty.
 func (s *ScannerService) SetTVIngest(ingest *MediaIngestService, parserService ParserServiceInterface) {
@@ -156,7 +176,7 @@ func (s *ScannerService) StartScan(ctx context.Context) (*ScanResult, error) {
 	s.mu.Lock()
 	if s.isScanning {
 		s.mu.Unlock()
-		return nil, fmt.Errorf("SCANNER_ALREADY_RUNNING: a scan is already in progress")
+		return nil, ErrScanAlreadyRunning
 	}
 	s.isScanning = true
 	s.cancelChan = make(chan struct{})
@@ -175,35 +195,7 @@ func (s *ScannerService) StartScan(ctx context.Context) (*ScanResult, error) {
 
 	startedAt := time.Now()
 
-	// Resolve scan directories from DB libraries or fallback to env var
-	type scanDir struct {
-		path        string
-		libraryID   string
-		contentType string
-	}
-	var dirs []scanDir
-
-	if s.libraryRepo != nil {
-		libraries, err := s.libraryRepo.GetAllWithPathsAndCounts(ctx)
-		if err != nil {
-			s.logger.Error("failed to read libraries from DB, falling back to env var", "error", err)
-		} else {
-			for _, lib := range libraries {
-				for _, p := range lib.Paths {
-					dirs = append(dirs, scanDir{path: p.Path, libraryID: lib.ID, contentType: string(lib.ContentType)})
-				}
-			}
-		}
-	}
-	if len(dirs) == 0 {
-		// Fallback to env var
-		for _, d := range s.mediaDirs {
-			dirs = append(dirs, scanDir{path: d})
-		}
-		if len(dirs) > 0 {
-			s.logger.Warn("Using VIDO_MEDIA_DIRS fallback — configure libraries in Settings for per-folder content type")
-		}
-	}
+	dirs := s.resolveScanDirs(ctx)
 
 	s.logger.Info("scan started", "dir_count", len(dirs))
 
@@ -262,7 +254,7 @@ func (s *ScannerService) StartScan(ctx context.Context) (*ScanResult, error) {
 			continue
 		}
 
-		err = s.walkDirectory(ctx, dir, sd.libraryID, sd.contentType, seenPaths, &pendingMovies)
+		err = s.walkDirectory(ctx, dir, dir, sd.libraryID, sd.contentType, seenPaths, &pendingMovies)
 		if err != nil {
 			s.logger.Error("SCANNER_PARSE_FAILED: error walking directory", "path", dir, "error", err)
 			s.mu.Lock()
@@ -310,6 +302,7 @@ func (s *ScannerService) StartScan(ctx context.Context) (*ScanResult, error) {
 		s.broadcastScanCancelled(result)
 	} else {
 		s.broadcastScanComplete(result)
+		s.notifyScanComplete(ctx, result)
 		// Trigger post-scan enrichment if configured
 		if s.onScanComplete != nil && (result.FilesCreated > 0 || result.FilesUpdated > 0) {
 			s.onScanComplete()
@@ -324,10 +317,277 @@ func (s *ScannerService) StartScan(ctx context.Context) (*ScanResult, error) {
 		"error_count", result.ErrorCount,
 		"duration", result.Duration,
 	)
+	observeScan("full", result)
 
 	return result, nil
 }
 
+// scanDir is one root the scanner walks, with the library it belongs to.
+// libraryID and contentType are empty on the VIDO_MEDIA_DIRS fallback.
+type scanDir struct {
+	path        string
+	libraryID   string
+	contentType string
+}
+
+// resolveScanDirs reads scan roots from the DB libraries, falling back to the
+// VIDO_MEDIA_DIRS env var when none are configured.
+func (s *ScannerService) resolveScanDirs(ctx context.Context) []scanDir {
+	var dirs []scanDir
+
+	if s.libraryRepo != nil {
+		libraries, err := s.libraryRepo.GetAllWithPathsAndCounts(ctx)
+		if err != nil {
+			s.logger.Error("failed to read libraries from DB, falling back to env var", "error", err)
+		} else {
+			for _, lib := range libraries {
+				for _, p := range lib.Paths {
+					dirs = append(dirs, scanDir{path: p.Path, libraryID: lib.ID, contentType: string(lib.ContentType)})
+				}
+			}
+		}
+	}
+	if len(dirs) == 0 {
+		// Fallback to env var
+		for _, d := range s.mediaDirs {
+			dirs = append(dirs, scanDir{path: d})
+		}
+		if len(dirs) > 0 {
+			s.logger.Warn("Using VIDO_MEDIA_DIRS fallback — configure libraries in Settings for per-folder content type")
+		}
+	}
+	return dirs
+}
+
+// ScanRoots returns the directories a full scan would walk. The library watcher
+// uses it to decide what to watch.
+func (s *ScannerService) ScanRoots(ctx context.Context) []string {
+	dirs := s.resolveScanDirs(ctx)
+	roots := make([]string, 0, len(dirs))
+	for _, d := range dirs {
+		roots = append(roots, d.path)
+	}
+	return roots
+}
+
+// PathChangeKind says what happened to a path reported by the library watcher.
+type PathChangeKind int
+
+const (
+	// PathChanged means the path was created, finished writing, or moved in.
+	PathChanged PathChangeKind = iota
+	// PathRemoved means the path was deleted or moved out.
+	PathRemoved
+)
+
+// PathChange is one debounced filesystem change under a scan root.
+type PathChange struct {
+	Path string
+	Kind PathChangeKind
+}
+
+// ProcessChangedPaths is the incremental counterpart of StartScan: it runs the
+// same per-file logic (processVideoFile / processTVFile, and the removed-file
+// marking) on just the given paths instead of walking every root. A changed
+// directory is walked, so a season folder moved in as a whole is picked up.
+//
+// It holds the same lock as StartScan and returns ErrScanAlreadyRunning while a
+// full scan runs; the caller retries later. On success it broadcasts
+// scan_complete and fires the SetOnScanComplete callback exactly like a full
+// scan, so enrichment and auto-subtitle generation see watcher-found files.
+func (s *ScannerService) ProcessChangedPaths(ctx context.Context, changes []PathChange) (*ScanResult, error) {
+	s.mu.Lock()
+	if s.isScanning {
+		s.mu.Unlock()
+		return nil, ErrScanAlreadyRunning
+	}
+	s.isScanning = true
+	s.cancelChan = make(chan struct{})
+	s.progress = ScanProgress{
+		IsActive:  true,
+		StartedAt: time.Now(),
+	}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.isScanning = false
+		s.progress.IsActive = false
+		s.mu.Unlock()
+	}()
+
+	startedAt := time.Now()
+	dirs := s.resolveScanDirs(ctx)
+	seenPaths := make(map[string]bool)
+	var pendingMovies []*models.Movie
+	var removed []string
+
+	for _, change := range changes {
+		root, ok := owningScanDir(dirs, change.Path)
+		if !ok {
+			s.logger.Debug("ignoring change outside every scan root", "path", change.Path)
+			continue
+		}
+
+		if change.Kind == PathRemoved {
+			removed = append(removed, change.Path)
+			continue
+		}
+
+		info, err := os.Stat(change.Path)
+		if err != nil {
+			// Gone again before the debounce fired — treat it as a removal.
+			if os.IsNotExist(err) {
+				removed = append(removed, change.Path)
+				continue
+			}
+			s.logger.Warn("failed to stat changed path", "path", change.Path, "error", err)
+			s.mu.Lock()
+			s.progress.ErrorCount++
+			s.mu.Unlock()
+			continue
+		}
+
+		if info.IsDir() {
+			if err := s.walkDirectory(ctx, change.Path, root.path, root.libraryID, root.contentType, seenPaths, &pendingMovies); err != nil {
+				s.logger.Error("SCANNER_PARSE_FAILED: error walking changed directory", "path", change.Path, "error", err)
+				s.mu.Lock()
+				s.progress.ErrorCount++
+				s.mu.Unlock()
+			}
+			continue
+		}
+		if !isVideoFile(change.Path) {
+			continue
+		}
+		s.processChangedFile(ctx, change.Path, root, seenPaths, &pendingMovies)
+	}
+
+	if len(pendingMovies) > 0 {
+		if err := s.flushBatch(ctx, &pendingMovies); err != nil {
+			s.logger.Error("failed to flush watcher batch", "error", err)
+		}
+	}
+
+	if len(removed) > 0 {
+		count, err := s.markRemovedPaths(ctx, removed)
+		if err != nil {
+			s.logger.Error("failed to mark removed paths", "error", err)
+		}
+		s.mu.Lock()
+		s.progress.FilesRemoved = count
+		s.mu.Unlock()
+	}
+
+	// Series file-size aggregation stays with the full scan: it stats every
+	// episode of every series, which is the cost the watcher exists to avoid.
+	result := s.buildResult(startedAt)
+	s.broadcastScanComplete(result)
+	observeScan("watcher", result)
+	if s.onScanComplete != nil && (result.FilesCreated > 0 || result.FilesUpdated > 0) {
+		s.onScanComplete()
+	}
+
+	s.logger.Info("incremental scan completed",
+		"changes", len(changes),
+		"files_created", result.FilesCreated,
+		"files_updated", result.FilesUpdated,
+		"files_removed", result.FilesRemoved,
+		"error_count", result.ErrorCount,
+		"duration", result.Duration,
+	)
+	return result, nil
+}
+
+// processChangedFile resolves and processes one watcher-reported video file,
+// with the same symlink and dedup handling walkDirectory applies.
+func (s *ScannerService) processChangedFile(ctx context.Context, path string, root scanDir, seenPaths map[string]bool, pendingMovies *[]*models.Movie) {
+	resolvedPath, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		resolvedPath, err = filepath.Abs(resolvedPath)
+	}
+	if err != nil {
+		s.logger.Warn("failed to resolve changed path", "path", path, "error", err)
+		s.mu.Lock()
+		s.progress.ErrorCount++
+		s.mu.Unlock()
+		return
+	}
+	if seenPaths[resolvedPath] {
+		return
+	}
+	seenPaths[resolvedPath] = true
+
+	s.mu.Lock()
+	s.progress.FilesFound++
+	s.progress.CurrentFile = resolvedPath
+	s.mu.Unlock()
+
+	if err := s.processVideoFile(ctx, resolvedPath, root.path, root.libraryID, root.contentType, pendingMovies); err != nil {
+		s.logger.Error("failed to process video file", "path", resolvedPath, "error", err)
+		s.mu.Lock()
+		s.progress.ErrorCount++
+		s.mu.Unlock()
+	}
+}
+
+// markRemovedPaths is detectRemovedFiles narrowed to the given paths: a movie
+// is marked removed when its file_path is one of them, or lies under one (a
+// deleted folder), and the file is really gone from disk.
+func (s *ScannerService) markRemovedPaths(ctx context.Context, paths []string) (int, error) {
+	movies, err := s.movieRepo.FindAllWithFilePath(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query movies with file paths: %w", err)
+	}
+
+	removedCount := 0
+	for i := range movies {
+		movie := &movies[i]
+		if movie.IsRemoved || !movie.FilePath.Valid || !pathUnderAny(movie.FilePath.String, paths) {
+			continue
+		}
+		if _, err := os.Stat(movie.FilePath.String); !os.IsNotExist(err) {
+			continue
+		}
+
+		movie.IsRemoved = true
+		movie.UpdatedAt = time.Now()
+		if err := s.movieRepo.Update(ctx, movie); err != nil {
+			s.logger.Error("failed to mark movie as removed", "id", movie.ID, "path", movie.FilePath.String, "error", err)
+			continue
+		}
+		removedCount++
+		s.logger.Info("marked movie as removed (watcher)", "id", movie.ID, "path", movie.FilePath.String)
+	}
+	return removedCount, nil
+}
+
+// owningScanDir returns the scan root that contains path, preferring the
+// deepest one when roots nest.
+func owningScanDir(dirs []scanDir, path string) (scanDir, bool) {
+	var best scanDir
+	found := false
+	for _, d := range dirs {
+		if pathUnderAny(path, []string{d.path}) && (!found || len(d.path) > len(best.path)) {
+			best = d
+			found = true
+		}
+	}
+	return best, found
+}
+
+// pathUnderAny reports whether path equals, or lies beneath, any of roots.
+func pathUnderAny(path string, roots []string) bool {
+	path = filepath.Clean(path)
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		if path == root || strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsScanActive returns true if a scan is currently in progress (thread-safe)
 func (s *ScannerService) IsScanActive() bool {
 	s.mu.Lock()
@@ -354,9 +614,11 @@ func (s *ScannerService) GetProgress() ScanProgress {
 	return s.progress
 }
 
-// walkDirectory recursively walks a directory and discovers video files
-func (s *ScannerService) walkDirectory(ctx context.Context, root string, libraryID string, contentType string, seenPaths map[string]bool, pendingMovies *[]*models.Movie) error {
-	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+// walkDirectory recursively walks dir and discovers video files. root is the
+// scan root dir belongs to — the same as dir for a full scan, an ancestor of
+// it when the watcher reports a new sub-folder.
+func (s *ScannerService) walkDirectory(ctx context.Context, dir, root string, libraryID string, contentType string, seenPaths map[string]bool, pendingMovies *[]*models.Movie) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		// Check cancellation
 		select {
 		case <-s.cancelChan:
@@ -460,6 +722,19 @@ func (s *ScannerService) processVideoFile(ctx context.Context, resolvedPath, sca
 		return fmt.Errorf("failed to stat file: %w", err)
 	}
 
+	if s.organized != nil {
+		left, err := s.organized.IsOrganizedSource(ctx, resolvedPath)
+		if err != nil {
+			return fmt.Errorf("failed to check organized sources: %w", err)
+		}
+		if left {
+			s.mu.Lock()
+			s.progress.FilesSkipped++
+			s.mu.Unlock()
+			return nil
+		}
+	}
+
 	if s.ingestService != nil {
 		if isTV, parseResult := s.resolveMediaType(contentType, resolvedPath); isTV {
 			return s.processTVFile(ctx, resolvedPath, scanRoot, libraryID, parseResult)
@@ -613,6 +888,36 @@ func (s *ScannerService) broadcastScanComplete(result *ScanResult) {
 	})
 }
 
+// notifyScanComplete hands a finished full scan to the notifier, if one is set.
+func (s *ScannerService) notifyScanComplete(ctx context.Context, result *ScanResult) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.Notify(ctx, models.NotificationEvent{
+		Type:  models.NotificationEventScanComplete,
+		Title: "媒體庫掃描完成",
+		Message: fmt.Sprintf("新增 %d、更新 %d、移除 %d 個檔案，錯誤 %d（耗時 %s）",
+			result.FilesCreated, result.FilesUpdated, result.FilesRemoved, result.ErrorCount, result.Duration),
+		Data: map[string]interface{}{
+			"files_found":   result.FilesFound,
+			"files_created": result.FilesCreated,
+			"files_updated": result.FilesUpdated,
+			"files_removed": result.FilesRemoved,
+			"error_count":   result.ErrorCount,
+			"duration":      result.Duration,
+		},
+	})
+}
+
+// observeScan records a finished scan on /metrics.
+func observeScan(kind string, result *ScanResult) {
+	metrics.ScanDuration.WithLabelValues(kind).Observe(result.CompletedAt.Sub(result.StartedAt).Seconds())
+	metrics.ScanFiles.WithLabelValues(kind, "created").Add(float64(result.FilesCreated))
+	metrics.ScanFiles.WithLabelValues(kind, "updated").Add(float64(result.FilesUpdated))
+	metrics.ScanFiles.WithLabelValues(kind, "removed").Add(float64(result.FilesRemoved))
+	metrics.ScanFiles.WithLabelValues(kind, "error").Add(float64(result.ErrorCount))
+}
+
 // broadcastScanCancelled sends a scan_cancelled SSE event.
 func (s *ScannerService) broadcastScanCancelled(result *ScanResult) {
 	if s.sseHub == nil {
@@ -841,3 +841,89 @@ func TestScannerService_MoviesStillRouteToMovies(t *testing.T) {
 		t.Errorf("episode count = %d, want 0", len(episodeRepo.episodes))
 	}
 }
+
+func TestScannerService_ProcessChangedPaths_NewFile(t *testing.T) {
+	dir := t.TempDir()
+	paths := createVideoFiles(t, dir, []string{"new.mkv", "untouched.mkv"})
+
+	svc, movieRepo, _ := setupScannerService(t, []string{dir})
+	movieRepo.On("FindByFilePath", mock.Anything, mock.AnythingOfType("string")).Return(nil, nil)
+	movieRepo.On("BulkCreate", mock.Anything, mock.MatchedBy(func(movies []*models.Movie) bool {
+		return len(movies) == 1 && strings.HasSuffix(movies[0].FilePath.String, "new.mkv")
+	})).Return(nil)
+
+	completed := 0
+	svc.SetOnScanComplete(func() { completed++ })
+
+	result, err := svc.ProcessChangedPaths(context.Background(), []PathChange{{Path: paths[0], Kind: PathChanged}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.FilesCreated)
+	assert.Equal(t, 1, completed, "new files must reach the post-scan callback")
+	movieRepo.AssertNumberOfCalls(t, "BulkCreate", 1)
+}
+
+func TestScannerService_ProcessChangedPaths_NewDirectory(t *testing.T) {
+	dir := t.TempDir()
+	season := filepath.Join(dir, "Some Movie (2024)")
+	require.NoError(t, os.MkdirAll(season, 0755))
+	createVideoFiles(t, season, []string{"Some.Movie.2024.1080p.mkv"})
+
+	svc, movieRepo, _ := setupScannerService(t, []string{dir})
+	movieRepo.On("FindByFilePath", mock.Anything, mock.AnythingOfType("string")).Return(nil, nil)
+	movieRepo.On("BulkCreate", mock.Anything, mock.AnythingOfType("[]*models.Movie")).Return(nil)
+
+	result, err := svc.ProcessChangedPaths(context.Background(), []PathChange{{Path: season, Kind: PathChanged}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.FilesCreated)
+}
+
+func TestScannerService_ProcessChangedPaths_Removed(t *testing.T) {
+	dir := t.TempDir()
+	svc, movieRepo, _ := setupScannerService(t, []string{dir})
+
+	gone := filepath.Join(dir, "gone.mkv")
+	kept := filepath.Join(dir, "other", "gone.mkv")
+	movieRepo.ExpectedCalls = filterCalls(movieRepo.ExpectedCalls, "FindAllWithFilePath")
+	movieRepo.On("FindAllWithFilePath", mock.Anything).Return([]models.Movie{
+		{ID: "movie-gone", FilePath: models.NewNullString(gone)},
+		{ID: "movie-elsewhere", FilePath: models.NewNullString(kept)},
+	}, nil)
+	movieRepo.On("Update", mock.Anything, mock.MatchedBy(func(m *models.Movie) bool {
+		return m.ID == "movie-gone" && m.IsRemoved
+	})).Return(nil)
+
+	completed := 0
+	svc.SetOnScanComplete(func() { completed++ })
+
+	result, err := svc.ProcessChangedPaths(context.Background(), []PathChange{{Path: gone, Kind: PathRemoved}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.FilesRemoved)
+	assert.Zero(t, completed, "a removal alone does not trigger post-scan enrichment")
+	movieRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestScannerService_ProcessChangedPaths_OutsideRoots(t *testing.T) {
+	dir := t.TempDir()
+	outside := createVideoFiles(t, t.TempDir(), []string{"stray.mkv"})
+
+	svc, movieRepo, _ := setupScannerService(t, []string{dir})
+
+	result, err := svc.ProcessChangedPaths(context.Background(), []PathChange{{Path: outside[0], Kind: PathChanged}})
+
+	require.NoError(t, err)
+	assert.Zero(t, result.FilesFound)
+	movieRepo.AssertNotCalled(t, "BulkCreate", mock.Anything, mock.Anything)
+}
+
+func TestScannerService_ProcessChangedPaths_ScanRunning(t *testing.T) {
+	svc, _, _ := setupScannerService(t, []string{t.TempDir()})
+	svc.mu.Lock()
+	svc.isScanning = true
+	svc.mu.Unlock()
+
+	_, err := svc.ProcessChangedPaths(context.Background(), []PathChange{{Path: "/x.mkv"}})
+	assert.ErrorIs(t, err, ErrScanAlreadyRunning)
+}
@@ -45,6 +45,9 @@ func (s *stubEpisodeRepo) FindBySeasonID(context.Context, string) ([]models.Epis
 func (s *stubEpisodeRepo) FindBySeriesSeasonEpisode(context.Context, string, int, int) (*models.Episode, error) {
 	return nil, nil
 }
+func (s *stubEpisodeRepo) FindByFilePath(context.Context, string) (*models.Episode, error) {
+	return nil, nil
+}
 func (s *stubEpisodeRepo) Update(context.Context, *models.Episode) error { return nil }
 func (s *stubEpisodeRepo) UpdateEpisodeSubtitleStatus(context.Context, string, models.SubtitleStatus, string, string) error {
 	return nil
@@ -84,6 +84,12 @@ func (s *ServiceStatusService) getCheckFunc(name models.ServiceName) (func(conte
 		return s.checker.CheckAI, nil
 	case models.ServiceNameQBittorrent:
 		return s.checker.CheckQBittorrent, nil
+	case models.ServiceNamePlex:
+		return s.checker.CheckPlex, nil
+	case models.ServiceNameTransmission:
+		return s.checker.CheckTransmission, nil
+	case models.ServiceNameDeluge:
+		return s.checker.CheckDeluge, nil
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
 	}
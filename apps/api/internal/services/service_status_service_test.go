@@ -18,7 +18,7 @@ func TestServiceStatusService_GetAllStatuses(t *testing.T) {
 
 	statuses, err := svc.GetAllStatuses(context.Background())
 	require.NoError(t, err)
-	assert.Len(t, statuses, 5)
+	assert.Len(t, statuses, 8)
 
 	// All should be connected (stub checker returns healthy)
 	for _, s := range statuses {
@@ -39,7 +39,7 @@ func TestServiceStatusService_GetAllStatuses_MixedStates(t *testing.T) {
 
 	statuses, err := svc.GetAllStatuses(context.Background())
 	require.NoError(t, err)
-	assert.Len(t, statuses, 5)
+	assert.Len(t, statuses, 8)
 
 	statusMap := make(map[string]string)
 	for _, s := range statuses {
@@ -64,7 +64,7 @@ func (s *TerminologyCorrectionService) Correct(ctx context.Context, subtitleCont
 	start := time.Now()
 
 	// Apply 30-second timeout per AC #6
-	ctx, cancel := context.WithTimeout(ctx, TerminologyCorrectionTimeout)
+	ctx, cancel := context.WithTimeout(ai.WithUsageFeature(ctx, ai.FeatureTerminology), TerminologyCorrectionTimeout)
 	defer cancel()
 
 	userPrompt := prompts.BuildTerminologyCorrectorPrompt(subtitleContent)
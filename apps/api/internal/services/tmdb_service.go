@@ -34,6 +34,9 @@ type TMDbServiceInterface interface {
 	// GetTVExternalIDs retrieves a TV show's external-service ids (tvdb/imdb),
 	// cached at the default TTL — the Sonarr TVDB-resolution flow (Story 13-4b)
 	GetTVExternalIDs(ctx context.Context, tvID int) (*tmdb.TVExternalIDs, error)
+	// GetCollection retrieves a movie collection and its parts (cached at the
+	// default TTL) — the collections/franchise view
+	GetCollection(ctx context.Context, collectionID int) (*tmdb.CollectionDetails, error)
 	// FindByExternalID finds movies/TV shows by an external ID (e.g., IMDB)
 	FindByExternalID(ctx context.Context, externalID string, externalSource string) (*tmdb.FindByExternalIDResponse, error)
 	// GetTrendingMovies returns trending movies (cached 1h, server-side filtered for zh-TW relevance).
@@ -322,6 +325,23 @@ func (s *TMDbService) GetTVExternalIDs(ctx context.Context, tvID int) (*tmdb.TVE
 	return result, nil
 }
 
+// GetCollection retrieves a movie collection and its parts, cached at the
+// default TTL.
+func (s *TMDbService) GetCollection(ctx context.Context, collectionID int) (*tmdb.CollectionDetails, error) {
+	if collectionID <= 0 {
+		return nil, tmdb.NewBadRequestError("collection ID must be greater than 0")
+	}
+
+	result, err := s.cacheService.GetCollection(ctx, collectionID)
+	if err != nil {
+		slog.Error("Failed to get collection", "collection_id", collectionID, "error", err)
+		return nil, err
+	}
+
+	slog.Debug("Collection retrieved", "collection_id", collectionID, "parts", len(result.Parts))
+	return result, nil
+}
+
 // FindByExternalID finds movies/TV shows by an external ID (e.g., IMDB).
 // This bypasses the cache layer and calls the client directly since find results are not cacheable.
 func (s *TMDbService) FindByExternalID(ctx context.Context, externalID string, externalSource string) (*tmdb.FindByExternalIDResponse, error) {
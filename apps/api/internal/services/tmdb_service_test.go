@@ -126,6 +126,10 @@ func (m *MockCacheService) GetTVExternalIDs(ctx context.Context, tvID int) (*tmd
 	return &tmdb.TVExternalIDs{ID: int64(tvID)}, nil
 }
 
+func (m *MockCacheService) GetCollection(ctx context.Context, collectionID int) (*tmdb.CollectionDetails, error) {
+	return &tmdb.CollectionDetails{ID: collectionID}, nil
+}
+
 // Story 10-1 additions
 
 func (m *MockCacheService) GetTrendingMovies(ctx context.Context, timeWindow string, page int) (*tmdb.SearchResultMovies, error) {
@@ -0,0 +1,878 @@
+// Package services — TraktService.
+//
+// Links one Trakt.tv account to Vido through the device-code flow and keeps
+// the two in step:
+//
+//   - watch history and ratings are imported into the history of the Vido
+//     user who linked the account, matched on the TMDb IDs of the library;
+//   - the Trakt watchlist and Vido's requests sync both ways: titles added
+//     to the watchlist since the last sync become requests, requests made
+//     since the last sync join the watchlist;
+//   - the library is exported as the Trakt collection;
+//   - playback is scrobbled as it is reported.
+//
+// Tokens and the client secret live in the secrets store; everything else
+// is plain settings under "trakt.".
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	"github.com/vido/api/internal/secrets"
+	"github.com/vido/api/internal/trakt"
+)
+
+// Trakt settings keys. The secrets use the same names.
+const (
+	SettingTraktClientID       = "trakt.client_id"
+	SettingTraktUsername       = "trakt.username"
+	SettingTraktUserID         = "trakt.user_id"
+	SettingTraktTokenExpiresAt = "trakt.token_expires_at"
+	SettingTraktLastSyncAt     = "trakt.last_sync_at"
+
+	secretTraktClientSecret = "trakt.client_secret"
+	secretTraktAccessToken  = "trakt.access_token"
+	secretTraktRefreshToken = "trakt.refresh_token"
+)
+
+const (
+	// defaultTraktSyncInterval is how often the linked account is synced in
+	// the background.
+	defaultTraktSyncInterval = 6 * time.Hour
+
+	// traktTokenRefreshMargin renews the access token this long before it
+	// expires, so a sync never starts with a token about to lapse.
+	traktTokenRefreshMargin = time.Hour
+
+	// traktScrobbleEvery throttles progress scrobbles: players report every
+	// few seconds, Trakt only needs to know playback is under way.
+	traktScrobbleEvery = 5 * time.Minute
+)
+
+// Device authorization outcomes the handler turns into responses.
+var (
+	// ErrTraktAuthorizationPending means the user has not entered the code yet.
+	ErrTraktAuthorizationPending = errors.New("trakt authorization pending")
+	// ErrTraktNotConnected means no account is linked.
+	ErrTraktNotConnected = errors.New("trakt account not connected")
+)
+
+// TraktConfigInput is the PUT /settings/trakt body: the API application
+// created at trakt.tv/oauth/applications. An empty secret keeps the stored
+// one.
+type TraktConfigInput struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// TraktStatus is the settings page's view of the integration.
+type TraktStatus struct {
+	ClientID        string     `json:"client_id"`
+	HasClientSecret bool       `json:"has_client_secret"`
+	Configured      bool       `json:"configured"`
+	Connected       bool       `json:"connected"`
+	Username        string     `json:"username,omitempty"`
+	UserID          string     `json:"user_id,omitempty"`
+	LastSyncAt      *time.Time `json:"last_sync_at,omitempty"`
+}
+
+// TraktSyncResult reports one sync.
+type TraktSyncResult struct {
+	HistoryImported    int `json:"history_imported"`    // items whose watch state changed
+	HistoryUnchanged   int `json:"history_unchanged"`   // items Vido already had
+	HistoryUnmatched   int `json:"history_unmatched"`   // watched on Trakt, not in the library
+	RatingsImported    int `json:"ratings_imported"`    // ratings saved
+	RequestsCreated    int `json:"requests_created"`    // watchlist titles requested
+	WatchlistAdded     int `json:"watchlist_added"`     // requests added to the watchlist
+	CollectionMovies   int `json:"collection_movies"`   // movies sent to the collection
+	CollectionEpisodes int `json:"collection_episodes"` // episodes sent to the collection
+}
+
+// TraktServiceInterface defines the contract for the Trakt integration.
+type TraktServiceInterface interface {
+	GetStatus(ctx context.Context) (*TraktStatus, error)
+	SaveConfig(ctx context.Context, input TraktConfigInput) error
+	// StartDeviceAuth begins linking the account of userID ("" on an install
+	// without accounts) and returns the code to show the user.
+	StartDeviceAuth(ctx context.Context, userID string) (*trakt.DeviceCode, error)
+	// PollDeviceAuth checks once whether the code was entered; it returns
+	// ErrTraktAuthorizationPending until it was.
+	PollDeviceAuth(ctx context.Context) (*TraktStatus, error)
+	Disconnect(ctx context.Context) error
+	Sync(ctx context.Context) (*TraktSyncResult, error)
+}
+
+// Narrow ports over the library and requests.
+type traktMovieStore interface {
+	FindByID(ctx context.Context, id string) (*models.Movie, error)
+	List(ctx context.Context, params repository.ListParams) ([]models.Movie, *repository.PaginationResult, error)
+}
+
+type traktSeriesStore interface {
+	FindByID(ctx context.Context, id string) (*models.Series, error)
+	List(ctx context.Context, params repository.ListParams) ([]models.Series, *repository.PaginationResult, error)
+}
+
+type traktEpisodeStore interface {
+	FindByID(ctx context.Context, id string) (*models.Episode, error)
+	FindBySeriesID(ctx context.Context, seriesID string) ([]models.Episode, error)
+}
+
+type traktRequestCreator interface {
+	CreateRequest(ctx context.Context, req CreateMediaRequestRequest) (*models.Request, error)
+}
+
+type traktRequestStore interface {
+	ListActive(ctx context.Context) ([]models.Request, error)
+}
+
+// traktDeviceAuth is a device authorization under way.
+type traktDeviceAuth struct {
+	deviceCode string
+	userID     string
+	expiresAt  time.Time
+}
+
+// TraktService implements TraktServiceInterface and PlaybackObserver.
+type TraktService struct {
+	settingsRepo repository.SettingsRepositoryInterface
+	secrets      secrets.SecretsServiceInterface
+	movies       traktMovieStore
+	series       traktSeriesStore
+	episodes     traktEpisodeStore
+	watch        watchStateMerger
+	ratings      repository.UserRatingRepositoryInterface
+	requests     traktRequestCreator
+	requestStore traktRequestStore
+	baseURL      string
+	interval     time.Duration
+	now          func() time.Time
+	logger       *slog.Logger
+
+	mu        sync.Mutex
+	pending   *traktDeviceAuth
+	scrobbled map[string]time.Time // media id → last progress scrobble
+	stopCh    chan struct{}
+	stopped   bool
+}
+
+// NewTraktService creates a TraktService.
+func NewTraktService(
+	settingsRepo repository.SettingsRepositoryInterface,
+	secretsService secrets.SecretsServiceInterface,
+	movies traktMovieStore,
+	series traktSeriesStore,
+	episodes traktEpisodeStore,
+	watch watchStateMerger,
+	ratings repository.UserRatingRepositoryInterface,
+	requests traktRequestCreator,
+	requestStore traktRequestStore,
+	logger *slog.Logger,
+) *TraktService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &TraktService{
+		settingsRepo: settingsRepo,
+		secrets:      secretsService,
+		movies:       movies,
+		series:       series,
+		episodes:     episodes,
+		watch:        watch,
+		ratings:      ratings,
+		requests:     requests,
+		requestStore: requestStore,
+		baseURL:      trakt.DefaultBaseURL,
+		interval:     defaultTraktSyncInterval,
+		now:          time.Now,
+		logger:       logger.With("service", "trakt"),
+		scrobbled:    make(map[string]time.Time),
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// GetStatus returns the configuration and link state.
+func (s *TraktService) GetStatus(ctx context.Context) (*TraktStatus, error) {
+	clientID, _ := s.settingsRepo.GetString(ctx, SettingTraktClientID)
+	hasSecret, err := s.secrets.Exists(ctx, secretTraktClientSecret)
+	if err != nil {
+		return nil, fmt.Errorf("check trakt client secret: %w", err)
+	}
+	connected, err := s.secrets.Exists(ctx, secretTraktAccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("check trakt token: %w", err)
+	}
+	status := &TraktStatus{
+		ClientID:        clientID,
+		HasClientSecret: hasSecret,
+		Configured:      clientID != "" && hasSecret,
+		Connected:       connected,
+	}
+	if connected {
+		status.Username, _ = s.settingsRepo.GetString(ctx, SettingTraktUsername)
+		status.UserID, _ = s.settingsRepo.GetString(ctx, SettingTraktUserID)
+	}
+	status.LastSyncAt = s.settingTime(ctx, SettingTraktLastSyncAt)
+	return status, nil
+}
+
+// SaveConfig stores the API application.
+func (s *TraktService) SaveConfig(ctx context.Context, input TraktConfigInput) error {
+	clientID := strings.TrimSpace(input.ClientID)
+	if clientID == "" {
+		return &models.ValidationError{Field: "client_id", Message: "client_id is required"}
+	}
+	if input.ClientSecret == "" {
+		if exists, _ := s.secrets.Exists(ctx, secretTraktClientSecret); !exists {
+			return &models.ValidationError{Field: "client_secret", Message: "client_secret is required"}
+		}
+	}
+
+	if err := s.settingsRepo.SetString(ctx, SettingTraktClientID, clientID); err != nil {
+		return fmt.Errorf("save trakt client id: %w", err)
+	}
+	if input.ClientSecret != "" {
+		if err := s.secrets.Store(ctx, secretTraktClientSecret, strings.TrimSpace(input.ClientSecret)); err != nil {
+			return fmt.Errorf("encrypt trakt client secret: %w", err)
+		}
+	}
+	s.logger.Info("Trakt application saved", "client_id", clientID)
+	return nil
+}
+
+// appClient returns a client for the API application without a user token.
+func (s *TraktService) appClient(ctx context.Context) (*trakt.Client, error) {
+	clientID, _ := s.settingsRepo.GetString(ctx, SettingTraktClientID)
+	var clientSecret string
+	if exists, _ := s.secrets.Exists(ctx, secretTraktClientSecret); exists {
+		secret, err := s.secrets.Retrieve(ctx, secretTraktClientSecret)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt trakt client secret: %w", err)
+		}
+		clientSecret = secret
+	}
+	if clientID == "" || clientSecret == "" {
+		return nil, &trakt.Error{Code: trakt.ErrCodeNotConfigured, Message: "Trakt client ID and secret are not set"}
+	}
+	return trakt.NewClient(trakt.Config{BaseURL: s.baseURL, ClientID: clientID, ClientSecret: clientSecret}), nil
+}
+
+// StartDeviceAuth requests a device code. A new request replaces one under way.
+func (s *TraktService) StartDeviceAuth(ctx context.Context, userID string) (*trakt.DeviceCode, error) {
+	client, err := s.appClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	code, err := client.RequestDeviceCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.pending = &traktDeviceAuth{
+		deviceCode: code.DeviceCode,
+		userID:     userID,
+		expiresAt:  s.now().Add(time.Duration(code.ExpiresIn) * time.Second),
+	}
+	s.mu.Unlock()
+	return code, nil
+}
+
+// PollDeviceAuth completes the link once the user has entered the code.
+func (s *TraktService) PollDeviceAuth(ctx context.Context) (*TraktStatus, error) {
+	s.mu.Lock()
+	pending := s.pending
+	s.mu.Unlock()
+	if pending == nil {
+		return nil, &models.ValidationError{Field: "device_code", Message: "no Trakt authorization is in progress"}
+	}
+	if s.now().After(pending.expiresAt) {
+		s.clearPending(pending)
+		return nil, trakt.ErrDeviceCodeExpired
+	}
+
+	client, err := s.appClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := client.PollDeviceToken(ctx, pending.deviceCode)
+	if errors.Is(err, trakt.ErrAuthorizationPending) || errors.Is(err, trakt.ErrSlowDown) {
+		return nil, ErrTraktAuthorizationPending
+	}
+	if err != nil {
+		if errors.Is(err, trakt.ErrDeviceCodeExpired) || errors.Is(err, trakt.ErrAccessDenied) || errors.Is(err, trakt.ErrInvalidDeviceCode) {
+			s.clearPending(pending)
+		}
+		return nil, err
+	}
+	s.clearPending(pending)
+
+	if err := s.saveToken(ctx, token); err != nil {
+		return nil, err
+	}
+	if err := s.settingsRepo.SetString(ctx, SettingTraktUserID, pending.userID); err != nil {
+		return nil, fmt.Errorf("save trakt user: %w", err)
+	}
+	// A fresh link imports the whole watchlist on its first sync.
+	if err := s.settingsRepo.Delete(ctx, SettingTraktLastSyncAt); err != nil {
+		s.logger.Warn("Failed to reset Trakt sync time", "error", err)
+	}
+
+	username := ""
+	userClient, err := s.userClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if settings, err := userClient.GetSettings(ctx); err == nil {
+		username = settings.User.Username
+	} else {
+		s.logger.Warn("Failed to read Trakt account", "error", err)
+	}
+	if err := s.settingsRepo.SetString(ctx, SettingTraktUsername, username); err != nil {
+		return nil, fmt.Errorf("save trakt username: %w", err)
+	}
+	s.logger.Info("Trakt account linked", "username", username, "user_id", pending.userID)
+	return s.GetStatus(ctx)
+}
+
+func (s *TraktService) clearPending(pending *traktDeviceAuth) {
+	s.mu.Lock()
+	if s.pending == pending {
+		s.pending = nil
+	}
+	s.mu.Unlock()
+}
+
+func (s *TraktService) saveToken(ctx context.Context, token *trakt.Token) error {
+	if err := s.secrets.Store(ctx, secretTraktAccessToken, token.AccessToken); err != nil {
+		return fmt.Errorf("encrypt trakt access token: %w", err)
+	}
+	if err := s.secrets.Store(ctx, secretTraktRefreshToken, token.RefreshToken); err != nil {
+		return fmt.Errorf("encrypt trakt refresh token: %w", err)
+	}
+	if err := s.settingsRepo.SetString(ctx, SettingTraktTokenExpiresAt, token.ExpiresAt().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("save trakt token expiry: %w", err)
+	}
+	return nil
+}
+
+// Disconnect revokes the token and forgets the account. Imported history
+// and ratings stay.
+func (s *TraktService) Disconnect(ctx context.Context) error {
+	if client, err := s.userClient(ctx); err == nil {
+		accessToken, _ := s.secrets.Retrieve(ctx, secretTraktAccessToken)
+		if err := client.RevokeToken(ctx, accessToken); err != nil {
+			s.logger.Warn("Failed to revoke Trakt token", "error", err)
+		}
+	}
+	for _, name := range []string{secretTraktAccessToken, secretTraktRefreshToken} {
+		if err := s.secrets.Delete(ctx, name); err != nil {
+			return fmt.Errorf("delete %s: %w", name, err)
+		}
+	}
+	for _, key := range []string{SettingTraktUsername, SettingTraktUserID, SettingTraktTokenExpiresAt, SettingTraktLastSyncAt} {
+		if err := s.settingsRepo.Delete(ctx, key); err != nil {
+			return fmt.Errorf("delete %s: %w", key, err)
+		}
+	}
+	s.logger.Info("Trakt account unlinked")
+	return nil
+}
+
+// userClient returns a client for the linked account, renewing the access
+// token when it is about to expire.
+func (s *TraktService) userClient(ctx context.Context) (*trakt.Client, error) {
+	if exists, _ := s.secrets.Exists(ctx, secretTraktAccessToken); !exists {
+		return nil, ErrTraktNotConnected
+	}
+	app, err := s.appClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := s.secrets.Retrieve(ctx, secretTraktAccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt trakt access token: %w", err)
+	}
+
+	if expiresAt := s.settingTime(ctx, SettingTraktTokenExpiresAt); expiresAt != nil && s.now().Add(traktTokenRefreshMargin).After(*expiresAt) {
+		refreshToken, err := s.secrets.Retrieve(ctx, secretTraktRefreshToken)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt trakt refresh token: %w", err)
+		}
+		token, err := app.RefreshToken(ctx, refreshToken)
+		if err != nil {
+			return nil, fmt.Errorf("refresh trakt token: %w", err)
+		}
+		if err := s.saveToken(ctx, token); err != nil {
+			return nil, err
+		}
+		accessToken = token.AccessToken
+		s.logger.Info("Trakt access token renewed")
+	}
+
+	clientID, _ := s.settingsRepo.GetString(ctx, SettingTraktClientID)
+	return trakt.NewClient(trakt.Config{BaseURL: s.baseURL, ClientID: clientID, AccessToken: accessToken}), nil
+}
+
+func (s *TraktService) settingTime(ctx context.Context, key string) *time.Time {
+	value, err := s.settingsRepo.GetString(ctx, key)
+	if err != nil || value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// Start syncs the linked account every interval until ctx is cancelled or
+// Stop is called. Nothing happens while no account is linked.
+func (s *TraktService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			if _, err := s.Sync(ctx); err != nil && !errors.Is(err, ErrTraktNotConnected) {
+				s.logger.Warn("Scheduled Trakt sync failed", "error", err)
+			}
+		}
+	}
+}
+
+// Stop stops the background sync. Idempotent.
+func (s *TraktService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.stopCh)
+	}
+}
+
+// traktLibrary indexes the library by TMDb ID.
+type traktLibrary struct {
+	movies map[int64]models.Movie
+	series map[int64]models.Series
+}
+
+// Sync runs every direction of the sync once.
+func (s *TraktService) Sync(ctx context.Context) (*TraktSyncResult, error) {
+	client, err := s.userClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	startedAt := s.now().UTC()
+	userID, _ := s.settingsRepo.GetString(ctx, SettingTraktUserID)
+	lastSync := s.settingTime(ctx, SettingTraktLastSyncAt)
+
+	library, err := s.loadLibrary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TraktSyncResult{}
+	if err := s.importHistory(ctx, client, userID, library, result); err != nil {
+		return nil, fmt.Errorf("import history: %w", err)
+	}
+	if err := s.importRatings(ctx, client, userID, library, result); err != nil {
+		return nil, fmt.Errorf("import ratings: %w", err)
+	}
+	if err := s.syncWatchlist(ctx, client, lastSync, result); err != nil {
+		return nil, fmt.Errorf("sync watchlist: %w", err)
+	}
+	if err := s.exportCollection(ctx, client, library, result); err != nil {
+		return nil, fmt.Errorf("export collection: %w", err)
+	}
+
+	if err := s.settingsRepo.SetString(ctx, SettingTraktLastSyncAt, startedAt.Format(time.RFC3339Nano)); err != nil {
+		return nil, fmt.Errorf("save trakt sync time: %w", err)
+	}
+	s.logger.Info("Trakt sync finished",
+		"history_imported", result.HistoryImported,
+		"history_unmatched", result.HistoryUnmatched,
+		"ratings_imported", result.RatingsImported,
+		"requests_created", result.RequestsCreated,
+		"watchlist_added", result.WatchlistAdded,
+		"collection_movies", result.CollectionMovies,
+		"collection_episodes", result.CollectionEpisodes,
+	)
+	return result, nil
+}
+
+func (s *TraktService) loadLibrary(ctx context.Context) (*traktLibrary, error) {
+	library := &traktLibrary{movies: map[int64]models.Movie{}, series: map[int64]models.Series{}}
+
+	params := repository.NewListParams()
+	params.PageSize = repository.MaxPageSize
+	for {
+		movies, pagination, err := s.movies.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("list movies: %w", err)
+		}
+		for _, m := range movies {
+			if m.TMDbID.Valid && m.TMDbID.Int64 > 0 {
+				library.movies[m.TMDbID.Int64] = m
+			}
+		}
+		if pagination == nil || params.Page >= pagination.TotalPages {
+			break
+		}
+		params.Page++
+	}
+
+	params = repository.NewListParams()
+	params.PageSize = repository.MaxPageSize
+	for {
+		series, pagination, err := s.series.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("list series: %w", err)
+		}
+		for _, sr := range series {
+			if sr.TMDbID.Valid && sr.TMDbID.Int64 > 0 {
+				library.series[sr.TMDbID.Int64] = sr
+			}
+		}
+		if pagination == nil || params.Page >= pagination.TotalPages {
+			break
+		}
+		params.Page++
+	}
+	return library, nil
+}
+
+// importHistory merges Trakt's watched movies and episodes into the linked
+// user's history. As for media servers, nothing recorded in Vido is undone.
+func (s *TraktService) importHistory(ctx context.Context, client *trakt.Client, userID string, library *traktLibrary, result *TraktSyncResult) error {
+	movies, err := client.GetWatchedMovies(ctx)
+	if err != nil {
+		return err
+	}
+	for _, watched := range movies {
+		movie, ok := library.movies[watched.Movie.IDs.TMDb]
+		if !ok {
+			result.HistoryUnmatched++
+			continue
+		}
+		if err := s.mergeWatched(ctx, userID, models.WatchMediaMovie, movie.ID, watched.Plays, watched.LastWatchedAt, result); err != nil {
+			return err
+		}
+	}
+
+	shows, err := client.GetWatchedShows(ctx)
+	if err != nil {
+		return err
+	}
+	for _, watched := range shows {
+		series, ok := library.series[watched.Show.IDs.TMDb]
+		if !ok {
+			result.HistoryUnmatched++
+			continue
+		}
+		episodes, err := s.episodes.FindBySeriesID(ctx, series.ID)
+		if err != nil {
+			return fmt.Errorf("list episodes of %s: %w", series.ID, err)
+		}
+		byNumber := make(map[[2]int]string, len(episodes))
+		for _, ep := range episodes {
+			byNumber[[2]int{ep.SeasonNumber, ep.EpisodeNumber}] = ep.ID
+		}
+		for _, season := range watched.Seasons {
+			for _, ep := range season.Episodes {
+				episodeID, ok := byNumber[[2]int{season.Number, ep.Number}]
+				if !ok {
+					result.HistoryUnmatched++
+					continue
+				}
+				if err := s.mergeWatched(ctx, userID, models.WatchMediaEpisode, episodeID, ep.Plays, ep.LastWatchedAt, result); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *TraktService) mergeWatched(ctx context.Context, userID, mediaType, mediaID string, plays int, lastWatched time.Time, result *TraktSyncResult) error {
+	ext := ExternalWatchState{Watched: true, PlayCount: plays}
+	if !lastWatched.IsZero() {
+		ext.LastPlayedAt = &lastWatched
+	}
+	changed, err := s.watch.MergeExternalState(ctx, userID, mediaType, mediaID, ext)
+	if err != nil {
+		return fmt.Errorf("merge %s %s: %w", mediaType, mediaID, err)
+	}
+	if changed {
+		result.HistoryImported++
+	} else {
+		result.HistoryUnchanged++
+	}
+	return nil
+}
+
+// importRatings saves Trakt's ratings of titles in the library. A rating
+// given in Vido is left alone.
+func (s *TraktService) importRatings(ctx context.Context, client *trakt.Client, userID string, library *traktLibrary, result *TraktSyncResult) error {
+	ratings, err := client.GetRatings(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range ratings {
+		var mediaType, mediaID string
+		switch {
+		case r.Movie != nil:
+			if movie, ok := library.movies[r.Movie.IDs.TMDb]; ok {
+				mediaType, mediaID = models.RatingMediaMovie, movie.ID
+			}
+		case r.Show != nil:
+			if series, ok := library.series[r.Show.IDs.TMDb]; ok {
+				mediaType, mediaID = models.RatingMediaSeries, series.ID
+			}
+		}
+		if mediaID == "" || r.Rating < 1 || r.Rating > 10 {
+			continue
+		}
+
+		existing, err := s.ratings.Get(ctx, userID, mediaType, mediaID)
+		if err != nil {
+			return err
+		}
+		if existing != nil && (existing.Source != models.RatingSourceTrakt || existing.Rating == r.Rating) {
+			continue
+		}
+		if err := s.ratings.Upsert(ctx, &models.UserRating{
+			UserID:    userID,
+			MediaType: mediaType,
+			MediaID:   mediaID,
+			Rating:    r.Rating,
+			Source:    models.RatingSourceTrakt,
+			RatedAt:   r.RatedAt,
+		}); err != nil {
+			return err
+		}
+		result.RatingsImported++
+	}
+	return nil
+}
+
+// syncWatchlist requests what was added to the watchlist since the last
+// sync and adds to the watchlist what was requested since then. Only
+// changes since the last sync move, so a title taken off either side is not
+// put back by the other; the first sync after linking moves everything.
+func (s *TraktService) syncWatchlist(ctx context.Context, client *trakt.Client, lastSync *time.Time, result *TraktSyncResult) error {
+	items, err := client.GetWatchlist(ctx)
+	if err != nil {
+		return err
+	}
+	onWatchlist := make(map[string]bool, len(items))
+	for _, item := range items {
+		mediaType, tmdbID := watchlistTarget(item)
+		if tmdbID == 0 {
+			continue
+		}
+		onWatchlist[requestKey(mediaType, tmdbID)] = true
+		if lastSync != nil && !item.ListedAt.After(*lastSync) {
+			continue
+		}
+
+		_, err := s.requests.CreateRequest(ctx, CreateMediaRequestRequest{TMDbID: tmdbID, MediaType: mediaType})
+		switch {
+		case err == nil:
+			result.RequestsCreated++
+		case errors.Is(err, ErrRequestAlreadyInLibrary), errors.Is(err, repository.ErrRequestDuplicate):
+		default:
+			s.logger.Warn("Failed to request Trakt watchlist title", "tmdb_id", tmdbID, "media_type", mediaType, "error", err)
+		}
+	}
+
+	active, err := s.requestStore.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("list active requests: %w", err)
+	}
+	var add trakt.SyncItems
+	for _, r := range active {
+		// Quality upgrades are of titles already owned, not wants.
+		if r.UpgradeCutoff.Valid || onWatchlist[requestKey(r.MediaType, r.TMDbID)] {
+			continue
+		}
+		if lastSync != nil && !r.RequestedAt.After(*lastSync) {
+			continue
+		}
+		ids := trakt.IDs{TMDb: r.TMDbID}
+		if r.MediaType == models.RequestMediaTypeTV {
+			add.Shows = append(add.Shows, trakt.SyncShow{IDs: ids})
+		} else {
+			add.Movies = append(add.Movies, trakt.SyncMovie{IDs: ids})
+		}
+	}
+	if len(add.Movies)+len(add.Shows) == 0 {
+		return nil
+	}
+	added, err := client.AddToWatchlist(ctx, add)
+	if err != nil {
+		return err
+	}
+	result.WatchlistAdded = added.Added.Movies + added.Added.Shows
+	return nil
+}
+
+func watchlistTarget(item trakt.WatchlistItem) (string, int64) {
+	switch {
+	case item.Movie != nil:
+		return models.RequestMediaTypeMovie, item.Movie.IDs.TMDb
+	case item.Show != nil:
+		return models.RequestMediaTypeTV, item.Show.IDs.TMDb
+	}
+	return "", 0
+}
+
+func requestKey(mediaType string, tmdbID int64) string {
+	return fmt.Sprintf("%s:%d", mediaType, tmdbID)
+}
+
+// exportCollection sends what is on disk as the Trakt collection: each movie
+// with a file, and each show with the episodes that have one.
+func (s *TraktService) exportCollection(ctx context.Context, client *trakt.Client, library *traktLibrary, result *TraktSyncResult) error {
+	var items trakt.SyncItems
+	for tmdbID, movie := range library.movies {
+		if !movie.FilePath.Valid || movie.FilePath.String == "" {
+			continue
+		}
+		collectedAt := movie.CreatedAt.UTC()
+		items.Movies = append(items.Movies, trakt.SyncMovie{IDs: trakt.IDs{TMDb: tmdbID}, CollectedAt: &collectedAt})
+	}
+	episodes := 0
+	for tmdbID, series := range library.series {
+		eps, err := s.episodes.FindBySeriesID(ctx, series.ID)
+		if err != nil {
+			return fmt.Errorf("list episodes of %s: %w", series.ID, err)
+		}
+		var seasons []trakt.SyncSeason
+		seasonIndex := map[int]int{}
+		for _, ep := range eps {
+			if !ep.FilePath.Valid || ep.FilePath.String == "" {
+				continue
+			}
+			i, ok := seasonIndex[ep.SeasonNumber]
+			if !ok {
+				i = len(seasons)
+				seasonIndex[ep.SeasonNumber] = i
+				seasons = append(seasons, trakt.SyncSeason{Number: ep.SeasonNumber})
+			}
+			seasons[i].Episodes = append(seasons[i].Episodes, trakt.SyncEpisode{Number: ep.EpisodeNumber})
+			episodes++
+		}
+		if len(seasons) > 0 {
+			items.Shows = append(items.Shows, trakt.SyncShow{IDs: trakt.IDs{TMDb: tmdbID}, Seasons: seasons})
+		}
+	}
+	if len(items.Movies)+len(items.Shows) == 0 {
+		return nil
+	}
+	if _, err := client.AddToCollection(ctx, items); err != nil {
+		return err
+	}
+	result.CollectionMovies = len(items.Movies)
+	result.CollectionEpisodes = episodes
+	return nil
+}
+
+// PlaybackProgress scrobbles the linked user's playback in the background;
+// a slow or unreachable Trakt never holds up a progress report.
+func (s *TraktService) PlaybackProgress(userID string, state models.WatchState, percent float64, finished bool) {
+	if !finished && !s.claimScrobble(state.MediaID) {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.scrobble(ctx, userID, state, percent, finished); err != nil && !errors.Is(err, ErrTraktNotConnected) {
+			s.logger.Debug("Trakt scrobble failed", "media_type", state.MediaType, "media_id", state.MediaID, "error", err)
+		}
+	}()
+}
+
+// claimScrobble throttles progress scrobbles of one item.
+func (s *TraktService) claimScrobble(mediaID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if last, ok := s.scrobbled[mediaID]; ok && now.Sub(last) < traktScrobbleEvery {
+		return false
+	}
+	s.scrobbled[mediaID] = now
+	return true
+}
+
+// scrobble reports one item: "start" while it plays, "stop" when it is
+// finished, which Trakt records as a play.
+func (s *TraktService) scrobble(ctx context.Context, userID string, state models.WatchState, percent float64, finished bool) error {
+	if exists, _ := s.secrets.Exists(ctx, secretTraktAccessToken); !exists {
+		return ErrTraktNotConnected
+	}
+	if linked, _ := s.settingsRepo.GetString(ctx, SettingTraktUserID); linked != userID {
+		return nil
+	}
+
+	item := trakt.ScrobbleItem{Progress: min(percent, 100)}
+	switch state.MediaType {
+	case models.WatchMediaMovie:
+		movie, err := s.movies.FindByID(ctx, state.MediaID)
+		if err != nil {
+			return err
+		}
+		if !movie.TMDbID.Valid {
+			return nil
+		}
+		item.Movie = &trakt.Media{IDs: trakt.IDs{TMDb: movie.TMDbID.Int64}}
+	case models.WatchMediaEpisode:
+		ep, err := s.episodes.FindByID(ctx, state.MediaID)
+		if err != nil {
+			return err
+		}
+		series, err := s.series.FindByID(ctx, ep.SeriesID)
+		if err != nil {
+			return err
+		}
+		if !series.TMDbID.Valid {
+			return nil
+		}
+		item.Show = &trakt.Media{IDs: trakt.IDs{TMDb: series.TMDbID.Int64}}
+		item.Episode = &trakt.ScrobbleEpisode{Season: ep.SeasonNumber, Number: ep.EpisodeNumber}
+	default:
+		return nil
+	}
+
+	client, err := s.userClient(ctx)
+	if err != nil {
+		return err
+	}
+	action := trakt.ScrobbleStart
+	if finished {
+		action = trakt.ScrobbleStop
+		s.mu.Lock()
+		delete(s.scrobbled, state.MediaID)
+		s.mu.Unlock()
+	}
+	return client.Scrobble(ctx, action, item)
+}
+
+// Compile-time interface verification
+var (
+	_ TraktServiceInterface = (*TraktService)(nil)
+	_ PlaybackObserver      = (*TraktService)(nil)
+)
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/trakt"
+)
+
+// fakeTraktAPI is a local stand-in for api.trakt.tv. Device codes "pending"
+// and "ok" answer the token poll; every write body is recorded by path.
+type fakeTraktAPI struct {
+	mu         sync.Mutex
+	deviceCode string
+	watchlist  string
+	writes     map[string][]json.RawMessage
+}
+
+func (f *fakeTraktAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("trakt-api-key") != "cid" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if r.Method == http.MethodPost {
+		var body json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.writes[r.URL.Path] = append(f.writes[r.URL.Path], body)
+	}
+	switch r.URL.Path {
+	case "/oauth/device/code":
+		fmt.Fprintf(w, `{"device_code":%q,"user_code":"ABCD1234","verification_url":"https://trakt.tv/activate","expires_in":600,"interval":5}`, f.deviceCode)
+	case "/oauth/device/token":
+		if f.deviceCode == "pending" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":"access","refresh_token":"refresh","expires_in":86400,"created_at":%d}`, time.Now().Unix())
+	case "/users/settings":
+		fmt.Fprint(w, `{"user":{"username":"sean"}}`)
+	case "/sync/watched/movies":
+		fmt.Fprint(w, `[{"plays":2,"last_watched_at":"2026-03-01T20:00:00.000Z","movie":{"ids":{"tmdb":27205}}},
+			{"plays":1,"last_watched_at":"2026-03-02T20:00:00.000Z","movie":{"ids":{"tmdb":1}}}]`)
+	case "/sync/watched/shows":
+		fmt.Fprint(w, `[{"plays":1,"show":{"ids":{"tmdb":95396}},"seasons":[{"number":1,"episodes":[{"number":2,"plays":1,"last_watched_at":"2026-03-03T20:00:00.000Z"}]}]}]`)
+	case "/sync/ratings/movies":
+		fmt.Fprint(w, `[{"rated_at":"2026-03-01T22:00:00.000Z","rating":9,"type":"movie","movie":{"ids":{"tmdb":27205}}}]`)
+	case "/sync/ratings/shows":
+		fmt.Fprint(w, `[{"rated_at":"2026-03-04T22:00:00.000Z","rating":8,"type":"show","show":{"ids":{"tmdb":95396}}}]`)
+	case "/sync/watchlist/movies":
+		fmt.Fprint(w, f.watchlist)
+	case "/sync/watchlist/shows":
+		fmt.Fprint(w, `[]`)
+	case "/sync/watchlist":
+		fmt.Fprint(w, `{"added":{"movies":1,"shows":0}}`)
+	case "/sync/collection":
+		fmt.Fprint(w, `{"added":{"movies":1,"episodes":2}}`)
+	case "/scrobble/start", "/scrobble/stop":
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeTraktAPI) written(path string) []json.RawMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.writes[path]
+}
+
+type fakeTraktRatings struct{ ratings map[string]*models.UserRating }
+
+func (f *fakeTraktRatings) Upsert(_ context.Context, r *models.UserRating) error {
+	f.ratings[r.MediaType+"/"+r.MediaID] = r
+	return nil
+}
+func (f *fakeTraktRatings) Get(_ context.Context, userID, mediaType, mediaID string) (*models.UserRating, error) {
+	return f.ratings[mediaType+"/"+mediaID], nil
+}
+func (f *fakeTraktRatings) ListByUser(context.Context, string) ([]models.UserRating, error) {
+	return nil, nil
+}
+
+// fakeTraktRequests records created requests and serves the active list.
+type fakeTraktRequests struct {
+	created []CreateMediaRequestRequest
+	active  []models.Request
+}
+
+func (f *fakeTraktRequests) CreateRequest(_ context.Context, req CreateMediaRequestRequest) (*models.Request, error) {
+	f.created = append(f.created, req)
+	return &models.Request{TMDbID: req.TMDbID, MediaType: req.MediaType}, nil
+}
+func (f *fakeTraktRequests) ListActive(context.Context) ([]models.Request, error) {
+	return f.active, nil
+}
+
+type traktFixture struct {
+	*watchFixture
+	svc      *TraktService
+	api      *fakeTraktAPI
+	secrets  *fakeSecrets
+	ratings  *fakeTraktRatings
+	requests *fakeTraktRequests
+}
+
+func setupTraktService(t *testing.T) *traktFixture {
+	t.Helper()
+	wf := setupWatchService(t)
+	api := &fakeTraktAPI{deviceCode: "ok", watchlist: `[]`, writes: map[string][]json.RawMessage{}}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	f := &traktFixture{
+		watchFixture: wf,
+		api:          api,
+		secrets:      &fakeSecrets{},
+		ratings:      &fakeTraktRatings{ratings: map[string]*models.UserRating{}},
+		requests:     &fakeTraktRequests{},
+	}
+	f.svc = NewTraktService(newFakeDVRSettingsRepo(), f.secrets, wf.movies, wf.series, wf.episodes,
+		wf.svc, f.ratings, f.requests, f.requests, nil)
+	f.svc.baseURL = server.URL
+	require.NoError(t, f.svc.SaveConfig(context.Background(), TraktConfigInput{ClientID: "cid", ClientSecret: "csecret"}))
+	return f
+}
+
+func (f *traktFixture) link(t *testing.T, userID string) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := f.svc.StartDeviceAuth(ctx, userID)
+	require.NoError(t, err)
+	_, err = f.svc.PollDeviceAuth(ctx)
+	require.NoError(t, err)
+}
+
+func TestTraktService_DeviceAuth(t *testing.T) {
+	f := setupTraktService(t)
+	ctx := context.Background()
+
+	_, err := f.svc.PollDeviceAuth(ctx)
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr, "nothing to poll before a code is requested")
+
+	f.api.deviceCode = "pending"
+	code, err := f.svc.StartDeviceAuth(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "ABCD1234", code.UserCode)
+	_, err = f.svc.PollDeviceAuth(ctx)
+	assert.ErrorIs(t, err, ErrTraktAuthorizationPending)
+
+	f.api.deviceCode = "ok"
+	_, err = f.svc.StartDeviceAuth(ctx, "u1")
+	require.NoError(t, err)
+	status, err := f.svc.PollDeviceAuth(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.Connected)
+	assert.Equal(t, "sean", status.Username)
+	assert.Equal(t, "u1", status.UserID)
+	assert.Equal(t, "access", f.secrets.values[secretTraktAccessToken], "tokens live in the secrets store")
+
+	require.NoError(t, f.svc.Disconnect(ctx))
+	status, err = f.svc.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.False(t, status.Connected)
+	assert.True(t, status.Configured, "unlinking keeps the application")
+	assert.Len(t, f.api.written("/oauth/revoke"), 1)
+}
+
+func TestTraktService_Sync(t *testing.T) {
+	f := setupTraktService(t)
+	ctx := context.Background()
+	require.NoError(t, f.movies.Create(ctx, &models.Movie{
+		ID: "m1", Title: "Inception", TMDbID: models.NewNullInt64(27205), FilePath: models.NewNullString("/movies/Inception.mkv"),
+	}))
+	f.addShow(t, "s1", [2]int{1, 1}, [2]int{1, 2})
+	series, err := f.series.FindByID(ctx, "s1")
+	require.NoError(t, err)
+	series.TMDbID = models.NewNullInt64(95396)
+	require.NoError(t, f.series.Update(ctx, series))
+
+	f.api.watchlist = `[{"listed_at":"2026-01-01T00:00:00.000Z","type":"movie","movie":{"ids":{"tmdb":438631}}}]`
+	f.requests.active = []models.Request{
+		{TMDbID: 603, MediaType: models.RequestMediaTypeMovie, RequestedAt: time.Now()},
+		{TMDbID: 438631, MediaType: models.RequestMediaTypeMovie, RequestedAt: time.Now()},
+		{TMDbID: 27205, MediaType: models.RequestMediaTypeMovie, RequestedAt: time.Now(), UpgradeCutoff: models.NewNullString("2160p")},
+	}
+	f.link(t, "")
+
+	result, err := f.svc.Sync(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.HistoryImported)
+	assert.Equal(t, 1, result.HistoryUnmatched, "a movie not in the library")
+	state, err := f.watchFixture.svc.GetState(ctx, "", models.WatchMediaMovie, "m1")
+	require.NoError(t, err)
+	assert.True(t, state.Watched)
+	assert.Equal(t, 2, state.WatchCount)
+	state, err = f.watchFixture.svc.GetState(ctx, "", models.WatchMediaEpisode, "s1-s1e2")
+	require.NoError(t, err)
+	assert.True(t, state.Watched)
+
+	assert.Equal(t, 2, result.RatingsImported)
+	assert.Equal(t, 9, f.ratings.ratings["movie/m1"].Rating)
+	assert.Equal(t, 8, f.ratings.ratings["series/s1"].Rating)
+
+	require.Len(t, f.requests.created, 1)
+	assert.Equal(t, CreateMediaRequestRequest{TMDbID: 438631, MediaType: "movie"}, f.requests.created[0])
+	watchlistWrites := f.api.written("/sync/watchlist")
+	require.Len(t, watchlistWrites, 1)
+	assert.JSONEq(t, `{"movies":[{"ids":{"tmdb":603}}]}`, string(watchlistWrites[0]),
+		"only requests missing from the watchlist, and no quality upgrades")
+
+	collection := f.api.written("/sync/collection")
+	require.Len(t, collection, 1)
+	var sent trakt.SyncItems
+	require.NoError(t, json.Unmarshal(collection[0], &sent))
+	require.Len(t, sent.Movies, 1)
+	assert.Equal(t, int64(27205), sent.Movies[0].IDs.TMDb)
+	require.Len(t, sent.Shows, 1)
+	assert.Equal(t, []trakt.SyncSeason{{Number: 1, Episodes: []trakt.SyncEpisode{{Number: 1}, {Number: 2}}}}, sent.Shows[0].Seasons)
+	assert.Equal(t, 2, result.CollectionEpisodes)
+
+	t.Run("later syncs only move what changed", func(t *testing.T) {
+		f.requests.created = nil
+		result, err := f.svc.Sync(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, f.requests.created, "the watchlist entry predates the last sync")
+		assert.Zero(t, result.WatchlistAdded)
+		assert.Zero(t, result.HistoryImported)
+		assert.Equal(t, 2, result.HistoryUnchanged)
+	})
+}
+
+func TestTraktService_Scrobble(t *testing.T) {
+	f := setupTraktService(t)
+	ctx := context.Background()
+	f.addShow(t, "s1", [2]int{1, 3})
+	series, err := f.series.FindByID(ctx, "s1")
+	require.NoError(t, err)
+	series.TMDbID = models.NewNullInt64(95396)
+	require.NoError(t, f.series.Update(ctx, series))
+	f.link(t, "u1")
+
+	state := models.WatchState{MediaType: models.WatchMediaEpisode, MediaID: "s1-s1e3"}
+	require.NoError(t, f.svc.scrobble(ctx, "u2", state, 40, false))
+	assert.Empty(t, f.api.written("/scrobble/start"), "only the linked user is scrobbled")
+
+	require.NoError(t, f.svc.scrobble(ctx, "u1", state, 100, true))
+	stops := f.api.written("/scrobble/stop")
+	require.Len(t, stops, 1)
+	assert.JSONEq(t, `{"show":{"ids":{"tmdb":95396}},"episode":{"season":1,"number":3},"progress":100}`, string(stops[0]))
+
+	assert.True(t, f.svc.claimScrobble("m1"))
+	assert.False(t, f.svc.claimScrobble("m1"), "progress scrobbles are throttled")
+}
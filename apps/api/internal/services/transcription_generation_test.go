@@ -72,6 +72,14 @@ func TestRunTranscription_Disabled(t *testing.T) {
 	assert.ErrorIs(t, err, ErrTranscriptionDisabled)
 }
 
+// TestTranscribeReference_Disabled — an alignment reference is refused on an
+// install without ASR exactly like a full transcription.
+func TestTranscribeReference_Disabled(t *testing.T) {
+	svc := NewTranscriptionService(nil, nil, nil, nil)
+	_, err := svc.TranscribeReference(context.Background(), uuidA, "/test.mkv")
+	assert.ErrorIs(t, err, ErrTranscriptionDisabled)
+}
+
 func TestRunTranscription_SharesSingleFlightMapWithAsyncPath(t *testing.T) {
 	extractor := &AudioExtractorService{available: true, semaphore: make(chan struct{}, 1)}
 	whisperClient := ai.NewWhisperClient("test-key")
@@ -124,7 +124,8 @@ type TranscriptionService struct {
 	sseHub             *sse.Hub
 	logger             *slog.Logger
 	timeout            time.Duration
-	runBudgetUSD       float64 // 9R-11: per-run AI cost ceiling (0 = unlimited)
+	runBudgetUSD       float64       // 9R-11: per-run AI cost ceiling (0 = unlimited)
+	spendGuard         ai.SpendGuard // global daily/monthly caps (nil = none)
 
 	// 9R-10 pipeline dependencies (all optional / nil-safe).
 	glossaryRepo repository.GlossaryRepositoryInterface // per-show glossary (9R-6/7)
@@ -186,6 +187,21 @@ func (s *TranscriptionService) SetRunBudgetUSD(usd float64) {
 	s.runBudgetUSD = usd
 }
 
+// SetSpendGuard wires the global AI spend caps. A run is refused before it
+// starts once a cap is used up; the ai package's own pre-call check still
+// stops a run that crosses the cap midway. Nil-safe.
+func (s *TranscriptionService) SetSpendGuard(g ai.SpendGuard) {
+	s.spendGuard = g
+}
+
+// checkSpend refuses new work once a global spend cap is used up.
+func (s *TranscriptionService) checkSpend(ctx context.Context) error {
+	if s.spendGuard == nil {
+		return nil
+	}
+	return s.spendGuard.CheckSpend(ctx)
+}
+
 // SetGlossaryRepository wires the per-show glossary (Story 9R-10). When set,
 // translation is glossary-aware (proper nouns render consistently). Nil-safe.
 func (s *TranscriptionService) SetGlossaryRepository(repo repository.GlossaryRepositoryInterface) {
@@ -301,6 +317,9 @@ func (s *TranscriptionService) StartTranscription(ctx context.Context, mediaID s
 	if !s.IsAvailable() && !(cfg.translate && s.canResumeTranslateOnly(ctx, cfg.mediaType, mediaID)) {
 		return "", ErrTranscriptionDisabled
 	}
+	if err := s.checkSpend(ctx); err != nil {
+		return "", err
+	}
 
 	jobID, err := s.acquireJob(mediaID)
 	if err != nil {
@@ -335,6 +354,9 @@ func (s *TranscriptionService) RunTranscription(ctx context.Context, mediaID str
 	if !s.IsAvailable() && !(cfg.translate && s.canResumeTranslateOnly(ctx, cfg.mediaType, mediaID)) {
 		return ErrTranscriptionDisabled
 	}
+	if err := s.checkSpend(ctx); err != nil {
+		return err
+	}
 
 	jobID, err := s.acquireJob(mediaID)
 	if err != nil {
@@ -346,6 +368,46 @@ func (s *TranscriptionService) RunTranscription(ctx context.Context, mediaID str
 	return s.runPipeline(pipelineCtx, jobID, cfg.mediaType, mediaID, filePath, mediaDir, cfg.translate)
 }
 
+// TranscribeReference transcribes a media file's English audio for subtitle
+// timing alignment and returns the SRT — nothing is written, broadcast, or
+// persisted. Only the segment boundaries matter to the caller, so the text is
+// whatever the ASR heard. It spends from the same per-run budget and global
+// caps as a full transcription; whether a caller may spend at all is the
+// caller's consent decision, not this method's.
+func (s *TranscriptionService) TranscribeReference(ctx context.Context, mediaID, filePath string) (string, error) {
+	if !s.IsAvailable() {
+		return "", ErrTranscriptionDisabled
+	}
+	if err := s.checkSpend(ctx); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	_, ctx = s.resolveBudget(ctx)
+	ctx = ai.WithUsageMedia(ctx, mediaID)
+
+	tracks, err := s.audioExtractor.ListAudioTracks(ctx, filePath)
+	if err != nil {
+		return "", fmt.Errorf("list audio tracks: %w", err)
+	}
+	track, err := SelectEnglishTrack(tracks)
+	if err != nil {
+		return "", fmt.Errorf("select audio track: %w", err)
+	}
+	audioPath, err := s.audioExtractor.ExtractAudio(ctx, filePath, track.Index)
+	if err != nil {
+		return "", fmt.Errorf("extract audio: %w", err)
+	}
+	defer os.Remove(audioPath)
+
+	srt, err := s.transcribeAudio(ctx, audioPath, WhisperLanguageFromTrack(track.Language))
+	if err != nil {
+		return "", fmt.Errorf("transcribe: %w", err)
+	}
+	return srt, nil
+}
+
 // resolveBudget returns the ctx-attached ai.Budget when one is present (9R-16
 // AC 6b — a generation batch attaches ONE shared Budget so the whole batch
 // spends from one envelope), else creates the per-run budget as before (9R-11)
@@ -439,6 +501,7 @@ func (s *TranscriptionService) runPipeline(ctx context.Context, jobID string, me
 	// 9R-11: one per-run budget spans BOTH transcription and translation of
 	// this media so ASR + LLM share the ceiling; logged at the end.
 	budget, ctx := s.resolveBudget(ctx)
+	ctx = ai.WithUsageMedia(ai.WithUsageRun(ctx, jobID), mediaID)
 	defer func() {
 		snap := budget.Snapshot()
 		s.logger.Info("transcription run AI usage",
@@ -319,7 +319,7 @@ func (s *TranslationService) TranslateWithGlossaryHarvest(ctx context.Context, b
 		// Call Claude API
 		userPrompt := prompts.BuildSubtitleTranslatorPromptWithGlossary(promptBlocks, contextBlocks, promptGlossary)
 
-		batchCtx, batchCancel := context.WithTimeout(ctx, TranslationTimeout)
+		batchCtx, batchCancel := context.WithTimeout(ai.WithUsageFeature(ctx, ai.FeatureTranslate), TranslationTimeout)
 		translated, err := s.provider.CompleteText(
 			batchCtx,
 			systemPrompt,
@@ -440,7 +440,7 @@ func (s *TranslationService) TranslateRequest(ctx context.Context, req Translati
 
 	userPrompt := prompts.BuildSubtitleTranslatorPromptWithGlossary(promptBlocks, nil, toPromptGlossary(req.Glossary))
 
-	batchCtx, cancel := context.WithTimeout(ctx, TranslationTimeout)
+	batchCtx, cancel := context.WithTimeout(ai.WithUsageFeature(ctx, ai.FeatureTranslate), TranslationTimeout)
 	defer cancel()
 	translated, err := s.provider.CompleteText(batchCtx, prompts.SubtitleTranslatorSystemPrompt, userPrompt, TranslationMaxTokens)
 	if err != nil {
@@ -488,7 +488,7 @@ func (s *TranslationService) TranslateChunk(ctx context.Context, sys []ai.System
 	}
 	userPrompt := prompts.BuildSubtitleTranslatorPrompt(blocks, contextBlocks)
 
-	chunkCtx, cancel := context.WithTimeout(ctx, TranslationTimeout)
+	chunkCtx, cancel := context.WithTimeout(ai.WithUsageFeature(ctx, ai.FeatureTranslate), TranslationTimeout)
 	defer cancel()
 
 	// Claude implements CachingCompleter, so usage — including both cache
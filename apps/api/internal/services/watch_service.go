@@ -0,0 +1,545 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+)
+
+const (
+	// watchedThreshold is the played fraction at which an item counts as
+	// watched: end credits are rarely sat through.
+	watchedThreshold = 0.9
+
+	// continueWatchingScan bounds how much recent history "continue watching"
+	// reads to fill its rows.
+	continueWatchingScan = 200
+)
+
+// ErrInvalidWatchProgress is returned for a negative or impossible position.
+var ErrInvalidWatchProgress = errors.New("invalid watch progress")
+
+// watchStateStore is the narrow port over the watch_state repository.
+type watchStateStore interface {
+	Get(ctx context.Context, userID, mediaType, mediaID string) (*models.WatchState, error)
+	Upsert(ctx context.Context, state *models.WatchState) error
+	ListBySeries(ctx context.Context, userID, seriesID string) ([]models.WatchState, error)
+	ListRecent(ctx context.Context, userID string, limit int) ([]models.WatchState, error)
+}
+
+type watchMovieFinder interface {
+	FindByID(ctx context.Context, id string) (*models.Movie, error)
+}
+
+type watchSeriesFinder interface {
+	FindByID(ctx context.Context, id string) (*models.Series, error)
+}
+
+type watchEpisodeFinder interface {
+	FindByID(ctx context.Context, id string) (*models.Episode, error)
+	FindBySeriesID(ctx context.Context, seriesID string) ([]models.Episode, error)
+}
+
+// PlaybackObserver hears about playback as it is recorded: each progress
+// report while an item plays, and the moment it becomes watched. It is
+// called inline and must not block; Trakt scrobbling is the observer.
+type PlaybackObserver interface {
+	PlaybackProgress(userID string, state models.WatchState, percent float64, finished bool)
+}
+
+// SeriesWatchProgress summarises a user's progress through a show.
+type SeriesWatchProgress struct {
+	SeriesID        string          `json:"series_id"`
+	TotalEpisodes   int             `json:"total_episodes"`
+	WatchedEpisodes int             `json:"watched_episodes"`
+	Status          string          `json:"status"`
+	NextEpisode     *models.Episode `json:"next_episode,omitempty"`
+}
+
+// ContinueWatchingItem is one row of "continue watching": a part-played movie
+// or the next episode of a show in progress.
+type ContinueWatchingItem struct {
+	Type         string             `json:"type"` // "movie" | "episode"
+	Movie        *models.Movie      `json:"movie,omitempty"`
+	Series       *models.Series     `json:"series,omitempty"`
+	Episode      *models.Episode    `json:"episode,omitempty"`
+	State        *models.WatchState `json:"state,omitempty"`
+	LastActivity time.Time          `json:"last_activity"`
+}
+
+// WatchService records per-user play progress and watched flags, and answers
+// "what next" questions from them. userID is "" on an install without
+// accounts, which shares one household history.
+type WatchService struct {
+	states   watchStateStore
+	movies   watchMovieFinder
+	series   watchSeriesFinder
+	episodes watchEpisodeFinder
+	observer PlaybackObserver
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// NewWatchService creates a WatchService.
+func NewWatchService(states watchStateStore, movies watchMovieFinder, series watchSeriesFinder, episodes watchEpisodeFinder, logger *slog.Logger) *WatchService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &WatchService{
+		states:   states,
+		movies:   movies,
+		series:   series,
+		episodes: episodes,
+		now:      time.Now,
+		logger:   logger.With("service", "watch"),
+	}
+}
+
+// SetPlaybackObserver wires the optional playback observer.
+func (s *WatchService) SetPlaybackObserver(o PlaybackObserver) {
+	s.observer = o
+}
+
+// GetState returns the user's state for an item; an item never played gets a
+// zero state rather than an error.
+func (s *WatchService) GetState(ctx context.Context, userID, mediaType, mediaID string) (*models.WatchState, error) {
+	seriesID, err := s.resolveItem(ctx, mediaType, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadState(ctx, userID, mediaType, mediaID, seriesID)
+}
+
+// ReportProgress records a player's position. Crossing watchedThreshold marks
+// the item watched, bumps its watch count and resets the position, so the
+// next play starts from the beginning.
+func (s *WatchService) ReportProgress(ctx context.Context, userID, mediaType, mediaID string, position, duration float64) (*models.WatchState, error) {
+	if position < 0 || duration < 0 || (duration > 0 && position > duration*1.05) {
+		return nil, fmt.Errorf("%w: position %.0fs of %.0fs", ErrInvalidWatchProgress, position, duration)
+	}
+	seriesID, err := s.resolveItem(ctx, mediaType, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.loadState(ctx, userID, mediaType, mediaID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	if duration > 0 {
+		state.DurationSeconds = duration
+	}
+	finished := state.DurationSeconds > 0 && position >= state.DurationSeconds*watchedThreshold
+	marked := false
+	if finished {
+		// Only a play that was actually under way counts. Reports that keep
+		// arriving through the credits after completion find the position
+		// already reset and an item already watched.
+		if !state.Watched || state.PositionSeconds > 0 {
+			s.markWatched(state)
+			marked = true
+		}
+	} else {
+		state.PositionSeconds = position
+	}
+
+	if err := s.states.Upsert(ctx, state); err != nil {
+		return nil, err
+	}
+	switch {
+	case marked:
+		s.notifyPlayback(userID, state, 100, true)
+	case !finished && state.DurationSeconds > 0:
+		s.notifyPlayback(userID, state, position/state.DurationSeconds*100, false)
+	}
+	return state, nil
+}
+
+// SetWatched marks an item watched or unwatched by hand. Marking watched
+// counts as one more view unless it already was; unwatching clears the flag
+// and any position but keeps the view count as history.
+func (s *WatchService) SetWatched(ctx context.Context, userID, mediaType, mediaID string, watched bool) (*models.WatchState, error) {
+	seriesID, err := s.resolveItem(ctx, mediaType, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.loadState(ctx, userID, mediaType, mediaID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	views := state.WatchCount
+	if err := s.applyWatched(ctx, state, watched); err != nil {
+		return nil, err
+	}
+	if state.WatchCount > views {
+		s.notifyPlayback(userID, state, 100, true)
+	}
+	return state, nil
+}
+
+// ExternalWatchState is play state read from another player, such as a
+// Jellyfin or Emby server.
+type ExternalWatchState struct {
+	Watched         bool
+	PlayCount       int
+	PositionSeconds float64
+	DurationSeconds float64
+	LastPlayedAt    *time.Time
+}
+
+// MergeExternalState folds another player's state into the user's history
+// without losing anything recorded here: the watched flag is only ever added,
+// the view count only grows, and a resume position is taken only when the
+// other player saw the item more recently. It reports whether anything changed.
+func (s *WatchService) MergeExternalState(ctx context.Context, userID, mediaType, mediaID string, ext ExternalWatchState) (bool, error) {
+	seriesID, err := s.resolveItem(ctx, mediaType, mediaID)
+	if err != nil {
+		return false, err
+	}
+	state, err := s.loadState(ctx, userID, mediaType, mediaID, seriesID)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	if ext.DurationSeconds > 0 && state.DurationSeconds == 0 {
+		state.DurationSeconds = ext.DurationSeconds
+		changed = true
+	}
+	if ext.Watched {
+		if !state.Watched {
+			state.Watched = true
+			changed = true
+		}
+		if count := max(ext.PlayCount, 1); count > state.WatchCount {
+			state.WatchCount = count
+			changed = true
+		}
+		if ext.LastPlayedAt != nil && (state.LastWatchedAt == nil || ext.LastPlayedAt.After(*state.LastWatchedAt)) {
+			at := ext.LastPlayedAt.UTC()
+			state.LastWatchedAt = &at
+			changed = true
+		}
+	}
+	if ext.PositionSeconds > 0 && ext.PositionSeconds != state.PositionSeconds {
+		newer := state.UpdatedAt.IsZero() || (ext.LastPlayedAt != nil && ext.LastPlayedAt.After(state.UpdatedAt))
+		if newer {
+			state.PositionSeconds = ext.PositionSeconds
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+	if err := s.states.Upsert(ctx, state); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetSeriesWatched marks every episode on disk of a show — or of one season
+// when season is not nil — watched or unwatched.
+func (s *WatchService) SetSeriesWatched(ctx context.Context, userID, seriesID string, season *int, watched bool) (*SeriesWatchProgress, error) {
+	episodes, err := s.seriesEpisodes(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	states, err := s.seriesStates(ctx, userID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range episodes {
+		ep := &episodes[i]
+		if season != nil && ep.SeasonNumber != *season {
+			continue
+		}
+		state, ok := states[ep.ID]
+		if !ok {
+			state = s.newState(userID, models.WatchMediaEpisode, ep.ID, seriesID)
+			states[ep.ID] = state
+		}
+		if state.Watched == watched && !state.InProgress() {
+			continue
+		}
+		if err := s.applyWatched(ctx, state, watched); err != nil {
+			return nil, err
+		}
+	}
+	return s.progress(seriesID, episodes, states), nil
+}
+
+// SeriesProgress reports how far the user is through a show and which episode
+// is up next.
+func (s *WatchService) SeriesProgress(ctx context.Context, userID, seriesID string) (*SeriesWatchProgress, error) {
+	episodes, err := s.seriesEpisodes(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	states, err := s.seriesStates(ctx, userID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return s.progress(seriesID, episodes, states), nil
+}
+
+// NextEpisode returns the episode to play next, or nil when the show is
+// finished (or has nothing on disk).
+func (s *WatchService) NextEpisode(ctx context.Context, userID, seriesID string) (*models.Episode, error) {
+	progress, err := s.SeriesProgress(ctx, userID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return progress.NextEpisode, nil
+}
+
+// ContinueWatching lists part-played movies and the next episode of every
+// show in progress, most recent activity first.
+func (s *WatchService) ContinueWatching(ctx context.Context, userID string, limit int) ([]ContinueWatchingItem, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	recent, err := s.states.ListRecent(ctx, userID, continueWatchingScan)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []ContinueWatchingItem{}
+	seenSeries := make(map[string]bool)
+	for i := range recent {
+		if len(items) >= limit {
+			break
+		}
+		state := &recent[i]
+		switch state.MediaType {
+		case models.WatchMediaMovie:
+			if !state.InProgress() {
+				continue
+			}
+			movie, err := s.movies.FindByID(ctx, state.MediaID)
+			if err != nil {
+				continue // deleted since it was played
+			}
+			items = append(items, ContinueWatchingItem{
+				Type: models.WatchMediaMovie, Movie: movie, State: state, LastActivity: state.UpdatedAt,
+			})
+		case models.WatchMediaEpisode:
+			seriesID := state.SeriesID.String
+			if !state.SeriesID.Valid || seenSeries[seriesID] {
+				continue
+			}
+			seenSeries[seriesID] = true
+			item, ok := s.nextEpisodeItem(ctx, userID, seriesID, state.UpdatedAt)
+			if ok {
+				items = append(items, item)
+			}
+		}
+	}
+	return items, nil
+}
+
+func (s *WatchService) nextEpisodeItem(ctx context.Context, userID, seriesID string, lastActivity time.Time) (ContinueWatchingItem, bool) {
+	series, err := s.series.FindByID(ctx, seriesID)
+	if err != nil {
+		return ContinueWatchingItem{}, false
+	}
+	progress, err := s.SeriesProgress(ctx, userID, seriesID)
+	if err != nil {
+		s.logger.Warn("failed to compute series progress", "series_id", seriesID, "error", err)
+		return ContinueWatchingItem{}, false
+	}
+	if progress.NextEpisode == nil {
+		return ContinueWatchingItem{}, false
+	}
+	state, err := s.loadState(ctx, userID, models.WatchMediaEpisode, progress.NextEpisode.ID, seriesID)
+	if err != nil {
+		return ContinueWatchingItem{}, false
+	}
+	return ContinueWatchingItem{
+		Type:         models.WatchMediaEpisode,
+		Series:       series,
+		Episode:      progress.NextEpisode,
+		State:        state,
+		LastActivity: lastActivity,
+	}, true
+}
+
+// progress derives a show's status and next episode. The next episode is the
+// most recently part-played one if any, otherwise the first unwatched episode
+// after the furthest one watched — so skipping ahead does not send the user
+// back to an episode they chose to skip — wrapping to the first unwatched
+// episode overall.
+func (s *WatchService) progress(seriesID string, episodes []models.Episode, states map[string]*models.WatchState) *SeriesWatchProgress {
+	p := &SeriesWatchProgress{SeriesID: seriesID, Status: models.WatchStatusUnwatched}
+
+	furthest := -1
+	var resume *models.Episode
+	var resumeAt time.Time
+	touched := false
+	for i := range episodes {
+		ep := &episodes[i]
+		state := states[ep.ID]
+		if state != nil && (state.Watched || state.InProgress()) {
+			touched = true
+		}
+		if !countsTowardProgress(ep) {
+			continue
+		}
+		p.TotalEpisodes++
+		if state == nil {
+			continue
+		}
+		if state.Watched {
+			p.WatchedEpisodes++
+			furthest = i
+		}
+		if state.InProgress() && (resume == nil || state.UpdatedAt.After(resumeAt)) {
+			resume, resumeAt = ep, state.UpdatedAt
+		}
+	}
+
+	switch {
+	case p.TotalEpisodes > 0 && p.WatchedEpisodes == p.TotalEpisodes:
+		p.Status = models.WatchStatusWatched
+	case touched:
+		p.Status = models.WatchStatusInProgress
+	}
+
+	if resume != nil {
+		p.NextEpisode = resume
+		return p
+	}
+	p.NextEpisode = firstUnwatched(episodes, states, furthest+1)
+	if p.NextEpisode == nil {
+		p.NextEpisode = firstUnwatched(episodes, states, 0)
+	}
+	return p
+}
+
+func firstUnwatched(episodes []models.Episode, states map[string]*models.WatchState, from int) *models.Episode {
+	for i := from; i < len(episodes); i++ {
+		ep := &episodes[i]
+		if !countsTowardProgress(ep) {
+			continue
+		}
+		if state := states[ep.ID]; state == nil || !state.Watched {
+			return ep
+		}
+	}
+	return nil
+}
+
+// countsTowardProgress keeps specials (season 0) and episodes without a file
+// out of progress and "up next": neither can be binged in order.
+func countsTowardProgress(ep *models.Episode) bool {
+	return ep.SeasonNumber > 0 && ep.FilePath.Valid && ep.FilePath.String != ""
+}
+
+func (s *WatchService) applyWatched(ctx context.Context, state *models.WatchState, watched bool) error {
+	if watched {
+		if !state.Watched || state.InProgress() {
+			s.markWatched(state)
+		}
+	} else {
+		state.Watched = false
+		state.PositionSeconds = 0
+	}
+	return s.states.Upsert(ctx, state)
+}
+
+func (s *WatchService) notifyPlayback(userID string, state *models.WatchState, percent float64, finished bool) {
+	if s.observer != nil {
+		s.observer.PlaybackProgress(userID, *state, percent, finished)
+	}
+}
+
+func (s *WatchService) markWatched(state *models.WatchState) {
+	now := s.now()
+	state.Watched = true
+	state.WatchCount++
+	state.LastWatchedAt = &now
+	state.PositionSeconds = 0
+}
+
+func (s *WatchService) seriesEpisodes(ctx context.Context, seriesID string) ([]models.Episode, error) {
+	if _, err := s.series.FindByID(ctx, seriesID); err != nil {
+		return nil, mapWatchLookupError(err)
+	}
+	episodes, err := s.episodes.FindBySeriesID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	// The repository already orders by season/episode; sorting again keeps
+	// "next" correct whatever backs the port.
+	sort.SliceStable(episodes, func(i, j int) bool {
+		if episodes[i].SeasonNumber != episodes[j].SeasonNumber {
+			return episodes[i].SeasonNumber < episodes[j].SeasonNumber
+		}
+		return episodes[i].EpisodeNumber < episodes[j].EpisodeNumber
+	})
+	return episodes, nil
+}
+
+func (s *WatchService) seriesStates(ctx context.Context, userID, seriesID string) (map[string]*models.WatchState, error) {
+	list, err := s.states.ListBySeries(ctx, userID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]*models.WatchState, len(list))
+	for i := range list {
+		states[list[i].MediaID] = &list[i]
+	}
+	return states, nil
+}
+
+// resolveItem checks the item exists and returns its series id (episodes only).
+func (s *WatchService) resolveItem(ctx context.Context, mediaType, mediaID string) (string, error) {
+	switch mediaType {
+	case models.WatchMediaMovie:
+		if _, err := s.movies.FindByID(ctx, mediaID); err != nil {
+			return "", mapWatchLookupError(err)
+		}
+		return "", nil
+	case models.WatchMediaEpisode:
+		ep, err := s.episodes.FindByID(ctx, mediaID)
+		if err != nil {
+			return "", mapWatchLookupError(err)
+		}
+		return ep.SeriesID, nil
+	default:
+		return "", &models.ValidationError{Field: "media_type", Message: "media type must be movie or episode"}
+	}
+}
+
+func (s *WatchService) loadState(ctx context.Context, userID, mediaType, mediaID, seriesID string) (*models.WatchState, error) {
+	state, err := s.states.Get(ctx, userID, mediaType, mediaID)
+	if errors.Is(err, repository.ErrWatchStateNotFound) {
+		return s.newState(userID, mediaType, mediaID, seriesID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *WatchService) newState(userID, mediaType, mediaID, seriesID string) *models.WatchState {
+	state := &models.WatchState{UserID: userID, MediaType: mediaType, MediaID: mediaID}
+	if seriesID != "" {
+		state.SeriesID = models.NewNullString(seriesID)
+	}
+	return state
+}
+
+func mapWatchLookupError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, repository.ErrEpisodeNotFound) {
+		return ErrMediaNotFound
+	}
+	return err
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/database/migrations"
+	"github.com/vido/api/internal/models"
+	"github.com/vido/api/internal/repository"
+	_ "modernc.org/sqlite"
+)
+
+type watchFixture struct {
+	svc      *WatchService
+	movies   *repository.MovieRepository
+	series   *repository.SeriesRepository
+	episodes *repository.EpisodeRepository
+}
+
+func setupWatchService(t *testing.T) *watchFixture {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	runner, err := migrations.NewRunner(db)
+	require.NoError(t, err)
+	require.NoError(t, runner.RegisterAll(migrations.GetAll()))
+	require.NoError(t, runner.Up(context.Background()))
+
+	f := &watchFixture{
+		movies:   repository.NewMovieRepository(db),
+		series:   repository.NewSeriesRepository(db),
+		episodes: repository.NewEpisodeRepository(db),
+	}
+	f.svc = NewWatchService(repository.NewWatchStateRepository(db), f.movies, f.series, f.episodes, nil)
+	return f
+}
+
+// addShow creates a series with the given episodes, "SxE" codes in order.
+func (f *watchFixture) addShow(t *testing.T, id string, codes ...[2]int) {
+	t.Helper()
+	ctx := context.Background()
+	require.NoError(t, f.series.Create(ctx, &models.Series{ID: id, Title: id}))
+	for _, c := range codes {
+		require.NoError(t, f.episodes.Create(ctx, &models.Episode{
+			ID: fmt.Sprintf("%s-s%de%d", id, c[0], c[1]), SeriesID: id,
+			SeasonNumber: c[0], EpisodeNumber: c[1],
+			FilePath: models.NewNullString(fmt.Sprintf("/tv/%s/S%02dE%02d.mkv", id, c[0], c[1])),
+		}))
+	}
+}
+
+func TestWatchService_ProgressCompletesAtThreshold(t *testing.T) {
+	f := setupWatchService(t)
+	ctx := context.Background()
+	require.NoError(t, f.movies.Create(ctx, &models.Movie{ID: "m1", Title: "Movie"}))
+
+	state, err := f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "m1", 600, 6000)
+	require.NoError(t, err)
+	assert.True(t, state.InProgress())
+	assert.False(t, state.Watched)
+
+	state, err = f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "m1", 5500, 6000)
+	require.NoError(t, err)
+	assert.True(t, state.Watched)
+	assert.Equal(t, 1, state.WatchCount)
+	assert.Zero(t, state.PositionSeconds, "a finished item restarts from the beginning")
+	assert.NotNil(t, state.LastWatchedAt)
+
+	t.Run("reports through the credits do not count again", func(t *testing.T) {
+		state, err := f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "m1", 5800, 6000)
+		require.NoError(t, err)
+		assert.Equal(t, 1, state.WatchCount)
+	})
+
+	t.Run("a rewatch counts", func(t *testing.T) {
+		_, err := f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "m1", 100, 6000)
+		require.NoError(t, err)
+		state, err := f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "m1", 5900, 6000)
+		require.NoError(t, err)
+		assert.Equal(t, 2, state.WatchCount)
+	})
+
+	t.Run("rejects impossible positions and unknown items", func(t *testing.T) {
+		_, err := f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "m1", -1, 6000)
+		assert.ErrorIs(t, err, ErrInvalidWatchProgress)
+		_, err = f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "missing", 10, 6000)
+		assert.ErrorIs(t, err, ErrMediaNotFound)
+	})
+}
+
+func TestWatchService_SetWatchedIsPerUser(t *testing.T) {
+	f := setupWatchService(t)
+	ctx := context.Background()
+	require.NoError(t, f.movies.Create(ctx, &models.Movie{ID: "m1", Title: "Movie"}))
+
+	state, err := f.svc.SetWatched(ctx, "alice", models.WatchMediaMovie, "m1", true)
+	require.NoError(t, err)
+	assert.True(t, state.Watched)
+
+	other, err := f.svc.GetState(ctx, "bob", models.WatchMediaMovie, "m1")
+	require.NoError(t, err)
+	assert.False(t, other.Watched)
+
+	state, err = f.svc.SetWatched(ctx, "alice", models.WatchMediaMovie, "m1", false)
+	require.NoError(t, err)
+	assert.False(t, state.Watched)
+	assert.Equal(t, 1, state.WatchCount, "unwatching keeps the view history")
+}
+
+func TestWatchService_NextEpisode(t *testing.T) {
+	f := setupWatchService(t)
+	ctx := context.Background()
+	f.addShow(t, "show", [2]int{0, 1}, [2]int{1, 1}, [2]int{1, 2}, [2]int{1, 3}, [2]int{2, 1})
+
+	t.Run("starts at S01E01, not the special", func(t *testing.T) {
+		next, err := f.svc.NextEpisode(ctx, "", "show")
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, "show-s1e1", next.ID)
+	})
+
+	t.Run("follows the furthest watched episode", func(t *testing.T) {
+		_, err := f.svc.SetWatched(ctx, "", models.WatchMediaEpisode, "show-s1e2", true)
+		require.NoError(t, err)
+		next, err := f.svc.NextEpisode(ctx, "", "show")
+		require.NoError(t, err)
+		assert.Equal(t, "show-s1e3", next.ID, "a skipped S01E01 is not forced back on the user")
+	})
+
+	t.Run("a part-played episode comes first", func(t *testing.T) {
+		_, err := f.svc.ReportProgress(ctx, "", models.WatchMediaEpisode, "show-s1e1", 300, 2400)
+		require.NoError(t, err)
+		next, err := f.svc.NextEpisode(ctx, "", "show")
+		require.NoError(t, err)
+		assert.Equal(t, "show-s1e1", next.ID)
+	})
+
+	t.Run("season marks and completion", func(t *testing.T) {
+		season := 1
+		progress, err := f.svc.SetSeriesWatched(ctx, "", "show", &season, true)
+		require.NoError(t, err)
+		assert.Equal(t, models.WatchStatusInProgress, progress.Status)
+		assert.Equal(t, "show-s2e1", progress.NextEpisode.ID)
+
+		progress, err = f.svc.SetSeriesWatched(ctx, "", "show", nil, true)
+		require.NoError(t, err)
+		assert.Equal(t, models.WatchStatusWatched, progress.Status)
+		assert.Equal(t, 4, progress.TotalEpisodes, "specials do not count toward progress")
+		assert.Nil(t, progress.NextEpisode)
+	})
+}
+
+func TestWatchService_ContinueWatching(t *testing.T) {
+	f := setupWatchService(t)
+	ctx := context.Background()
+	require.NoError(t, f.movies.Create(ctx, &models.Movie{ID: "m1", Title: "Movie"}))
+	require.NoError(t, f.movies.Create(ctx, &models.Movie{ID: "m2", Title: "Finished"}))
+	f.addShow(t, "show", [2]int{1, 1}, [2]int{1, 2})
+	f.addShow(t, "done", [2]int{1, 1})
+
+	clock := time.Now()
+	f.svc.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	_, err := f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "m1", 600, 6000)
+	require.NoError(t, err)
+	_, err = f.svc.SetWatched(ctx, "", models.WatchMediaMovie, "m2", true)
+	require.NoError(t, err)
+	_, err = f.svc.SetWatched(ctx, "", models.WatchMediaEpisode, "done-s1e1", true)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond) // updated_at is wall-clock in the repository
+	_, err = f.svc.SetWatched(ctx, "", models.WatchMediaEpisode, "show-s1e1", true)
+	require.NoError(t, err)
+
+	items, err := f.svc.ContinueWatching(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, items, 2, "finished movies and finished shows drop out")
+	assert.Equal(t, models.WatchMediaEpisode, items[0].Type)
+	assert.Equal(t, "show-s1e2", items[0].Episode.ID)
+	assert.Equal(t, "show", items[0].Series.ID)
+	assert.Equal(t, "m1", items[1].Movie.ID)
+}
+
+// recordingObserver collects playback notifications.
+type recordingObserver struct{ calls []string }
+
+func (r *recordingObserver) PlaybackProgress(userID string, state models.WatchState, percent float64, finished bool) {
+	r.calls = append(r.calls, fmt.Sprintf("%s %.0f %v", state.MediaID, percent, finished))
+}
+
+func TestWatchService_PlaybackObserver(t *testing.T) {
+	f := setupWatchService(t)
+	ctx := context.Background()
+	require.NoError(t, f.movies.Create(ctx, &models.Movie{ID: "m1", Title: "Movie"}))
+	observer := &recordingObserver{}
+	f.svc.SetPlaybackObserver(observer)
+
+	_, err := f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "m1", 1500, 6000)
+	require.NoError(t, err)
+	_, err = f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "m1", 5500, 6000)
+	require.NoError(t, err)
+	_, err = f.svc.ReportProgress(ctx, "", models.WatchMediaMovie, "m1", 5800, 6000)
+	require.NoError(t, err)
+	_, err = f.svc.SetWatched(ctx, "", models.WatchMediaMovie, "m1", true)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"m1 25 false", "m1 100 true"}, observer.calls,
+		"credits and re-marking a watched item are not new plays")
+}
@@ -0,0 +1,364 @@
+package subtitle
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Timing alignment.
+//
+// A downloaded subtitle is written for SOME release of the title, not
+// necessarily this one: a different cut adds or drops a few seconds of
+// logos, a PAL release plays 4% fast, a recap is trimmed. Alignment fits the
+// subtitle's cues onto a reference that is known to match this file — the
+// ASR segments of its audio, or an embedded text track — by comparing where
+// each side has speech.
+//
+// The fit is done in three passes, each only kept when it clearly helps:
+//
+//  1. offset    — one global shift;
+//  2. linear    — a global shift plus one of the frame-rate ratios releases
+//     actually differ by (25/23.976 and friends);
+//  3. piecewise — cues split at long silences, each run re-shifted on its own,
+//     for cuts that moved one scene but not the next.
+//
+// Confidence is how well the two sides agree on when someone is speaking
+// after the fit, corrected for chance (see agreement).
+
+// AlignMethod names the re-timing an alignment applied.
+type AlignMethod string
+
+const (
+	AlignNone      AlignMethod = "none"
+	AlignOffset    AlignMethod = "offset"
+	AlignLinear    AlignMethod = "linear"
+	AlignPiecewise AlignMethod = "piecewise"
+)
+
+// Alignment is the fitted re-timing of one subtitle.
+type Alignment struct {
+	Method AlignMethod
+	// Offset is the global shift, applied after Scale.
+	Offset time.Duration
+	// Scale is the global speed ratio; 1 means no drift.
+	Scale float64
+	// Segments is how many cue runs were shifted on their own (piecewise).
+	Segments int
+	// Confidence is the speech overlap after the fit, 0..1.
+	Confidence float64
+	// Before is the same measure for the subtitle as it came.
+	Before float64
+	// Blocks are the re-timed cues, in SRT notation.
+	Blocks []SubtitleBlock
+}
+
+const (
+	// alignMaxOffset bounds the global shift search: past two minutes the
+	// subtitle belongs to a different edit, not a different release.
+	alignMaxOffset  = 120 * time.Second
+	alignCoarseStep = 250 * time.Millisecond
+	alignFineStep   = 10 * time.Millisecond
+	// alignMinShift is the smallest shift worth writing back; below it the
+	// fit is noise on a subtitle that was already right.
+	alignMinShift = 50 * time.Millisecond
+
+	// alignScaleMargin is how much better a drift fit must score than the
+	// plain offset before it is believed.
+	alignScaleMargin = 0.03
+
+	// Piecewise: a silence this long starts a new cue run; runs shorter than
+	// alignMinRunCues join the previous one; each run may move this far from
+	// the global fit and must gain alignRunMargin to be moved at all.
+	alignRunGap      = 8 * time.Second
+	alignMinRunCues  = 6
+	alignMaxRunShift = 10 * time.Second
+	alignRunStep     = 100 * time.Millisecond
+	alignRunMargin   = 0.1
+)
+
+// alignScales are the speed ratios between releases of one title: PAL
+// speed-up and NTSC pulldown in both directions.
+var alignScales = []float64{
+	1,
+	25 / 23.976, 23.976 / 25,
+	25.0 / 24, 24.0 / 25,
+	24 / 23.976, 23.976 / 24,
+}
+
+// span is one stretch of speech in milliseconds.
+type span struct{ start, end float64 }
+
+// AlignCues fits cues onto the reference cues' timing. A subtitle or
+// reference without usable timestamps comes back unchanged with zero
+// confidence.
+func AlignCues(cues, reference []SubtitleBlock) Alignment {
+	result := Alignment{Method: AlignNone, Scale: 1, Blocks: cues}
+
+	sub := cueSpans(cues)
+	ref := mergeSpans(cueSpans(reference))
+	if len(sub) == 0 || len(ref) == 0 {
+		return result
+	}
+	merged := mergeSpans(sub)
+	refTotal := spanTotal(ref)
+	score := func(spans []span, scale, offset float64) float64 {
+		return agreement(transformSpans(spans, scale, offset), ref, refTotal)
+	}
+
+	result.Before = score(merged, 1, 0)
+
+	// Passes 1 and 2: the global fit, plain offset first.
+	bestScale, bestOffset, best := 1.0, 0.0, result.Before
+	plainOffset, plain := searchOffset(merged, 1, 0, msOf(alignMaxOffset), score)
+	if plain > best {
+		bestOffset, best = plainOffset, plain
+	}
+	for _, scale := range alignScales[1:] {
+		offset, s := searchOffset(merged, scale, 0, msOf(alignMaxOffset), score)
+		if s > best+alignScaleMargin && s > plain+alignScaleMargin {
+			bestScale, bestOffset, best = scale, offset, s
+		}
+	}
+
+	// Pass 3: per-run refinement on top of the global fit. A run is scored
+	// by the share of its own speech the reference covers; the whole result
+	// must still agree better than the global fit alone, or the runs stay put.
+	runs := cueRuns(sub)
+	shifts := make([]float64, len(runs))
+	moved := 0
+	if len(runs) > 1 {
+		prevEnd := math.Inf(-1)
+		for i, run := range runs {
+			spans := mergeSpans(sub[run[0]:run[1]])
+			runTotal := spanTotal(spans)
+			coverage := func(spans []span, scale, offset float64) float64 {
+				return spanOverlap(transformSpans(spans, scale, offset), ref) / (runTotal * scale)
+			}
+			base := coverage(spans, bestScale, bestOffset)
+			delta, s := searchOffset(spans, bestScale, bestOffset, msOf(alignMaxRunShift), coverage)
+			start := spans[0].start*bestScale + bestOffset + delta
+			if s >= base+alignRunMargin && math.Abs(delta) >= msOf(alignRunStep) && start >= prevEnd {
+				shifts[i] = delta
+				moved++
+			}
+			prevEnd = spans[len(spans)-1].end*bestScale + bestOffset + shifts[i]
+		}
+	}
+	if moved > 0 {
+		var after []span
+		for r, run := range runs {
+			after = append(after, transformSpans(sub[run[0]:run[1]], bestScale, bestOffset+shifts[r])...)
+		}
+		if s := agreement(mergeSpans(after), ref, refTotal); s > best {
+			best = s
+		} else {
+			moved = 0
+			clear(shifts)
+		}
+	}
+
+	result.Confidence = best
+	result.Scale = bestScale
+	result.Offset = time.Duration(math.Round(bestOffset)) * time.Millisecond
+
+	switch {
+	case moved > 0:
+		result.Method = AlignPiecewise
+		result.Segments = moved
+	case bestScale != 1:
+		result.Method = AlignLinear
+	case math.Abs(bestOffset) >= msOf(alignMinShift):
+		result.Method = AlignOffset
+	default:
+		// Nothing worth moving: hand the cues back untouched rather than
+		// rewritten to the same time in a different notation.
+		result.Offset = 0
+		result.Confidence = result.Before
+		return result
+	}
+
+	retimed := make([]SubtitleBlock, len(cues))
+	copy(retimed, cues)
+	timed := timedCueIndexes(cues)
+	for r, run := range runs {
+		for k := run[0]; k < run[1]; k++ {
+			s := transformMs(sub[k].start, bestScale, bestOffset+shifts[r])
+			e := transformMs(sub[k].end, bestScale, bestOffset+shifts[r])
+			retimed[timed[k]].Start = FormatSRTTime(time.Duration(s) * time.Millisecond)
+			retimed[timed[k]].End = FormatSRTTime(time.Duration(e) * time.Millisecond)
+		}
+	}
+	result.Blocks = retimed
+	return result
+}
+
+// agreement is how far the two speech timelines agree beyond chance (Cohen's
+// kappa over the time they jointly span): 1 when every moment is speech or
+// silence on both sides, 0 for two unrelated dialogues — which would
+// otherwise overlap plenty by accident, dialogue being half of a film.
+func agreement(sub, ref []span, refTotal float64) float64 {
+	if len(sub) == 0 || len(ref) == 0 {
+		return 0
+	}
+	lo := math.Min(sub[0].start, ref[0].start)
+	hi := math.Max(sub[len(sub)-1].end, ref[len(ref)-1].end)
+	window := hi - lo
+	if window <= 0 {
+		return 0
+	}
+	a, b := spanTotal(sub)/window, refTotal/window
+	both := spanOverlap(sub, ref) / window
+	observed := 1 - a - b + 2*both
+	chance := a*b + (1-a)*(1-b)
+	if chance >= 1 {
+		return 0
+	}
+	return math.Max(0, math.Min(1, (observed-chance)/(1-chance)))
+}
+
+// searchOffset finds the shift in [-limit, limit] around base that scores
+// best: a coarse sweep, then a fine one around the coarse winner. Ties keep
+// the smallest shift.
+func searchOffset(spans []span, scale, base, limit float64, score func([]span, float64, float64) float64) (float64, float64) {
+	coarse, fine := msOf(alignCoarseStep), msOf(alignFineStep)
+	if limit <= msOf(alignMaxRunShift) {
+		coarse = msOf(alignRunStep)
+	}
+	bestDelta, best := 0.0, score(spans, scale, base)
+	for d := -limit; d <= limit; d += coarse {
+		if s := score(spans, scale, base+d); s > best || (s == best && math.Abs(d) < math.Abs(bestDelta)) {
+			bestDelta, best = d, s
+		}
+	}
+	center := bestDelta
+	for d := center - coarse; d <= center+coarse; d += fine {
+		if s := score(spans, scale, base+d); s > best || (s == best && math.Abs(d) < math.Abs(bestDelta)) {
+			bestDelta, best = d, s
+		}
+	}
+	return bestDelta, best
+}
+
+// cueSpans is the timed cues as spans, in cue order. Cues whose timestamps
+// do not parse are left out (timedCueIndexes maps back to them).
+func cueSpans(cues []SubtitleBlock) []span {
+	out := make([]span, 0, len(cues))
+	for _, c := range cues {
+		if s, e, ok := cueBounds(c); ok {
+			out = append(out, span{s, e})
+		}
+	}
+	return out
+}
+
+// timedCueIndexes maps cueSpans positions back to cue positions.
+func timedCueIndexes(cues []SubtitleBlock) []int {
+	out := make([]int, 0, len(cues))
+	for i, c := range cues {
+		if _, _, ok := cueBounds(c); ok {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func cueBounds(c SubtitleBlock) (float64, float64, bool) {
+	start, err := ParseCueTime(c.Start)
+	if err != nil {
+		return 0, 0, false
+	}
+	end, err := ParseCueTime(c.End)
+	if err != nil || end <= start {
+		return 0, 0, false
+	}
+	return msOf(start), msOf(end), true
+}
+
+// cueRuns splits the timed cues into [from, to) runs at long silences,
+// folding short runs into the one before.
+func cueRuns(spans []span) [][2]int {
+	if len(spans) == 0 {
+		return nil
+	}
+	var runs [][2]int
+	from := 0
+	for i := 1; i <= len(spans); i++ {
+		if i < len(spans) && spans[i].start-spans[i-1].end < msOf(alignRunGap) {
+			continue
+		}
+		if len(runs) > 0 && i-from < alignMinRunCues {
+			runs[len(runs)-1][1] = i
+		} else {
+			runs = append(runs, [2]int{from, i})
+		}
+		from = i
+	}
+	// A short first run folds forward instead.
+	if len(runs) > 1 && runs[0][1]-runs[0][0] < alignMinRunCues {
+		runs[1][0] = runs[0][0]
+		runs = runs[1:]
+	}
+	return runs
+}
+
+// mergeSpans returns the sorted union of spans.
+func mergeSpans(spans []span) []span {
+	if len(spans) == 0 {
+		return nil
+	}
+	sorted := append([]span(nil), spans...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start < sorted[j].start })
+	out := []span{sorted[0]}
+	for _, s := range sorted[1:] {
+		last := &out[len(out)-1]
+		if s.start <= last.end {
+			last.end = math.Max(last.end, s.end)
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func transformSpans(spans []span, scale, offset float64) []span {
+	out := make([]span, len(spans))
+	for i, s := range spans {
+		out[i] = span{s.start*scale + offset, s.end*scale + offset}
+	}
+	return out
+}
+
+// transformMs maps one timestamp, clamped at zero.
+func transformMs(ms, scale, offset float64) float64 {
+	return math.Max(0, math.Round(ms*scale+offset))
+}
+
+// spanOverlap is the total time two sorted, disjoint span lists share.
+func spanOverlap(a, b []span) float64 {
+	total := 0.0
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		lo := math.Max(a[i].start, b[j].start)
+		hi := math.Min(a[i].end, b[j].end)
+		if hi > lo {
+			total += hi - lo
+		}
+		if a[i].end < b[j].end {
+			i++
+		} else {
+			j++
+		}
+	}
+	return total
+}
+
+func spanTotal(spans []span) float64 {
+	total := 0.0
+	for _, s := range spans {
+		total += s.end - s.start
+	}
+	return total
+}
+
+func msOf(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
@@ -0,0 +1,232 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// speechCues is a deterministic dialogue track: n cues of 1–4s separated by
+// 0.3–3s pauses, with a long silence every runEvery cues (0 = never).
+func speechCues(seed int64, n, runEvery int) []SubtitleBlock {
+	rng := rand.New(rand.NewSource(seed))
+	out := make([]SubtitleBlock, 0, n)
+	at := 5 * time.Second
+	for i := 0; i < n; i++ {
+		if runEvery > 0 && i > 0 && i%runEvery == 0 {
+			at += 15 * time.Second
+		}
+		d := time.Second + time.Duration(rng.Intn(3000))*time.Millisecond
+		out = append(out, SubtitleBlock{
+			Index: i + 1,
+			Start: FormatSRTTime(at),
+			End:   FormatSRTTime(at + d),
+			Text:  "line",
+		})
+		at += d + 300*time.Millisecond + time.Duration(rng.Intn(2700))*time.Millisecond
+	}
+	return out
+}
+
+// retime maps every cue time through fn from cue index from onwards.
+func retime(blocks []SubtitleBlock, from int, fn func(time.Duration) time.Duration) []SubtitleBlock {
+	out := append([]SubtitleBlock(nil), blocks...)
+	for i := from; i < len(out); i++ {
+		s, _ := ParseCueTime(out[i].Start)
+		e, _ := ParseCueTime(out[i].End)
+		out[i].Start, out[i].End = FormatSRTTime(fn(s)), FormatSRTTime(fn(e))
+	}
+	return out
+}
+
+func shiftBy(d time.Duration) func(time.Duration) time.Duration {
+	return func(t time.Duration) time.Duration { return t + d }
+}
+
+func TestAlignCues_FindsAGlobalOffset(t *testing.T) {
+	ref := speechCues(1, 200, 0)
+	sub := retime(ref, 0, shiftBy(3200*time.Millisecond))
+
+	fit := AlignCues(sub, ref)
+
+	assert.Equal(t, AlignOffset, fit.Method)
+	assert.InDelta(t, -3200, fit.Offset.Milliseconds(), 20)
+	assert.Equal(t, 1.0, fit.Scale)
+	assert.Greater(t, fit.Confidence, 0.9)
+	assert.Less(t, fit.Before, 0.5)
+	assert.Equal(t, ref[10].Start, fit.Blocks[10].Start)
+	assert.Equal(t, sub[10].Text, fit.Blocks[10].Text)
+}
+
+func TestAlignCues_FindsFrameRateDrift(t *testing.T) {
+	ref := speechCues(2, 300, 0)
+	// A PAL subtitle runs 25/23.976 fast against a film-rate release.
+	pal := 23.976 / 25
+	sub := retime(ref, 0, func(t time.Duration) time.Duration {
+		return time.Duration(float64(t) * pal)
+	})
+
+	fit := AlignCues(sub, ref)
+
+	assert.Equal(t, AlignLinear, fit.Method)
+	assert.InDelta(t, 25/23.976, fit.Scale, 1e-9)
+	assert.Greater(t, fit.Confidence, 0.9)
+}
+
+func TestAlignCues_RetimesAShiftedSceneOnItsOwn(t *testing.T) {
+	ref := speechCues(3, 120, 40)
+	// The release the subtitle was made for has 4s more in the last scene.
+	sub := retime(ref, 80, shiftBy(4*time.Second))
+
+	fit := AlignCues(sub, ref)
+
+	assert.Equal(t, AlignPiecewise, fit.Method)
+	assert.Equal(t, 1, fit.Segments)
+	assert.Greater(t, fit.Confidence, 0.9)
+	for _, i := range []int{0, 79, 80, 119} {
+		assert.Equal(t, ref[i].Start, fit.Blocks[i].Start, "cue %d", i)
+	}
+}
+
+func TestAlignCues_LeavesAMatchingSubtitleUntouched(t *testing.T) {
+	ref := speechCues(4, 100, 0)
+
+	fit := AlignCues(ref, ref)
+
+	assert.Equal(t, AlignNone, fit.Method)
+	assert.Equal(t, ref, fit.Blocks)
+	assert.InDelta(t, 1.0, fit.Confidence, 1e-9)
+}
+
+func TestAlignCues_UnrelatedDialogueIsNotConfident(t *testing.T) {
+	fit := AlignCues(speechCues(5, 200, 0), speechCues(6, 200, 0))
+	assert.Less(t, fit.Confidence, DefaultAlignMinConfidence)
+}
+
+func TestAlignCues_NoTimestampsIsANoOp(t *testing.T) {
+	sub := []SubtitleBlock{{Index: 1, Start: "garbage", End: "", Text: "x"}}
+	fit := AlignCues(sub, speechCues(7, 30, 0))
+	assert.Equal(t, AlignNone, fit.Method)
+	assert.Zero(t, fit.Confidence)
+	assert.Equal(t, sub, fit.Blocks)
+}
+
+func TestAgreement_IsChanceCorrected(t *testing.T) {
+	a := []span{{0, 1000}, {2000, 3000}}
+	assert.InDelta(t, 1.0, agreement(a, a, spanTotal(a)), 1e-9)
+	b := []span{{1000, 2000}, {3000, 4000}}
+	assert.Zero(t, agreement(a, b, spanTotal(b)))
+	assert.False(t, math.IsNaN(agreement(a, nil, 0)))
+}
+
+// --- Aligner ---
+
+type fakeTimingReference struct {
+	name  string
+	free  bool
+	slow  bool
+	cues  []SubtitleBlock
+	err   error
+	calls int
+}
+
+func (f *fakeTimingReference) Name() string { return f.name }
+func (f *fakeTimingReference) Free() bool   { return f.free }
+func (f *fakeTimingReference) Inline() bool { return !f.slow }
+func (f *fakeTimingReference) ReferenceCues(context.Context, string, string) ([]SubtitleBlock, error) {
+	f.calls++
+	return f.cues, f.err
+}
+
+func TestAligner_AppliesAConfidentFitInTheSubtitlesOwnFormat(t *testing.T) {
+	ref := speechCues(8, 60, 0)
+	doc := &Document{Format: CueFormatASS, Blocks: retime(ref, 0, shiftBy(-2*time.Second))}
+	data := []byte(doc.Serialize())
+
+	embedded := &fakeTimingReference{name: ReferenceEmbedded, free: true, cues: ref}
+	out, err := NewAligner(nil, embedded).Align(context.Background(), AlignRequest{Data: data, Format: "ass"})
+	require.NoError(t, err)
+
+	assert.True(t, out.Applied)
+	assert.Equal(t, ReferenceEmbedded, out.Reference)
+	assert.Equal(t, CueFormatASS, out.Format)
+	aligned, err := ParseASS(string(out.Data))
+	require.NoError(t, err)
+	assert.Equal(t, ref[0].Start, aligned.Blocks[0].Start)
+}
+
+func TestAligner_KeepsTheOriginalWhenNotConfident(t *testing.T) {
+	data := []byte(SerializeSRT(speechCues(9, 60, 0)))
+	embedded := &fakeTimingReference{name: ReferenceEmbedded, free: true, cues: speechCues(10, 60, 0)}
+
+	out, err := NewAligner(nil, embedded).Align(context.Background(), AlignRequest{Data: data})
+	require.NoError(t, err)
+
+	assert.False(t, out.Applied)
+	assert.Equal(t, data, out.Data)
+}
+
+// TestAligner_PaidReferenceNeedsConsent — the automatic paths never pass
+// AllowPaid, so an ASR reference that costs money is not even produced.
+func TestAligner_PaidReferenceNeedsConsent(t *testing.T) {
+	ref := speechCues(11, 60, 0)
+	data := []byte(SerializeSRT(retime(ref, 0, shiftBy(time.Second))))
+	audio := &fakeTimingReference{name: ReferenceAudio, free: false, cues: ref}
+	aligner := NewAligner(nil, audio)
+
+	_, err := aligner.Align(context.Background(), AlignRequest{Data: data})
+	assert.ErrorIs(t, err, ErrSubtitleNoTimingReference)
+	assert.Zero(t, audio.calls)
+
+	out, err := aligner.Align(context.Background(), AlignRequest{Data: data, Reference: ReferenceAudio, AllowPaid: true, AllowSlow: true})
+	require.NoError(t, err)
+	assert.True(t, out.Applied)
+	assert.Equal(t, ReferenceAudio, out.Reference)
+}
+
+// TestAligner_SlowReferenceNeedsAnExplicitRequest — a self-hosted ASR is free
+// but transcribes the whole film, so the inline paths never produce it.
+func TestAligner_SlowReferenceNeedsAnExplicitRequest(t *testing.T) {
+	ref := speechCues(14, 60, 0)
+	data := []byte(SerializeSRT(retime(ref, 0, shiftBy(time.Second))))
+	embedded := &fakeTimingReference{name: ReferenceEmbedded, free: true}
+	audio := &fakeTimingReference{name: ReferenceAudio, free: true, slow: true, cues: ref}
+	aligner := NewAligner(nil, embedded, audio)
+
+	_, err := aligner.Align(context.Background(), AlignRequest{Data: data})
+	assert.ErrorIs(t, err, ErrSubtitleNoTimingReference)
+	assert.Equal(t, 1, embedded.calls)
+	assert.Zero(t, audio.calls)
+
+	out, err := aligner.Align(context.Background(), AlignRequest{Data: data, Reference: ReferenceAudio, AllowSlow: true})
+	require.NoError(t, err)
+	assert.True(t, out.Applied)
+	assert.Equal(t, ReferenceAudio, out.Reference)
+}
+
+func TestAligner_FallsThroughToTheNextReference(t *testing.T) {
+	ref := speechCues(12, 60, 0)
+	data := []byte(SerializeSRT(retime(ref, 0, shiftBy(time.Second))))
+	embedded := &fakeTimingReference{name: ReferenceEmbedded, free: true, err: errors.New("ffprobe: exit 1")}
+	audio := &fakeTimingReference{name: ReferenceAudio, free: true, cues: ref}
+
+	out, err := NewAligner(nil, embedded, audio).Align(context.Background(), AlignRequest{Data: data})
+	require.NoError(t, err)
+	assert.Equal(t, ReferenceAudio, out.Reference)
+	assert.Equal(t, 1, embedded.calls)
+}
+
+func TestAligner_ReferenceRestriction(t *testing.T) {
+	data := []byte(SerializeSRT(speechCues(13, 60, 0)))
+	embedded := &fakeTimingReference{name: ReferenceEmbedded, free: true, cues: speechCues(13, 60, 0)}
+
+	_, err := NewAligner(nil, embedded).Align(context.Background(), AlignRequest{Data: data, Reference: ReferenceAudio})
+	assert.ErrorIs(t, err, ErrSubtitleNoTimingReference)
+	assert.Zero(t, embedded.calls)
+}
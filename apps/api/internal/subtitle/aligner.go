@@ -0,0 +1,270 @@
+package subtitle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+
+	"github.com/vido/api/internal/services"
+)
+
+// Aligner re-times a subtitle against a reference that is known to match the
+// media file (AlignCues does the fitting).
+//
+// References are tried in the order they were wired, cheapest first: an
+// embedded text track costs an ffmpeg pass, a speech reference costs an ASR
+// run over the whole film. The automatic paths (the search engine, a manual
+// download) align inline while the placement waits, so they never pass
+// AllowSlow or AllowPaid: a speech reference is used only when the manual
+// sync endpoint asks for "audio" by name — even a free self-hosted ASR takes
+// far longer on NAS hardware than a download should.
+//
+// A fit is only applied when it is confident AND better than what came in:
+// a subtitle that already matched, or one for a different edit entirely, is
+// handed back byte-for-byte.
+type Aligner struct {
+	references    []TimingReference
+	minConfidence float64
+	logger        *slog.Logger
+}
+
+// Reference names, as the sync endpoint accepts them.
+const (
+	ReferenceAuto     = "auto"
+	ReferenceEmbedded = "embedded"
+	ReferenceAudio    = "audio"
+)
+
+const (
+	// DefaultAlignMinConfidence is the agreement a fit must reach before
+	// it is written back.
+	DefaultAlignMinConfidence = 0.5
+	// alignMinGain is how much a fit must improve on the subtitle as it came.
+	alignMinGain = 0.05
+	// alignMinReferenceCues keeps a forced-only or sign track from standing
+	// in for the dialogue.
+	alignMinReferenceCues = 20
+)
+
+// TimingReference produces reference cues for a media file.
+type TimingReference interface {
+	// Name is the reference's ReferenceEmbedded/ReferenceAudio name.
+	Name() string
+	// Free reports whether producing the reference costs no AI spend.
+	Free() bool
+	// Inline reports whether the reference is quick enough to produce while
+	// a download waits. Cost and speed are separate: a self-hosted ASR is
+	// free but still a transcription of the whole file.
+	Inline() bool
+	ReferenceCues(ctx context.Context, mediaID, mediaPath string) ([]SubtitleBlock, error)
+}
+
+// NewAligner wires an aligner to its references, tried in the given order.
+func NewAligner(logger *slog.Logger, references ...TimingReference) *Aligner {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Aligner{
+		references:    references,
+		minConfidence: DefaultAlignMinConfidence,
+		logger:        logger.With("component", "subtitle_aligner"),
+	}
+}
+
+// AlignRequest is one subtitle to re-time.
+type AlignRequest struct {
+	MediaID   string
+	MediaPath string
+	Data      []byte
+	// Format is a format hint or extension; empty sniffs the content.
+	Format string
+	// Reference restricts the reference used; empty or ReferenceAuto tries
+	// every reference the request may use.
+	Reference string
+	// AllowPaid admits a reference that spends AI budget.
+	AllowPaid bool
+	// AllowSlow admits a reference that is not Inline.
+	AllowSlow bool
+}
+
+// AlignOutcome is the fit and what became of it.
+type AlignOutcome struct {
+	Alignment
+	// Reference names the reference the fit was made against.
+	Reference string
+	// Applied reports whether Data is re-timed.
+	Applied bool
+	// Data is the re-timed subtitle in its own format, or the input as-is.
+	Data   []byte
+	Format CueFormat
+}
+
+// Align fits req.Data onto the first usable reference. It errors only when
+// the subtitle does not parse or no reference could be had
+// (ErrSubtitleNoTimingReference); a poor fit is an outcome, not an error.
+func (a *Aligner) Align(ctx context.Context, req AlignRequest) (*AlignOutcome, error) {
+	format, _ := ParseCueFormat(req.Format)
+	doc, err := ParseDocument(string(req.Data), format)
+	if err != nil {
+		return nil, fmt.Errorf("align: parse subtitle: %w", err)
+	}
+
+	name, reference, err := a.reference(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fit := AlignCues(doc.Blocks, reference)
+	out := &AlignOutcome{Alignment: fit, Reference: name, Data: req.Data, Format: doc.Format}
+	if fit.Method != AlignNone && fit.Confidence >= a.minConfidence && fit.Confidence >= fit.Before+alignMinGain {
+		out.Data = []byte(doc.WithBlocks(fit.Blocks).Serialize())
+		out.Applied = true
+	}
+
+	a.logger.Info("subtitle timing aligned",
+		"media_id", req.MediaID, "reference", name, "method", fit.Method,
+		"offset_ms", fit.Offset.Milliseconds(), "scale", fit.Scale, "segments", fit.Segments,
+		"confidence", fit.Confidence, "before", fit.Before, "applied", out.Applied)
+	return out, nil
+}
+
+// reference returns the first reference the request may use that yields
+// enough cues.
+func (a *Aligner) reference(ctx context.Context, req AlignRequest) (string, []SubtitleBlock, error) {
+	var lastErr error
+	for _, ref := range a.references {
+		if req.Reference != "" && req.Reference != ReferenceAuto && req.Reference != ref.Name() {
+			continue
+		}
+		if !ref.Free() && !req.AllowPaid {
+			continue
+		}
+		if !ref.Inline() && !req.AllowSlow {
+			continue
+		}
+		cues, err := ref.ReferenceCues(ctx, req.MediaID, req.MediaPath)
+		if err != nil {
+			a.logger.Warn("timing reference unavailable",
+				"media_id", req.MediaID, "reference", ref.Name(), "error", err)
+			lastErr = err
+			continue
+		}
+		if len(cues) < alignMinReferenceCues {
+			continue
+		}
+		return ref.Name(), cues, nil
+	}
+	if lastErr != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrSubtitleNoTimingReference, lastErr)
+	}
+	return "", nil, ErrSubtitleNoTimingReference
+}
+
+// EmbeddedTrackReference times against a text subtitle track embedded in the
+// media file — free, and exact whenever the file has one. English tracks are
+// tried first; any language will do, only the timing is read.
+type EmbeddedTrackReference struct {
+	prober    TechProber
+	extractor TrackExtractor
+}
+
+// NewEmbeddedTrackReference wires the reference to the router's two ports.
+func NewEmbeddedTrackReference(prober TechProber, extractor TrackExtractor) *EmbeddedTrackReference {
+	return &EmbeddedTrackReference{prober: prober, extractor: extractor}
+}
+
+func (r *EmbeddedTrackReference) Name() string { return ReferenceEmbedded }
+func (r *EmbeddedTrackReference) Free() bool   { return true }
+func (r *EmbeddedTrackReference) Inline() bool { return true }
+
+func (r *EmbeddedTrackReference) ReferenceCues(ctx context.Context, _, mediaPath string) ([]SubtitleBlock, error) {
+	info, err := r.prober.Probe(ctx, mediaPath)
+	if err != nil {
+		return nil, fmt.Errorf("probe %s: %w", mediaPath, err)
+	}
+	var tracks []services.SubtitleTrack
+	if info != nil {
+		for _, t := range info.SubtitleTracks {
+			if !t.External && IsTextSubtitleCodec(t.Format) {
+				tracks = append(tracks, t)
+			}
+		}
+	}
+	if len(tracks) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(tracks, func(i, j int) bool {
+		return isEnglishTag(tracks[i].Language) && !isEnglishTag(tracks[j].Language)
+	})
+
+	tmpDir, err := os.MkdirTemp("", "vido-align-")
+	if err != nil {
+		return nil, fmt.Errorf("temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	indexes := make([]int, len(tracks))
+	for i, t := range tracks {
+		indexes[i] = t.StreamIndex
+	}
+	outputs, err := r.extractor.Extract(ctx, mediaPath, tmpDir, indexes)
+	if err != nil {
+		return nil, fmt.Errorf("extract %s: %w", mediaPath, err)
+	}
+
+	for _, t := range tracks {
+		path, ok := outputs[t.StreamIndex]
+		if !ok {
+			continue
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		doc, err := ParseDocumentFile(path, raw)
+		if err != nil {
+			continue
+		}
+		// Sound annotations are timed to the soundtrack, not the dialogue.
+		if blocks, _ := FilterSDH(doc.Blocks); len(blocks) >= alignMinReferenceCues {
+			return blocks, nil
+		}
+	}
+	return nil, nil
+}
+
+// ReferenceTranscriber is the narrow port over services.TranscriptionService.
+type ReferenceTranscriber interface {
+	IsAvailable() bool
+	TranscribeReference(ctx context.Context, mediaID, filePath string) (string, error)
+}
+
+// SpeechReference times against the ASR segments of the file's own audio.
+// It is free only when the ASR endpoint is self-hosted, and never Inline.
+type SpeechReference struct {
+	transcriber ReferenceTranscriber
+	free        bool
+}
+
+// NewSpeechReference wires the reference; free is whether the ASR it runs
+// costs nothing (ai.IsSelfHostedASRBaseURL).
+func NewSpeechReference(transcriber ReferenceTranscriber, free bool) *SpeechReference {
+	return &SpeechReference{transcriber: transcriber, free: free}
+}
+
+func (r *SpeechReference) Name() string { return ReferenceAudio }
+func (r *SpeechReference) Free() bool   { return r.free }
+func (r *SpeechReference) Inline() bool { return false }
+
+func (r *SpeechReference) ReferenceCues(ctx context.Context, mediaID, mediaPath string) ([]SubtitleBlock, error) {
+	if r.transcriber == nil || !r.transcriber.IsAvailable() {
+		return nil, nil
+	}
+	srt, err := r.transcriber.TranscribeReference(ctx, mediaID, mediaPath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSRT(srt)
+}
@@ -0,0 +1,357 @@
+package subtitle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ASS/SSA codec.
+//
+// An ASS file is an INI-like script: [Script Info], a styles section, an
+// [Events] section of Dialogue/Comment lines, and optionally embedded fonts
+// and graphics. Fansub releases lean on all of it — the style table carries
+// the typesetting, and per-line override blocks ({\an8}, {\pos(…)}, {\i1})
+// place signs and songs. The pipeline only ever rewrites dialogue TEXT, so
+// the codec keeps every line it does not understand verbatim and re-emits
+// each Dialogue event from its own field list with only Start/End/Text
+// substituted.
+
+// assFormatDefaults is the Events Format line used when a script does not
+// declare one (and for scripts this package creates from scratch).
+var assFormatDefaults = map[CueFormat][]string{
+	CueFormatASS: {"Layer", "Start", "End", "Style", "Name", "MarginL", "MarginR", "MarginV", "Effect", "Text"},
+	CueFormatSSA: {"Marked", "Start", "End", "Style", "Name", "MarginL", "MarginR", "MarginV", "Effect", "Text"},
+}
+
+// assScript is the part of an ASS document the SRT model cannot carry.
+type assScript struct {
+	format CueFormat
+	// lines is the file, line by line, with every translatable Dialogue
+	// event replaced by a placeholder pointing into events.
+	lines []assLine
+	// fields is the [Events] Format declaration, lower-cased.
+	fields []string
+}
+
+// assLine is one physical line of the script. event is nil for anything
+// that is re-emitted verbatim (section headers, styles, comments, drawings).
+type assLine struct {
+	raw   string
+	event *assEvent
+}
+
+// assEvent is one translatable Dialogue line.
+type assEvent struct {
+	index  int      // the SubtitleBlock.Index it surfaces as
+	values []string // every field value, in Format order
+	// prefix is the run of override blocks the text OPENS with ({\an8},
+	// {\pos(…)}). It survives translation: it positions the whole line.
+	prefix string
+	// rawText is the original Text field, used verbatim when the block's
+	// text comes back unchanged so inline tags are not lost on a pass-through.
+	rawText string
+	// plain is rawText with tags stripped and \N/\n turned into newlines —
+	// the text the block carried when parsed.
+	plain string
+}
+
+// ParseASS parses an ASS (v4.00+) or SSA (v4.00) script. Only Dialogue
+// events with visible text become blocks; Comment events and vector
+// drawings ({\p1}) are kept verbatim but never offered for translation.
+func ParseASS(content string) (*Document, error) {
+	content = strings.TrimPrefix(content, "\xEF\xBB\xBF")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+
+	format := SniffCueFormat([]byte(content))
+	if format != CueFormatSSA {
+		format = CueFormatASS
+	}
+	script := &assScript{format: format}
+
+	section := ""
+	var blocks []SubtitleBlock
+	for _, raw := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section = strings.ToLower(trimmed)
+			script.lines = append(script.lines, assLine{raw: raw})
+			continue
+		}
+		if section != "[events]" {
+			script.lines = append(script.lines, assLine{raw: raw})
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			script.lines = append(script.lines, assLine{raw: raw})
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "format":
+			script.fields = splitASSFormat(value)
+			script.lines = append(script.lines, assLine{raw: raw})
+		case "dialogue":
+			event, err := script.parseDialogue(value, len(blocks)+1)
+			if err != nil {
+				return nil, err
+			}
+			if event == nil {
+				script.lines = append(script.lines, assLine{raw: raw})
+				continue
+			}
+			start, _ := toSRTTime(event.values[script.fieldIndex("start")])
+			end, _ := toSRTTime(event.values[script.fieldIndex("end")])
+			blocks = append(blocks, SubtitleBlock{Index: event.index, Start: start, End: end, Text: event.plain})
+			script.lines = append(script.lines, assLine{event: event})
+		default:
+			script.lines = append(script.lines, assLine{raw: raw})
+		}
+	}
+
+	return &Document{Format: format, Blocks: blocks, ass: script}, nil
+}
+
+// newASSScript builds the minimal script a document without a source (an
+// SRT converted to ASS) is written into.
+func newASSScript(format CueFormat) *assScript {
+	styles := "[V4+ Styles]"
+	styleFormat := "Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding"
+	styleLine := "Style: Default,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1"
+	scriptType := "v4.00+"
+	if format == CueFormatSSA {
+		styles = "[V4 Styles]"
+		styleFormat = "Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, TertiaryColour, BackColour, Bold, Italic, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, AlphaLevel, Encoding"
+		styleLine = "Style: Default,Arial,20,16777215,255,0,0,0,0,1,2,2,2,10,10,10,0,1"
+		scriptType = "v4.00"
+	}
+	fields := assFormatDefaults[format]
+	raws := []string{
+		"[Script Info]",
+		"ScriptType: " + scriptType,
+		"WrapStyle: 0",
+		"",
+		styles,
+		styleFormat,
+		styleLine,
+		"",
+		"[Events]",
+		"Format: " + strings.Join(fields, ", "),
+	}
+	script := &assScript{format: format, fields: lowerAll(fields)}
+	for _, raw := range raws {
+		script.lines = append(script.lines, assLine{raw: raw})
+	}
+	return script
+}
+
+// parseDialogue splits one Dialogue value into its fields. It returns nil
+// (no error) for an event that carries nothing to translate.
+func (s *assScript) parseDialogue(value string, index int) (*assEvent, error) {
+	if len(s.fields) == 0 {
+		s.fields = lowerAll(assFormatDefaults[s.format])
+	}
+	// Text is always the LAST field and may itself contain commas, so the
+	// split is bounded by the field count.
+	values := strings.SplitN(strings.TrimLeft(value, " "), ",", len(s.fields))
+	if len(values) != len(s.fields) {
+		return nil, fmt.Errorf("ass: dialogue has %d fields, format declares %d: %q", len(values), len(s.fields), value)
+	}
+	textIdx := s.fieldIndex("text")
+	if textIdx < 0 || s.fieldIndex("start") < 0 || s.fieldIndex("end") < 0 {
+		return nil, fmt.Errorf("ass: events format lacks Start/End/Text: %v", s.fields)
+	}
+	for _, name := range []string{"start", "end"} {
+		if _, err := ParseCueTime(values[s.fieldIndex(name)]); err != nil {
+			return nil, fmt.Errorf("ass: %w", err)
+		}
+	}
+
+	rawText := values[textIdx]
+	if isASSDrawing(rawText) {
+		return nil, nil
+	}
+	plain := assPlainText(rawText)
+	if strings.TrimSpace(plain) == "" {
+		return nil, nil
+	}
+	return &assEvent{
+		index:   index,
+		values:  values,
+		prefix:  assLeadingOverrides(rawText),
+		rawText: rawText,
+		plain:   plain,
+	}, nil
+}
+
+func (s *assScript) fieldIndex(name string) int {
+	for i, f := range s.fields {
+		if f == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// serialize writes the script back with blocks as the dialogue. A source
+// event whose Index is absent from blocks is dropped; a block with no source
+// event (a document built from SRT) is appended as a Default-style line.
+func (s *assScript) serialize(blocks []SubtitleBlock) string {
+	byIndex := make(map[int]SubtitleBlock, len(blocks))
+	for _, b := range blocks {
+		byIndex[b.Index] = b
+	}
+
+	var sb strings.Builder
+	seen := make(map[int]bool, len(blocks))
+	for _, line := range s.lines {
+		if line.event == nil {
+			sb.WriteString(line.raw)
+			sb.WriteByte('\n')
+			continue
+		}
+		b, ok := byIndex[line.event.index]
+		if !ok {
+			continue
+		}
+		seen[b.Index] = true
+		sb.WriteString(s.dialogueLine(line.event.values, line.event, b))
+		sb.WriteByte('\n')
+	}
+
+	for _, b := range blocks {
+		if seen[b.Index] {
+			continue
+		}
+		values := make([]string, len(s.fields))
+		for i, f := range s.fields {
+			switch f {
+			case "layer", "marked":
+				values[i] = "0"
+			case "style":
+				values[i] = "Default"
+			case "marginl", "marginr", "marginv":
+				values[i] = "0"
+			}
+		}
+		sb.WriteString(s.dialogueLine(values, nil, b))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// dialogueLine renders one Dialogue event, substituting the block's timing
+// and text into the source field values.
+func (s *assScript) dialogueLine(values []string, event *assEvent, b SubtitleBlock) string {
+	out := append([]string(nil), values...)
+	if d, err := ParseCueTime(b.Start); err == nil {
+		out[s.fieldIndex("start")] = formatASSTime(d)
+	}
+	if d, err := ParseCueTime(b.End); err == nil {
+		out[s.fieldIndex("end")] = formatASSTime(d)
+	}
+
+	text := assEncodeText(b.Text)
+	if event != nil {
+		if b.Text == event.plain {
+			text = event.rawText
+		} else {
+			text = event.prefix + text
+		}
+	}
+	out[s.fieldIndex("text")] = text
+	return "Dialogue: " + strings.Join(out, ",")
+}
+
+// splitASSFormat parses the field list of a Format line.
+func splitASSFormat(value string) []string {
+	parts := strings.Split(value, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		out = append(out, strings.ToLower(strings.TrimSpace(p)))
+	}
+	return out
+}
+
+func lowerAll(in []string) []string {
+	out := make([]string, len(in))
+	for i, s := range in {
+		out[i] = strings.ToLower(s)
+	}
+	return out
+}
+
+// isASSDrawing reports whether a Text field switches into vector drawing
+// mode (\p1 and up) — the shape commands are not words.
+func isASSDrawing(text string) bool {
+	for _, block := range assOverrideBlocks(text) {
+		for _, tag := range strings.Split(block, `\`) {
+			if strings.HasPrefix(tag, "p") && len(tag) > 1 && tag[1] >= '1' && tag[1] <= '9' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// assOverrideBlocks returns the contents of every {…} block in text.
+func assOverrideBlocks(text string) []string {
+	var out []string
+	for {
+		open := strings.IndexByte(text, '{')
+		if open < 0 {
+			return out
+		}
+		end := strings.IndexByte(text[open:], '}')
+		if end < 0 {
+			return out
+		}
+		out = append(out, text[open+1:open+end])
+		text = text[open+end+1:]
+	}
+}
+
+// assLeadingOverrides returns the override blocks a Text field opens with.
+func assLeadingOverrides(text string) string {
+	i := 0
+	for i < len(text) && text[i] == '{' {
+		end := strings.IndexByte(text[i:], '}')
+		if end < 0 {
+			break
+		}
+		i += end + 1
+	}
+	return text[:i]
+}
+
+// assPlainText strips override blocks and decodes the ASS line-break escapes:
+// \N is a hard break, \n a soft one (rendered as a break under WrapStyle 2),
+// \h a non-breaking space.
+func assPlainText(text string) string {
+	var sb strings.Builder
+	depth := 0
+	for _, r := range text {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}' && depth > 0:
+			depth--
+		case depth == 0:
+			sb.WriteRune(r)
+		}
+	}
+	plain := sb.String()
+	plain = strings.ReplaceAll(plain, `\N`, "\n")
+	plain = strings.ReplaceAll(plain, `\n`, "\n")
+	plain = strings.ReplaceAll(plain, `\h`, "\u00a0")
+	return strings.TrimSpace(plain)
+}
+
+// assEncodeText is assPlainText's inverse for text coming back from the
+// pipeline. A literal brace would open an override block, so it is swapped
+// for its full-width form rather than dropped.
+func assEncodeText(text string) string {
+	text = strings.NewReplacer("{", "｛", "}", "｝", "\u00a0", `\h`).Replace(text)
+	return strings.ReplaceAll(text, "\n", `\N`)
+}
@@ -0,0 +1,114 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fansubASS = `[Script Info]
+Title: Episode 1
+ScriptType: v4.00+
+PlayResX: 1920
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,微软雅黑,60,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1
+Style: Sign,Arial,40,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,8,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Comment: 0,0:00:00.00,0:00:01.00,Default,,0,0,0,,timing note
+Dialogue: 0,0:00:01.50,0:00:04.00,Default,Ann,0,0,0,,Hello, {\i1}dear{\i0} friend.\NHow are you?
+Dialogue: 0,0:00:05.00,0:00:07.25,Sign,,0,0,0,,{\an8\pos(960,80)}THE OLD MILL
+Dialogue: 1,0:00:05.00,0:00:07.25,Sign,,0,0,0,,{\p1}m 0 0 l 100 0 100 100{\p0}
+`
+
+func TestParseASS_DialogueBecomesBlocks(t *testing.T) {
+	doc, err := ParseASS(fansubASS)
+	require.NoError(t, err)
+
+	assert.Equal(t, CueFormatASS, doc.Format)
+	require.Len(t, doc.Blocks, 2, "comments and drawings are not dialogue")
+
+	assert.Equal(t, SubtitleBlock{Index: 1, Start: "00:00:01,500", End: "00:00:04,000",
+		Text: "Hello, dear friend.\nHow are you?"}, doc.Blocks[0])
+	assert.Equal(t, SubtitleBlock{Index: 2, Start: "00:00:05,000", End: "00:00:07,250",
+		Text: "THE OLD MILL"}, doc.Blocks[1])
+}
+
+func TestASS_UnchangedRoundTripIsLossless(t *testing.T) {
+	doc, err := ParseASS(fansubASS)
+	require.NoError(t, err)
+
+	assert.Equal(t, fansubASS, doc.Serialize())
+}
+
+func TestASS_TranslationKeepsStylesAndLeadingOverrides(t *testing.T) {
+	doc, err := ParseASS(fansubASS)
+	require.NoError(t, err)
+
+	translated := append([]SubtitleBlock(nil), doc.Blocks...)
+	translated[0].Text = "你好，親愛的朋友。\n你好嗎？"
+	translated[1].Text = "老磨坊"
+	out := doc.WithBlocks(translated).Serialize()
+
+	assert.Contains(t, out, "Style: Default,微软雅黑,60", "the style table is never rewritten")
+	assert.Contains(t, out, `Dialogue: 0,0:00:01.50,0:00:04.00,Default,Ann,0,0,0,,你好，親愛的朋友。\N你好嗎？`)
+	assert.Contains(t, out, `Dialogue: 0,0:00:05.00,0:00:07.25,Sign,,0,0,0,,{\an8\pos(960,80)}老磨坊`)
+	assert.Contains(t, out, `{\p1}m 0 0 l 100 0 100 100{\p0}`, "drawings pass through")
+	assert.Contains(t, out, "Comment: 0,0:00:00.00,0:00:01.00,Default,,0,0,0,,timing note")
+}
+
+func TestASS_DroppedBlockDropsItsEvent(t *testing.T) {
+	doc, err := ParseASS(fansubASS)
+	require.NoError(t, err)
+
+	out := doc.WithBlocks(doc.Blocks[1:]).Serialize()
+	assert.NotContains(t, out, "How are you?")
+	assert.Contains(t, out, "THE OLD MILL")
+}
+
+func TestASS_EncodesBracesAndBreaks(t *testing.T) {
+	doc := &Document{Format: CueFormatASS, Blocks: []SubtitleBlock{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:02,005", Text: "a {b}\nc"},
+	}}
+	out := doc.Serialize()
+
+	assert.Contains(t, out, "[V4+ Styles]")
+	assert.Contains(t, out, `Dialogue: 0,0:00:01.00,0:00:02.01,Default,,0,0,0,,a ｛b｝\Nc`)
+
+	back, err := ParseASS(out)
+	require.NoError(t, err)
+	require.Len(t, back.Blocks, 1)
+	assert.Equal(t, "00:00:02,010", back.Blocks[0].End, "ASS carries centiseconds only")
+}
+
+func TestParseASS_SSAScript(t *testing.T) {
+	input := strings.Join([]string{
+		"[Script Info]",
+		"ScriptType: v4.00",
+		"",
+		"[V4 Styles]",
+		"Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, TertiaryColour, BackColour, Bold, Italic, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, AlphaLevel, Encoding",
+		"Style: Default,Arial,20,16777215,255,0,0,0,0,1,2,2,2,10,10,10,0,1",
+		"",
+		"[Events]",
+		"Format: Marked, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text",
+		"Dialogue: Marked=0,0:00:01.00,0:00:02.00,Default,,0000,0000,0000,,Hi there",
+		"",
+	}, "\r\n")
+
+	doc, err := ParseASS(input)
+	require.NoError(t, err)
+	assert.Equal(t, CueFormatSSA, doc.Format)
+	require.Len(t, doc.Blocks, 1)
+	assert.Equal(t, "Hi there", doc.Blocks[0].Text)
+}
+
+func TestParseASS_MalformedDialogue(t *testing.T) {
+	_, err := ParseASS("[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\nDialogue: 0,bad,0:00:02.00,Default,,0,0,0,,x\n")
+	assert.Error(t, err)
+}
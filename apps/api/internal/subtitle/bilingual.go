@@ -0,0 +1,159 @@
+package subtitle
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vido/api/internal/models"
+)
+
+// Bilingual output.
+//
+// A bilingual run delivers the translation AND the source line it came from
+// in one file, for viewers who follow the original dialogue and want the
+// translation beside it. Only the translate route has two languages to pair:
+// an embedded Chinese track delivered as-is or converted by OpenCC has no
+// source line worth keeping, so those routes deliver the plain zh-Hant
+// sidecar whatever the mode says.
+//
+// The file gets its own placer tag (Movie.zh-Hant.en.srt) so it never
+// overwrites — or satisfies the pre-flight for — the translated-only sidecar,
+// and the run row records the mode so a resume lookup cannot mistake one for
+// the other. The segment cache is untouched: a cue's translation is the same
+// whichever layout delivers it.
+
+// bilingualLanguage is the placer tag of a bilingual sidecar: the delivered
+// language first, then the source it is paired with.
+const bilingualLanguage = deliveredLanguage + ".en"
+
+// OutputModePolicy is the narrow port over the media libraries: which output
+// mode the library an item belongs to asks for. main.go adapts it over the
+// media library repository.
+type OutputModePolicy interface {
+	SubtitleOutputMode(ctx context.Context, libraryID string) (models.SubtitleOutputMode, error)
+}
+
+// WithOutputModePolicy injects the OPTIONAL per-library output mode lookup.
+// Nil-safe: unwired means every item is delivered translated-only unless its
+// run asks otherwise.
+func WithOutputModePolicy(policy OutputModePolicy) PipelineOption {
+	return func(p *Pipeline) { p.outputModes = policy }
+}
+
+// resolveOutputMode picks the layout for one item: the run's own override,
+// then its library's setting, then translated-only. A failed library lookup
+// is not worth the item — it falls back to the translated sidecar.
+func (p *Pipeline) resolveOutputMode(ctx context.Context, ref MediaRef, item *MediaItem, opts ProcessItemOptions) models.SubtitleOutputMode {
+	mode := opts.OutputMode
+	if mode == models.SubtitleOutputDefault && p.outputModes != nil && item.LibraryID != "" {
+		libraryMode, err := p.outputModes.SubtitleOutputMode(ctx, item.LibraryID)
+		if err != nil {
+			p.logger.Warn("library output mode lookup failed — delivering translated only",
+				"media_id", ref.ID, "media_type", ref.MediaType, "library_id", item.LibraryID, "error", err)
+		} else {
+			mode = libraryMode
+		}
+	}
+	if !mode.IsBilingual() {
+		return models.SubtitleOutputTranslated
+	}
+	return mode
+}
+
+// outputModeVersion is the RunVersion.OutputMode value for mode: "" for the
+// translated sidecar, so every run row written before bilingual mode still
+// matches its resume lookup.
+func outputModeVersion(mode models.SubtitleOutputMode) string {
+	if !mode.IsBilingual() {
+		return ""
+	}
+	return string(mode)
+}
+
+// sidecarLanguage is the placer tag a run in mode writes under.
+func sidecarLanguage(mode models.SubtitleOutputMode) string {
+	if mode.IsBilingual() {
+		return bilingualLanguage
+	}
+	return deliveredLanguage
+}
+
+// serializeBilingual writes the translated track paired with its source, cue
+// by cue. The two slices share Index and timing (checkTimestampInvariant has
+// already held them to it), so the pairing is by Index.
+func serializeBilingual(mode models.SubtitleOutputMode, source, translated []SubtitleBlock) ([]byte, CueFormat) {
+	original := make(map[int]string, len(source))
+	for _, b := range source {
+		original[b.Index] = b.Text
+	}
+	if mode == models.SubtitleOutputBilingualASS {
+		return []byte(serializeBilingualASS(translated, original)), CueFormatASS
+	}
+	return []byte(serializeBilingualSRT(translated, original)), CueFormatSRT
+}
+
+// serializeBilingualSRT stacks the translation over the source line in each
+// cue. A cue that kept its English original (a stubborn cue) is written once
+// rather than twice.
+func serializeBilingualSRT(translated []SubtitleBlock, original map[int]string) string {
+	stacked := make([]SubtitleBlock, len(translated))
+	for i, b := range translated {
+		if src := strings.TrimSpace(original[b.Index]); src != "" && src != strings.TrimSpace(b.Text) {
+			b.Text = b.Text + "\n" + src
+		}
+		stacked[i] = b
+	}
+	return SerializeSRT(stacked)
+}
+
+// Bilingual ASS styles. Both sit bottom-centre; the translation's larger
+// vertical margin keeps it above the smaller source line.
+const (
+	bilingualTopStyle    = "Translation"
+	bilingualBottomStyle = "Source"
+)
+
+// serializeBilingualASS writes a fresh ASS script with the translation in
+// the top style and the source in the bottom one, one Dialogue event each.
+// A routed ASS source's own styling is not carried over: its style table was
+// designed for one line per cue, not two.
+func serializeBilingualASS(translated []SubtitleBlock, original map[int]string) string {
+	fields := assFormatDefaults[CueFormatASS]
+	var sb strings.Builder
+	for _, line := range []string{
+		"[Script Info]",
+		"ScriptType: v4.00+",
+		"WrapStyle: 0",
+		"",
+		"[V4+ Styles]",
+		"Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding",
+		"Style: " + bilingualTopStyle + ",Arial,22,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,2,10,10,40,1",
+		"Style: " + bilingualBottomStyle + ",Arial,16,&H00C8C8C8,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,1,1,2,10,10,12,1",
+		"",
+		"[Events]",
+		"Format: " + strings.Join(fields, ", "),
+	} {
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+
+	for _, b := range translated {
+		sb.WriteString(bilingualDialogue(b, bilingualTopStyle, b.Text))
+		if src := strings.TrimSpace(original[b.Index]); src != "" && src != strings.TrimSpace(b.Text) {
+			sb.WriteString(bilingualDialogue(b, bilingualBottomStyle, src))
+		}
+	}
+	return sb.String()
+}
+
+// bilingualDialogue renders one Dialogue line of the bilingual script.
+func bilingualDialogue(b SubtitleBlock, style, text string) string {
+	start, end := "0:00:00.00", "0:00:00.00"
+	if d, err := ParseCueTime(b.Start); err == nil {
+		start = formatASSTime(d)
+	}
+	if d, err := ParseCueTime(b.End); err == nil {
+		end = formatASSTime(d)
+	}
+	return "Dialogue: " + strings.Join([]string{"0", start, end, style, "", "0", "0", "0", "", assEncodeText(text)}, ",") + "\n"
+}
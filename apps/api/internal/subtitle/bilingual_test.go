@@ -0,0 +1,147 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+)
+
+// fakeOutputModes answers every library with one mode.
+type fakeOutputModes struct {
+	mode    models.SubtitleOutputMode
+	err     error
+	lookups []string
+}
+
+func (f *fakeOutputModes) SubtitleOutputMode(_ context.Context, libraryID string) (models.SubtitleOutputMode, error) {
+	f.lookups = append(f.lookups, libraryID)
+	return f.mode, f.err
+}
+
+func TestSerializeBilingualSRT_StacksTranslationOverSource(t *testing.T) {
+	source := cues("Good morning.", "OK")
+	translated := cues("早安。", "OK")
+
+	payload, format := serializeBilingual(models.SubtitleOutputBilingualSRT, source, translated)
+	assert.Equal(t, CueFormatSRT, format)
+
+	blocks, err := ParseSRT(string(payload))
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	assert.Equal(t, "早安。\nGood morning.", blocks[0].Text)
+	assert.Equal(t, "OK", blocks[1].Text, "a cue that kept its original is not written twice")
+	assert.Equal(t, source[0].Start, blocks[0].Start)
+}
+
+func TestSerializeBilingualASS_UsesTopAndBottomStyles(t *testing.T) {
+	source := cues("Good morning.")
+	translated := cues("早安。")
+
+	payload, format := serializeBilingual(models.SubtitleOutputBilingualASS, source, translated)
+	assert.Equal(t, CueFormatASS, format)
+
+	doc, err := ParseASS(string(payload))
+	require.NoError(t, err)
+	require.Len(t, doc.Blocks, 2)
+
+	text := string(payload)
+	assert.Contains(t, text, "Style: Translation,")
+	assert.Contains(t, text, "Style: Source,")
+	assert.Contains(t, text, ",Translation,,0,0,0,,早安。")
+	assert.Contains(t, text, ",Source,,0,0,0,,Good morning.")
+}
+
+func TestProcessItem_LibraryBilingualModeIsPlacedUnderItsOwnTag(t *testing.T) {
+	policy := &fakeOutputModes{mode: models.SubtitleOutputBilingualASS}
+	h := newItemHarness(t, translateDecision("Good morning."), WithOutputModePolicy(policy))
+	h.media.item.LibraryID = "lib-anime"
+
+	_, err := h.pipeline.ProcessItem(context.Background(), h.ref, ProcessItemOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"lib-anime"}, policy.lookups)
+	require.Len(t, h.placer.requests, 1)
+	req := h.placer.requests[0]
+	assert.Equal(t, "zh-Hant.en", req.Language)
+	assert.Equal(t, "ass", req.Format)
+	assert.Contains(t, string(req.SubtitleData), "早安")
+	assert.Contains(t, string(req.SubtitleData), "Good morning.")
+
+	final := h.runs.lastUpdate(t)
+	assert.Equal(t, string(models.SubtitleOutputBilingualASS), final.OutputMode)
+	assert.Equal(t, models.SubtitleRunCompleted, final.Status)
+
+	last := h.media.writes[len(h.media.writes)-1]
+	assert.Equal(t, deliveredLanguage, last.language, "the media row still reports a zh-Hant subtitle")
+}
+
+// TestProcessItem_BilingualReusesTheSegmentCache — the output mode is not
+// part of the cue key, so switching layouts costs no translation.
+func TestProcessItem_BilingualReusesTheSegmentCache(t *testing.T) {
+	h := newItemHarness(t, translateDecision("Good morning.", "Good night."))
+
+	_, err := h.pipeline.ProcessItem(context.Background(), h.ref, ProcessItemOptions{})
+	require.NoError(t, err)
+	calls := len(h.trans.calls)
+	require.NotZero(t, calls)
+
+	_, err = h.pipeline.ProcessItem(context.Background(), h.ref,
+		ProcessItemOptions{OutputMode: models.SubtitleOutputBilingualSRT})
+	require.NoError(t, err)
+
+	assert.Len(t, h.trans.calls, calls, "every cue must come from the cache")
+	require.Len(t, h.placer.requests, 2)
+	assert.Equal(t, "zh-Hant.en", h.placer.requests[1].Language)
+	assert.Contains(t, string(h.placer.requests[1].SubtitleData), "早安\nGood morning.")
+}
+
+func TestProcessItem_RunOutputModeOverridesTheLibrary(t *testing.T) {
+	policy := &fakeOutputModes{mode: models.SubtitleOutputBilingualASS}
+	h := newItemHarness(t, translateDecision("Good morning."), WithOutputModePolicy(policy))
+	h.media.item.LibraryID = "lib-anime"
+
+	_, err := h.pipeline.ProcessItem(context.Background(), h.ref,
+		ProcessItemOptions{OutputMode: models.SubtitleOutputTranslated})
+	require.NoError(t, err)
+
+	assert.Empty(t, policy.lookups)
+	require.Len(t, h.placer.requests, 1)
+	assert.Equal(t, deliveredLanguage, h.placer.requests[0].Language)
+	assert.Empty(t, h.runs.lastUpdate(t).OutputMode)
+}
+
+func TestProcessItem_OutputModeLookupFailureDeliversTranslatedOnly(t *testing.T) {
+	policy := &fakeOutputModes{err: errors.New("database is locked")}
+	h := newItemHarness(t, translateDecision("Good morning."), WithOutputModePolicy(policy))
+	h.media.item.LibraryID = "lib-anime"
+
+	_, err := h.pipeline.ProcessItem(context.Background(), h.ref, ProcessItemOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, h.placer.requests, 1)
+	assert.Equal(t, deliveredLanguage, h.placer.requests[0].Language)
+}
+
+// TestProcessItem_BilingualOnlyAppliesToTheTranslateRoute — an embedded
+// Traditional track has no source line to pair, so it ships as the plain
+// sidecar and the run row does not claim a bilingual delivery.
+func TestProcessItem_BilingualOnlyAppliesToTheTranslateRoute(t *testing.T) {
+	h := newItemHarness(t, RouteDecision{
+		Kind:            RouteDeliverDirect,
+		Track:           &ExtractedTrack{StreamIndex: 3, Language: "chi", Blocks: cues("早安")},
+		DetectedVariant: LangTraditional,
+	})
+
+	_, err := h.pipeline.ProcessItem(context.Background(), h.ref,
+		ProcessItemOptions{OutputMode: models.SubtitleOutputBilingualSRT})
+	require.NoError(t, err)
+
+	require.Len(t, h.placer.requests, 1)
+	assert.Equal(t, deliveredLanguage, h.placer.requests[0].Language)
+	assert.Empty(t, h.runs.lastUpdate(t).OutputMode)
+}
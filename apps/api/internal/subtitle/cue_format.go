@@ -0,0 +1,204 @@
+package subtitle
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CueFormat names a subtitle container the cue codec can read and write.
+// The values double as the sidecar file extension.
+type CueFormat string
+
+const (
+	CueFormatSRT CueFormat = "srt"
+	CueFormatASS CueFormat = "ass"
+	CueFormatSSA CueFormat = "ssa"
+	CueFormatVTT CueFormat = "vtt"
+)
+
+// sidecarFormats is the order the pre-flight probes for an existing sidecar:
+// SRT first because every route that cannot keep styling still writes it.
+var sidecarFormats = []CueFormat{CueFormatSRT, CueFormatASS, CueFormatSSA, CueFormatVTT}
+
+// ParseCueFormat maps a format hint or file extension ("ass", ".vtt",
+// "webvtt", "subrip") onto a CueFormat. ok is false for anything the codec
+// cannot round-trip.
+func ParseCueFormat(s string) (CueFormat, bool) {
+	switch strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), ".")) {
+	case "srt", "subrip":
+		return CueFormatSRT, true
+	case "ass":
+		return CueFormatASS, true
+	case "ssa":
+		return CueFormatSSA, true
+	case "vtt", "webvtt":
+		return CueFormatVTT, true
+	default:
+		return "", false
+	}
+}
+
+// SniffCueFormat classifies subtitle content by its header. Only the first
+// few hundred bytes are read; anything without an ASS or WebVTT header is
+// treated as SRT, which is also what the legacy callers assumed.
+func SniffCueFormat(content []byte) CueFormat {
+	head := strings.TrimPrefix(string(content[:min(len(content), 500)]), "\xEF\xBB\xBF")
+	lower := strings.ToLower(strings.ReplaceAll(head, "\r\n", "\n"))
+	switch {
+	case strings.HasPrefix(strings.TrimLeft(head, " \t\r\n"), "WEBVTT"):
+		return CueFormatVTT
+	case strings.Contains(lower, "[v4+ styles]"), strings.Contains(lower, "scripttype: v4.00+"):
+		return CueFormatASS
+	case strings.Contains(lower, "[v4 styles]"), strings.Contains(lower, "scripttype: v4.00\n"):
+		return CueFormatSSA
+	case strings.Contains(lower, "[script info]"):
+		return CueFormatASS
+	default:
+		return CueFormatSRT
+	}
+}
+
+// Document is the format-neutral view of one subtitle file.
+//
+// Blocks is the only part the pipeline edits: one SubtitleBlock per spoken
+// cue, with Start/End always in SRT notation (00:00:01,000) whatever the
+// source format, so the FR17 timestamp invariant and the segment cache keep
+// comparing like with like. Everything the SRT model cannot carry — ASS
+// script info, styles, override tags, drawing events, the WebVTT header —
+// stays in the unexported per-format state and is written back verbatim by
+// Serialize.
+type Document struct {
+	Format CueFormat
+	Blocks []SubtitleBlock
+
+	ass *assScript
+	vtt *vttFile
+}
+
+// ParseDocument parses content as the given format. An empty format sniffs
+// the content instead.
+func ParseDocument(content string, format CueFormat) (*Document, error) {
+	if format == "" {
+		format = SniffCueFormat([]byte(content))
+	}
+	switch format {
+	case CueFormatSRT:
+		blocks, err := ParseSRT(content)
+		if err != nil {
+			return nil, err
+		}
+		return &Document{Format: CueFormatSRT, Blocks: blocks}, nil
+	case CueFormatASS, CueFormatSSA:
+		return ParseASS(content)
+	case CueFormatVTT:
+		return ParseVTT(content)
+	default:
+		return nil, fmt.Errorf("unsupported subtitle format: %q", format)
+	}
+}
+
+// ParseDocumentFile is ParseDocument with the format taken from the file
+// extension, falling back to sniffing when the extension says nothing.
+func ParseDocumentFile(path string, content []byte) (*Document, error) {
+	format, _ := ParseCueFormat(filepath.Ext(path))
+	return ParseDocument(string(content), format)
+}
+
+// WithBlocks returns a copy of the document carrying blocks as its dialogue.
+// Cues are matched by Index: a source cue with no counterpart in blocks (an
+// SDH annotation the filter removed) is dropped from the output, and every
+// non-dialogue element of the source is kept as-is.
+func (d *Document) WithBlocks(blocks []SubtitleBlock) *Document {
+	out := *d
+	out.Blocks = append([]SubtitleBlock(nil), blocks...)
+	return &out
+}
+
+// Serialize writes the document back in its own format.
+func (d *Document) Serialize() string {
+	switch d.Format {
+	case CueFormatASS, CueFormatSSA:
+		if d.ass != nil {
+			return d.ass.serialize(d.Blocks)
+		}
+		return newASSScript(d.Format).serialize(d.Blocks)
+	case CueFormatVTT:
+		if d.vtt != nil {
+			return d.vtt.serialize(d.Blocks)
+		}
+		return (&vttFile{header: "WEBVTT"}).serialize(d.Blocks)
+	default:
+		return SerializeSRT(d.Blocks)
+	}
+}
+
+// cueTimePattern accepts every timestamp notation the three codecs produce:
+// SRT (00:00:01,000), WebVTT (00:00:01.000 or 00:01.000) and ASS
+// (0:00:01.00, centiseconds).
+var cueTimePattern = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{2})[,.](\d{1,3})$`)
+
+// ParseCueTime parses a cue timestamp in SRT, WebVTT or ASS notation.
+func ParseCueTime(ts string) (time.Duration, error) {
+	m := cueTimePattern.FindStringSubmatch(strings.TrimSpace(ts))
+	if m == nil {
+		return 0, fmt.Errorf("invalid cue timestamp %q", ts)
+	}
+	hours := 0
+	if m[1] != "" {
+		hours, _ = strconv.Atoi(m[1])
+	}
+	minutes, _ := strconv.Atoi(m[2])
+	seconds, _ := strconv.Atoi(m[3])
+	// The fraction is scaled by its width: ".5" is 500 ms, ".05" 50 ms,
+	// ".005" 5 ms — ASS centiseconds and SRT milliseconds share one rule.
+	frac := m[4]
+	millis, _ := strconv.Atoi(frac + strings.Repeat("0", 3-len(frac)))
+
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(millis)*time.Millisecond, nil
+}
+
+// FormatSRTTime renders d in SRT notation (00:00:01,000). Negative durations
+// clamp to zero.
+func FormatSRTTime(d time.Duration) string {
+	h, m, s, ms := splitCueTime(d)
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, ms)
+}
+
+// formatVTTTime renders d in WebVTT notation (00:00:01.000).
+func formatVTTTime(d time.Duration) string {
+	h, m, s, ms := splitCueTime(d)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
+}
+
+// formatASSTime renders d in ASS notation (0:00:01.00). ASS only carries
+// centiseconds, so the millisecond digit is rounded away.
+func formatASSTime(d time.Duration) string {
+	d = (d + 5*time.Millisecond).Truncate(10 * time.Millisecond)
+	h, m, s, ms := splitCueTime(d)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", h, m, s, ms/10)
+}
+
+func splitCueTime(d time.Duration) (h, m, s, ms int) {
+	if d < 0 {
+		d = 0
+	}
+	total := int(d / time.Millisecond)
+	return total / 3600000, total / 60000 % 60, total / 1000 % 60, total % 1000
+}
+
+// toSRTTime normalizes a timestamp from any supported notation to SRT
+// notation, the one SubtitleBlock carries.
+func toSRTTime(ts string) (string, error) {
+	d, err := ParseCueTime(ts)
+	if err != nil {
+		return "", err
+	}
+	return FormatSRTTime(d), nil
+}
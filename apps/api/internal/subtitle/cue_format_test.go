@@ -0,0 +1,73 @@
+package subtitle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSniffCueFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    CueFormat
+	}{
+		{"srt", "1\n00:00:01,000 --> 00:00:02,000\nhi\n", CueFormatSRT},
+		{"vtt with BOM", "\xEF\xBB\xBFWEBVTT\n\n00:01.000 --> 00:02.000\nhi\n", CueFormatVTT},
+		{"ass", "[Script Info]\nScriptType: v4.00+\n", CueFormatASS},
+		{"ass without script type", "[Script Info]\nTitle: x\n", CueFormatASS},
+		{"ssa", "[Script Info]\r\nScriptType: v4.00\r\n", CueFormatSSA},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SniffCueFormat([]byte(tt.content)))
+		})
+	}
+}
+
+func TestParseCueTime_AllNotations(t *testing.T) {
+	tests := map[string]time.Duration{
+		"00:00:01,000": time.Second,
+		"01:02:03,456": time.Hour + 2*time.Minute + 3*time.Second + 456*time.Millisecond,
+		"00:01.500":    1500 * time.Millisecond,
+		"0:00:01.25":   1250 * time.Millisecond,
+		"10:00:00.000": 10 * time.Hour,
+	}
+	for in, want := range tests {
+		got, err := ParseCueTime(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := ParseCueTime("1.5")
+	assert.Error(t, err)
+}
+
+func TestCueTimeFormatters(t *testing.T) {
+	d := time.Hour + 2*time.Minute + 3*time.Second + 456*time.Millisecond
+	assert.Equal(t, "01:02:03,456", FormatSRTTime(d))
+	assert.Equal(t, "01:02:03.456", formatVTTTime(d))
+	assert.Equal(t, "1:02:03.46", formatASSTime(d))
+	assert.Equal(t, "00:00:00,000", FormatSRTTime(-time.Second))
+}
+
+func TestParseDocument_ConvertsBetweenFormats(t *testing.T) {
+	doc, err := ParseDocument("1\n00:00:01,000 --> 00:00:02,000\nhi\n", "")
+	require.NoError(t, err)
+	require.Equal(t, CueFormatSRT, doc.Format)
+
+	doc.Format = CueFormatVTT
+	assert.Equal(t, "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nhi\n", doc.Serialize())
+}
+
+func TestParseCueFormat(t *testing.T) {
+	for in, want := range map[string]CueFormat{".srt": CueFormatSRT, "subrip": CueFormatSRT, "ASS": CueFormatASS, ".ssa": CueFormatSSA, "webvtt": CueFormatVTT} {
+		got, ok := ParseCueFormat(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, ok := ParseCueFormat(".sub")
+	assert.False(t, ok)
+}
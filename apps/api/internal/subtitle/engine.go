@@ -10,6 +10,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/vido/api/internal/metrics"
 	"github.com/vido/api/internal/models"
 	"github.com/vido/api/internal/services"
 	"github.com/vido/api/internal/sse"
@@ -77,6 +78,7 @@ type Engine struct {
 	converter          *Converter
 	placer             *Placer
 	terminologyService services.TerminologyCorrectionServiceInterface
+	aligner            SubtitleAligner
 	sseHub             *sse.Hub
 	movieRepo          SubtitleStatusUpdater
 	seriesRepo         SubtitleStatusUpdater
@@ -109,6 +111,18 @@ func (e *Engine) SetTerminologyService(svc services.TerminologyCorrectionService
 	e.terminologyService = svc
 }
 
+// SubtitleAligner is the narrow port the engine needs from *Aligner.
+type SubtitleAligner interface {
+	Align(ctx context.Context, req AlignRequest) (*AlignOutcome, error)
+}
+
+// SetAligner sets the optional timing aligner. When set, a downloaded
+// subtitle is re-timed against the media file before it is placed — using
+// the embedded track only, so a search never waits on or pays for ASR.
+func (e *Engine) SetAligner(a SubtitleAligner) {
+	e.aligner = a
+}
+
 // ConversionPolicy controls how the engine handles simplified→traditional conversion.
 type ConversionPolicy int
 
@@ -153,7 +167,7 @@ func (e *Engine) Process(ctx context.Context, mediaID, mediaType, mediaFilePath
 	e.broadcastStatus(mediaID, mediaType, StageSearching, "Searching subtitle providers...")
 	e.updateStatus(ctx, mediaID, mediaType, models.SubtitleStatusSearching)
 
-	results, err := e.search(ctx, query)
+	results, err := e.search(ctx, AttachFileHash(query, mediaFilePath))
 	if err != nil {
 		return e.handleFailure(ctx, mediaID, mediaType, fmt.Errorf("search: %w", err))
 	}
@@ -202,6 +216,14 @@ func (e *Engine) Process(ctx context.Context, mediaID, mediaType, mediaFilePath
 		}
 	}
 
+	// Stage 4.6: Timing alignment (optional). A subtitle from another release
+	// of the title is shifted onto this file's timing; no reference, or no
+	// confident fit, places it as downloaded.
+	if e.aligner != nil && mediaFilePath != "" {
+		e.broadcastStatus(mediaID, mediaType, StageCorrecting, "Aligning subtitle timing...")
+		convertedData = e.align(ctx, mediaID, mediaFilePath, convertedData, match.Format)
+	}
+
 	// Stage 5: Place
 	e.broadcastStatus(mediaID, mediaType, StagePlacing, "Placing subtitle file...")
 	placeResult, err := e.placer.Place(PlaceRequest{
@@ -228,6 +250,39 @@ func (e *Engine) Process(ctx context.Context, mediaID, mediaType, mediaFilePath
 	}
 }
 
+// align re-times data against the media file, returning data unchanged when
+// alignment errors or declines.
+func (e *Engine) align(ctx context.Context, mediaID, mediaFilePath string, data []byte, format string) []byte {
+	out, err := e.aligner.Align(ctx, AlignRequest{
+		MediaID:   mediaID,
+		MediaPath: mediaFilePath,
+		Data:      data,
+		Format:    format,
+	})
+	if err != nil {
+		slog.Debug("Subtitle timing left as downloaded", "error", err, "mediaID", mediaID)
+		return data
+	}
+	return out.Data
+}
+
+// AttachFileHash fills query.FileHash with the OpenSubtitles moviehash of the
+// media file, so hash-capable providers can match the exact release before
+// falling back to the title. A file that cannot be hashed (missing, or too
+// small to be a real release) is searched by title alone.
+func AttachFileHash(query providers.SubtitleQuery, mediaFilePath string) providers.SubtitleQuery {
+	if query.FileHash != "" || mediaFilePath == "" {
+		return query
+	}
+	hash, err := providers.CalculateOpenSubHash(mediaFilePath)
+	if err != nil {
+		slog.Debug("Subtitle search without file hash", "path", mediaFilePath, "error", err)
+		return query
+	}
+	query.FileHash = hash
+	return query
+}
+
 // search queries all providers in parallel and merges results.
 func (e *Engine) search(ctx context.Context, query providers.SubtitleQuery) ([]providers.SubtitleResult, error) {
 	var (
@@ -386,8 +441,19 @@ func (e *Engine) updateSubtitleFound(ctx context.Context, mediaID, mediaType, pa
 	}
 }
 
+// observeRun counts a run on /metrics once it reaches a terminal stage.
+// engine is "search" for the provider engine and "pipeline" for the
+// extract/transcribe/translate chain.
+func observeRun(engine string, stage PipelineStage) {
+	switch stage {
+	case StageComplete, StageFailed, StageSkipped:
+		metrics.SubtitleRuns.WithLabelValues(engine, string(stage)).Inc()
+	}
+}
+
 // broadcastStatus sends an SSE event for the current pipeline stage.
 func (e *Engine) broadcastStatus(mediaID, mediaType string, stage PipelineStage, message string) {
+	observeRun("search", stage)
 	if e.sseHub == nil {
 		return
 	}
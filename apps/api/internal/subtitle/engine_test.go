@@ -506,6 +506,62 @@ func TestEngine_SetTerminologyService(t *testing.T) {
 	assert.Equal(t, svc, engine.terminologyService)
 }
 
+// stubAligner shifts nothing but records what it was asked and answers with
+// a fixed outcome.
+type stubAligner struct {
+	out  *AlignOutcome
+	err  error
+	reqs []AlignRequest
+}
+
+func (s *stubAligner) Align(_ context.Context, req AlignRequest) (*AlignOutcome, error) {
+	s.reqs = append(s.reqs, req)
+	return s.out, s.err
+}
+
+func TestEngine_Process_AlignsBeforePlacing(t *testing.T) {
+	prov := &mockProvider{
+		name:         "assrt",
+		searchResult: []providers.SubtitleResult{{ID: "1", Source: "assrt", Format: "srt"}},
+		downloadData: []byte("1\n00:00:01,000 --> 00:00:03,000\n這是繁體中文\n"),
+	}
+	engine, mediaPath := newTestEngine(t, []providers.SubtitleProvider{prov}, nil)
+	aligned := []byte("1\n00:00:02,500 --> 00:00:04,500\n這是繁體中文\n")
+	aligner := &stubAligner{out: &AlignOutcome{Applied: true, Data: aligned}}
+	engine.SetAligner(aligner)
+
+	result := engine.Process(context.Background(), "movie-1", "movie", mediaPath,
+		providers.SubtitleQuery{Title: "Test"}, "1080p")
+	require.True(t, result.Success)
+
+	require.Len(t, aligner.reqs, 1)
+	assert.Equal(t, mediaPath, aligner.reqs[0].MediaPath)
+	assert.False(t, aligner.reqs[0].AllowPaid, "a search never spends ASR budget on timing")
+	assert.False(t, aligner.reqs[0].AllowSlow, "nor waits on a transcription, even a free one")
+	placed, err := os.ReadFile(result.SubtitlePath)
+	require.NoError(t, err)
+	assert.Equal(t, aligned, placed)
+}
+
+func TestEngine_Process_AlignmentFailurePlacesAsDownloaded(t *testing.T) {
+	data := []byte("1\n00:00:01,000 --> 00:00:03,000\n這是繁體中文\n")
+	prov := &mockProvider{
+		name:         "assrt",
+		searchResult: []providers.SubtitleResult{{ID: "1", Source: "assrt", Format: "srt"}},
+		downloadData: data,
+	}
+	engine, mediaPath := newTestEngine(t, []providers.SubtitleProvider{prov}, nil)
+	engine.SetAligner(&stubAligner{err: ErrSubtitleNoTimingReference})
+
+	result := engine.Process(context.Background(), "movie-1", "movie", mediaPath,
+		providers.SubtitleQuery{Title: "Test"}, "1080p")
+	require.True(t, result.Success)
+
+	placed, err := os.ReadFile(result.SubtitlePath)
+	require.NoError(t, err)
+	assert.Equal(t, data, placed)
+}
+
 // --- Story sub-1-3 AC #6.1: the [@contract-v1] PipelineStage wire value set ---
 
 // TestPipelineStageValues asserts the exact string literal of every stage
@@ -32,4 +32,14 @@ var (
 	// ErrSubtitleTimestampMismatch — the FR17 invariant broke: translated cue
 	// count or per-cue timings diverge from the source track. Consumer: sub-1-5a.
 	ErrSubtitleTimestampMismatch = errors.New("SUBTITLE_TIMESTAMP_MISMATCH: translated cue timestamps diverge from source")
+
+	// ErrSubtitleNoTimingReference — alignment found nothing to time a
+	// subtitle against: no embedded text track, and no speech reference it
+	// was allowed to use. Consumer: the sync endpoint.
+	ErrSubtitleNoTimingReference = errors.New("SUBTITLE_NO_TIMING_REFERENCE: no reference track to align subtitle timing against")
+
+	// ErrSubtitleOCRFailed — a bitmap (PGS/VobSub) track was extracted but
+	// could not be decoded or read by the OCR engine. Consumer: the router,
+	// which falls back to the next route.
+	ErrSubtitleOCRFailed = errors.New("SUBTITLE_OCR_FAILED: bitmap subtitle track could not be read")
 )
@@ -25,6 +25,8 @@ func subtitleSentinels() []struct {
 		{"no text source", ErrSubtitleNoTextSource, "SUBTITLE_NO_TEXT_SOURCE"},
 		{"translate failed", ErrSubtitleTranslateFailed, "SUBTITLE_TRANSLATE_FAILED"},
 		{"timestamp mismatch", ErrSubtitleTimestampMismatch, "SUBTITLE_TIMESTAMP_MISMATCH"},
+		{"no timing reference", ErrSubtitleNoTimingReference, "SUBTITLE_NO_TIMING_REFERENCE"},
+		{"ocr failed", ErrSubtitleOCRFailed, "SUBTITLE_OCR_FAILED"},
 	}
 }
 
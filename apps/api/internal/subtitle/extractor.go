@@ -36,10 +36,11 @@ var textSubtitleCodecs = map[string]struct{}{
 	"webvtt":   {},
 }
 
-// imageSubtitleCodecs are bitmap tracks. They are never extraction candidates —
-// turning them into text needs OCR, which M1 does not do (FR5 routes them to
-// "no usable text source"). Listed explicitly so the classification is auditable
-// rather than "anything not in the text set".
+// imageSubtitleCodecs are bitmap tracks. They are never text extraction
+// candidates — turning them into text takes the OCR route (ocr.go), and only
+// for the codecs it decodes; the rest stay "no usable text source" (FR5).
+// Listed explicitly so the classification is auditable rather than "anything
+// not in the text set".
 var imageSubtitleCodecs = map[string]struct{}{
 	"hdmv_pgs_subtitle": {},
 	"dvd_subtitle":      {},
@@ -186,11 +187,59 @@ func (e *Extractor) Extract(ctx context.Context, mediaPath, tmpDir string, strea
 		return nil, fmt.Errorf("subtitle extract: no stream indexes supplied for %s", filepath.Base(mediaPath))
 	}
 
+	outputs := make(map[int]string, len(streamIndexes))
+	for _, idx := range streamIndexes {
+		outputs[idx] = trackOutputPath(tmpDir, idx)
+	}
+	return e.run(ctx, mediaPath, buildExtractArgs(mediaPath, tmpDir, streamIndexes), streamIndexes, outputs)
+}
+
+// ExtractTracks is Extract for callers that know each track's codec: ASS/SSA
+// and WebVTT tracks are stream-copied into their own container so styles and
+// override tags survive, and every other text codec is transcoded to .srt
+// exactly as Extract does. Still ONE ffmpeg pass (FR3).
+func (e *Extractor) ExtractTracks(ctx context.Context, mediaPath, tmpDir string, tracks []services.SubtitleTrack) (map[int]string, error) {
+	if !e.available {
+		return nil, fmt.Errorf("subtitle extract: %w", services.ErrFFmpegNotAvailable)
+	}
+	if len(tracks) == 0 {
+		return nil, fmt.Errorf("subtitle extract: no stream indexes supplied for %s", filepath.Base(mediaPath))
+	}
+
+	indexes := make([]int, 0, len(tracks))
+	outputs := make(map[int]string, len(tracks))
+	for _, t := range tracks {
+		indexes = append(indexes, t.StreamIndex)
+		outputs[t.StreamIndex] = nativeTrackOutputPath(tmpDir, t)
+	}
+	return e.run(ctx, mediaPath, buildNativeExtractArgs(mediaPath, tmpDir, tracks), indexes, outputs)
+}
+
+// ExtractBitmap stream-copies one image subtitle track for OCR: PGS into a
+// .sup file, VobSub into an MPEG program stream (the only muxer ffmpeg copies
+// dvd_subtitle into without a palette sidecar). Copy, never a transcode —
+// there is no bitmap-to-text codec, and the frames must arrive untouched.
+func (e *Extractor) ExtractBitmap(ctx context.Context, mediaPath, tmpDir string, track services.SubtitleTrack) (string, error) {
+	if !e.available {
+		return "", fmt.Errorf("subtitle extract: %w", services.ErrFFmpegNotAvailable)
+	}
+	args, out, err := buildBitmapExtractArgs(mediaPath, tmpDir, track)
+	if err != nil {
+		return "", fmt.Errorf("subtitle extract: %w", err)
+	}
+	idx := track.StreamIndex
+	outputs, err := e.run(ctx, mediaPath, args, []int{idx}, map[int]string{idx: out})
+	if err != nil {
+		return "", err
+	}
+	return outputs[idx], nil
+}
+
+// run executes one ffmpeg demux pass and verifies every expected output.
+func (e *Extractor) run(ctx context.Context, mediaPath string, args []string, streamIndexes []int, expected map[int]string) (map[int]string, error) {
 	extractCtx, cancel := context.WithTimeout(ctx, e.timeout)
 	defer cancel()
 
-	args := buildExtractArgs(mediaPath, tmpDir, streamIndexes)
-
 	//nolint:gosec // mediaPath comes from a trusted DB record; tmpDir is caller-owned
 	cmd := exec.CommandContext(extractCtx, "ffmpeg", args...)
 	var stderr bytes.Buffer
@@ -216,7 +265,7 @@ func (e *Extractor) Extract(ctx context.Context, mediaPath, tmpDir string, strea
 
 	outputs := make(map[int]string, len(streamIndexes))
 	for _, idx := range streamIndexes {
-		out := trackOutputPath(tmpDir, idx)
+		out := expected[idx]
 		if _, err := os.Stat(out); err != nil {
 			return nil, extractFailure(
 				fmt.Sprintf("ffmpeg produced no output for stream %d of %s", idx, filepath.Base(mediaPath)),
@@ -257,6 +306,64 @@ func trackOutputPath(tmpDir string, streamIndex int) string {
 	return filepath.Join(tmpDir, fmt.Sprintf("track_%d.srt", streamIndex))
 }
 
+// buildBitmapExtractArgs assembles the ffmpeg invocation for ExtractBitmap.
+func buildBitmapExtractArgs(mediaPath, tmpDir string, track services.SubtitleTrack) ([]string, string, error) {
+	var muxer, ext string
+	switch strings.ToLower(strings.TrimSpace(track.Format)) {
+	case "hdmv_pgs_subtitle":
+		muxer, ext = "sup", "sup"
+	case "dvd_subtitle":
+		muxer, ext = "vob", "vob"
+	default:
+		return nil, "", fmt.Errorf("stream %d: codec %q is not an OCR bitmap codec", track.StreamIndex, track.Format)
+	}
+	out := filepath.Join(tmpDir, fmt.Sprintf("track_%d.%s", track.StreamIndex, ext))
+	return []string{
+		"-nostdin", "-y", "-i", mediaPath,
+		"-map", fmt.Sprintf("0:%d", track.StreamIndex),
+		"-c:s", "copy",
+		"-f", muxer,
+		out,
+	}, out, nil
+}
+
+// styledSubtitleCodecs are the text codecs whose styling an .srt transcode
+// would flatten. They are stream-copied instead; copy is safe because the
+// target muxer matches the source codec.
+var styledSubtitleCodecs = map[string]CueFormat{
+	"ass":    CueFormatASS,
+	"ssa":    CueFormatSSA,
+	"webvtt": CueFormatVTT,
+}
+
+// buildNativeExtractArgs is buildExtractArgs with per-codec output: styled
+// tracks keep their container via `-c:s copy`, everything else goes to .srt.
+func buildNativeExtractArgs(mediaPath, tmpDir string, tracks []services.SubtitleTrack) []string {
+	args := []string{"-nostdin", "-y", "-i", mediaPath}
+	for _, t := range tracks {
+		codec := "srt"
+		if _, ok := styledSubtitleCodecs[strings.ToLower(strings.TrimSpace(t.Format))]; ok {
+			codec = "copy"
+		}
+		args = append(args,
+			"-map", fmt.Sprintf("0:%d", t.StreamIndex),
+			"-c:s", codec,
+			nativeTrackOutputPath(tmpDir, t),
+		)
+	}
+	return args
+}
+
+// nativeTrackOutputPath is trackOutputPath with the extension of the format
+// the track is extracted as.
+func nativeTrackOutputPath(tmpDir string, t services.SubtitleTrack) string {
+	format, ok := styledSubtitleCodecs[strings.ToLower(strings.TrimSpace(t.Format))]
+	if !ok {
+		return trackOutputPath(tmpDir, t.StreamIndex)
+	}
+	return filepath.Join(tmpDir, fmt.Sprintf("track_%d.%s", t.StreamIndex, format))
+}
+
 // extractFailure wraps a failure as the sub-1-3 ErrSubtitleExtractFailed
 // sentinel so callers classify with errors.Is, and CHAINS the cause with a
 // second %w — the orchestrator (1.5b) must be able to tell a cancellation
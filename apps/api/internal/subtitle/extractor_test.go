@@ -435,3 +435,37 @@ func TestExtractor_Integration_MissingStreamClassifies(t *testing.T) {
 	assert.ErrorIs(t, err, ErrSubtitleExtractFailed,
 		fmt.Sprintf("ffmpeg failure must classify as the sub-1-3 sentinel, got: %v", err))
 }
+
+func TestBuildNativeExtractArgs_CopiesStyledCodecs(t *testing.T) {
+	tracks := []services.SubtitleTrack{
+		{StreamIndex: 2, Format: "ass"},
+		{StreamIndex: 3, Format: "subrip"},
+		{StreamIndex: 4, Format: "webvtt"},
+	}
+
+	args := buildNativeExtractArgs("/m.mkv", "/tmp/x", tracks)
+
+	assert.Equal(t, []string{
+		"-nostdin", "-y", "-i", "/m.mkv",
+		"-map", "0:2", "-c:s", "copy", filepath.Join("/tmp/x", "track_2.ass"),
+		"-map", "0:3", "-c:s", "srt", filepath.Join("/tmp/x", "track_3.srt"),
+		"-map", "0:4", "-c:s", "copy", filepath.Join("/tmp/x", "track_4.vtt"),
+	}, args)
+}
+
+func TestBuildBitmapExtractArgs_CopiesIntoTheCodecsOwnMuxer(t *testing.T) {
+	args, out, err := buildBitmapExtractArgs("/m.mkv", "/tmp/x", services.SubtitleTrack{StreamIndex: 5, Format: "hdmv_pgs_subtitle"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/x", "track_5.sup"), out)
+	assert.Equal(t, []string{
+		"-nostdin", "-y", "-i", "/m.mkv",
+		"-map", "0:5", "-c:s", "copy", "-f", "sup", out,
+	}, args)
+
+	_, out, err = buildBitmapExtractArgs("/m.mkv", "/tmp/x", services.SubtitleTrack{StreamIndex: 6, Format: "dvd_subtitle"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/x", "track_6.vob"), out)
+
+	_, _, err = buildBitmapExtractArgs("/m.mkv", "/tmp/x", services.SubtitleTrack{StreamIndex: 7, Format: "dvb_subtitle"})
+	assert.Error(t, err)
+}
@@ -99,7 +99,8 @@ func (s *repoMediaStore) loadMovie(ctx context.Context, id string) (*MediaItem,
 		SubtitleLanguage: movie.SubtitleLanguage.String,
 		// Movies carry no ShowKey: nothing shares their prompt prefix, so the
 		// D10 gate bypasses them entirely (sub-1-5b AC #5.1).
-		ShowKey: "",
+		ShowKey:   "",
+		LibraryID: movie.LibraryID.String,
 		Context: TranslateContext{
 			Title:         movie.Title,
 			OriginalTitle: movie.OriginalTitle.String,
@@ -123,8 +124,9 @@ func (s *repoMediaStore) loadSeries(ctx context.Context, id string) (*MediaItem,
 		SubtitlePath:     series.SubtitlePath.String,
 		SubtitleLanguage: series.SubtitleLanguage.String,
 		// A series row IS its own show, so it keys the gate on itself.
-		ShowKey: series.ID,
-		Context: seriesContext(series),
+		ShowKey:   series.ID,
+		LibraryID: series.LibraryID.String,
+		Context:   seriesContext(series),
 	}, nil
 }
 
@@ -161,6 +163,7 @@ func (s *repoMediaStore) loadEpisode(ctx context.Context, id string) (*MediaItem
 	// unmatched would be worse than translating it with less context.
 	if series, err := s.loadSeriesRow(ctx, episode.SeriesID); err == nil {
 		item.Context = seriesContext(series)
+		item.LibraryID = series.LibraryID.String
 	}
 	return item, nil
 }
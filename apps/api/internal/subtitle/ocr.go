@@ -0,0 +1,314 @@
+package subtitle
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vido/api/internal/services"
+)
+
+// OCR turns an embedded bitmap subtitle track (Blu-ray PGS, DVD VobSub) into
+// timed text cues: ffmpeg stream-copies the track out, the frames are decoded
+// here, and a local OCR engine reads each one. Blu-ray remuxes routinely carry
+// a studio Chinese or English track ONLY as PGS — before this, those files fell
+// through to "no text source" and paid for ASR to guess at dialogue a disc
+// author had already typed.
+//
+// It is free: the engine runs on the NAS, so the OCR route never needs cost
+// consent. It is also slow (seconds per hundred cues on a J4125), which is why
+// the router only reaches for it when no text track qualifies.
+type OCR struct {
+	extractor  BitmapExtractor
+	recognizer TextRecognizer
+	languages  map[string]bool
+	logger     *slog.Logger
+}
+
+// TrackOCR is the narrow port the router needs from *OCR.
+type TrackOCR interface {
+	// CanRead reports whether the track is a bitmap codec this OCR decodes,
+	// tagged with a language it has a model for.
+	CanRead(track services.SubtitleTrack) bool
+	// ReadTrack extracts, decodes and reads the track into cues with the
+	// original timing. The cues are cleaned but not SDH-filtered.
+	ReadTrack(ctx context.Context, mediaPath, tmpDir string, track services.SubtitleTrack) ([]SubtitleBlock, error)
+}
+
+// BitmapExtractor is the narrow port OCR needs from *Extractor.
+type BitmapExtractor interface {
+	ExtractBitmap(ctx context.Context, mediaPath, tmpDir string, track services.SubtitleTrack) (string, error)
+}
+
+// TextRecognizer is a local OCR engine (*Tesseract in production).
+type TextRecognizer interface {
+	// Languages lists the installed models by tesseract name (eng,
+	// chi_tra, chi_sim). Empty means the engine is unavailable.
+	Languages() []string
+	Recognize(ctx context.Context, img image.Image, language string) (OCRText, error)
+}
+
+// OCRText is one recognised image.
+type OCRText struct {
+	Text string
+	// Confidence is the engine's mean word confidence, 0–100.
+	Confidence float64
+}
+
+// OCR model names, as tesseract calls its traineddata.
+const (
+	OCRModelEnglish     = "eng"
+	OCRModelTraditional = "chi_tra"
+	OCRModelSimplified  = "chi_sim"
+)
+
+const (
+	// ocrModelSampleCues is how many cues decide between the Traditional and
+	// Simplified models. Each is read twice, so it stays small.
+	ocrModelSampleCues = 8
+	// ocrDefaultCueLength ends a cue whose track never says when it clears.
+	ocrDefaultCueLength = 4 * time.Second
+	// ocrMergeGap joins consecutive cues with the same text: PGS redraws the
+	// screen for a fade or a second line, and each redraw is a display set.
+	ocrMergeGap = 100 * time.Millisecond
+	// ocrCanvasMargin is the white border around a rendered frame; tesseract
+	// misses glyphs that touch the image edge.
+	ocrCanvasMargin = 10
+)
+
+// ocrCodecs are the image codecs OCR can decode. dvb_subtitle and xsub stay
+// out: neither shows up in the remuxes this serves.
+var ocrCodecs = map[string]struct{}{
+	"hdmv_pgs_subtitle": {},
+	"dvd_subtitle":      {},
+}
+
+// NewOCR wires OCR to its extractor and engine. The engine's model list is
+// read once, here — installing a model takes a container rebuild anyway.
+func NewOCR(extractor BitmapExtractor, recognizer TextRecognizer, logger *slog.Logger) *OCR {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	languages := map[string]bool{}
+	for _, l := range recognizer.Languages() {
+		languages[l] = true
+	}
+	return &OCR{
+		extractor:  extractor,
+		recognizer: recognizer,
+		languages:  languages,
+		logger:     logger.With("component", "subtitle_ocr"),
+	}
+}
+
+// CanRead implements TrackOCR. The language gate is the text path's: an `und`
+// bitmap track is never read as English.
+func (o *OCR) CanRead(track services.SubtitleTrack) bool {
+	if track.External {
+		return false
+	}
+	if _, ok := ocrCodecs[strings.ToLower(strings.TrimSpace(track.Format))]; !ok {
+		return false
+	}
+	return len(o.models(track.Language)) > 0
+}
+
+// models lists the installed models that may read a track with this tag, in
+// preference order.
+func (o *OCR) models(tag string) []string {
+	var candidates []string
+	switch {
+	case isChineseTag(tag):
+		candidates = []string{OCRModelTraditional, OCRModelSimplified}
+	case isEnglishTag(tag):
+		candidates = []string{OCRModelEnglish}
+	}
+	var out []string
+	for _, m := range candidates {
+		if o.languages[m] {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// ReadTrack implements TrackOCR. Extraction failures carry
+// ErrSubtitleExtractFailed; a track that cannot be decoded or read carries
+// ErrSubtitleOCRFailed.
+func (o *OCR) ReadTrack(ctx context.Context, mediaPath, tmpDir string, track services.SubtitleTrack) ([]SubtitleBlock, error) {
+	models := o.models(track.Language)
+	if len(models) == 0 {
+		return nil, fmt.Errorf("%w: no OCR model for stream %d tagged %q", ErrSubtitleOCRFailed, track.StreamIndex, track.Language)
+	}
+
+	path, err := o.extractor.ExtractBitmap(ctx, mediaPath, tmpDir, track)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path) //nolint:gosec // path is the extractor's own temp output
+	if err != nil {
+		return nil, fmt.Errorf("%w: read stream %d: %w", ErrSubtitleOCRFailed, track.StreamIndex, err)
+	}
+	cues, err := decodeBitmapTrack(track.Format, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decode stream %d: %w", ErrSubtitleOCRFailed, track.StreamIndex, err)
+	}
+	if len(cues) == 0 {
+		return nil, nil
+	}
+	fillCueEnds(cues)
+
+	texts := make([]string, len(cues))
+	model, sampled, err := o.pickModel(ctx, cues, models)
+	if err != nil {
+		return nil, fmt.Errorf("%w: stream %d: %w", ErrSubtitleOCRFailed, track.StreamIndex, err)
+	}
+	for i, text := range sampled {
+		texts[i] = text
+	}
+
+	// One frame at a time, single-threaded (see NewTesseract): the pipeline's
+	// two workers are the NAS's whole CPU budget (NFR-P3), and an OCR pass
+	// fanning out would take it from the media server.
+	for i, cue := range cues {
+		if _, done := sampled[i]; done {
+			continue
+		}
+		got, err := o.recognizer.Recognize(ctx, cue.render(), model)
+		if err != nil {
+			return nil, fmt.Errorf("%w: stream %d cue %d: %w", ErrSubtitleOCRFailed, track.StreamIndex, i+1, err)
+		}
+		texts[i] = got.Text
+	}
+
+	blocks := buildOCRBlocks(cues, texts, model != OCRModelEnglish)
+	o.logger.Info("bitmap subtitle track read",
+		"media", mediaPath, "stream_index", track.StreamIndex, "codec", track.Format,
+		"model", model, "frames", len(cues), "cues", len(blocks))
+	return blocks, nil
+}
+
+// pickModel chooses between the Traditional and Simplified models by which
+// reads a spread of sample cues more confidently — the tag says only "Chinese"
+// (see isChineseTag), and the wrong model reads the other script as garbage.
+// The winner's sample reads are returned so they are not repeated.
+func (o *OCR) pickModel(ctx context.Context, cues []bitmapCue, models []string) (string, map[int]string, error) {
+	if len(models) == 1 {
+		return models[0], nil, nil
+	}
+	n := min(ocrModelSampleCues, len(cues))
+	var (
+		best      string
+		bestScore = -1.0
+		bestTexts map[int]string
+	)
+	for _, model := range models {
+		texts := make(map[int]string, n)
+		total := 0.0
+		for k := 0; k < n; k++ {
+			i := k * len(cues) / n
+			got, err := o.recognizer.Recognize(ctx, cues[i].render(), model)
+			if err != nil {
+				return "", nil, err
+			}
+			texts[i] = got.Text
+			total += got.Confidence
+		}
+		// Strictly greater: a tie keeps the earlier, Traditional model.
+		if score := total / float64(n); score > bestScore {
+			best, bestScore, bestTexts = model, score, texts
+		}
+	}
+	return best, bestTexts, nil
+}
+
+// decodeBitmapTrack dispatches on the codec ffprobe reported.
+func decodeBitmapTrack(codec string, raw []byte) ([]bitmapCue, error) {
+	switch strings.ToLower(strings.TrimSpace(codec)) {
+	case "hdmv_pgs_subtitle":
+		return parsePGS(raw)
+	case "dvd_subtitle":
+		return parseVobSub(raw)
+	default:
+		return nil, fmt.Errorf("no bitmap decoder for codec %q", codec)
+	}
+}
+
+// fillCueEnds ends an open cue where the next one starts, or after
+// ocrDefaultCueLength when nothing follows.
+func fillCueEnds(cues []bitmapCue) {
+	for i := range cues {
+		if cues[i].end > cues[i].start {
+			continue
+		}
+		cues[i].end = cues[i].start + ocrDefaultCueLength
+		if i+1 < len(cues) && cues[i+1].start > cues[i].start && cues[i+1].start < cues[i].end {
+			cues[i].end = cues[i+1].start
+		}
+	}
+}
+
+// buildOCRBlocks cleans the read text and numbers the cues, dropping frames
+// that read as nothing (a blank clear, a logo) and merging a redraw of the
+// same line into the cue it continues.
+func buildOCRBlocks(cues []bitmapCue, texts []string, chinese bool) []SubtitleBlock {
+	var (
+		blocks []SubtitleBlock
+		ends   []time.Duration
+	)
+	for i, cue := range cues {
+		text := cleanOCRText(texts[i], chinese)
+		if text == "" {
+			continue
+		}
+		if n := len(blocks); n > 0 && blocks[n-1].Text == text && cue.start-ends[n-1] <= ocrMergeGap {
+			ends[n-1] = max(ends[n-1], cue.end)
+			blocks[n-1].End = FormatSRTTime(ends[n-1])
+			continue
+		}
+		blocks = append(blocks, SubtitleBlock{
+			Index: len(blocks) + 1,
+			Start: FormatSRTTime(cue.start),
+			End:   FormatSRTTime(cue.end),
+			Text:  text,
+		})
+		ends = append(ends, cue.end)
+	}
+	return blocks
+}
+
+// bitmapCue is one decoded subtitle frame. render is deferred so a long track
+// holds compressed bitmaps, not a few thousand rendered images.
+type bitmapCue struct {
+	start, end time.Duration // end 0 = the track never cleared it
+	render     func() *image.Gray
+}
+
+// ocrCanvas is the image handed to the engine: black ink on white, each
+// source pixel drawn as a scale×scale block, inside a white margin.
+type ocrCanvas struct {
+	img   *image.Gray
+	scale int
+}
+
+func newOCRCanvas(width, height, scale int) *ocrCanvas {
+	img := image.NewGray(image.Rect(0, 0, width*scale+2*ocrCanvasMargin, height*scale+2*ocrCanvasMargin))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	return &ocrCanvas{img: img, scale: scale}
+}
+
+// ink blackens source pixel (x, y).
+func (c *ocrCanvas) ink(x, y int) {
+	for dy := 0; dy < c.scale; dy++ {
+		for dx := 0; dx < c.scale; dx++ {
+			c.img.SetGray(ocrCanvasMargin+x*c.scale+dx, ocrCanvasMargin+y*c.scale+dy, color.Gray{})
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package subtitle
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// cleanOCRText repairs what an OCR engine predictably gets wrong on subtitle
+// frames, line by line. The fixes are deliberately narrow — each one only
+// fires next to the characters that make it unambiguous — because a cue the
+// cleanup mangles is worse than one it left alone: the translate and convert
+// stages downstream cope with a stray space, not with a wrong word.
+//
+// Lines with no letter or digit at all (a stray speck read as "、" or "-")
+// are dropped; an empty result means the frame held no text.
+func cleanOCRText(text string, chinese bool) string {
+	var out []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = toHalfWidthAlnum(strings.TrimSpace(line))
+		if chinese {
+			line = cleanCJKLine(line)
+		} else {
+			line = cleanLatinLine(line)
+		}
+		line = strings.TrimSpace(ocrRepeatedSpaces.ReplaceAllString(line, " "))
+		if !hasLetterOrDigit(line) {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+var ocrRepeatedSpaces = regexp.MustCompile(` {2,}`)
+
+// toHalfWidthAlnum folds full-width digits and Latin letters (Ｓ, ２) to
+// ASCII — Chinese subtitles set names and numbers half-width, and the models
+// emit either. Full-width punctuation and the ideographic space are kept.
+func toHalfWidthAlnum(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '０' && r <= '９', r >= 'Ａ' && r <= 'Ｚ', r >= 'ａ' && r <= 'ｚ':
+			return r - 0xFEE0
+		}
+		return r
+	}, s)
+}
+
+func hasLetterOrDigit(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHan(r rune) bool { return unicode.Is(unicode.Han, r) }
+
+// cjkLookalikes are glyphs the Chinese models confuse with an ideograph of
+// the same shape. Only replaced beside an ideograph: Chinese subtitles carry
+// no kana, but a lone "ー" may be a dash.
+var cjkLookalikes = map[rune]rune{
+	'ロ': '口',
+	'カ': '力',
+	'エ': '工',
+	'ー': '一',
+	'-': '一',
+	'_': '一',
+}
+
+// cjkFullWidthPunct is the ASCII punctuation a Chinese line sets full-width.
+var cjkFullWidthPunct = map[rune]rune{
+	',': '，',
+	'?': '？',
+	'!': '！',
+	':': '：',
+	';': '；',
+	'(': '（',
+	')': '）',
+}
+
+// cjkPhraseFixes are whole-word misreads between near-identical ideographs
+// (己/已/巳, 曰/日), in both scripts. A phrase, never a lone character: 自己
+// and 已經 are both words, and only the pairing tells which one was meant.
+var cjkPhraseFixes = strings.NewReplacer(
+	"己經", "已經", "巳經", "已經", "己经", "已经", "巳经", "已经",
+	"自已", "自己", "自巳", "自己",
+	"而己", "而已", "而巳", "而已",
+	"己婚", "已婚", "巳婚", "已婚",
+	"曰子", "日子", "今曰", "今日", "明曰", "明日", "昨曰", "昨日", "生曰", "生日",
+	"。。。", "…", "...", "…",
+)
+
+// cleanCJKLine applies the Chinese fixes to one line.
+func cleanCJKLine(line string) string {
+	runes := stripInterHanSpaces([]rune(line))
+	for i, r := range runes {
+		prevHan := i > 0 && isHan(runes[i-1])
+		nextHan := i+1 < len(runes) && isHan(runes[i+1])
+		if to, ok := cjkLookalikes[r]; ok && prevHan && nextHan {
+			runes[i] = to
+			continue
+		}
+		if to, ok := cjkFullWidthPunct[r]; ok && (prevHan || nextHan) {
+			runes[i] = to
+		}
+	}
+	return cjkPhraseFixes.Replace(string(runes))
+}
+
+// stripInterHanSpaces removes the spaces tesseract puts between ideographs —
+// but only when the line is spaced that way throughout. A Chinese subtitle
+// legitimately separates clauses with a single space ("我知道 但是不行"), and
+// that must survive; a line with more spaced ideograph pairs than adjacent
+// ones is the engine's doing.
+func stripInterHanSpaces(runes []rune) []rune {
+	spaced, adjacent := 0, 0
+	for i := 0; i+1 < len(runes); i++ {
+		if !isHan(runes[i]) {
+			continue
+		}
+		if isHan(runes[i+1]) {
+			adjacent++
+		} else if runes[i+1] == ' ' && i+2 < len(runes) && isHan(runes[i+2]) {
+			spaced++
+		}
+	}
+	if spaced <= adjacent {
+		return runes
+	}
+	out := make([]rune, 0, len(runes))
+	for i, r := range runes {
+		if r == ' ' && i > 0 && i+1 < len(runes) && isHan(runes[i-1]) && isHan(runes[i+1]) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+var (
+	// A lone "l" or "|" is the pronoun I; so is "l" before a contraction.
+	ocrLonePronoun = regexp.MustCompile(`(^|[\s"'(\-])[l|]([\s,.!?]|$)`)
+	ocrContraction = regexp.MustCompile(`(^|[\s"'(\-])[l|]'(m|ll|ve|d)\b`)
+	ocrPipeBeforeV = regexp.MustCompile(`(^|[\s"'(\-])\|([aeiouy])`)
+	ocrPipeAtStart = regexp.MustCompile(`(^|[\s"'(\-])\|`)
+	ocrWord        = regexp.MustCompile(`[0-9A-Za-z]+`)
+)
+
+// cleanLatinLine applies the English fixes to one line: I misread as l or |,
+// and o misread as 0 in a word.
+func cleanLatinLine(line string) string {
+	line = ocrContraction.ReplaceAllString(line, "${1}I'$2")
+	// The pronoun pattern consumes its trailing separator, so "l l" needs a
+	// second pass to reach the second l.
+	for i := 0; i < 2; i++ {
+		line = ocrLonePronoun.ReplaceAllString(line, "${1}I$2")
+	}
+	// Any other "|" is a misread of I or l. Words that open with l go on to a
+	// vowel (leave, look, like); the ones that open with I do not (It's, If,
+	// In, Is). Inside a word it is always l.
+	line = ocrPipeBeforeV.ReplaceAllString(line, "${1}l$2")
+	line = ocrPipeAtStart.ReplaceAllString(line, "${1}I")
+	line = strings.ReplaceAll(line, "|", "l")
+	return ocrWord.ReplaceAllStringFunc(line, zeroToO)
+}
+
+// zeroToO reads a 0 in a word as the letter it resembles — when the word has
+// letters and no other digit, so 10am and 2000s stay numbers. The case
+// follows the word: "G0" is Go, "GR0UP" GROUP, "0K" OK.
+func zeroToO(word string) string {
+	letters, lower := 0, false
+	for _, r := range word {
+		switch {
+		case r >= '1' && r <= '9':
+			return word
+		case r >= 'a' && r <= 'z':
+			letters++
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			letters++
+		}
+	}
+	if letters == 0 || !strings.Contains(word, "0") {
+		return word
+	}
+	o := "o"
+	if !lower && (len(word) > 2 || word[0] == '0') {
+		o = "O"
+	}
+	return strings.ReplaceAll(word, "0", o)
+}
@@ -0,0 +1,56 @@
+package subtitle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanOCRText_Chinese(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"engine spacing between ideographs", "我 們 走 吧", "我們走吧"},
+		{"a clause space survives", "我知道 但是不行", "我知道 但是不行"},
+		{"己/已 misread", "他己經走了", "他已經走了"},
+		{"已/己 misread", "你要照顧自已", "你要照顧自己"},
+		{"simplified too", "他己经走了", "他已经走了"},
+		{"曰/日 misread", "今曰是我的生曰", "今日是我的生日"},
+		{"dash read for 一", "我們-起走", "我們一起走"},
+		{"katakana lookalikes", "他的ロ袋裡有カ量", "他的口袋裡有力量"},
+		{"ASCII punctuation goes full-width", "你好,你是誰?", "你好，你是誰？"},
+		{"ellipsis", "我...不知道", "我…不知道"},
+		{"full-width alphanumerics fold", "ＦＢＩ在２樓", "FBI在2樓"},
+		{"dialogue dash is left alone", "-你去哪\n-回家", "-你去哪\n-回家"},
+		{"a speck line is dropped", "、\n你好", "你好"},
+		{"nothing at all", " \n ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cleanOCRText(tt.in, true))
+		})
+	}
+}
+
+func TestCleanOCRText_English(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"lone l", "l know.", "I know."},
+		{"lone pipe", "Yes, | do.", "Yes, I do."},
+		{"contraction", "l'm here and l'll stay.", "I'm here and I'll stay."},
+		{"pipe opening an I word", "|t's fine.", "It's fine."},
+		{"pipe opening an l word", "Don't |eave.", "Don't leave."},
+		{"pipe inside a word", "he|p", "help"},
+		{"zero in a word", "G0 n0w.", "Go now."},
+		{"zero in a shouted word", "0K, GR0UP!", "OK, GROUP!"},
+		{"numbers stay numbers", "At 10am in 2000.", "At 10am in 2000."},
+		{"l inside words is untouched", "hello all", "hello all"},
+		{"repeated spaces", "Wait   for  me", "Wait for me"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cleanOCRText(tt.in, false))
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package subtitle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"time"
+)
+
+// PGS (Blu-ray "Presentation Graphic Stream", ffprobe codec
+// hdmv_pgs_subtitle) decoding, from the .sup file ffmpeg stream-copies the
+// track into.
+//
+// A .sup file is a run of segments, each "PG" + PTS + DTS + type + size.
+// Segments arrive in display sets closed by an END segment: a composition
+// (PCS) saying which objects are on screen where, the palettes (PDS) and
+// object bitmaps (ODS) it uses, and the windows (WDS) they sit in. A display
+// set with objects starts a cue; the next display set ends it, whether it
+// clears the screen or replaces the picture.
+
+const (
+	pgsSegmentPalette     = 0x14
+	pgsSegmentObject      = 0x15
+	pgsSegmentComposition = 0x16
+	pgsSegmentWindow      = 0x17
+	pgsSegmentEnd         = 0x80
+
+	pgsHeaderSize = 13
+)
+
+type pgsPaletteEntry struct{ y, alpha byte }
+
+type pgsObject struct {
+	width, height int
+	rle           []byte
+}
+
+type pgsPlacement struct {
+	object *pgsObject
+	x, y   int
+}
+
+type pgsComposition struct {
+	pts        time.Duration
+	paletteID  byte
+	placements []pgsCompositionObject
+}
+
+type pgsCompositionObject struct {
+	objectID uint16
+	x, y     int
+}
+
+// parsePGS decodes a .sup stream into timed bitmap cues.
+func parsePGS(data []byte) ([]bitmapCue, error) {
+	var (
+		cues     []bitmapCue
+		open     = -1
+		palettes = map[byte]*[256]pgsPaletteEntry{}
+		objects  = map[uint16]*pgsObject{}
+		pcs      *pgsComposition
+	)
+
+	for off := 0; off < len(data); {
+		if len(data)-off < pgsHeaderSize || data[off] != 'P' || data[off+1] != 'G' {
+			return nil, fmt.Errorf("pgs: bad segment header at byte %d", off)
+		}
+		pts := pgsTime(binary.BigEndian.Uint32(data[off+2:]))
+		kind := data[off+10]
+		size := int(binary.BigEndian.Uint16(data[off+11:]))
+		off += pgsHeaderSize
+		if len(data)-off < size {
+			return nil, fmt.Errorf("pgs: truncated segment at byte %d", off)
+		}
+		seg := data[off : off+size]
+		off += size
+
+		switch kind {
+		case pgsSegmentComposition:
+			if len(seg) < 11 {
+				return nil, fmt.Errorf("pgs: short composition segment")
+			}
+			pcs = &pgsComposition{pts: pts, paletteID: seg[9]}
+			// An epoch start (composition state 0x80) throws away every
+			// object and palette defined before it.
+			if seg[7]&0x80 != 0 {
+				palettes = map[byte]*[256]pgsPaletteEntry{}
+				objects = map[uint16]*pgsObject{}
+			}
+			n := int(seg[10])
+			p := 11
+			for i := 0; i < n && p+8 <= len(seg); i++ {
+				obj := pgsCompositionObject{
+					objectID: binary.BigEndian.Uint16(seg[p:]),
+					x:        int(binary.BigEndian.Uint16(seg[p+4:])),
+					y:        int(binary.BigEndian.Uint16(seg[p+6:])),
+				}
+				// Cropping is a player effect for forced subtitles that
+				// scroll; the whole object is what OCR wants.
+				if seg[p+3]&0x40 != 0 {
+					p += 8
+				}
+				p += 8
+				pcs.placements = append(pcs.placements, obj)
+			}
+
+		case pgsSegmentPalette:
+			if len(seg) < 2 {
+				continue
+			}
+			pal, ok := palettes[seg[0]]
+			if !ok {
+				pal = &[256]pgsPaletteEntry{}
+				palettes[seg[0]] = pal
+			}
+			for p := 2; p+5 <= len(seg); p += 5 {
+				pal[seg[p]] = pgsPaletteEntry{y: seg[p+1], alpha: seg[p+4]}
+			}
+
+		case pgsSegmentObject:
+			if len(seg) < 4 {
+				continue
+			}
+			id := binary.BigEndian.Uint16(seg)
+			if seg[3]&0x80 != 0 { // first fragment: 3-byte data length, then the size
+				if len(seg) < 11 {
+					continue
+				}
+				objects[id] = &pgsObject{
+					width:  int(binary.BigEndian.Uint16(seg[7:])),
+					height: int(binary.BigEndian.Uint16(seg[9:])),
+					rle:    append([]byte(nil), seg[11:]...),
+				}
+			} else if obj, ok := objects[id]; ok {
+				obj.rle = append(obj.rle, seg[4:]...)
+			}
+
+		case pgsSegmentWindow:
+			// Windows only bound where objects may be drawn.
+
+		case pgsSegmentEnd:
+			if pcs == nil {
+				continue
+			}
+			if open >= 0 && cues[open].end == 0 {
+				cues[open].end = pcs.pts
+			}
+			open = -1
+			if placements, palette := pgsSnapshot(pcs, objects, palettes); len(placements) > 0 {
+				cues = append(cues, bitmapCue{start: pcs.pts, render: pgsRenderer(placements, palette)})
+				open = len(cues) - 1
+			}
+			pcs = nil
+		}
+	}
+	return cues, nil
+}
+
+// pgsSnapshot resolves a composition to the objects and palette it shows.
+// Objects are immutable once stored (a redefinition replaces the pointer), so
+// the snapshot stays valid after later display sets change the maps.
+func pgsSnapshot(pcs *pgsComposition, objects map[uint16]*pgsObject, palettes map[byte]*[256]pgsPaletteEntry) ([]pgsPlacement, [256]pgsPaletteEntry) {
+	var palette [256]pgsPaletteEntry
+	if p, ok := palettes[pcs.paletteID]; ok {
+		palette = *p
+	}
+	var placements []pgsPlacement
+	for _, c := range pcs.placements {
+		obj, ok := objects[c.objectID]
+		if !ok || obj.width == 0 || obj.height == 0 {
+			continue
+		}
+		placements = append(placements, pgsPlacement{object: obj, x: c.x, y: c.y})
+	}
+	return placements, palette
+}
+
+// pgsRenderer draws the placed objects onto one canvas cropped to their
+// bounding box: bright opaque pixels — the lettering, not its dark outline —
+// become black ink on white, the polarity tesseract reads best.
+func pgsRenderer(placements []pgsPlacement, palette [256]pgsPaletteEntry) func() *image.Gray {
+	return func() *image.Gray {
+		bounds := image.Rectangle{}
+		for i, p := range placements {
+			r := image.Rect(p.x, p.y, p.x+p.object.width, p.y+p.object.height)
+			if i == 0 {
+				bounds = r
+			} else {
+				bounds = bounds.Union(r)
+			}
+		}
+		canvas := newOCRCanvas(bounds.Dx(), bounds.Dy(), 1)
+		for _, p := range placements {
+			indexes := decodePGSRLE(p.object.rle, p.object.width, p.object.height)
+			for y := 0; y < p.object.height; y++ {
+				for x := 0; x < p.object.width; x++ {
+					e := palette[indexes[y*p.object.width+x]]
+					if e.alpha >= 128 && e.y >= 128 {
+						canvas.ink(p.x-bounds.Min.X+x, p.y-bounds.Min.Y+y)
+					}
+				}
+			}
+		}
+		return canvas.img
+	}
+}
+
+// decodePGSRLE expands one object's run-length coded bitmap into palette
+// indexes, row-major. A malformed stream leaves the rest transparent rather
+// than failing the track.
+func decodePGSRLE(data []byte, width, height int) []byte {
+	out := make([]byte, width*height)
+	x, y, i := 0, 0, 0
+	put := func(color byte, run int) {
+		for ; run > 0 && x < width; run-- {
+			out[y*width+x] = color
+			x++
+		}
+	}
+	for i < len(data) && y < height {
+		b := data[i]
+		i++
+		if b != 0 {
+			put(b, 1)
+			continue
+		}
+		if i >= len(data) {
+			break
+		}
+		flags := data[i]
+		i++
+		if flags == 0 { // end of line
+			x = 0
+			y++
+			continue
+		}
+		run := int(flags & 0x3F)
+		if flags&0x40 != 0 {
+			if i >= len(data) {
+				break
+			}
+			run = run<<8 | int(data[i])
+			i++
+		}
+		color := byte(0)
+		if flags&0x80 != 0 {
+			if i >= len(data) {
+				break
+			}
+			color = data[i]
+			i++
+		}
+		put(color, run)
+	}
+	return out
+}
+
+// pgsTime converts a 90 kHz presentation timestamp.
+func pgsTime(ticks uint32) time.Duration {
+	return time.Duration(ticks) * time.Second / 90000
+}
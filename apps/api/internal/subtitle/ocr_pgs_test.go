@@ -0,0 +1,182 @@
+package subtitle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─── A minimal .sup encoder, so the decoder is tested without ffmpeg ────────
+
+// Palette indexes the test bitmaps use: bright fill, dark outline, and an
+// index the palette leaves undefined (fully transparent).
+const (
+	pgsTestTransparent = 0
+	pgsTestFill        = 1
+	pgsTestOutline     = 2
+)
+
+// pgsTestFrame is one subtitle shown from start to end (end 0 = never
+// cleared), drawn as a filled box with a 1px outline.
+type pgsTestFrame struct {
+	start, end    time.Duration
+	width, height int
+}
+
+func (f pgsTestFrame) pixels() []byte {
+	px := make([]byte, f.width*f.height)
+	for y := 0; y < f.height; y++ {
+		for x := 0; x < f.width; x++ {
+			switch {
+			case x == 0 || y == 0 || x == f.width-1 || y == f.height-1:
+				px[y*f.width+x] = pgsTestOutline
+			default:
+				px[y*f.width+x] = pgsTestFill
+			}
+		}
+	}
+	return px
+}
+
+func pgsSegment(buf *bytes.Buffer, pts time.Duration, kind byte, payload []byte) {
+	buf.WriteString("PG")
+	_ = binary.Write(buf, binary.BigEndian, uint32(pts*90000/time.Second))
+	_ = binary.Write(buf, binary.BigEndian, uint32(0))
+	buf.WriteByte(kind)
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(payload)))
+	buf.Write(payload)
+}
+
+func encodePGS(frames ...pgsTestFrame) []byte {
+	var buf bytes.Buffer
+	for _, f := range frames {
+		// PCS: 1920x1080, epoch start, palette 0, one object at (400, 900).
+		pgsSegment(&buf, f.start, pgsSegmentComposition, []byte{
+			0x07, 0x80, 0x04, 0x38, 0x10, 0x00, 0x01, 0x80, 0x00, 0x00, 1,
+			0x00, 0x00, 0x00, 0x00, 0x01, 0x90, 0x03, 0x84,
+		})
+		pgsSegment(&buf, f.start, pgsSegmentWindow, []byte{1, 0, 0x01, 0x90, 0x03, 0x84, 0x02, 0x00, 0x00, 0x40})
+		pgsSegment(&buf, f.start, pgsSegmentPalette, []byte{
+			0, 0,
+			pgsTestFill, 235, 128, 128, 255,
+			pgsTestOutline, 16, 128, 128, 255,
+		})
+		rle := encodePGSRLE(f.pixels(), f.width)
+		ods := []byte{0x00, 0x00, 0, 0xC0, 0, 0, 0,
+			byte(f.width >> 8), byte(f.width), byte(f.height >> 8), byte(f.height)}
+		pgsSegment(&buf, f.start, pgsSegmentObject, append(ods, rle...))
+		pgsSegment(&buf, f.start, pgsSegmentEnd, nil)
+
+		if f.end > 0 {
+			pgsSegment(&buf, f.end, pgsSegmentComposition, []byte{
+				0x07, 0x80, 0x04, 0x38, 0x10, 0x00, 0x02, 0x00, 0x00, 0x00, 0,
+			})
+			pgsSegment(&buf, f.end, pgsSegmentWindow, []byte{1, 0, 0x01, 0x90, 0x03, 0x84, 0x02, 0x00, 0x00, 0x40})
+			pgsSegment(&buf, f.end, pgsSegmentEnd, nil)
+		}
+	}
+	return buf.Bytes()
+}
+
+func encodePGSRLE(px []byte, width int) []byte {
+	var out []byte
+	for row := 0; row < len(px)/width; row++ {
+		line := px[row*width : (row+1)*width]
+		for x := 0; x < len(line); {
+			color, run := line[x], 1
+			for x+run < len(line) && line[x+run] == color {
+				run++
+			}
+			x += run
+			switch {
+			case color != 0 && run == 1:
+				out = append(out, color)
+			case color == 0 && run < 64:
+				out = append(out, 0, byte(run))
+			case color == 0:
+				out = append(out, 0, 0x40|byte(run>>8), byte(run))
+			case run < 64:
+				out = append(out, 0, 0x80|byte(run), color)
+			default:
+				out = append(out, 0, 0xC0|byte(run>>8), byte(run), color)
+			}
+		}
+		out = append(out, 0, 0)
+	}
+	return out
+}
+
+// isInk reports whether rendered source pixel (x, y) at the given scale came
+// out black.
+func isInk(img *image.Gray, x, y, scale int) bool {
+	return img.GrayAt(ocrCanvasMargin+x*scale, ocrCanvasMargin+y*scale).Y == 0
+}
+
+// ─── Decoding ──────────────────────────────────────────────────────────────
+
+func TestParsePGS_TimesEachDisplaySet(t *testing.T) {
+	data := encodePGS(
+		pgsTestFrame{start: 1 * time.Second, end: 3500 * time.Millisecond, width: 80, height: 20},
+		pgsTestFrame{start: 5 * time.Second, end: 7 * time.Second, width: 120, height: 20},
+	)
+
+	cues, err := parsePGS(data)
+	require.NoError(t, err)
+	require.Len(t, cues, 2)
+	assert.Equal(t, 1*time.Second, cues[0].start)
+	assert.Equal(t, 3500*time.Millisecond, cues[0].end)
+	assert.Equal(t, 5*time.Second, cues[1].start)
+	assert.Equal(t, 7*time.Second, cues[1].end)
+}
+
+// TestParsePGS_ReplacedPictureEndsTheOpenCue — a display set that swaps the
+// picture without clearing first still ends the cue before it.
+func TestParsePGS_ReplacedPictureEndsTheOpenCue(t *testing.T) {
+	data := encodePGS(
+		pgsTestFrame{start: 1 * time.Second, width: 40, height: 10},
+		pgsTestFrame{start: 2 * time.Second, width: 40, height: 10},
+	)
+
+	cues, err := parsePGS(data)
+	require.NoError(t, err)
+	require.Len(t, cues, 2)
+	assert.Equal(t, 2*time.Second, cues[0].end)
+	assert.Zero(t, cues[1].end, "nothing ever cleared the last picture")
+}
+
+func TestParsePGS_RendersTheFillAsInkAndDropsTheOutline(t *testing.T) {
+	cues, err := parsePGS(encodePGS(pgsTestFrame{start: time.Second, end: 2 * time.Second, width: 100, height: 30}))
+	require.NoError(t, err)
+	require.Len(t, cues, 1)
+
+	img := cues[0].render()
+	assert.Equal(t, 100+2*ocrCanvasMargin, img.Bounds().Dx(), "cropped to the object, plus the margin")
+	assert.True(t, isInk(img, 50, 15, 1), "bright opaque fill is the lettering")
+	assert.False(t, isInk(img, 0, 0, 1), "the dark outline is not")
+	assert.Equal(t, uint8(0xFF), img.GrayAt(0, 0).Y, "the margin is white")
+}
+
+func TestParsePGS_RejectsSomethingThatIsNotASupFile(t *testing.T) {
+	_, err := parsePGS([]byte("1\n00:00:01,000 --> 00:00:02,000\nhi\n"))
+	assert.Error(t, err)
+}
+
+func TestDecodePGSRLE_LongRuns(t *testing.T) {
+	px := make([]byte, 300*2)
+	for i := 0; i < 300; i++ {
+		px[i] = 7
+	}
+	got := decodePGSRLE(encodePGSRLE(px, 300), 300, 2)
+	assert.Equal(t, px, got)
+}
+
+func TestDecodePGSRLE_TruncatedDataLeavesTheRestTransparent(t *testing.T) {
+	rle := encodePGSRLE([]byte{1, 1, 1, 1, 2, 2, 2, 2}, 4)
+	got := decodePGSRLE(rle[:4], 4, 2)
+	assert.Equal(t, []byte{1, 1, 1, 1, 0, 0, 0, 0}, got)
+}
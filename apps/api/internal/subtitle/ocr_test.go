@@ -0,0 +1,183 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vido/api/internal/services"
+)
+
+// ─── Fakes ─────────────────────────────────────────────────────────────────
+
+// fakeBitmapExtractor writes the configured bytes where ffmpeg would.
+type fakeBitmapExtractor struct {
+	data      []byte
+	err       error
+	callCount int
+}
+
+func (f *fakeBitmapExtractor) ExtractBitmap(_ context.Context, _, tmpDir string, track services.SubtitleTrack) (string, error) {
+	f.callCount++
+	if f.err != nil {
+		return "", f.err
+	}
+	path := filepath.Join(tmpDir, "track.sup")
+	return path, os.WriteFile(path, f.data, 0o600)
+}
+
+// fakeRecognizer reads a frame by its width — each test frame is a different
+// size — and answers with a fixed confidence per model.
+type fakeRecognizer struct {
+	languages  []string
+	texts      map[string]map[int]string // model → frame width → text
+	confidence map[string]float64
+	err        error
+	calls      map[string]int
+}
+
+func (f *fakeRecognizer) Languages() []string { return f.languages }
+
+func (f *fakeRecognizer) Recognize(_ context.Context, img image.Image, language string) (OCRText, error) {
+	if f.calls == nil {
+		f.calls = map[string]int{}
+	}
+	f.calls[language]++
+	if f.err != nil {
+		return OCRText{}, f.err
+	}
+	width := img.Bounds().Dx() - 2*ocrCanvasMargin
+	return OCRText{Text: f.texts[language][width], Confidence: f.confidence[language]}, nil
+}
+
+func pgsTrack(idx int, lang string) services.SubtitleTrack {
+	return services.SubtitleTrack{StreamIndex: idx, Language: lang, Format: "hdmv_pgs_subtitle"}
+}
+
+// ─── CanRead ───────────────────────────────────────────────────────────────
+
+func TestOCR_CanRead(t *testing.T) {
+	ocr := NewOCR(&fakeBitmapExtractor{}, &fakeRecognizer{languages: []string{"eng", "chi_tra"}}, nil)
+
+	assert.True(t, ocr.CanRead(pgsTrack(3, "chi")))
+	assert.True(t, ocr.CanRead(pgsTrack(3, "eng")))
+	assert.True(t, ocr.CanRead(services.SubtitleTrack{Language: "zh-TW", Format: "dvd_subtitle"}))
+	assert.False(t, ocr.CanRead(pgsTrack(3, "und")), "an untagged track is never read as English")
+	assert.False(t, ocr.CanRead(pgsTrack(3, "jpn")))
+	assert.False(t, ocr.CanRead(services.SubtitleTrack{Language: "eng", Format: "dvb_subtitle"}))
+	assert.False(t, ocr.CanRead(services.SubtitleTrack{Language: "eng", Format: "subrip"}))
+	assert.False(t, ocr.CanRead(services.SubtitleTrack{Language: "eng", Format: "hdmv_pgs_subtitle", External: true}))
+
+	noChinese := NewOCR(&fakeBitmapExtractor{}, &fakeRecognizer{languages: []string{"eng"}}, nil)
+	assert.False(t, noChinese.CanRead(pgsTrack(3, "chi")), "no model, no route")
+	assert.False(t, NewOCR(&fakeBitmapExtractor{}, &fakeRecognizer{}, nil).CanRead(pgsTrack(3, "eng")),
+		"a boot without tesseract turns the route off")
+}
+
+// ─── ReadTrack ─────────────────────────────────────────────────────────────
+
+func TestOCR_ReadTrack_RebuildsTimedCues(t *testing.T) {
+	ex := &fakeBitmapExtractor{data: encodePGS(
+		pgsTestFrame{start: 1 * time.Second, end: 3 * time.Second, width: 100, height: 20},
+		pgsTestFrame{start: 4 * time.Second, end: 5 * time.Second, width: 30, height: 20},
+		// A fade redraws the same line: one cue, not two.
+		pgsTestFrame{start: 6 * time.Second, width: 120, height: 20},
+		pgsTestFrame{start: 7 * time.Second, end: 8 * time.Second, width: 121, height: 20},
+		// The last frame is never cleared.
+		pgsTestFrame{start: 10 * time.Second, width: 140, height: 20},
+	)}
+	rec := &fakeRecognizer{
+		languages: []string{"eng"},
+		texts: map[string]map[int]string{"eng": {
+			100: "l'm not |eaving.",
+			30:  "  ",
+			120: "G0 home.",
+			121: "Go home.",
+			140: "Why?",
+		}},
+	}
+	ocr := NewOCR(ex, rec, nil)
+
+	blocks, err := ocr.ReadTrack(context.Background(), "/media/m.mkv", t.TempDir(), pgsTrack(4, "eng"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []SubtitleBlock{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:03,000", Text: "I'm not leaving."},
+		{Index: 2, Start: "00:00:06,000", End: "00:00:08,000", Text: "Go home."},
+		{Index: 3, Start: "00:00:10,000", End: "00:00:14,000", Text: "Why?"},
+	}, blocks)
+}
+
+// TestOCR_ReadTrack_PicksTheChineseModelThatReadsBest — the tag says only
+// "chi"; the model that reads the samples more confidently wins, and the
+// samples it read are not read again.
+func TestOCR_ReadTrack_PicksTheChineseModelThatReadsBest(t *testing.T) {
+	frames := make([]pgsTestFrame, 0, 12)
+	texts := map[int]string{}
+	for i := 0; i < 12; i++ {
+		w := 50 + i
+		frames = append(frames, pgsTestFrame{start: time.Duration(i*2) * time.Second, end: time.Duration(i*2+1) * time.Second, width: w, height: 20})
+		texts[w] = "我 們 己 經 到 了"
+	}
+	rec := &fakeRecognizer{
+		languages:  []string{"eng", "chi_sim", "chi_tra"},
+		texts:      map[string]map[int]string{"chi_sim": {}, "chi_tra": texts},
+		confidence: map[string]float64{"chi_sim": 41, "chi_tra": 88},
+	}
+	ocr := NewOCR(&fakeBitmapExtractor{data: encodePGS(frames...)}, rec, nil)
+
+	blocks, err := ocr.ReadTrack(context.Background(), "/media/m.mkv", t.TempDir(), pgsTrack(3, "chi"))
+	require.NoError(t, err)
+
+	require.Len(t, blocks, 12)
+	assert.Equal(t, "我們已經到了", blocks[0].Text, "spaced ideographs rejoined, 己經 fixed")
+	assert.Equal(t, ocrModelSampleCues, rec.calls["chi_sim"])
+	assert.Equal(t, 12, rec.calls["chi_tra"], "each frame read once by the winner")
+	assert.Zero(t, rec.calls["eng"])
+}
+
+func TestOCR_ReadTrack_Failures(t *testing.T) {
+	rec := &fakeRecognizer{languages: []string{"eng"}}
+
+	_, err := NewOCR(&fakeBitmapExtractor{err: ErrSubtitleExtractFailed}, rec, nil).
+		ReadTrack(context.Background(), "/m.mkv", t.TempDir(), pgsTrack(2, "eng"))
+	assert.ErrorIs(t, err, ErrSubtitleExtractFailed)
+
+	_, err = NewOCR(&fakeBitmapExtractor{data: []byte("not a sup file")}, rec, nil).
+		ReadTrack(context.Background(), "/m.mkv", t.TempDir(), pgsTrack(2, "eng"))
+	assert.ErrorIs(t, err, ErrSubtitleOCRFailed)
+
+	failing := &fakeRecognizer{languages: []string{"eng"}, err: errors.New("tesseract: exit 1")}
+	ex := &fakeBitmapExtractor{data: encodePGS(pgsTestFrame{start: time.Second, width: 10, height: 10})}
+	_, err = NewOCR(ex, failing, nil).ReadTrack(context.Background(), "/m.mkv", t.TempDir(), pgsTrack(2, "eng"))
+	assert.ErrorIs(t, err, ErrSubtitleOCRFailed)
+
+	ex = &fakeBitmapExtractor{}
+	_, err = NewOCR(ex, rec, nil).ReadTrack(context.Background(), "/m.mkv", t.TempDir(), pgsTrack(2, "und"))
+	assert.ErrorIs(t, err, ErrSubtitleOCRFailed)
+	assert.Zero(t, ex.callCount, "no model — nothing is extracted")
+}
+
+func TestFillCueEnds(t *testing.T) {
+	cues := []bitmapCue{
+		{start: 1 * time.Second},
+		{start: 2 * time.Second, end: 3 * time.Second},
+		{start: 10 * time.Second},
+	}
+	fillCueEnds(cues)
+	assert.Equal(t, 2*time.Second, cues[0].end, "ends where the next starts")
+	assert.Equal(t, 3*time.Second, cues[1].end)
+	assert.Equal(t, 10*time.Second+ocrDefaultCueLength, cues[2].end)
+}
+
+func TestOCRSatisfiesItsPorts(t *testing.T) {
+	var _ BitmapExtractor = NewExtractor(0, nil)
+	var _ TextRecognizer = NewTesseract(0, nil)
+	var _ TrackOCR = NewOCR(NewExtractor(0, nil), NewTesseract(0, nil), nil)
+}
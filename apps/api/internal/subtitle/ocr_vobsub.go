@@ -0,0 +1,300 @@
+package subtitle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"time"
+)
+
+// VobSub (DVD subpicture, ffprobe codec dvd_subtitle) decoding, from the
+// MPEG program stream ffmpeg stream-copies the track into.
+//
+// Each subpicture unit (SPU) travels in private-stream-1 PES packets, split
+// across as many as it needs; its first two bytes give its size. The SPU
+// holds a 2-bit-per-pixel, interlaced, nibble run-length bitmap and a chain of
+// control sequences that say when to show and hide it, where, and which of
+// the four pixel values are see-through.
+//
+// The colours themselves live in the container's palette, which a program
+// stream does not carry — so ink is chosen by shape instead: the lettering is
+// the opaque pixel value that touches transparency least, because its
+// outline sits between it and the background.
+
+const (
+	spuCmdForcedStart = 0x00
+	spuCmdStart       = 0x01
+	spuCmdStop        = 0x02
+	spuCmdColor       = 0x03
+	spuCmdContrast    = 0x04
+	spuCmdArea        = 0x05
+	spuCmdOffsets     = 0x06
+	spuCmdEnd         = 0xFF
+)
+
+// parseVobSub demuxes a program stream and decodes every SPU in it.
+func parseVobSub(data []byte) ([]bitmapCue, error) {
+	var (
+		cues []bitmapCue
+		spu  []byte
+		pts  time.Duration
+	)
+	found := false
+	for i := 0; i+4 <= len(data); {
+		if data[i] != 0 || data[i+1] != 0 || data[i+2] != 1 {
+			i++
+			continue
+		}
+		code := data[i+3]
+		switch {
+		case code == 0xBA: // pack header
+			if i+14 <= len(data) && data[i+4]&0xC0 == 0x40 {
+				i += 14 + int(data[i+13]&0x07)
+			} else {
+				i += 12
+			}
+		case code == 0xB9: // program end
+			i = len(data)
+		case code >= 0xBB:
+			if i+6 > len(data) {
+				i = len(data)
+				continue
+			}
+			length := int(binary.BigEndian.Uint16(data[i+4:]))
+			end := min(i+6+length, len(data))
+			if code == 0xBD {
+				payload, packetPTS, ok := privateStreamPayload(data[i+6 : end])
+				if ok && len(payload) > 1 && payload[0] >= 0x20 && payload[0] < 0x40 {
+					found = true
+					if spu == nil {
+						pts = packetPTS
+					}
+					spu = append(spu, payload[1:]...)
+					if len(spu) >= 2 {
+						if size := int(binary.BigEndian.Uint16(spu)); size > 0 && len(spu) >= size {
+							if cue, ok := decodeSPU(spu[:size], pts); ok {
+								cues = append(cues, cue)
+							}
+							spu = nil
+						}
+					}
+				}
+			}
+			i = end
+		default:
+			i += 4
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("vobsub: no subpicture packets in stream")
+	}
+	return cues, nil
+}
+
+// privateStreamPayload strips an MPEG-2 PES header, returning the payload and
+// the packet's presentation time.
+func privateStreamPayload(pes []byte) ([]byte, time.Duration, bool) {
+	if len(pes) < 3 || pes[0]&0xC0 != 0x80 {
+		return nil, 0, false
+	}
+	headerEnd := 3 + int(pes[2])
+	if headerEnd > len(pes) {
+		return nil, 0, false
+	}
+	var pts time.Duration
+	if pes[1]&0x80 != 0 && len(pes) >= 8 {
+		b := pes[3:8]
+		ticks := uint64(b[0]>>1&0x07)<<30 | uint64(b[1])<<22 | uint64(b[2]>>1)<<15 | uint64(b[3])<<7 | uint64(b[4]>>1)
+		pts = time.Duration(ticks) * time.Second / 90000
+	}
+	return pes[headerEnd:], pts, true
+}
+
+// spuImage is a decoded subpicture before it is rendered for OCR.
+type spuImage struct {
+	width, height int
+	pixels        []byte // 2-bit pixel values, row-major
+	alpha         [4]byte
+}
+
+// decodeSPU reads one subpicture unit. ok is false for a unit with no
+// bitmap or no display start.
+func decodeSPU(pkt []byte, pts time.Duration) (bitmapCue, bool) {
+	if len(pkt) < 4 {
+		return bitmapCue{}, false
+	}
+	var (
+		start, stop        time.Duration
+		started            bool
+		x1, x2, y1, y2     int
+		topOff, bottomOff  int
+		haveArea, haveRows bool
+		// Without a contrast command, pixel value 0 is the background and
+		// the rest are drawn — the DVD default.
+		alpha = [4]byte{0, 15, 15, 15}
+	)
+
+	off := int(binary.BigEndian.Uint16(pkt[2:]))
+	for seen := 0; off+4 <= len(pkt) && seen < 16; seen++ {
+		delay := time.Duration(binary.BigEndian.Uint16(pkt[off:])) * 1024 * time.Second / 90000
+		next := int(binary.BigEndian.Uint16(pkt[off+2:]))
+		at := pts + delay
+		p := off + 4
+	commands:
+		for p < len(pkt) {
+			switch pkt[p] {
+			case spuCmdForcedStart, spuCmdStart:
+				start, started = at, true
+				p++
+			case spuCmdStop:
+				stop = at
+				p++
+			case spuCmdColor:
+				p += 3
+			case spuCmdContrast:
+				if p+3 > len(pkt) {
+					break commands
+				}
+				alpha = [4]byte{pkt[p+2] & 0x0F, pkt[p+2] >> 4, pkt[p+1] & 0x0F, pkt[p+1] >> 4}
+				p += 3
+			case spuCmdArea:
+				if p+7 > len(pkt) {
+					break commands
+				}
+				b := pkt[p+1 : p+7]
+				x1, x2 = int(b[0])<<4|int(b[1])>>4, int(b[1]&0x0F)<<8|int(b[2])
+				y1, y2 = int(b[3])<<4|int(b[4])>>4, int(b[4]&0x0F)<<8|int(b[5])
+				haveArea = true
+				p += 7
+			case spuCmdOffsets:
+				if p+5 > len(pkt) {
+					break commands
+				}
+				topOff = int(binary.BigEndian.Uint16(pkt[p+1:]))
+				bottomOff = int(binary.BigEndian.Uint16(pkt[p+3:]))
+				haveRows = true
+				p += 5
+			default: // spuCmdEnd, or a command this decoder does not know
+				break commands
+			}
+		}
+		if next == off {
+			break
+		}
+		off = next
+	}
+
+	width, height := x2-x1+1, y2-y1+1
+	if !started || !haveArea || !haveRows || width <= 0 || height <= 0 {
+		return bitmapCue{}, false
+	}
+	img := &spuImage{width: width, height: height, pixels: make([]byte, width*height), alpha: alpha}
+	decodeSPUField(pkt, topOff, img, 0)
+	decodeSPUField(pkt, bottomOff, img, 1)
+
+	cue := bitmapCue{start: start, render: img.render}
+	if stop > start {
+		cue.end = stop
+	}
+	return cue, true
+}
+
+// decodeSPUField expands one interlaced field (every other row from first)
+// starting at byte offset off.
+func decodeSPUField(pkt []byte, off int, img *spuImage, first int) {
+	pos := off * 2 // in nibbles
+	nibble := func() (int, bool) {
+		if pos/2 >= len(pkt) {
+			return 0, false
+		}
+		b := pkt[pos/2]
+		pos++
+		if pos%2 == 1 {
+			return int(b >> 4), true
+		}
+		return int(b & 0x0F), true
+	}
+	for y := first; y < img.height; y += 2 {
+		for x := 0; x < img.width; {
+			v, ok := nibble()
+			if !ok {
+				return
+			}
+			// Codes grow a nibble at a time until the run fits: 4, 8, 12 or
+			// 16 bits, the last two bits always the pixel value.
+			for _, limit := range []int{0x04, 0x10, 0x40} {
+				if v >= limit {
+					break
+				}
+				n, ok := nibble()
+				if !ok {
+					return
+				}
+				v = v<<4 | n
+			}
+			run, color := v>>2, byte(v&0x03)
+			if run == 0 {
+				run = img.width - x
+			}
+			for ; run > 0 && x < img.width; run-- {
+				img.pixels[y*img.width+x] = color
+				x++
+			}
+		}
+		if pos%2 == 1 { // rows are byte aligned
+			pos++
+		}
+	}
+}
+
+// render picks the ink value and draws it at twice the size: DVD lettering
+// is about 20px tall, under what tesseract reads reliably.
+func (s *spuImage) render() *image.Gray {
+	ink := s.inkValue()
+	canvas := newOCRCanvas(s.width, s.height, 2)
+	if ink < 0 {
+		return canvas.img
+	}
+	for y := 0; y < s.height; y++ {
+		for x := 0; x < s.width; x++ {
+			if int(s.pixels[y*s.width+x]) == ink {
+				canvas.ink(x, y)
+			}
+		}
+	}
+	return canvas.img
+}
+
+// inkValue is the opaque pixel value with the smallest share of pixels next
+// to a transparent one (or the edge), -1 when nothing is opaque.
+func (s *spuImage) inkValue() int {
+	var total, edge [4]int
+	opaque := func(x, y int) bool {
+		if x < 0 || y < 0 || x >= s.width || y >= s.height {
+			return false
+		}
+		return s.alpha[s.pixels[y*s.width+x]] > 0
+	}
+	for y := 0; y < s.height; y++ {
+		for x := 0; x < s.width; x++ {
+			v := s.pixels[y*s.width+x]
+			if s.alpha[v] == 0 {
+				continue
+			}
+			total[v]++
+			if !opaque(x-1, y) || !opaque(x+1, y) || !opaque(x, y-1) || !opaque(x, y+1) {
+				edge[v]++
+			}
+		}
+	}
+	ink, best := -1, 2.0
+	for v := 0; v < 4; v++ {
+		if total[v] == 0 {
+			continue
+		}
+		if share := float64(edge[v]) / float64(total[v]); share < best {
+			ink, best = v, share
+		}
+	}
+	return ink
+}
@@ -0,0 +1,221 @@
+package subtitle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─── A minimal VobSub program-stream encoder ───────────────────────────────
+
+// spuTestBox is a width×height subpicture: value 1 filling a box, a 2px
+// value-2 outline around it, value 0 outside — the shape DVD lettering takes.
+type spuTestBox struct {
+	width, height int
+	pts           time.Duration
+	duration      time.Duration // 0 = no stop command
+	contrast      []byte        // nil = no contrast command
+}
+
+func (b spuTestBox) pixels() []byte {
+	px := make([]byte, b.width*b.height)
+	for y := 0; y < b.height; y++ {
+		for x := 0; x < b.width; x++ {
+			edge := min(x, y, b.width-1-x, b.height-1-y)
+			switch {
+			case edge < 2:
+				px[y*b.width+x] = 0
+			case edge < 4:
+				px[y*b.width+x] = 2
+			default:
+				px[y*b.width+x] = 1
+			}
+		}
+	}
+	return px
+}
+
+// nibbleWriter packs the SPU's 4-bit run-length codes.
+type nibbleWriter struct {
+	out  []byte
+	half bool
+}
+
+func (w *nibbleWriter) put(n byte) {
+	if w.half {
+		w.out[len(w.out)-1] |= n & 0x0F
+	} else {
+		w.out = append(w.out, n<<4)
+	}
+	w.half = !w.half
+}
+
+func (w *nibbleWriter) code(run int, color byte) {
+	v := run<<2 | int(color)
+	switch {
+	case run == 0: // "to the end of the line" is always the 16-bit code
+		w.put(0)
+		w.put(0)
+		w.put(0)
+		w.put(color)
+	case run < 4:
+		w.put(byte(v))
+	case run < 16:
+		w.put(byte(v >> 4))
+		w.put(byte(v))
+	case run < 64:
+		w.put(0)
+		w.put(byte(v >> 4))
+		w.put(byte(v))
+	default:
+		w.put(0)
+		w.put(byte(v >> 8))
+		w.put(byte(v >> 4))
+		w.put(byte(v))
+	}
+}
+
+func (w *nibbleWriter) field(px []byte, width, first int) {
+	for y := first; y < len(px)/width; y += 2 {
+		line := px[y*width : (y+1)*width]
+		for x := 0; x < width; {
+			color, run := line[x], 1
+			for x+run < width && line[x+run] == color {
+				run++
+			}
+			if x+run == width && color == 0 {
+				w.code(0, 0) // run 0: to the end of the line
+			} else {
+				w.code(run, color)
+			}
+			x += run
+		}
+		if w.half {
+			w.put(0)
+		}
+	}
+}
+
+func encodeSPU(b spuTestBox) []byte {
+	px := b.pixels()
+	var w nibbleWriter
+	w.out = []byte{0, 0, 0, 0} // size + control offset, patched below
+	top := len(w.out)
+	w.field(px, b.width, 0)
+	bottom := len(w.out)
+	w.field(px, b.width, 1)
+	spu := w.out
+
+	first := len(spu)
+	x1, y1 := 100, 400
+	x2, y2 := x1+b.width-1, y1+b.height-1
+	seq := []byte{0, 0, 0, 0, spuCmdColor, 0x01, 0x23}
+	if b.contrast != nil {
+		seq = append(seq, spuCmdContrast, b.contrast[0], b.contrast[1])
+	}
+	seq = append(seq,
+		spuCmdArea, byte(x1>>4), byte(x1<<4)|byte(x2>>8), byte(x2), byte(y1>>4), byte(y1<<4)|byte(y2>>8), byte(y2),
+		spuCmdOffsets, byte(top>>8), byte(top), byte(bottom>>8), byte(bottom),
+		spuCmdStart, spuCmdEnd)
+	last := first
+	if b.duration > 0 {
+		last = first + len(seq)
+	}
+	binary.BigEndian.PutUint16(seq[2:], uint16(last))
+	spu = append(spu, seq...)
+	if b.duration > 0 {
+		delay := uint16(b.duration * 90000 / time.Second / 1024)
+		spu = append(spu, byte(delay>>8), byte(delay), byte(last>>8), byte(last), spuCmdStop, spuCmdEnd)
+	}
+	binary.BigEndian.PutUint16(spu[0:], uint16(len(spu)))
+	binary.BigEndian.PutUint16(spu[2:], uint16(first))
+	return spu
+}
+
+func psPTS(d time.Duration) []byte {
+	ticks := uint64(d * 90000 / time.Second)
+	return []byte{
+		0x21 | byte(ticks>>29)&0x0E,
+		byte(ticks >> 22),
+		byte(ticks>>14)&0xFE | 1,
+		byte(ticks >> 7),
+		byte(ticks<<1)&0xFE | 1,
+	}
+}
+
+// encodeVobSub wraps each SPU the way ffmpeg's vob muxer does: a pack header
+// per packet, the SPU split across private-stream-1 PES packets of at most
+// chunk bytes, PTS on the first only — with a padding packet in between.
+func encodeVobSub(chunk int, boxes ...spuTestBox) []byte {
+	var buf bytes.Buffer
+	for _, b := range boxes {
+		spu := encodeSPU(b)
+		for off := 0; off < len(spu); off += chunk {
+			buf.Write([]byte{0, 0, 1, 0xBA, 0x44, 0, 4, 0, 4, 1, 0x01, 0x89, 0xC3, 0xF8})
+			header := []byte{0x81, 0x00, 0x00}
+			if off == 0 {
+				header = append([]byte{0x81, 0x80, 0x05}, psPTS(b.pts)...)
+			}
+			payload := append(append(header, 0x20), spu[off:min(off+chunk, len(spu))]...)
+			buf.Write([]byte{0, 0, 1, 0xBD, byte(len(payload) >> 8), byte(len(payload))})
+			buf.Write(payload)
+			buf.Write([]byte{0, 0, 1, 0xBE, 0, 4, 0xFF, 0xFF, 0xFF, 0xFF})
+		}
+	}
+	buf.Write([]byte{0, 0, 1, 0xB9})
+	return buf.Bytes()
+}
+
+// ─── Decoding ──────────────────────────────────────────────────────────────
+
+func TestParseVobSub_ReassemblesAndTimesEachSubpicture(t *testing.T) {
+	data := encodeVobSub(64,
+		spuTestBox{width: 90, height: 24, pts: 2 * time.Second, duration: 3 * time.Second},
+		spuTestBox{width: 60, height: 24, pts: 10 * time.Second},
+	)
+
+	cues, err := parseVobSub(data)
+	require.NoError(t, err)
+	require.Len(t, cues, 2)
+	assert.Equal(t, 2*time.Second, cues[0].start)
+	assert.InDelta(t, 5*time.Second, cues[0].end, float64(12*time.Millisecond), "delays tick at 1024/90000 s")
+	assert.Equal(t, 10*time.Second, cues[1].start)
+	assert.Zero(t, cues[1].end, "no stop command — the OCR fills it in")
+}
+
+// TestParseVobSub_InkIsTheFillNotTheOutline — with no palette to say which
+// value is bright, the lettering is found by shape.
+func TestParseVobSub_InkIsTheFillNotTheOutline(t *testing.T) {
+	box := spuTestBox{width: 90, height: 24, pts: time.Second, duration: time.Second}
+	cues, err := parseVobSub(encodeVobSub(2048, box))
+	require.NoError(t, err)
+	require.Len(t, cues, 1)
+
+	img := cues[0].render()
+	assert.Equal(t, 90*2+2*ocrCanvasMargin, img.Bounds().Dx(), "rendered at twice the size")
+	assert.True(t, isInk(img, 45, 12, 2), "the fill")
+	assert.False(t, isInk(img, 2, 12, 2), "the outline")
+	assert.False(t, isInk(img, 0, 0, 2), "the background")
+	assert.True(t, isInk(img, 45, 13, 2), "the second field is decoded too")
+}
+
+func TestParseVobSub_ContrastMakesValuesTransparent(t *testing.T) {
+	// Contrast 0x0F00: only value 2 (the outline) is opaque, so it is the ink.
+	box := spuTestBox{width: 40, height: 16, pts: time.Second, contrast: []byte{0x0F, 0x00}}
+	cues, err := parseVobSub(encodeVobSub(2048, box))
+	require.NoError(t, err)
+	require.Len(t, cues, 1)
+
+	img := cues[0].render()
+	assert.True(t, isInk(img, 2, 8, 2))
+	assert.False(t, isInk(img, 20, 8, 2))
+}
+
+func TestParseVobSub_NoSubpictureStream(t *testing.T) {
+	_, err := parseVobSub([]byte{0, 0, 1, 0xBA, 0x44, 0, 4, 0, 4, 1, 0x01, 0x89, 0xC3, 0xF8, 0, 0, 1, 0xB9})
+	assert.Error(t, err)
+}
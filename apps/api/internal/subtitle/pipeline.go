@@ -117,6 +117,10 @@ type ProcessItemOptions struct {
 	// nothing on this path can produce a charge. `internal/cost_consent_test.go`
 	// still guards the library-wide paid sweep, which remains uncalled.
 	FreeOnly bool
+
+	// OutputMode overrides the library's subtitle output mode for this run;
+	// the zero value uses the library's. ADDITIVE on v1 like FreeOnly.
+	OutputMode models.SubtitleOutputMode
 }
 
 // ProcessOutcome is what one item flow produced.
@@ -157,6 +161,9 @@ type MediaItem struct {
 	// on that would serialize nothing while defeating the exact season-batch
 	// case D10 exists for.
 	ShowKey string
+	// LibraryID is the media library the item belongs to (an episode's is its
+	// series'); empty when unassigned. It picks the library's output mode.
+	LibraryID string
 	// Context is the FR26 show metadata injected into the translation prompt
 	// and hashed into the run version.
 	Context TranslateContext
@@ -268,6 +275,10 @@ type Pipeline struct {
 	// fed, nothing harvested. Deliberately NOT in requireItemPorts.
 	glossary GlossaryStore
 
+	// outputModes is the OPTIONAL per-library output mode lookup — nil means
+	// translated-only unless a run overrides it.
+	outputModes OutputModePolicy
+
 	// modelID is the model that produces the translations — a RunVersion field,
 	// so it is wiring-supplied (sub-1-6 reads it from config) rather than
 	// discovered at call time.
@@ -501,6 +512,7 @@ func countRequest(ctx context.Context) {
 // the hook is sub-1-6's SSE bridge, and every call site here must work with it
 // absent (AC #8 keeps SSE entirely out of this story).
 func (p *Pipeline) emitProgress(ref MediaRef, stage PipelineStage, message string) {
+	observeRun("pipeline", stage)
 	if p.progress == nil {
 		return
 	}
@@ -543,7 +555,9 @@ func (p *Pipeline) observeChunk(ctx context.Context, chunk, total int, usage ai.
 // deliveredLanguage / deliveredFormat are what M1 always writes. They are
 // constants rather than parameters on purpose: D3 gives placer.go sole
 // ownership of the sidecar, and every route in this pipeline converges on one
-// zh-Hant .srt beside the media file.
+// zh-Hant sidecar beside the media file. deliveredFormat is the DEFAULT: a
+// routed ASS or WebVTT track is written back in its own format so its styling
+// survives, and the ASR leg always writes SRT.
 const (
 	deliveredLanguage = "zh-Hant"
 	deliveredFormat   = "srt"
@@ -557,8 +571,25 @@ func ExpectedSidecarPath(mediaPath string) string {
 	return BuildSubtitleFilename(mediaPath, NormalizeLanguageTag(deliveredLanguage), deliveredFormat)
 }
 
+// existingSidecarPath is the sidecar under language the pre-flight should
+// judge: the first of the .srt/.ass/.ssa/.vtt candidates that exists, or the
+// .srt path when none does. Without it a styled delivery would never satisfy
+// P5 and every re-trigger would pay for the translation again. language is
+// deliveredLanguage, or bilingualLanguage for a bilingual run — which must
+// never be satisfied by the translated-only file.
+func existingSidecarPath(mediaPath, language string) string {
+	lang := NormalizeLanguageTag(language)
+	for _, format := range sidecarFormats {
+		path := BuildSubtitleFilename(mediaPath, lang, string(format))
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return BuildSubtitleFilename(mediaPath, lang, deliveredFormat)
+}
+
 // acceptableSidecar is P5's predicate, spelled out: the file EXISTS, parses via
-// ParseSRT without error, and carries at least one cue.
+// the cue codec for its extension without error, and carries at least one cue.
 //
 // The cue-count clause is the whole point. An existence-only check treats a
 // zero-byte or truncated artifact as valid and permanently blocks regeneration
@@ -573,10 +604,11 @@ func acceptableSidecar(path string) (bool, string) {
 		return false, fmt.Sprintf("existing zh-Hant sidecar is unreadable (%v)", err)
 	}
 
-	blocks, err := ParseSRT(string(raw))
+	doc, err := ParseDocumentFile(path, raw)
 	if err != nil {
 		return false, fmt.Sprintf("existing zh-Hant sidecar does not parse (%v)", err)
 	}
+	blocks := doc.Blocks
 	if len(blocks) == 0 {
 		return false, fmt.Sprintf("existing zh-Hant sidecar parsed to 0 cues (%d bytes) — truncated, not acceptable", len(raw))
 	}
@@ -590,12 +622,16 @@ func acceptableSidecar(path string) (bool, string) {
 //
 // force bypasses the gate entirely: the operator asked for a re-run, and
 // placer.Place's .bak backup is the safety net for the overwrite.
+//
+// The sidecar judged is the one version's output mode writes: a bilingual run
+// is not satisfied by the translated-only file, nor the reverse.
 func (p *Pipeline) preflightSkip(ctx context.Context, ref MediaRef, mediaPath string, version models.RunVersion, opts ProcessItemOptions) (bool, string) {
 	if opts.Force {
 		return false, "force: pre-flight and segment-cache reads bypassed"
 	}
 
-	ok, reason := acceptableSidecar(ExpectedSidecarPath(mediaPath))
+	language := sidecarLanguage(models.SubtitleOutputMode(version.OutputMode))
+	ok, reason := acceptableSidecar(existingSidecarPath(mediaPath, language))
 	if !ok {
 		return false, reason
 	}
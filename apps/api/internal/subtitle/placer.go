@@ -10,10 +10,12 @@ import (
 	"strings"
 )
 
-// Supported subtitle output formats.
+// Supported subtitle output formats — every format the cue codec writes.
 var supportedFormats = map[string]bool{
-	"srt": true,
-	"ass": true,
+	string(CueFormatSRT): true,
+	string(CueFormatASS): true,
+	string(CueFormatSSA): true,
+	string(CueFormatVTT): true,
 }
 
 // PlacerConfig controls subtitle file placement behavior.
@@ -28,10 +30,17 @@ func DefaultPlacerConfig() PlacerConfig {
 	return PlacerConfig{BackupExisting: true}
 }
 
+// ChangeNotifier is told about every subtitle the placer writes, so a media
+// server can pick it up without waiting for its own library scan.
+type ChangeNotifier interface {
+	NotifyChanged(path string)
+}
+
 // Placer handles subtitle file placement alongside media files.
 // It performs pure file operations with no database dependency.
 type Placer struct {
-	config PlacerConfig
+	config   PlacerConfig
+	notifier ChangeNotifier
 }
 
 // NewPlacer creates a subtitle file placer with the given config.
@@ -39,6 +48,11 @@ func NewPlacer(config PlacerConfig) *Placer {
 	return &Placer{config: config}
 }
 
+// SetNotifier wires the optional post-write notification (nil disables it).
+func (p *Placer) SetNotifier(n ChangeNotifier) {
+	p.notifier = n
+}
+
 // PlaceRequest contains the parameters for placing a subtitle file.
 type PlaceRequest struct {
 	// MediaFilePath is the absolute path to the media file.
@@ -50,8 +64,9 @@ type PlaceRequest struct {
 	// Language is the detected language tag (e.g., "zh-Hant", "zh-Hans").
 	Language string
 
-	// Format is a hint for the subtitle format (e.g., "srt", "ass").
-	// If empty, format is detected from content.
+	// Format is a hint for the subtitle format (e.g., "srt", "ass", "vtt").
+	// If empty, format is detected from content, so a translated ASS track is
+	// written back as .ass rather than renamed to .srt.
 	Format string
 
 	// Score is the subtitle scoring result (stored in DB).
@@ -123,6 +138,10 @@ func (p *Placer) Place(req PlaceRequest) (*PlaceResult, error) {
 		"size", len(req.SubtitleData),
 	)
 
+	if p.notifier != nil {
+		p.notifier.NotifyChanged(targetPath)
+	}
+
 	return &PlaceResult{
 		SubtitlePath: targetPath,
 		Language:     langTag,
@@ -130,8 +149,55 @@ func (p *Placer) Place(req PlaceRequest) (*PlaceResult, error) {
 	}, nil
 }
 
+// Rewrite replaces an existing subtitle file with data under its own name —
+// a re-timed sidecar keeps the name the player already picked up. The old
+// content is backed up first when the placer is configured to.
+func (p *Placer) Rewrite(subtitlePath string, data []byte) (*PlaceResult, error) {
+	cleanPath := filepath.Clean(subtitlePath)
+	if !filepath.IsAbs(cleanPath) {
+		return nil, fmt.Errorf("placer: subtitle path must be absolute: %s", subtitlePath)
+	}
+	if info, err := os.Stat(cleanPath); err != nil || info.IsDir() {
+		return nil, fmt.Errorf("placer: no subtitle file to rewrite: %s", cleanPath)
+	}
+
+	var backupPath string
+	if p.config.BackupExisting {
+		bp, err := backupExistingFile(cleanPath)
+		if err != nil {
+			return nil, fmt.Errorf("placer: backup failed: %w", err)
+		}
+		backupPath = bp
+	}
+	if err := writeFileAtomic(cleanPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("placer: write failed: %w", err)
+	}
+
+	slog.Info("Subtitle rewritten", "path", cleanPath, "size", len(data))
+	if p.notifier != nil {
+		p.notifier.NotifyChanged(cleanPath)
+	}
+	return &PlaceResult{SubtitlePath: cleanPath, BackupPath: backupPath}, nil
+}
+
 // normalizeLanguageTag maps various language tag formats to IETF BCP 47.
 func normalizeLanguageTag(lang string) string {
+	// A composite tag ("zh-Hant.en", a bilingual sidecar) is accepted only
+	// when every part is one of the canonical tags below — a dot is otherwise
+	// exactly what a crafted tag would use to reach another file name.
+	if strings.Contains(lang, ".") {
+		parts := strings.Split(lang, ".")
+		for i, part := range parts {
+			if part == "" || strings.Contains(part, ".") {
+				return "und"
+			}
+			parts[i] = normalizeLanguageTag(part)
+			if !compositeTagParts[parts[i]] {
+				return "und"
+			}
+		}
+		return strings.Join(parts, ".")
+	}
 	lower := strings.ToLower(lang)
 	switch lower {
 	case "zh-hant", "zh-tw", "cht", "繁體", "繁体":
@@ -155,6 +221,9 @@ func normalizeLanguageTag(lang string) string {
 	}
 }
 
+// compositeTagParts are the canonical tags a composite tag may combine.
+var compositeTagParts = map[string]bool{"zh-Hant": true, "zh-Hans": true, "zh": true, "en": true}
+
 // safeTagPattern matches valid BCP 47-like language tags (alphanumeric + hyphens).
 var safeTagPattern = regexp.MustCompile(`^[a-zA-Z0-9\-]+$`)
 
@@ -200,17 +269,14 @@ func detectFormat(data []byte, hintFormat string) (string, error) {
 		return "", fmt.Errorf("unsupported subtitle format: %q", hintFormat)
 	}
 
-	// Content-based detection
-	content := string(data[:min(len(data), 500)])
-
-	// ASS format: starts with [Script Info]
-	if strings.Contains(content, "[Script Info]") || strings.Contains(content, "[V4+ Styles]") {
-		return "ass", nil
+	// Content-based detection: ASS/SSA and WebVTT announce themselves in a
+	// header; SRT has none, so it needs a timestamp arrow to qualify.
+	if format := SniffCueFormat(data); format != CueFormatSRT {
+		return string(format), nil
 	}
-
-	// SRT format: digit followed by timestamp pattern (00:00:00,000 --> 00:00:00,000)
+	content := string(data[:min(len(data), 500)])
 	if strings.Contains(content, " --> ") {
-		return "srt", nil
+		return string(CueFormatSRT), nil
 	}
 
 	return "", fmt.Errorf("unable to detect subtitle format from content")
@@ -96,9 +96,13 @@ func TestDetectFormat(t *testing.T) {
 		{"hint srt", nil, "srt", "srt", false},
 		{"hint ass", nil, "ass", "ass", false},
 		{"hint .srt with dot", nil, ".srt", "srt", false},
-		{"hint unsupported", nil, "vtt", "", true},
+		{"hint vtt", nil, "vtt", "vtt", false},
+		{"hint ssa", nil, "ssa", "ssa", false},
+		{"hint unsupported", nil, "sub", "", true},
 		{"content SRT", []byte("1\n00:00:01,000 --> 00:00:03,000\nHello\n"), "", "srt", false},
 		{"content ASS", []byte("[Script Info]\nTitle: Test\n[V4+ Styles]\n"), "", "ass", false},
+		{"content SSA", []byte("[Script Info]\nScriptType: v4.00\n[V4 Styles]\n"), "", "ssa", false},
+		{"content WebVTT", []byte("WEBVTT\n\n00:00:01.000 --> 00:00:03.000\nHello\n"), "", "vtt", false},
 		{"content unknown", []byte("random text without markers"), "", "", true},
 	}
 
@@ -445,3 +449,35 @@ func TestPlacer_Place_FormatDetection(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, filepath.Ext(result2.SubtitlePath) == ".ass")
 }
+
+func TestNormalizeLanguageTag_Composite(t *testing.T) {
+	// A bilingual sidecar's tag is two canonical tags joined by a dot.
+	assert.Equal(t, "zh-Hant.en", normalizeLanguageTag("zh-Hant.en"))
+	assert.Equal(t, "zh-Hant.en", normalizeLanguageTag("zh-TW.EN"))
+	// Anything else with a dot stays unsafe.
+	assert.Equal(t, "und", normalizeLanguageTag("zh-Hant..en"))
+	assert.Equal(t, "und", normalizeLanguageTag("zh-Hant.ja"))
+	assert.Equal(t, "und", normalizeLanguageTag("../zh-Hant"))
+
+	got := BuildSubtitleFilename("/media/Movie.mkv", NormalizeLanguageTag(bilingualLanguage), "ass")
+	assert.Equal(t, filepath.Join("/media", "Movie.zh-Hant.en.ass"), got)
+}
+
+func TestPlacer_Rewrite_KeepsTheNameAndBacksUp(t *testing.T) {
+	dir := t.TempDir()
+	subtitlePath := filepath.Join(dir, "Movie.zh-Hant.ass")
+	require.NoError(t, os.WriteFile(subtitlePath, []byte("old timing"), 0644))
+
+	result, err := NewPlacer(DefaultPlacerConfig()).Rewrite(subtitlePath, []byte("new timing"))
+	require.NoError(t, err)
+	assert.Equal(t, subtitlePath, result.SubtitlePath)
+	assert.Equal(t, subtitlePath+".bak", result.BackupPath)
+
+	content, _ := os.ReadFile(subtitlePath)
+	assert.Equal(t, "new timing", string(content))
+	bakContent, _ := os.ReadFile(subtitlePath + ".bak")
+	assert.Equal(t, "old timing", string(bakContent))
+
+	_, err = NewPlacer(DefaultPlacerConfig()).Rewrite(filepath.Join(dir, "Missing.srt"), []byte("x"))
+	assert.Error(t, err, "rewrite never creates a file")
+}
@@ -48,7 +48,24 @@ func (r *Router) PredictRoute(ctx context.Context, mediaPath string) (RoutePredi
 	if info == nil {
 		return "", fmt.Errorf("predict route for %s: probe returned no tech info", mediaPath)
 	}
-	return PredictFromTracks(info.SubtitleTracks), nil
+	return r.PredictTracks(info.SubtitleTracks), nil
+}
+
+// PredictTracks is PredictFromTracks for a router with OCR wired: a bitmap
+// track the OCR route can read is an extraction, not an ASR run — it is what
+// SelectAndRoute would route before ever reaching the no-track verdict. It
+// stays probe-only; CanRead looks at codec and tag, never at the frames.
+func (r *Router) PredictTracks(tracks []services.SubtitleTrack) RoutePrediction {
+	prediction := PredictFromTracks(tracks)
+	if prediction == PredictExtract || r.ocr == nil {
+		return prediction
+	}
+	for _, t := range tracks {
+		if r.ocr.CanRead(t) {
+			return PredictExtract
+		}
+	}
+	return prediction
 }
 
 // PredictFromTracks is the pure classifier over an already-known track list.
@@ -61,7 +78,8 @@ func (r *Router) PredictRoute(ctx context.Context, mediaPath string) (RoutePredi
 // The tiering mirrors the run-time router exactly: SelectCandidates decides
 // what is usable (embedded + text codec + Chinese-or-eng/en tag), and anything
 // it rejects falls through to the same text-vs-image reasoning
-// verdictWithoutTrack applies.
+// verdictWithoutTrack applies. It knows nothing of OCR; a router with OCR
+// wired answers through PredictTracks.
 func PredictFromTracks(tracks []services.SubtitleTrack) RoutePrediction {
 	if len(SelectCandidates(tracks)) > 0 {
 		return PredictExtract
@@ -104,3 +104,22 @@ func TestPredictFromTracks_IsUsableWithoutProbing(t *testing.T) {
 	assert.Equal(t, PredictSkip,
 		PredictFromTracks([]services.SubtitleTrack{{Language: "und", Format: "subrip"}}))
 }
+
+// A bitmap track the router's OCR can read is routed before the no-track
+// verdict, so it must be quoted as an extraction, not a paid ASR run.
+func TestPredictTracks_CountsOCRReadableBitmapTracks(t *testing.T) {
+	ocr := &fakeTrackOCR{readable: map[int]bool{3: true}}
+	router := NewRouter(&fakeProber{}, &fakeExtractor{}, nil, WithRouterOCR(ocr))
+	pgs := services.SubtitleTrack{StreamIndex: 3, Language: "eng", Format: "hdmv_pgs_subtitle"}
+
+	assert.Equal(t, PredictExtract, router.PredictTracks([]services.SubtitleTrack{pgs}))
+	assert.Equal(t, PredictExtract, router.PredictTracks([]services.SubtitleTrack{
+		{StreamIndex: 2, Language: "und", Format: "subrip"}, pgs,
+	}), "readable beats skip: the router reaches OCR before declining")
+	assert.Equal(t, PredictASR, router.PredictTracks([]services.SubtitleTrack{
+		{StreamIndex: 4, Language: "eng", Format: "dvd_subtitle"},
+	}))
+
+	withoutOCR, _, _ := predictRouter(t, nil, nil)
+	assert.Equal(t, PredictASR, withoutOCR.PredictTracks([]services.SubtitleTrack{pgs}))
+}
@@ -50,12 +50,16 @@ func (p *Pipeline) ProcessItem(ctx context.Context, ref MediaRef, opts ProcessIt
 	p.feedGlossary(ctx, ref, item)
 
 	version := p.runVersion(item.Context)
+	// The output mode joins the resume identity only: the cache key reads the
+	// four translation fields, so a bilingual run reuses every cached cue.
+	mode := p.resolveOutputMode(ctx, ref, item, opts)
+	version.OutputMode = outputModeVersion(mode)
 
 	// ── Step 1: pre-flight (AC #2) ──────────────────────────────────────────
 	// Deliberately BEFORE the run row: an early-exit must leave no provenance
 	// behind, or every scan would append a row per already-done item.
 	if skip, reason := p.preflightSkip(ctx, ref, item.FilePath, version, opts); skip {
-		return &ProcessOutcome{SubtitlePath: ExpectedSidecarPath(item.FilePath)}, nil
+		return &ProcessOutcome{SubtitlePath: existingSidecarPath(item.FilePath, sidecarLanguage(mode))}, nil
 	} else if reason != "" {
 		p.logger.Debug("subtitle pre-flight proceeding", "media_id", ref.ID, "reason", reason)
 	}
@@ -99,12 +103,16 @@ func (p *Pipeline) ProcessItem(ctx context.Context, ref MediaRef, opts ProcessIt
 		GlossaryVersion: version.GlossaryVersion,
 		PromptVersion:   version.PromptVersion,
 		ModelID:         version.ModelID,
+		OutputMode:      version.OutputMode,
 		Status:          models.SubtitleRunPending,
 		StartedAt:       p.now().UTC(),
 	}
 	if err := p.runs.Create(ctx, run); err != nil {
 		return nil, fmt.Errorf("subtitle pipeline: create run for %s %s: %w", ref.MediaType, ref.ID, err)
 	}
+	// The spend ledger files this item's calls under its run row, unless a
+	// consent batch already named the run.
+	ctx = ai.WithUsageMedia(ai.WithUsageRun(ctx, run.ID), ref.ID)
 
 	// pending → running is a separate write on purpose: a process killed
 	// mid-item leaves an honest `running` row, distinguishable from one that
@@ -179,7 +187,13 @@ func (p *Pipeline) ProcessItem(ctx context.Context, ref MediaRef, opts ProcessIt
 	}
 
 	// ── Step 3b: produce the deliverable ────────────────────────────────────
-	payload, cueCount, err := p.deliverable(ctx, ref, decision, item, version, opts)
+	// Only the translate route has a source line to pair; the others deliver
+	// the plain zh-Hant sidecar and the run row says so.
+	if decision.Kind != RouteTranslate {
+		mode = models.SubtitleOutputTranslated
+		run.OutputMode = ""
+	}
+	payload, cueCount, format, err := p.deliverable(ctx, ref, decision, item, version, mode, opts)
 	if err != nil {
 		return p.failItem(ctx, ref, run, string(decision.Kind), err)
 	}
@@ -189,8 +203,8 @@ func (p *Pipeline) ProcessItem(ctx context.Context, ref MediaRef, opts ProcessIt
 	placed, err := p.placer.Place(PlaceRequest{
 		MediaFilePath: item.FilePath,
 		SubtitleData:  payload,
-		Language:      deliveredLanguage,
-		Format:        deliveredFormat,
+		Language:      sidecarLanguage(mode),
+		Format:        string(format),
 		// Score stays 0 so the repository writes NULL: this file was generated,
 		// not scored against provider results (AC #6.3).
 		Score: 0,
@@ -232,6 +246,7 @@ func (p *Pipeline) ProcessItem(ctx context.Context, ref MediaRef, opts ProcessIt
 		"media_id", ref.ID,
 		"media_type", ref.MediaType,
 		"route", string(decision.Kind),
+		"output_mode", string(mode),
 		"cue_count", cueCount,
 		"cache_enabled", run.CacheEnabled,
 		"requests_sent", scope.requestsSent,
@@ -246,22 +261,24 @@ func (p *Pipeline) ProcessItem(ctx context.Context, ref MediaRef, opts ProcessIt
 }
 
 // deliverable turns a verdict into the exact bytes placer.Place will write,
-// plus the cue count recorded as provenance.
+// plus the cue count recorded as provenance and the format to place them as.
 func (p *Pipeline) deliverable(
 	ctx context.Context,
 	ref MediaRef,
 	decision RouteDecision,
 	item *MediaItem,
 	version models.RunVersion,
+	mode models.SubtitleOutputMode,
 	opts ProcessItemOptions,
-) ([]byte, int, error) {
+) ([]byte, int, CueFormat, error) {
 	source := decision.Track.Blocks
 
 	switch decision.Kind {
 	case RouteDeliverDirect:
 		// FR7 — the content is already Traditional; the only thing wrong with
 		// it was the ffprobe language tag.
-		return []byte(SerializeSRT(source)), len(source), nil
+		payload, format := serializeTrack(decision.Track, source)
+		return payload, len(source), format, nil
 
 	case RouteConvertThenDeliver:
 		// FR8 — one deterministic s2twp pass over the serialized track. Unlike
@@ -269,22 +286,58 @@ func (p *Pipeline) deliverable(
 		// Simplified leak and OpenCC is polish), conversion IS the deliverable
 		// here: shipping unconverted text under a .zh-Hant.srt name would be a
 		// lie, so a converter failure fails the item.
+		if decision.Track.Document != nil {
+			return p.convertStyledTrack(decision.Track)
+		}
 		converted, err := p.converter.ConvertS2TWP([]byte(SerializeSRT(source)))
 		if err != nil {
-			return nil, 0, fmt.Errorf("opencc s2twp on the routed track: %w", err)
+			return nil, 0, "", fmt.Errorf("opencc s2twp on the routed track: %w", err)
 		}
-		return converted, len(source), nil
+		return converted, len(source), CueFormatSRT, nil
 
 	case RouteTranslate:
 		blocks, err := p.translateWithCache(ctx, ref, decision.Track, item.Context, version, opts)
 		if err != nil {
-			return nil, 0, err
+			return nil, 0, "", err
+		}
+		if mode.IsBilingual() {
+			payload, format := serializeBilingual(mode, source, blocks)
+			return payload, len(blocks), format, nil
 		}
-		return []byte(SerializeSRT(blocks)), len(blocks), nil
+		payload, format := serializeTrack(decision.Track, blocks)
+		return payload, len(blocks), format, nil
 
 	default:
-		return nil, 0, fmt.Errorf("unreachable verdict %q", decision.Kind)
+		return nil, 0, "", fmt.Errorf("unreachable verdict %q", decision.Kind)
+	}
+}
+
+// serializeTrack writes blocks in the routed track's own format: through its
+// Document when it carries one (only dialogue text is replaced — styles,
+// override tags and cue settings are the source's), as SRT otherwise.
+func serializeTrack(track *ExtractedTrack, blocks []SubtitleBlock) ([]byte, CueFormat) {
+	if track.Document == nil {
+		return []byte(SerializeSRT(blocks)), CueFormatSRT
+	}
+	return []byte(track.Document.WithBlocks(blocks).Serialize()), track.Document.Format
+}
+
+// convertStyledTrack is FR8 for a track with a styled source. The s2twp pass
+// runs per CUE rather than over the serialized file: an ASS style table names
+// fonts ("微软雅黑"), and converting those would point the renderer at a font
+// that does not exist.
+func (p *Pipeline) convertStyledTrack(track *ExtractedTrack) ([]byte, int, CueFormat, error) {
+	converted := make([]SubtitleBlock, len(track.Blocks))
+	for i, b := range track.Blocks {
+		text, err := p.converter.ConvertS2TWP([]byte(b.Text))
+		if err != nil {
+			return nil, 0, "", fmt.Errorf("opencc s2twp on cue %d of the routed track: %w", b.Index, err)
+		}
+		b.Text = string(text)
+		converted[i] = b
 	}
+	payload, format := serializeTrack(track, converted)
+	return payload, len(converted), format, nil
 }
 
 // translateWithCache is the FR10 path: split the track against the segment
@@ -1085,3 +1085,71 @@ func TestProcessItem_TranslateLegBudgetCeilingFailsTheItem(t *testing.T) {
 	last := h.media.writes[len(h.media.writes)-1]
 	assert.Equal(t, models.SubtitleStatusNotSearched, last.status, "budget-failed items stay retryable")
 }
+
+// ─── Styled source formats (ASS/SSA, WebVTT) ───────────────────────────────
+
+// TestProcessItem_TranslatedASSTrackIsPlacedAsASS — a fansub ASS track keeps
+// its style table and positioning; only the dialogue text is replaced.
+func TestProcessItem_TranslatedASSTrackIsPlacedAsASS(t *testing.T) {
+	doc, err := ParseASS(fansubASS)
+	require.NoError(t, err)
+
+	h := newItemHarness(t, RouteDecision{
+		Kind:            RouteTranslate,
+		Track:           &ExtractedTrack{StreamIndex: 2, Language: "eng", Codec: "ass", Blocks: doc.Blocks, Document: doc},
+		DetectedVariant: LangUndetermined,
+	})
+
+	_, err = h.pipeline.ProcessItem(context.Background(), h.ref, ProcessItemOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, h.placer.requests, 1)
+	req := h.placer.requests[0]
+	assert.Equal(t, "ass", req.Format)
+	payload := string(req.SubtitleData)
+	assert.Contains(t, payload, "Style: Sign,Arial,40")
+	assert.Contains(t, payload, `Dialogue: 0,0:00:01.50,0:00:04.00,Default,Ann,0,0,0,,早安`)
+	assert.Contains(t, payload, `{\an8\pos(960,80)}早安`)
+}
+
+// TestProcessItem_StyledConvertLeavesTheStyleTableAlone — s2twp runs per cue,
+// so a Simplified font name in the style table is not "converted" into a
+// font the renderer cannot find.
+func TestProcessItem_StyledConvertLeavesTheStyleTableAlone(t *testing.T) {
+	doc, err := ParseASS(fansubASS)
+	require.NoError(t, err)
+	blocks := append([]SubtitleBlock(nil), doc.Blocks...)
+	blocks[0].Text = "这个软件很好用"
+
+	h := newItemHarness(t, RouteDecision{
+		Kind:            RouteConvertThenDeliver,
+		Track:           &ExtractedTrack{StreamIndex: 3, Language: "chi", Codec: "ass", Blocks: blocks, Document: doc},
+		DetectedVariant: LangSimplified,
+	})
+
+	_, err = h.pipeline.ProcessItem(context.Background(), h.ref, ProcessItemOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, h.placer.requests, 1)
+	payload := string(h.placer.requests[0].SubtitleData)
+	assert.Contains(t, payload, "Style: Default,微软雅黑,60")
+	assert.Contains(t, payload, "這個軟件很好用")
+	for _, in := range h.conv.inputs {
+		assert.NotContains(t, in, "[V4+ Styles]", "the converter only ever sees cue text")
+	}
+}
+
+// TestProcessItem_PreflightAcceptsAStyledSidecar — a previous run's .ass
+// delivery satisfies P5; without it every re-trigger would pay again.
+func TestProcessItem_PreflightAcceptsAStyledSidecar(t *testing.T) {
+	h := newItemHarness(t, translateDecision("Good morning."))
+	assPath := BuildSubtitleFilename(h.mediaPath, "zh-Hant", "ass")
+	require.NoError(t, os.WriteFile(assPath, []byte(fansubASS), 0o600))
+
+	outcome, err := h.pipeline.ProcessItem(context.Background(), h.ref, ProcessItemOptions{})
+	require.NoError(t, err)
+
+	assert.Nil(t, outcome.Run)
+	assert.Equal(t, assPath, outcome.SubtitlePath)
+	assert.Zero(t, h.router.calls)
+}
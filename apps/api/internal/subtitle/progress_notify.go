@@ -0,0 +1,85 @@
+package subtitle
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/vido/api/internal/models"
+)
+
+// Notifier is the narrow port over services.NotificationService — the
+// subtitle package only ever hands it finished or failed runs.
+type Notifier interface {
+	Notify(ctx context.Context, event models.NotificationEvent)
+}
+
+// NewNotifyProgressHook adapts the Pipeline's progress hook onto the outbound
+// notification targets. Only the two terminal stages are forwarded: the
+// intermediate ones are UI progress and would spam a chat channel several
+// times per item.
+//
+// The item label is looked up through the same MediaStore the pipeline uses
+// (best-effort — a failed lookup falls back to the file name, then the id),
+// because a bare media id means nothing on a phone lock screen.
+func NewNotifyProgressHook(n Notifier, media MediaStore) func(ref MediaRef, stage PipelineStage, message string) {
+	return func(ref MediaRef, stage PipelineStage, message string) {
+		if n == nil {
+			return
+		}
+		var eventType models.NotificationEventType
+		var title string
+		switch stage {
+		case StageComplete:
+			eventType, title = models.NotificationEventSubtitleComplete, "字幕已生成"
+		case StageFailed:
+			eventType, title = models.NotificationEventSubtitleFailed, "字幕生成失敗"
+		default:
+			return
+		}
+
+		ctx := context.Background()
+		n.Notify(ctx, models.NotificationEvent{
+			Type:    eventType,
+			Title:   title,
+			Message: notifyMediaLabel(ctx, media, ref) + "\n" + message,
+			Data: map[string]interface{}{
+				"media_id":   ref.ID,
+				"media_type": ref.MediaType,
+				"stage":      string(stage),
+				"detail":     message,
+			},
+		})
+	}
+}
+
+// notifyMediaLabel names the item for a human: show title, else file name,
+// else the raw id.
+func notifyMediaLabel(ctx context.Context, media MediaStore, ref MediaRef) string {
+	if media == nil {
+		return ref.ID
+	}
+	item, err := media.Load(ctx, ref)
+	if err != nil || item == nil {
+		return ref.ID
+	}
+	if item.Context.Title != "" {
+		return item.Context.Title
+	}
+	if item.FilePath != "" {
+		return filepath.Base(item.FilePath)
+	}
+	return ref.ID
+}
+
+// ComposeProgress fans one progress callback out to several hooks, in order,
+// so main.go can keep the SSE bridge and add the notification bridge through
+// the single WithProgress option. Nil hooks are skipped.
+func ComposeProgress(hooks ...func(ref MediaRef, stage PipelineStage, message string)) func(ref MediaRef, stage PipelineStage, message string) {
+	return func(ref MediaRef, stage PipelineStage, message string) {
+		for _, hook := range hooks {
+			if hook != nil {
+				hook(ref, stage, message)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/models"
+)
+
+type fakeNotifier struct{ events []models.NotificationEvent }
+
+func (f *fakeNotifier) Notify(_ context.Context, event models.NotificationEvent) {
+	f.events = append(f.events, event)
+}
+
+func TestNotifyProgressHook_ForwardsOnlyTerminalStages(t *testing.T) {
+	n := &fakeNotifier{}
+	media := &fakeMediaStore{item: &MediaItem{FilePath: "/media/Parasite.mkv", Context: TranslateContext{Title: "寄生上流"}}}
+	hook := NewNotifyProgressHook(n, media)
+	ref := MediaRef{ID: "m1", MediaType: "movie"}
+
+	hook(ref, StageTranslating, "translating chunk 1/4")
+	hook(ref, StagePlacing, "placing subtitle file")
+	assert.Empty(t, n.events, "intermediate stages are UI progress, not notifications")
+
+	hook(ref, StageComplete, "subtitle generated")
+	hook(ref, StageFailed, "ffmpeg exited 1")
+	require.Len(t, n.events, 2)
+	assert.Equal(t, models.NotificationEventSubtitleComplete, n.events[0].Type)
+	assert.Contains(t, n.events[0].Message, "寄生上流")
+	assert.Equal(t, models.NotificationEventSubtitleFailed, n.events[1].Type)
+	assert.Equal(t, "ffmpeg exited 1", n.events[1].Data["detail"])
+}
+
+func TestNotifyProgressHook_LabelFallsBack(t *testing.T) {
+	ref := MediaRef{ID: "ep-9", MediaType: "episode"}
+
+	byFile := &fakeNotifier{}
+	NewNotifyProgressHook(byFile, &fakeMediaStore{item: &MediaItem{FilePath: "/tv/Show/S01E02.mkv"}})(ref, StageComplete, "")
+	require.Len(t, byFile.events, 1)
+	assert.Contains(t, byFile.events[0].Message, "S01E02.mkv")
+
+	byID := &fakeNotifier{}
+	NewNotifyProgressHook(byID, &fakeMediaStore{loadErr: errors.New("gone")})(ref, StageComplete, "")
+	require.Len(t, byID.events, 1)
+	assert.Contains(t, byID.events[0].Message, "ep-9")
+}
+
+func TestComposeProgress_CallsEveryHookInOrder(t *testing.T) {
+	var calls []string
+	composed := ComposeProgress(
+		func(MediaRef, PipelineStage, string) { calls = append(calls, "sse") },
+		nil,
+		func(MediaRef, PipelineStage, string) { calls = append(calls, "notify") },
+	)
+	composed(MediaRef{ID: "m1"}, StageComplete, "done")
+	assert.Equal(t, []string{"sse", "notify"}, calls)
+}
@@ -183,6 +183,7 @@ type openSubSearchAttrs struct {
 	DownloadCount int                  `json:"download_count"`
 	Release       string               `json:"release"`
 	UploadDate    string               `json:"upload_date"`
+	MovieHashMatch bool                `json:"moviehash_match"`
 	Uploader      *openSubUploader     `json:"uploader"`
 	Files         []openSubFile        `json:"files"`
 	FeatureDetails *openSubFeature     `json:"feature_details"`
@@ -202,38 +203,62 @@ type openSubFeature struct {
 }
 
 // Search queries the OpenSubtitles API for subtitles.
+//
+// With a FileHash the release hash is tried first: a moviehash hit is the same
+// release the user has on disk, which a title query cannot tell apart from
+// every other encode of a common title. Only when the hash finds nothing does
+// the title/IMDB query run.
 func (p *OpenSubProvider) Search(ctx context.Context, query SubtitleQuery) ([]SubtitleResult, error) {
-	return p.searchWithRetry(ctx, query, 0)
-}
-
-func (p *OpenSubProvider) searchWithRetry(ctx context.Context, query SubtitleQuery, retryCount int) ([]SubtitleResult, error) {
 	if p.disabled {
 		slog.Info("OpenSubtitles search skipped — provider disabled")
 		return nil, nil
 	}
 
-	if query.Title == "" && query.ImdbID == "" {
-		return nil, fmt.Errorf("opensubtitles: search requires title or IMDB ID")
+	if query.Title == "" && query.ImdbID == "" && query.FileHash == "" {
+		return nil, fmt.Errorf("opensubtitles: search requires title or IMDB ID (or a file hash)")
 	}
 
-	if err := p.ensureAuth(ctx); err != nil {
-		return nil, fmt.Errorf("opensubtitles: auth failed: %w", err)
+	if query.FileHash != "" {
+		results, err := p.searchWithRetry(ctx, hashSearchParams(query), 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) > 0 || (query.Title == "" && query.ImdbID == "") {
+			return results, nil
+		}
+		slog.Debug("OpenSubtitles: no moviehash matches, falling back to query search",
+			"moviehash", query.FileHash)
 	}
 
-	u, err := url.Parse(p.baseURL() + "/subtitles")
-	if err != nil {
-		return nil, fmt.Errorf("opensubtitles: invalid URL: %w", err)
+	return p.searchWithRetry(ctx, querySearchParams(query), 0)
+}
+
+// hashSearchParams narrows by release hash, plus the IMDB ID when known. The
+// free-text title is left out: it would drop hash hits whose feature title is
+// spelled differently.
+func hashSearchParams(query SubtitleQuery) url.Values {
+	q := url.Values{}
+	q.Set("moviehash", query.FileHash)
+	if query.ImdbID != "" {
+		q.Set("imdb_id", query.ImdbID)
 	}
+	setCommonSearchParams(q, query)
+	return q
+}
 
-	q := u.Query()
+// querySearchParams searches by IMDB ID, or by title when there is none.
+func querySearchParams(query SubtitleQuery) url.Values {
+	q := url.Values{}
 	if query.ImdbID != "" {
 		q.Set("imdb_id", query.ImdbID)
 	} else {
 		q.Set("query", query.Title)
 	}
-	if query.FileHash != "" {
-		q.Set("moviehash", query.FileHash)
-	}
+	setCommonSearchParams(q, query)
+	return q
+}
+
+func setCommonSearchParams(q url.Values, query SubtitleQuery) {
 	if len(query.Languages) > 0 {
 		q.Set("languages", strings.Join(query.Languages, ","))
 	} else {
@@ -245,7 +270,18 @@ func (p *OpenSubProvider) searchWithRetry(ctx context.Context, query SubtitleQue
 	if query.Episode > 0 {
 		q.Set("episode_number", strconv.Itoa(query.Episode))
 	}
-	u.RawQuery = q.Encode()
+}
+
+func (p *OpenSubProvider) searchWithRetry(ctx context.Context, params url.Values, retryCount int) ([]SubtitleResult, error) {
+	if err := p.ensureAuth(ctx); err != nil {
+		return nil, fmt.Errorf("opensubtitles: auth failed: %w", err)
+	}
+
+	u, err := url.Parse(p.baseURL() + "/subtitles")
+	if err != nil {
+		return nil, fmt.Errorf("opensubtitles: invalid URL: %w", err)
+	}
+	u.RawQuery = params.Encode()
 
 	if err := p.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("opensubtitles: rate limiter: %w", err)
@@ -272,7 +308,7 @@ func (p *OpenSubProvider) searchWithRetry(ctx context.Context, query SubtitleQue
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		}
-		return p.searchWithRetry(ctx, query, retryCount+1)
+		return p.searchWithRetry(ctx, params, retryCount+1)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -291,6 +327,7 @@ func (p *OpenSubProvider) searchWithRetry(ctx context.Context, query SubtitleQue
 			Source:    "opensubtitles",
 			Language:  item.Attributes.Language,
 			Downloads: item.Attributes.DownloadCount,
+			HashMatch: item.Attributes.MovieHashMatch,
 		}
 
 		if len(item.Attributes.Files) > 0 {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sync/atomic"
@@ -109,15 +110,13 @@ func TestOpenSubProvider_SearchWithIMDB(t *testing.T) {
 }
 
 func TestOpenSubProvider_SearchWithHash(t *testing.T) {
+	var requests []url.Values
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/login" {
 			json.NewEncoder(w).Encode(map[string]string{"token": "tok"})
 			return
 		}
-
-		// Verify hash parameter is sent
-		assert.Equal(t, "abc123hash", r.URL.Query().Get("moviehash"))
-		assert.Equal(t, "tt1234567", r.URL.Query().Get("imdb_id"))
+		requests = append(requests, r.URL.Query())
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(openSubSearchResponse{Data: nil})
@@ -131,6 +130,47 @@ func TestOpenSubProvider_SearchWithHash(t *testing.T) {
 		FileHash: "abc123hash",
 	})
 	require.NoError(t, err)
+
+	// Hash first, then the IMDB query without the hash once it found nothing.
+	require.Len(t, requests, 2)
+	assert.Equal(t, "abc123hash", requests[0].Get("moviehash"))
+	assert.Equal(t, "tt1234567", requests[0].Get("imdb_id"))
+	assert.Empty(t, requests[1].Get("moviehash"))
+	assert.Equal(t, "tt1234567", requests[1].Get("imdb_id"))
+}
+
+func TestOpenSubProvider_SearchHashHitSkipsQuery(t *testing.T) {
+	var searches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			json.NewEncoder(w).Encode(map[string]string{"token": "tok"})
+			return
+		}
+		atomic.AddInt32(&searches, 1)
+		assert.Equal(t, "abc123hash", r.URL.Query().Get("moviehash"))
+		assert.Empty(t, r.URL.Query().Get("query"), "the title does not narrow a hash search")
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(openSubSearchResponse{
+			Data: []openSubSearchItem{{
+				ID: "sub1",
+				Attributes: openSubSearchAttrs{
+					Language:       "zh-tw",
+					MovieHashMatch: true,
+					Files:          []openSubFile{{FileID: 7, FileName: "release.zh-tw.srt"}},
+				},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	p := newTestOpenSubProvider(server.URL)
+
+	results, err := p.Search(context.Background(), SubtitleQuery{Title: "Common Title", FileHash: "abc123hash"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].HashMatch)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&searches))
 }
 
 func TestOpenSubProvider_SearchWithSeasonEpisode(t *testing.T) {
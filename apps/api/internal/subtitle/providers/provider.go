@@ -79,4 +79,8 @@ type SubtitleResult struct {
 
 	// Format is the subtitle file format (e.g., "srt", "ass", "ssa").
 	Format string
+
+	// HashMatch is true when the source matched this result by the media
+	// file's release hash rather than by title (used for scoring).
+	HashMatch bool
 }
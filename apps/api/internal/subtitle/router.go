@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/vido/api/internal/services"
@@ -41,9 +42,15 @@ type RouteDecision struct {
 type ExtractedTrack struct {
 	StreamIndex int             // absolute ffmpeg stream index
 	Language    string          // the ffprobe tag that admitted it ("chi"/"zho"/…/"eng"/"en")
-	Codec       string          // source codec (subrip/ass/mov_text/…)
-	Path        string          // extracted .srt in the caller-owned temp dir
+	Codec       string          // source codec (subrip/ass/mov_text/…, or hdmv_pgs_subtitle/dvd_subtitle when OCR read it)
+	Path        string          // extracted subtitle file in the caller-owned temp dir
 	Blocks      []SubtitleBlock // parsed + SDH-filtered cues (original numbering — P7)
+
+	// Document is the parsed source file, carrying whatever styling its
+	// format holds (ASS styles and overrides, WebVTT cue settings). Delivery
+	// writes Blocks back through it so the sidecar keeps the source format.
+	// nil means plain SRT — the shape every pre-codec caller constructs.
+	Document *Document
 }
 
 // TechProber is the narrow port the router needs from services.FFprobeService,
@@ -58,6 +65,14 @@ type TrackExtractor interface {
 	Extract(ctx context.Context, mediaPath, tmpDir string, streamIndexes []int) (map[int]string, error)
 }
 
+// StyledTrackExtractor is the OPTIONAL widening of TrackExtractor that keeps
+// ASS/SSA and WebVTT tracks in their own container. The router type-asserts
+// for it, so an extractor without it (every test fake) keeps the .srt-only
+// behaviour and a styled track is simply delivered as SRT.
+type StyledTrackExtractor interface {
+	ExtractTracks(ctx context.Context, mediaPath, tmpDir string, tracks []services.SubtitleTrack) (map[int]string, error)
+}
+
 // Router turns a media file into a routing verdict: probe → extract →
 // SDH-filter → detect. It is PURE with respect to the outside world — it writes
 // nothing to the DB, broadcasts no SSE, and never calls converter.go or
@@ -65,19 +80,35 @@ type TrackExtractor interface {
 type Router struct {
 	prober    TechProber
 	extractor TrackExtractor
+	ocr       TrackOCR // nil = bitmap tracks are never read
 	logger    *slog.Logger
 }
 
+// RouterOption configures optional Router ports.
+type RouterOption func(*Router)
+
+// WithRouterOCR lets the router read PGS/VobSub tracks through OCR when no
+// text track of the same language tier exists.
+func WithRouterOCR(ocr TrackOCR) RouterOption {
+	return func(r *Router) {
+		r.ocr = ocr
+	}
+}
+
 // NewRouter wires the router to its two ports.
-func NewRouter(prober TechProber, extractor TrackExtractor, logger *slog.Logger) *Router {
+func NewRouter(prober TechProber, extractor TrackExtractor, logger *slog.Logger, opts ...RouterOption) *Router {
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return &Router{
+	r := &Router{
 		prober:    prober,
 		extractor: extractor,
 		logger:    logger.With("component", "subtitle_router"),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // SelectAndRoute probes fresh, extracts every candidate track in ONE ffmpeg
@@ -87,6 +118,12 @@ func NewRouter(prober TechProber, extractor TrackExtractor, logger *slog.Logger)
 // subtitle_tracks JSON: files move or change after a scan, pre-existing rows
 // carry no stream index, and `-map 0:{n}` needs the index to be true NOW. The
 // persisted column stays the scan-time signal; this probe is the run-time truth.
+//
+// With OCR wired, bitmap tracks slot into the language tiers: Chinese text,
+// then Chinese PGS/VobSub, then English text, then English PGS/VobSub. A
+// Chinese bitmap track beats English text for the same reason Chinese text
+// does (SelectCandidates) — the human translation is already in the file — and
+// reading it is free. A track OCR cannot read falls through to the next tier.
 func (r *Router) SelectAndRoute(ctx context.Context, mediaPath, tmpDir string) (RouteDecision, error) {
 	info, err := r.prober.Probe(ctx, mediaPath)
 	if err != nil {
@@ -99,16 +136,19 @@ func (r *Router) SelectAndRoute(ctx context.Context, mediaPath, tmpDir string) (
 	}
 
 	candidates := SelectCandidates(tracks)
+	if len(candidates) == 0 || !isChineseTag(candidates[0].Language) {
+		if decision, ok, err := r.routeByOCR(ctx, mediaPath, tmpDir, tracks, isChineseTag); ok || err != nil {
+			return decision, err
+		}
+	}
 	if len(candidates) == 0 {
+		if decision, ok, err := r.routeByOCR(ctx, mediaPath, tmpDir, tracks, isEnglishTag); ok || err != nil {
+			return decision, err
+		}
 		return r.verdictWithoutTrack(tracks), nil
 	}
 
-	indexes := make([]int, 0, len(candidates))
-	for _, c := range candidates {
-		indexes = append(indexes, c.StreamIndex)
-	}
-
-	outputs, err := r.extractor.Extract(ctx, mediaPath, tmpDir, indexes)
+	outputs, err := r.extract(ctx, mediaPath, tmpDir, candidates)
 	if err != nil {
 		return RouteDecision{}, fmt.Errorf("subtitle route: extract %s: %w", mediaPath, err)
 	}
@@ -149,6 +189,87 @@ func (r *Router) SelectAndRoute(ctx context.Context, mediaPath, tmpDir string) (
 	}, nil
 }
 
+// extract prefers the styled extraction when the wired extractor offers it.
+func (r *Router) extract(ctx context.Context, mediaPath, tmpDir string, candidates []services.SubtitleTrack) (map[int]string, error) {
+	if styled, ok := r.extractor.(StyledTrackExtractor); ok {
+		return styled.ExtractTracks(ctx, mediaPath, tmpDir, candidates)
+	}
+	indexes := make([]int, 0, len(candidates))
+	for _, c := range candidates {
+		indexes = append(indexes, c.StreamIndex)
+	}
+	return r.extractor.Extract(ctx, mediaPath, tmpDir, indexes)
+}
+
+// routeByOCR reads every bitmap track whose tag admit accepts and OCR can
+// read, and routes the best of them exactly as a text track would be routed
+// (pickBestCandidate's heuristic, routeForVariant's verdict). ok is false when
+// no such track yields a cue, so the caller moves on to the next tier; err is
+// only a cancellation, which must stop the run rather than fall through.
+func (r *Router) routeByOCR(ctx context.Context, mediaPath, tmpDir string, tracks []services.SubtitleTrack, admit func(string) bool) (RouteDecision, bool, error) {
+	if r.ocr == nil {
+		return RouteDecision{}, false, nil
+	}
+
+	var best ExtractedTrack
+	bestVariant := ""
+	found := false
+	for _, t := range tracks {
+		if !admit(t.Language) || !r.ocr.CanRead(t) {
+			continue
+		}
+		blocks, err := r.ocr.ReadTrack(ctx, mediaPath, tmpDir, t)
+		if err != nil {
+			if ctx.Err() != nil {
+				return RouteDecision{}, false, fmt.Errorf("subtitle route: ocr %s: %w", mediaPath, ctx.Err())
+			}
+			r.logger.Warn("subtitle candidate skipped", "stream_index", t.StreamIndex, "reason", "ocr failed", "error", err)
+			continue
+		}
+		kept, removed := FilterSDH(blocks)
+		r.logger.Debug("subtitle candidate filtered",
+			"stream_index", t.StreamIndex, "codec", t.Format, "cues_parsed", len(blocks), "cues_removed", removed, "cues_kept", len(kept))
+		if len(kept) == 0 {
+			continue
+		}
+
+		candidate := ExtractedTrack{StreamIndex: t.StreamIndex, Language: t.Language, Codec: t.Format, Blocks: kept}
+		variant := Detect([]byte(cueText(kept))).Language
+		if !found || betterCandidate(candidate, variant, best, bestVariant) {
+			best = candidate
+			bestVariant = variant
+			found = true
+		}
+	}
+	if !found {
+		return RouteDecision{}, false, nil
+	}
+
+	// The OCR result is written out like an extracted track, so the temp dir
+	// holds what was routed whichever way the track was read.
+	path := filepath.Join(tmpDir, fmt.Sprintf("track_%d.ocr.srt", best.StreamIndex))
+	if err := os.WriteFile(path, []byte(SerializeSRT(best.Blocks)), 0o600); err == nil {
+		best.Path = path
+	}
+
+	kind, reason := routeForVariant(bestVariant, best.StreamIndex, best.Language)
+	r.logger.Debug("subtitle route decided",
+		"media", mediaPath,
+		"stream_index", best.StreamIndex,
+		"language_tag", best.Language,
+		"codec", best.Codec,
+		"detected_variant", bestVariant,
+		"cue_count", len(best.Blocks),
+		"route", string(kind),
+	)
+	return RouteDecision{
+		Kind:            kind,
+		Track:           &best,
+		DetectedVariant: bestVariant,
+		Reason:          fmt.Sprintf("%s (read by OCR from %s)", reason, best.Codec),
+	}, true, nil
+}
+
 // verdictWithoutTrack distinguishes FR9 (a text track exists but M1 refuses to
 // guess its language) from FR5 (there is no usable text source at all). Only the
 // former is a deliberate skip; the latter is what P2's ASR can later recover.
@@ -215,11 +336,12 @@ func (r *Router) pickBestCandidate(candidates []services.SubtitleTrack, outputs
 			continue
 		}
 
-		blocks, err := ParseSRT(string(raw))
+		doc, err := ParseDocumentFile(path, raw)
 		if err != nil {
 			r.logger.Warn("subtitle candidate skipped", "stream_index", c.StreamIndex, "reason", "parse failed", "error", err)
 			continue
 		}
+		blocks := doc.Blocks
 		if len(blocks) == 0 {
 			r.logger.Warn("subtitle candidate skipped", "stream_index", c.StreamIndex, "reason", "no cues parsed")
 			continue
@@ -236,6 +358,9 @@ func (r *Router) pickBestCandidate(candidates []services.SubtitleTrack, outputs
 			Path:        path,
 			Blocks:      kept,
 		}
+		if doc.Format != CueFormatSRT {
+			candidate.Document = doc
+		}
 		variant := Detect([]byte(cueText(kept))).Language
 
 		if !found || betterCandidate(candidate, variant, best, bestVariant) {
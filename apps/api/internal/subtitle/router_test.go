@@ -570,3 +570,191 @@ func TestExtractorSatisfiesTrackExtractor(t *testing.T) {
 	var _ TrackExtractor = NewExtractor(0, nil)
 	var _ TechProber = services.NewFFprobeService(1, 0, nil)
 }
+
+// styledExtractor is a fakeExtractor that also offers the styled widening,
+// writing each track under the extension the production extractor would.
+type styledExtractor struct {
+	fakeExtractor
+	styledCalls int
+}
+
+func (f *styledExtractor) ExtractTracks(_ context.Context, _, tmpDir string, tracks []services.SubtitleTrack) (map[int]string, error) {
+	f.styledCalls++
+	out := make(map[int]string, len(tracks))
+	for _, t := range tracks {
+		path := nativeTrackOutputPath(tmpDir, t)
+		if err := os.WriteFile(path, []byte(f.contents[t.StreamIndex]), 0o600); err != nil {
+			return nil, err
+		}
+		out[t.StreamIndex] = path
+	}
+	return out, nil
+}
+
+func TestSelectAndRoute_StyledTrackCarriesItsDocument(t *testing.T) {
+	prober := &fakeProber{info: &services.MediaTechInfo{SubtitleTracks: []services.SubtitleTrack{
+		embedded(2, "eng", "ass"),
+	}}}
+	ex := &styledExtractor{fakeExtractor: fakeExtractor{contents: map[int]string{2: fansubASS}}}
+	r := NewRouter(prober, ex, nil)
+
+	got, err := r.SelectAndRoute(context.Background(), "/media/m.mkv", t.TempDir())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, ex.styledCalls)
+	assert.Zero(t, ex.callCount, "the styled extraction replaces the .srt one")
+	assert.Equal(t, RouteTranslate, got.Kind)
+	require.NotNil(t, got.Track.Document)
+	assert.Equal(t, CueFormatASS, got.Track.Document.Format)
+	assert.Len(t, got.Track.Blocks, 2)
+}
+
+func TestSelectAndRoute_PlainExtractorLeavesDocumentNil(t *testing.T) {
+	prober := &fakeProber{info: &services.MediaTechInfo{SubtitleTracks: []services.SubtitleTrack{
+		embedded(2, "eng", "ass"),
+	}}}
+	ex := &fakeExtractor{contents: map[int]string{2: srtOf("Hello there.")}}
+	r, tmp := newTestRouter(t, prober, ex)
+
+	got, err := r.SelectAndRoute(context.Background(), "/media/m.mkv", tmp)
+
+	require.NoError(t, err)
+	require.NotNil(t, got.Track)
+	assert.Nil(t, got.Track.Document, "an SRT extraction delivers as SRT")
+}
+
+// ─── OCR route (bitmap tracks) ─────────────────────────────────────────────
+
+// fakeTrackOCR reads the configured cues per stream; readable is what
+// CanRead admits.
+type fakeTrackOCR struct {
+	readable map[int]bool
+	cues     map[int][]string
+	errs     map[int]error
+	reads    []int
+}
+
+func (f *fakeTrackOCR) CanRead(track services.SubtitleTrack) bool {
+	return f.readable[track.StreamIndex]
+}
+
+func (f *fakeTrackOCR) ReadTrack(_ context.Context, _, _ string, track services.SubtitleTrack) ([]SubtitleBlock, error) {
+	f.reads = append(f.reads, track.StreamIndex)
+	if err := f.errs[track.StreamIndex]; err != nil {
+		return nil, err
+	}
+	return ParseSRT(srtOf(f.cues[track.StreamIndex]...))
+}
+
+func TestSelectAndRoute_ChineseBitmapTrackBeatsEnglishText(t *testing.T) {
+	// The Blu-ray remux shape: English as text, the studio Chinese only as PGS.
+	prober := &fakeProber{info: &services.MediaTechInfo{SubtitleTracks: []services.SubtitleTrack{
+		embedded(2, "eng", "subrip"),
+		embedded(3, "chi", "hdmv_pgs_subtitle"),
+	}}}
+	ex := &fakeExtractor{contents: map[int]string{2: srtOf("We're being lied to.")}}
+	ocr := &fakeTrackOCR{
+		readable: map[int]bool{3: true},
+		cues:     map[int][]string{3: {"我們一直以來都被騙", "所有人都必須看看這個"}},
+	}
+	tmp := t.TempDir()
+	r := NewRouter(prober, ex, nil, WithRouterOCR(ocr))
+
+	got, err := r.SelectAndRoute(context.Background(), "/media/m.mkv", tmp)
+
+	require.NoError(t, err)
+	assert.Equal(t, RouteDeliverDirect, got.Kind)
+	require.NotNil(t, got.Track)
+	assert.Equal(t, 3, got.Track.StreamIndex)
+	assert.Equal(t, "hdmv_pgs_subtitle", got.Track.Codec)
+	assert.Len(t, got.Track.Blocks, 2)
+	assert.Contains(t, got.Reason, "OCR")
+	assert.FileExists(t, got.Track.Path)
+	assert.Zero(t, ex.callCount, "the English text track is never extracted")
+}
+
+func TestSelectAndRoute_ChineseTextStillBeatsChineseBitmap(t *testing.T) {
+	prober := &fakeProber{info: &services.MediaTechInfo{SubtitleTracks: []services.SubtitleTrack{
+		embedded(2, "chi", "subrip"),
+		embedded(3, "chi", "hdmv_pgs_subtitle"),
+	}}}
+	ex := &fakeExtractor{contents: map[int]string{2: srtOf("我們一直以來都被騙")}}
+	ocr := &fakeTrackOCR{readable: map[int]bool{3: true}}
+	r := NewRouter(prober, ex, nil, WithRouterOCR(ocr))
+
+	got, err := r.SelectAndRoute(context.Background(), "/media/m.mkv", t.TempDir())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Track.StreamIndex)
+	assert.Empty(t, ocr.reads, "OCR is slow — it is never run when text will do")
+}
+
+func TestSelectAndRoute_EnglishBitmapTrackIsTranslated(t *testing.T) {
+	prober := &fakeProber{info: &services.MediaTechInfo{SubtitleTracks: []services.SubtitleTrack{
+		embedded(3, "eng", "hdmv_pgs_subtitle"),
+		embedded(4, "und", "hdmv_pgs_subtitle"),
+	}}}
+	ocr := &fakeTrackOCR{
+		readable: map[int]bool{3: true},
+		cues:     map[int][]string{3: {"We're being lied to.", "[DOOR SLAMS]"}},
+	}
+	r := NewRouter(prober, &fakeExtractor{}, nil, WithRouterOCR(ocr))
+
+	got, err := r.SelectAndRoute(context.Background(), "/media/m.mkv", t.TempDir())
+
+	require.NoError(t, err)
+	assert.Equal(t, RouteTranslate, got.Kind)
+	assert.Equal(t, 3, got.Track.StreamIndex)
+	assert.Len(t, got.Track.Blocks, 1, "OCR cues are SDH-filtered like any track")
+	assert.Equal(t, []int{3}, ocr.reads)
+}
+
+func TestSelectAndRoute_UnreadableBitmapFallsThroughToTheNextTier(t *testing.T) {
+	prober := &fakeProber{info: &services.MediaTechInfo{SubtitleTracks: []services.SubtitleTrack{
+		embedded(2, "eng", "subrip"),
+		embedded(3, "chi", "hdmv_pgs_subtitle"),
+	}}}
+	ex := &fakeExtractor{contents: map[int]string{2: srtOf("We're being lied to.")}}
+	ocr := &fakeTrackOCR{
+		readable: map[int]bool{3: true},
+		errs:     map[int]error{3: ErrSubtitleOCRFailed},
+	}
+	r := NewRouter(prober, ex, nil, WithRouterOCR(ocr))
+
+	got, err := r.SelectAndRoute(context.Background(), "/media/m.mkv", t.TempDir())
+
+	require.NoError(t, err)
+	assert.Equal(t, RouteTranslate, got.Kind)
+	assert.Equal(t, 2, got.Track.StreamIndex)
+}
+
+func TestSelectAndRoute_NoReadableBitmapIsStillNoTextSource(t *testing.T) {
+	prober := &fakeProber{info: &services.MediaTechInfo{SubtitleTracks: []services.SubtitleTrack{
+		embedded(3, "chi", "dvb_subtitle"),
+	}}}
+	ocr := &fakeTrackOCR{}
+	r := NewRouter(prober, &fakeExtractor{}, nil, WithRouterOCR(ocr))
+
+	got, err := r.SelectAndRoute(context.Background(), "/media/m.mkv", t.TempDir())
+
+	require.NoError(t, err)
+	assert.Equal(t, RouteNoTextSource, got.Kind)
+	assert.Empty(t, ocr.reads)
+}
+
+func TestSelectAndRoute_OCRCancellationStopsTheRun(t *testing.T) {
+	prober := &fakeProber{info: &services.MediaTechInfo{SubtitleTracks: []services.SubtitleTrack{
+		embedded(2, "eng", "subrip"),
+		embedded(3, "chi", "hdmv_pgs_subtitle"),
+	}}}
+	ex := &fakeExtractor{contents: map[int]string{2: srtOf("Hi.")}}
+	ocr := &fakeTrackOCR{readable: map[int]bool{3: true}, errs: map[int]error{3: context.Canceled}}
+	r := NewRouter(prober, ex, nil, WithRouterOCR(ocr))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := r.SelectAndRoute(ctx, "/media/m.mkv", t.TempDir())
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, ex.callCount)
+}
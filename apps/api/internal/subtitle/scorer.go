@@ -11,18 +11,24 @@ import (
 // scoreEpsilon is the threshold for treating two scores as equal.
 const scoreEpsilon = 1e-9
 
-// Scoring weight defaults (Gate 2A decision).
+// Scoring weight defaults (Gate 2A decision). The release-hash weight was
+// carved out of the other five in proportion, and outweighs resolution and
+// group: a hash match proves the timing fits the exact file, tags only suggest
+// it. When no result matched by hash, Score drops the hash weight and the
+// other five return to their Gate 2A values (0.40/0.20/0.20/0.10/0.10).
 const (
-	DefaultWeightLanguage   = 0.40
-	DefaultWeightResolution = 0.20
-	DefaultWeightTrust      = 0.20
-	DefaultWeightGroup      = 0.10
-	DefaultWeightDownloads  = 0.10
+	DefaultWeightLanguage   = 0.30
+	DefaultWeightHash       = 0.25
+	DefaultWeightResolution = 0.15
+	DefaultWeightTrust      = 0.15
+	DefaultWeightGroup      = 0.075
+	DefaultWeightDownloads  = 0.075
 )
 
 // ScorerConfig holds configurable weights and provider trust values.
 type ScorerConfig struct {
 	WeightLanguage   float64
+	WeightHash       float64
 	WeightResolution float64
 	WeightTrust      float64
 	WeightGroup      float64
@@ -36,6 +42,7 @@ type ScorerConfig struct {
 func NewDefaultScorerConfig() ScorerConfig {
 	return ScorerConfig{
 		WeightLanguage:   DefaultWeightLanguage,
+		WeightHash:       DefaultWeightHash,
 		WeightResolution: DefaultWeightResolution,
 		WeightTrust:      DefaultWeightTrust,
 		WeightGroup:      DefaultWeightGroup,
@@ -50,6 +57,7 @@ func NewDefaultScorerConfig() ScorerConfig {
 // ScoreBreakdown shows the individual factor scores for debugging/UI display.
 type ScoreBreakdown struct {
 	Language   float64 `json:"language"`
+	Hash       float64 `json:"hash"`
 	Resolution float64 `json:"resolution"`
 	SourceTrust float64 `json:"source_trust"`
 	Group      float64 `json:"group"`
@@ -89,6 +97,7 @@ func (s *Scorer) Score(results []providers.SubtitleResult, mediaResolution strin
 		}
 	}
 	singleResult := len(results) == 1
+	w := s.effectiveWeights(results)
 
 	scored := make([]ScoredResult, len(results))
 	for i, r := range results {
@@ -101,17 +110,19 @@ func (s *Scorer) Score(results []providers.SubtitleResult, mediaResolution strin
 
 		bd := ScoreBreakdown{
 			Language:    scoreLanguage(r.Language),
+			Hash:        scoreHash(r.HashMatch),
 			Resolution:  scoreResolution(mediaResolution, r.Resolution),
 			SourceTrust: s.scoreSourceTrust(r.Source),
 			Group:       scoreGroup(r.Group),
 			Downloads:   dlScore,
 		}
 
-		composite := bd.Language*s.config.WeightLanguage +
-			bd.Resolution*s.config.WeightResolution +
-			bd.SourceTrust*s.config.WeightTrust +
-			bd.Group*s.config.WeightGroup +
-			bd.Downloads*s.config.WeightDownloads
+		composite := bd.Language*w.WeightLanguage +
+			bd.Hash*w.WeightHash +
+			bd.Resolution*w.WeightResolution +
+			bd.SourceTrust*w.WeightTrust +
+			bd.Group*w.WeightGroup +
+			bd.Downloads*w.WeightDownloads
 
 		scored[i] = ScoredResult{
 			SubtitleResult: r,
@@ -150,6 +161,40 @@ func scoreLanguage(lang string) float64 {
 	}
 }
 
+// effectiveWeights returns the configured weights, or — when no result was
+// matched by release hash — the weights with the hash share removed and the
+// rest scaled back up to the same total. A search that could not use a hash
+// (no local file, or a source without hash support) is then scored exactly as
+// before hash matching existed, instead of capping every result below 1.0.
+func (s *Scorer) effectiveWeights(results []providers.SubtitleResult) ScorerConfig {
+	w := s.config
+	for _, r := range results {
+		if r.HashMatch {
+			return w
+		}
+	}
+	rest := 1 - w.WeightHash
+	if w.WeightHash <= 0 || rest <= 0 {
+		return w
+	}
+	w.WeightLanguage /= rest
+	w.WeightResolution /= rest
+	w.WeightTrust /= rest
+	w.WeightGroup /= rest
+	w.WeightDownloads /= rest
+	w.WeightHash = 0
+	return w
+}
+
+// scoreHash returns the release-hash factor: 1.0 when the source matched the
+// result by the media file's hash, 0.0 otherwise.
+func scoreHash(hashMatch bool) float64 {
+	if hashMatch {
+		return 1.0
+	}
+	return 0.0
+}
+
 // scoreResolution returns the resolution factor (0.0–1.0).
 // Exact match = 1.0, untagged = 0.5, mismatch = 0.2.
 func scoreResolution(mediaRes, subtitleRes string) float64 {
@@ -346,19 +346,53 @@ func TestScorer_Score_TertiaryTieBreakByID(t *testing.T) {
 
 func TestDefaultWeightsSumToOne(t *testing.T) {
 	config := NewDefaultScorerConfig()
-	sum := config.WeightLanguage + config.WeightResolution + config.WeightTrust +
+	sum := config.WeightLanguage + config.WeightHash + config.WeightResolution + config.WeightTrust +
 		config.WeightGroup + config.WeightDownloads
 	assert.InDelta(t, 1.0, sum, 0.001, "default weights must sum to 1.0")
 }
 
 func TestNewDefaultScorerConfig(t *testing.T) {
 	config := NewDefaultScorerConfig()
-	assert.InDelta(t, 0.4, config.WeightLanguage, 0.001)
-	assert.InDelta(t, 0.2, config.WeightResolution, 0.001)
-	assert.InDelta(t, 0.2, config.WeightTrust, 0.001)
-	assert.InDelta(t, 0.1, config.WeightGroup, 0.001)
-	assert.InDelta(t, 0.1, config.WeightDownloads, 0.001)
+	assert.InDelta(t, 0.3, config.WeightLanguage, 0.001)
+	assert.InDelta(t, 0.25, config.WeightHash, 0.001)
+	assert.InDelta(t, 0.15, config.WeightResolution, 0.001)
+	assert.InDelta(t, 0.15, config.WeightTrust, 0.001)
+	assert.InDelta(t, 0.075, config.WeightGroup, 0.001)
+	assert.InDelta(t, 0.075, config.WeightDownloads, 0.001)
+	assert.Greater(t, config.WeightHash, config.WeightResolution, "a hash match outweighs resolution")
+	assert.Greater(t, config.WeightHash, config.WeightGroup, "a hash match outweighs group")
 	// 9R-14: zimuku removed — only the two live sources remain
 	assert.Len(t, config.ProviderTrust, 2)
 	assert.NotContains(t, config.ProviderTrust, "zimuku")
 }
+
+// --- Release-hash matching ---
+
+func TestScorer_Score_HashMatchOutranksTags(t *testing.T) {
+	s := NewScorer(NewDefaultScorerConfig())
+
+	results := []providers.SubtitleResult{
+		// Right language, matching resolution, known group — but another release.
+		{ID: "tagged", Source: "opensubtitles", Language: "zh-TW", Resolution: "1080p", Group: "CHD", Downloads: 900},
+		// Same language, untagged, unknown uploader — matched by the file's hash.
+		{ID: "hash", Source: "opensubtitles", Language: "zh-TW", Group: "someone", Downloads: 100, HashMatch: true},
+	}
+
+	scored := s.Score(results, "1080p")
+	require.Len(t, scored, 2)
+	assert.Equal(t, "hash", scored[0].ID)
+	assert.InDelta(t, 1.0, scored[0].ScoreBreakdown.Hash, 0.001)
+	assert.InDelta(t, 0.0, scored[1].ScoreBreakdown.Hash, 0.001)
+}
+
+func TestScorer_Score_NoHashMatchesKeepsGate2AWeights(t *testing.T) {
+	s := NewScorer(NewDefaultScorerConfig())
+
+	// zh-Hant, exact resolution, assrt (0.8 trust), known group, single result:
+	// 0.40 + 0.20 + 0.16 + 0.10 + 0.10 under the Gate 2A weights.
+	scored := s.Score([]providers.SubtitleResult{
+		{ID: "1", Source: "assrt", Language: "zh-Hant", Resolution: "1080p", Group: "CHD", Downloads: 10},
+	}, "1080p")
+	require.Len(t, scored, 1)
+	assert.InDelta(t, 0.96, scored[0].Score, 0.001)
+}
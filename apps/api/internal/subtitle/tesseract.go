@@ -0,0 +1,147 @@
+package subtitle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultOCRTimeout bounds one tesseract run over one subtitle frame — a
+// frame is a line or two of text, so anything near this is a hung process.
+const defaultOCRTimeout = 30 * time.Second
+
+// Tesseract is the TextRecognizer over the tesseract CLI. Like Extractor, it
+// probes for the binary once at startup; the installed models come from
+// `tesseract --list-langs` at the same time. A missing binary leaves the
+// model list empty, which turns the OCR route off rather than failing runs.
+type Tesseract struct {
+	timeout   time.Duration
+	languages []string
+	logger    *slog.Logger
+}
+
+// NewTesseract creates a Tesseract, checking for the binary via exec.LookPath.
+func NewTesseract(timeout time.Duration, logger *slog.Logger) *Tesseract {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if timeout <= 0 {
+		timeout = defaultOCRTimeout
+	}
+	t := &Tesseract{
+		timeout: timeout,
+		logger:  logger.With("service", "subtitle_tesseract"),
+	}
+
+	if _, err := exec.LookPath("tesseract"); err != nil {
+		t.logger.Warn("tesseract not found — bitmap subtitle OCR disabled")
+		return t
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "tesseract", "--list-langs").Output()
+	if err != nil {
+		t.logger.Warn("tesseract --list-langs failed — bitmap subtitle OCR disabled", "error", err)
+		return t
+	}
+	t.languages = parseTesseractLangs(string(out))
+	t.logger.Info("tesseract available", "languages", t.languages, "timeout", t.timeout)
+	return t
+}
+
+// Languages implements TextRecognizer.
+func (t *Tesseract) Languages() []string {
+	return t.languages
+}
+
+// Recognize implements TextRecognizer: the frame goes in as a PNG on stdin and
+// comes back as TSV, which carries the per-word confidence plain text lacks.
+// --psm 6 reads the frame as one block of lines, which is what a subtitle is.
+func (t *Tesseract) Recognize(ctx context.Context, img image.Image, language string) (OCRText, error) {
+	var frame bytes.Buffer
+	if err := png.Encode(&frame, img); err != nil {
+		return OCRText{}, fmt.Errorf("tesseract: encode frame: %w", err)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(runCtx, "tesseract", "stdin", "stdout", "-l", language, "--psm", "6", "tsv")
+	// One thread: the pipeline's worker count is the CPU bound (NFR-P3), and
+	// tesseract's OpenMP would otherwise take every core per frame.
+	cmd.Env = append(os.Environ(), "OMP_THREAD_LIMIT=1")
+	cmd.Stdin = &frame
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := runCtx.Err(); ctxErr != nil {
+			return OCRText{}, fmt.Errorf("tesseract %s: %w", language, ctxErr)
+		}
+		return OCRText{}, fmt.Errorf("tesseract %s: %w (stderr: %s)", language, err, stderrTail(stderr.String()))
+	}
+	return parseTesseractTSV(stdout.String()), nil
+}
+
+// parseTesseractLangs reads `tesseract --list-langs`: a header line, then one
+// model per line. "osd" is orientation detection, not a language.
+func parseTesseractLangs(out string) []string {
+	var langs []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "osd" || strings.HasPrefix(line, "List of") {
+			continue
+		}
+		langs = append(langs, line)
+	}
+	return langs
+}
+
+// parseTesseractTSV rebuilds the lines of a TSV result and averages the word
+// confidences. Columns: level page block par line word left top width height
+// conf text; words are level 5, and only they carry a confidence.
+func parseTesseractTSV(tsv string) OCRText {
+	type lineKey struct{ block, par, line string }
+	var (
+		order []lineKey
+		words = map[lineKey][]string{}
+		total float64
+		count int
+	)
+	for _, row := range strings.Split(tsv, "\n") {
+		cols := strings.Split(strings.TrimRight(row, "\r"), "\t")
+		if len(cols) < 12 || cols[0] != "5" {
+			continue
+		}
+		text := strings.TrimSpace(cols[11])
+		conf, err := strconv.ParseFloat(cols[10], 64)
+		if text == "" || err != nil || conf < 0 {
+			continue
+		}
+		key := lineKey{cols[2], cols[3], cols[4]}
+		if _, seen := words[key]; !seen {
+			order = append(order, key)
+		}
+		words[key] = append(words[key], text)
+		total += conf
+		count++
+	}
+
+	lines := make([]string, 0, len(order))
+	for _, key := range order {
+		lines = append(lines, strings.Join(words[key], " "))
+	}
+	out := OCRText{Text: strings.Join(lines, "\n")}
+	if count > 0 {
+		out.Confidence = total / float64(count)
+	}
+	return out
+}
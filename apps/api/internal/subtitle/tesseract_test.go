@@ -0,0 +1,57 @@
+package subtitle
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTesseractTSV(t *testing.T) {
+	tsv := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+		"1\t1\t0\t0\t0\t0\t0\t0\t600\t120\t-1\t\n" +
+		"4\t1\t1\t1\t1\t0\t10\t10\t300\t40\t-1\t\n" +
+		"5\t1\t1\t1\t1\t1\t10\t10\t60\t40\t96.5\tWhere\n" +
+		"5\t1\t1\t1\t1\t2\t80\t10\t60\t40\t91.5\tare\n" +
+		"5\t1\t1\t1\t2\t1\t10\t60\t60\t40\t80\tyou?\n" +
+		"5\t1\t1\t1\t2\t2\t80\t60\t10\t40\t95\t \n"
+
+	got := parseTesseractTSV(tsv)
+
+	assert.Equal(t, "Where are\nyou?", got.Text)
+	assert.InDelta(t, 89.33, got.Confidence, 0.01, "blank words carry no confidence")
+}
+
+func TestParseTesseractTSV_Empty(t *testing.T) {
+	got := parseTesseractTSV("level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n")
+	assert.Empty(t, got.Text)
+	assert.Zero(t, got.Confidence)
+}
+
+func TestParseTesseractLangs(t *testing.T) {
+	out := "List of available languages in \"/usr/share/tessdata/\" (4):\nchi_sim\nchi_tra\neng\nosd\n"
+	assert.Equal(t, []string{"chi_sim", "chi_tra", "eng"}, parseTesseractLangs(out))
+}
+
+func TestNewTesseract_Defaults(t *testing.T) {
+	tess := NewTesseract(0, nil)
+	assert.Equal(t, defaultOCRTimeout, tess.timeout)
+	assert.Equal(t, 5*time.Second, NewTesseract(5*time.Second, nil).timeout)
+}
+
+// TestTesseract_Integration_BlankFrame runs the real binary when it is
+// installed (the Docker image) and is skipped otherwise.
+func TestTesseract_Integration_BlankFrame(t *testing.T) {
+	if _, err := exec.LookPath("tesseract"); err != nil {
+		t.Skip("tesseract not installed")
+	}
+	tess := NewTesseract(0, nil)
+	require.Contains(t, tess.Languages(), OCRModelEnglish)
+
+	got, err := tess.Recognize(context.Background(), newOCRCanvas(200, 40, 1).img, OCRModelEnglish)
+	require.NoError(t, err)
+	assert.Empty(t, cleanOCRText(got.Text, false))
+}
@@ -0,0 +1,200 @@
+package subtitle
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// WebVTT codec.
+//
+// A WebVTT file is a WEBVTT header followed by blank-line separated blocks:
+// cues (optional identifier line, a timing line with optional cue settings,
+// payload lines) and NOTE / STYLE / REGION blocks. Cue settings
+// (line:10% align:start) and payload markup (<i>, <c.yellow>, <v Speaker>)
+// are the styling; both survive a text rewrite the same way ASS overrides
+// do — settings are positional and kept, inline markup is kept when the text
+// comes back unchanged.
+
+// vttTimingPattern matches a cue timing line and captures any cue settings.
+var vttTimingPattern = regexp.MustCompile(`^((?:\d+:)?\d{2}:\d{2}\.\d{3})\s+-->\s+((?:\d+:)?\d{2}:\d{2}\.\d{3})(.*)$`)
+
+// vttTagPattern matches WebVTT payload markup, including timestamp tags.
+var vttTagPattern = regexp.MustCompile(`</?[a-zA-Z0-9.:_ -]*>|<\d{2}:[\d:.]+>`)
+
+// vttFile is the part of a WebVTT document the SRT model cannot carry.
+type vttFile struct {
+	// header is the WEBVTT line plus any header text and metadata lines.
+	header string
+	// items is every block after the header, in file order.
+	items []vttItem
+}
+
+// vttItem is either a verbatim block (NOTE, STYLE, REGION) or a cue.
+type vttItem struct {
+	raw string
+	cue *vttCue
+}
+
+type vttCue struct {
+	index    int
+	id       string
+	settings string // everything after the end timestamp, leading space included
+	rawText  string // payload as written, markup included
+	plain    string // payload with markup stripped and entities decoded
+}
+
+// ParseVTT parses a WebVTT file.
+func ParseVTT(content string) (*Document, error) {
+	content = strings.TrimPrefix(content, "\xEF\xBB\xBF")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+
+	chunks := splitBlankLineBlocks(content)
+	if len(chunks) == 0 || !strings.HasPrefix(chunks[0], "WEBVTT") {
+		return nil, fmt.Errorf("webvtt: missing WEBVTT header")
+	}
+
+	file := &vttFile{header: chunks[0]}
+	var blocks []SubtitleBlock
+	for _, chunk := range chunks[1:] {
+		lines := strings.Split(chunk, "\n")
+		timing := 0
+		if !vttTimingPattern.MatchString(lines[0]) {
+			timing = 1
+		}
+		if timing >= len(lines) || !vttTimingPattern.MatchString(lines[timing]) {
+			// NOTE, STYLE and REGION blocks, and anything unrecognized.
+			file.items = append(file.items, vttItem{raw: chunk})
+			continue
+		}
+
+		m := vttTimingPattern.FindStringSubmatch(lines[timing])
+		start, err := toSRTTime(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("webvtt: %w", err)
+		}
+		end, err := toSRTTime(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("webvtt: %w", err)
+		}
+
+		cue := &vttCue{
+			index:    len(blocks) + 1,
+			settings: m[3],
+			rawText:  strings.Join(lines[timing+1:], "\n"),
+		}
+		if timing == 1 {
+			cue.id = lines[0]
+		}
+		cue.plain = vttPlainText(cue.rawText)
+		if strings.TrimSpace(cue.plain) == "" {
+			file.items = append(file.items, vttItem{raw: chunk})
+			continue
+		}
+
+		blocks = append(blocks, SubtitleBlock{Index: cue.index, Start: start, End: end, Text: cue.plain})
+		file.items = append(file.items, vttItem{cue: cue})
+	}
+
+	return &Document{Format: CueFormatVTT, Blocks: blocks, vtt: file}, nil
+}
+
+// serialize writes the file back with blocks as the cues, matching by Index
+// exactly as assScript.serialize does.
+func (f *vttFile) serialize(blocks []SubtitleBlock) string {
+	byIndex := make(map[int]SubtitleBlock, len(blocks))
+	for _, b := range blocks {
+		byIndex[b.Index] = b
+	}
+
+	parts := []string{f.header}
+	seen := make(map[int]bool, len(blocks))
+	for _, item := range f.items {
+		if item.cue == nil {
+			parts = append(parts, item.raw)
+			continue
+		}
+		b, ok := byIndex[item.cue.index]
+		if !ok {
+			continue
+		}
+		seen[b.Index] = true
+		parts = append(parts, vttCueText(item.cue, b))
+	}
+	for _, b := range blocks {
+		if !seen[b.Index] {
+			parts = append(parts, vttCueText(nil, b))
+		}
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+func vttCueText(cue *vttCue, b SubtitleBlock) string {
+	var sb strings.Builder
+	start, end := b.Start, b.End
+	if d, err := ParseCueTime(start); err == nil {
+		start = formatVTTTime(d)
+	}
+	if d, err := ParseCueTime(end); err == nil {
+		end = formatVTTTime(d)
+	}
+
+	text := vttEncodeText(b.Text)
+	settings := ""
+	if cue != nil {
+		if cue.id != "" {
+			sb.WriteString(cue.id)
+			sb.WriteByte('\n')
+		}
+		settings = cue.settings
+		if b.Text == cue.plain {
+			text = cue.rawText
+		}
+	}
+	sb.WriteString(start + " --> " + end + settings)
+	sb.WriteByte('\n')
+	sb.WriteString(text)
+	return sb.String()
+}
+
+// vttEntities are the character references a WebVTT payload may carry.
+var vttEntities = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&nbsp;", "\u00a0", "&lrm;", "\u200e", "&rlm;", "\u200f")
+
+func vttPlainText(text string) string {
+	return strings.TrimSpace(vttEntities.Replace(vttTagPattern.ReplaceAllString(text, "")))
+}
+
+// vttEncodeText escapes the three characters a payload may not carry raw.
+// A blank line would end the cue, so empty lines are collapsed.
+func vttEncodeText(text string) string {
+	text = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	for _, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			kept = append(kept, l)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// splitBlankLineBlocks splits normalized content on runs of blank lines.
+func splitBlankLineBlocks(content string) []string {
+	var out []string
+	var cur []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(cur) > 0 {
+				out = append(out, strings.Join(cur, "\n"))
+				cur = nil
+			}
+			continue
+		}
+		cur = append(cur, line)
+	}
+	if len(cur) > 0 {
+		out = append(out, strings.Join(cur, "\n"))
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package subtitle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleVTT = `WEBVTT
+Kind: captions
+Language: en
+
+NOTE produced by the web encoder
+
+intro
+00:01.000 --> 00:04.000 line:10% align:start
+<v Ann>Hello &amp; welcome</v>
+
+00:00:05.500 --> 00:00:07.000
+<i>Second</i> line
+`
+
+func TestParseVTT_Cues(t *testing.T) {
+	doc, err := ParseVTT(sampleVTT)
+	require.NoError(t, err)
+
+	assert.Equal(t, CueFormatVTT, doc.Format)
+	require.Len(t, doc.Blocks, 2)
+	assert.Equal(t, SubtitleBlock{Index: 1, Start: "00:00:01,000", End: "00:00:04,000", Text: "Hello & welcome"}, doc.Blocks[0])
+	assert.Equal(t, SubtitleBlock{Index: 2, Start: "00:00:05,500", End: "00:00:07,000", Text: "Second line"}, doc.Blocks[1])
+}
+
+func TestVTT_TranslationKeepsHeaderIDsAndSettings(t *testing.T) {
+	doc, err := ParseVTT(sampleVTT)
+	require.NoError(t, err)
+
+	translated := append([]SubtitleBlock(nil), doc.Blocks...)
+	translated[0].Text = "你好 <歡迎>"
+	out := doc.WithBlocks(translated).Serialize()
+
+	assert.Equal(t, `WEBVTT
+Kind: captions
+Language: en
+
+NOTE produced by the web encoder
+
+intro
+00:00:01.000 --> 00:00:04.000 line:10% align:start
+你好 &lt;歡迎&gt;
+
+00:00:05.500 --> 00:00:07.000
+<i>Second</i> line
+`, out)
+}
+
+func TestParseVTT_RequiresHeader(t *testing.T) {
+	_, err := ParseVTT("00:01.000 --> 00:02.000\nhi\n")
+	assert.Error(t, err)
+}
@@ -173,6 +173,14 @@ func (m *MockMovieRepository) CountMissingZhHantSubtitle(ctx context.Context) (i
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockMovieRepository) FindAllWithFilePathByTMDbID(ctx context.Context, tmdbID int64) ([]models.Movie, error) {
+	args := m.Called(ctx, tmdbID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Movie), args.Error(1)
+}
+
 func (m *MockMovieRepository) FindAllWithFilePath(ctx context.Context) ([]models.Movie, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -393,6 +401,7 @@ func SetupDefaultMovieExpectations(m *MockMovieRepository) {
 	m.On("FindMissingZhHantSubtitle", mock.Anything).Maybe().Return([]models.Movie(nil), nil)
 	m.On("CountMissingZhHantSubtitle", mock.Anything).Maybe().Return(0, nil)
 	m.On("FindAllWithFilePath", mock.Anything).Maybe().Return([]models.Movie(nil), nil)
+	m.On("FindAllWithFilePathByTMDbID", mock.Anything, mock.Anything).Maybe().Return([]models.Movie(nil), nil)
 	m.On("GetStats", mock.Anything).Maybe().Return((*repository.MediaStats)(nil), nil)
 	m.On("FindOwnedTMDbIDs", mock.Anything, mock.Anything).Maybe().Return([]int64(nil), nil)
 	m.On("UpdateDoubanRating", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(nil)
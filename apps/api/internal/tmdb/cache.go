@@ -155,6 +155,10 @@ type CacheServiceInterface interface {
 	// TTL (Story 13-4b — ids are immutable-ish). Language-neutral: rides the
 	// raw providersClient like GetWatchProviders.
 	GetTVExternalIDs(ctx context.Context, tvID int) (*TVExternalIDs, error)
+	// GetCollection returns a movie collection and its parts cached at the
+	// default TTL. Rides the raw providersClient: the collection is only used
+	// for its member ids and display name.
+	GetCollection(ctx context.Context, collectionID int) (*CollectionDetails, error)
 }
 
 // Compile-time interface verification
@@ -413,6 +417,38 @@ func (s *CacheService) GetTVExternalIDs(ctx context.Context, tvID int) (*TVExter
 	return result, nil
 }
 
+// GetCollection returns a movie collection with caching.
+// Cache key: tmdb:collection/{id} — default TTL.
+func (s *CacheService) GetCollection(ctx context.Context, collectionID int) (*CollectionDetails, error) {
+	cacheKey := fmt.Sprintf("tmdb:collection/%d", collectionID)
+
+	cached, err := s.cache.Get(ctx, cacheKey)
+	if err == nil && cached != nil {
+		var result CollectionDetails
+		if err := json.Unmarshal([]byte(cached.Value), &result); err == nil {
+			slog.Debug("Cache hit", "key", cacheKey, "type", CacheTypeTMDb)
+			return &result, nil
+		}
+		slog.Warn("Failed to unmarshal cached data", "key", cacheKey, "error", err)
+	}
+
+	slog.Debug("Cache miss", "key", cacheKey, "type", CacheTypeTMDb)
+	if s.providersClient == nil {
+		return nil, fmt.Errorf("collection client not initialized")
+	}
+	result, err := s.providersClient.GetCollection(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		if err := s.cache.Set(ctx, cacheKey, string(data), CacheTypeTMDb, s.ttl); err != nil {
+			slog.Warn("Failed to cache TMDb response", "key", cacheKey, "error", err)
+		}
+	}
+	return result, nil
+}
+
 // GetTrendingMovies returns trending movies with caching (1-hour TTL).
 // Cache key format: tmdb:trending/movie:{window}:{page}
 func (s *CacheService) GetTrendingMovies(ctx context.Context, timeWindow string, page int) (*SearchResultMovies, error) {
@@ -1602,3 +1602,20 @@ func TestCacheService_GetTVExternalIDs_NilClient(t *testing.T) {
 	_, err := svc.GetTVExternalIDs(context.Background(), 1399)
 	require.Error(t, err, "must error (not panic) when the providers client is unset")
 }
+
+func TestCacheService_GetCollection_CacheMissThenHit(t *testing.T) {
+	repo := NewMockCacheRepository()
+	rawClient := &MockClient{}
+	svc := NewCacheService(&MockFallbackClient{}, repo, CacheServiceConfig{TTL: DefaultCacheTTL})
+	svc.SetProvidersClient(rawClient)
+
+	r1, err := svc.GetCollection(context.Background(), 1241)
+	require.NoError(t, err)
+	assert.Equal(t, 1241, r1.ID)
+	cached, _ := repo.Get(context.Background(), "tmdb:collection/1241")
+	require.NotNil(t, cached, "expected entry under tmdb:collection/1241")
+
+	_, err = svc.GetCollection(context.Background(), 1241)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rawClient.CollectionCalled, "cache hit must not call upstream again")
+}
@@ -63,6 +63,9 @@ type ClientInterface interface {
 	// GetTVExternalIDs retrieves a TV show's external-service ids (tvdb/imdb) —
 	// language-neutral (Story 13-4b, the Sonarr TVDB-resolution flow)
 	GetTVExternalIDs(ctx context.Context, tvID int) (*TVExternalIDs, error)
+	// GetCollection retrieves a movie collection and its parts in the client's
+	// default language
+	GetCollection(ctx context.Context, collectionID int) (*CollectionDetails, error)
 	// GetMovieRecommendations retrieves recommended movies for a movie
 	GetMovieRecommendations(ctx context.Context, movieID int) (*SearchResultMovies, error)
 	// GetMovieRecommendationsWithLanguage retrieves recommended movies with a specific language
@@ -45,6 +45,7 @@ type MockClient struct {
 	TVExternalIDsResponse *TVExternalIDs
 	TVExternalIDsError    error
 	TVExternalIDsCalled   int
+	CollectionCalled      int
 }
 
 func (m *MockClient) SearchMovies(ctx context.Context, query string, page int) (*SearchResultMovies, error) {
@@ -136,6 +137,11 @@ func (m *MockClient) GetTVExternalIDs(ctx context.Context, tvID int) (*TVExterna
 	return &TVExternalIDs{ID: int64(tvID)}, nil
 }
 
+func (m *MockClient) GetCollection(ctx context.Context, collectionID int) (*CollectionDetails, error) {
+	m.CollectionCalled++
+	return &CollectionDetails{ID: collectionID}, nil
+}
+
 func (m *MockClient) GetWatchProviders(ctx context.Context, mediaType string, id int, region string) (*WatchProvidersResponse, error) {
 	m.WatchProvidersCalled++
 	if m.WatchProvidersError != nil {
@@ -73,6 +73,28 @@ func (c *Client) GetMovieDetailsWithLanguage(ctx context.Context, movieID int, l
 	return &result, nil
 }
 
+// GetCollection retrieves a movie collection (franchise) and its parts. The
+// results will be in the language specified by the client; collections have
+// no fallback chain — a missing zh-TW name still carries the TMDb ids that
+// ownership is matched on.
+func (c *Client) GetCollection(ctx context.Context, collectionID int) (*CollectionDetails, error) {
+	if collectionID <= 0 {
+		return nil, NewBadRequestError("collection ID must be greater than 0")
+	}
+
+	endpoint := fmt.Sprintf("/collection/%d", collectionID)
+	queryParams := url.Values{
+		"language": []string{c.language},
+	}
+
+	var result CollectionDetails
+	if err := c.Get(ctx, endpoint, queryParams, &result); err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	return &result, nil
+}
+
 // FindByExternalID finds movies/TV shows using an external ID (e.g., IMDB ID).
 // externalSource should be "imdb_id", "tvdb_id", etc.
 func (c *Client) FindByExternalID(ctx context.Context, externalID string, externalSource string) (*FindByExternalIDResponse, error) {
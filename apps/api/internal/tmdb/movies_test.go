@@ -208,6 +208,41 @@ func TestClient_GetMovieDetails(t *testing.T) {
 	}
 }
 
+func TestClient_GetCollection(t *testing.T) {
+	t.Run("decodes the collection and the movie's reference to it", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/collection/1241":
+				assert.Equal(t, "zh-TW", r.URL.Query().Get("language"))
+				_, _ = w.Write([]byte(`{"id":1241,"name":"哈利波特（系列）","parts":[{"id":671,"title":"哈利波特：神秘的魔法石","release_date":"2001-11-16"}]}`))
+			case "/movie/671":
+				_, _ = w.Write([]byte(`{"id":671,"belongs_to_collection":{"id":1241,"name":"哈利波特（系列）","poster_path":"/hp.jpg"}}`))
+			default:
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+		}))
+		defer server.Close()
+
+		client := NewClient(ClientConfig{APIKey: "test-key", BaseURL: server.URL, Language: "zh-TW"})
+		collection, err := client.GetCollection(context.Background(), 1241)
+		require.NoError(t, err)
+		require.Len(t, collection.Parts, 1)
+		assert.Equal(t, 671, collection.Parts[0].ID)
+
+		details, err := client.GetMovieDetails(context.Background(), 671)
+		require.NoError(t, err)
+		require.NotNil(t, details.BelongsToCollection)
+		assert.Equal(t, 1241, details.BelongsToCollection.ID)
+	})
+
+	t.Run("invalid collection id", func(t *testing.T) {
+		client := NewClient(ClientConfig{APIKey: "test-key", BaseURL: "http://unused"})
+		_, err := client.GetCollection(context.Background(), 0)
+		assert.Error(t, err)
+	})
+}
+
 func TestClient_GetMovieDetailsWithLanguage(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Verify language parameter
@@ -34,6 +34,27 @@ type MovieDetails struct {
 	SpokenLanguages     []Language `json:"spoken_languages"`
 	ImdbID              string     `json:"imdb_id" example:"tt0137523"`
 	Homepage            *string    `json:"homepage"`
+	// BelongsToCollection is the franchise the movie is part of, nil for
+	// standalone titles.
+	BelongsToCollection *CollectionRef `json:"belongs_to_collection"`
+}
+
+// CollectionRef is the belongs_to_collection stub embedded in movie details.
+type CollectionRef struct {
+	ID           int     `json:"id" example:"1241"`
+	Name         string  `json:"name" example:"Harry Potter Collection"`
+	PosterPath   *string `json:"poster_path"`
+	BackdropPath *string `json:"backdrop_path"`
+}
+
+// CollectionDetails represents a TMDb collection with its member movies.
+type CollectionDetails struct {
+	ID           int     `json:"id" example:"1241"`
+	Name         string  `json:"name" example:"Harry Potter Collection"`
+	Overview     string  `json:"overview"`
+	PosterPath   *string `json:"poster_path"`
+	BackdropPath *string `json:"backdrop_path"`
+	Parts        []Movie `json:"parts"`
 }
 
 // TVShow represents a TV show from TMDb API
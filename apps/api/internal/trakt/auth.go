@@ -0,0 +1,130 @@
+package trakt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Device-token poll outcomes other than success. The caller keeps polling
+// on ErrAuthorizationPending, backs off on ErrSlowDown, and starts over on
+// the rest.
+var (
+	ErrAuthorizationPending = errors.New("trakt: waiting for the user to enter the code")
+	ErrSlowDown             = errors.New("trakt: polling too fast")
+	ErrDeviceCodeExpired    = errors.New("trakt: device code expired")
+	ErrAccessDenied         = errors.New("trakt: the user denied access")
+	ErrInvalidDeviceCode    = errors.New("trakt: invalid or already used device code")
+)
+
+// oobRedirect is the redirect URI of a device (PIN) application.
+const oobRedirect = "urn:ietf:wg:oauth:2.0:oob"
+
+// DeviceCode is what the user needs to authorize Vido: the code to type
+// at VerificationURL. Interval is the poll period in seconds.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURL string `json:"verification_url"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// Token is an access grant. ExpiresAt is derived from created_at and
+// expires_in.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	CreatedAt    int64  `json:"created_at"`
+}
+
+// ExpiresAt is when the access token stops working.
+func (t *Token) ExpiresAt() time.Time {
+	created := time.Unix(t.CreatedAt, 0)
+	if t.CreatedAt == 0 {
+		created = time.Now()
+	}
+	return created.Add(time.Duration(t.ExpiresIn) * time.Second).UTC()
+}
+
+// RequestDeviceCode starts the device flow.
+func (c *Client) RequestDeviceCode(ctx context.Context) (*DeviceCode, error) {
+	var code DeviceCode
+	if err := c.do(ctx, http.MethodPost, "/oauth/device/code", map[string]string{"client_id": c.config.ClientID}, &code); err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// PollDeviceToken checks once whether the user has entered the code.
+func (c *Client) PollDeviceToken(ctx context.Context, deviceCode string) (*Token, error) {
+	var token Token
+	err := c.do(ctx, http.MethodPost, "/oauth/device/token", map[string]string{
+		"code":          deviceCode,
+		"client_id":     c.config.ClientID,
+		"client_secret": c.config.ClientSecret,
+	}, &token)
+
+	var traktErr *Error
+	if errors.As(err, &traktErr) {
+		switch traktErr.StatusCode {
+		case http.StatusBadRequest:
+			return nil, ErrAuthorizationPending
+		case http.StatusTooManyRequests:
+			return nil, ErrSlowDown
+		case http.StatusGone:
+			return nil, ErrDeviceCodeExpired
+		case http.StatusTeapot:
+			return nil, ErrAccessDenied
+		case http.StatusNotFound, http.StatusConflict:
+			return nil, ErrInvalidDeviceCode
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RefreshToken exchanges a refresh token for a new grant.
+func (c *Client) RefreshToken(ctx context.Context, refreshToken string) (*Token, error) {
+	var token Token
+	if err := c.do(ctx, http.MethodPost, "/oauth/token", map[string]string{
+		"refresh_token": refreshToken,
+		"client_id":     c.config.ClientID,
+		"client_secret": c.config.ClientSecret,
+		"redirect_uri":  oobRedirect,
+		"grant_type":    "refresh_token",
+	}, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeToken signs Vido out of the user's account.
+func (c *Client) RevokeToken(ctx context.Context, accessToken string) error {
+	return c.do(ctx, http.MethodPost, "/oauth/revoke", map[string]string{
+		"token":         accessToken,
+		"client_id":     c.config.ClientID,
+		"client_secret": c.config.ClientSecret,
+	}, nil)
+}
+
+// Settings is the signed-in user, as shown on the settings page.
+type Settings struct {
+	User struct {
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	} `json:"user"`
+}
+
+// GetSettings returns the signed-in user; it doubles as a token check.
+func (c *Client) GetSettings(ctx context.Context) (*Settings, error) {
+	var settings Settings
+	if err := c.do(ctx, http.MethodGet, "/users/settings", nil, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
@@ -0,0 +1,146 @@
+// Package trakt is a client for the Trakt.tv API: the device-code OAuth
+// flow, the /sync endpoints for history, ratings, watchlist and collection,
+// and scrobbling. Items are addressed by their TMDb IDs, which is what Vido
+// stores on movies and series.
+//
+// Reference: https://trakt.docs.apiary.io
+package trakt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the production API.
+const DefaultBaseURL = "https://api.trakt.tv"
+
+// apiVersion is sent as the trakt-api-version header on every call.
+const apiVersion = "2"
+
+// Error codes following project convention: SOURCE_ERROR_TYPE.
+const (
+	ErrCodeNotConfigured = "TRAKT_NOT_CONFIGURED"
+	ErrCodeUnauthorized  = "TRAKT_UNAUTHORIZED"
+	ErrCodeRateLimited   = "TRAKT_RATE_LIMIT"
+	ErrCodeRequestFailed = "TRAKT_REQUEST_FAILED"
+	ErrCodeUnavailable   = "TRAKT_UNAVAILABLE"
+)
+
+// Error is a failed Trakt call.
+type Error struct {
+	Code       string
+	Message    string
+	StatusCode int
+	Cause      error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s (cause: %v)", e.Code, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error { return e.Cause }
+
+// IsUnauthorized reports whether err means the access token was rejected:
+// it expired or the user revoked Vido on trakt.tv.
+func IsUnauthorized(err error) bool {
+	var traktErr *Error
+	return errors.As(err, &traktErr) && traktErr.Code == ErrCodeUnauthorized
+}
+
+// Config holds the API application and the user's access token. The token
+// may be empty for the device-code calls that obtain one.
+type Config struct {
+	BaseURL      string
+	ClientID     string
+	ClientSecret string
+	AccessToken  string
+	Timeout      time.Duration
+}
+
+// Client talks to Trakt for one user. It is safe for concurrent use.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewClient creates a Client. The default timeout is 15 seconds; the sync
+// lists of a long-time user are large.
+func NewClient(config Config) *Client {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 15 * time.Second
+	}
+	return &Client{config: config, httpClient: &http.Client{Timeout: timeout}}
+}
+
+// do sends one call and decodes a 2xx body into out (when not nil). Other
+// statuses come back as *Error; the device-token poll reads them itself
+// through statusErr.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	if c.config.ClientID == "" {
+		return &Error{Code: ErrCodeNotConfigured, Message: "Trakt client ID is not set"}
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("trakt %s: %w", path, err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.config.BaseURL+path, reader)
+	if err != nil {
+		return &Error{Code: ErrCodeRequestFailed, Message: "failed to create request", Cause: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("trakt-api-version", apiVersion)
+	req.Header.Set("trakt-api-key", c.config.ClientID)
+	if c.config.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &Error{Code: ErrCodeUnavailable, Message: "cannot reach Trakt", Cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return statusErr(path, resp.StatusCode)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<20)).Decode(out); err != nil {
+		return &Error{Code: ErrCodeRequestFailed, Message: "failed to decode Trakt response", Cause: err}
+	}
+	return nil
+}
+
+func statusErr(path string, status int) *Error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return &Error{Code: ErrCodeUnauthorized, Message: "Trakt rejected the access token", StatusCode: status}
+	case status == http.StatusTooManyRequests:
+		return &Error{Code: ErrCodeRateLimited, Message: "Trakt rate limit exceeded", StatusCode: status}
+	case status >= 500:
+		return &Error{Code: ErrCodeUnavailable, Message: fmt.Sprintf("Trakt is unavailable (status %d)", status), StatusCode: status}
+	default:
+		return &Error{Code: ErrCodeRequestFailed, Message: fmt.Sprintf("trakt %s failed with status %d", path, status), StatusCode: status}
+	}
+}
@@ -0,0 +1,116 @@
+package trakt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStubClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClient(Config{BaseURL: server.URL, ClientID: "cid", ClientSecret: "secret", AccessToken: "tok"})
+}
+
+func TestClient_Headers(t *testing.T) {
+	client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.Header.Get("trakt-api-version"))
+		assert.Equal(t, "cid", r.Header.Get("trakt-api-key"))
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"user":{"username":"sean","name":"Sean"}}`)
+	})
+
+	settings, err := client.GetSettings(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "sean", settings.User.Username)
+
+	client.config.AccessToken = "revoked"
+	_, err = client.GetSettings(context.Background())
+	assert.True(t, IsUnauthorized(err))
+
+	_, err = NewClient(Config{}).GetSettings(context.Background())
+	var traktErr *Error
+	require.ErrorAs(t, err, &traktErr)
+	assert.Equal(t, ErrCodeNotConfigured, traktErr.Code)
+}
+
+func TestClient_PollDeviceToken(t *testing.T) {
+	statuses := map[string]int{
+		"pending": http.StatusBadRequest,
+		"slow":    http.StatusTooManyRequests,
+		"expired": http.StatusGone,
+		"denied":  http.StatusTeapot,
+		"used":    http.StatusConflict,
+	}
+	client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/oauth/device/token", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "secret", body["client_secret"])
+		if status, ok := statuses[body["code"]]; ok {
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"a","refresh_token":"r","expires_in":86400,"created_at":1700000000}`)
+	})
+	ctx := context.Background()
+
+	for code, want := range map[string]error{
+		"pending": ErrAuthorizationPending,
+		"slow":    ErrSlowDown,
+		"expired": ErrDeviceCodeExpired,
+		"denied":  ErrAccessDenied,
+		"used":    ErrInvalidDeviceCode,
+	} {
+		_, err := client.PollDeviceToken(ctx, code)
+		assert.ErrorIs(t, err, want, code)
+	}
+
+	token, err := client.PollDeviceToken(ctx, "ok")
+	require.NoError(t, err)
+	assert.Equal(t, "a", token.AccessToken)
+	assert.Equal(t, int64(1700086400), token.ExpiresAt().Unix())
+}
+
+func TestClient_WatchlistAndScrobble(t *testing.T) {
+	var scrobbled map[string]any
+	client := newStubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sync/watchlist/movies":
+			fmt.Fprint(w, `[{"listed_at":"2026-01-02T03:04:05.000Z","type":"movie","movie":{"title":"Dune","year":2021,"ids":{"trakt":1,"tmdb":438631}}}]`)
+		case "/sync/watchlist/shows":
+			fmt.Fprint(w, `[{"listed_at":"2026-01-02T03:04:05.000Z","type":"show","show":{"title":"Severance","ids":{"tmdb":95396}}}]`)
+		case "/scrobble/stop":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&scrobbled))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+
+	items, err := client.GetWatchlist(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, int64(438631), items[0].Movie.IDs.TMDb)
+	assert.Equal(t, int64(95396), items[1].Show.IDs.TMDb)
+
+	require.NoError(t, client.Scrobble(ctx, ScrobbleStop, ScrobbleItem{
+		Show:     &Media{IDs: IDs{TMDb: 95396}},
+		Episode:  &ScrobbleEpisode{Season: 1, Number: 2},
+		Progress: 95,
+	}))
+	assert.Equal(t, map[string]any{"season": float64(1), "number": float64(2)}, scrobbled["episode"])
+	assert.Equal(t, 95.0, scrobbled["progress"])
+}
@@ -0,0 +1,200 @@
+package trakt
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// IDs identifies an item. Vido only sends and reads TMDb; Trakt and IMDb are
+// decoded for logging.
+type IDs struct {
+	Trakt int64  `json:"trakt,omitempty"`
+	TMDb  int64  `json:"tmdb,omitempty"`
+	IMDb  string `json:"imdb,omitempty"`
+}
+
+// Media is a movie or a show as Trakt lists it.
+type Media struct {
+	Title string `json:"title,omitempty"`
+	Year  int    `json:"year,omitempty"`
+	IDs   IDs    `json:"ids"`
+}
+
+// WatchedMovie is one movie of the user's watched list.
+type WatchedMovie struct {
+	Plays         int       `json:"plays"`
+	LastWatchedAt time.Time `json:"last_watched_at"`
+	Movie         Media     `json:"movie"`
+}
+
+// WatchedEpisode is one episode of a watched show.
+type WatchedEpisode struct {
+	Number        int       `json:"number"`
+	Plays         int       `json:"plays"`
+	LastWatchedAt time.Time `json:"last_watched_at"`
+}
+
+// WatchedSeason groups a watched show's episodes.
+type WatchedSeason struct {
+	Number   int              `json:"number"`
+	Episodes []WatchedEpisode `json:"episodes"`
+}
+
+// WatchedShow is one show of the user's watched list, with every episode
+// seen at least once.
+type WatchedShow struct {
+	Plays         int             `json:"plays"`
+	LastWatchedAt time.Time       `json:"last_watched_at"`
+	Show          Media           `json:"show"`
+	Seasons       []WatchedSeason `json:"seasons"`
+}
+
+// Rating is one of the user's ratings; exactly one of Movie and Show is set.
+type Rating struct {
+	RatedAt time.Time `json:"rated_at"`
+	Rating  int       `json:"rating"`
+	Type    string    `json:"type"`
+	Movie   *Media    `json:"movie,omitempty"`
+	Show    *Media    `json:"show,omitempty"`
+}
+
+// WatchlistItem is one watchlist entry; exactly one of Movie and Show is set.
+type WatchlistItem struct {
+	ListedAt time.Time `json:"listed_at"`
+	Type     string    `json:"type"`
+	Movie    *Media    `json:"movie,omitempty"`
+	Show     *Media    `json:"show,omitempty"`
+}
+
+// SyncEpisode, SyncSeason, SyncShow and SyncMovie address items in the
+// bodies of the /sync write calls.
+type SyncEpisode struct {
+	Number int `json:"number"`
+}
+
+type SyncSeason struct {
+	Number   int           `json:"number"`
+	Episodes []SyncEpisode `json:"episodes,omitempty"`
+}
+
+type SyncShow struct {
+	IDs     IDs          `json:"ids"`
+	Seasons []SyncSeason `json:"seasons,omitempty"`
+}
+
+type SyncMovie struct {
+	IDs         IDs        `json:"ids"`
+	CollectedAt *time.Time `json:"collected_at,omitempty"`
+}
+
+// SyncItems is the body of a /sync write call.
+type SyncItems struct {
+	Movies []SyncMovie `json:"movies,omitempty"`
+	Shows  []SyncShow  `json:"shows,omitempty"`
+}
+
+// SyncCounts is how many items of each kind a write call touched.
+type SyncCounts struct {
+	Movies   int `json:"movies"`
+	Shows    int `json:"shows"`
+	Seasons  int `json:"seasons"`
+	Episodes int `json:"episodes"`
+}
+
+// SyncResult is Trakt's answer to a /sync write call.
+type SyncResult struct {
+	Added    SyncCounts `json:"added"`
+	Deleted  SyncCounts `json:"deleted"`
+	Existing SyncCounts `json:"existing"`
+}
+
+// GetWatchedMovies returns every movie the user has watched.
+func (c *Client) GetWatchedMovies(ctx context.Context) ([]WatchedMovie, error) {
+	var movies []WatchedMovie
+	err := c.do(ctx, http.MethodGet, "/sync/watched/movies", nil, &movies)
+	return movies, err
+}
+
+// GetWatchedShows returns every show the user has watched an episode of.
+func (c *Client) GetWatchedShows(ctx context.Context) ([]WatchedShow, error) {
+	var shows []WatchedShow
+	err := c.do(ctx, http.MethodGet, "/sync/watched/shows", nil, &shows)
+	return shows, err
+}
+
+// GetRatings returns the user's movie and show ratings.
+func (c *Client) GetRatings(ctx context.Context) ([]Rating, error) {
+	var movies, shows []Rating
+	if err := c.do(ctx, http.MethodGet, "/sync/ratings/movies", nil, &movies); err != nil {
+		return nil, err
+	}
+	if err := c.do(ctx, http.MethodGet, "/sync/ratings/shows", nil, &shows); err != nil {
+		return nil, err
+	}
+	return append(movies, shows...), nil
+}
+
+// GetWatchlist returns the user's movie and show watchlist.
+func (c *Client) GetWatchlist(ctx context.Context) ([]WatchlistItem, error) {
+	var movies, shows []WatchlistItem
+	if err := c.do(ctx, http.MethodGet, "/sync/watchlist/movies", nil, &movies); err != nil {
+		return nil, err
+	}
+	if err := c.do(ctx, http.MethodGet, "/sync/watchlist/shows", nil, &shows); err != nil {
+		return nil, err
+	}
+	return append(movies, shows...), nil
+}
+
+// AddToWatchlist adds items to the user's watchlist.
+func (c *Client) AddToWatchlist(ctx context.Context, items SyncItems) (*SyncResult, error) {
+	return c.write(ctx, "/sync/watchlist", items)
+}
+
+// RemoveFromWatchlist removes items from the user's watchlist.
+func (c *Client) RemoveFromWatchlist(ctx context.Context, items SyncItems) (*SyncResult, error) {
+	return c.write(ctx, "/sync/watchlist/remove", items)
+}
+
+// AddToCollection marks items as collected. A show lists the episodes that
+// are on disk.
+func (c *Client) AddToCollection(ctx context.Context, items SyncItems) (*SyncResult, error) {
+	return c.write(ctx, "/sync/collection", items)
+}
+
+func (c *Client) write(ctx context.Context, path string, items SyncItems) (*SyncResult, error) {
+	var result SyncResult
+	if err := c.do(ctx, http.MethodPost, path, items, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Scrobble actions.
+const (
+	ScrobbleStart = "start"
+	ScrobblePause = "pause"
+	ScrobbleStop  = "stop"
+)
+
+// ScrobbleItem is what is playing: a movie, or an episode addressed by its
+// show and numbers. Progress is a percentage.
+type ScrobbleItem struct {
+	Movie    *Media           `json:"movie,omitempty"`
+	Show     *Media           `json:"show,omitempty"`
+	Episode  *ScrobbleEpisode `json:"episode,omitempty"`
+	Progress float64          `json:"progress"`
+}
+
+// ScrobbleEpisode addresses an episode within ScrobbleItem.Show.
+type ScrobbleEpisode struct {
+	Season int `json:"season"`
+	Number int `json:"number"`
+}
+
+// Scrobble reports playback. Trakt marks the item watched when a stop
+// arrives at 80% or more.
+func (c *Client) Scrobble(ctx context.Context, action string, item ScrobbleItem) error {
+	return c.do(ctx, http.MethodPost, "/scrobble/"+action, item, nil)
+}
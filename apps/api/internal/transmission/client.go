@@ -0,0 +1,264 @@
+// Package transmission is a Transmission RPC client that speaks the
+// download subsystem's torrent model (qbittorrent.Torrent), so Vido can use
+// Transmission in place of qBittorrent.
+//
+// Reference: https://github.com/transmission/transmission/blob/main/docs/rpc-spec.md
+package transmission
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vido/api/internal/qbittorrent"
+)
+
+// DefaultBasePath is where Transmission serves RPC unless rpc-url was
+// changed.
+const DefaultBasePath = "/transmission/rpc"
+
+// sessionHeader carries Transmission's CSRF token. The first request of a
+// session is answered 409 with the token to use.
+const sessionHeader = "X-Transmission-Session-Id"
+
+// Config holds a Transmission connection. Username and Password are the
+// RPC credentials (basic auth); both may be empty when authentication is
+// off.
+type Config struct {
+	Host     string
+	Username string
+	Password string
+	BasePath string
+	Timeout  time.Duration
+}
+
+// Client talks to one Transmission daemon. It is safe for concurrent use.
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+// NewClient creates a Client. The default timeout is 10 seconds, as for
+// qBittorrent.
+func NewClient(config *Config) *Client {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	return &Client{config: config, httpClient: &http.Client{Timeout: timeout}}
+}
+
+func (c *Client) rpcURL() string {
+	basePath := strings.TrimSuffix(c.config.BasePath, "/")
+	if basePath == "" {
+		basePath = DefaultBasePath
+	}
+	return strings.TrimSuffix(c.config.Host, "/") + basePath
+}
+
+type rpcRequest struct {
+	Method    string `json:"method"`
+	Arguments any    `json:"arguments,omitempty"`
+}
+
+type rpcResponse struct {
+	Result    string          `json:"result"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// call runs one RPC method and decodes its arguments into out (when not
+// nil), fetching a fresh session id once if Transmission asks for one.
+func (c *Client) call(ctx context.Context, method string, args, out any) error {
+	body, err := json.Marshal(rpcRequest{Method: method, Arguments: args})
+	if err != nil {
+		return fmt.Errorf("transmission %s: %w", method, err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.rpcURL(), bytes.NewReader(body))
+		if err != nil {
+			return &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeConnectionFailed, Message: "failed to create request", Cause: err}
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if c.config.Username != "" || c.config.Password != "" {
+			req.SetBasicAuth(c.config.Username, c.config.Password)
+		}
+		c.mu.Lock()
+		if c.sessionID != "" {
+			req.Header.Set(sessionHeader, c.sessionID)
+		}
+		c.mu.Unlock()
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			code := qbittorrent.ErrCodeConnectionFailed
+			if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "Client.Timeout") {
+				code = qbittorrent.ErrCodeTimeout
+			}
+			return &qbittorrent.ConnectionError{Code: code, Message: "cannot reach Transmission", Cause: err}
+		}
+
+		if resp.StatusCode == http.StatusConflict && attempt == 0 {
+			c.mu.Lock()
+			c.sessionID = resp.Header.Get(sessionHeader)
+			c.mu.Unlock()
+			resp.Body.Close()
+			continue
+		}
+		defer resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			return &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeAuthFailed, Message: "Transmission rejected the credentials"}
+		case resp.StatusCode != http.StatusOK:
+			return &qbittorrent.ConnectionError{
+				Code:    qbittorrent.ErrCodeConnectionFailed,
+				Message: fmt.Sprintf("transmission %s failed with status %d", method, resp.StatusCode),
+			}
+		}
+
+		var rpcResp rpcResponse
+		if err := json.NewDecoder(io.LimitReader(resp.Body, 32<<20)).Decode(&rpcResp); err != nil {
+			return &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeConnectionFailed, Message: "failed to decode Transmission response", Cause: err}
+		}
+		if rpcResp.Result != "success" {
+			return &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeRequestRejected, Message: "Transmission: " + rpcResp.Result}
+		}
+		if out != nil {
+			if err := json.Unmarshal(rpcResp.Arguments, out); err != nil {
+				return &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeConnectionFailed, Message: "failed to decode Transmission arguments", Cause: err}
+			}
+		}
+		return nil
+	}
+}
+
+// Ping checks Transmission is reachable with the configured credentials.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.TestConnection(ctx)
+	return err
+}
+
+// TestConnection returns the daemon version; APIVersion is the RPC version.
+func (c *Client) TestConnection(ctx context.Context) (*qbittorrent.VersionInfo, error) {
+	var session struct {
+		Version    string `json:"version"`
+		RPCVersion int    `json:"rpc-version"`
+	}
+	if err := c.call(ctx, "session-get", map[string]any{"fields": []string{"version", "rpc-version"}}, &session); err != nil {
+		return nil, err
+	}
+	return &qbittorrent.VersionInfo{AppVersion: session.Version, APIVersion: fmt.Sprint(session.RPCVersion)}, nil
+}
+
+// torrentFields are requested by every torrent-get.
+var torrentFields = []string{
+	"hashString", "name", "totalSize", "percentDone", "rateDownload", "rateUpload",
+	"eta", "status", "error", "addedDate", "doneDate", "peersSendingToUs",
+	"peersGettingFromUs", "downloadedEver", "uploadedEver", "uploadRatio",
+	"downloadDir", "labels", "pieceSize", "comment", "creator", "dateCreated",
+	"corruptEver", "secondsDownloading", "secondsSeeding",
+}
+
+func (c *Client) getTorrents(ctx context.Context, hashes []string) ([]trTorrent, error) {
+	args := map[string]any{"fields": torrentFields}
+	if hashes != nil {
+		args["ids"] = hashes
+	}
+	var out struct {
+		Torrents []trTorrent `json:"torrents"`
+	}
+	if err := c.call(ctx, "torrent-get", args, &out); err != nil {
+		return nil, err
+	}
+	return out.Torrents, nil
+}
+
+// GetTorrents lists torrents, filtered and sorted client-side.
+func (c *Client) GetTorrents(ctx context.Context, opts *qbittorrent.ListTorrentsOptions) ([]qbittorrent.Torrent, error) {
+	raw, err := c.getTorrents(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	torrents := make([]qbittorrent.Torrent, len(raw))
+	for i, t := range raw {
+		torrents[i] = t.toTorrent()
+	}
+	return qbittorrent.ApplyListOptions(torrents, opts), nil
+}
+
+// GetTorrentDetails returns one torrent.
+func (c *Client) GetTorrentDetails(ctx context.Context, hash string) (*qbittorrent.TorrentDetails, error) {
+	raw, err := c.getTorrents(ctx, []string{hash})
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, &qbittorrent.ConnectionError{Code: qbittorrent.ErrCodeTorrentNotFound, Message: "torrent not found: " + hash}
+	}
+	return raw[0].toDetails(), nil
+}
+
+// PauseTorrents stops torrents.
+func (c *Client) PauseTorrents(ctx context.Context, hashes []string) error {
+	return c.call(ctx, "torrent-stop", map[string]any{"ids": hashes}, nil)
+}
+
+// ResumeTorrents starts torrents.
+func (c *Client) ResumeTorrents(ctx context.Context, hashes []string) error {
+	return c.call(ctx, "torrent-start", map[string]any{"ids": hashes}, nil)
+}
+
+// DeleteTorrents removes torrents, with their data when deleteFiles is set.
+func (c *Client) DeleteTorrents(ctx context.Context, hashes []string, deleteFiles bool) error {
+	return c.call(ctx, "torrent-remove", map[string]any{"ids": hashes, "delete-local-data": deleteFiles}, nil)
+}
+
+// AddTorrent adds each URL and the torrent file, if any. Transmission has no
+// categories; the category and tags become labels, which it reports back as
+// the torrent's tags.
+func (c *Client) AddTorrent(ctx context.Context, opts qbittorrent.AddTorrentOptions) error {
+	if len(opts.URLs) == 0 && len(opts.Torrent) == 0 {
+		return fmt.Errorf("add torrent: no URL or torrent file given")
+	}
+	base := map[string]any{"paused": opts.Paused}
+	if opts.SavePath != "" {
+		base["download-dir"] = opts.SavePath
+	}
+	var labels []string
+	if opts.Category != "" {
+		labels = append(labels, opts.Category)
+	}
+	labels = append(labels, opts.Tags...)
+	if len(labels) > 0 {
+		base["labels"] = labels
+	}
+
+	add := func(key, value string) error {
+		args := map[string]any{key: value}
+		for k, v := range base {
+			args[k] = v
+		}
+		return c.call(ctx, "torrent-add", args, nil)
+	}
+	for _, u := range opts.URLs {
+		if err := add("filename", u); err != nil {
+			return err
+		}
+	}
+	if len(opts.Torrent) > 0 {
+		return add("metainfo", base64.StdEncoding.EncodeToString(opts.Torrent))
+	}
+	return nil
+}
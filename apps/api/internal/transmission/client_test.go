@@ -0,0 +1,142 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vido/api/internal/qbittorrent"
+)
+
+// fakeTransmission enforces the session-id handshake and basic auth, and
+// records every call.
+type fakeTransmission struct {
+	calls    []rpcCall
+	torrents string
+}
+
+type rpcCall struct {
+	Method    string         `json:"method"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+func (f *fakeTransmission) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != DefaultBasePath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get(sessionHeader) != "s1" {
+		w.Header().Set(sessionHeader, "s1")
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	var call rpcCall
+	_ = json.NewDecoder(r.Body).Decode(&call)
+	f.calls = append(f.calls, call)
+	switch call.Method {
+	case "session-get":
+		fmt.Fprint(w, `{"result":"success","arguments":{"version":"4.0.5 (a6fe2a64aa)","rpc-version":17}}`)
+	case "torrent-get":
+		fmt.Fprintf(w, `{"result":"success","arguments":{"torrents":%s}}`, f.torrents)
+	case "torrent-add":
+		if call.Arguments["filename"] == "magnet:?bad" {
+			fmt.Fprint(w, `{"result":"invalid or corrupt torrent file","arguments":{}}`)
+			return
+		}
+		fmt.Fprint(w, `{"result":"success","arguments":{"torrent-added":{}}}`)
+	default:
+		fmt.Fprint(w, `{"result":"success","arguments":{}}`)
+	}
+}
+
+func newFakeClient(t *testing.T, password string) (*Client, *fakeTransmission) {
+	fake := &fakeTransmission{torrents: `[]`}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return NewClient(&Config{Host: server.URL, Username: "admin", Password: password}), fake
+}
+
+func TestClient_TestConnection(t *testing.T) {
+	client, _ := newFakeClient(t, "secret")
+	info, err := client.TestConnection(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "4.0.5 (a6fe2a64aa)", info.AppVersion)
+	assert.Equal(t, "17", info.APIVersion)
+
+	t.Run("wrong password", func(t *testing.T) {
+		client, _ := newFakeClient(t, "nope")
+		_, err := client.TestConnection(context.Background())
+		var connErr *qbittorrent.ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Equal(t, qbittorrent.ErrCodeAuthFailed, connErr.Code)
+	})
+}
+
+func TestClient_GetTorrents(t *testing.T) {
+	client, fake := newFakeClient(t, "secret")
+	fake.torrents = `[
+		{"hashString":"AAAA","name":"Show.S01E01","totalSize":100,"percentDone":0.5,"rateDownload":10,"peersSendingToUs":2,"status":4,"addedDate":1700000000,"labels":["vido-request:r1"]},
+		{"hashString":"bbbb","name":"Movie","percentDone":1,"status":0,"doneDate":1700000500,"uploadRatio":-1},
+		{"hashString":"cccc","name":"Broken","percentDone":0.1,"status":4,"error":3}
+	]`
+
+	torrents, err := client.GetTorrents(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, torrents, 3)
+	assert.Equal(t, "aaaa", torrents[0].Hash)
+	assert.Equal(t, qbittorrent.StatusDownloading, torrents[0].Status)
+	assert.Equal(t, []string{"vido-request:r1"}, torrents[0].Tags)
+	assert.Equal(t, qbittorrent.StatusCompleted, torrents[1].Status)
+	require.NotNil(t, torrents[1].CompletedOn)
+	assert.Zero(t, torrents[1].Ratio)
+	assert.Equal(t, qbittorrent.StatusError, torrents[2].Status)
+
+	downloading, err := client.GetTorrents(context.Background(), &qbittorrent.ListTorrentsOptions{Filter: qbittorrent.FilterDownloading})
+	require.NoError(t, err)
+	assert.Len(t, downloading, 1)
+
+	t.Run("unknown hash is not found", func(t *testing.T) {
+		fake.torrents = `[]`
+		_, err := client.GetTorrentDetails(context.Background(), "dddd")
+		var connErr *qbittorrent.ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Equal(t, qbittorrent.ErrCodeTorrentNotFound, connErr.Code)
+	})
+}
+
+func TestClient_Actions(t *testing.T) {
+	client, fake := newFakeClient(t, "secret")
+	ctx := context.Background()
+
+	require.NoError(t, client.PauseTorrents(ctx, []string{"aaaa"}))
+	require.NoError(t, client.DeleteTorrents(ctx, []string{"aaaa"}, true))
+	require.NoError(t, client.AddTorrent(ctx, qbittorrent.AddTorrentOptions{
+		URLs: []string{"magnet:?xt=urn:btih:aaaa"}, Torrent: []byte("d4:infoe"),
+		SavePath: "/data", Category: "vido", Tags: []string{"vido-request:r1"}, Paused: true,
+	}))
+
+	require.Len(t, fake.calls, 4)
+	assert.Equal(t, "torrent-stop", fake.calls[0].Method)
+	assert.Equal(t, true, fake.calls[1].Arguments["delete-local-data"])
+	assert.Equal(t, "magnet:?xt=urn:btih:aaaa", fake.calls[2].Arguments["filename"])
+	assert.Equal(t, []any{"vido", "vido-request:r1"}, fake.calls[2].Arguments["labels"])
+	assert.Equal(t, "/data", fake.calls[2].Arguments["download-dir"])
+	assert.Equal(t, "ZDQ6aW5mb2U=", fake.calls[3].Arguments["metainfo"])
+
+	t.Run("rejected add", func(t *testing.T) {
+		err := client.AddTorrent(ctx, qbittorrent.AddTorrentOptions{URLs: []string{"magnet:?bad"}})
+		var connErr *qbittorrent.ConnectionError
+		require.ErrorAs(t, err, &connErr)
+		assert.Equal(t, qbittorrent.ErrCodeRequestRejected, connErr.Code)
+	})
+}
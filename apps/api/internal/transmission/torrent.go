@@ -0,0 +1,125 @@
+package transmission
+
+import (
+	"strings"
+	"time"
+
+	"github.com/vido/api/internal/qbittorrent"
+)
+
+// Transmission torrent status values (tr_torrent_activity).
+const (
+	statusStopped      = 0
+	statusCheckWait    = 1
+	statusCheck        = 2
+	statusDownloadWait = 3
+	statusDownload     = 4
+	statusSeedWait     = 5
+	statusSeed         = 6
+)
+
+// errorLocal is tr_stat.error for a local failure (disk full, missing
+// files). 1 and 2 are tracker warnings and errors, which do not stop the
+// torrent.
+const errorLocal = 3
+
+// trTorrent is one torrent-get entry.
+type trTorrent struct {
+	HashString         string   `json:"hashString"`
+	Name               string   `json:"name"`
+	TotalSize          int64    `json:"totalSize"`
+	PercentDone        float64  `json:"percentDone"`
+	RateDownload       int64    `json:"rateDownload"`
+	RateUpload         int64    `json:"rateUpload"`
+	ETA                int64    `json:"eta"`
+	Status             int      `json:"status"`
+	Error              int      `json:"error"`
+	AddedDate          int64    `json:"addedDate"`
+	DoneDate           int64    `json:"doneDate"`
+	PeersSendingToUs   int      `json:"peersSendingToUs"`
+	PeersGettingFromUs int      `json:"peersGettingFromUs"`
+	DownloadedEver     int64    `json:"downloadedEver"`
+	UploadedEver       int64    `json:"uploadedEver"`
+	UploadRatio        float64  `json:"uploadRatio"`
+	DownloadDir        string   `json:"downloadDir"`
+	Labels             []string `json:"labels"`
+	PieceSize          int64    `json:"pieceSize"`
+	Comment            string   `json:"comment"`
+	Creator            string   `json:"creator"`
+	DateCreated        int64    `json:"dateCreated"`
+	CorruptEver        int64    `json:"corruptEver"`
+	SecondsDownloading int64    `json:"secondsDownloading"`
+	SecondsSeeding     int64    `json:"secondsSeeding"`
+}
+
+// mapStatus normalises Transmission's activity to the shared statuses. A
+// stopped torrent is "paused" until it has all its data and "completed"
+// after, matching qBittorrent's pausedDL/pausedUP split.
+func mapStatus(t trTorrent) qbittorrent.TorrentStatus {
+	if t.Error == errorLocal {
+		return qbittorrent.StatusError
+	}
+	switch t.Status {
+	case statusStopped:
+		if t.PercentDone >= 1 {
+			return qbittorrent.StatusCompleted
+		}
+		return qbittorrent.StatusPaused
+	case statusCheckWait, statusCheck:
+		return qbittorrent.StatusChecking
+	case statusDownloadWait, statusSeedWait:
+		return qbittorrent.StatusQueued
+	case statusDownload:
+		if t.PeersSendingToUs == 0 && t.RateDownload == 0 {
+			return qbittorrent.StatusStalled
+		}
+		return qbittorrent.StatusDownloading
+	case statusSeed:
+		return qbittorrent.StatusSeeding
+	default:
+		return qbittorrent.StatusDownloading
+	}
+}
+
+func (t trTorrent) toTorrent() qbittorrent.Torrent {
+	out := qbittorrent.Torrent{
+		Hash:          strings.ToLower(t.HashString),
+		Name:          t.Name,
+		Size:          t.TotalSize,
+		Progress:      t.PercentDone,
+		DownloadSpeed: t.RateDownload,
+		UploadSpeed:   t.RateUpload,
+		ETA:           t.ETA,
+		Status:        mapStatus(t),
+		AddedOn:       time.Unix(t.AddedDate, 0).UTC(),
+		Seeds:         t.PeersSendingToUs,
+		Peers:         t.PeersGettingFromUs,
+		Downloaded:    t.DownloadedEver,
+		Uploaded:      t.UploadedEver,
+		Ratio:         t.UploadRatio,
+		SavePath:      t.DownloadDir,
+		Tags:          t.Labels,
+	}
+	// uploadRatio is -1 (nothing downloaded) or -2 (infinite) as sentinels.
+	if out.Ratio < 0 {
+		out.Ratio = 0
+	}
+	if t.DoneDate > 0 {
+		doneOn := time.Unix(t.DoneDate, 0).UTC()
+		out.CompletedOn = &doneOn
+	}
+	return out
+}
+
+func (t trTorrent) toDetails() *qbittorrent.TorrentDetails {
+	return &qbittorrent.TorrentDetails{
+		Torrent:      t.toTorrent(),
+		PieceSize:    t.PieceSize,
+		Comment:      t.Comment,
+		CreatedBy:    t.Creator,
+		CreationDate: time.Unix(t.DateCreated, 0).UTC(),
+		TotalWasted:  t.CorruptEver,
+		TimeElapsed:  t.SecondsDownloading + t.SecondsSeeding,
+		SeedingTime:  t.SecondsSeeding,
+	}
+}
@@ -208,6 +208,7 @@ function getTaskTypeLabel(taskType: string): string {
   const labels: Record<string, string> = {
     parse: '解析',
     metadata_fetch: '取得元資料',
+    notification: '通知',
   };
   return labels[taskType] || taskType;
 }
@@ -410,7 +410,7 @@ describe('ManageSubtitleDialogV2 (F1 管理字幕)', () => {
         resolution: '1080p',
         format: 'srt',
         score: 0.9,
-        scoreBreakdown: { language: 1, resolution: 1, sourceTrust: 1, group: 1, downloads: 1 },
+        scoreBreakdown: { language: 1, hash: 0, resolution: 1, sourceTrust: 1, group: 1, downloads: 1 },
       },
     ];
     renderDialog();
@@ -155,8 +155,10 @@ describe('SubtitleSearchDialog — Results Table', () => {
       resolution: '1080p',
       format: 'SRT',
       score: 0.85,
+      hashMatch: true,
       scoreBreakdown: {
         language: 0.9,
+        hash: 1,
         resolution: 0.8,
         sourceTrust: 0.7,
         group: 0.6,
@@ -176,6 +178,7 @@ describe('SubtitleSearchDialog — Results Table', () => {
       score: 0.45,
       scoreBreakdown: {
         language: 0.5,
+        hash: 0,
         resolution: 0.4,
         sourceTrust: 0.3,
         group: 0.2,
@@ -233,6 +236,12 @@ describe('SubtitleSearchDialog — Results Table', () => {
     expect(screen.getByText('45%')).toBeInTheDocument();
   });
 
+  it('marks release-hash matches', () => {
+    renderDialog();
+    expect(screen.getByTestId('hash-match-sub-1')).toBeInTheDocument();
+    expect(screen.queryByTestId('hash-match-sub-2')).not.toBeInTheDocument();
+  });
+
   it('displays format column values (AC #3)', () => {
     renderDialog();
     expect(screen.getByText('SRT')).toBeInTheDocument();
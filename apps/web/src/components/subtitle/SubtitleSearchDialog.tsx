@@ -111,8 +111,9 @@ export function SubtitleSearchDialog({
       mediaType,
       providers: selectedProviders,
       query,
+      mediaFilePath,
     });
-  }, [search, mediaId, mediaType, selectedProviders, query]);
+  }, [search, mediaId, mediaType, selectedProviders, query, mediaFilePath]);
 
   const handleDownload = useCallback(
     (result: SubtitleSearchResult) => {
@@ -334,6 +335,15 @@ export function SubtitleSearchDialog({
                           className="max-w-[200px] truncate px-3 py-2 text-[var(--text-secondary)]"
                           title={result.filename}
                         >
+                          {result.hashMatch && (
+                            <span
+                              className="mr-1 rounded border border-green-400/40 bg-green-400/10 px-1 text-xs text-[var(--success)]"
+                              title="檔案雜湊相符：此字幕對應你手上的同一版本"
+                              data-testid={`hash-match-${result.id}`}
+                            >
+                              同版本
+                            </span>
+                          )}
                           {result.filename}
                         </td>
                         <td className="px-3 py-2 text-xs uppercase text-[var(--text-muted)]">
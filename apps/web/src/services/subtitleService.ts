@@ -27,10 +27,13 @@ export interface SubtitleSearchParams {
   mediaType: 'movie' | 'series';
   providers?: string[];
   query?: string;
+  /** Local media file; lets hash-capable providers match the exact release. */
+  mediaFilePath?: string;
 }
 
 export interface SubtitleScoreBreakdown {
   language: number;
+  hash: number;
   resolution: number;
   sourceTrust: number;
   group: number;
@@ -49,6 +52,8 @@ export interface SubtitleSearchResult {
   format: string;
   score: number;
   scoreBreakdown: SubtitleScoreBreakdown;
+  /** True when the provider matched the local file by its release hash. */
+  hashMatch?: boolean;
 }
 
 export interface SubtitleDownloadParams {